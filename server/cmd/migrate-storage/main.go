@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/johnrirwin/flyingforge/internal/config"
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/radio"
+	"github.com/johnrirwin/flyingforge/internal/storage"
+)
+
+// migrate-storage moves image bytes out of Postgres and radio backup files off
+// local disk into the configured S3-compatible object store.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be moved without changing anything")
+	batchSize := flag.Int("batch-size", 100, "number of records to move per batch")
+	moveImages := flag.Bool("images", true, "move image assets stored in the database")
+	moveBackups := flag.Bool("radio-backups", true, "move radio backups stored on local disk")
+	deleteLocal := flag.Bool("delete-local", false, "delete local backup files after they are uploaded")
+
+	cfg := config.Load()
+	if cfg.Storage.Backend != "s3" {
+		fmt.Fprintln(os.Stderr, "STORAGE_BACKEND must be s3 to migrate blobs into object storage")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	db, err := database.New(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Database: cfg.Database.Database,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// Ensures the storage_key columns exist
+	if err := db.Migrate(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to run migrations: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := storage.NewS3StoreFromConfig(ctx, cfg.Storage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize object storage: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Object storage: bucket %s\n", cfg.Storage.S3Bucket)
+
+	if *moveImages {
+		imageStore := database.NewImageAssetStore(db, store)
+		if *dryRun {
+			count, err := imageStore.CountInDatabase(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to count images: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Images: %d would be moved\n", count)
+		} else {
+			total := 0
+			for {
+				moved, err := imageStore.MoveToObjectStore(ctx, *batchSize)
+				total += moved
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "failed to move images after %d: %v\n", total, err)
+					os.Exit(1)
+				}
+				if moved == 0 {
+					break
+				}
+				fmt.Printf("  moved %d images\n", total)
+			}
+			fmt.Printf("Images: %d moved\n", total)
+
+			total = 0
+			for {
+				moved, err := imageStore.MoveDerivativesToObjectStore(ctx, *batchSize)
+				total += moved
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "failed to move image derivatives after %d: %v\n", total, err)
+					os.Exit(1)
+				}
+				if moved == 0 {
+					break
+				}
+			}
+			fmt.Printf("Image derivatives: %d moved\n", total)
+		}
+	}
+
+	if *moveBackups {
+		radioStore := database.NewRadioStore(db)
+		if *dryRun {
+			count := 0
+			for {
+				backups, err := radioStore.ListLocalBackups(ctx, *batchSize, count)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "failed to list radio backups: %v\n", err)
+					os.Exit(1)
+				}
+				if len(backups) == 0 {
+					break
+				}
+				count += len(backups)
+			}
+			fmt.Printf("Radio backups: %d would be moved\n", count)
+		} else {
+			logger := logging.New(logging.LevelInfo)
+			radioSvc := radio.NewService(radioStore, store, cfg.Storage.PresignTTL, logger)
+			moved, skipped, err := radioSvc.MoveLocalBackups(ctx, *batchSize, *deleteLocal)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to move radio backups after %d: %v\n", moved, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Radio backups: %d moved, %d skipped\n", moved, skipped)
+		}
+	}
+}
@@ -57,7 +57,16 @@ func (a *Aggregator) SetRetentionDays(days int) {
 	a.retentionDays = days
 }
 
+// RefreshProgressFunc is called after each source finishes fetching, whether
+// or not the fetch succeeded
+type RefreshProgressFunc func(done, total int, source string)
+
 func (a *Aggregator) Refresh(ctx context.Context) error {
+	return a.RefreshWithProgress(ctx, nil)
+}
+
+// RefreshWithProgress refreshes like Refresh and reports per-source progress
+func (a *Aggregator) RefreshWithProgress(ctx context.Context, progress RefreshProgressFunc) error {
 	var wg sync.WaitGroup
 	results := make(chan sources.FetchResult, len(a.fetchers))
 
@@ -81,7 +90,12 @@ func (a *Aggregator) Refresh(ctx context.Context) error {
 	}()
 
 	allItems := make([]models.FeedItem, 0)
+	done := 0
 	for result := range results {
+		done++
+		if progress != nil {
+			progress(done, len(a.fetchers), result.Source.Name)
+		}
 		if result.Error != nil {
 			a.logger.Warn("Failed to fetch from source", logging.WithFields(map[string]interface{}{
 				"source": result.Source.Name,
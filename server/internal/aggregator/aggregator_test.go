@@ -10,6 +10,7 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
 	"github.com/johnrirwin/flyingforge/internal/sources"
+	"github.com/johnrirwin/flyingforge/internal/tagging"
 )
 
 func TestSortByDate(t *testing.T) {
@@ -422,3 +423,40 @@ func TestAggregator_GetItems_LoadsFromGenericCachePayload(t *testing.T) {
 		t.Fatalf("GetItems() first generic cached item = %q, want %q", resp.Items[0].ID, "generic-cache-item")
 	}
 }
+
+type stubFetcher struct {
+	name  string
+	items []models.FeedItem
+	err   error
+}
+
+func (f stubFetcher) Name() string { return f.name }
+
+func (f stubFetcher) Fetch(_ context.Context) ([]models.FeedItem, error) { return f.items, f.err }
+
+func (f stubFetcher) SourceInfo() models.SourceInfo {
+	return models.SourceInfo{ID: f.name, Name: f.name}
+}
+
+func TestAggregator_RefreshWithProgress_ReportsEverySource(t *testing.T) {
+	a := New([]sources.Fetcher{
+		stubFetcher{name: "reddit", items: []models.FeedItem{{ID: "1", Title: "Quad", URL: "https://example.com/1"}}},
+		stubFetcher{name: "youtube", err: context.DeadlineExceeded},
+	}, nil, tagging.New(), logging.New(logging.LevelError))
+
+	var calls []int
+	total := 0
+	err := a.RefreshWithProgress(context.Background(), func(done, sourceCount int, _ string) {
+		calls = append(calls, done)
+		total = sourceCount
+	})
+	if err != nil {
+		t.Fatalf("RefreshWithProgress() error = %v", err)
+	}
+	if len(calls) != 2 || calls[0] != 1 || calls[1] != 2 || total != 2 {
+		t.Errorf("progress calls = %v (total %d), want 1 and 2 of 2", calls, total)
+	}
+	if len(a.items) != 1 {
+		t.Errorf("items = %d, want the successful source's item", len(a.items))
+	}
+}
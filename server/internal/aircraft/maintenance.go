@@ -0,0 +1,291 @@
+package aircraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ListMaintenance returns an aircraft's crash/repair/maintenance log, newest first
+func (s *Service) ListMaintenance(ctx context.Context, aircraftID string, userID string) ([]models.MaintenanceEvent, error) {
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+	return s.store.ListMaintenanceEvents(ctx, aircraftID)
+}
+
+// GetMaintenanceEvent returns one entry from an aircraft's maintenance log
+func (s *Service) GetMaintenanceEvent(ctx context.Context, aircraftID string, userID string, eventID string) (*models.MaintenanceEvent, error) {
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+	event, err := s.store.GetMaintenanceEvent(ctx, aircraftID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, &ServiceError{Message: "maintenance event not found"}
+	}
+	return event, nil
+}
+
+// LogMaintenance records a crash, repair or maintenance event. Each listed
+// component is matched to the part installed in that slot, and its tracked
+// unit is marked damaged or repaired.
+func (s *Service) LogMaintenance(ctx context.Context, aircraftID string, userID string, params models.CreateMaintenanceEventParams) (*models.MaintenanceEvent, error) {
+	eventType, ok := models.NormalizeMaintenanceEventType(params.Type)
+	if !ok {
+		return nil, &ServiceError{Message: "type must be one of crash, repair, maintenance"}
+	}
+	params.Type = eventType
+	params.Title = strings.TrimSpace(params.Title)
+	if params.Title == "" {
+		return nil, &ServiceError{Message: "title is required"}
+	}
+	params.Description = strings.TrimSpace(params.Description)
+	if params.RepairCost != nil && *params.RepairCost < 0 {
+		return nil, &ServiceError{Message: "repairCost cannot be negative"}
+	}
+
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+
+	components := make([]models.MaintenanceEventComponent, 0, len(params.Components))
+	if len(params.Components) > 0 {
+		installed, err := s.store.GetComponents(ctx, aircraftID)
+		if err != nil {
+			return nil, err
+		}
+		bySlot := make(map[models.ComponentCategory]models.AircraftComponent, len(installed))
+		for _, component := range installed {
+			bySlot[models.NormalizeComponentCategory(component.Category)] = component
+		}
+
+		for _, param := range params.Components {
+			component, err := s.resolveMaintenanceComponent(ctx, aircraftID, userID, bySlot, param)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, component)
+		}
+	}
+
+	event, err := s.store.CreateMaintenanceEvent(ctx, aircraftID, userID, params, components)
+	if err != nil {
+		s.logger.Error("Failed to log maintenance event", logging.WithFields(map[string]interface{}{
+			"aircraft_id": aircraftID,
+			"error":       err.Error(),
+		}))
+		return nil, err
+	}
+
+	for _, component := range event.Components {
+		if component.UnitID == "" {
+			continue
+		}
+		condition := component.UnitCondition
+		if _, err := s.inventorySvc.UpdateUnit(ctx, userID, component.OldInventoryItemID, models.UpdateInventoryUnitParams{
+			ID:        component.UnitID,
+			Condition: &condition,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to mark unit condition: %w", err)
+		}
+	}
+
+	s.logger.Info("Logged maintenance event", logging.WithFields(map[string]interface{}{
+		"aircraft_id": aircraftID,
+		"event_id":    event.ID,
+		"type":        event.Type,
+	}))
+	return event, nil
+}
+
+// UpdateMaintenanceEvent edits an event's type, title, date or repair cost
+func (s *Service) UpdateMaintenanceEvent(ctx context.Context, aircraftID string, userID string, params models.UpdateMaintenanceEventParams) (*models.MaintenanceEvent, error) {
+	if params.Type != nil {
+		eventType, ok := models.NormalizeMaintenanceEventType(*params.Type)
+		if !ok {
+			return nil, &ServiceError{Message: "type must be one of crash, repair, maintenance"}
+		}
+		params.Type = &eventType
+	}
+	if params.Title != nil {
+		title := strings.TrimSpace(*params.Title)
+		if title == "" {
+			return nil, &ServiceError{Message: "title cannot be empty"}
+		}
+		params.Title = &title
+	}
+	if params.RepairCost != nil && *params.RepairCost < 0 {
+		return nil, &ServiceError{Message: "repairCost cannot be negative"}
+	}
+
+	event, err := s.store.UpdateMaintenanceEvent(ctx, aircraftID, userID, params)
+	if errors.Is(err, database.ErrMaintenanceEventNotFound) {
+		return nil, &ServiceError{Message: "maintenance event not found"}
+	}
+	return event, err
+}
+
+// DeleteMaintenanceEvent removes an event from the log. Unit conditions it set are left as they are.
+func (s *Service) DeleteMaintenanceEvent(ctx context.Context, aircraftID string, userID string, eventID string) error {
+	err := s.store.DeleteMaintenanceEvent(ctx, aircraftID, userID, eventID)
+	if errors.Is(err, database.ErrMaintenanceEventNotFound) {
+		return &ServiceError{Message: "maintenance event not found"}
+	}
+	return err
+}
+
+// GetCostOfOwnership totals installed parts, parts replaced during
+// maintenance and repair costs for an aircraft
+func (s *Service) GetCostOfOwnership(ctx context.Context, aircraftID string, userID string) (*models.AircraftCostOfOwnership, error) {
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+
+	components, err := s.store.GetComponents(ctx, aircraftID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.store.ListMaintenanceEvents(ctx, aircraftID)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := models.CalculateAircraftCostOfOwnership(aircraftID, components, events)
+	return &cost, nil
+}
+
+func (s *Service) resolveMaintenanceComponent(ctx context.Context, aircraftID string, userID string, bySlot map[models.ComponentCategory]models.AircraftComponent, param models.MaintenanceComponentParams) (models.MaintenanceEventComponent, error) {
+	category := models.NormalizeComponentCategory(param.Category)
+	if category == "" {
+		return models.MaintenanceEventComponent{}, &ServiceError{Message: "component category is required"}
+	}
+	action, ok := models.NormalizeMaintenanceComponentAction(param.Action)
+	if !ok {
+		return models.MaintenanceEventComponent{}, &ServiceError{Message: "component action must be one of damaged, repaired, replaced"}
+	}
+	installed, ok := bySlot[category]
+	if !ok || installed.InventoryItemID == "" {
+		return models.MaintenanceEventComponent{}, &ServiceError{Message: fmt.Sprintf("aircraft has no %s component", category)}
+	}
+
+	component := models.MaintenanceEventComponent{
+		Category:           category,
+		Action:             action,
+		OldInventoryItemID: installed.InventoryItemID,
+		Notes:              strings.TrimSpace(param.Notes),
+	}
+
+	unit, err := s.findAircraftUnit(ctx, aircraftID, userID, installed.InventoryItemID, strings.TrimSpace(param.UnitID))
+	if err != nil {
+		return models.MaintenanceEventComponent{}, err
+	}
+	if unit != nil {
+		condition := action.UnitCondition()
+		if param.UnitCondition != "" {
+			normalized, ok := models.NormalizeInventoryUnitCondition(string(param.UnitCondition))
+			if !ok {
+				return models.MaintenanceEventComponent{}, &ServiceError{Message: "unitCondition must be one of new, used, damaged, dead, repaired"}
+			}
+			condition = normalized
+		}
+		component.UnitID = unit.ID
+		component.UnitCondition = condition
+	}
+	return component, nil
+}
+
+// findAircraftUnit returns unitID from the item's tracked units, or when
+// unitID is empty, the only unit of the item assigned to the aircraft.
+// Returns nil when no single unit can be picked.
+func (s *Service) findAircraftUnit(ctx context.Context, aircraftID string, userID string, itemID string, unitID string) (*models.InventoryUnit, error) {
+	units, err := s.inventorySvc.ListUnits(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *models.InventoryUnit
+	for i := range units {
+		unit := &units[i]
+		if unitID != "" {
+			if unit.ID == unitID {
+				return unit, nil
+			}
+			continue
+		}
+		if unit.AircraftID != aircraftID {
+			continue
+		}
+		if match != nil {
+			return nil, nil
+		}
+		match = unit
+	}
+	if unitID != "" {
+		return nil, &ServiceError{Message: "unitId must be a unit of the installed component"}
+	}
+	return match, nil
+}
+
+// recordComponentSwap logs a SetComponent swap on a maintenance event and
+// retires the replaced unit: it is left in the requested condition and
+// unassigned from the aircraft.
+func (s *Service) recordComponentSwap(ctx context.Context, userID string, params models.SetComponentParams, previous *models.AircraftComponent, newItemID string) error {
+	component := models.MaintenanceEventComponent{
+		Category:           params.Category,
+		Action:             models.MaintenanceComponentReplaced,
+		NewInventoryItemID: newItemID,
+		Notes:              strings.TrimSpace(params.Notes),
+	}
+
+	if previous != nil && previous.InventoryItemID != "" {
+		component.OldInventoryItemID = previous.InventoryItemID
+		if previous.InventoryItem != nil && previous.InventoryItem.PurchasePrice != nil {
+			price := *previous.InventoryItem.PurchasePrice
+			component.ReplacedCost = &price
+		}
+
+		unit, err := s.findAircraftUnit(ctx, params.AircraftID, userID, previous.InventoryItemID, "")
+		if err != nil {
+			return err
+		}
+		if unit != nil {
+			condition := params.ReplacedUnitCondition
+			if condition == "" {
+				condition = models.InventoryUnitDamaged
+			}
+			unassigned := ""
+			if _, err := s.inventorySvc.UpdateUnit(ctx, userID, previous.InventoryItemID, models.UpdateInventoryUnitParams{
+				ID:         unit.ID,
+				Condition:  &condition,
+				AircraftID: &unassigned,
+			}); err != nil {
+				return fmt.Errorf("failed to retire replaced unit: %w", err)
+			}
+			component.UnitID = unit.ID
+			component.UnitCondition = condition
+			if unit.PurchasePrice != nil {
+				price := *unit.PurchasePrice
+				component.ReplacedCost = &price
+			}
+		}
+	}
+
+	if _, err := s.store.AddMaintenanceEventComponent(ctx, params.MaintenanceEventID, component); err != nil {
+		return err
+	}
+
+	s.logger.Info("Recorded component swap", logging.WithFields(map[string]interface{}{
+		"aircraft_id": params.AircraftID,
+		"event_id":    params.MaintenanceEventID,
+		"category":    params.Category,
+	}))
+	return nil
+}
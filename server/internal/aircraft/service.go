@@ -3,6 +3,7 @@ package aircraft
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -19,16 +20,20 @@ type Service struct {
 	store            *database.AircraftStore
 	inventorySvc     inventory.InventoryManager
 	gearCatalogStore *database.GearCatalogStore
+	batteryStore     *database.BatteryStore
+	radioStore       *database.RadioStore
 	imageSvc         *images.Service
 	logger           *logging.Logger
 }
 
 // NewService creates a new aircraft service
-func NewService(store *database.AircraftStore, inventorySvc inventory.InventoryManager, gearCatalogStore *database.GearCatalogStore, imageSvc *images.Service, logger *logging.Logger) *Service {
+func NewService(store *database.AircraftStore, inventorySvc inventory.InventoryManager, gearCatalogStore *database.GearCatalogStore, batteryStore *database.BatteryStore, radioStore *database.RadioStore, imageSvc *images.Service, logger *logging.Logger) *Service {
 	return &Service{
 		store:            store,
 		inventorySvc:     inventorySvc,
 		gearCatalogStore: gearCatalogStore,
+		batteryStore:     batteryStore,
+		radioStore:       radioStore,
 		imageSvc:         imageSvc,
 		logger:           logger,
 	}
@@ -100,9 +105,47 @@ func (s *Service) List(ctx context.Context, userID string, params models.Aircraf
 	return s.store.List(ctx, userID, params)
 }
 
-// GetDetails retrieves full aircraft details
+// GetDetails retrieves full aircraft details, including which radio models bind it
 func (s *Service) GetDetails(ctx context.Context, id string, userID string) (*models.AircraftDetailsResponse, error) {
-	return s.store.GetDetails(ctx, id, userID)
+	details, err := s.store.GetDetails(ctx, id, userID)
+	if err != nil || details == nil {
+		return details, err
+	}
+
+	if s.radioStore != nil {
+		// Radio links are informational; don't fail the details view over them
+		links, err := s.GetRadioLinks(ctx, userID)
+		if err != nil {
+			s.logger.Warn("Failed to link radio models", logging.WithFields(map[string]interface{}{
+				"aircraft_id": id,
+				"error":       err.Error(),
+			}))
+		} else {
+			details.RadioBindings, details.RadioWarnings = links.ForAircraft(id)
+		}
+	}
+
+	return details, nil
+}
+
+// GetRadioLinks matches the EdgeTX models in each radio's newest parsed backup
+// to the user's aircraft by model match number and name, and reports model
+// match collisions across the fleet
+func (s *Service) GetRadioLinks(ctx context.Context, userID string) (*models.RadioLinksResponse, error) {
+	if s.radioStore == nil {
+		return &models.RadioLinksResponse{Bindings: []models.AircraftRadioBinding{}, Warnings: []models.RadioLinkWarning{}}, nil
+	}
+
+	aircraft, err := s.store.ListWithReceiverSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.radioStore.ListLatestModelSources(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.LinkRadioModels(aircraft, sources), nil
 }
 
 // SetComponent assigns a component to an aircraft
@@ -125,6 +168,15 @@ func (s *Service) SetComponent(ctx context.Context, userID string, params models
 		return nil, &ServiceError{Message: "aircraft not found"}
 	}
 
+	// A swap made during a maintenance event is recorded on that event
+	var previous *models.AircraftComponent
+	if params.MaintenanceEventID != "" {
+		previous, err = s.prepareComponentSwap(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	inventoryItemID := params.InventoryItemID
 
 	// If newGear is provided, create the inventory item first (auto-add feature)
@@ -186,6 +238,11 @@ func (s *Service) SetComponent(ctx context.Context, userID string, params models
 		if err := s.store.RemoveComponent(ctx, params.AircraftID, params.Category); err != nil {
 			return nil, err
 		}
+		if params.MaintenanceEventID != "" && previous != nil {
+			if err := s.recordComponentSwap(ctx, userID, params, previous, ""); err != nil {
+				return nil, err
+			}
+		}
 		return nil, nil
 	}
 
@@ -212,9 +269,46 @@ func (s *Service) SetComponent(ctx context.Context, userID string, params models
 		"item_id":     inventoryItemID,
 	}))
 
+	if params.MaintenanceEventID != "" && (previous == nil || previous.InventoryItemID != inventoryItemID) {
+		if err := s.recordComponentSwap(ctx, userID, params, previous, inventoryItemID); err != nil {
+			return nil, err
+		}
+	}
+
 	return component, nil
 }
 
+// prepareComponentSwap checks the maintenance event a swap is recorded on and
+// returns the component currently in the slot, if any.
+func (s *Service) prepareComponentSwap(ctx context.Context, params *models.SetComponentParams) (*models.AircraftComponent, error) {
+	if params.ReplacedUnitCondition != "" {
+		condition, ok := models.NormalizeInventoryUnitCondition(string(params.ReplacedUnitCondition))
+		if !ok {
+			return nil, &ServiceError{Message: "replacedUnitCondition must be one of new, used, damaged, dead, repaired"}
+		}
+		params.ReplacedUnitCondition = condition
+	}
+
+	event, err := s.store.GetMaintenanceEvent(ctx, params.AircraftID, params.MaintenanceEventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, &ServiceError{Message: "maintenance event not found"}
+	}
+
+	components, err := s.store.GetComponents(ctx, params.AircraftID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range components {
+		if models.NormalizeComponentCategory(components[i].Category) == params.Category {
+			return &components[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // SetReceiverSettings sets receiver settings for an aircraft
 func (s *Service) SetReceiverSettings(ctx context.Context, userID string, params models.SetReceiverSettingsParams) (*models.AircraftReceiverSettings, error) {
 	if params.AircraftID == "" {
@@ -278,6 +372,198 @@ func (s *Service) GetComponents(ctx context.Context, aircraftID string, userID s
 	return s.store.GetComponents(ctx, aircraftID)
 }
 
+// GetPowerProfile derives the battery requirements of an aircraft from its installed components
+func (s *Service) GetPowerProfile(ctx context.Context, aircraftID string, userID string) (*models.AircraftPowerProfile, error) {
+	aircraft, err := s.store.Get(ctx, aircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if aircraft == nil {
+		return nil, &NotFoundError{Message: "aircraft not found"}
+	}
+
+	components, err := s.store.GetComponents(ctx, aircraftID)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]models.PowerComponentSpec, 0, len(components))
+	for _, c := range components {
+		if c.InventoryItem == nil {
+			continue
+		}
+		spec := models.PowerComponentSpec{
+			Category: c.Category,
+			Name:     c.InventoryItem.Name,
+			Specs:    c.InventoryItem.Specs,
+		}
+		// Catalog specs are usually more complete than the user's inventory copy;
+		// merge them underneath so user-entered values still win.
+		if c.InventoryItem.CatalogID != "" && s.gearCatalogStore != nil {
+			catalogItem, err := s.gearCatalogStore.Get(ctx, c.InventoryItem.CatalogID)
+			if err != nil {
+				s.logger.Warn("Failed to load catalog specs for power profile", logging.WithFields(map[string]interface{}{
+					"catalog_id": c.InventoryItem.CatalogID,
+					"error":      err.Error(),
+				}))
+			} else if catalogItem != nil {
+				spec.Specs = mergeSpecs(catalogItem.Specs, c.InventoryItem.Specs)
+			}
+		}
+		specs = append(specs, spec)
+	}
+
+	profile := models.DerivePowerProfile(aircraft.Type, specs)
+	return &profile, nil
+}
+
+// ListBatteries lists the battery packs assigned to an aircraft with their compatibility
+func (s *Service) ListBatteries(ctx context.Context, aircraftID string, userID string) ([]models.AircraftBattery, error) {
+	profile, err := s.GetPowerProfile(ctx, aircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	batteries, err := s.store.ListBatteries(ctx, aircraftID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range batteries {
+		if batteries[i].Battery == nil {
+			continue
+		}
+		compat := models.CheckBatteryCompatibility(*profile, *batteries[i].Battery)
+		batteries[i].Compatibility = &compat
+	}
+	return batteries, nil
+}
+
+// AssignBattery assigns a battery pack to an aircraft.
+// Packs with blocking compatibility issues (cell count, connector) are rejected.
+func (s *Service) AssignBattery(ctx context.Context, userID string, params models.AssignAircraftBatteryParams) (*models.AircraftBattery, error) {
+	if params.AircraftID == "" {
+		return nil, &ServiceError{Message: "aircraftId is required"}
+	}
+	if params.BatteryID == "" {
+		return nil, &ServiceError{Message: "batteryId is required"}
+	}
+
+	profile, err := s.GetPowerProfile(ctx, params.AircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the battery belongs to the user
+	battery, err := s.batteryStore.Get(ctx, params.BatteryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if battery == nil {
+		return nil, &NotFoundError{Message: "battery not found"}
+	}
+
+	compat := models.CheckBatteryCompatibility(*profile, *battery)
+	if !compat.Compatible {
+		return nil, &ServiceError{Message: "battery is not compatible with this aircraft: " + strings.Join(compat.Issues, "; ")}
+	}
+
+	assigned, err := s.store.AssignBattery(ctx, params.AircraftID, params.BatteryID, strings.TrimSpace(params.Notes))
+	if err != nil {
+		s.logger.Error("Failed to assign battery", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+	assigned.Battery = battery
+	assigned.Compatibility = &compat
+
+	s.logger.Info("Assigned battery to aircraft", logging.WithFields(map[string]interface{}{
+		"aircraft_id": params.AircraftID,
+		"battery_id":  params.BatteryID,
+	}))
+	return assigned, nil
+}
+
+// UnassignBattery removes a battery pack from an aircraft
+func (s *Service) UnassignBattery(ctx context.Context, aircraftID string, batteryID string, userID string) error {
+	aircraft, err := s.store.Get(ctx, aircraftID, userID)
+	if err != nil {
+		return err
+	}
+	if aircraft == nil {
+		return &NotFoundError{Message: "aircraft not found"}
+	}
+
+	if err := s.store.UnassignBattery(ctx, aircraftID, batteryID); err != nil {
+		if errors.Is(err, database.ErrBatteryNotAssigned) {
+			return &ServiceError{Message: err.Error()}
+		}
+		return err
+	}
+
+	s.logger.Info("Unassigned battery from aircraft", logging.WithFields(map[string]interface{}{
+		"aircraft_id": aircraftID,
+		"battery_id":  batteryID,
+	}))
+	return nil
+}
+
+// FindCompatibleBatteries checks every battery the user owns against an aircraft
+func (s *Service) FindCompatibleBatteries(ctx context.Context, aircraftID string, userID string) (*models.CompatibleBatteriesResponse, error) {
+	profile, err := s.GetPowerProfile(ctx, aircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := s.store.ListBatteries(ctx, aircraftID)
+	if err != nil {
+		return nil, err
+	}
+	assignedIDs := make(map[string]bool, len(assigned))
+	for _, ab := range assigned {
+		assignedIDs[ab.BatteryID] = true
+	}
+
+	batteries, err := s.batteryStore.ListAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CompatibleBatteriesResponse{
+		AircraftID: aircraftID,
+		Profile:    *profile,
+		Batteries:  make([]models.CompatibleBattery, 0, len(batteries)),
+	}
+	for _, b := range batteries {
+		result.Batteries = append(result.Batteries, models.CompatibleBattery{
+			Battery:       b,
+			Compatibility: models.CheckBatteryCompatibility(*profile, b),
+			Assigned:      assignedIDs[b.ID],
+		})
+	}
+	return result, nil
+}
+
+// mergeSpecs overlays override spec keys on top of base specs.
+// Returns whichever side is valid if the other cannot be decoded as an object.
+func mergeSpecs(base, override json.RawMessage) json.RawMessage {
+	baseMap := map[string]interface{}{}
+	if len(base) == 0 || json.Unmarshal(base, &baseMap) != nil {
+		return override
+	}
+	overrideMap := map[string]interface{}{}
+	if len(override) == 0 || json.Unmarshal(override, &overrideMap) != nil {
+		return base
+	}
+	for k, v := range overrideMap {
+		baseMap[k] = v
+	}
+	merged, err := json.Marshal(baseMap)
+	if err != nil {
+		return override
+	}
+	return merged
+}
+
 // SetImage uploads an image for an aircraft
 func (s *Service) SetImage(ctx context.Context, userID string, params models.SetAircraftImageParams) (*models.ModerationDecision, error) {
 	if params.AircraftID == "" {
@@ -296,51 +582,12 @@ func (s *Service) SetImage(ctx context.Context, userID string, params models.Set
 		return nil, &ServiceError{Message: "aircraft not found"}
 	}
 
-	var (
-		decision *models.ModerationDecision
-		asset    *models.ImageAsset
-	)
-
-	uploadID := strings.TrimSpace(params.UploadID)
-	if uploadID != "" {
-		asset, err = s.imageSvc.PersistApprovedUpload(ctx, userID, uploadID, models.ImageEntityAircraft, params.AircraftID)
-		if err != nil {
-			return nil, err
-		}
-		decision = &models.ModerationDecision{
-			Status: models.ImageModerationApproved,
-			Reason: "Approved",
-		}
-		if params.ImageType == "" {
-			params.ImageType = http.DetectContentType(asset.ImageBytes)
-		}
-	} else {
-		if len(params.ImageData) == 0 {
-			return nil, &ServiceError{Message: "image data is required"}
-		}
-		if params.ImageType != "image/jpeg" && params.ImageType != "image/png" {
-			return nil, &ServiceError{Message: "image must be JPEG or PNG"}
-		}
-
-		// Validate file size (max 2MB)
-		const maxImageSize = 2 * 1024 * 1024
-		if len(params.ImageData) > maxImageSize {
-			return nil, &ServiceError{Message: "image must be less than 2MB"}
-		}
-
-		decision, asset, err = s.imageSvc.ModerateAndPersist(ctx, images.SaveRequest{
-			OwnerUserID: userID,
-			EntityType:  models.ImageEntityAircraft,
-			EntityID:    params.AircraftID,
-			ImageBytes:  params.ImageData,
-		})
-		if err != nil {
-			s.logger.Error("Failed to moderate aircraft image", logging.WithField("error", err.Error()))
-			return nil, err
-		}
-		if decision.Status != models.ImageModerationApproved {
-			return decision, nil
-		}
+	decision, asset, err := s.persistAircraftImage(ctx, userID, params.AircraftID, params.UploadID, params.ImageType, params.ImageData)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.Accepted() {
+		return decision, nil
 	}
 
 	if params.ImageType == "" {
@@ -367,19 +614,190 @@ func (s *Service) SetImage(ctx context.Context, userID string, params models.Set
 	return decision, nil
 }
 
-// GetImage retrieves an aircraft's image
-func (s *Service) GetImage(ctx context.Context, aircraftID string, userID string) ([]byte, string, error) {
-	imageData, imageType, err := s.store.GetImage(ctx, aircraftID, userID)
-	if err != nil {
-		return nil, "", err
+// persistAircraftImage stores an aircraft image from an upload token, or
+// moderates raw bytes first. The asset is nil unless moderation approved the
+// image or queued it for review.
+func (s *Service) persistAircraftImage(ctx context.Context, userID string, aircraftID string, uploadID string, imageType string, imageData []byte) (*models.ModerationDecision, *models.ImageAsset, error) {
+	uploadID = strings.TrimSpace(uploadID)
+	if uploadID != "" {
+		asset, err := s.imageSvc.PersistApprovedUpload(ctx, userID, uploadID, models.ImageEntityAircraft, aircraftID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return images.AssetDecision(asset), asset, nil
 	}
+
 	if len(imageData) == 0 {
-		return imageData, imageType, nil
+		return nil, nil, &ServiceError{Message: "image data is required"}
+	}
+	if imageType != "image/jpeg" && imageType != "image/png" {
+		return nil, nil, &ServiceError{Message: "image must be JPEG or PNG"}
+	}
+
+	// Validate file size (max 2MB)
+	const maxImageSize = 2 * 1024 * 1024
+	if len(imageData) > maxImageSize {
+		return nil, nil, &ServiceError{Message: "image must be less than 2MB"}
+	}
+
+	decision, asset, err := s.imageSvc.ModerateAndPersist(ctx, images.SaveRequest{
+		OwnerUserID: userID,
+		EntityType:  models.ImageEntityAircraft,
+		EntityID:    aircraftID,
+		ImageBytes:  imageData,
+	})
+	if err != nil {
+		s.logger.Error("Failed to moderate aircraft image", logging.WithField("error", err.Error()))
+		return nil, nil, err
 	}
-	if imageType == "" {
-		imageType = http.DetectContentType(imageData)
+	if !decision.Accepted() {
+		return decision, nil, nil
+	}
+	return decision, asset, nil
+}
+
+// ListGallery returns an aircraft's gallery.
+func (s *Service) ListGallery(ctx context.Context, aircraftID string, userID string) ([]models.GalleryImage, error) {
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
 	}
-	return imageData, imageType, nil
+	return s.store.ListGallery(ctx, aircraftID)
+}
+
+// AddGalleryImage moderates an image and appends it to an aircraft gallery.
+// A nil image means moderation rejected the image or could not check it; an
+// image queued for review is added and shows a placeholder until approved.
+func (s *Service) AddGalleryImage(ctx context.Context, aircraftID string, userID string, params models.AddGalleryImageParams) (*models.ModerationDecision, *models.GalleryImage, error) {
+	if s.imageSvc == nil {
+		return nil, nil, &ServiceError{Message: "image moderation unavailable"}
+	}
+	caption, err := models.NormalizeGalleryCaption(params.Caption)
+	if err != nil {
+		return nil, nil, &ServiceError{Message: err.Error()}
+	}
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	// Check the limit before moderating so a full gallery doesn't cost a scan
+	gallery, err := s.store.ListGallery(ctx, aircraftID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(gallery) >= models.MaxGalleryImages {
+		return nil, nil, &ServiceError{Message: database.ErrGalleryFull.Error()}
+	}
+
+	decision, asset, err := s.persistAircraftImage(ctx, userID, aircraftID, params.UploadID, params.ImageType, params.ImageData)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !decision.Accepted() {
+		return decision, nil, nil
+	}
+
+	image, err := s.store.AddGalleryImage(ctx, aircraftID, userID, asset.ID, caption)
+	if err != nil {
+		_ = s.imageSvc.Delete(ctx, asset.ID)
+		if errors.Is(err, database.ErrGalleryFull) {
+			return nil, nil, &ServiceError{Message: err.Error()}
+		}
+		return nil, nil, err
+	}
+	return decision, image, nil
+}
+
+// UpdateGalleryImage edits a gallery image caption or makes it the cover.
+func (s *Service) UpdateGalleryImage(ctx context.Context, aircraftID string, userID string, imageID string, params models.UpdateGalleryImageParams) (*models.GalleryImage, error) {
+	if params.Caption != nil {
+		caption, err := models.NormalizeGalleryCaption(*params.Caption)
+		if err != nil {
+			return nil, &ServiceError{Message: err.Error()}
+		}
+		params.Caption = &caption
+	}
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+
+	image, err := s.store.UpdateGalleryImage(ctx, aircraftID, userID, imageID, params)
+	if errors.Is(err, database.ErrGalleryImageNotFound) || (err == nil && image == nil) {
+		return nil, &ServiceError{Message: "gallery image not found"}
+	}
+	return image, err
+}
+
+// ReorderGallery sets the display order of an aircraft gallery.
+func (s *Service) ReorderGallery(ctx context.Context, aircraftID string, userID string, imageIDs []string) ([]models.GalleryImage, error) {
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+	current, err := s.store.ListGallery(ctx, aircraftID)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.ValidateGalleryOrder(current, imageIDs); err != nil {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	return s.store.ReorderGallery(ctx, aircraftID, userID, imageIDs)
+}
+
+// DeleteGalleryImage removes an image from an aircraft gallery.
+func (s *Service) DeleteGalleryImage(ctx context.Context, aircraftID string, userID string, imageID string) error {
+	if err := s.requireOwned(ctx, aircraftID, userID); err != nil {
+		return err
+	}
+	orphanAssetID, err := s.store.DeleteGalleryImage(ctx, aircraftID, userID, imageID)
+	if errors.Is(err, database.ErrGalleryImageNotFound) {
+		return &ServiceError{Message: "gallery image not found"}
+	}
+	if err != nil {
+		return err
+	}
+	if orphanAssetID != "" && s.imageSvc != nil {
+		_ = s.imageSvc.Delete(ctx, orphanAssetID)
+	}
+	return nil
+}
+
+// GetGalleryImage opens an aircraft gallery image at the requested size.
+func (s *Service) GetGalleryImage(ctx context.Context, aircraftID string, userID string, imageID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetGalleryImage(ctx, aircraftID, userID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	return s.openImageRef(ctx, ref, size)
+}
+
+func (s *Service) requireOwned(ctx context.Context, aircraftID string, userID string) error {
+	aircraft, err := s.store.Get(ctx, aircraftID, userID)
+	if err != nil {
+		return err
+	}
+	if aircraft == nil {
+		return &ServiceError{Message: "aircraft not found"}
+	}
+	return nil
+}
+
+// GetImage opens an aircraft's image at the requested size.
+// Returns nil when the aircraft has no image.
+func (s *Service) GetImage(ctx context.Context, aircraftID string, userID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetImage(ctx, aircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.openImageRef(ctx, ref, size)
+}
+
+func (s *Service) openImageRef(ctx context.Context, ref *models.ImageRef, size models.ImageSize) (*images.ImageStream, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	if s.imageSvc == nil {
+		return nil, &ServiceError{Message: "image storage unavailable"}
+	}
+	return s.imageSvc.OpenRef(ctx, ref, size)
 }
 
 // DeleteImage removes an aircraft's image
@@ -443,3 +861,12 @@ type ServiceError struct {
 func (e *ServiceError) Error() string {
 	return e.Message
 }
+
+// NotFoundError is returned when the aircraft or battery a request names doesn't exist
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
@@ -1,6 +1,9 @@
 package aircraft
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/johnrirwin/flyingforge/internal/models"
@@ -144,3 +147,62 @@ func TestMapComponentToEquipmentCategory(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		override string
+		want     string
+	}{
+		{name: "override wins on shared keys", base: `{"voltage":"3-6S","connector":"XT60"}`, override: `{"voltage":"4-6S"}`, want: `{"connector":"XT60","voltage":"4-6S"}`},
+		{name: "empty base returns override", base: ``, override: `{"voltage":"4-6S"}`, want: `{"voltage":"4-6S"}`},
+		{name: "empty override returns base", base: `{"voltage":"3-6S"}`, override: ``, want: `{"voltage":"3-6S"}`},
+		{name: "invalid override returns base", base: `{"voltage":"3-6S"}`, override: `[1,2]`, want: `{"voltage":"3-6S"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeSpecs(json.RawMessage(tt.base), json.RawMessage(tt.override))
+			if string(got) != tt.want {
+				t.Errorf("mergeSpecs() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogMaintenance_Validation(t *testing.T) {
+	negative := -5.0
+	tests := []struct {
+		name   string
+		params models.CreateMaintenanceEventParams
+		errMsg string
+	}{
+		{
+			name:   "unknown type",
+			params: models.CreateMaintenanceEventParams{Type: "flyaway", Title: "Lost it"},
+			errMsg: "type must be one of crash, repair, maintenance",
+		},
+		{
+			name:   "blank title",
+			params: models.CreateMaintenanceEventParams{Type: models.MaintenanceEventCrash, Title: "  "},
+			errMsg: "title is required",
+		},
+		{
+			name:   "negative repair cost",
+			params: models.CreateMaintenanceEventParams{Type: models.MaintenanceEventRepair, Title: "New arm", RepairCost: &negative},
+			errMsg: "repairCost cannot be negative",
+		},
+	}
+
+	svc := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.LogMaintenance(context.Background(), "aircraft-1", "user-1", tt.params)
+			var svcErr *ServiceError
+			if !errors.As(err, &svcErr) || svcErr.Message != tt.errMsg {
+				t.Fatalf("LogMaintenance() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -16,17 +16,23 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/crypto"
 	"github.com/johnrirwin/flyingforge/internal/database"
 	"github.com/johnrirwin/flyingforge/internal/equipment"
+	"github.com/johnrirwin/flyingforge/internal/fcconfig"
+	"github.com/johnrirwin/flyingforge/internal/flights"
 	"github.com/johnrirwin/flyingforge/internal/httpapi"
 	"github.com/johnrirwin/flyingforge/internal/images"
 	"github.com/johnrirwin/flyingforge/internal/inventory"
 	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/mailer"
 	"github.com/johnrirwin/flyingforge/internal/mcp"
 	"github.com/johnrirwin/flyingforge/internal/models"
 	"github.com/johnrirwin/flyingforge/internal/moderation"
+	"github.com/johnrirwin/flyingforge/internal/orders"
 	"github.com/johnrirwin/flyingforge/internal/radio"
 	"github.com/johnrirwin/flyingforge/internal/ratelimit"
 	"github.com/johnrirwin/flyingforge/internal/sources"
+	"github.com/johnrirwin/flyingforge/internal/storage"
 	"github.com/johnrirwin/flyingforge/internal/tagging"
+	"github.com/johnrirwin/flyingforge/internal/takeout"
 )
 
 // App holds all application dependencies
@@ -42,6 +48,10 @@ type App struct {
 	AnnouncementSvc   *announcements.Service
 	RadioSvc          *radio.Service
 	BatterySvc        *battery.Service
+	FlightSvc         *flights.Service
+	OrderSvc          *orders.Service
+	ExportSvc         *takeout.Service
+	ImportSvc         *takeout.Importer
 	AuthService       *auth.Service
 	AuthMiddleware    *auth.Middleware
 	MCPAuthService    *auth.MCPAuthService
@@ -53,6 +63,10 @@ type App struct {
 	userStore         *database.UserStore
 	oauthStore        *database.OAuthStore
 	aircraftStore     *database.AircraftStore
+	batteryStore      *database.BatteryStore
+	flightStore       *database.FlightStore
+	orderStore        *database.OrderStore
+	radioStore        *database.RadioStore
 	fcConfigStore     *database.FCConfigStore
 	inventoryStore    *database.InventoryStore
 	buildStore        *database.BuildStore
@@ -85,7 +99,9 @@ func New(cfg *config.Config) (*App, error) {
 	app.Aggregator.SetRetentionDays(cfg.Server.FeedRetentionDays)
 
 	// Initialize database, inventory, and auth services
-	app.initDatabaseServices()
+	if err := app.initDatabaseServices(); err != nil {
+		return nil, err
+	}
 
 	// Initialize catalog-backed equipment service (seller APIs removed).
 	app.EquipmentSvc = equipment.NewService(app.gearCatalogStore, app.Logger)
@@ -189,7 +205,7 @@ func (a *App) initFetchers(limiter *ratelimit.Limiter) []sources.Fetcher {
 	return sources.CreateFetchersFromConfig(defaultConfig, limiter, fetcherConfig)
 }
 
-func (a *App) initDatabaseServices() {
+func (a *App) initDatabaseServices() error {
 	dbConfig := database.Config{
 		Host:     a.Config.Database.Host,
 		Port:     a.Config.Database.Port,
@@ -205,7 +221,7 @@ func (a *App) initDatabaseServices() {
 		a.InventorySvc = inventory.NewInMemoryService(a.Logger)
 		// Auth service requires database, so we create a no-op middleware
 		a.AuthMiddleware = auth.NewMiddleware(nil)
-		return
+		return nil
 	}
 
 	a.Logger.Info("Connected to PostgreSQL")
@@ -213,7 +229,7 @@ func (a *App) initDatabaseServices() {
 		a.Logger.Warn("Failed to run migrations, using in-memory inventory (auth disabled)", logging.WithField("error", err.Error()))
 		a.InventorySvc = inventory.NewInMemoryService(a.Logger)
 		a.AuthMiddleware = auth.NewMiddleware(nil)
-		return
+		return nil
 	}
 
 	a.db = db
@@ -237,7 +253,16 @@ func (a *App) initDatabaseServices() {
 	a.InventorySvc = inventory.NewService(a.inventoryStore, a.Logger)
 
 	// Initialize centralized image storage + moderation pipeline
-	a.imageAssetStore = database.NewImageAssetStore(db)
+	objectStore, err := a.newObjectStore()
+	if err != nil {
+		db.Close()
+		return err
+	}
+	var imageBlobs storage.ObjectStore
+	if objectStore != nil {
+		imageBlobs = objectStore
+	}
+	a.imageAssetStore = database.NewImageAssetStore(db, imageBlobs)
 	moderatorSvc, err := a.newModerationService()
 	if err != nil {
 		a.Logger.Warn("Image moderation setup failed, uploads will return PENDING_REVIEW",
@@ -256,9 +281,15 @@ func (a *App) initDatabaseServices() {
 	// Initialize gear catalog store (before aircraft, since aircraft contributes to catalog)
 	a.gearCatalogStore = database.NewGearCatalogStore(db)
 
+	// Initialize battery store (before aircraft, since aircraft checks pack compatibility)
+	a.batteryStore = database.NewBatteryStore(db)
+
+	// Initialize radio store (before aircraft, since aircraft details link radio models)
+	a.radioStore = database.NewRadioStore(db)
+
 	// Initialize aircraft (with encryption support and gear catalog contribution)
 	a.aircraftStore = database.NewAircraftStore(db, encryptor)
-	a.AircraftSvc = aircraft.NewService(a.aircraftStore, a.InventorySvc, a.gearCatalogStore, a.imageSvc, a.Logger)
+	a.AircraftSvc = aircraft.NewService(a.aircraftStore, a.InventorySvc, a.gearCatalogStore, a.batteryStore, a.radioStore, a.imageSvc, a.Logger)
 
 	// Initialize builds service (public builds + draft/temp builder)
 	a.buildStore = database.NewBuildStore(db)
@@ -267,24 +298,75 @@ func (a *App) initDatabaseServices() {
 	a.AnnouncementSvc = announcements.NewService(a.announcementStore, a.Logger)
 
 	// Initialize radio
-	radioStore := database.NewRadioStore(db)
-	a.RadioSvc = radio.NewService(radioStore, "", a.Logger) // Empty string uses default storage dir
+	var radioBlobs storage.ObjectStore
+	if objectStore != nil {
+		radioBlobs = objectStore
+	}
+	a.RadioSvc = radio.NewService(a.radioStore, radioBlobs, a.Config.Storage.PresignTTL, a.Logger) // Nil store keeps backups on local disk
 
 	// Initialize battery
-	batteryStore := database.NewBatteryStore(db)
-	a.BatterySvc = battery.NewService(batteryStore, a.Logger)
+	a.BatterySvc = battery.NewService(a.batteryStore, a.Logger)
+
+	// Initialize flight log (logs pack usage through the battery service)
+	a.flightStore = database.NewFlightStore(db)
+	a.FlightSvc = flights.NewService(a.flightStore, a.BatterySvc, a.Logger)
+
+	// Initialize orders and carrier tracking
+	a.orderStore = database.NewOrderStore(db)
+	a.OrderSvc = orders.NewService(a.orderStore, a.Logger)
+	if apiURL := a.Config.Orders.TrackingAPIURL; apiURL != "" {
+		for _, carrier := range []models.OrderCarrier{models.CarrierUPS, models.CarrierUSPS, models.CarrierFedEx, models.CarrierDHL} {
+			a.OrderSvc.RegisterTracker(carrier, orders.NewHTTPTracker(apiURL, a.Config.Orders.TrackingAPIKey, carrier, a.Config.Orders.Timeout))
+		}
+	}
 
 	// Initialize auth
 	a.userStore = database.NewUserStore(db)
 	a.oauthStore = database.NewOAuthStore(db)
 	a.AuthService = auth.NewService(a.userStore, a.Config.Auth, a.Logger)
+	a.AuthService.SetMailSender(a.newMailSender()) // Email sign-in links
 	a.AuthMiddleware = auth.NewMiddleware(a.AuthService)
 	a.OAuthService = auth.NewOAuthServerService(a.Config.MCP, a.Config.Auth, a.userStore, a.oauthStore, a.AuthService, a.Logger)
 
 	// Initialize FC config store
 	a.fcConfigStore = database.NewFCConfigStore(db)
 
+	// Initialize account data export (takeout); archives share the radio backups' storage
+	var exportBlobs storage.ObjectStore
+	if objectStore != nil {
+		exportBlobs = objectStore
+	}
+	a.ExportSvc = takeout.NewService(database.NewDataExportStore(db), takeout.Sources{
+		Profile:     a.userStore,
+		Inventory:   a.inventoryStore,
+		Aircraft:    a.aircraftStore,
+		Maintenance: a.aircraftStore,
+		FCConfigs:   a.fcConfigStore,
+		Batteries:   a.batteryStore,
+		Flights:     a.flightStore,
+		Orders:      a.orderStore,
+		Radios:      a.RadioSvc,
+		Builds:      a.BuildSvc,
+		Catalog:     a.gearCatalogStore,
+		Images:      a.imageAssetStore,
+	}, exportBlobs, a.Config.Storage.PresignTTL, a.Logger) // Nil store keeps archives on local disk
+
+	// Imports restore an export archive, possibly from another instance.
+	// Images go back through moderation via the aircraft and build services.
+	a.ImportSvc = takeout.NewImporter(database.NewDataImportStore(db), takeout.Targets{
+		Catalog:   a.gearCatalogStore,
+		Inventory: a.InventorySvc,
+		Aircraft:  a.AircraftSvc,
+		FCConfigs: a.fcConfigStore,
+		Batteries: a.BatterySvc,
+		Radios:    a.RadioSvc,
+		Builds:    a.BuildSvc,
+		Profile:   a.userStore,
+		Images:    a.imageSvc,
+	}, exportBlobs, a.Logger)
+
 	a.Logger.Info("Authentication service initialized")
+	return nil
 }
 
 func (a *App) initServers() {
@@ -297,10 +379,34 @@ func (a *App) initServers() {
 		a.Config.MCP.Auth.RequiredScopes,
 		a.Logger,
 	)
+	mcpHandler.SetFlightLogger(a.FlightSvc)
+	mcpHandler.SetBatteryCycleLogger(a.BatterySvc)
+	mcpHandler.SetInventoryWriter(a.InventorySvc)
+	mcpHandler.SetAircraftComponentWriter(a.AircraftSvc)
+	mcpHandler.SetFCConfigSaver(fcconfig.NewService(a.fcConfigStore, a.inventoryStore, a.Logger))
+	mcpHandler.SetBuildDraftCreator(a.BuildSvc)
+	mcpHandler.SetFCConfigReader(a.fcConfigStore)
+	mcpHandler.SetBuildReader(a.BuildSvc)
+	mcpHandler.SetCatalogSearcher(a.gearCatalogStore)
+	mcpHandler.SetGearCatalogReader(a.gearCatalogStore)
+	mcpHandler.SetPublicBuildReader(a.BuildSvc)
+	mcpHandler.SetBatteryHistoryReader(a.BatterySvc)
+	if a.Config.Server.EnableManualRefresh {
+		mcpHandler.SetNewsRefreshLimiter(a.refreshLimiter)
+	}
 	mcpProtocol := mcp.NewProtocol(mcpHandler, a.Logger)
 	a.MCPServer = mcp.NewServer(mcpProtocol, a.Logger)
 	a.MCPAuthService = auth.NewMCPAuthService(a.Config.MCP, a.userStore, a.Logger)
 	a.MCPHTTPHandler = mcp.NewHTTPHandler(mcpProtocol, a.MCPAuthService, a.Config.MCP.AllowedOrigins, a.Logger)
+	var mcpSessions mcp.SessionStore = mcp.NewInMemorySessionStore(a.Config.MCP.SessionTTL)
+	if redisCache, ok := a.Cache.(*cache.RedisCache); ok {
+		a.Logger.Info("Using Redis MCP session store")
+		mcpSessions = mcp.NewRedisSessionStore(redisCache.Client(), a.Config.MCP.SessionTTL)
+	} else {
+		a.Logger.Info("Using in-memory MCP session store")
+	}
+	a.MCPHTTPHandler.SetSessionStore(mcpSessions)
+	mcpHandler.SetResourceChangeNotifier(a.MCPHTTPHandler.ResourcesChanged)
 
 	// Initialize HTTP server with auth, aircraft, radio, battery, fc-config, gear-catalog, and MCP support.
 	a.HTTPServer = httpapi.New(
@@ -312,6 +418,10 @@ func (a *App) initServers() {
 		a.BuildSvc,
 		a.RadioSvc,
 		a.BatterySvc,
+		a.FlightSvc,
+		a.OrderSvc,
+		a.ExportSvc,
+		a.ImportSvc,
 		a.AuthService,
 		a.OAuthService,
 		a.AuthMiddleware,
@@ -328,6 +438,42 @@ func (a *App) initServers() {
 	)
 }
 
+// newObjectStore returns the configured S3 store, or nil to use the local defaults
+// (image bytes in Postgres, radio backups on disk) when no backend is configured.
+// A configured backend that can't be set up is an error rather than a silent
+// fallback, since objects already stored there would become unreadable.
+func (a *App) newObjectStore() (*storage.S3Store, error) {
+	if a.Config.Storage.Backend != "s3" {
+		return nil, nil
+	}
+
+	store, err := storage.NewS3StoreFromConfig(context.Background(), a.Config.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up S3 object storage: %w", err)
+	}
+	a.Logger.Info("Using S3 object storage", logging.WithField("bucket", a.Config.Storage.S3Bucket))
+	return store, nil
+}
+
+// newMailSender returns the SMTP sender, or one that writes mail to the log
+// when no SMTP relay is configured (local development). It returns nil, which
+// disables email sign-in, when the SMTP settings are unusable.
+func (a *App) newMailSender() mailer.Sender {
+	if a.Config.Mail.SMTPHost == "" {
+		a.Logger.Warn("SMTP not configured; sign-in emails will be written to the log")
+		return mailer.NewLogSender(a.Logger)
+	}
+
+	sender, err := mailer.NewSMTPSender(a.Config.Mail)
+	if err != nil {
+		a.Logger.Warn("SMTP setup failed; email sign-in is disabled",
+			logging.WithField("error", err.Error()))
+		return nil
+	}
+	a.Logger.Info("Sending mail through SMTP", logging.WithField("host", a.Config.Mail.SMTPHost))
+	return sender
+}
+
 func (a *App) newModerationService() (images.Moderator, error) {
 	if !a.Config.Moderation.Enabled {
 		a.Logger.Warn("Image moderation explicitly disabled; uploads will auto-approve")
@@ -343,7 +489,7 @@ func (a *App) newModerationService() (images.Moderator, error) {
 	if err != nil {
 		return nil, err
 	}
-	return moderation.NewService(detector, a.Config.Moderation.RejectConfidence), nil
+	return moderation.NewServiceWithReview(detector, a.Config.Moderation.RejectConfidence, a.Config.Moderation.ReviewConfidence), nil
 }
 
 func (a *App) runMCPMode(ctx context.Context) error {
@@ -363,6 +509,15 @@ func (a *App) runHTTPMode(ctx context.Context) error {
 	if a.BuildSvc != nil {
 		go a.runTempBuildCleanup(ctx)
 	}
+	if a.imageSvc != nil {
+		go a.runImageHashBackfill(ctx)
+	}
+	if a.OrderSvc != nil && a.Config.Orders.PollInterval > 0 {
+		go a.runOrderPolling(ctx)
+	}
+	if a.ExportSvc != nil {
+		go a.runDataExportCleanup(ctx)
+	}
 
 	return a.HTTPServer.Start(a.Config.Server.HTTPAddr)
 }
@@ -377,6 +532,30 @@ func (a *App) runRefreshOnceMode(ctx context.Context) error {
 	return nil
 }
 
+// imageHashBackfillLockKey is the Postgres advisory lock held while one
+// instance backfills perceptual hashes
+const imageHashBackfillLockKey int64 = 0x66660035
+
+// runImageHashBackfill computes perceptual hashes for images stored before
+// near-duplicate detection existed. New uploads are hashed on save. Only the
+// instance that takes the advisory lock runs it; the others skip it.
+func (a *App) runImageHashBackfill(ctx context.Context) {
+	hashed := 0
+	ran, err := a.db.TryAdvisoryLock(ctx, imageHashBackfillLockKey, func(ctx context.Context) error {
+		var err error
+		hashed, err = a.imageSvc.BackfillPerceptualHashes(ctx, 100)
+		return err
+	})
+	if err != nil {
+		a.Logger.Warn("Image hash backfill failed", logging.WithField("error", err.Error()))
+	} else if !ran {
+		a.Logger.Debug("Image hash backfill is running on another instance")
+	}
+	if hashed > 0 {
+		a.Logger.Info("Backfilled image perceptual hashes", logging.WithField("count", hashed))
+	}
+}
+
 func (a *App) runTempBuildCleanup(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Minute)
 	defer ticker.Stop()
@@ -404,3 +583,76 @@ func (a *App) runTempBuildCleanup(ctx context.Context) {
 		}
 	}
 }
+
+// runDataExportCleanup deletes export archives past their expiry and fails
+// imports interrupted by a restart
+func (a *App) runDataExportCleanup(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	cleanup := func() {
+		deleted, err := a.ExportSvc.CleanupExpired(ctx)
+		if err != nil {
+			a.Logger.Warn("Data export cleanup failed", logging.WithField("error", err.Error()))
+			return
+		}
+		if deleted > 0 {
+			a.Logger.Info("Removed expired data exports", logging.WithField("count", deleted))
+		}
+
+		if a.ImportSvc == nil {
+			return
+		}
+		failed, err := a.ImportSvc.CleanupStale(ctx)
+		if err != nil {
+			a.Logger.Warn("Data import cleanup failed", logging.WithField("error", err.Error()))
+			return
+		}
+		if failed > 0 {
+			a.Logger.Warn("Failed interrupted data imports", logging.WithField("count", failed))
+		}
+	}
+
+	// Run once at startup, then periodically.
+	cleanup()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cleanup()
+		}
+	}
+}
+
+// runOrderPolling checks open orders' carrier status. Each tick works through
+// a batch of the least recently checked orders, so a large backlog catches up
+// over several ticks rather than hammering the tracking API.
+func (a *App) runOrderPolling(ctx context.Context) {
+	ticker := time.NewTicker(a.Config.Orders.PollInterval)
+	defer ticker.Stop()
+
+	poll := func() {
+		checked, err := a.OrderSvc.PollDue(ctx, a.Config.Orders.RecheckAfter, 50)
+		if err != nil {
+			a.Logger.Warn("Order polling failed", logging.WithField("error", err.Error()))
+			return
+		}
+		if checked > 0 {
+			a.Logger.Debug("Checked order statuses", logging.WithField("count", checked))
+		}
+	}
+
+	// Run once at startup, then periodically.
+	poll()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/mailer"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	// At most maxEmailLoginLinks sign-in links are sent to one address per
+	// emailLoginLinkWindow, so the endpoint can't be used to flood an inbox
+	maxEmailLoginLinks   = 3
+	emailLoginLinkWindow = 15 * time.Minute
+
+	maxEmailAddressLength = 254
+)
+
+// RequestEmailLogin emails a single-use sign-in link to the address. It
+// succeeds whether or not an account exists, so it can't be used to find out
+// who has one; the account is created when the link is used.
+func (s *Service) RequestEmailLogin(ctx context.Context, params models.EmailLoginParams) error {
+	if s.mailer == nil {
+		return &AuthError{Code: "not_configured", Message: "email sign-in is not available"}
+	}
+
+	email, err := normalizeEmailAddress(params.Email)
+	if err != nil {
+		return err
+	}
+
+	s.pruneExpiredLoginState(ctx)
+
+	now := time.Now()
+	sent, err := s.userStore.CountEmailLoginTokensSince(ctx, email, now.Add(-emailLoginLinkWindow))
+	if err != nil {
+		return fmt.Errorf("failed to count sign-in links: %w", err)
+	}
+	if sent >= maxEmailLoginLinks {
+		return &AuthError{Code: "rate_limited", Message: "too many sign-in links requested; try again in a few minutes"}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate sign-in link: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	if _, err := s.userStore.CreateEmailLoginToken(ctx, email, hashToken(token), now.Add(s.config.EmailLinkTTL)); err != nil {
+		return fmt.Errorf("failed to store sign-in link: %w", err)
+	}
+
+	if err := s.mailer.Send(ctx, s.emailLoginMessage(email, token)); err != nil {
+		return fmt.Errorf("failed to send sign-in email: %w", err)
+	}
+	return nil
+}
+
+// LoginWithEmailLink signs in with the token from an emailed link, creating
+// the account or linking the address to an existing one on first use
+func (s *Service) LoginWithEmailLink(ctx context.Context, params models.EmailLoginVerifyParams) (*models.AuthResponse, error) {
+	token := strings.TrimSpace(params.Token)
+	if token == "" {
+		return nil, &AuthError{Code: "invalid_input", Message: "token is required"}
+	}
+
+	stored, err := s.userStore.ConsumeEmailLoginToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check sign-in link: %w", err)
+	}
+	if stored == nil {
+		return nil, &AuthError{Code: "invalid_token", Message: "sign-in link is invalid or has expired"}
+	}
+
+	user, isNewUser, isLinked, err := s.resolveEmailUser(ctx, stored.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, isNewUser, isLinked)
+}
+
+// resolveEmailUser finds or creates the user for a verified address. Holding
+// the link proves the address, as a verified email claim does for Google.
+func (s *Service) resolveEmailUser(ctx context.Context, email string) (*models.User, bool, bool, error) {
+	identity, err := s.userStore.GetIdentityByProvider(ctx, models.AuthProviderEmail, email)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("failed to check identity: %w", err)
+	}
+
+	if identity != nil {
+		user, err := s.userStore.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return nil, false, false, &AuthError{Code: "user_not_found", Message: "user not found"}
+		}
+		return user, false, false, nil
+	}
+
+	user, err := s.userStore.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("failed to check user: %w", err)
+	}
+
+	if user != nil {
+		if _, err := s.linkIdentity(ctx, user.ID, models.AuthProviderEmail, email, email); err != nil {
+			return nil, false, false, err
+		}
+		s.logger.Info("Linked email identity to existing user", logging.WithField("userId", user.ID))
+		return user, false, true, nil
+	}
+
+	user, err = s.userStore.Create(ctx, models.CreateUserParams{
+		Email:       email,
+		DisplayName: "",
+		Status:      models.UserStatusActive,
+	})
+	if err != nil {
+		return nil, false, false, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if _, err := s.linkIdentity(ctx, user.ID, models.AuthProviderEmail, email, email); err != nil {
+		return nil, false, false, err
+	}
+
+	s.logger.Info("Created new user via email sign-in link", logging.WithField("userId", user.ID))
+	return user, true, false, nil
+}
+
+// emailLoginMessage builds the sign-in email. The token goes in the URL
+// fragment so it stays out of server and proxy logs; the frontend posts it
+// to /api/auth/email/verify.
+func (s *Service) emailLoginMessage(email, token string) mailer.Message {
+	link := s.config.EmailLinkURL + "#token=" + token
+	minutes := max(1, int(s.config.EmailLinkTTL.Round(time.Minute)/time.Minute))
+
+	return mailer.Message{
+		To:      email,
+		Subject: "Your FlyingForge sign-in link",
+		Text: fmt.Sprintf(
+			"Use this link to sign in to FlyingForge:\n\n%s\n\n"+
+				"The link works once and expires in %d minutes. "+
+				"If you didn't ask to sign in, you can ignore this email.\n",
+			link, minutes),
+	}
+}
+
+// normalizeEmailAddress validates a bare address and lowercases it, as
+// addresses are compared case-insensitively everywhere else
+func normalizeEmailAddress(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", &AuthError{Code: "invalid_input", Message: "email is required"}
+	}
+	parsed, err := mail.ParseAddress(value)
+	if err != nil || parsed.Address != value || len(value) > maxEmailAddressLength {
+		return "", &AuthError{Code: "invalid_input", Message: "email address is invalid"}
+	}
+	return strings.ToLower(value), nil
+}
+
+func (s *Service) pruneExpiredLoginState(ctx context.Context) {
+	// Links are kept for a window past expiry so they still count toward the send limit
+	if err := s.userStore.CleanupExpiredLoginState(ctx, time.Now().Add(-emailLoginLinkWindow)); err != nil {
+		s.logger.Warn("Failed to clean up expired sign-in state", logging.WithField("error", err.Error()))
+	}
+}
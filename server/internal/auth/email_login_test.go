@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/config"
+	"github.com/johnrirwin/flyingforge/internal/mailer"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// recordingSender keeps sent mail instead of delivering it
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg mailer.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *recordingSender) last(t *testing.T) mailer.Message {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.messages) == 0 {
+		t.Fatal("no email was sent")
+	}
+	return s.messages[len(s.messages)-1]
+}
+
+// linkToken extracts the token from a sign-in email's link
+func linkToken(t *testing.T, msg mailer.Message) string {
+	t.Helper()
+	const marker = "https://flyingforge.example/auth/email#token="
+	start := strings.Index(msg.Text, marker)
+	if start < 0 {
+		t.Fatalf("sign-in link not found in %q", msg.Text)
+	}
+	return strings.Fields(msg.Text[start+len(marker):])[0]
+}
+
+func TestNormalizeEmailAddress(t *testing.T) {
+	valid := map[string]string{
+		"pilot@example.com":         "pilot@example.com",
+		"  Pilot.Name@Example.COM ": "pilot.name@example.com",
+	}
+	for input, want := range valid {
+		if got, err := normalizeEmailAddress(input); err != nil || got != want {
+			t.Errorf("normalizeEmailAddress(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"not-an-address",
+		"Pilot <pilot@example.com>",
+		"pilot@example.com, other@example.com",
+		strings.Repeat("a", 250) + "@example.com",
+	}
+	for _, input := range invalid {
+		if _, err := normalizeEmailAddress(input); err == nil {
+			t.Errorf("expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestEmailLoginMessageKeepsTokenInFragment(t *testing.T) {
+	service := &Service{config: config.AuthConfig{
+		EmailLinkURL: "https://flyingforge.example/auth/email",
+		EmailLinkTTL: 15 * time.Minute,
+	}}
+
+	msg := service.emailLoginMessage("pilot@example.com", "abc_123-XYZ")
+	if msg.To != "pilot@example.com" || msg.Subject == "" {
+		t.Fatalf("unexpected envelope %+v", msg)
+	}
+	if !strings.Contains(msg.Text, "https://flyingforge.example/auth/email#token=abc_123-XYZ\n") {
+		t.Errorf("expected the token in the link's fragment, got %q", msg.Text)
+	}
+	if !strings.Contains(msg.Text, "expires in 15 minutes") {
+		t.Errorf("expected the link lifetime in the message, got %q", msg.Text)
+	}
+}
+
+func TestRequestEmailLoginRequiresMailSender(t *testing.T) {
+	service := &Service{}
+	err := service.RequestEmailLogin(context.Background(), models.EmailLoginParams{Email: "pilot@example.com"})
+	if authErr, ok := err.(*AuthError); !ok || authErr.Code != "not_configured" {
+		t.Fatalf("expected not_configured, got %v", err)
+	}
+}
+
+func TestEmailLoginCreatesThenSignsInUser(t *testing.T) {
+	service := setupTestAuthService(t)
+	sender := &recordingSender{}
+	service.SetMailSender(sender)
+	ctx := context.Background()
+	email := fmt.Sprintf("email-login-%d@example.com", time.Now().UnixNano())
+
+	if err := service.RequestEmailLogin(ctx, models.EmailLoginParams{Email: strings.ToUpper(email)}); err != nil {
+		t.Fatalf("RequestEmailLogin: %v", err)
+	}
+	token := linkToken(t, sender.last(t))
+
+	response, err := service.LoginWithEmailLink(ctx, models.EmailLoginVerifyParams{Token: token})
+	if err != nil {
+		t.Fatalf("LoginWithEmailLink: %v", err)
+	}
+	if !response.IsNewUser || response.User.Email != email || response.Tokens.AccessToken == "" {
+		t.Fatalf("expected a new user signed in as %s, got %+v", email, response)
+	}
+
+	if _, err := service.LoginWithEmailLink(ctx, models.EmailLoginVerifyParams{Token: token}); err == nil {
+		t.Fatal("expected a used link to be rejected")
+	}
+
+	if err := service.RequestEmailLogin(ctx, models.EmailLoginParams{Email: email}); err != nil {
+		t.Fatalf("RequestEmailLogin: %v", err)
+	}
+	again, err := service.LoginWithEmailLink(ctx, models.EmailLoginVerifyParams{Token: linkToken(t, sender.last(t))})
+	if err != nil {
+		t.Fatalf("LoginWithEmailLink: %v", err)
+	}
+	if again.IsNewUser || again.IsLinked || again.User.ID != response.User.ID {
+		t.Errorf("expected the same user to sign in again, got %+v", again)
+	}
+
+	identities, err := service.userStore.GetIdentitiesByUserID(ctx, response.User.ID)
+	if err != nil {
+		t.Fatalf("GetIdentitiesByUserID: %v", err)
+	}
+	if len(identities) != 1 || identities[0].Provider != models.AuthProviderEmail || identities[0].ProviderSubject != email {
+		t.Errorf("expected one email identity, got %+v", identities)
+	}
+
+	if err := service.RequestEmailLogin(ctx, models.EmailLoginParams{Email: email}); err != nil {
+		t.Fatalf("RequestEmailLogin: %v", err)
+	}
+	err = service.RequestEmailLogin(ctx, models.EmailLoginParams{Email: email})
+	if authErr, ok := err.(*AuthError); !ok || authErr.Code != "rate_limited" {
+		t.Errorf("expected the fourth link in the window to be rate limited, got %v", err)
+	}
+}
+
+func TestEmailLoginLinksExistingAccount(t *testing.T) {
+	service := setupTestAuthService(t)
+	sender := &recordingSender{}
+	service.SetMailSender(sender)
+	ctx := context.Background()
+	email := fmt.Sprintf("email-link-%d@example.com", time.Now().UnixNano())
+
+	existing, err := service.userStore.Create(ctx, models.CreateUserParams{Email: email, Status: models.UserStatusActive})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := service.userStore.CreateIdentity(ctx, existing.ID, models.AuthProviderGoogle, "google-"+email, email); err != nil {
+		t.Fatalf("CreateIdentity: %v", err)
+	}
+
+	if err := service.RequestEmailLogin(ctx, models.EmailLoginParams{Email: email}); err != nil {
+		t.Fatalf("RequestEmailLogin: %v", err)
+	}
+	response, err := service.LoginWithEmailLink(ctx, models.EmailLoginVerifyParams{Token: linkToken(t, sender.last(t))})
+	if err != nil {
+		t.Fatalf("LoginWithEmailLink: %v", err)
+	}
+	if response.IsNewUser || !response.IsLinked || response.User.ID != existing.ID {
+		t.Fatalf("expected the email to be linked to the existing account, got %+v", response)
+	}
+
+	identities, err := service.userStore.GetIdentitiesByUserID(ctx, existing.ID)
+	if err != nil {
+		t.Fatalf("GetIdentitiesByUserID: %v", err)
+	}
+	if len(identities) != 2 {
+		t.Errorf("expected Google and email identities, got %+v", identities)
+	}
+}
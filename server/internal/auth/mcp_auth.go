@@ -7,6 +7,7 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -23,7 +24,7 @@ import (
 )
 
 const (
-	defaultMCPReadScope = "flyingforge.read"
+	defaultMCPReadScope = models.MCPScopeRead
 	jwksCacheTTL        = 10 * time.Minute
 )
 
@@ -36,6 +37,18 @@ type MCPProtectedResourceMetadata struct {
 	ResourceDocumentation string   `json:"resource_documentation,omitempty"`
 }
 
+// MCPPrincipal is the FlyingForge user behind an MCP access token and the
+// scopes the token was granted.
+type MCPPrincipal struct {
+	UserID string
+	Scopes []string
+}
+
+// HasScope reports whether the token was granted scope.
+func (p *MCPPrincipal) HasScope(scope string) bool {
+	return p != nil && containsString(p.Scopes, scope)
+}
+
 // MCPAuthError describes an MCP OAuth failure in a way the transport can turn
 // into WWW-Authenticate challenges and tool-level auth prompts.
 type MCPAuthError struct {
@@ -141,8 +154,28 @@ func (s *MCPAuthService) RequiredScopes() []string {
 	return append([]string(nil), s.cfg.Auth.RequiredScopes...)
 }
 
-func (s *MCPAuthService) Challenge(errorCode, description string) string {
-	scope := strings.Join(s.RequiredScopes(), " ")
+// SupportedScopes returns the required read scopes followed by every MCP
+// write scope.
+func (s *MCPAuthService) SupportedScopes() []string {
+	scopes := s.RequiredScopes()
+	for _, scope := range models.MCPWriteScopes() {
+		if !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// Challenge builds a WWW-Authenticate value. The scope parameter lists the
+// required read scopes plus any extra scopes the failed call needed.
+func (s *MCPAuthService) Challenge(errorCode, description string, scopes ...string) string {
+	challengeScopes := s.RequiredScopes()
+	for _, scope := range scopes {
+		if scope != "" && !containsString(challengeScopes, scope) {
+			challengeScopes = append(challengeScopes, scope)
+		}
+	}
+	scope := strings.Join(challengeScopes, " ")
 	resourceMetadataURL := s.ResourceMetadataURL()
 
 	params := []string{}
@@ -187,14 +220,14 @@ func (s *MCPAuthService) ProtectedResourceMetadata() *MCPProtectedResourceMetada
 	return &MCPProtectedResourceMetadata{
 		Resource:              resource,
 		AuthorizationServers:  []string{strings.TrimRight(strings.TrimSpace(s.cfg.Auth.Issuer), "/")},
-		ScopesSupported:       s.RequiredScopes(),
+		ScopesSupported:       s.SupportedScopes(),
 		ResourceDocumentation: resourceDocumentation,
 	}
 }
 
-func (s *MCPAuthService) AuthenticateBearerToken(ctx context.Context, token string) (string, error) {
+func (s *MCPAuthService) AuthenticateBearerToken(ctx context.Context, token string) (*MCPPrincipal, error) {
 	if !s.Enabled() {
-		return "", &MCPAuthError{
+		return nil, &MCPAuthError{
 			Code:    "invalid_token",
 			Message: "MCP authentication is not configured",
 			Scope:   strings.Join(s.RequiredScopes(), " "),
@@ -203,7 +236,7 @@ func (s *MCPAuthService) AuthenticateBearerToken(ctx context.Context, token stri
 
 	token = strings.TrimSpace(token)
 	if token == "" {
-		return "", &MCPAuthError{
+		return nil, &MCPAuthError{
 			Code:    "invalid_token",
 			Message: "Authentication required: no access token provided.",
 			Scope:   strings.Join(s.RequiredScopes(), " "),
@@ -212,24 +245,24 @@ func (s *MCPAuthService) AuthenticateBearerToken(ctx context.Context, token stri
 
 	claims, err := s.verifyToken(ctx, token)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	identity, err := extractMCPIdentity(claims)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	userID, err := s.resolveUser(ctx, identity)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if err := s.userStore.UpdateLastLogin(ctx, userID); err != nil {
 		s.logger.Warn("Failed to update MCP user last login", logging.WithField("error", err.Error()))
 	}
 
-	return userID, nil
+	return &MCPPrincipal{UserID: userID, Scopes: grantedScopes(claims)}, nil
 }
 
 func (s *MCPAuthService) verifyToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
@@ -556,20 +589,14 @@ func (s *MCPAuthService) resolveUser(ctx context.Context, identity *mcpIdentity)
 		}
 	}
 
-	if _, err := s.linkIdentity(ctx, user.ID, models.AuthProviderMCPOAuth, subjectKey, email); err != nil && !isIdentityAlreadyLinkedError(err) {
-		return "", err
-	} else if err != nil {
-		linkedIdentity, lookupErr := s.lookupIdentityByProvider(ctx, models.AuthProviderMCPOAuth, subjectKey)
-		if lookupErr != nil {
-			return "", lookupErr
-		}
-		if linkedIdentity == nil || linkedIdentity.UserID != user.ID {
-			return "", &MCPAuthError{
-				Code:    "invalid_token",
-				Message: "Authentication failed: this MCP identity is already linked to another FlyingForge account",
-				Scope:   strings.Join(s.RequiredScopes(), " "),
-			}
+	if _, err := s.linkIdentity(ctx, user.ID, models.AuthProviderMCPOAuth, subjectKey, email); errors.Is(err, errIdentityLinkedElsewhere) {
+		return "", &MCPAuthError{
+			Code:    "invalid_token",
+			Message: "Authentication failed: this MCP identity is already linked to another FlyingForge account",
+			Scope:   strings.Join(s.RequiredScopes(), " "),
 		}
+	} else if err != nil {
+		return "", err
 	}
 
 	return user.ID, nil
@@ -583,10 +610,11 @@ func (s *MCPAuthService) lookupIdentityByProvider(ctx context.Context, provider
 }
 
 func (s *MCPAuthService) linkIdentity(ctx context.Context, userID string, provider models.AuthProvider, subject, email string) (*models.UserIdentity, error) {
+	create := s.userStore.CreateIdentity
 	if s.createIdentity != nil {
-		return s.createIdentity(ctx, userID, provider, subject, email)
+		create = s.createIdentity
 	}
-	return s.userStore.CreateIdentity(ctx, userID, provider, subject, email)
+	return linkUserIdentity(ctx, create, s.lookupIdentityByProvider, userID, provider, subject, email)
 }
 
 func waitForCacheLoad(ctx context.Context, wait <-chan struct{}) error {
@@ -654,16 +682,9 @@ func hasRequiredScopes(claims jwt.MapClaims, required []string) bool {
 		return true
 	}
 
-	scopeValues := map[string]struct{}{}
-	for _, scope := range claimScopeStrings(claims["scope"]) {
-		scopeValues[scope] = struct{}{}
-	}
-	for _, scope := range claimScopeStrings(claims["scp"]) {
-		scopeValues[scope] = struct{}{}
-	}
-
+	granted := grantedScopes(claims)
 	for _, requiredScope := range required {
-		if _, ok := scopeValues[requiredScope]; !ok {
+		if !containsString(granted, requiredScope) {
 			return false
 		}
 	}
@@ -671,6 +692,11 @@ func hasRequiredScopes(claims jwt.MapClaims, required []string) bool {
 	return true
 }
 
+// grantedScopes merges the space-delimited scope claim and the scp claim.
+func grantedScopes(claims jwt.MapClaims) []string {
+	return uniqueStrings(append(claimScopeStrings(claims["scope"]), claimScopeStrings(claims["scp"])...))
+}
+
 func claimScopeStrings(value interface{}) []string {
 	rawValues := claimStrings(value)
 	if len(rawValues) == 0 {
@@ -290,14 +290,19 @@ func TestMCPAuthAuthenticateBearerTokenLinksVerifiedEmail(t *testing.T) {
 	}
 	service.client = provider.server.Client()
 
-	token := provider.signToken(t, baseClaims(provider.server.URL))
+	claims := baseClaims(provider.server.URL)
+	claims["scope"] = "flyingforge.read inventory:write"
+	token := provider.signToken(t, claims)
 
-	userID, err := service.AuthenticateBearerToken(ctx, token)
+	principal, err := service.AuthenticateBearerToken(ctx, token)
 	if err != nil {
 		t.Fatalf("expected bearer token to authenticate, got %v", err)
 	}
-	if userID != existingUser.ID {
-		t.Fatalf("expected existing user ID %s, got %s", existingUser.ID, userID)
+	if principal.UserID != existingUser.ID {
+		t.Fatalf("expected existing user ID %s, got %s", existingUser.ID, principal.UserID)
+	}
+	if !principal.HasScope("inventory:write") || principal.HasScope("batteries:write") {
+		t.Fatalf("expected granted scopes from the token, got %v", principal.Scopes)
 	}
 
 	identity, err := userStore.GetIdentityByProvider(ctx, models.AuthProviderMCPOAuth, provider.server.URL+"|subject-123")
@@ -665,4 +670,9 @@ func TestMCPAuthChallengeFormatsBearerHeader(t *testing.T) {
 	if !strings.Contains(challenge, `error_description="needs 'quotes'"`) {
 		t.Fatalf("expected sanitized description in challenge, got %q", challenge)
 	}
+
+	challenge = service.Challenge("insufficient_scope", "needs write access", "batteries:write")
+	if !strings.Contains(challenge, `scope="flyingforge.read batteries:write"`) {
+		t.Fatalf("expected the missing write scope in challenge, got %q", challenge)
+	}
 }
@@ -2,8 +2,11 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
 )
 
 // contextKey is a type for context keys
@@ -24,18 +27,29 @@ func NewMiddleware(authService *Service) *Middleware {
 	return &Middleware{authService: authService}
 }
 
-// RequireAuth is middleware that requires a valid JWT token
+// RequireAuth is middleware that requires a valid JWT token or a personal
+// access token carrying the scope the request needs
 func (m *Middleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		token := extractToken(r)
 		if token == "" {
-			http.Error(w, `{"error":"authorization required"}`, http.StatusUnauthorized)
+			writeAuthFailure(w, http.StatusUnauthorized, "authorization required")
+			return
+		}
+
+		if IsPersonalAccessToken(token) {
+			userID, status, err := m.authenticatePersonalAccessToken(r, token)
+			if err != nil {
+				writeAuthFailure(w, status, err.Message)
+				return
+			}
+			next(w, r.WithContext(context.WithValue(r.Context(), UserIDKey, userID)))
 			return
 		}
 
 		userID, err := m.authService.ValidateAccessToken(token)
 		if err != nil {
-			http.Error(w, `{"error":"invalid or expired token"}`, http.StatusUnauthorized)
+			writeAuthFailure(w, http.StatusUnauthorized, "invalid or expired token")
 			return
 		}
 
@@ -49,7 +63,11 @@ func (m *Middleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 func (m *Middleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		token := extractToken(r)
-		if token != "" {
+		if IsPersonalAccessToken(token) {
+			if userID, _, err := m.authenticatePersonalAccessToken(r, token); err == nil {
+				r = r.WithContext(context.WithValue(r.Context(), UserIDKey, userID))
+			}
+		} else if token != "" {
 			userID, err := m.authService.ValidateAccessToken(token)
 			if err == nil {
 				ctx := context.WithValue(r.Context(), UserIDKey, userID)
@@ -60,6 +78,43 @@ func (m *Middleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// authenticatePersonalAccessToken resolves a personal access token to its
+// user. Tokens are only accepted in the Authorization header, never the
+// query string, and must carry the scope the route needs.
+func (m *Middleware) authenticatePersonalAccessToken(r *http.Request, token string) (string, int, *AuthError) {
+	if m.authService == nil || extractBearerToken(r) != token {
+		return "", http.StatusUnauthorized, &AuthError{Code: "invalid_token", Message: "invalid or expired token"}
+	}
+
+	accessToken, err := m.authService.ValidatePersonalAccessToken(r.Context(), token)
+	if err != nil {
+		if authErr, ok := err.(*AuthError); ok {
+			return "", http.StatusUnauthorized, authErr
+		}
+		m.authService.logger.Error("Failed to validate personal access token", logging.WithField("error", err.Error()))
+		return "", http.StatusInternalServerError, &AuthError{Code: "internal_error", Message: "failed to validate token"}
+	}
+
+	scope := RequiredPersonalAccessTokenScope(r.Method, r.URL.Path)
+	if scope == "" {
+		return "", http.StatusForbidden, &AuthError{Code: "insufficient_scope", Message: "personal access tokens cannot be used here"}
+	}
+	if !containsString(accessToken.Scopes, scope) {
+		return "", http.StatusForbidden, &AuthError{Code: "insufficient_scope", Message: "token is missing the " + scope + " scope"}
+	}
+
+	return accessToken.UserID, http.StatusOK, nil
+}
+
+// writeAuthFailure writes a JSON error body like the one http.Error would,
+// encoding the message so it can't break out of the JSON string
+func writeAuthFailure(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
 // GetUserID extracts the user ID from the request context
 func GetUserID(ctx context.Context) string {
 	userID, _ := ctx.Value(UserIDKey).(string)
@@ -69,12 +124,8 @@ func GetUserID(ctx context.Context) string {
 // extractToken extracts the JWT token from the Authorization header or query parameter
 func extractToken(r *http.Request) string {
 	// First check Authorization header
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" {
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
-			return parts[1]
-		}
+	if token := extractBearerToken(r); token != "" {
+		return token
 	}
 
 	// Fall back to query parameter (for image URLs in img tags)
@@ -84,3 +135,15 @@ func extractToken(r *http.Request) string {
 
 	return ""
 }
+
+// extractBearerToken extracts the token from the Authorization header only
+func extractBearerToken(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader != "" {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+			return parts[1]
+		}
+	}
+	return ""
+}
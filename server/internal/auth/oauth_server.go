@@ -253,7 +253,7 @@ func (s *OAuthServerService) AuthorizationServerMetadata() *OAuthAuthorizationSe
 		RegistrationEndpoint:  issuer + "/oauth/register",
 		JWKSURI:               issuer + "/oauth/jwks.json",
 		AuthorizationResponseIssParameterSupported: true,
-		ScopesSupported:                   append([]string(nil), s.mcpCfg.Auth.RequiredScopes...),
+		ScopesSupported:                   s.supportedScopes(),
 		ResponseTypesSupported:            []string{models.OAuthResponseTypeCode},
 		ResponseModesSupported:            []string{"query", "web_message", "web_message.opener"},
 		GrantTypesSupported:               []string{models.OAuthGrantTypeAuthorizationCode, models.OAuthGrantTypeRefreshToken},
@@ -457,8 +457,17 @@ func (s *OAuthServerService) validateAuthorizationRequest(ctx context.Context, r
 	if err != nil {
 		return nil, err
 	}
-	if client.Scope != "" && scope != client.Scope {
-		return nil, &OAuthError{Code: "invalid_scope", Description: "requested scope does not match the registered client scope", StatusCode: 400}
+	if client.Scope != "" {
+		// Clients may ask for less than they registered, never more
+		if strings.TrimSpace(req.Scope) == "" {
+			scope = client.Scope
+		}
+		registered := strings.Fields(client.Scope)
+		for _, requested := range strings.Fields(scope) {
+			if !containsString(registered, requested) {
+				return nil, &OAuthError{Code: "invalid_scope", Description: "requested scope exceeds the registered client scope", StatusCode: 400}
+			}
+		}
 	}
 
 	resource := strings.TrimSpace(req.Resource)
@@ -952,28 +961,31 @@ func isSupportedAuthorizationResponseMode(mode string) bool {
 	}
 }
 
+// supportedScopes returns the required read scopes followed by every MCP
+// write scope a client can be granted.
+func (s *OAuthServerService) supportedScopes() []string {
+	return uniqueStrings(append(append([]string(nil), s.mcpCfg.Auth.RequiredScopes...), models.MCPWriteScopes()...))
+}
+
+// normalizeRequestedScope validates a space-delimited scope request and
+// returns it sorted. The required read scopes are always granted, since MCP
+// tokens without them are rejected outright.
 func (s *OAuthServerService) normalizeRequestedScope(raw string) (string, error) {
 	requested := strings.Fields(strings.TrimSpace(raw))
 	if len(requested) == 0 {
 		return strings.Join(s.mcpCfg.Auth.RequiredScopes, " "), nil
 	}
 
-	allowed := make(map[string]struct{}, len(s.mcpCfg.Auth.RequiredScopes))
-	for _, scope := range s.mcpCfg.Auth.RequiredScopes {
+	allowed := make(map[string]struct{})
+	for _, scope := range s.supportedScopes() {
 		allowed[scope] = struct{}{}
 	}
-	seen := map[string]struct{}{}
-	normalized := make([]string, 0, len(requested))
 	for _, scope := range requested {
 		if _, ok := allowed[scope]; !ok {
 			return "", &OAuthError{Code: "invalid_scope", Description: "requested scope is not supported", StatusCode: 400}
 		}
-		if _, ok := seen[scope]; ok {
-			continue
-		}
-		seen[scope] = struct{}{}
-		normalized = append(normalized, scope)
 	}
+	normalized := uniqueStrings(append(requested, s.mcpCfg.Auth.RequiredScopes...))
 	sort.Strings(normalized)
 	return strings.Join(normalized, " "), nil
 }
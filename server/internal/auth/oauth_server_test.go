@@ -158,6 +158,99 @@ func TestOAuthServerService_AuthorizationCodeAndRefreshFlow(t *testing.T) {
 	}
 }
 
+func TestOAuthServerService_GrantsRegisteredWriteScopes(t *testing.T) {
+	service, userStore := setupTestOAuthServerService(t)
+	ctx := context.Background()
+
+	user, err := userStore.Create(ctx, models.CreateUserParams{
+		Email:  "writer@example.com",
+		Status: models.UserStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	if _, err := service.RegisterClient(ctx, OAuthDynamicClientRegistrationRequest{
+		RedirectURIs: []string{"https://chat.openai.com/a/oauth/callback"},
+		Scope:        "admin:write",
+	}); err == nil {
+		t.Fatal("expected unknown scope to be rejected at registration")
+	}
+
+	registration, err := service.RegisterClient(ctx, OAuthDynamicClientRegistrationRequest{
+		ClientName:   "Battery Assistant",
+		RedirectURIs: []string{"https://chat.openai.com/a/oauth/callback"},
+		Scope:        "inventory:write batteries:write",
+	})
+	if err != nil {
+		t.Fatalf("register client: %v", err)
+	}
+	if registration.Scope != "batteries:write flyingforge.read inventory:write" {
+		t.Fatalf("expected write scopes plus the read scope, got %q", registration.Scope)
+	}
+
+	authorize := func(scope string) (*OAuthAuthorizationPrompt, error) {
+		values := url.Values{}
+		values.Set("response_type", "code")
+		values.Set("client_id", registration.ClientID)
+		values.Set("redirect_uri", registration.RedirectURIs[0])
+		values.Set("scope", scope)
+		values.Set("code_challenge", codeChallengeForVerifier("test-code-verifier-1234567890"))
+		values.Set("code_challenge_method", "S256")
+		authRequest, err := service.ParseAuthorizationRequest(values)
+		if err != nil {
+			return nil, err
+		}
+		return service.DescribeAuthorizationRequest(ctx, authRequest, user.ID)
+	}
+
+	prompt, err := authorize("inventory:write")
+	if err != nil {
+		t.Fatalf("expected a registered write scope to be grantable, got %v", err)
+	}
+	if prompt.Scope != "flyingforge.read inventory:write" {
+		t.Fatalf("expected the read scope to be granted alongside inventory:write, got %q", prompt.Scope)
+	}
+
+	prompt, err = authorize("")
+	if err != nil {
+		t.Fatalf("expected an empty scope request to succeed, got %v", err)
+	}
+	if prompt.Scope != registration.Scope {
+		t.Fatalf("expected the registered scope by default, got %q", prompt.Scope)
+	}
+
+	if _, err := authorize("builds:write"); err == nil {
+		t.Fatal("expected a scope the client did not register to be rejected")
+	}
+
+	metadata := service.AuthorizationServerMetadata()
+	if !containsString(metadata.ScopesSupported, "flyingforge.read") || !containsString(metadata.ScopesSupported, "builds:write") {
+		t.Fatalf("expected read and write scopes in metadata, got %v", metadata.ScopesSupported)
+	}
+}
+
+func TestOAuthServerService_NormalizeRequestedScope(t *testing.T) {
+	service := &OAuthServerService{mcpCfg: config.MCPConfig{Auth: config.MCPAuthConfig{RequiredScopes: []string{"flyingforge.read"}}}}
+
+	tests := map[string]string{
+		"":                 "flyingforge.read",
+		"flyingforge.read": "flyingforge.read",
+		"inventory:write":  "flyingforge.read inventory:write",
+		"flights:write aircraft:write flights:write": "aircraft:write flights:write flyingforge.read",
+	}
+	for raw, want := range tests {
+		got, err := service.normalizeRequestedScope(raw)
+		if err != nil || got != want {
+			t.Errorf("normalizeRequestedScope(%q) = %q, %v, want %q", raw, got, err, want)
+		}
+	}
+
+	if _, err := service.normalizeRequestedScope("flyingforge.read admin:write"); err == nil {
+		t.Error("expected unsupported scope to be rejected")
+	}
+}
+
 func TestOAuthServerService_ParseAuthorizationRequestSupportsPopupResponseModes(t *testing.T) {
 	service, _ := setupTestOAuthServerService(t)
 
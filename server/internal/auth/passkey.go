@@ -0,0 +1,360 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	// passkeyCeremonyTimeout is how long a registration or sign-in challenge
+	// can be answered
+	passkeyCeremonyTimeout = 5 * time.Minute
+
+	passkeyPurposeRegistration = "registration"
+	passkeyPurposeLogin        = "login"
+
+	maxPasskeysPerUser   = 10
+	maxPasskeyNameLength = 100
+	defaultPasskeyName   = "Passkey"
+)
+
+// BeginPasskeyRegistration returns the options for adding a passkey to the
+// signed-in user's account
+func (s *Service) BeginPasskeyRegistration(ctx context.Context, userID string) (*models.PasskeyCreationOptions, error) {
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, &AuthError{Code: "user_not_found", Message: "user not found"}
+	}
+
+	existing, err := s.userStore.ListPasskeyCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxPasskeysPerUser {
+		return nil, &AuthError{
+			Code:    "invalid_input",
+			Message: fmt.Sprintf("you can have at most %d passkeys; remove one first", maxPasskeysPerUser),
+		}
+	}
+
+	challenge, err := s.newPasskeyChallenge(ctx, passkeyPurposeRegistration, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make([]models.PasskeyCredentialDescriptor, 0, len(existing))
+	for _, credential := range existing {
+		exclude = append(exclude, models.PasskeyCredentialDescriptor{
+			Type:       "public-key",
+			ID:         credential.CredentialID,
+			Transports: credential.Transports,
+		})
+	}
+
+	return &models.PasskeyCreationOptions{
+		Challenge: challenge,
+		RP: models.PasskeyRelyingParty{
+			ID:   s.config.PasskeyRPID,
+			Name: s.config.PasskeyRPName,
+		},
+		User: models.PasskeyUserEntity{
+			ID:          base64.RawURLEncoding.EncodeToString([]byte(user.ID)),
+			Name:        user.Email,
+			DisplayName: user.EffectiveDisplayName(),
+		},
+		PubKeyCredParams:   passkeyCredentialParams(),
+		Timeout:            int(passkeyCeremonyTimeout / time.Millisecond),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: models.PasskeyAuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies a new credential and links it to the
+// signed-in user as a passkey identity
+func (s *Service) FinishPasskeyRegistration(ctx context.Context, userID string, params models.PasskeyRegistrationParams) (*models.PasskeyCredential, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	if len(name) > maxPasskeyNameLength {
+		return nil, &AuthError{Code: "invalid_input", Message: fmt.Sprintf("name must be at most %d characters", maxPasskeyNameLength)}
+	}
+
+	credential := params.Credential
+	if credential.Type != "public-key" {
+		return nil, &AuthError{Code: "invalid_input", Message: "credential type must be public-key"}
+	}
+	rawID, err := decodeBase64URL(credential.RawID)
+	if err != nil || len(rawID) == 0 {
+		return nil, &AuthError{Code: "invalid_input", Message: "credential rawId is invalid"}
+	}
+	clientDataJSON, err := decodeBase64URL(credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, &AuthError{Code: "invalid_input", Message: "clientDataJSON is invalid"}
+	}
+	attestationObject, err := decodeBase64URL(credential.Response.AttestationObject)
+	if err != nil {
+		return nil, &AuthError{Code: "invalid_input", Message: "attestationObject is invalid"}
+	}
+
+	parsed, err := parseRegistration(rawID, clientDataJSON, attestationObject, credential.Response.Transports)
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+	challenge := parsed.Response.CollectedClientData.Challenge
+	if err := s.consumePasskeyChallenge(ctx, challenge, passkeyPurposeRegistration, userID); err != nil {
+		return nil, err
+	}
+	if err := s.relyingParty().verifyRegistration(parsed, challenge); err != nil {
+		return nil, passkeyError(err)
+	}
+
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, &AuthError{Code: "user_not_found", Message: "user not found"}
+	}
+
+	authData := parsed.Response.AttestationObject.AuthData
+	created, err := s.userStore.CreatePasskeyCredential(ctx, &models.PasskeyCredential{
+		UserID:       userID,
+		CredentialID: base64.RawURLEncoding.EncodeToString(authData.AttData.CredentialID),
+		PublicKey:    authData.AttData.CredentialPublicKey,
+		SignCount:    authData.Counter,
+		Name:         name,
+		Transports:   normalizePasskeyTransports(credential.Response.Transports),
+	}, user.Email)
+	if err != nil {
+		if strings.Contains(err.Error(), "already registered") {
+			return nil, &AuthError{Code: "invalid_input", Message: "this passkey is already registered"}
+		}
+		return nil, fmt.Errorf("failed to store passkey: %w", err)
+	}
+
+	s.logger.Info("Registered passkey", logging.WithFields(map[string]interface{}{
+		"userId":    userID,
+		"passkeyId": created.ID,
+	}))
+
+	return created, nil
+}
+
+// BeginPasskeyLogin returns the options for signing in with any discoverable
+// passkey
+func (s *Service) BeginPasskeyLogin(ctx context.Context) (*models.PasskeyRequestOptions, error) {
+	challenge, err := s.newPasskeyChallenge(ctx, passkeyPurposeLogin, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PasskeyRequestOptions{
+		Challenge:        challenge,
+		Timeout:          int(passkeyCeremonyTimeout / time.Millisecond),
+		RPID:             s.config.PasskeyRPID,
+		AllowCredentials: []models.PasskeyCredentialDescriptor{},
+		UserVerification: "required",
+	}, nil
+}
+
+// LoginWithPasskey verifies a signed passkey assertion and signs in the user
+// the passkey identity belongs to
+func (s *Service) LoginWithPasskey(ctx context.Context, params models.PasskeyLoginParams) (*models.AuthResponse, error) {
+	if params.Type != "public-key" {
+		return nil, &AuthError{Code: "invalid_input", Message: "credential type must be public-key"}
+	}
+	rawID, err := decodeBase64URL(params.RawID)
+	if err != nil || len(rawID) == 0 {
+		return nil, &AuthError{Code: "invalid_input", Message: "credential rawId is invalid"}
+	}
+	clientDataJSON, err := decodeBase64URL(params.Response.ClientDataJSON)
+	if err != nil {
+		return nil, &AuthError{Code: "invalid_input", Message: "clientDataJSON is invalid"}
+	}
+	rawAuthData, err := decodeBase64URL(params.Response.AuthenticatorData)
+	if err != nil {
+		return nil, &AuthError{Code: "invalid_input", Message: "authenticatorData is invalid"}
+	}
+	signature, err := decodeBase64URL(params.Response.Signature)
+	if err != nil || len(signature) == 0 {
+		return nil, &AuthError{Code: "invalid_input", Message: "signature is invalid"}
+	}
+	userHandle, err := decodeBase64URL(params.Response.UserHandle)
+	if err != nil {
+		return nil, &AuthError{Code: "invalid_input", Message: "userHandle is invalid"}
+	}
+
+	parsed, err := parseAssertion(rawID, clientDataJSON, rawAuthData, signature, userHandle)
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+	challenge := parsed.Response.CollectedClientData.Challenge
+	if err := s.consumePasskeyChallenge(ctx, challenge, passkeyPurposeLogin, ""); err != nil {
+		return nil, err
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(rawID)
+	stored, err := s.userStore.GetPasskeyCredential(ctx, credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+	if stored == nil {
+		return nil, &AuthError{Code: "invalid_credentials", Message: "this passkey is not registered with FlyingForge"}
+	}
+	if len(userHandle) > 0 && string(userHandle) != stored.UserID {
+		return nil, passkeyError(fmt.Errorf("user handle does not match the passkey"))
+	}
+	if err := s.relyingParty().verifyAssertion(parsed, challenge, stored.PublicKey); err != nil {
+		return nil, passkeyError(err)
+	}
+	signCount := parsed.Response.AuthenticatorData.Counter
+	if err := checkSignCount(stored.SignCount, signCount); err != nil {
+		s.logger.Warn("Rejected passkey with a stale signature counter", logging.WithFields(map[string]interface{}{
+			"userId":    stored.UserID,
+			"passkeyId": stored.ID,
+		}))
+		return nil, passkeyError(err)
+	}
+
+	identity, err := s.userStore.GetIdentityByProvider(ctx, models.AuthProviderPasskey, credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check identity: %w", err)
+	}
+	if identity == nil || identity.UserID != stored.UserID {
+		return nil, &AuthError{Code: "invalid_credentials", Message: "this passkey is not registered with FlyingForge"}
+	}
+	user, err := s.userStore.GetByID(ctx, identity.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, &AuthError{Code: "user_not_found", Message: "user not found"}
+	}
+
+	if err := s.userStore.UpdatePasskeySignCount(ctx, stored.ID, signCount); err != nil {
+		s.logger.Warn("Failed to record passkey use", logging.WithField("error", err.Error()))
+	}
+
+	return s.completeLogin(ctx, user, false, false)
+}
+
+// ListPasskeys returns the user's passkeys without their keys
+func (s *Service) ListPasskeys(ctx context.Context, userID string) ([]models.PasskeyCredential, error) {
+	return s.userStore.ListPasskeyCredentials(ctx, userID)
+}
+
+// DeletePasskey removes one of the user's passkeys and its identity. The
+// last passkey can't be removed while it is the only way to sign in.
+func (s *Service) DeletePasskey(ctx context.Context, userID, passkeyID string) error {
+	deleted, err := s.userStore.DeletePasskeyCredential(ctx, strings.TrimSpace(passkeyID), userID, s.signInProviders())
+	if errors.Is(err, database.ErrLastSignInMethod) {
+		return &AuthError{Code: "invalid_input", Message: "this passkey is your only way to sign in; add another sign-in method before removing it"}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	if !deleted {
+		return &AuthError{Code: "not_found", Message: "passkey not found"}
+	}
+	return nil
+}
+
+// signInProviders lists the identities that can sign in to FlyingForge
+// besides a passkey. Any address can get an emailed link when email sign-in
+// is configured, so then no other identity is needed and nil is returned.
+func (s *Service) signInProviders() []models.AuthProvider {
+	if s.mailer != nil {
+		return nil
+	}
+	return []models.AuthProvider{models.AuthProviderGoogle, models.AuthProviderEmail, models.AuthProviderPasskey}
+}
+
+func (s *Service) relyingParty() webauthnRelyingParty {
+	return webauthnRelyingParty{id: s.config.PasskeyRPID, origins: s.config.PasskeyOrigins}
+}
+
+// newPasskeyChallenge issues and stores a random challenge for one ceremony
+func (s *Service) newPasskeyChallenge(ctx context.Context, purpose, userID string) (string, error) {
+	if s.config.PasskeyRPID == "" || len(s.config.PasskeyOrigins) == 0 {
+		return "", &AuthError{Code: "not_configured", Message: "passkey sign-in is not available"}
+	}
+
+	s.pruneExpiredLoginState(ctx)
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate passkey challenge: %w", err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.userStore.CreateWebAuthnChallenge(ctx, challenge, purpose, userID, time.Now().Add(passkeyCeremonyTimeout)); err != nil {
+		return "", fmt.Errorf("failed to store passkey challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// consumePasskeyChallenge accepts a challenge once, and only from the user
+// it was issued to
+func (s *Service) consumePasskeyChallenge(ctx context.Context, challenge, purpose, userID string) error {
+	owner, found, err := s.userStore.ConsumeWebAuthnChallenge(ctx, challenge, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to check passkey challenge: %w", err)
+	}
+	if !found || owner != userID {
+		return &AuthError{Code: "invalid_credentials", Message: "passkey challenge is invalid or has expired; please try again"}
+	}
+	return nil
+}
+
+// checkSignCount rejects a signature counter that didn't advance, a sign of
+// a cloned authenticator. Authenticators that don't count always report 0.
+func checkSignCount(stored, reported uint32) error {
+	if (stored != 0 || reported != 0) && reported <= stored {
+		return fmt.Errorf("signature counter did not increase")
+	}
+	return nil
+}
+
+// normalizePasskeyTransports keeps the transport hints browsers report,
+// which are only passed back to them in excludeCredentials
+func normalizePasskeyTransports(transports []string) []string {
+	normalized := []string{}
+	for _, transport := range transports {
+		transport = strings.TrimSpace(transport)
+		if transport != "" && len(transport) <= 32 && !containsString(normalized, transport) && len(normalized) < 8 {
+			normalized = append(normalized, transport)
+		}
+	}
+	return normalized
+}
+
+// passkeyCredentialParams lists passkeyAlgorithms for the creation options
+func passkeyCredentialParams() []models.PasskeyCredentialParam {
+	params := make([]models.PasskeyCredentialParam, 0, len(passkeyAlgorithms))
+	for _, alg := range passkeyAlgorithms {
+		params = append(params, models.PasskeyCredentialParam{Type: "public-key", Alg: int(alg)})
+	}
+	return params
+}
+
+func passkeyError(err error) error {
+	return &AuthError{Code: "invalid_credentials", Message: "passkey verification failed: " + describeWebAuthnError(err)}
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	service := setupTestAuthService(t)
+	ctx := context.Background()
+	email := fmt.Sprintf("passkey-%d@example.com", time.Now().UnixNano())
+
+	user, err := service.userStore.Create(ctx, models.CreateUserParams{Email: email, Status: models.UserStatusActive})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	creation, err := service.BeginPasskeyRegistration(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	if creation.RP.ID != testRPID || creation.User.Name != email || creation.AuthenticatorSelection.UserVerification != "required" {
+		t.Fatalf("unexpected creation options %+v", creation)
+	}
+
+	authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+	authenticator.userHandle = mustDecodeBase64URL(t, creation.User.ID)
+	passkey, err := service.FinishPasskeyRegistration(ctx, user.ID, models.PasskeyRegistrationParams{
+		Name:       "Laptop",
+		Credential: authenticator.register(creation.Challenge, testOrigin),
+	})
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration: %v", err)
+	}
+	if passkey.Name != "Laptop" || passkey.UserID != user.ID {
+		t.Fatalf("unexpected passkey %+v", passkey)
+	}
+
+	identity, err := service.userStore.GetIdentityByProvider(ctx, models.AuthProviderPasskey, passkey.CredentialID)
+	if err != nil || identity == nil || identity.UserID != user.ID {
+		t.Fatalf("expected a passkey identity for the user, got %+v (err %v)", identity, err)
+	}
+
+	// The registration challenge can't be answered twice
+	if _, err := service.FinishPasskeyRegistration(ctx, user.ID, models.PasskeyRegistrationParams{
+		Credential: authenticator.register(creation.Challenge, testOrigin),
+	}); err == nil {
+		t.Fatal("expected a reused registration challenge to be rejected")
+	}
+
+	// Registering the same credential again fails without leaving an extra
+	// identity behind
+	creation, err = service.BeginPasskeyRegistration(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	_, err = service.FinishPasskeyRegistration(ctx, user.ID, models.PasskeyRegistrationParams{
+		Credential: authenticator.register(creation.Challenge, testOrigin),
+	})
+	if authErr, ok := err.(*AuthError); !ok || authErr.Code != "invalid_input" {
+		t.Fatalf("expected a duplicate passkey to be rejected, got %v", err)
+	}
+	identities, err := service.userStore.GetIdentitiesByUserID(ctx, user.ID)
+	if err != nil || len(identities) != 1 {
+		t.Fatalf("expected only the passkey identity, got %+v (err %v)", identities, err)
+	}
+
+	request, err := service.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	response, err := service.LoginWithPasskey(ctx, authenticator.signIn(request.Challenge, testOrigin))
+	if err != nil {
+		t.Fatalf("LoginWithPasskey: %v", err)
+	}
+	if response.User.ID != user.ID || response.Tokens.AccessToken == "" {
+		t.Fatalf("expected to sign in as %s, got %+v", user.ID, response)
+	}
+
+	request, err = service.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	if _, err := service.LoginWithPasskey(ctx, authenticator.signIn(request.Challenge, "https://evil.example")); err == nil {
+		t.Fatal("expected an assertion from another origin to be rejected")
+	}
+
+	// The only passkey can't be removed until there's another way to sign in
+	err = service.DeletePasskey(ctx, user.ID, passkey.ID)
+	if authErr, ok := err.(*AuthError); !ok || authErr.Code != "invalid_input" {
+		t.Fatalf("expected the last sign-in method to be kept, got %v", err)
+	}
+	if _, err := service.userStore.CreateIdentity(ctx, user.ID, models.AuthProviderGoogle, "google-"+email, email); err != nil {
+		t.Fatalf("CreateIdentity: %v", err)
+	}
+	if err := service.DeletePasskey(ctx, user.ID, passkey.ID); err != nil {
+		t.Fatalf("DeletePasskey: %v", err)
+	}
+	identity, err = service.userStore.GetIdentityByProvider(ctx, models.AuthProviderPasskey, passkey.CredentialID)
+	if err != nil || identity != nil {
+		t.Fatalf("expected the passkey identity to be deleted, got %+v (err %v)", identity, err)
+	}
+
+	request, err = service.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin: %v", err)
+	}
+	if _, err := service.LoginWithPasskey(ctx, authenticator.signIn(request.Challenge, testOrigin)); err == nil {
+		t.Fatal("expected a deleted passkey to be rejected")
+	}
+}
+
+func TestPasskeyRegistrationChallengeIsBoundToUser(t *testing.T) {
+	service := setupTestAuthService(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	owner, err := service.userStore.Create(ctx, models.CreateUserParams{Email: fmt.Sprintf("passkey-owner-%d@example.com", suffix), Status: models.UserStatusActive})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other, err := service.userStore.Create(ctx, models.CreateUserParams{Email: fmt.Sprintf("passkey-other-%d@example.com", suffix), Status: models.UserStatusActive})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	creation, err := service.BeginPasskeyRegistration(ctx, owner.ID)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration: %v", err)
+	}
+	authenticator := newTestAuthenticator(t, webauthncose.AlgEdDSA)
+	_, err = service.FinishPasskeyRegistration(ctx, other.ID, models.PasskeyRegistrationParams{
+		Credential: authenticator.register(creation.Challenge, testOrigin),
+	})
+	if authErr, ok := err.(*AuthError); !ok || authErr.Code != "invalid_credentials" {
+		t.Fatalf("expected another user's challenge to be rejected, got %v", err)
+	}
+}
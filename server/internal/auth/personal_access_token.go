@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	defaultPersonalAccessTokenDays   = 90
+	maxPersonalAccessTokenDays       = 365
+	maxPersonalAccessTokenNameLength = 100
+	maxPersonalAccessTokensPerUser   = 25
+
+	// personalAccessTokenDisplayLength is how much of a token is kept in
+	// clear for the token list
+	personalAccessTokenDisplayLength = len(models.PersonalAccessTokenPrefix) + 6
+)
+
+// PersonalAccessTokenScopes returns the scopes a personal access token can
+// carry. They are the MCP OAuth scopes, so a token reads with
+// flyingforge.read and writes one area of data per write scope.
+func PersonalAccessTokenScopes() []string {
+	return append([]string{models.MCPScopeRead}, models.MCPWriteScopes()...)
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, models.PersonalAccessTokenPrefix)
+}
+
+// personalAccessTokenWriteScopes maps API path prefixes to the scope that
+// unlocks their write methods
+var personalAccessTokenWriteScopes = []struct {
+	prefix string
+	scope  string
+}{
+	{"/api/inventory", models.MCPScopeInventoryWrite},
+	{"/api/orders", models.MCPScopeInventoryWrite},
+	{"/api/batteries", models.MCPScopeBatteriesWrite},
+	{"/api/aircraft", models.MCPScopeAircraftWrite},
+	{"/api/fc-configs", models.MCPScopeAircraftWrite},
+	{"/api/builds", models.MCPScopeBuildsWrite},
+	{"/api/flights", models.MCPScopeFlightsWrite},
+}
+
+// personalAccessTokenBlockedPrefixes are never reachable with a personal
+// access token, so a leaked token cannot mint tokens, act as an admin, or
+// download an account takeout with its decrypted receiver settings
+var personalAccessTokenBlockedPrefixes = []string{
+	"/api/me/tokens",
+	"/api/me/exports",
+	"/api/me/imports",
+	"/api/admin",
+	"/api/auth",
+}
+
+// RequiredPersonalAccessTokenScope returns the scope a personal access token
+// needs for a request, or "" when personal access tokens are not accepted
+// there. Reads need flyingforge.read; writes need the area's write scope.
+func RequiredPersonalAccessTokenScope(method, path string) string {
+	for _, prefix := range personalAccessTokenBlockedPrefixes {
+		if pathHasPrefix(path, prefix) {
+			return ""
+		}
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.MCPScopeRead
+	}
+
+	for _, entry := range personalAccessTokenWriteScopes {
+		if pathHasPrefix(path, entry.prefix) {
+			return entry.scope
+		}
+	}
+	return ""
+}
+
+func pathHasPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// CreatePersonalAccessToken issues a token for the user. The secret is only
+// returned in this response.
+func (s *Service) CreatePersonalAccessToken(ctx context.Context, userID string, params models.CreatePersonalAccessTokenParams) (*models.PersonalAccessTokenCreateResponse, error) {
+	name, scopes, days, err := normalizePersonalAccessTokenParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.userStore.ListPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	if len(existing) >= maxPersonalAccessTokensPerUser {
+		return nil, &AuthError{
+			Code:    "invalid_input",
+			Message: fmt.Sprintf("you can have at most %d personal access tokens; revoke one first", maxPersonalAccessTokensPerUser),
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate personal access token: %w", err)
+	}
+	token := models.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	expiresAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+
+	accessToken, err := s.userStore.CreatePersonalAccessToken(ctx, userID, name, token[:personalAccessTokenDisplayLength], hashToken(token), scopes, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store personal access token: %w", err)
+	}
+
+	s.logger.Info("Created personal access token", logging.WithFields(map[string]interface{}{
+		"userId":  userID,
+		"tokenId": accessToken.ID,
+		"scopes":  strings.Join(scopes, " "),
+	}))
+
+	return &models.PersonalAccessTokenCreateResponse{
+		Token:       token,
+		AccessToken: accessToken,
+	}, nil
+}
+
+// ListPersonalAccessTokens returns the user's unrevoked tokens without secrets
+func (s *Service) ListPersonalAccessTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	return s.userStore.ListPersonalAccessTokens(ctx, userID)
+}
+
+// RevokePersonalAccessToken revokes one of the user's tokens
+func (s *Service) RevokePersonalAccessToken(ctx context.Context, userID, tokenID string) error {
+	revoked, err := s.userStore.RevokePersonalAccessToken(ctx, strings.TrimSpace(tokenID), userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke personal access token: %w", err)
+	}
+	if !revoked {
+		return &AuthError{Code: "not_found", Message: "personal access token not found"}
+	}
+	return nil
+}
+
+// ValidatePersonalAccessToken resolves a token to its stored record and
+// records the use
+func (s *Service) ValidatePersonalAccessToken(ctx context.Context, token string) (*models.PersonalAccessToken, error) {
+	if !IsPersonalAccessToken(token) {
+		return nil, &AuthError{Code: "invalid_token", Message: "invalid or expired token"}
+	}
+
+	accessToken, err := s.userStore.GetActivePersonalAccessTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+	if accessToken == nil {
+		return nil, &AuthError{Code: "invalid_token", Message: "invalid or expired token"}
+	}
+
+	if err := s.userStore.TouchPersonalAccessToken(ctx, accessToken.ID); err != nil {
+		s.logger.Warn("Failed to record personal access token use", logging.WithField("error", err.Error()))
+	}
+
+	return accessToken, nil
+}
+
+func normalizePersonalAccessTokenParams(params models.CreatePersonalAccessTokenParams) (string, []string, int, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return "", nil, 0, &AuthError{Code: "invalid_input", Message: "name is required"}
+	}
+	if len(name) > maxPersonalAccessTokenNameLength {
+		return "", nil, 0, &AuthError{Code: "invalid_input", Message: fmt.Sprintf("name must be at most %d characters", maxPersonalAccessTokenNameLength)}
+	}
+
+	requested := make(map[string]bool, len(params.Scopes))
+	for _, scope := range params.Scopes {
+		requested[strings.TrimSpace(scope)] = true
+	}
+	scopes := []string{}
+	for _, scope := range PersonalAccessTokenScopes() {
+		if requested[scope] {
+			scopes = append(scopes, scope)
+			delete(requested, scope)
+		}
+	}
+	for scope := range requested {
+		return "", nil, 0, &AuthError{Code: "invalid_input", Message: fmt.Sprintf("unknown scope %q", scope)}
+	}
+	if len(scopes) == 0 {
+		return "", nil, 0, &AuthError{Code: "invalid_input", Message: "at least one scope is required"}
+	}
+
+	days := params.ExpiresInDays
+	if days == 0 {
+		days = defaultPersonalAccessTokenDays
+	}
+	if days < 1 || days > maxPersonalAccessTokenDays {
+		return "", nil, 0, &AuthError{Code: "invalid_input", Message: fmt.Sprintf("expiresInDays must be between 1 and %d", maxPersonalAccessTokenDays)}
+	}
+
+	return name, scopes, days, nil
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+func TestRequiredPersonalAccessTokenScope(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/api/inventory", models.MCPScopeRead},
+		{http.MethodGet, "/api/aircraft/abc/details", models.MCPScopeRead},
+		{http.MethodPost, "/api/inventory", models.MCPScopeInventoryWrite},
+		{http.MethodPatch, "/api/inventory/item-1", models.MCPScopeInventoryWrite},
+		{http.MethodPost, "/api/batteries/bat-1/logs", models.MCPScopeBatteriesWrite},
+		{http.MethodPut, "/api/fc-configs/cfg-1", models.MCPScopeAircraftWrite},
+		{http.MethodDelete, "/api/builds/build-1", models.MCPScopeBuildsWrite},
+		{http.MethodPost, "/api/flights", models.MCPScopeFlightsWrite},
+		{http.MethodPost, "/api/inventoryx", ""},
+		{http.MethodDelete, "/api/me/profile", ""},
+		{http.MethodGet, "/api/me/tokens", ""},
+		{http.MethodPost, "/api/me/tokens", ""},
+		{http.MethodGet, "/api/me/exports/export-1/download", ""},
+		{http.MethodPost, "/api/me/exports", ""},
+		{http.MethodGet, "/api/me/imports", ""},
+		{http.MethodGet, "/api/admin/users", ""},
+		{http.MethodPost, "/api/auth/logout", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RequiredPersonalAccessTokenScope(tt.method, tt.path); got != tt.want {
+			t.Errorf("RequiredPersonalAccessTokenScope(%s, %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePersonalAccessTokenParams(t *testing.T) {
+	name, scopes, days, err := normalizePersonalAccessTokenParams(models.CreatePersonalAccessTokenParams{
+		Name:   "  Bench scripts ",
+		Scopes: []string{"batteries:write", " flyingforge.read", "batteries:write"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if name != "Bench scripts" || days != defaultPersonalAccessTokenDays {
+		t.Fatalf("unexpected name %q or days %d", name, days)
+	}
+	if strings.Join(scopes, " ") != "flyingforge.read batteries:write" {
+		t.Fatalf("expected deduplicated scopes in canonical order, got %v", scopes)
+	}
+
+	invalid := []models.CreatePersonalAccessTokenParams{
+		{Name: "", Scopes: []string{models.MCPScopeRead}},
+		{Name: strings.Repeat("x", maxPersonalAccessTokenNameLength+1), Scopes: []string{models.MCPScopeRead}},
+		{Name: "no scopes"},
+		{Name: "unknown scope", Scopes: []string{"admin"}},
+		{Name: "too long", Scopes: []string{models.MCPScopeRead}, ExpiresInDays: maxPersonalAccessTokenDays + 1},
+		{Name: "negative", Scopes: []string{models.MCPScopeRead}, ExpiresInDays: -1},
+	}
+	for _, params := range invalid {
+		if _, _, _, err := normalizePersonalAccessTokenParams(params); err == nil {
+			t.Errorf("expected %+v to be rejected", params)
+		}
+	}
+}
+
+func TestRequireAuthRejectsPersonalAccessTokenInQuery(t *testing.T) {
+	middleware := NewMiddleware(nil)
+	called := false
+	handler := middleware.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/inventory?token="+models.PersonalAccessTokenPrefix+"secret", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusUnauthorized || called {
+		t.Fatalf("expected 401 without calling the handler, got %d", rec.Code)
+	}
+}
+
+func TestRequireAuthEncodesErrorsAsJSON(t *testing.T) {
+	handler := NewMiddleware(nil).RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected the handler not to be called")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/inventory", nil)
+	req.Header.Set("Authorization", "Bearer "+models.PersonalAccessTokenPrefix+"secret")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected a JSON body, got %q: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusUnauthorized || body["error"] != "invalid or expired token" {
+		t.Fatalf("unexpected response %d %v", rec.Code, body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+}
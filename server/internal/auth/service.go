@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,6 +20,7 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/config"
 	"github.com/johnrirwin/flyingforge/internal/database"
 	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/mailer"
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
 
@@ -26,6 +28,7 @@ import (
 type Service struct {
 	config    config.AuthConfig
 	userStore *database.UserStore
+	mailer    mailer.Sender
 	logger    *logging.Logger
 }
 
@@ -38,6 +41,11 @@ func NewService(userStore *database.UserStore, cfg config.AuthConfig, logger *lo
 	}
 }
 
+// SetMailSender enables email sign-in links, delivered through sender
+func (s *Service) SetMailSender(sender mailer.Sender) {
+	s.mailer = sender
+}
+
 // LoginWithGoogle authenticates a user with Google OAuth
 func (s *Service) LoginWithGoogle(ctx context.Context, params models.GoogleLoginParams) (*models.AuthResponse, error) {
 	var claims *models.GoogleClaims
@@ -60,6 +68,11 @@ func (s *Service) LoginWithGoogle(ctx context.Context, params models.GoogleLogin
 		return nil, err
 	}
 
+	return s.completeLogin(ctx, user, isNewUser, isLinked)
+}
+
+// completeLogin issues a session for a user resolved by any sign-in method
+func (s *Service) completeLogin(ctx context.Context, user *models.User, isNewUser, isLinked bool) (*models.AuthResponse, error) {
 	// Check status
 	if user.Status != models.UserStatusActive {
 		return nil, &AuthError{Code: "account_disabled", Message: "account is disabled"}
@@ -84,6 +97,46 @@ func (s *Service) LoginWithGoogle(ctx context.Context, params models.GoogleLogin
 	}, nil
 }
 
+// linkIdentity links a provider identity to a user. Linking an identity the
+// user already has is not an error; one linked to another account is.
+func (s *Service) linkIdentity(ctx context.Context, userID string, provider models.AuthProvider, subject, email string) (*models.UserIdentity, error) {
+	identity, err := linkUserIdentity(ctx, s.userStore.CreateIdentity, s.userStore.GetIdentityByProvider, userID, provider, subject, email)
+	if errors.Is(err, errIdentityLinkedElsewhere) {
+		return nil, &AuthError{Code: "identity_linked", Message: "this sign-in method is already linked to another account"}
+	}
+	return identity, err
+}
+
+// errIdentityLinkedElsewhere means the identity belongs to another user
+var errIdentityLinkedElsewhere = errors.New("identity is linked to another account")
+
+// linkUserIdentity creates a provider identity for a user, or returns the
+// existing one when the user already has it. It is shared by the sign-in
+// service and MCP auth, which pass in their own store functions.
+func linkUserIdentity(
+	ctx context.Context,
+	create func(context.Context, string, models.AuthProvider, string, string) (*models.UserIdentity, error),
+	lookup func(context.Context, models.AuthProvider, string) (*models.UserIdentity, error),
+	userID string, provider models.AuthProvider, subject, email string,
+) (*models.UserIdentity, error) {
+	identity, err := create(ctx, userID, provider, subject, email)
+	if err == nil {
+		return identity, nil
+	}
+	if !isIdentityAlreadyLinkedError(err) {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	existing, err := lookup(ctx, provider, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check identity: %w", err)
+	}
+	if existing == nil || existing.UserID != userID {
+		return nil, errIdentityLinkedElsewhere
+	}
+	return existing, nil
+}
+
 func (s *Service) resolveGoogleUser(ctx context.Context, claims *models.GoogleClaims) (*models.User, bool, bool, error) {
 	email := strings.ToLower(strings.TrimSpace(claims.Email))
 	isNewUser := false
@@ -27,6 +27,11 @@ func setupTestAuthService(t *testing.T) *Service {
 		RefreshTokenTTL:   7 * 24 * time.Hour,
 		GoogleClientID:    "test-client-id",
 		GoogleRedirectURI: "http://localhost:3000/auth/callback",
+		EmailLinkURL:      "https://flyingforge.example/auth/email",
+		EmailLinkTTL:      15 * time.Minute,
+		PasskeyRPID:       testRPID,
+		PasskeyRPName:     "FlyingForge",
+		PasskeyOrigins:    []string{testOrigin},
 	}
 	return NewService(userStore, cfg, logger)
 }
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// passkeyAlgorithms are the COSE algorithms FlyingForge accepts, in order of
+// preference
+var passkeyAlgorithms = []webauthncose.COSEAlgorithmIdentifier{
+	webauthncose.AlgES256,
+	webauthncose.AlgEdDSA,
+	webauthncose.AlgRS256,
+}
+
+const minRSAKeyBits = 2048
+
+// webauthnRelyingParty verifies ceremony responses against the configured
+// relying party ID and origins. Parsing and the WebAuthn verification steps
+// are done by go-webauthn; FlyingForge's own policy is layered on top.
+type webauthnRelyingParty struct {
+	id      string
+	origins []string
+}
+
+// parseRegistration parses a registration response so its challenge can be
+// looked up before the response is verified
+func parseRegistration(rawID, clientDataJSON, attestationObject []byte, transports []string) (*protocol.ParsedCredentialCreationData, error) {
+	response := protocol.CredentialCreationResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{
+				ID:   base64.RawURLEncoding.EncodeToString(rawID),
+				Type: string(protocol.PublicKeyCredentialType),
+			},
+			RawID: rawID,
+		},
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AttestationObject:     attestationObject,
+			Transports:            transports,
+		},
+	}
+	return response.Parse()
+}
+
+// verifyRegistration checks a parsed registration against the challenge it
+// answered. It requires user verification, accepts any attestation format
+// go-webauthn can verify without metadata, and rejects credentials whose ID
+// doesn't match the attested one or whose key is weak.
+func (rp webauthnRelyingParty) verifyRegistration(parsed *protocol.ParsedCredentialCreationData, challenge string) error {
+	if parsed.Response.CollectedClientData.CrossOrigin {
+		return fmt.Errorf("cross-origin ceremonies are not allowed")
+	}
+
+	params := make([]protocol.CredentialParameter, 0, len(passkeyAlgorithms))
+	for _, alg := range passkeyAlgorithms {
+		params = append(params, protocol.CredentialParameter{Type: protocol.PublicKeyCredentialType, Algorithm: alg})
+	}
+	if _, err := parsed.Verify(challenge, true, true, rp.id, rp.origins, nil, protocol.TopOriginExplicitVerificationMode, nil, params); err != nil {
+		return err
+	}
+
+	attested := parsed.Response.AttestationObject.AuthData.AttData
+	if !bytes.Equal(attested.CredentialID, parsed.RawID) {
+		return fmt.Errorf("credential ID does not match the attested credential")
+	}
+	return checkPasskeyPublicKey(attested.CredentialPublicKey)
+}
+
+// parseAssertion parses a sign-in response so its challenge and credential
+// can be looked up before the response is verified
+func parseAssertion(rawID, clientDataJSON, authenticatorData, signature, userHandle []byte) (*protocol.ParsedCredentialAssertionData, error) {
+	response := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{
+				ID:   base64.RawURLEncoding.EncodeToString(rawID),
+				Type: string(protocol.PublicKeyCredentialType),
+			},
+			RawID: rawID,
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AuthenticatorData:     authenticatorData,
+			Signature:             signature,
+			UserHandle:            userHandle,
+		},
+	}
+	return response.Parse()
+}
+
+// verifyAssertion checks a parsed sign-in response against its challenge and
+// the stored credential's COSE public key
+func (rp webauthnRelyingParty) verifyAssertion(parsed *protocol.ParsedCredentialAssertionData, challenge string, publicKey []byte) error {
+	if parsed.Response.CollectedClientData.CrossOrigin {
+		return fmt.Errorf("cross-origin ceremonies are not allowed")
+	}
+	return parsed.Verify(challenge, rp.id, rp.origins, nil, protocol.TopOriginExplicitVerificationMode, "", true, true, publicKey)
+}
+
+// checkPasskeyPublicKey rejects keys go-webauthn would accept but FlyingForge
+// doesn't: RSA keys under 2048 bits and EC or Ed25519 keys that aren't valid
+// points
+func checkPasskeyPublicKey(coseKey []byte) error {
+	key, err := webauthncose.ParsePublicKey(coseKey)
+	if err != nil {
+		return fmt.Errorf("unsupported public key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case webauthncose.EC2PublicKeyData:
+		if webauthncose.COSEEllipticCurve(k.Curve) != webauthncose.P256 {
+			return fmt.Errorf("public key is not on the P-256 curve")
+		}
+		ecKey, err := k.ToECDSA()
+		if err != nil {
+			return fmt.Errorf("unsupported public key: %w", err)
+		}
+		if _, err := ecKey.ECDH(); err != nil {
+			return fmt.Errorf("public key is not a valid curve point")
+		}
+	case webauthncose.OKPPublicKeyData:
+		if webauthncose.COSEAlgorithmIdentifier(k.Algorithm) != webauthncose.AlgEdDSA || len(k.XCoord) != ed25519.PublicKeySize {
+			return fmt.Errorf("public key is not a valid Ed25519 key")
+		}
+	case webauthncose.RSAPublicKeyData:
+		if new(big.Int).SetBytes(k.Modulus).BitLen() < minRSAKeyBits {
+			return fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+	default:
+		return fmt.Errorf("unsupported public key type")
+	}
+	return nil
+}
+
+// describeWebAuthnError turns a go-webauthn error into a message that says
+// which check failed
+func describeWebAuthnError(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return protocolErr.Details + ": " + protocolErr.DevInfo
+	}
+	return err.Error()
+}
+
+// decodeBase64URL decodes a WebAuthn JSON binary field, with or without padding
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
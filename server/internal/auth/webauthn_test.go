@@ -0,0 +1,449 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	testRPID   = "flyingforge.example"
+	testOrigin = "https://flyingforge.example"
+)
+
+// testAuthenticator is a software passkey that produces registration and
+// sign-in responses the way a browser would hand them to the frontend. Its
+// rpID, flags and crossOrigin can be changed to produce bad responses.
+type testAuthenticator struct {
+	t            *testing.T
+	credentialID []byte
+	signer       crypto.Signer
+	coseKey      []byte
+	signCount    uint32
+	userHandle   []byte
+
+	rpID        string
+	flags       protocol.AuthenticatorFlags
+	crossOrigin bool
+}
+
+func newTestAuthenticator(t *testing.T, alg webauthncose.COSEAlgorithmIdentifier) *testAuthenticator {
+	t.Helper()
+
+	credentialID := make([]byte, 32)
+	rand.Read(credentialID)
+	a := &testAuthenticator{
+		t:            t,
+		credentialID: credentialID,
+		rpID:         testRPID,
+		flags:        protocol.FlagUserPresent | protocol.FlagUserVerified,
+	}
+
+	switch alg {
+	case webauthncose.AlgES256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("generate P-256 key: %v", err)
+		}
+		a.signer = key
+		a.coseKey = encodeCOSEKey(t, map[int]interface{}{
+			1: int(webauthncose.EllipticKey), 3: int(webauthncose.AlgES256), -1: int(webauthncose.P256),
+			-2: key.X.FillBytes(make([]byte, 32)), -3: key.Y.FillBytes(make([]byte, 32)),
+		})
+	case webauthncose.AlgEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("generate Ed25519 key: %v", err)
+		}
+		a.signer = private
+		a.coseKey = encodeCOSEKey(t, map[int]interface{}{
+			1: int(webauthncose.OctetKey), 3: int(webauthncose.AlgEdDSA), -1: int(webauthncose.Ed25519),
+			-2: []byte(public),
+		})
+	case webauthncose.AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("generate RSA key: %v", err)
+		}
+		a.signer = key
+		a.coseKey = encodeCOSEKey(t, map[int]interface{}{
+			1: int(webauthncose.RSAKey), 3: int(webauthncose.AlgRS256),
+			-1: key.N.Bytes(), -2: big.NewInt(int64(key.E)).Bytes(),
+		})
+	default:
+		t.Fatalf("unsupported test algorithm %d", alg)
+	}
+	return a
+}
+
+func encodeCOSEKey(t *testing.T, key map[int]interface{}) []byte {
+	t.Helper()
+	encoded, err := webauthncbor.Marshal(key)
+	if err != nil {
+		t.Fatalf("encode COSE key: %v", err)
+	}
+	return encoded
+}
+
+func (a *testAuthenticator) clientData(ceremony protocol.CeremonyType, challenge, origin string) []byte {
+	data, err := json.Marshal(protocol.CollectedClientData{
+		Type:        ceremony,
+		Challenge:   challenge,
+		Origin:      origin,
+		CrossOrigin: a.crossOrigin,
+	})
+	if err != nil {
+		a.t.Fatalf("marshal client data: %v", err)
+	}
+	return data
+}
+
+func (a *testAuthenticator) authenticatorData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := a.flags
+	if attested {
+		flags |= protocol.FlagAttestedCredentialData
+	}
+	data := append(rpIDHash[:], byte(flags))
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey...)
+	}
+	return data
+}
+
+// register answers a creation challenge with a "none" attestation
+func (a *testAuthenticator) register(challenge, origin string) models.PasskeyAttestationCredential {
+	a.t.Helper()
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authenticatorData(true),
+	})
+	if err != nil {
+		a.t.Fatalf("encode attestation object: %v", err)
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(a.credentialID)
+	return models.PasskeyAttestationCredential{
+		ID:    id,
+		RawID: id,
+		Type:  "public-key",
+		Response: models.PasskeyAttestationResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(a.clientData(protocol.CreateCeremony, challenge, origin)),
+			AttestationObject: base64.RawURLEncoding.EncodeToString(attestation),
+			Transports:        []string{"internal", "hybrid"},
+		},
+	}
+}
+
+// signIn answers a sign-in challenge, advancing the signature counter
+func (a *testAuthenticator) signIn(challenge, origin string) models.PasskeyLoginParams {
+	a.t.Helper()
+
+	a.signCount++
+	authData := a.authenticatorData(false)
+	clientData := a.clientData(protocol.AssertCeremony, challenge, origin)
+
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	var signature []byte
+	var err error
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		signature, err = a.signer.Sign(rand.Reader, signed, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(signed)
+		signature, err = a.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		a.t.Fatalf("sign assertion: %v", err)
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(a.credentialID)
+	return models.PasskeyLoginParams{
+		ID:    id,
+		RawID: id,
+		Type:  "public-key",
+		Response: models.PasskeyAssertionResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+			Signature:         base64.RawURLEncoding.EncodeToString(signature),
+			UserHandle:        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	}
+}
+
+func mustDecodeBase64URL(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := decodeBase64URL(value)
+	if err != nil {
+		t.Fatalf("decode %q: %v", value, err)
+	}
+	return decoded
+}
+
+var testRelyingParty = webauthnRelyingParty{id: testRPID, origins: []string{testOrigin}}
+
+// verifyTestRegistration runs a registration response through the same
+// parse and verify steps FinishPasskeyRegistration does
+func verifyTestRegistration(t *testing.T, credential models.PasskeyAttestationCredential, challenge string) (*protocol.ParsedCredentialCreationData, error) {
+	t.Helper()
+	parsed, err := parseRegistration(
+		mustDecodeBase64URL(t, credential.RawID),
+		mustDecodeBase64URL(t, credential.Response.ClientDataJSON),
+		mustDecodeBase64URL(t, credential.Response.AttestationObject),
+		credential.Response.Transports,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parsed, testRelyingParty.verifyRegistration(parsed, challenge)
+}
+
+// verifyTestAssertion runs a sign-in response through the same parse and
+// verify steps LoginWithPasskey does
+func verifyTestAssertion(t *testing.T, assertion models.PasskeyLoginParams, challenge string, publicKey []byte) error {
+	t.Helper()
+	parsed, err := parseAssertion(
+		mustDecodeBase64URL(t, assertion.RawID),
+		mustDecodeBase64URL(t, assertion.Response.ClientDataJSON),
+		mustDecodeBase64URL(t, assertion.Response.AuthenticatorData),
+		mustDecodeBase64URL(t, assertion.Response.Signature),
+		mustDecodeBase64URL(t, assertion.Response.UserHandle),
+	)
+	if err != nil {
+		return err
+	}
+	return testRelyingParty.verifyAssertion(parsed, challenge, publicKey)
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	for _, alg := range passkeyAlgorithms {
+		authenticator := newTestAuthenticator(t, alg)
+		parsed, err := verifyTestRegistration(t, authenticator.register("challenge", testOrigin), "challenge")
+		if err != nil {
+			t.Fatalf("alg %d: verifyRegistration: %v", alg, err)
+		}
+		attested := parsed.Response.AttestationObject.AuthData.AttData
+		if !bytes.Equal(attested.CredentialID, authenticator.credentialID) || !bytes.Equal(attested.CredentialPublicKey, authenticator.coseKey) {
+			t.Errorf("alg %d: parsed credential does not match the authenticator", alg)
+		}
+	}
+}
+
+func TestVerifyRegistrationRejectsBadResponses(t *testing.T) {
+	weakRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-384 key: %v", err)
+	}
+
+	tests := map[string]func(t *testing.T) (models.PasskeyAttestationCredential, string){
+		"wrong origin": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			return newTestAuthenticator(t, webauthncose.AlgES256).register("challenge", "https://evil.example"), "challenge"
+		},
+		"wrong relying party": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			authenticator.rpID = "evil.example"
+			return authenticator.register("challenge", testOrigin), "challenge"
+		},
+		"no user verification": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			authenticator.flags = protocol.FlagUserPresent
+			return authenticator.register("challenge", testOrigin), "challenge"
+		},
+		"cross origin": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			authenticator.crossOrigin = true
+			return authenticator.register("challenge", testOrigin), "challenge"
+		},
+		"other challenge": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			return newTestAuthenticator(t, webauthncose.AlgES256).register("challenge", testOrigin), "other"
+		},
+		"sign-in ceremony": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			credential := authenticator.register("challenge", testOrigin)
+			credential.Response.ClientDataJSON = base64.RawURLEncoding.EncodeToString(authenticator.clientData(protocol.AssertCeremony, "challenge", testOrigin))
+			return credential, "challenge"
+		},
+		"mismatched credential ID": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			credential := newTestAuthenticator(t, webauthncose.AlgES256).register("challenge", testOrigin)
+			credential.RawID = base64.RawURLEncoding.EncodeToString([]byte("another-credential"))
+			return credential, "challenge"
+		},
+		"1024-bit RSA key": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgRS256)
+			authenticator.coseKey = encodeCOSEKey(t, map[int]interface{}{
+				1: int(webauthncose.RSAKey), 3: int(webauthncose.AlgRS256),
+				-1: weakRSA.N.Bytes(), -2: big.NewInt(int64(weakRSA.E)).Bytes(),
+			})
+			return authenticator.register("challenge", testOrigin), "challenge"
+		},
+		"point off the curve": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			authenticator.coseKey = encodeCOSEKey(t, map[int]interface{}{
+				1: int(webauthncose.EllipticKey), 3: int(webauthncose.AlgES256), -1: int(webauthncose.P256),
+				-2: bytes.Repeat([]byte{1}, 32), -3: bytes.Repeat([]byte{2}, 32),
+			})
+			return authenticator.register("challenge", testOrigin), "challenge"
+		},
+		"unrequested algorithm": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			authenticator.coseKey = encodeCOSEKey(t, map[int]interface{}{
+				1: int(webauthncose.EllipticKey), 3: int(webauthncose.AlgES384), -1: int(webauthncose.P384),
+				-2: p384.X.FillBytes(make([]byte, 48)), -3: p384.Y.FillBytes(make([]byte, 48)),
+			})
+			return authenticator.register("challenge", testOrigin), "challenge"
+		},
+		"attestation statement with none format": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			authenticator := newTestAuthenticator(t, webauthncose.AlgES256)
+			credential := authenticator.register("challenge", testOrigin)
+			attestation, err := webauthncbor.Marshal(map[string]interface{}{
+				"fmt":      "none",
+				"attStmt":  map[string]interface{}{"sig": []byte{1}},
+				"authData": authenticator.authenticatorData(true),
+			})
+			if err != nil {
+				t.Fatalf("encode attestation object: %v", err)
+			}
+			credential.Response.AttestationObject = base64.RawURLEncoding.EncodeToString(attestation)
+			return credential, "challenge"
+		},
+		"malformed attestation object": func(t *testing.T) (models.PasskeyAttestationCredential, string) {
+			credential := newTestAuthenticator(t, webauthncose.AlgES256).register("challenge", testOrigin)
+			credential.Response.AttestationObject = base64.RawURLEncoding.EncodeToString([]byte{0xa1, 0x63, 'f', 'm'})
+			return credential, "challenge"
+		},
+	}
+	for name, build := range tests {
+		t.Run(name, func(t *testing.T) {
+			credential, challenge := build(t)
+			if _, err := verifyTestRegistration(t, credential, challenge); err == nil {
+				t.Error("expected the registration to be rejected")
+			}
+		})
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	for _, alg := range passkeyAlgorithms {
+		authenticator := newTestAuthenticator(t, alg)
+		if err := verifyTestAssertion(t, authenticator.signIn("challenge", testOrigin), "challenge", authenticator.coseKey); err != nil {
+			t.Errorf("alg %d: expected a valid assertion, got %v", alg, err)
+		}
+	}
+}
+
+func TestVerifyAssertionRejectsBadResponses(t *testing.T) {
+	tests := map[string]func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte){
+		"wrong origin": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			return a.signIn("challenge", "https://evil.example"), "challenge", a.coseKey
+		},
+		"wrong relying party": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			a.rpID = "evil.example"
+			return a.signIn("challenge", testOrigin), "challenge", a.coseKey
+		},
+		"no user verification": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			a.flags = protocol.FlagUserPresent
+			return a.signIn("challenge", testOrigin), "challenge", a.coseKey
+		},
+		"cross origin": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			a.crossOrigin = true
+			return a.signIn("challenge", testOrigin), "challenge", a.coseKey
+		},
+		"other challenge": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			return a.signIn("challenge", testOrigin), "other", a.coseKey
+		},
+		"tampered authenticator data": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			assertion := a.signIn("challenge", testOrigin)
+			authData := mustDecodeBase64URL(t, assertion.Response.AuthenticatorData)
+			authData[len(authData)-1]++ // Bump the signature counter
+			assertion.Response.AuthenticatorData = base64.RawURLEncoding.EncodeToString(authData)
+			return assertion, "challenge", a.coseKey
+		},
+		"signed by another key": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			other := newTestAuthenticator(t, webauthncose.AlgES256)
+			return a.signIn("challenge", testOrigin), "challenge", other.coseKey
+		},
+		"empty signature": func(t *testing.T, a *testAuthenticator) (models.PasskeyLoginParams, string, []byte) {
+			assertion := a.signIn("challenge", testOrigin)
+			assertion.Response.Signature = ""
+			return assertion, "challenge", a.coseKey
+		},
+	}
+	for name, build := range tests {
+		t.Run(name, func(t *testing.T) {
+			assertion, challenge, publicKey := build(t, newTestAuthenticator(t, webauthncose.AlgES256))
+			if err := verifyTestAssertion(t, assertion, challenge, publicKey); err == nil {
+				t.Error("expected the assertion to be rejected")
+			}
+		})
+	}
+}
+
+func TestCheckPasskeyPublicKey(t *testing.T) {
+	for _, alg := range passkeyAlgorithms {
+		if err := checkPasskeyPublicKey(newTestAuthenticator(t, alg).coseKey); err != nil {
+			t.Errorf("alg %d: expected the key to be accepted, got %v", alg, err)
+		}
+	}
+
+	rejected := map[string][]byte{
+		"not CBOR": {0xff},
+		"short Ed25519 key": encodeCOSEKey(t, map[int]interface{}{
+			1: int(webauthncose.OctetKey), 3: int(webauthncose.AlgEdDSA), -1: int(webauthncose.Ed25519), -2: []byte{1, 2, 3},
+		}),
+		"ES256 on another curve": encodeCOSEKey(t, map[int]interface{}{
+			1: int(webauthncose.EllipticKey), 3: int(webauthncose.AlgES256), -1: int(webauthncose.Secp256k1),
+			-2: bytes.Repeat([]byte{1}, 32), -3: bytes.Repeat([]byte{2}, 32),
+		}),
+		"unknown key type": encodeCOSEKey(t, map[int]interface{}{1: 9, 3: int(webauthncose.AlgES256)}),
+	}
+	for name, key := range rejected {
+		if err := checkPasskeyPublicKey(key); err == nil {
+			t.Errorf("%s: expected the key to be rejected", name)
+		}
+	}
+}
+
+func TestCheckSignCount(t *testing.T) {
+	tests := []struct {
+		stored, reported uint32
+		ok               bool
+	}{
+		{0, 0, true}, // Authenticator without a counter
+		{0, 1, true},
+		{5, 6, true},
+		{5, 5, false},
+		{5, 0, false},
+	}
+	for _, tt := range tests {
+		if err := checkSignCount(tt.stored, tt.reported); (err == nil) != tt.ok {
+			t.Errorf("checkSignCount(%d, %d) = %v, want ok=%v", tt.stored, tt.reported, err, tt.ok)
+		}
+	}
+}
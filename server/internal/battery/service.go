@@ -31,9 +31,12 @@ type Store interface {
 	Update(ctx context.Context, userID string, params models.UpdateBatteryParams) (*models.Battery, error)
 	Delete(ctx context.Context, id, userID string) error
 	List(ctx context.Context, userID string, params models.BatteryListParams) (*models.BatteryListResponse, error)
+	ListAll(ctx context.Context, userID string) ([]models.Battery, error)
 	CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error)
 	ListLogs(ctx context.Context, batteryID, userID string, limit int) (*models.BatteryLogListResponse, error)
 	DeleteLog(ctx context.Context, logID, userID string) error
+	ListAllLogs(ctx context.Context, userID string) ([]models.BatteryLog, error)
+	ListAircraftNames(ctx context.Context, userID string) (map[string]string, error)
 }
 
 // Service handles battery operations
@@ -207,6 +210,7 @@ func (s *Service) CreateLog(ctx context.Context, userID string, params models.Cr
 	if params.BatteryID == "" {
 		return nil, &ServiceError{Message: "batteryId is required"}
 	}
+	params.AircraftID = strings.TrimSpace(params.AircraftID)
 
 	// Validate IR array length if provided
 	if params.IRMohmPerCell != nil {
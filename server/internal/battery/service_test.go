@@ -54,6 +54,10 @@ func (m *mockStore) List(ctx context.Context, userID string, params models.Batte
 	return &models.BatteryListResponse{}, nil
 }
 
+func (m *mockStore) ListAll(ctx context.Context, userID string) ([]models.Battery, error) {
+	return []models.Battery{}, nil
+}
+
 func (m *mockStore) CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error) {
 	return nil, nil
 }
@@ -66,6 +70,14 @@ func (m *mockStore) DeleteLog(ctx context.Context, logID, userID string) error {
 	return nil
 }
 
+func (m *mockStore) ListAllLogs(ctx context.Context, userID string) ([]models.BatteryLog, error) {
+	return []models.BatteryLog{}, nil
+}
+
+func (m *mockStore) ListAircraftNames(ctx context.Context, userID string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
 func newTestService() *Service {
 	return &Service{
 		store:  &mockStore{},
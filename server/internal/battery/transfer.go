@@ -0,0 +1,742 @@
+package battery
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// maxBatteryCodeLength matches the batteries.battery_code column size
+const maxBatteryCodeLength = 20
+
+// csvBatteryColumns and csvLogColumns make up the flattened CSV layout. Import
+// reads columns by header name, so spreadsheets may reorder or omit optional ones.
+var (
+	csvBatteryColumns = []string{
+		"battery_code", "name", "chemistry", "cells", "capacity_mah", "c_rating", "connector",
+		"weight_grams", "brand", "model", "purchase_date", "notes", "total_cycles",
+	}
+	csvLogColumns = []string{
+		"log_date", "cycle_count", "ir_milliohms", "min_cell_v", "max_cell_v", "storage_voltage_ok",
+		"aircraft_name", "log_notes",
+	}
+)
+
+// csvFormulaPrefixes are the leading characters that make a spreadsheet
+// treat a cell as a formula, plus the apostrophe escapeCSVText adds
+const csvFormulaPrefixes = "=+-@\t\r'"
+
+// importRecord is one battery parsed from an import file, with its logs
+type importRecord struct {
+	row    int
+	code   string
+	params models.CreateBatteryParams
+	logs   []importLog
+	err    string // Parse error; the whole battery is skipped
+}
+
+// importLog is one log entry parsed from an import file
+type importLog struct {
+	row          int
+	params       models.CreateBatteryLogParams
+	aircraftName string // Matched to one of the user's aircraft on import
+	err          string // Parse error; only this log is skipped
+}
+
+// Export builds the nested export of all batteries and their full log history
+func (s *Service) Export(ctx context.Context, userID string) (*models.BatteryExport, error) {
+	batteries, err := s.store.ListAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.store.ListAllLogs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logsByBattery := make(map[string][]models.BatteryLog)
+	for _, l := range logs {
+		logsByBattery[l.BatteryID] = append(logsByBattery[l.BatteryID], l)
+	}
+
+	export := &models.BatteryExport{
+		ExportedAt: time.Now().UTC(),
+		Batteries:  make([]models.BatteryExportItem, 0, len(batteries)),
+	}
+	for _, b := range batteries {
+		batteryLogs := logsByBattery[b.ID]
+		if batteryLogs == nil {
+			batteryLogs = []models.BatteryLog{}
+		}
+		export.Batteries = append(export.Batteries, models.BatteryExportItem{Battery: b, Logs: batteryLogs})
+	}
+
+	s.logger.Info("Exported batteries", logging.WithFields(map[string]interface{}{
+		"user_id":   userID,
+		"batteries": len(batteries),
+		"logs":      len(logs),
+	}))
+	return export, nil
+}
+
+// WriteExportCSV writes a flattened export: one row per log with the battery
+// columns repeated. Batteries without logs get a single row with empty log columns.
+func WriteExportCSV(w io.Writer, export *models.BatteryExport) error {
+	writer := csv.NewWriter(w)
+	header := append(append([]string{}, csvBatteryColumns...), csvLogColumns...)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	emptyLog := make([]string, len(csvLogColumns))
+	for _, item := range export.Batteries {
+		batteryFields := batteryCSVFields(item.Battery)
+		if len(item.Logs) == 0 {
+			if err := writer.Write(append(batteryFields, emptyLog...)); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+			continue
+		}
+		for _, l := range item.Logs {
+			row := append(append([]string{}, batteryFields...), logCSVFields(l)...)
+			if err := writer.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func batteryCSVFields(b models.Battery) []string {
+	purchaseDate := ""
+	if b.PurchaseDate != nil {
+		purchaseDate = b.PurchaseDate.Format("2006-01-02")
+	}
+	return []string{
+		escapeCSVText(b.BatteryCode),
+		escapeCSVText(b.Name),
+		string(b.Chemistry),
+		strconv.Itoa(b.Cells),
+		strconv.Itoa(b.CapacityMah),
+		formatOptionalInt(b.CRating),
+		escapeCSVText(b.Connector),
+		formatOptionalInt(b.WeightGrams),
+		escapeCSVText(b.Brand),
+		escapeCSVText(b.Model),
+		purchaseDate,
+		escapeCSVText(b.Notes),
+		strconv.Itoa(b.TotalCycles),
+	}
+}
+
+func logCSVFields(l models.BatteryLog) []string {
+	ir := ""
+	var irValues []float64
+	if len(l.IRMohmPerCell) > 0 && json.Unmarshal(l.IRMohmPerCell, &irValues) == nil {
+		parts := make([]string, len(irValues))
+		for i, v := range irValues {
+			parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		ir = strings.Join(parts, ";")
+	}
+	storageOk := ""
+	if l.StorageOk != nil {
+		storageOk = strconv.FormatBool(*l.StorageOk)
+	}
+	return []string{
+		l.LoggedAt.UTC().Format(time.RFC3339),
+		strconv.Itoa(l.CycleDelta),
+		ir,
+		formatOptionalFloat(l.MinCellV),
+		formatOptionalFloat(l.MaxCellV),
+		storageOk,
+		escapeCSVText(l.AircraftName),
+		escapeCSVText(l.Notes),
+	}
+}
+
+// escapeCSVText keeps spreadsheets from running free text as a formula by
+// prefixing an apostrophe, which they treat as a text marker. Values that
+// already start with an apostrophe get another so import can undo it exactly.
+func escapeCSVText(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVText undoes escapeCSVText
+func unescapeCSVText(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func formatOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// Import upserts batteries by battery code and appends their logs.
+// Invalid rows are reported in the result without aborting the rest of the file.
+// Logs already present for a battery (same log date, to the second) are skipped,
+// so re-importing an export is safe. A log's aircraft_name is matched to one of
+// the user's aircraft; when none matches, the log is kept with a warning.
+func (s *Service) Import(ctx context.Context, userID string, format models.BatteryExportFormat, r io.Reader) (*models.BatteryImportResult, error) {
+	var (
+		records []importRecord
+		err     error
+	)
+	switch format {
+	case models.BatteryExportFormatCSV:
+		records, err = parseImportCSV(r)
+	case models.BatteryExportFormatJSON:
+		records, err = parseImportJSON(r)
+	default:
+		return nil, &ServiceError{Message: "format must be csv or json"}
+	}
+	if err != nil {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+
+	existingLogs, err := s.store.ListAllLogs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	seenLogs := make(map[string]bool, len(existingLogs))
+	for _, l := range existingLogs {
+		seenLogs[importLogKey(l.BatteryID, l.LoggedAt)] = true
+	}
+
+	aircraft, err := s.importAircraftResolver(ctx, userID, records)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BatteryImportResult{}
+	fail := func(row int, code string, msg string) {
+		result.Errors = append(result.Errors, models.BatteryImportRowError{Row: row, BatteryCode: code, Error: msg})
+	}
+	warn := func(row int, code string, msg string) {
+		result.Warnings = append(result.Warnings, models.BatteryImportRowError{Row: row, BatteryCode: code, Error: msg})
+	}
+
+	for _, rec := range records {
+		if rec.err != "" {
+			fail(rec.row, rec.code, rec.err)
+			continue
+		}
+
+		battery, created, err := s.upsertImportedBattery(ctx, userID, rec)
+		if err != nil {
+			fail(rec.row, rec.code, err.Error())
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+
+		for _, l := range rec.logs {
+			if l.err != "" {
+				fail(l.row, battery.BatteryCode, l.err)
+				continue
+			}
+			if l.params.LoggedAt == nil {
+				fail(l.row, battery.BatteryCode, "log_date is required for imported logs")
+				continue
+			}
+
+			key := importLogKey(battery.ID, *l.params.LoggedAt)
+			if seenLogs[key] {
+				result.LogsSkipped++
+				continue
+			}
+
+			l.params.BatteryID = battery.ID
+			if l.aircraftName != "" {
+				aircraftID, warning := aircraft.resolve(l.aircraftName)
+				if warning != "" {
+					warn(l.row, battery.BatteryCode, warning)
+				}
+				l.params.AircraftID = aircraftID
+			}
+			if _, err := s.CreateLog(ctx, userID, l.params); err != nil {
+				fail(l.row, battery.BatteryCode, err.Error())
+				continue
+			}
+			seenLogs[key] = true
+			result.LogsCreated++
+		}
+	}
+	result.Failed = len(result.Errors)
+
+	s.logger.Info("Imported batteries", logging.WithFields(map[string]interface{}{
+		"user_id":      userID,
+		"created":      result.Created,
+		"updated":      result.Updated,
+		"logs_created": result.LogsCreated,
+		"failed":       result.Failed,
+	}))
+	return result, nil
+}
+
+// importAircraft matches imported aircraft names to the user's aircraft,
+// ignoring case. Names used by more than one aircraft match none of them.
+type importAircraft map[string][]string
+
+// importAircraftResolver loads the user's aircraft when any imported log
+// names one
+func (s *Service) importAircraftResolver(ctx context.Context, userID string, records []importRecord) (importAircraft, error) {
+	needed := false
+	for _, rec := range records {
+		for _, l := range rec.logs {
+			needed = needed || l.aircraftName != ""
+		}
+	}
+	if !needed {
+		return importAircraft{}, nil
+	}
+
+	names, err := s.store.ListAircraftNames(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	aircraft := make(importAircraft, len(names))
+	for id, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		aircraft[key] = append(aircraft[key], id)
+	}
+	return aircraft, nil
+}
+
+// resolve returns the ID of the aircraft with the name, or a warning saying
+// why the log is imported without one
+func (a importAircraft) resolve(name string) (string, string) {
+	switch ids := a[strings.ToLower(strings.TrimSpace(name))]; len(ids) {
+	case 0:
+		return "", fmt.Sprintf("aircraft %q not found; log imported without an aircraft", name)
+	case 1:
+		return ids[0], ""
+	default:
+		return "", fmt.Sprintf("several aircraft are named %q; log imported without an aircraft", name)
+	}
+}
+
+// upsertImportedBattery creates the battery, or updates the user's battery with
+// the same code. Empty optional fields leave existing values untouched.
+func (s *Service) upsertImportedBattery(ctx context.Context, userID string, rec importRecord) (*models.Battery, bool, error) {
+	if err := s.validateCreateParams(rec.params); err != nil {
+		return nil, false, err
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(rec.code))
+	if len(code) > maxBatteryCodeLength {
+		return nil, false, &ServiceError{Message: fmt.Sprintf("battery_code must be at most %d characters", maxBatteryCodeLength)}
+	}
+
+	if code != "" {
+		existing, err := s.store.GetByCode(ctx, code, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			battery, err := s.store.Update(ctx, userID, importUpdateParams(existing.ID, rec.params))
+			if err != nil {
+				return nil, false, err
+			}
+			if battery == nil {
+				return nil, false, &ServiceError{Message: "battery not found"}
+			}
+			return battery, false, nil
+		}
+	} else {
+		generated, err := s.generateBatteryCode(ctx, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		code = generated
+	}
+
+	battery, err := s.store.Create(ctx, userID, code, rec.params)
+	if err != nil {
+		return nil, false, err
+	}
+	return battery, true, nil
+}
+
+func importUpdateParams(id string, params models.CreateBatteryParams) models.UpdateBatteryParams {
+	update := models.UpdateBatteryParams{
+		ID:           id,
+		Chemistry:    &params.Chemistry,
+		Cells:        &params.Cells,
+		CapacityMah:  &params.CapacityMah,
+		CRating:      params.CRating,
+		WeightGrams:  params.WeightGrams,
+		PurchaseDate: params.PurchaseDate,
+	}
+	if params.Name != "" {
+		update.Name = &params.Name
+	}
+	if params.Connector != "" {
+		update.Connector = &params.Connector
+	}
+	if params.Brand != "" {
+		update.Brand = &params.Brand
+	}
+	if params.Model != "" {
+		update.Model = &params.Model
+	}
+	if params.Notes != "" {
+		update.Notes = &params.Notes
+	}
+	return update
+}
+
+func importLogKey(batteryID string, loggedAt time.Time) string {
+	return batteryID + "|" + strconv.FormatInt(loggedAt.Unix(), 10)
+}
+
+// parseImportCSV reads a flattened CSV. Rows sharing a battery_code are merged:
+// the first row supplies the battery fields and every row may add a log entry.
+func parseImportCSV(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[normalizeCSVHeader(h)] = i
+	}
+	for _, required := range []string{"chemistry", "cells", "capacity_mah"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV is missing required column %q", required)
+		}
+	}
+
+	records := []importRecord{}
+	recordByCode := make(map[string]int)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				records = append(records, importRecord{row: parseErr.StartLine, err: parseErr.Err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		row, _ := reader.FieldPos(0)
+
+		get := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(fields) {
+				return ""
+			}
+			return unescapeCSVText(strings.TrimSpace(fields[idx]))
+		}
+
+		if isBlankRow(fields) {
+			continue
+		}
+
+		code := strings.ToUpper(get("battery_code"))
+		hasLog := false
+		for _, col := range csvLogColumns {
+			if get(col) != "" {
+				hasLog = true
+				break
+			}
+		}
+
+		idx, seen := recordByCode[code]
+		if !seen || code == "" {
+			records = append(records, parseCSVBattery(row, code, get))
+			idx = len(records) - 1
+			if code != "" {
+				recordByCode[code] = idx
+			}
+		}
+		if hasLog {
+			records[idx].logs = append(records[idx].logs, parseCSVLog(row, get))
+		}
+	}
+
+	return records, nil
+}
+
+func parseCSVBattery(row int, code string, get func(string) string) importRecord {
+	rec := importRecord{row: row, code: code}
+	var errs []string
+	parseInt := func(col string) int {
+		v := get(col)
+		if v == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s %q", col, v))
+		}
+		return n
+	}
+	parseOptionalInt := func(col string) *int {
+		if get(col) == "" {
+			return nil
+		}
+		n := parseInt(col)
+		return &n
+	}
+
+	rec.params = models.CreateBatteryParams{
+		Name:        get("name"),
+		Chemistry:   normalizeChemistry(get("chemistry")),
+		Cells:       parseInt("cells"),
+		CapacityMah: parseInt("capacity_mah"),
+		CRating:     parseOptionalInt("c_rating"),
+		Connector:   get("connector"),
+		WeightGrams: parseOptionalInt("weight_grams"),
+		Brand:       get("brand"),
+		Model:       get("model"),
+		Notes:       get("notes"),
+	}
+	if v := get("purchase_date"); v != "" {
+		t, err := parseImportTime(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid purchase_date %q", v))
+		} else {
+			rec.params.PurchaseDate = &t
+		}
+	}
+
+	rec.err = strings.Join(errs, "; ")
+	return rec
+}
+
+func parseCSVLog(row int, get func(string) string) importLog {
+	l := importLog{row: row}
+	var errs []string
+	parseOptionalFloat := func(col string) *float64 {
+		v := get(col)
+		if v == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s %q", col, v))
+			return nil
+		}
+		return &f
+	}
+
+	l.params.Notes = get("log_notes")
+	l.aircraftName = get("aircraft_name")
+	l.params.MinCellV = parseOptionalFloat("min_cell_v")
+	l.params.MaxCellV = parseOptionalFloat("max_cell_v")
+	if v := get("log_date"); v != "" {
+		t, err := parseImportTime(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid log_date %q", v))
+		} else {
+			l.params.LoggedAt = &t
+		}
+	}
+	if v := get("cycle_count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid cycle_count %q", v))
+		}
+		l.params.CycleDelta = n
+	}
+	if v := get("storage_voltage_ok"); v != "" {
+		b, err := strconv.ParseBool(strings.ToLower(v))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid storage_voltage_ok %q", v))
+		} else {
+			l.params.StorageOk = &b
+		}
+	}
+	if v := get("ir_milliohms"); v != "" {
+		ir, err := parseIRValues(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid ir_milliohms %q", v))
+		} else {
+			l.params.IRMohmPerCell = ir
+		}
+	}
+
+	l.err = strings.Join(errs, "; ")
+	return l
+}
+
+// parseImportJSON reads either a full export ({"batteries": [...]}) or a bare array of batteries
+func parseImportJSON(r io.Reader) ([]importRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("JSON file is empty")
+	}
+
+	var items []json.RawMessage
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else {
+		var wrapper struct {
+			Batteries []json.RawMessage `json:"batteries"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		items = wrapper.Batteries
+	}
+
+	records := make([]importRecord, 0, len(items))
+	for i, raw := range items {
+		row := i + 1
+		var item models.BatteryExportItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			records = append(records, importRecord{row: row, err: fmt.Sprintf("invalid battery: %v", err)})
+			continue
+		}
+
+		rec := importRecord{
+			row:  row,
+			code: strings.ToUpper(strings.TrimSpace(item.BatteryCode)),
+			params: models.CreateBatteryParams{
+				Name:         item.Name,
+				Chemistry:    normalizeChemistry(string(item.Chemistry)),
+				Cells:        item.Cells,
+				CapacityMah:  item.CapacityMah,
+				CRating:      item.CRating,
+				Connector:    item.Connector,
+				WeightGrams:  item.WeightGrams,
+				Brand:        item.Brand,
+				Model:        item.Model,
+				PurchaseDate: item.PurchaseDate,
+				Notes:        item.Notes,
+			},
+		}
+		for _, l := range item.Logs {
+			loggedAt := l.LoggedAt
+			params := models.CreateBatteryLogParams{
+				CycleDelta:    l.CycleDelta,
+				IRMohmPerCell: l.IRMohmPerCell,
+				MinCellV:      l.MinCellV,
+				MaxCellV:      l.MaxCellV,
+				StorageOk:     l.StorageOk,
+				Notes:         l.Notes,
+			}
+			if !loggedAt.IsZero() {
+				params.LoggedAt = &loggedAt
+			}
+			rec.logs = append(rec.logs, importLog{row: row, params: params, aircraftName: l.AircraftName})
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// normalizeChemistry maps spreadsheet spellings like "LiPo HV" or "Li-Ion" onto chemistry constants.
+// Unknown values are passed through so validation reports them.
+func normalizeChemistry(value string) models.BatteryChemistry {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	switch strings.NewReplacer(" ", "", "-", "", "_", "").Replace(upper) {
+	case "LIPO":
+		return models.ChemistryLIPO
+	case "LIPOHV", "LIHV":
+		return models.ChemistryLIPOHV
+	case "LIION":
+		return models.ChemistryLIION
+	default:
+		return models.BatteryChemistry(upper)
+	}
+}
+
+// parseIRValues accepts "2.1;2.3;2.0" (CSV export format), comma/space separated values, or a JSON array
+func parseIRValues(value string) (json.RawMessage, error) {
+	if strings.HasPrefix(value, "[") {
+		var values []float64
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			return nil, err
+		}
+		return json.Marshal(values)
+	}
+
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == ',' || r == ' ' || r == '|'
+	})
+	values := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return json.Marshal(values)
+}
+
+// parseImportTime accepts RFC3339 timestamps and plain dates
+func parseImportTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "01/02/2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+func normalizeCSVHeader(h string) string {
+	h = strings.TrimPrefix(h, "\ufeff") // Excel BOM
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.ReplaceAll(h, " ", "_")
+}
+
+func isBlankRow(fields []string) bool {
+	for _, f := range fields {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,351 @@
+package battery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// transferStore is an in-memory Store for export/import round trips
+type transferStore struct {
+	mockStore
+	batteries []*models.Battery
+	logs      []models.BatteryLog
+	aircraft  map[string]string // Aircraft names by ID
+}
+
+func (m *transferStore) Create(ctx context.Context, userID, code string, params models.CreateBatteryParams) (*models.Battery, error) {
+	b := &models.Battery{
+		ID:           fmt.Sprintf("bat-%d", len(m.batteries)+1),
+		UserID:       userID,
+		BatteryCode:  code,
+		Name:         params.Name,
+		Chemistry:    params.Chemistry,
+		Cells:        params.Cells,
+		CapacityMah:  params.CapacityMah,
+		CRating:      params.CRating,
+		Connector:    params.Connector,
+		Brand:        params.Brand,
+		PurchaseDate: params.PurchaseDate,
+	}
+	m.batteries = append(m.batteries, b)
+	return b, nil
+}
+
+func (m *transferStore) Get(ctx context.Context, id, userID string) (*models.Battery, error) {
+	for _, b := range m.batteries {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *transferStore) GetByCode(ctx context.Context, code, userID string) (*models.Battery, error) {
+	for _, b := range m.batteries {
+		if b.BatteryCode == code {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *transferStore) Update(ctx context.Context, userID string, params models.UpdateBatteryParams) (*models.Battery, error) {
+	b, _ := m.Get(ctx, params.ID, userID)
+	if b == nil {
+		return nil, nil
+	}
+	if params.Name != nil {
+		b.Name = *params.Name
+	}
+	if params.Cells != nil {
+		b.Cells = *params.Cells
+	}
+	if params.CapacityMah != nil {
+		b.CapacityMah = *params.CapacityMah
+	}
+	return b, nil
+}
+
+func (m *transferStore) ListAll(ctx context.Context, userID string) ([]models.Battery, error) {
+	out := make([]models.Battery, 0, len(m.batteries))
+	for _, b := range m.batteries {
+		out = append(out, *b)
+	}
+	return out, nil
+}
+
+func (m *transferStore) CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error) {
+	l := models.BatteryLog{
+		ID:            fmt.Sprintf("log-%d", len(m.logs)+1),
+		BatteryID:     params.BatteryID,
+		AircraftID:    params.AircraftID,
+		AircraftName:  m.aircraft[params.AircraftID],
+		LoggedAt:      *params.LoggedAt,
+		CycleDelta:    params.CycleDelta,
+		IRMohmPerCell: params.IRMohmPerCell,
+		MinCellV:      params.MinCellV,
+		StorageOk:     params.StorageOk,
+		Notes:         params.Notes,
+	}
+	m.logs = append(m.logs, l)
+	return &l, nil
+}
+
+func (m *transferStore) ListAllLogs(ctx context.Context, userID string) ([]models.BatteryLog, error) {
+	return append([]models.BatteryLog{}, m.logs...), nil
+}
+
+func (m *transferStore) ListAircraftNames(ctx context.Context, userID string) (map[string]string, error) {
+	return m.aircraft, nil
+}
+
+func newTransferService(store *transferStore) *Service {
+	return &Service{store: store, logger: testutil.NullLogger()}
+}
+
+func TestImport_CSV(t *testing.T) {
+	store := &transferStore{}
+	svc := newTransferService(store)
+
+	csvData := strings.Join([]string{
+		"Battery Code,Name,Chemistry,Cells,Capacity_mAh,log_date,cycle_count,ir_milliohms,storage_voltage_ok",
+		"BAT-0001,Race pack,LiPo,6,1300,2025-03-01T10:00:00Z,1,2.1;2.2;2.0;2.3;2.1;2.2,true",
+		"BAT-0001,,,,,2025-03-02T10:00:00Z,1,,",
+		"BAT-0002,Bad cells,LIPO,12,1300,,,,",
+		"BAT-0003,Bad IR,LIPO,4,850,2025-03-01T10:00:00Z,1,2.1;2.2,",
+		",No code,Li-Ion,2,3000,,,,",
+	}, "\n")
+
+	result, err := svc.Import(context.Background(), "user-1", models.BatteryExportFormatCSV, strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if result.Created != 3 {
+		t.Errorf("Created = %d, want 3", result.Created)
+	}
+	if result.LogsCreated != 2 {
+		t.Errorf("LogsCreated = %d, want 2", result.LogsCreated)
+	}
+	if result.Failed != 2 || len(result.Errors) != 2 {
+		t.Fatalf("Failed = %d, Errors = %+v, want 2 failures", result.Failed, result.Errors)
+	}
+	if result.Errors[0].Row != 4 || !strings.Contains(result.Errors[0].Error, "cells must be between 1 and 8") {
+		t.Errorf("Errors[0] = %+v, want row 4 cell validation error", result.Errors[0])
+	}
+	if result.Errors[1].Row != 5 || !strings.Contains(result.Errors[1].Error, "IR array length") {
+		t.Errorf("Errors[1] = %+v, want row 5 IR length error", result.Errors[1])
+	}
+
+	noCode := store.batteries[2]
+	if !strings.HasPrefix(noCode.BatteryCode, "BAT-") || noCode.Chemistry != models.ChemistryLIION {
+		t.Errorf("battery without code = %+v, want generated code and LIION chemistry", noCode)
+	}
+}
+
+func TestImport_UpsertsByCodeAndSkipsExistingLogs(t *testing.T) {
+	loggedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := &transferStore{
+		batteries: []*models.Battery{{ID: "bat-1", BatteryCode: "BAT-0001", Name: "Old name", Chemistry: models.ChemistryLIPO, Cells: 4, CapacityMah: 1500}},
+		logs:      []models.BatteryLog{{ID: "log-1", BatteryID: "bat-1", LoggedAt: loggedAt.Add(250 * time.Millisecond), CycleDelta: 1}},
+	}
+	svc := newTransferService(store)
+
+	jsonData := `{"batteries": [{
+		"battery_code": "bat-0001",
+		"name": "New name",
+		"chemistry": "LIPO",
+		"cells": 4,
+		"capacity_mah": 1550,
+		"logs": [
+			{"log_date": "2025-03-01T10:00:00Z", "cycle_count": 1},
+			{"log_date": "2025-03-05T10:00:00Z", "cycle_count": 2}
+		]
+	}]}`
+
+	result, err := svc.Import(context.Background(), "user-1", models.BatteryExportFormatJSON, strings.NewReader(jsonData))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if result.Created != 0 || result.Updated != 1 {
+		t.Errorf("Created/Updated = %d/%d, want 0/1", result.Created, result.Updated)
+	}
+	if result.LogsCreated != 1 || result.LogsSkipped != 1 {
+		t.Errorf("LogsCreated/LogsSkipped = %d/%d, want 1/1", result.LogsCreated, result.LogsSkipped)
+	}
+	if store.batteries[0].Name != "New name" || store.batteries[0].CapacityMah != 1550 {
+		t.Errorf("battery = %+v, want updated name and capacity", store.batteries[0])
+	}
+}
+
+func TestImport_InvalidFile(t *testing.T) {
+	svc := newTransferService(&transferStore{})
+
+	tests := []struct {
+		name   string
+		format models.BatteryExportFormat
+		data   string
+		errMsg string
+	}{
+		{name: "unknown format", format: "xlsx", data: "", errMsg: "format must be csv or json"},
+		{name: "empty CSV", format: models.BatteryExportFormatCSV, data: "", errMsg: "CSV file is empty"},
+		{name: "missing column", format: models.BatteryExportFormatCSV, data: "battery_code,cells\nBAT-1,4", errMsg: `missing required column "chemistry"`},
+		{name: "malformed JSON", format: models.BatteryExportFormatJSON, data: `{"batteries":`, errMsg: "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Import(context.Background(), "user-1", tt.format, strings.NewReader(tt.data))
+			if err == nil {
+				t.Fatal("Import() expected error, got nil")
+			}
+			if !containsString(err.Error(), tt.errMsg) {
+				t.Errorf("Import() error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	storageOk := true
+	source := &transferStore{}
+	svc := newTransferService(source)
+	ctx := context.Background()
+
+	b, _ := source.Create(ctx, "user-1", "BAT-0001", models.CreateBatteryParams{Name: "Pack, \"A\"", Chemistry: models.ChemistryLIPOHV, Cells: 4, CapacityMah: 1500, CRating: intPtr(100)})
+	_, _ = source.Create(ctx, "user-1", "BAT-0002", models.CreateBatteryParams{Chemistry: models.ChemistryLIPO, Cells: 1, CapacityMah: 450})
+	loggedAt := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	_, _ = source.CreateLog(ctx, "user-1", models.CreateBatteryLogParams{
+		BatteryID: b.ID, LoggedAt: &loggedAt, CycleDelta: 1,
+		IRMohmPerCell: json.RawMessage(`[2.5,2.6,2.4,2.5]`), StorageOk: &storageOk,
+	})
+
+	export, err := svc.Export(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(export.Batteries) != 2 || len(export.Batteries[0].Logs) != 1 || export.Batteries[1].Logs == nil {
+		t.Fatalf("Export() = %+v, want 2 batteries with nested logs", export)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExportCSV(&buf, export); err != nil {
+		t.Fatalf("WriteExportCSV() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "2.5;2.6;2.4;2.5") {
+		t.Errorf("CSV = %s, want flattened IR values", buf.String())
+	}
+
+	target := &transferStore{}
+	result, err := newTransferService(target).Import(ctx, "user-1", models.BatteryExportFormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Created != 2 || result.LogsCreated != 1 || result.Failed != 0 {
+		t.Fatalf("Import() = %+v, want 2 created, 1 log, no failures", result)
+	}
+	if target.batteries[0].Name != `Pack, "A"` || *target.batteries[0].CRating != 100 {
+		t.Errorf("imported battery = %+v, want quoted name and c_rating preserved", target.batteries[0])
+	}
+	if string(target.logs[0].IRMohmPerCell) != `[2.5,2.6,2.4,2.5]` || !target.logs[0].LoggedAt.Equal(loggedAt) {
+		t.Errorf("imported log = %+v, want IR and log date preserved", target.logs[0])
+	}
+}
+
+func TestExport_CSVEscapesFormulas(t *testing.T) {
+	source := &transferStore{aircraft: map[string]string{"ac-1": "@Race quad"}}
+	ctx := context.Background()
+	b, _ := source.Create(ctx, "user-1", "BAT-0001", models.CreateBatteryParams{
+		Name: "=HYPERLINK(\"http://evil.example\")", Chemistry: models.ChemistryLIPO, Cells: 4, CapacityMah: 1500,
+		Connector: "+XT60", Brand: "'Quoted",
+	})
+	loggedAt := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	_, _ = source.CreateLog(ctx, "user-1", models.CreateBatteryLogParams{
+		BatteryID: b.ID, AircraftID: "ac-1", LoggedAt: &loggedAt, Notes: "-cmd|' /C calc'!A0",
+	})
+
+	export, err := newTransferService(source).Export(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteExportCSV(&buf, export); err != nil {
+		t.Fatalf("WriteExportCSV() error = %v", err)
+	}
+	for _, want := range []string{`"'=HYPERLINK(""http://evil.example"")"`, "'+XT60", "''Quoted", "'@Race quad", "'-cmd|"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("CSV = %s, want it to contain %s", buf.String(), want)
+		}
+	}
+
+	target := &transferStore{aircraft: map[string]string{"ac-9": "@race quad"}}
+	result, err := newTransferService(target).Import(ctx, "user-1", models.BatteryExportFormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Created != 1 || result.LogsCreated != 1 || result.Failed != 0 || len(result.Warnings) != 0 {
+		t.Fatalf("Import() = %+v, want 1 battery and 1 log without problems", result)
+	}
+	imported := target.batteries[0]
+	if imported.Name != `=HYPERLINK("http://evil.example")` || imported.Connector != "+XT60" || imported.Brand != "'Quoted" {
+		t.Errorf("imported battery = %+v, want the escapes undone", imported)
+	}
+	if target.logs[0].Notes != "-cmd|' /C calc'!A0" || target.logs[0].AircraftID != "ac-9" {
+		t.Errorf("imported log = %+v, want notes restored and the aircraft matched by name", target.logs[0])
+	}
+}
+
+func TestImport_ResolvesAircraftByName(t *testing.T) {
+	store := &transferStore{aircraft: map[string]string{
+		"ac-1": "Cinewhoop",
+		"ac-2": "Freestyle",
+		"ac-3": "freestyle",
+	}}
+	svc := newTransferService(store)
+
+	csvData := strings.Join([]string{
+		"battery_code,chemistry,cells,capacity_mah,log_date,aircraft_name",
+		"BAT-0001,LIPO,4,1500,2025-03-01T10:00:00Z,cinewhoop",
+		"BAT-0001,,,,2025-03-02T10:00:00Z,Freestyle",
+		"BAT-0001,,,,2025-03-03T10:00:00Z,Long range",
+	}, "\n")
+
+	result, err := svc.Import(context.Background(), "user-1", models.BatteryExportFormatCSV, strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.LogsCreated != 3 || result.Failed != 0 {
+		t.Fatalf("Import() = %+v, want 3 logs and no failures", result)
+	}
+	if store.logs[0].AircraftID != "ac-1" || store.logs[1].AircraftID != "" || store.logs[2].AircraftID != "" {
+		t.Errorf("log aircraft = %q, %q, %q; want only the first matched", store.logs[0].AircraftID, store.logs[1].AircraftID, store.logs[2].AircraftID)
+	}
+	if len(result.Warnings) != 2 || result.Warnings[0].Row != 3 || !strings.Contains(result.Warnings[0].Error, "several aircraft") ||
+		result.Warnings[1].Row != 4 || !strings.Contains(result.Warnings[1].Error, "not found") {
+		t.Errorf("Warnings = %+v, want an ambiguous and a missing aircraft", result.Warnings)
+	}
+}
+
+func TestNormalizeChemistry(t *testing.T) {
+	tests := map[string]models.BatteryChemistry{
+		"LiPo":    models.ChemistryLIPO,
+		"lipo hv": models.ChemistryLIPOHV,
+		"LIPO_HV": models.ChemistryLIPOHV,
+		"Li-Ion":  models.ChemistryLIION,
+		"nimh":    "NIMH",
+	}
+	for input, want := range tests {
+		if got := normalizeChemistry(input); got != want {
+			t.Errorf("normalizeChemistry(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
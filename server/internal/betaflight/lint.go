@@ -0,0 +1,107 @@
+package betaflight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// LintSeverity ranks how urgent a lint finding is
+type LintSeverity string
+
+const (
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// LintFinding is one rule-of-thumb observation about a parsed tune
+type LintFinding struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+}
+
+// Lint checks a parsed tune against common Betaflight rules of thumb. It only
+// looks at values that were parsed, so a partial dump yields fewer findings
+// rather than false positives.
+func Lint(tuning *models.ParsedTuning) []LintFinding {
+	findings := []LintFinding{}
+	if tuning == nil {
+		return findings
+	}
+
+	if pids := tuning.PIDs; pids != nil {
+		for _, axis := range []struct {
+			name string
+			pid  models.AxisPID
+		}{{"roll", pids.Roll}, {"pitch", pids.Pitch}} {
+			if axis.pid.P > 0 && axis.pid.D > axis.pid.P {
+				findings = append(findings, LintFinding{
+					Rule:     "d-above-p",
+					Severity: LintSeverityWarning,
+					Message:  fmt.Sprintf("%s D (%d) is higher than P (%d), which often runs motors hot", axis.name, axis.pid.D, axis.pid.P),
+				})
+			}
+		}
+		if pids.Yaw.D > 0 {
+			findings = append(findings, LintFinding{
+				Rule:     "yaw-d",
+				Severity: LintSeverityInfo,
+				Message:  fmt.Sprintf("yaw D is %d; most tunes leave it at 0", pids.Yaw.D),
+			})
+		}
+	}
+
+	if filters := tuning.Filters; filters != nil {
+		if !filters.RPMFilterEnabled && !filters.DynNotchEnabled {
+			findings = append(findings, LintFinding{
+				Rule:     "no-notch-filtering",
+				Severity: LintSeverityWarning,
+				Message:  "neither the RPM filter nor the dynamic notch is enabled, so motor noise reaches the PID loop unfiltered",
+			})
+		}
+		if !filters.GyroLowpassEnabled && !filters.GyroLowpass2Enabled && !filters.GyroDynLowpassEnabled {
+			findings = append(findings, LintFinding{
+				Rule:     "no-gyro-lowpass",
+				Severity: LintSeverityWarning,
+				Message:  "all gyro lowpass filters are disabled",
+			})
+		}
+		if filters.RPMFilterEnabled && tuning.MotorMixer != nil && !tuning.MotorMixer.DShotBidir {
+			findings = append(findings, LintFinding{
+				Rule:     "rpm-filter-without-bidir",
+				Severity: LintSeverityWarning,
+				Message:  "the RPM filter is enabled but bidirectional DShot is off, so it has no RPM data to work with",
+			})
+		}
+	}
+
+	if mixer := tuning.MotorMixer; mixer != nil {
+		if mixer.MotorProtocol != "" && !strings.HasPrefix(strings.ToUpper(mixer.MotorProtocol), "DSHOT") {
+			findings = append(findings, LintFinding{
+				Rule:     "analog-motor-protocol",
+				Severity: LintSeverityInfo,
+				Message:  fmt.Sprintf("motor protocol is %s; DShot is recommended for modern ESCs", mixer.MotorProtocol),
+			})
+		}
+		if mixer.GyroHz > 0 && mixer.PIDHz > 0 && mixer.PIDHz > mixer.GyroHz {
+			findings = append(findings, LintFinding{
+				Rule:     "pid-faster-than-gyro",
+				Severity: LintSeverityWarning,
+				Message:  fmt.Sprintf("PID loop (%d Hz) runs faster than the gyro (%d Hz)", mixer.PIDHz, mixer.GyroHz),
+			})
+		}
+	}
+
+	if misc := tuning.Misc; misc != nil && misc.VBatWarningCellVoltage > 0 && misc.VBatMinCellVoltage > 0 &&
+		misc.VBatWarningCellVoltage <= misc.VBatMinCellVoltage {
+		findings = append(findings, LintFinding{
+			Rule:     "vbat-warning-below-min",
+			Severity: LintSeverityWarning,
+			Message:  "the low battery warning voltage is not above the minimum cell voltage, so it will never warn before the pack is empty",
+		})
+	}
+
+	return findings
+}
@@ -0,0 +1,47 @@
+package betaflight
+
+import (
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+func lintRules(findings []LintFinding) map[string]bool {
+	rules := map[string]bool{}
+	for _, finding := range findings {
+		rules[finding.Rule] = true
+	}
+	return rules
+}
+
+func TestLint_FlagsCommonMistakes(t *testing.T) {
+	tuning := &models.ParsedTuning{
+		PIDs: &models.PIDProfile{
+			Roll:  models.AxisPID{P: 45, I: 80, D: 50},
+			Pitch: models.AxisPID{P: 47, I: 84, D: 40},
+			Yaw:   models.AxisPID{P: 45, I: 80, D: 5},
+		},
+		Filters:    &models.FilterSettings{GyroLowpassEnabled: true, RPMFilterEnabled: true},
+		MotorMixer: &models.MotorMixerConfig{MotorProtocol: "MULTISHOT", GyroHz: 4000, PIDHz: 8000},
+		Misc:       &models.MiscSettings{VBatMinCellVoltage: 330, VBatWarningCellVoltage: 330},
+	}
+
+	rules := lintRules(Lint(tuning))
+	for _, want := range []string{"d-above-p", "yaw-d", "rpm-filter-without-bidir", "analog-motor-protocol", "pid-faster-than-gyro", "vbat-warning-below-min"} {
+		if !rules[want] {
+			t.Errorf("Lint() missing %q, got %v", want, rules)
+		}
+	}
+	if rules["no-notch-filtering"] || rules["no-gyro-lowpass"] {
+		t.Errorf("Lint() flagged filters that are enabled: %v", rules)
+	}
+}
+
+func TestLint_PartialTuneHasNoFindings(t *testing.T) {
+	if findings := Lint(&models.ParsedTuning{}); len(findings) != 0 {
+		t.Errorf("Lint() = %v, want no findings for an empty tune", findings)
+	}
+	if findings := Lint(nil); len(findings) != 0 {
+		t.Errorf("Lint(nil) = %v, want no findings", findings)
+	}
+}
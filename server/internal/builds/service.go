@@ -5,8 +5,9 @@ import (
 	crand "crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"io"
 	"sort"
 	"strings"
 	"time"
@@ -58,13 +59,21 @@ type buildStore interface {
 	SetStatus(ctx context.Context, id string, ownerUserID string, status models.BuildStatus) (*models.Build, error)
 	SetImage(ctx context.Context, id string, ownerUserID string, imageAssetID string) (string, error)
 	SetImageForModeration(ctx context.Context, id string, imageAssetID string) (string, error)
-	GetImageForOwner(ctx context.Context, id string, ownerUserID string) ([]byte, error)
-	GetPublicImage(ctx context.Context, id string) ([]byte, error)
+	GetImageForOwner(ctx context.Context, id string, ownerUserID string) (*models.ImageRef, error)
+	GetPublicImage(ctx context.Context, id string) (*models.ImageRef, error)
 	SetReaction(ctx context.Context, id string, userID string, reaction models.BuildReaction) (*models.Build, error)
 	ClearReaction(ctx context.Context, id string, userID string) (*models.Build, error)
-	GetImageForModeration(ctx context.Context, id string) ([]byte, error)
+	GetImageForModeration(ctx context.Context, id string) (*models.ImageRef, error)
 	DeleteImage(ctx context.Context, id string, ownerUserID string) (string, error)
 	DeleteImageForModeration(ctx context.Context, id string) (string, error)
+	ListGallery(ctx context.Context, id string, ownerUserID string) ([]models.GalleryImage, error)
+	AddGalleryImage(ctx context.Context, id string, ownerUserID string, imageAssetID string, caption string) (*models.GalleryImage, error)
+	UpdateGalleryImage(ctx context.Context, id string, ownerUserID string, imageID string, params models.UpdateGalleryImageParams) (*models.GalleryImage, error)
+	ReorderGallery(ctx context.Context, id string, ownerUserID string, imageIDs []string) ([]models.GalleryImage, error)
+	DeleteGalleryImage(ctx context.Context, id string, ownerUserID string, imageID string) (string, error)
+	GetGalleryImageForOwner(ctx context.Context, id string, ownerUserID string, imageID string) (*models.ImageRef, error)
+	GetPublicGalleryImage(ctx context.Context, id string, imageID string) (*models.ImageRef, error)
+	GetGalleryImageForModeration(ctx context.Context, id string, imageID string) (*models.ImageRef, error)
 	ApproveForModeration(ctx context.Context, id string) (*models.Build, error)
 	DeclineForModeration(ctx context.Context, id string, reason string) (*models.Build, error)
 	Delete(ctx context.Context, id string, ownerUserID string) (bool, error)
@@ -73,7 +82,7 @@ type buildStore interface {
 
 type aircraftDetailsReader interface {
 	GetDetails(ctx context.Context, id string, userID string) (*models.AircraftDetailsResponse, error)
-	GetImage(ctx context.Context, id string, userID string) ([]byte, string, error)
+	GetImage(ctx context.Context, id string, userID string) (*models.ImageRef, error)
 }
 
 type gearCatalogMigrator interface {
@@ -89,6 +98,7 @@ type imagePipeline interface {
 	ModerateAndPersist(ctx context.Context, req images.SaveRequest) (*models.ModerationDecision, *models.ImageAsset, error)
 	PersistApprovedUpload(ctx context.Context, ownerUserID, uploadID string, entityType models.ImageEntityType, entityID string) (*models.ImageAsset, error)
 	Delete(ctx context.Context, imageID string) error
+	OpenRef(ctx context.Context, ref *models.ImageRef, size models.ImageSize) (*images.ImageStream, error)
 }
 
 // Service coordinates build business logic.
@@ -782,65 +792,225 @@ func (s *Service) SetImage(ctx context.Context, userID string, params models.Set
 		return nil, &ServiceError{Message: "build not found"}
 	}
 
-	var (
-		decision *models.ModerationDecision
-		asset    *models.ImageAsset
-	)
+	decision, asset, err := s.persistBuildImage(ctx, userID, build.ID, params.UploadID, params.ImageType, params.ImageData)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.Accepted() {
+		return decision, nil
+	}
+	if asset == nil {
+		return nil, &ServiceError{Message: "failed to persist build image"}
+	}
+
+	previousAssetID, err := s.store.SetImage(ctx, build.ID, userID, asset.ID)
+	if err != nil {
+		_ = s.imageSvc.Delete(ctx, asset.ID)
+		return nil, err
+	}
+	if previousAssetID != "" && previousAssetID != asset.ID {
+		_ = s.imageSvc.Delete(ctx, previousAssetID)
+	}
+
+	return decision, nil
+}
 
-	uploadID := strings.TrimSpace(params.UploadID)
+// persistBuildImage stores a build image from an upload token, or moderates
+// raw bytes first. The asset is nil unless moderation approved the image or
+// queued it for review.
+func (s *Service) persistBuildImage(ctx context.Context, userID string, buildID string, uploadID string, imageType string, imageData []byte) (*models.ModerationDecision, *models.ImageAsset, error) {
+	uploadID = strings.TrimSpace(uploadID)
 	if uploadID != "" {
-		asset, err = s.imageSvc.PersistApprovedUpload(ctx, userID, uploadID, models.ImageEntityBuild, build.ID)
+		asset, err := s.imageSvc.PersistApprovedUpload(ctx, userID, uploadID, models.ImageEntityBuild, buildID)
 		if err != nil {
-			return nil, err
-		}
-		decision = &models.ModerationDecision{
-			Status: models.ImageModerationApproved,
-			Reason: "Approved",
-		}
-		if params.ImageType == "" {
-			params.ImageType = http.DetectContentType(asset.ImageBytes)
-		}
-	} else {
-		if len(params.ImageData) == 0 {
-			return nil, &ServiceError{Message: "image data is required"}
-		}
-		if params.ImageType != "image/jpeg" && params.ImageType != "image/png" {
-			return nil, &ServiceError{Message: "image must be JPEG or PNG"}
+			return nil, nil, err
 		}
+		return images.AssetDecision(asset), asset, nil
+	}
+
+	if len(imageData) == 0 {
+		return nil, nil, &ServiceError{Message: "image data is required"}
+	}
+	if imageType != "image/jpeg" && imageType != "image/png" {
+		return nil, nil, &ServiceError{Message: "image must be JPEG or PNG"}
+	}
+
+	const maxImageSize = 2 * 1024 * 1024
+	if len(imageData) > maxImageSize {
+		return nil, nil, &ServiceError{Message: "image must be less than 2MB"}
+	}
+
+	decision, asset, err := s.imageSvc.ModerateAndPersist(ctx, images.SaveRequest{
+		OwnerUserID: userID,
+		EntityType:  models.ImageEntityBuild,
+		EntityID:    buildID,
+		ImageBytes:  imageData,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !decision.Accepted() {
+		return decision, nil, nil
+	}
+	return decision, asset, nil
+}
+
+// ListGallery returns a build's gallery as its owner sees it.
+func (s *Service) ListGallery(ctx context.Context, userID string, buildID string) ([]models.GalleryImage, error) {
+	build, err := s.store.GetForOwner(ctx, strings.TrimSpace(buildID), userID)
+	if err != nil {
+		return nil, err
+	}
+	if build == nil {
+		return nil, &ServiceError{Message: "build not found"}
+	}
+	return s.store.ListGallery(ctx, build.ID, userID)
+}
+
+// AddGalleryImage moderates an image and appends it to a build gallery.
+// A nil image means moderation rejected the image or could not check it; an
+// image queued for review is added and shows a placeholder until approved.
+func (s *Service) AddGalleryImage(ctx context.Context, userID string, buildID string, params models.AddGalleryImageParams) (*models.ModerationDecision, *models.GalleryImage, error) {
+	if s.imageSvc == nil {
+		return nil, nil, &ServiceError{Message: "image moderation unavailable"}
+	}
+	caption, err := models.NormalizeGalleryCaption(params.Caption)
+	if err != nil {
+		return nil, nil, &ServiceError{Message: err.Error()}
+	}
+
+	build, err := s.store.GetForOwner(ctx, strings.TrimSpace(buildID), userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if build == nil {
+		return nil, nil, &ServiceError{Message: "build not found"}
+	}
 
-		const maxImageSize = 2 * 1024 * 1024
-		if len(params.ImageData) > maxImageSize {
-			return nil, &ServiceError{Message: "image must be less than 2MB"}
+	// Check the limit before moderating so a full gallery doesn't cost a scan
+	gallery, err := s.store.ListGallery(ctx, build.ID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(gallery) >= models.MaxGalleryImages {
+		return nil, nil, &ServiceError{Message: database.ErrGalleryFull.Error()}
+	}
+
+	decision, asset, err := s.persistBuildImage(ctx, userID, build.ID, params.UploadID, params.ImageType, params.ImageData)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !decision.Accepted() {
+		return decision, nil, nil
+	}
+	if asset == nil {
+		return nil, nil, &ServiceError{Message: "failed to persist build image"}
+	}
+
+	image, err := s.store.AddGalleryImage(ctx, build.ID, userID, asset.ID, caption)
+	if err != nil {
+		_ = s.imageSvc.Delete(ctx, asset.ID)
+		if errors.Is(err, database.ErrGalleryFull) {
+			return nil, nil, &ServiceError{Message: err.Error()}
 		}
+		return nil, nil, err
+	}
+	return decision, image, nil
+}
 
-		decision, asset, err = s.imageSvc.ModerateAndPersist(ctx, images.SaveRequest{
-			OwnerUserID: userID,
-			EntityType:  models.ImageEntityBuild,
-			EntityID:    build.ID,
-			ImageBytes:  params.ImageData,
-		})
+// UpdateGalleryImage edits a gallery image caption or makes it the cover.
+func (s *Service) UpdateGalleryImage(ctx context.Context, userID string, buildID string, imageID string, params models.UpdateGalleryImageParams) (*models.GalleryImage, error) {
+	if params.Caption != nil {
+		caption, err := models.NormalizeGalleryCaption(*params.Caption)
 		if err != nil {
-			return nil, err
-		}
-		if decision == nil || decision.Status != models.ImageModerationApproved {
-			return decision, nil
+			return nil, &ServiceError{Message: err.Error()}
 		}
+		params.Caption = &caption
 	}
 
-	if asset == nil {
-		return nil, &ServiceError{Message: "failed to persist build image"}
+	build, err := s.store.GetForOwner(ctx, strings.TrimSpace(buildID), userID)
+	if err != nil {
+		return nil, err
+	}
+	if build == nil {
+		return nil, &ServiceError{Message: "build not found"}
 	}
 
-	previousAssetID, err := s.store.SetImage(ctx, build.ID, userID, asset.ID)
+	image, err := s.store.UpdateGalleryImage(ctx, build.ID, userID, strings.TrimSpace(imageID), params)
+	if errors.Is(err, database.ErrGalleryImageNotFound) || (err == nil && image == nil) {
+		return nil, &ServiceError{Message: "gallery image not found"}
+	}
+	return image, err
+}
+
+// ReorderGallery sets the display order of a build gallery.
+func (s *Service) ReorderGallery(ctx context.Context, userID string, buildID string, imageIDs []string) ([]models.GalleryImage, error) {
+	build, err := s.store.GetForOwner(ctx, strings.TrimSpace(buildID), userID)
 	if err != nil {
-		_ = s.imageSvc.Delete(ctx, asset.ID)
 		return nil, err
 	}
-	if previousAssetID != "" && previousAssetID != asset.ID {
-		_ = s.imageSvc.Delete(ctx, previousAssetID)
+	if build == nil {
+		return nil, &ServiceError{Message: "build not found"}
 	}
 
-	return decision, nil
+	current, err := s.store.ListGallery(ctx, build.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.ValidateGalleryOrder(current, imageIDs); err != nil {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	return s.store.ReorderGallery(ctx, build.ID, userID, imageIDs)
+}
+
+// DeleteGalleryImage removes an image from a build gallery.
+func (s *Service) DeleteGalleryImage(ctx context.Context, userID string, buildID string, imageID string) error {
+	build, err := s.store.GetForOwner(ctx, strings.TrimSpace(buildID), userID)
+	if err != nil {
+		return err
+	}
+	if build == nil {
+		return &ServiceError{Message: "build not found"}
+	}
+
+	orphanAssetID, err := s.store.DeleteGalleryImage(ctx, build.ID, userID, strings.TrimSpace(imageID))
+	if errors.Is(err, database.ErrGalleryImageNotFound) {
+		return &ServiceError{Message: "gallery image not found"}
+	}
+	if err != nil {
+		return err
+	}
+	if orphanAssetID != "" && s.imageSvc != nil {
+		_ = s.imageSvc.Delete(ctx, orphanAssetID)
+	}
+	return nil
+}
+
+// GetGalleryImage opens a gallery image for the build owner.
+func (s *Service) GetGalleryImage(ctx context.Context, buildID string, userID string, imageID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetGalleryImageForOwner(ctx, strings.TrimSpace(buildID), userID, strings.TrimSpace(imageID))
+	if err != nil {
+		return nil, err
+	}
+	return s.openImage(ctx, ref, size)
+}
+
+// GetPublicGalleryImage opens a gallery image of a published build.
+func (s *Service) GetPublicGalleryImage(ctx context.Context, buildID string, imageID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetPublicGalleryImage(ctx, strings.TrimSpace(buildID), strings.TrimSpace(imageID))
+	if err != nil {
+		return nil, err
+	}
+	return s.openImage(ctx, ref, size)
+}
+
+// GetGalleryImageForModeration opens a gallery image for moderation views.
+func (s *Service) GetGalleryImageForModeration(ctx context.Context, buildID string, imageID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetGalleryImageForModeration(ctx, strings.TrimSpace(buildID), strings.TrimSpace(imageID))
+	if err != nil {
+		return nil, err
+	}
+	return s.openImage(ctx, ref, size)
 }
 
 // SetImageForModeration uploads an image for moderator-curated build updates.
@@ -881,7 +1051,7 @@ func (s *Service) SetImageForModeration(ctx context.Context, moderatorUserID str
 	if err != nil {
 		return nil, err
 	}
-	if decision == nil || decision.Status != models.ImageModerationApproved || asset == nil {
+	if !decision.Accepted() || asset == nil {
 		return decision, nil
 	}
 
@@ -897,40 +1067,41 @@ func (s *Service) SetImageForModeration(ctx context.Context, moderatorUserID str
 	return decision, nil
 }
 
-// GetImage retrieves a build image for its owner.
-func (s *Service) GetImage(ctx context.Context, buildID string, userID string) ([]byte, string, error) {
-	imageData, err := s.store.GetImageForOwner(ctx, strings.TrimSpace(buildID), userID)
+// GetImage opens a build image for its owner. Returns nil when the build has no image.
+func (s *Service) GetImage(ctx context.Context, buildID string, userID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetImageForOwner(ctx, strings.TrimSpace(buildID), userID)
 	if err != nil {
-		return nil, "", err
-	}
-	if len(imageData) == 0 {
-		return imageData, "", nil
+		return nil, err
 	}
-	return imageData, http.DetectContentType(imageData), nil
+	return s.openImage(ctx, ref, size)
 }
 
-// GetPublicImage retrieves a published build image for public views.
-func (s *Service) GetPublicImage(ctx context.Context, buildID string) ([]byte, string, error) {
-	imageData, err := s.store.GetPublicImage(ctx, strings.TrimSpace(buildID))
+// GetPublicImage opens a published build image for public views.
+func (s *Service) GetPublicImage(ctx context.Context, buildID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetPublicImage(ctx, strings.TrimSpace(buildID))
 	if err != nil {
-		return nil, "", err
-	}
-	if len(imageData) == 0 {
-		return imageData, "", nil
+		return nil, err
 	}
-	return imageData, http.DetectContentType(imageData), nil
+	return s.openImage(ctx, ref, size)
 }
 
-// GetImageForModeration retrieves a build image for moderation views.
-func (s *Service) GetImageForModeration(ctx context.Context, buildID string) ([]byte, string, error) {
-	imageData, err := s.store.GetImageForModeration(ctx, strings.TrimSpace(buildID))
+// GetImageForModeration opens a build image for moderation views.
+func (s *Service) GetImageForModeration(ctx context.Context, buildID string, size models.ImageSize) (*images.ImageStream, error) {
+	ref, err := s.store.GetImageForModeration(ctx, strings.TrimSpace(buildID))
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	if len(imageData) == 0 {
-		return imageData, "", nil
+	return s.openImage(ctx, ref, size)
+}
+
+func (s *Service) openImage(ctx context.Context, ref *models.ImageRef, size models.ImageSize) (*images.ImageStream, error) {
+	if ref == nil {
+		return nil, nil
 	}
-	return imageData, http.DetectContentType(imageData), nil
+	if s.imageSvc == nil {
+		return nil, &ServiceError{Message: "image storage unavailable"}
+	}
+	return s.imageSvc.OpenRef(ctx, ref, size)
 }
 
 // DeleteImage removes an image from a build.
@@ -983,7 +1154,16 @@ func (s *Service) copyAircraftImageToBuild(ctx context.Context, userID string, a
 		return false, nil
 	}
 
-	imageData, _, err := s.aircraftStore.GetImage(ctx, aircraftID, userID)
+	ref, err := s.aircraftStore.GetImage(ctx, aircraftID, userID)
+	if err != nil {
+		return false, err
+	}
+	stream, err := s.imageSvc.OpenRef(ctx, ref, models.ImageSizeOriginal)
+	if err != nil || stream == nil {
+		return false, err
+	}
+	imageData, err := io.ReadAll(stream.Body)
+	stream.Body.Close()
 	if err != nil {
 		return false, err
 	}
@@ -1000,7 +1180,7 @@ func (s *Service) copyAircraftImageToBuild(ctx context.Context, userID string, a
 	if err != nil {
 		return false, err
 	}
-	if decision == nil || decision.Status != models.ImageModerationApproved || asset == nil {
+	if !decision.Accepted() || asset == nil {
 		return false, nil
 	}
 
@@ -1043,7 +1223,7 @@ func ValidateForPublish(build *models.Build) models.BuildValidationResult {
 		errors = append(errors, models.BuildValidationError{
 			Category: "image",
 			Code:     "missing_required",
-			Message:  "Build image is required before submitting for review",
+			Message:  "Cover image is required before submitting for review",
 		})
 	}
 
@@ -1,12 +1,16 @@
 package builds
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/johnrirwin/flyingforge/internal/database"
 	"github.com/johnrirwin/flyingforge/internal/images"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
@@ -1187,6 +1191,45 @@ func TestSetImage_WithApprovedUpload_PersistsAndCleansPreviousAsset(t *testing.T
 	}
 }
 
+func TestSetImage_QueuedForReview_AttachesPendingAsset(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeBuildStore()
+	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+	svc.imageSvc = &fakeImagePipeline{
+		moderateDecision: &models.ModerationDecision{
+			Status: models.ImageModerationPendingReview,
+			Reason: "Awaiting review",
+			Queued: true,
+		},
+		moderateAsset: &models.ImageAsset{ID: "asset-pending", Status: models.ImageModerationPendingReview},
+	}
+
+	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Image Build"})
+	if err != nil {
+		t.Fatalf("CreateDraft error: %v", err)
+	}
+
+	decision, err := svc.SetImage(ctx, "user-1", models.SetBuildImageParams{
+		BuildID:   build.ID,
+		ImageType: "image/jpeg",
+		ImageData: []byte{0xFF, 0xD8, 0xFF, 0xDB},
+	})
+	if err != nil {
+		t.Fatalf("SetImage error: %v", err)
+	}
+	if decision == nil || !decision.Queued {
+		t.Fatalf("expected queued decision, got %+v", decision)
+	}
+
+	updated, err := store.GetForOwner(ctx, build.ID, "user-1")
+	if err != nil {
+		t.Fatalf("GetForOwner error: %v", err)
+	}
+	if updated == nil || updated.ImageAssetID != "asset-pending" {
+		t.Fatalf("expected pending asset to be attached, got %+v", updated)
+	}
+}
+
 func TestSetImage_NonApprovedModeration_DoesNotPersist(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -1281,6 +1324,8 @@ func TestGetImageAndGetPublicImage_ReturnDetectedContentType(t *testing.T) {
 	ctx := context.Background()
 	store := newFakeBuildStore()
 	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+	imageSvc := &fakeImagePipeline{}
+	svc.imageSvc = imageSvc
 
 	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Image Build"})
 	if err != nil {
@@ -1290,14 +1335,14 @@ func TestGetImageAndGetPublicImage_ReturnDetectedContentType(t *testing.T) {
 		t.Fatalf("SetImage setup error: %v", err)
 	}
 
-	imageData, imageType, err := svc.GetImage(ctx, build.ID, "user-1")
+	ownerImage, err := svc.GetImage(ctx, build.ID, "user-1", models.ImageSizeOriginal)
 	if err != nil {
 		t.Fatalf("GetImage error: %v", err)
 	}
-	if len(imageData) == 0 {
+	if ownerImage == nil || ownerImage.Size == 0 {
 		t.Fatalf("expected image data for owner")
 	}
-	if imageType == "" {
+	if ownerImage.ContentType == "" {
 		t.Fatalf("expected detected content type for owner image")
 	}
 
@@ -1305,16 +1350,176 @@ func TestGetImageAndGetPublicImage_ReturnDetectedContentType(t *testing.T) {
 		t.Fatalf("SetStatus setup error: %v", err)
 	}
 
-	publicData, publicType, err := svc.GetPublicImage(ctx, build.ID)
+	publicImage, err := svc.GetPublicImage(ctx, build.ID, models.ImageSizeCard)
 	if err != nil {
 		t.Fatalf("GetPublicImage error: %v", err)
 	}
-	if len(publicData) == 0 {
+	if publicImage == nil || publicImage.Size == 0 {
 		t.Fatalf("expected public image data")
 	}
-	if publicType == "" {
+	if publicImage.ContentType == "" {
 		t.Fatalf("expected detected content type for public image")
 	}
+	if len(imageSvc.openedSizes) != 2 || imageSvc.openedSizes[1] != models.ImageSizeCard {
+		t.Fatalf("expected requested sizes to reach the image pipeline, got %v", imageSvc.openedSizes)
+	}
+}
+
+func TestAddGalleryImage_FirstImageBecomesCover(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeBuildStore()
+	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+	svc.imageSvc = &fakeImagePipeline{}
+
+	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Gallery Build"})
+	if err != nil {
+		t.Fatalf("CreateDraft error: %v", err)
+	}
+
+	decision, first, err := svc.AddGalleryImage(ctx, "user-1", build.ID, models.AddGalleryImageParams{UploadID: "upload-1", Caption: "  Front  "})
+	if err != nil {
+		t.Fatalf("AddGalleryImage error: %v", err)
+	}
+	if decision == nil || decision.Status != models.ImageModerationApproved {
+		t.Fatalf("expected approved decision, got %+v", decision)
+	}
+	if first == nil || !first.IsCover || first.Caption != "Front" {
+		t.Fatalf("expected first image to be the trimmed-caption cover, got %+v", first)
+	}
+
+	_, second, err := svc.AddGalleryImage(ctx, "user-1", build.ID, models.AddGalleryImageParams{UploadID: "upload-2"})
+	if err != nil {
+		t.Fatalf("AddGalleryImage error: %v", err)
+	}
+	if second == nil || second.IsCover || second.Position != 1 {
+		t.Fatalf("expected second image appended without cover, got %+v", second)
+	}
+
+	isCover := true
+	updated, err := svc.UpdateGalleryImage(ctx, "user-1", build.ID, second.ID, models.UpdateGalleryImageParams{IsCover: &isCover})
+	if err != nil {
+		t.Fatalf("UpdateGalleryImage error: %v", err)
+	}
+	if !updated.IsCover {
+		t.Fatalf("expected second image to become cover")
+	}
+	owned, _ := store.GetForOwner(ctx, build.ID, "user-1")
+	if owned.ImageAssetID != "asset-upload-2" {
+		t.Fatalf("expected build cover to follow gallery, got %s", owned.ImageAssetID)
+	}
+}
+
+func TestAddGalleryImage_RejectsFullGalleryBeforeModeration(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeBuildStore()
+	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+	imageSvc := &fakeImagePipeline{}
+	svc.imageSvc = imageSvc
+
+	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Full Gallery"})
+	if err != nil {
+		t.Fatalf("CreateDraft error: %v", err)
+	}
+	for i := 0; i < models.MaxGalleryImages; i++ {
+		if _, err := store.AddGalleryImage(ctx, build.ID, "user-1", "asset-"+strconvItoa(i), ""); err != nil {
+			t.Fatalf("AddGalleryImage setup error: %v", err)
+		}
+	}
+
+	_, _, err = svc.AddGalleryImage(ctx, "user-1", build.ID, models.AddGalleryImageParams{UploadID: "upload-extra"})
+	if _, ok := err.(*ServiceError); !ok {
+		t.Fatalf("expected service error for full gallery, got %v", err)
+	}
+	if imageSvc.persistUploadID != "" {
+		t.Fatalf("expected upload not to be persisted, got %s", imageSvc.persistUploadID)
+	}
+}
+
+func TestAddGalleryImage_NonApprovedModerationDoesNotAdd(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeBuildStore()
+	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+	svc.imageSvc = &fakeImagePipeline{
+		moderateDecision: &models.ModerationDecision{Status: models.ImageModerationRejected, Reason: "Not allowed"},
+	}
+
+	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Moderated"})
+	if err != nil {
+		t.Fatalf("CreateDraft error: %v", err)
+	}
+
+	decision, image, err := svc.AddGalleryImage(ctx, "user-1", build.ID, models.AddGalleryImageParams{
+		ImageType: "image/jpeg",
+		ImageData: []byte{0xFF, 0xD8, 0xFF, 0xDB},
+	})
+	if err != nil {
+		t.Fatalf("AddGalleryImage error: %v", err)
+	}
+	if decision == nil || decision.Status != models.ImageModerationRejected || image != nil {
+		t.Fatalf("expected rejected decision and no image, got %+v %+v", decision, image)
+	}
+	if len(store.galleries[build.ID]) != 0 {
+		t.Fatalf("expected gallery to stay empty, got %d images", len(store.galleries[build.ID]))
+	}
+}
+
+func TestReorderGallery_RequiresEveryImageOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeBuildStore()
+	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+
+	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Reorder"})
+	if err != nil {
+		t.Fatalf("CreateDraft error: %v", err)
+	}
+	a, _ := store.AddGalleryImage(ctx, build.ID, "user-1", "asset-a", "")
+	b, _ := store.AddGalleryImage(ctx, build.ID, "user-1", "asset-b", "")
+
+	if _, err := svc.ReorderGallery(ctx, "user-1", build.ID, []string{b.ID}); err == nil {
+		t.Fatalf("expected error when an image is missing from the order")
+	}
+	if _, err := svc.ReorderGallery(ctx, "user-1", build.ID, []string{b.ID, b.ID}); err == nil {
+		t.Fatalf("expected error for duplicate image ids")
+	}
+
+	images, err := svc.ReorderGallery(ctx, "user-1", build.ID, []string{b.ID, a.ID})
+	if err != nil {
+		t.Fatalf("ReorderGallery error: %v", err)
+	}
+	if len(images) != 2 || images[0].ID != b.ID || images[1].ID != a.ID {
+		t.Fatalf("unexpected order: %+v", images)
+	}
+}
+
+func TestDeleteGalleryImage_PromotesNextCoverAndDeletesAsset(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeBuildStore()
+	svc := NewServiceWithDeps(store, nil, nil, logging.New(logging.LevelError))
+	imageSvc := &fakeImagePipeline{}
+	svc.imageSvc = imageSvc
+
+	build, err := svc.CreateDraft(ctx, "user-1", models.CreateBuildParams{Title: "Delete"})
+	if err != nil {
+		t.Fatalf("CreateDraft error: %v", err)
+	}
+	cover, _ := store.AddGalleryImage(ctx, build.ID, "user-1", "asset-a", "")
+	_, _ = store.AddGalleryImage(ctx, build.ID, "user-1", "asset-b", "")
+
+	if err := svc.DeleteGalleryImage(ctx, "user-1", build.ID, cover.ID); err != nil {
+		t.Fatalf("DeleteGalleryImage error: %v", err)
+	}
+	if len(imageSvc.deletedIDs) != 1 || imageSvc.deletedIDs[0] != "asset-a" {
+		t.Fatalf("expected removed asset cleanup, deleted=%v", imageSvc.deletedIDs)
+	}
+	owned, _ := store.GetForOwner(ctx, build.ID, "user-1")
+	if owned.ImageAssetID != "asset-b" {
+		t.Fatalf("expected next image to become cover, got %q", owned.ImageAssetID)
+	}
+
+	err = svc.DeleteGalleryImage(ctx, "user-1", build.ID, cover.ID)
+	if svcErr, ok := err.(*ServiceError); !ok || svcErr.Message != "gallery image not found" {
+		t.Fatalf("expected not found service error, got %v", err)
+	}
 }
 
 func TestListForModeration_SeparatesDeclinedAndUnpublishedBuilds(t *testing.T) {
@@ -1387,6 +1592,7 @@ type fakeBuildStore struct {
 	reactions           map[string]map[string]models.BuildReaction
 	revisionByPublished map[string]string
 	publishedByRevision map[string]string
+	galleries           map[string][]models.GalleryImage
 	nextID              int
 }
 
@@ -1397,6 +1603,7 @@ func newFakeBuildStore() *fakeBuildStore {
 		reactions:           map[string]map[string]models.BuildReaction{},
 		revisionByPublished: map[string]string{},
 		publishedByRevision: map[string]string{},
+		galleries:           map[string][]models.GalleryImage{},
 	}
 }
 
@@ -1833,28 +2040,28 @@ func (s *fakeBuildStore) SetImageForModeration(ctx context.Context, id string, i
 	return prev, nil
 }
 
-func (s *fakeBuildStore) GetImageForOwner(ctx context.Context, id string, ownerUserID string) ([]byte, error) {
+func (s *fakeBuildStore) GetImageForOwner(ctx context.Context, id string, ownerUserID string) (*models.ImageRef, error) {
 	build := s.byID[id]
 	if build == nil || build.OwnerUserID != ownerUserID || build.ImageAssetID == "" {
 		return nil, nil
 	}
-	return []byte("image"), nil
+	return &models.ImageRef{AssetID: build.ImageAssetID}, nil
 }
 
-func (s *fakeBuildStore) GetPublicImage(ctx context.Context, id string) ([]byte, error) {
+func (s *fakeBuildStore) GetPublicImage(ctx context.Context, id string) (*models.ImageRef, error) {
 	build := s.byID[id]
 	if build == nil || build.Status != models.BuildStatusPublished || build.ImageAssetID == "" {
 		return nil, nil
 	}
-	return []byte("image"), nil
+	return &models.ImageRef{AssetID: build.ImageAssetID}, nil
 }
 
-func (s *fakeBuildStore) GetImageForModeration(ctx context.Context, id string) ([]byte, error) {
+func (s *fakeBuildStore) GetImageForModeration(ctx context.Context, id string) (*models.ImageRef, error) {
 	build := s.byID[id]
 	if build == nil || build.ImageAssetID == "" {
 		return nil, nil
 	}
-	return []byte("image"), nil
+	return &models.ImageRef{AssetID: build.ImageAssetID}, nil
 }
 
 func (s *fakeBuildStore) DeleteImage(ctx context.Context, id string, ownerUserID string) (string, error) {
@@ -1879,6 +2086,130 @@ func (s *fakeBuildStore) DeleteImageForModeration(ctx context.Context, id string
 	return prev, nil
 }
 
+func (s *fakeBuildStore) ListGallery(ctx context.Context, id string, ownerUserID string) ([]models.GalleryImage, error) {
+	build := s.byID[id]
+	if build == nil || build.OwnerUserID != ownerUserID {
+		return nil, nil
+	}
+	images := make([]models.GalleryImage, len(s.galleries[id]))
+	for i, image := range s.galleries[id] {
+		image.IsCover = image.ImageAssetID == build.ImageAssetID
+		images[i] = image
+	}
+	return images, nil
+}
+
+func (s *fakeBuildStore) AddGalleryImage(ctx context.Context, id string, ownerUserID string, imageAssetID string, caption string) (*models.GalleryImage, error) {
+	build := s.byID[id]
+	if build == nil || build.OwnerUserID != ownerUserID {
+		return nil, fmt.Errorf("build not found")
+	}
+	if len(s.galleries[id]) >= models.MaxGalleryImages {
+		return nil, database.ErrGalleryFull
+	}
+	s.nextID++
+	image := models.GalleryImage{
+		ID:           "image-" + strconvItoa(s.nextID),
+		ImageAssetID: imageAssetID,
+		Caption:      caption,
+		Position:     len(s.galleries[id]),
+	}
+	s.galleries[id] = append(s.galleries[id], image)
+	if build.ImageAssetID == "" {
+		build.ImageAssetID = imageAssetID
+		image.IsCover = true
+	}
+	return &image, nil
+}
+
+func (s *fakeBuildStore) UpdateGalleryImage(ctx context.Context, id string, ownerUserID string, imageID string, params models.UpdateGalleryImageParams) (*models.GalleryImage, error) {
+	build := s.byID[id]
+	if build == nil || build.OwnerUserID != ownerUserID {
+		return nil, fmt.Errorf("build not found")
+	}
+	for i := range s.galleries[id] {
+		image := &s.galleries[id][i]
+		if image.ID != imageID {
+			continue
+		}
+		if params.Caption != nil {
+			image.Caption = *params.Caption
+		}
+		if params.IsCover != nil && *params.IsCover {
+			build.ImageAssetID = image.ImageAssetID
+		}
+		updated := *image
+		updated.IsCover = updated.ImageAssetID == build.ImageAssetID
+		return &updated, nil
+	}
+	return nil, database.ErrGalleryImageNotFound
+}
+
+func (s *fakeBuildStore) ReorderGallery(ctx context.Context, id string, ownerUserID string, imageIDs []string) ([]models.GalleryImage, error) {
+	byID := map[string]models.GalleryImage{}
+	for _, image := range s.galleries[id] {
+		byID[image.ID] = image
+	}
+	reordered := make([]models.GalleryImage, 0, len(imageIDs))
+	for position, imageID := range imageIDs {
+		image := byID[imageID]
+		image.Position = position
+		reordered = append(reordered, image)
+	}
+	s.galleries[id] = reordered
+	return s.ListGallery(ctx, id, ownerUserID)
+}
+
+func (s *fakeBuildStore) DeleteGalleryImage(ctx context.Context, id string, ownerUserID string, imageID string) (string, error) {
+	build := s.byID[id]
+	if build == nil || build.OwnerUserID != ownerUserID {
+		return "", fmt.Errorf("build not found")
+	}
+	for i, image := range s.galleries[id] {
+		if image.ID != imageID {
+			continue
+		}
+		s.galleries[id] = append(s.galleries[id][:i], s.galleries[id][i+1:]...)
+		if build.ImageAssetID == image.ImageAssetID {
+			build.ImageAssetID = ""
+			if len(s.galleries[id]) > 0 {
+				build.ImageAssetID = s.galleries[id][0].ImageAssetID
+			}
+		}
+		return image.ImageAssetID, nil
+	}
+	return "", database.ErrGalleryImageNotFound
+}
+
+func (s *fakeBuildStore) GetGalleryImageForOwner(ctx context.Context, id string, ownerUserID string, imageID string) (*models.ImageRef, error) {
+	build := s.byID[id]
+	if build == nil || build.OwnerUserID != ownerUserID {
+		return nil, nil
+	}
+	return s.galleryImageRef(id, imageID), nil
+}
+
+func (s *fakeBuildStore) GetPublicGalleryImage(ctx context.Context, id string, imageID string) (*models.ImageRef, error) {
+	build := s.byID[id]
+	if build == nil || build.Status != models.BuildStatusPublished {
+		return nil, nil
+	}
+	return s.galleryImageRef(id, imageID), nil
+}
+
+func (s *fakeBuildStore) GetGalleryImageForModeration(ctx context.Context, id string, imageID string) (*models.ImageRef, error) {
+	return s.galleryImageRef(id, imageID), nil
+}
+
+func (s *fakeBuildStore) galleryImageRef(id string, imageID string) *models.ImageRef {
+	for _, image := range s.galleries[id] {
+		if image.ID == imageID {
+			return &models.ImageRef{AssetID: image.ImageAssetID}
+		}
+	}
+	return nil
+}
+
 func (s *fakeBuildStore) Delete(ctx context.Context, id string, ownerUserID string) (bool, error) {
 	build := s.byID[id]
 	if build == nil || build.OwnerUserID != ownerUserID {
@@ -2000,7 +2331,8 @@ type fakeImagePipeline struct {
 	persistEntityType models.ImageEntityType
 	persistEntityID   string
 
-	deletedIDs []string
+	deletedIDs  []string
+	openedSizes []models.ImageSize
 }
 
 func (f *fakeImagePipeline) ModerateAndPersist(ctx context.Context, req images.SaveRequest) (*models.ModerationDecision, *models.ImageAsset, error) {
@@ -2031,11 +2363,25 @@ func (f *fakeImagePipeline) PersistApprovedUpload(ctx context.Context, ownerUser
 		return f.persistAsset, nil
 	}
 	return &models.ImageAsset{
-		ID:         "asset-from-upload",
+		ID:         "asset-" + uploadID,
 		ImageBytes: []byte{0xFF, 0xD8, 0xFF, 0xDB},
 	}, nil
 }
 
+func (f *fakeImagePipeline) OpenRef(ctx context.Context, ref *models.ImageRef, size models.ImageSize) (*images.ImageStream, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	f.openedSizes = append(f.openedSizes, size)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xDB}
+	return &images.ImageStream{
+		Body:        io.NopCloser(bytes.NewReader(data)),
+		Size:        int64(len(data)),
+		ContentType: http.DetectContentType(data),
+		ETag:        `"` + ref.AssetID + `"`,
+	}, nil
+}
+
 func (f *fakeImagePipeline) Delete(ctx context.Context, imageID string) error {
 	f.deletedIDs = append(f.deletedIDs, imageID)
 	return nil
@@ -4,6 +4,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"flag"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -20,6 +21,9 @@ type Config struct {
 	Auth       AuthConfig
 	Crypto     CryptoConfig
 	Moderation ModerationConfig
+	Storage    StorageConfig
+	Orders     OrdersConfig
+	Mail       MailConfig
 }
 
 // ServerConfig holds HTTP/MCP server configuration
@@ -36,6 +40,7 @@ type ServerConfig struct {
 type MCPConfig struct {
 	PublicBaseURL  string
 	AllowedOrigins []string
+	SessionTTL     time.Duration // Idle lifetime of a Streamable HTTP session
 	Auth           MCPAuthConfig
 }
 
@@ -92,6 +97,11 @@ type AuthConfig struct {
 	GoogleClientSecret string
 	GoogleRedirectURI  string
 	EnableAdminTools   bool
+	EmailLinkURL       string        // Frontend page that completes an email sign-in link
+	EmailLinkTTL       time.Duration // Lifetime of an email sign-in link
+	PasskeyRPID        string        // WebAuthn relying party ID, the site's registrable domain
+	PasskeyRPName      string
+	PasskeyOrigins     []string // Origins allowed to complete passkey ceremonies
 }
 
 // CryptoConfig holds encryption configuration for sensitive data at rest
@@ -108,10 +118,43 @@ type ModerationConfig struct {
 	Enabled          bool
 	AWSRegion        string
 	RejectConfidence float64
+	ReviewConfidence float64
 	Timeout          time.Duration
 	PendingUploadTTL time.Duration
 }
 
+// StorageConfig selects where uploaded files (image assets, radio backups) are kept.
+type StorageConfig struct {
+	Backend           string // "local" (images in Postgres, backups on disk) or "s3"
+	S3Bucket          string
+	S3Region          string
+	S3Endpoint        string // Custom endpoint for S3-compatible stores such as MinIO
+	S3AccessKeyID     string // Optional; ambient AWS credentials are used when empty
+	S3SecretAccessKey string
+	S3ForcePathStyle  bool
+	PresignTTL        time.Duration // Lifetime of direct download URLs
+}
+
+// OrdersConfig controls shipment tracking for orders.
+type OrdersConfig struct {
+	PollInterval   time.Duration // How often the polling job runs; 0 disables it
+	RecheckAfter   time.Duration // Minimum time between checks of the same order
+	TrackingAPIURL string        // JSON tracking API for ups/usps/fedex/dhl; orders aren't polled when empty
+	TrackingAPIKey string
+	Timeout        time.Duration // Per-request timeout for the tracking API
+}
+
+// MailConfig holds outgoing mail settings. Mail is written to the log
+// instead of sent when SMTPHost is empty.
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string // Optional; SMTP AUTH is skipped when empty
+	SMTPPassword string
+	From         string
+	Timeout      time.Duration // Per-message timeout for the SMTP conversation
+}
+
 // Load parses flags and environment variables to build configuration
 func Load() *Config {
 	cfg := &Config{}
@@ -183,6 +226,15 @@ func Load() *Config {
 	// Load moderation config from environment
 	cfg.Moderation = loadModerationConfig()
 
+	// Load object storage config from environment
+	cfg.Storage = loadStorageConfig()
+
+	// Load order tracking config from environment
+	cfg.Orders = loadOrdersConfig()
+
+	// Load outgoing mail config from environment
+	cfg.Mail = loadMailConfig()
+
 	return cfg
 }
 
@@ -253,6 +305,13 @@ func loadMCPConfig() MCPConfig {
 		}
 	}
 
+	mcpSessionTTL := time.Hour
+	if raw := strings.TrimSpace(os.Getenv("MCP_SESSION_TTL")); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			mcpSessionTTL = parsed
+		}
+	}
+
 	googleRedirectURI := strings.TrimSpace(os.Getenv("MCP_AUTH_GOOGLE_REDIRECT_URI"))
 	if googleRedirectURI == "" && publicBaseURL != "" {
 		googleRedirectURI = strings.TrimRight(publicBaseURL, "/") + "/oauth/google/callback"
@@ -283,6 +342,7 @@ func loadMCPConfig() MCPConfig {
 	return MCPConfig{
 		PublicBaseURL:  publicBaseURL,
 		AllowedOrigins: allowedOrigins,
+		SessionTTL:     mcpSessionTTL,
 		Auth:           authCfg,
 	}
 }
@@ -302,6 +362,29 @@ func loadAuthConfig() AuthConfig {
 		}
 	}
 
+	emailLinkTTL := 15 * time.Minute
+	if v := os.Getenv("AUTH_EMAIL_LINK_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			emailLinkTTL = d
+		}
+	}
+
+	frontendURL := strings.TrimRight(getEnvOrDefault("AUTH_FRONTEND_URL", "http://localhost:3000"), "/")
+
+	passkeyRPID := strings.TrimSpace(os.Getenv("AUTH_PASSKEY_RP_ID"))
+	if passkeyRPID == "" {
+		if parsed, err := url.Parse(frontendURL); err == nil {
+			passkeyRPID = parsed.Hostname()
+		}
+	}
+
+	passkeyOrigins := []string{frontendURL}
+	if raw := strings.TrimSpace(os.Getenv("AUTH_PASSKEY_ORIGINS")); raw != "" {
+		if parsed := splitAndTrim(raw); len(parsed) > 0 {
+			passkeyOrigins = parsed
+		}
+	}
+
 	return AuthConfig{
 		JWTSecret:          getEnvOrDefault("AUTH_JWT_SECRET", "change-me-in-production"),
 		JWTIssuer:          getEnvOrDefault("AUTH_JWT_ISSUER", "flyingforge"),
@@ -312,6 +395,11 @@ func loadAuthConfig() AuthConfig {
 		GoogleClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
 		GoogleRedirectURI:  getEnvOrDefault("GOOGLE_REDIRECT_URI", "http://localhost:8080/api/auth/google/callback"),
 		EnableAdminTools:   os.Getenv("ENABLE_ADMIN_TOOLS") == "true",
+		EmailLinkURL:       getEnvOrDefault("AUTH_EMAIL_LINK_URL", frontendURL+"/auth/email"),
+		EmailLinkTTL:       emailLinkTTL,
+		PasskeyRPID:        passkeyRPID,
+		PasskeyRPName:      getEnvOrDefault("AUTH_PASSKEY_RP_NAME", "FlyingForge"),
+		PasskeyOrigins:     passkeyOrigins,
 	}
 }
 
@@ -337,6 +425,13 @@ func loadModerationConfig() ModerationConfig {
 		}
 	}
 
+	reviewConfidence := 50.0
+	if v := os.Getenv("MODERATION_REVIEW_CONFIDENCE"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 0 {
+			reviewConfidence = parsed
+		}
+	}
+
 	timeout := 5 * time.Second
 	if v := os.Getenv("MODERATION_TIMEOUT"); v != "" {
 		if parsed, err := time.ParseDuration(v); err == nil && parsed > 0 {
@@ -360,11 +455,95 @@ func loadModerationConfig() ModerationConfig {
 		Enabled:          enabled,
 		AWSRegion:        os.Getenv("AWS_REGION"),
 		RejectConfidence: rejectConfidence,
+		ReviewConfidence: reviewConfidence,
 		Timeout:          timeout,
 		PendingUploadTTL: pendingTTL,
 	}
 }
 
+func loadStorageConfig() StorageConfig {
+	backend := strings.ToLower(strings.TrimSpace(getEnvOrDefault("STORAGE_BACKEND", "local")))
+
+	presignTTL := 15 * time.Minute
+	if v := os.Getenv("STORAGE_PRESIGN_TTL"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil && parsed > 0 {
+			presignTTL = parsed
+		}
+	}
+
+	forcePathStyle := false
+	if v := strings.ToLower(strings.TrimSpace(os.Getenv("S3_FORCE_PATH_STYLE"))); v == "true" || v == "1" {
+		forcePathStyle = true
+	}
+
+	return StorageConfig{
+		Backend:           backend,
+		S3Bucket:          strings.TrimSpace(os.Getenv("S3_BUCKET")),
+		S3Region:          strings.TrimSpace(getEnvOrDefault("S3_REGION", os.Getenv("AWS_REGION"))),
+		S3Endpoint:        strings.TrimSpace(os.Getenv("S3_ENDPOINT")),
+		S3AccessKeyID:     strings.TrimSpace(os.Getenv("S3_ACCESS_KEY_ID")),
+		S3SecretAccessKey: strings.TrimSpace(os.Getenv("S3_SECRET_ACCESS_KEY")),
+		S3ForcePathStyle:  forcePathStyle,
+		PresignTTL:        presignTTL,
+	}
+}
+
+func loadOrdersConfig() OrdersConfig {
+	pollInterval := 15 * time.Minute
+	if v := os.Getenv("ORDER_POLL_INTERVAL"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil && parsed >= 0 {
+			pollInterval = parsed
+		}
+	}
+
+	recheckAfter := 2 * time.Hour
+	if v := os.Getenv("ORDER_RECHECK_AFTER"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil && parsed > 0 {
+			recheckAfter = parsed
+		}
+	}
+
+	timeout := 10 * time.Second
+	if v := os.Getenv("ORDER_TRACKING_TIMEOUT"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil && parsed > 0 {
+			timeout = parsed
+		}
+	}
+
+	return OrdersConfig{
+		PollInterval:   pollInterval,
+		RecheckAfter:   recheckAfter,
+		TrackingAPIURL: strings.TrimRight(strings.TrimSpace(os.Getenv("ORDER_TRACKING_API_URL")), "/"),
+		TrackingAPIKey: strings.TrimSpace(os.Getenv("ORDER_TRACKING_API_KEY")),
+		Timeout:        timeout,
+	}
+}
+
+func loadMailConfig() MailConfig {
+	port := 587
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			port = parsed
+		}
+	}
+
+	timeout := 10 * time.Second
+	if v := os.Getenv("SMTP_TIMEOUT"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil && parsed > 0 {
+			timeout = parsed
+		}
+	}
+
+	return MailConfig{
+		SMTPHost:     strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		SMTPPort:     port,
+		SMTPUsername: strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		From:         strings.TrimSpace(getEnvOrDefault("MAIL_FROM", "FlyingForge <no-reply@localhost>")),
+		Timeout:      timeout,
+	}
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -272,3 +272,101 @@ func generateTestECDSAPrivateKeyPEM(t *testing.T) string {
 		Bytes: der,
 	}))
 }
+
+func TestLoadStorageConfig(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", " S3 ")
+	t.Setenv("S3_BUCKET", "flyingforge-uploads")
+	t.Setenv("S3_REGION", "")
+	t.Setenv("AWS_REGION", "us-west-2")
+	t.Setenv("S3_FORCE_PATH_STYLE", "true")
+	t.Setenv("STORAGE_PRESIGN_TTL", "5m")
+
+	cfg := loadStorageConfig()
+	if cfg.Backend != "s3" || cfg.S3Bucket != "flyingforge-uploads" {
+		t.Fatalf("backend/bucket = %q/%q, want s3/flyingforge-uploads", cfg.Backend, cfg.S3Bucket)
+	}
+	if cfg.S3Region != "us-west-2" {
+		t.Errorf("S3Region = %q, want AWS_REGION fallback", cfg.S3Region)
+	}
+	if !cfg.S3ForcePathStyle || cfg.PresignTTL != 5*time.Minute {
+		t.Errorf("path style/presign TTL = %v/%v, want true/5m", cfg.S3ForcePathStyle, cfg.PresignTTL)
+	}
+
+	t.Setenv("STORAGE_BACKEND", "")
+	t.Setenv("STORAGE_PRESIGN_TTL", "-1s")
+	cfg = loadStorageConfig()
+	if cfg.Backend != "local" || cfg.PresignTTL != 15*time.Minute {
+		t.Errorf("defaults = %q/%v, want local/15m", cfg.Backend, cfg.PresignTTL)
+	}
+}
+
+func TestLoadOrdersConfig(t *testing.T) {
+	t.Setenv("ORDER_POLL_INTERVAL", "0")
+	t.Setenv("ORDER_RECHECK_AFTER", "30m")
+	t.Setenv("ORDER_TRACKING_API_URL", " https://tracking.example.com/v1/ ")
+	t.Setenv("ORDER_TRACKING_TIMEOUT", "")
+
+	cfg := loadOrdersConfig()
+	if cfg.PollInterval != 0 || cfg.RecheckAfter != 30*time.Minute {
+		t.Errorf("poll/recheck = %v/%v, want 0/30m", cfg.PollInterval, cfg.RecheckAfter)
+	}
+	if cfg.TrackingAPIURL != "https://tracking.example.com/v1" {
+		t.Errorf("TrackingAPIURL = %q, want trailing slash trimmed", cfg.TrackingAPIURL)
+	}
+	if cfg.Timeout != 10*time.Second {
+		t.Errorf("Timeout = %v, want 10s default", cfg.Timeout)
+	}
+
+	t.Setenv("ORDER_POLL_INTERVAL", "-5m")
+	t.Setenv("ORDER_RECHECK_AFTER", "0")
+	cfg = loadOrdersConfig()
+	if cfg.PollInterval != 15*time.Minute || cfg.RecheckAfter != 2*time.Hour {
+		t.Errorf("defaults = %v/%v, want 15m/2h", cfg.PollInterval, cfg.RecheckAfter)
+	}
+}
+
+func TestLoadAuthConfig_PasswordlessDefaults(t *testing.T) {
+	t.Setenv("AUTH_FRONTEND_URL", "https://app.flyingforge.example/")
+	t.Setenv("AUTH_EMAIL_LINK_URL", "")
+	t.Setenv("AUTH_EMAIL_LINK_TTL", "-1m")
+	t.Setenv("AUTH_PASSKEY_RP_ID", "")
+	t.Setenv("AUTH_PASSKEY_ORIGINS", " , ")
+
+	cfg := loadAuthConfig()
+	if cfg.EmailLinkURL != "https://app.flyingforge.example/auth/email" || cfg.EmailLinkTTL != 15*time.Minute {
+		t.Errorf("email link = %q/%v, want frontend /auth/email and 15m", cfg.EmailLinkURL, cfg.EmailLinkTTL)
+	}
+	if cfg.PasskeyRPID != "app.flyingforge.example" {
+		t.Errorf("PasskeyRPID = %q, want frontend host", cfg.PasskeyRPID)
+	}
+	if len(cfg.PasskeyOrigins) != 1 || cfg.PasskeyOrigins[0] != "https://app.flyingforge.example" {
+		t.Errorf("PasskeyOrigins = %v, want frontend origin", cfg.PasskeyOrigins)
+	}
+
+	t.Setenv("AUTH_PASSKEY_RP_ID", "flyingforge.example")
+	t.Setenv("AUTH_PASSKEY_ORIGINS", "https://flyingforge.example, https://app.flyingforge.example")
+	cfg = loadAuthConfig()
+	if cfg.PasskeyRPID != "flyingforge.example" || len(cfg.PasskeyOrigins) != 2 {
+		t.Errorf("overrides = %q/%v, want configured RP ID and two origins", cfg.PasskeyRPID, cfg.PasskeyOrigins)
+	}
+}
+
+func TestLoadMailConfig(t *testing.T) {
+	t.Setenv("SMTP_HOST", " smtp.example.com ")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("SMTP_TIMEOUT", "")
+	t.Setenv("MAIL_FROM", "")
+
+	cfg := loadMailConfig()
+	if cfg.SMTPHost != "smtp.example.com" || cfg.SMTPPort != 2525 {
+		t.Errorf("host/port = %q/%d, want smtp.example.com/2525", cfg.SMTPHost, cfg.SMTPPort)
+	}
+	if cfg.Timeout != 10*time.Second || cfg.From == "" {
+		t.Errorf("timeout/from = %v/%q, want 10s and a default sender", cfg.Timeout, cfg.From)
+	}
+
+	t.Setenv("SMTP_PORT", "zero")
+	if cfg = loadMailConfig(); cfg.SMTPPort != 587 {
+		t.Errorf("SMTPPort = %d, want 587 default", cfg.SMTPPort)
+	}
+}
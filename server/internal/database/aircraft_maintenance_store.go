@@ -0,0 +1,313 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ErrMaintenanceEventNotFound is returned when an event doesn't exist on the aircraft.
+var ErrMaintenanceEventNotFound = errors.New("maintenance event not found")
+
+const maintenanceEventColumns = `id, aircraft_id, event_type, title, description, occurred_at, repair_cost, created_at, updated_at`
+
+const maintenanceComponentColumns = `id, event_id, category, action, old_inventory_item_id, new_inventory_item_id,
+	unit_id, unit_condition, replaced_cost, notes, created_at`
+
+// CreateMaintenanceEvent logs an event and the components it affected.
+func (s *AircraftStore) CreateMaintenanceEvent(ctx context.Context, aircraftID string, userID string, params models.CreateMaintenanceEventParams, components []models.MaintenanceEventComponent) (*models.MaintenanceEvent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start maintenance transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lockOwnedTx(ctx, tx, aircraftID, userID); err != nil {
+		return nil, err
+	}
+
+	occurredAt := time.Now()
+	if params.OccurredAt != nil && !params.OccurredAt.IsZero() {
+		occurredAt = *params.OccurredAt
+	}
+
+	event, err := scanMaintenanceEvent(tx.QueryRowContext(ctx, `
+		INSERT INTO maintenance_events (aircraft_id, event_type, title, description, occurred_at, repair_cost)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+maintenanceEventColumns,
+		aircraftID, string(params.Type), params.Title, nullString(params.Description), occurredAt, params.RepairCost,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create maintenance event: %w", err)
+	}
+
+	event.Components = make([]models.MaintenanceEventComponent, 0, len(components))
+	for _, component := range components {
+		created, err := insertMaintenanceComponent(ctx, tx, event.ID, component)
+		if err != nil {
+			return nil, err
+		}
+		event.Components = append(event.Components, *created)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit maintenance event: %w", err)
+	}
+	return event, nil
+}
+
+// ListMaintenanceEvents returns an aircraft's maintenance log, newest first. Callers check ownership.
+func (s *AircraftStore) ListMaintenanceEvents(ctx context.Context, aircraftID string) ([]models.MaintenanceEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+maintenanceEventColumns+`
+		FROM maintenance_events
+		WHERE aircraft_id = $1
+		ORDER BY occurred_at DESC, created_at DESC
+	`, aircraftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]models.MaintenanceEvent, 0)
+	for rows.Next() {
+		event, err := scanMaintenanceEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance event: %w", err)
+		}
+		events = append(events, *event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate maintenance events: %w", err)
+	}
+
+	if err := attachMaintenanceComponents(ctx, s.db, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetMaintenanceEvent returns one event from an aircraft's log. Callers check ownership.
+func (s *AircraftStore) GetMaintenanceEvent(ctx context.Context, aircraftID string, eventID string) (*models.MaintenanceEvent, error) {
+	event, err := scanMaintenanceEvent(s.db.QueryRowContext(ctx, `
+		SELECT `+maintenanceEventColumns+`
+		FROM maintenance_events
+		WHERE id::text = $1 AND aircraft_id = $2
+	`, eventID, aircraftID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance event: %w", err)
+	}
+
+	events := []models.MaintenanceEvent{*event}
+	if err := attachMaintenanceComponents(ctx, s.db, events); err != nil {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+// UpdateMaintenanceEvent edits an event's details; its component records are left alone.
+func (s *AircraftStore) UpdateMaintenanceEvent(ctx context.Context, aircraftID string, userID string, params models.UpdateMaintenanceEventParams) (*models.MaintenanceEvent, error) {
+	sets := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Type != nil {
+		sets = append(sets, fmt.Sprintf("event_type = $%d", argIndex))
+		args = append(args, string(*params.Type))
+		argIndex++
+	}
+	if params.Title != nil {
+		sets = append(sets, fmt.Sprintf("title = $%d", argIndex))
+		args = append(args, *params.Title)
+		argIndex++
+	}
+	if params.Description != nil {
+		sets = append(sets, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, nullString(*params.Description))
+		argIndex++
+	}
+	if params.OccurredAt != nil {
+		sets = append(sets, fmt.Sprintf("occurred_at = $%d", argIndex))
+		args = append(args, *params.OccurredAt)
+		argIndex++
+	}
+	if params.RepairCost != nil {
+		sets = append(sets, fmt.Sprintf("repair_cost = $%d", argIndex))
+		args = append(args, *params.RepairCost)
+		argIndex++
+	}
+
+	if len(sets) > 0 {
+		sets = append(sets, "updated_at = NOW()")
+		query := fmt.Sprintf(`
+			UPDATE maintenance_events e
+			SET %s
+			FROM aircraft a
+			WHERE e.id::text = $%d AND e.aircraft_id = $%d AND a.id = e.aircraft_id AND a.user_id = $%d
+		`, strings.Join(sets, ", "), argIndex, argIndex+1, argIndex+2)
+		args = append(args, params.ID, aircraftID, userID)
+
+		result, err := s.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update maintenance event: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return nil, ErrMaintenanceEventNotFound
+		}
+	}
+
+	event, err := s.GetMaintenanceEvent(ctx, aircraftID, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrMaintenanceEventNotFound
+	}
+	return event, nil
+}
+
+// DeleteMaintenanceEvent removes an event and its component records.
+func (s *AircraftStore) DeleteMaintenanceEvent(ctx context.Context, aircraftID string, userID string, eventID string) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM maintenance_events e
+		USING aircraft a
+		WHERE e.id::text = $1 AND e.aircraft_id = $2 AND a.id = e.aircraft_id AND a.user_id = $3
+	`, eventID, aircraftID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance event: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrMaintenanceEventNotFound
+	}
+	return nil
+}
+
+// AddMaintenanceEventComponent records a component change on an existing event.
+func (s *AircraftStore) AddMaintenanceEventComponent(ctx context.Context, eventID string, component models.MaintenanceEventComponent) (*models.MaintenanceEventComponent, error) {
+	created, err := insertMaintenanceComponent(ctx, s.db, eventID, component)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE maintenance_events SET updated_at = NOW() WHERE id = $1`, eventID); err != nil {
+		return nil, fmt.Errorf("failed to touch maintenance event: %w", err)
+	}
+	return created, nil
+}
+
+func insertMaintenanceComponent(ctx context.Context, db sqlQueryer, eventID string, component models.MaintenanceEventComponent) (*models.MaintenanceEventComponent, error) {
+	created, err := scanMaintenanceComponent(db.QueryRowContext(ctx, `
+		INSERT INTO maintenance_event_components (
+			event_id, category, action, old_inventory_item_id, new_inventory_item_id,
+			unit_id, unit_condition, replaced_cost, notes
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+maintenanceComponentColumns,
+		eventID,
+		string(component.Category),
+		string(component.Action),
+		nullString(component.OldInventoryItemID),
+		nullString(component.NewInventoryItemID),
+		nullString(component.UnitID),
+		nullString(string(component.UnitCondition)),
+		component.ReplacedCost,
+		nullString(component.Notes),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record maintenance component: %w", err)
+	}
+	return created, nil
+}
+
+func attachMaintenanceComponents(ctx context.Context, db sqlQueryer, events []models.MaintenanceEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.MaintenanceEvent, len(events))
+	ids := make([]string, 0, len(events))
+	for i := range events {
+		events[i].Components = []models.MaintenanceEventComponent{}
+		byID[events[i].ID] = &events[i]
+		ids = append(ids, events[i].ID)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+maintenanceComponentColumns+`
+		FROM maintenance_event_components
+		WHERE event_id::text = ANY($1)
+		ORDER BY created_at, id
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list maintenance components: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		component, err := scanMaintenanceComponent(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan maintenance component: %w", err)
+		}
+		if event := byID[component.EventID]; event != nil {
+			event.Components = append(event.Components, *component)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate maintenance components: %w", err)
+	}
+	return nil
+}
+
+func scanMaintenanceEvent(row interface{ Scan(...any) error }) (*models.MaintenanceEvent, error) {
+	var event models.MaintenanceEvent
+	var eventType string
+	var description sql.NullString
+	var repairCost sql.NullFloat64
+	if err := row.Scan(
+		&event.ID, &event.AircraftID, &eventType, &event.Title, &description,
+		&event.OccurredAt, &repairCost, &event.CreatedAt, &event.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	event.Type = models.MaintenanceEventType(eventType)
+	event.Description = description.String
+	if repairCost.Valid {
+		event.RepairCost = &repairCost.Float64
+	}
+	return &event, nil
+}
+
+func scanMaintenanceComponent(row interface{ Scan(...any) error }) (*models.MaintenanceEventComponent, error) {
+	var component models.MaintenanceEventComponent
+	var category, action string
+	var oldItemID, newItemID, unitID, unitCondition, notes sql.NullString
+	var replacedCost sql.NullFloat64
+	if err := row.Scan(
+		&component.ID, &component.EventID, &category, &action, &oldItemID, &newItemID,
+		&unitID, &unitCondition, &replacedCost, &notes, &component.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	component.Category = models.ComponentCategory(category)
+	component.Action = models.MaintenanceComponentAction(action)
+	component.OldInventoryItemID = oldItemID.String
+	component.NewInventoryItemID = newItemID.String
+	component.UnitID = unitID.String
+	component.UnitCondition = models.InventoryUnitCondition(unitCondition.String)
+	component.Notes = notes.String
+	if replacedCost.Valid {
+		component.ReplacedCost = &replacedCost.Float64
+	}
+	return &component, nil
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/johnrirwin/flyingforge/internal/crypto"
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
 
+// ErrBatteryNotAssigned is returned when unassigning a pack that isn't assigned to the aircraft
+var ErrBatteryNotAssigned = errors.New("battery not assigned to aircraft")
+
 // AircraftStore handles aircraft database operations
 type AircraftStore struct {
 	db        *DB
@@ -456,6 +460,106 @@ func (s *AircraftStore) RemoveComponent(ctx context.Context, aircraftID string,
 	return nil
 }
 
+// AssignBattery assigns a battery pack to an aircraft, updating notes if already assigned
+func (s *AircraftStore) AssignBattery(ctx context.Context, aircraftID string, batteryID string, notes string) (*models.AircraftBattery, error) {
+	query := `
+		INSERT INTO aircraft_batteries (aircraft_id, battery_id, notes)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (aircraft_id, battery_id) DO UPDATE SET
+			notes = EXCLUDED.notes
+		RETURNING id, aircraft_id, battery_id, notes, created_at
+	`
+
+	ab := &models.AircraftBattery{}
+	var scanNotes sql.NullString
+	err := s.db.QueryRowContext(ctx, query, aircraftID, batteryID, nullString(notes)).Scan(
+		&ab.ID, &ab.AircraftID, &ab.BatteryID, &scanNotes, &ab.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign battery: %w", err)
+	}
+	ab.Notes = scanNotes.String
+
+	return ab, nil
+}
+
+// UnassignBattery removes a battery pack from an aircraft
+func (s *AircraftStore) UnassignBattery(ctx context.Context, aircraftID string, batteryID string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM aircraft_batteries WHERE aircraft_id = $1 AND battery_id = $2`,
+		aircraftID, batteryID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unassign battery: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrBatteryNotAssigned
+	}
+	return nil
+}
+
+// ListBatteries retrieves all battery packs assigned to an aircraft
+func (s *AircraftStore) ListBatteries(ctx context.Context, aircraftID string) ([]models.AircraftBattery, error) {
+	query := `
+		SELECT ab.id, ab.aircraft_id, ab.battery_id, ab.notes, ab.created_at,
+		       b.id, b.user_id, b.battery_code, b.name, b.chemistry, b.cells, b.capacity_mah,
+		       b.c_rating, b.connector, b.weight_grams, b.brand, b.model, b.created_at, b.updated_at
+		FROM aircraft_batteries ab
+		JOIN batteries b ON b.id = ab.battery_id
+		WHERE ab.aircraft_id = $1
+		ORDER BY b.battery_code
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, aircraftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aircraft batteries: %w", err)
+	}
+	defer rows.Close()
+
+	batteries := []models.AircraftBattery{}
+	for rows.Next() {
+		var ab models.AircraftBattery
+		b := &models.Battery{}
+		var (
+			scanNotes                                     sql.NullString
+			scanName, scanConnector, scanBrand, scanModel sql.NullString
+			scanCRating, scanWeightGrams                  sql.NullInt32
+		)
+
+		if err := rows.Scan(
+			&ab.ID, &ab.AircraftID, &ab.BatteryID, &scanNotes, &ab.CreatedAt,
+			&b.ID, &b.UserID, &b.BatteryCode, &scanName, &b.Chemistry, &b.Cells, &b.CapacityMah,
+			&scanCRating, &scanConnector, &scanWeightGrams, &scanBrand, &scanModel, &b.CreatedAt, &b.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan aircraft battery: %w", err)
+		}
+
+		ab.Notes = scanNotes.String
+		b.Name = scanName.String
+		b.Connector = scanConnector.String
+		b.Brand = scanBrand.String
+		b.Model = scanModel.String
+		if scanCRating.Valid {
+			v := int(scanCRating.Int32)
+			b.CRating = &v
+		}
+		if scanWeightGrams.Valid {
+			v := int(scanWeightGrams.Int32)
+			b.WeightGrams = &v
+		}
+		ab.Battery = b
+
+		batteries = append(batteries, ab)
+	}
+
+	return batteries, nil
+}
+
 // SetReceiverSettings sets or updates receiver settings for an aircraft.
 // SECURITY: Sensitive fields (BindPhrase, BindingPhrase, UID, WifiPassword) are encrypted before storage.
 func (s *AircraftStore) SetReceiverSettings(ctx context.Context, aircraftID string, settings json.RawMessage) (*models.AircraftReceiverSettings, error) {
@@ -522,6 +626,57 @@ func (s *AircraftStore) GetReceiverSettings(ctx context.Context, aircraftID stri
 	return rx, nil
 }
 
+// ListWithReceiverSettings lists all of a user's aircraft with their receiver settings attached.
+// SECURITY: Encrypted fields are left encrypted; callers should only read plaintext fields such as model match.
+func (s *AircraftStore) ListWithReceiverSettings(ctx context.Context, userID string) ([]models.Aircraft, error) {
+	query := `
+		SELECT a.id, a.name, a.nickname, a.type, a.created_at, a.updated_at,
+		       rs.id, rs.settings_json, rs.created_at, rs.updated_at
+		FROM aircraft a
+		LEFT JOIN aircraft_receiver_settings rs ON rs.aircraft_id = a.id
+		WHERE a.user_id = $1
+		ORDER BY a.created_at ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aircraft receiver settings: %w", err)
+	}
+	defer rows.Close()
+
+	aircraft := []models.Aircraft{}
+	for rows.Next() {
+		var a models.Aircraft
+		var scanNickname, scanType, rxID sql.NullString
+		var rxSettings []byte
+		var rxCreatedAt, rxUpdatedAt sql.NullTime
+
+		if err := rows.Scan(
+			&a.ID, &a.Name, &scanNickname, &scanType, &a.CreatedAt, &a.UpdatedAt,
+			&rxID, &rxSettings, &rxCreatedAt, &rxUpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan aircraft receiver settings: %w", err)
+		}
+
+		a.UserID = userID
+		a.Nickname = scanNickname.String
+		a.Type = models.AircraftType(scanType.String)
+		if rxID.Valid {
+			a.ReceiverSettings = &models.AircraftReceiverSettings{
+				ID:         rxID.String,
+				AircraftID: a.ID,
+				Settings:   rxSettings,
+				CreatedAt:  rxCreatedAt.Time,
+				UpdatedAt:  rxUpdatedAt.Time,
+			}
+		}
+
+		aircraft = append(aircraft, a)
+	}
+
+	return aircraft, rows.Err()
+}
+
 // encryptReceiverSettings encrypts sensitive fields in receiver settings JSON.
 // Fields encrypted: BindPhrase, BindingPhrase, UID, WifiPassword
 func (s *AircraftStore) encryptReceiverSettings(settings json.RawMessage) (json.RawMessage, error) {
@@ -597,10 +752,23 @@ func (s *AircraftStore) GetDetails(ctx context.Context, id string, userID string
 		return nil, err
 	}
 
+	batteries, err := s.ListBatteries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	gallery, err := aircraftGallery.list(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	setGalleryImageURLs(gallery, fmt.Sprintf("/api/aircraft/%s/gallery", id))
+	aircraft.Gallery = gallery
+
 	return &models.AircraftDetailsResponse{
 		Aircraft:         *aircraft,
 		Components:       components,
 		ReceiverSettings: receiverSettings,
+		Batteries:        batteries,
 	}, nil
 }
 
@@ -632,61 +800,55 @@ func (s *AircraftStore) SetImage(ctx context.Context, id string, userID string,
 		return "", fmt.Errorf("aircraft not found")
 	}
 
-	if previousAssetID.Valid {
-		return previousAssetID.String, nil
+	if err := aircraftGallery.replaceCover(ctx, s.db, id, previousAssetID.String, imageAssetID); err != nil {
+		return "", err
 	}
-	return "", nil
+	return orphanedAssetID(ctx, s.db, previousAssetID.String)
 }
 
-// GetImage retrieves the image data for an aircraft
-func (s *AircraftStore) GetImage(ctx context.Context, id string, userID string) ([]byte, string, error) {
+// GetImage locates the image for an aircraft. Returns nil if it has none.
+func (s *AircraftStore) GetImage(ctx context.Context, id string, userID string) (*models.ImageRef, error) {
 	query := `
-		SELECT COALESCE(ia.image_bytes, a.image_data), a.image_type
+		SELECT ia.id, a.image_data, a.image_type
 		FROM aircraft a
-		LEFT JOIN image_assets ia ON ia.id = a.image_asset_id AND ia.status = 'APPROVED'
+		LEFT JOIN image_assets ia ON ia.id = a.image_asset_id AND ia.status IN ('APPROVED', 'PENDING_REVIEW')
 		WHERE a.id = $1
 		  AND (a.user_id = $2 OR a.user_id IS NULL)
 		  AND ((a.image_asset_id IS NOT NULL AND ia.id IS NOT NULL) OR a.image_data IS NOT NULL)
 	`
-	var imageData []byte
-	var imageType sql.NullString
-
-	err := s.db.QueryRowContext(ctx, query, id, userID).Scan(&imageData, &imageType)
+	ref, err := scanImageRef(s.db.QueryRowContext(ctx, query, id, userID))
 	if err == sql.ErrNoRows {
-		return nil, "", nil
+		return nil, nil
 	}
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get aircraft image: %w", err)
+		return nil, fmt.Errorf("failed to get aircraft image: %w", err)
 	}
 
-	return imageData, imageType.String, nil
+	return ref, nil
 }
 
-// GetPublicImage retrieves image data for an aircraft if the owner allows it
+// GetPublicImage locates the image for an aircraft if the owner allows it
 // This is used for public pilot profiles - checks owner's social settings
-func (s *AircraftStore) GetPublicImage(ctx context.Context, aircraftID string) ([]byte, string, error) {
+func (s *AircraftStore) GetPublicImage(ctx context.Context, aircraftID string) (*models.ImageRef, error) {
 	query := `
-		SELECT COALESCE(ia.image_bytes, a.image_data), a.image_type 
+		SELECT ia.id, a.image_data, a.image_type
 		FROM aircraft a
-		LEFT JOIN image_assets ia ON ia.id = a.image_asset_id AND ia.status = 'APPROVED'
+		LEFT JOIN image_assets ia ON ia.id = a.image_asset_id AND ia.status IN ('APPROVED', 'PENDING_REVIEW')
 		JOIN users u ON a.user_id = u.id
 		WHERE a.id = $1 
 		  AND ((a.image_asset_id IS NOT NULL AND ia.id IS NOT NULL) OR a.image_data IS NOT NULL)
 		  AND u.show_aircraft = true
 		  AND u.profile_visibility = 'public'
 	`
-	var imageData []byte
-	var imageType sql.NullString
-
-	err := s.db.QueryRowContext(ctx, query, aircraftID).Scan(&imageData, &imageType)
+	ref, err := scanImageRef(s.db.QueryRowContext(ctx, query, aircraftID))
 	if err == sql.ErrNoRows {
-		return nil, "", nil
+		return nil, nil
 	}
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get public aircraft image: %w", err)
+		return nil, fmt.Errorf("failed to get public aircraft image: %w", err)
 	}
 
-	return imageData, imageType.String, nil
+	return ref, nil
 }
 
 // DeleteImage removes the image from an aircraft and returns the previous asset ID.
@@ -701,8 +863,7 @@ func (s *AircraftStore) DeleteImage(ctx context.Context, id string, userID strin
 
 	query := `
 		UPDATE aircraft
-		SET image_asset_id = NULL,
-		    image_data = NULL,
+		SET image_data = NULL,
 		    image_type = NULL,
 		    updated_at = NOW()
 		WHERE id = $1 AND user_id = $2
@@ -715,10 +876,153 @@ func (s *AircraftStore) DeleteImage(ctx context.Context, id string, userID strin
 	if rows == 0 {
 		return "", fmt.Errorf("aircraft not found")
 	}
-	if previousAssetID.Valid {
-		return previousAssetID.String, nil
+
+	// The next gallery image, if any, becomes the cover
+	if err := aircraftGallery.removeCover(ctx, s.db, id, previousAssetID.String); err != nil {
+		return "", fmt.Errorf("failed to delete aircraft image: %w", err)
+	}
+	return orphanedAssetID(ctx, s.db, previousAssetID.String)
+}
+
+// ListGallery returns an aircraft's gallery in display order. Callers check ownership.
+func (s *AircraftStore) ListGallery(ctx context.Context, id string) ([]models.GalleryImage, error) {
+	images, err := aircraftGallery.list(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	setGalleryImageURLs(images, fmt.Sprintf("/api/aircraft/%s/gallery", id))
+	return images, nil
+}
+
+// AddGalleryImage appends an approved image asset to an aircraft gallery.
+func (s *AircraftStore) AddGalleryImage(ctx context.Context, id string, userID string, imageAssetID string, caption string) (*models.GalleryImage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lockOwnedTx(ctx, tx, id, userID); err != nil {
+		return nil, err
+	}
+	image, err := aircraftGallery.add(ctx, tx, id, imageAssetID, caption)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit gallery image: %w", err)
+	}
+
+	image.ImageURL = galleryImageURL(fmt.Sprintf("/api/aircraft/%s/gallery", id), image.ID)
+	return image, nil
+}
+
+// UpdateGalleryImage edits a gallery image caption or makes it the aircraft cover.
+func (s *AircraftStore) UpdateGalleryImage(ctx context.Context, id string, userID string, imageID string, params models.UpdateGalleryImageParams) (*models.GalleryImage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lockOwnedTx(ctx, tx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := aircraftGallery.update(ctx, tx, id, imageID, params); err != nil {
+		return nil, err
+	}
+	image, err := aircraftGallery.get(ctx, tx, id, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit gallery update: %w", err)
 	}
-	return "", nil
+
+	if image != nil {
+		image.ImageURL = galleryImageURL(fmt.Sprintf("/api/aircraft/%s/gallery", id), image.ID)
+	}
+	return image, nil
+}
+
+// ReorderGallery sets the gallery order; imageIDs must list every image once.
+func (s *AircraftStore) ReorderGallery(ctx context.Context, id string, userID string, imageIDs []string) ([]models.GalleryImage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lockOwnedTx(ctx, tx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := aircraftGallery.reorder(ctx, tx, id, imageIDs); err != nil {
+		return nil, err
+	}
+	images, err := aircraftGallery.list(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit gallery reorder: %w", err)
+	}
+
+	setGalleryImageURLs(images, fmt.Sprintf("/api/aircraft/%s/gallery", id))
+	return images, nil
+}
+
+// DeleteGalleryImage removes an image from an aircraft gallery. Returns the
+// image asset ID when nothing else still uses it.
+func (s *AircraftStore) DeleteGalleryImage(ctx context.Context, id string, userID string, imageID string) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lockOwnedTx(ctx, tx, id, userID); err != nil {
+		return "", err
+	}
+	imageAssetID, err := aircraftGallery.remove(ctx, tx, id, imageID)
+	if err != nil {
+		return "", err
+	}
+	orphanID, err := orphanedAssetID(ctx, tx, imageAssetID)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit gallery delete: %w", err)
+	}
+	return orphanID, nil
+}
+
+// GetGalleryImage locates a gallery image for an aircraft the user can see.
+func (s *AircraftStore) GetGalleryImage(ctx context.Context, id string, userID string, imageID string) (*models.ImageRef, error) {
+	var visible bool
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM aircraft WHERE id = $1 AND (user_id = $2 OR user_id IS NULL))`,
+		id,
+		userID,
+	).Scan(&visible); err != nil {
+		return nil, fmt.Errorf("failed to check aircraft: %w", err)
+	}
+	if !visible {
+		return nil, nil
+	}
+	return aircraftGallery.imageRef(ctx, s.db, id, imageID, true)
+}
+
+func (s *AircraftStore) lockOwnedTx(ctx context.Context, tx *sql.Tx, id string, userID string) error {
+	var lockedID string
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM aircraft WHERE id = $1 AND user_id = $2 FOR UPDATE`, id, userID).Scan(&lockedID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("aircraft not found")
+		}
+		return fmt.Errorf("failed to load aircraft before editing gallery: %w", err)
+	}
+	return nil
 }
 
 // Helper to join strings
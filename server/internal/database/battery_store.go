@@ -533,6 +533,27 @@ func (s *BatteryStore) List(ctx context.Context, userID string, params models.Ba
 	}, nil
 }
 
+// ListAll returns every battery for a user, paging through List
+func (s *BatteryStore) ListAll(ctx context.Context, userID string) ([]models.Battery, error) {
+	const pageSize = 100
+	batteries := []models.Battery{}
+	for {
+		page, err := s.List(ctx, userID, models.BatteryListParams{
+			Sort:      "created_at",
+			SortOrder: "ASC",
+			Limit:     pageSize,
+			Offset:    len(batteries),
+		})
+		if err != nil {
+			return nil, err
+		}
+		batteries = append(batteries, page.Batteries...)
+		if len(page.Batteries) < pageSize || len(batteries) >= page.TotalCount {
+			return batteries, nil
+		}
+	}
+}
+
 // BatteryCodeExists checks if a battery code already exists for a user
 func (s *BatteryStore) BatteryCodeExists(ctx context.Context, userID string, code string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM batteries WHERE user_id = $1 AND battery_code = $2)`
@@ -556,10 +577,24 @@ func (s *BatteryStore) CreateLog(ctx context.Context, userID string, params mode
 		return nil, fmt.Errorf("battery not found")
 	}
 
+	// Verify the aircraft (if any) belongs to user
+	var aircraftID sql.NullString
+	if params.AircraftID != "" {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM aircraft WHERE id = $1 AND user_id = $2)", params.AircraftID, userID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify aircraft: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("aircraft not found")
+		}
+		aircraftID = sql.NullString{String: params.AircraftID, Valid: true}
+	}
+
 	query := `
-		INSERT INTO battery_logs (battery_id, user_id, logged_at, cycle_delta, ir_mohm_per_cell, min_cell_v, max_cell_v, storage_ok, notes)
-		VALUES ($1, $2, COALESCE($3, NOW()), $4, $5, $6, $7, $8, $9)
-		RETURNING id, battery_id, user_id, logged_at, cycle_delta, ir_mohm_per_cell, min_cell_v, max_cell_v, storage_ok, notes, created_at
+		INSERT INTO battery_logs (battery_id, user_id, aircraft_id, logged_at, cycle_delta, ir_mohm_per_cell, min_cell_v, max_cell_v, storage_ok, notes)
+		VALUES ($1, $2, $3, COALESCE($4, NOW()), $5, $6, $7, $8, $9, $10)
+		RETURNING id, battery_id, user_id, aircraft_id, logged_at, cycle_delta, ir_mohm_per_cell, min_cell_v, max_cell_v, storage_ok, notes, created_at
 	`
 
 	var loggedAt sql.NullTime
@@ -574,17 +609,17 @@ func (s *BatteryStore) CreateLog(ctx context.Context, userID string, params mode
 
 	log := &models.BatteryLog{}
 	var (
-		scanNotes          sql.NullString
-		scanMinV, scanMaxV sql.NullFloat64
-		scanStorageOk      sql.NullBool
-		scanIR             []byte
+		scanNotes, scanAircraftID sql.NullString
+		scanMinV, scanMaxV        sql.NullFloat64
+		scanStorageOk             sql.NullBool
+		scanIR                    []byte
 	)
 
 	err = s.db.QueryRowContext(ctx, query,
-		params.BatteryID, userID, loggedAt, params.CycleDelta, irJSON,
+		params.BatteryID, userID, aircraftID, loggedAt, params.CycleDelta, irJSON,
 		params.MinCellV, params.MaxCellV, params.StorageOk, params.Notes,
 	).Scan(
-		&log.ID, &log.BatteryID, &log.UserID, &log.LoggedAt, &log.CycleDelta,
+		&log.ID, &log.BatteryID, &log.UserID, &scanAircraftID, &log.LoggedAt, &log.CycleDelta,
 		&scanIR, &scanMinV, &scanMaxV, &scanStorageOk, &scanNotes, &log.CreatedAt,
 	)
 	if err != nil {
@@ -592,6 +627,7 @@ func (s *BatteryStore) CreateLog(ctx context.Context, userID string, params mode
 	}
 
 	log.Notes = scanNotes.String
+	log.AircraftID = scanAircraftID.String
 	if scanMinV.Valid {
 		log.MinCellV = &scanMinV.Float64
 	}
@@ -634,10 +670,12 @@ func (s *BatteryStore) ListLogs(ctx context.Context, batteryID string, userID st
 	}
 
 	query := `
-		SELECT id, battery_id, user_id, logged_at, cycle_delta, ir_mohm_per_cell, min_cell_v, max_cell_v, storage_ok, notes, created_at
-		FROM battery_logs
-		WHERE battery_id = $1
-		ORDER BY logged_at DESC
+		SELECT l.id, l.battery_id, l.user_id, l.aircraft_id, a.name, l.logged_at, l.cycle_delta, l.ir_mohm_per_cell,
+		       l.min_cell_v, l.max_cell_v, l.storage_ok, l.notes, l.created_at
+		FROM battery_logs l
+		LEFT JOIN aircraft a ON a.id = l.aircraft_id
+		WHERE l.battery_id = $1
+		ORDER BY l.logged_at DESC
 		LIMIT $2
 	`
 
@@ -647,24 +685,80 @@ func (s *BatteryStore) ListLogs(ctx context.Context, batteryID string, userID st
 	}
 	defer rows.Close()
 
+	logs, err := scanBatteryLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BatteryLogListResponse{
+		Logs:       logs,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// ListAllLogs returns every log entry across a user's batteries, oldest first
+func (s *BatteryStore) ListAllLogs(ctx context.Context, userID string) ([]models.BatteryLog, error) {
+	query := `
+		SELECT l.id, l.battery_id, l.user_id, l.aircraft_id, a.name, l.logged_at, l.cycle_delta, l.ir_mohm_per_cell,
+		       l.min_cell_v, l.max_cell_v, l.storage_ok, l.notes, l.created_at
+		FROM battery_logs l
+		JOIN batteries b ON b.id = l.battery_id
+		LEFT JOIN aircraft a ON a.id = l.aircraft_id
+		WHERE b.user_id = $1
+		ORDER BY l.battery_id, l.logged_at ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBatteryLogs(rows)
+}
+
+// ListAircraftNames returns the names of a user's aircraft keyed by ID, so
+// imported battery logs can be matched to an aircraft by name
+func (s *BatteryStore) ListAircraftNames(ctx context.Context, userID string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name FROM aircraft WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aircraft: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan aircraft: %w", err)
+		}
+		names[id] = name
+	}
+	return names, rows.Err()
+}
+
+// scanBatteryLogs scans battery_logs rows joined with the aircraft name
+func scanBatteryLogs(rows *sql.Rows) ([]models.BatteryLog, error) {
 	logs := []models.BatteryLog{}
 	for rows.Next() {
 		log := models.BatteryLog{}
 		var (
-			scanNotes          sql.NullString
-			scanMinV, scanMaxV sql.NullFloat64
-			scanStorageOk      sql.NullBool
-			scanIR             []byte
+			scanNotes, scanAircraftID, scanAircraftName sql.NullString
+			scanMinV, scanMaxV                          sql.NullFloat64
+			scanStorageOk                               sql.NullBool
+			scanIR                                      []byte
 		)
 
 		if err := rows.Scan(
-			&log.ID, &log.BatteryID, &log.UserID, &log.LoggedAt, &log.CycleDelta,
+			&log.ID, &log.BatteryID, &log.UserID, &scanAircraftID, &scanAircraftName, &log.LoggedAt, &log.CycleDelta,
 			&scanIR, &scanMinV, &scanMaxV, &scanStorageOk, &scanNotes, &log.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan log: %w", err)
 		}
 
 		log.Notes = scanNotes.String
+		log.AircraftID = scanAircraftID.String
+		log.AircraftName = scanAircraftName.String
 		if scanMinV.Valid {
 			log.MinCellV = &scanMinV.Float64
 		}
@@ -681,10 +775,11 @@ func (s *BatteryStore) ListLogs(ctx context.Context, batteryID string, userID st
 		logs = append(logs, log)
 	}
 
-	return &models.BatteryLogListResponse{
-		Logs:       logs,
-		TotalCount: totalCount,
-	}, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate logs: %w", err)
+	}
+
+	return logs, nil
 }
 
 // DeleteLog deletes a battery log entry
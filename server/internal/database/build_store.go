@@ -268,6 +268,19 @@ func (s *BuildStore) ListPublic(ctx context.Context, params models.BuildListPara
 		argIndex++
 	}
 
+	if strings.TrimSpace(params.CatalogItemID) != "" {
+		conditions = append(conditions, fmt.Sprintf(`
+			EXISTS (
+				SELECT 1
+				FROM build_parts bp
+				WHERE bp.build_id = b.id
+				  AND bp.catalog_item_id::text = $%d
+			)
+		`, argIndex))
+		args = append(args, strings.TrimSpace(params.CatalogItemID))
+		argIndex++
+	}
+
 	whereClause := strings.Join(conditions, " AND ")
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM builds b WHERE %s`, whereClause)
@@ -436,6 +449,13 @@ func (s *BuildStore) GetForOwner(ctx context.Context, id string, ownerUserID str
 		return nil, err
 	}
 	s.setMainImageURLs([]*models.Build{build}, false)
+	galleryBuildID := build.ID
+	if build.Status == models.BuildStatusPublished && strings.TrimSpace(build.StagedRevisionID) != "" {
+		galleryBuildID = build.StagedRevisionID
+	}
+	if err := s.attachGallery(ctx, build, galleryBuildID, fmt.Sprintf("/api/builds/%s/gallery", build.ID), true); err != nil {
+		return nil, err
+	}
 	if err := s.attachReactionSummary(ctx, []*models.Build{build}, ownerUserID); err != nil {
 		return nil, err
 	}
@@ -453,6 +473,9 @@ func (s *BuildStore) GetPublic(ctx context.Context, id string, viewerUserID stri
 		return nil, err
 	}
 	s.setMainImageURLs([]*models.Build{build}, true)
+	if err := s.attachGallery(ctx, build, build.ID, fmt.Sprintf("/api/public/builds/%s/gallery", build.ID), false); err != nil {
+		return nil, err
+	}
 	if err := s.attachReactionSummary(ctx, []*models.Build{build}, viewerUserID); err != nil {
 		return nil, err
 	}
@@ -829,20 +852,24 @@ func (s *BuildStore) SetImage(ctx context.Context, id string, ownerUserID string
 		return "", fmt.Errorf("build not found")
 	}
 
-	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("failed to commit build image update: %w", err)
+	if err := buildGallery.replaceCover(ctx, tx, targetBuildID, previousAssetID.String, imageAssetID); err != nil {
+		return "", err
+	}
+	orphanID, err := orphanedAssetID(ctx, tx, previousAssetID.String)
+	if err != nil {
+		return "", err
 	}
 
-	if previousAssetID.Valid {
-		return previousAssetID.String, nil
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit build image update: %w", err)
 	}
-	return "", nil
+	return orphanID, nil
 }
 
-// GetImageForOwner loads approved build image bytes for an owner-visible build.
-func (s *BuildStore) GetImageForOwner(ctx context.Context, id string, ownerUserID string) ([]byte, error) {
+// GetImageForOwner locates the image for an owner-visible build, including one awaiting review.
+func (s *BuildStore) GetImageForOwner(ctx context.Context, id string, ownerUserID string) (*models.ImageRef, error) {
 	query := `
-		SELECT ia.image_bytes
+		SELECT ia.id, NULL::bytea, NULL::text
 		FROM builds b
 		LEFT JOIN builds r
 		  ON r.revision_of_build_id = b.id
@@ -856,7 +883,7 @@ func (s *BuildStore) GetImageForOwner(ctx context.Context, id string, ownerUserI
 			END,
 			b.image_asset_id
 		  )
-		 AND ia.status = 'APPROVED'
+		 AND ia.status IN ('APPROVED', 'PENDING_REVIEW')
 		WHERE b.id = $1
 		  AND b.owner_user_id = $2
 		  AND b.status IN ('DRAFT', 'PENDING_REVIEW', 'PUBLISHED', 'UNPUBLISHED', 'DECLINED')
@@ -869,37 +896,36 @@ func (s *BuildStore) GetImageForOwner(ctx context.Context, id string, ownerUserI
 		  ) IS NOT NULL
 	`
 
-	var imageData []byte
-	err := s.db.QueryRowContext(ctx, query, id, ownerUserID).Scan(&imageData)
+	ref, err := scanImageRef(s.db.QueryRowContext(ctx, query, id, ownerUserID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get build image: %w", err)
 	}
-	return imageData, nil
+	return ref, nil
 }
 
-// GetPublicImage loads approved build image bytes for a published build.
-func (s *BuildStore) GetPublicImage(ctx context.Context, id string) ([]byte, error) {
+// GetPublicImage locates the image for a published build. An image awaiting
+// review is returned too and served as a placeholder.
+func (s *BuildStore) GetPublicImage(ctx context.Context, id string) (*models.ImageRef, error) {
 	query := `
-		SELECT ia.image_bytes
+		SELECT ia.id, NULL::bytea, NULL::text
 		FROM builds b
-		JOIN image_assets ia ON ia.id = b.image_asset_id AND ia.status = 'APPROVED'
+		JOIN image_assets ia ON ia.id = b.image_asset_id AND ia.status IN ('APPROVED', 'PENDING_REVIEW')
 		WHERE b.id = $1
 		  AND b.status = 'PUBLISHED'
 		  AND b.image_asset_id IS NOT NULL
 	`
 
-	var imageData []byte
-	err := s.db.QueryRowContext(ctx, query, id).Scan(&imageData)
+	ref, err := scanImageRef(s.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get public build image: %w", err)
 	}
-	return imageData, nil
+	return ref, nil
 }
 
 // DeleteImage removes a build image and returns any previous image asset ID.
@@ -948,28 +974,237 @@ func (s *BuildStore) DeleteImage(ctx context.Context, id string, ownerUserID str
 		return "", fmt.Errorf("failed to fetch existing build image reference: %w", err)
 	}
 
-	query := `
-		UPDATE builds
-		SET image_asset_id = NULL,
-		    updated_at = NOW()
-		WHERE id = $1 AND owner_user_id = $2 AND status IN ('DRAFT', 'PENDING_REVIEW', 'UNPUBLISHED', 'DECLINED')
-	`
-	result, err := tx.ExecContext(ctx, query, targetBuildID, ownerUserID)
-	if err != nil {
+	// The next gallery image, if any, becomes the cover
+	if err := buildGallery.removeCover(ctx, tx, targetBuildID, previousAssetID.String); err != nil {
 		return "", fmt.Errorf("failed to delete build image: %w", err)
 	}
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return "", fmt.Errorf("build not found")
+	orphanID, err := orphanedAssetID(ctx, tx, previousAssetID.String)
+	if err != nil {
+		return "", err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return "", fmt.Errorf("failed to commit build image delete: %w", err)
 	}
-	if previousAssetID.Valid {
-		return previousAssetID.String, nil
+	return orphanID, nil
+}
+
+// ListGallery returns the owner-visible gallery, including staged revision edits.
+func (s *BuildStore) ListGallery(ctx context.Context, id string, ownerUserID string) ([]models.GalleryImage, error) {
+	targetBuildID, err := s.ownerGalleryBuildID(ctx, id, ownerUserID)
+	if err != nil || targetBuildID == "" {
+		return nil, err
+	}
+	images, err := buildGallery.list(ctx, s.db, targetBuildID)
+	if err != nil {
+		return nil, err
 	}
-	return "", nil
+	setGalleryImageURLs(images, fmt.Sprintf("/api/builds/%s/gallery", id))
+	return images, nil
+}
+
+// AddGalleryImage appends an approved image asset to a build gallery.
+// Published builds are edited through their revision draft.
+func (s *BuildStore) AddGalleryImage(ctx context.Context, id string, ownerUserID string, imageAssetID string, caption string) (*models.GalleryImage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	targetBuildID, err := s.editableBuildIDTx(ctx, tx, id, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	image, err := buildGallery.add(ctx, tx, targetBuildID, imageAssetID, caption)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit gallery image: %w", err)
+	}
+
+	image.ImageURL = galleryImageURL(fmt.Sprintf("/api/builds/%s/gallery", id), image.ID)
+	return image, nil
+}
+
+// UpdateGalleryImage edits a gallery image caption or makes it the build cover.
+func (s *BuildStore) UpdateGalleryImage(ctx context.Context, id string, ownerUserID string, imageID string, params models.UpdateGalleryImageParams) (*models.GalleryImage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	targetBuildID, err := s.editableBuildIDTx(ctx, tx, id, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := buildGallery.update(ctx, tx, targetBuildID, imageID, params); err != nil {
+		return nil, err
+	}
+	image, err := buildGallery.get(ctx, tx, targetBuildID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit gallery update: %w", err)
+	}
+
+	if image != nil {
+		image.ImageURL = galleryImageURL(fmt.Sprintf("/api/builds/%s/gallery", id), image.ID)
+	}
+	return image, nil
+}
+
+// ReorderGallery sets the gallery order; imageIDs must list every image once.
+func (s *BuildStore) ReorderGallery(ctx context.Context, id string, ownerUserID string, imageIDs []string) ([]models.GalleryImage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	targetBuildID, err := s.editableBuildIDTx(ctx, tx, id, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := buildGallery.reorder(ctx, tx, targetBuildID, imageIDs); err != nil {
+		return nil, err
+	}
+	images, err := buildGallery.list(ctx, tx, targetBuildID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit gallery reorder: %w", err)
+	}
+
+	setGalleryImageURLs(images, fmt.Sprintf("/api/builds/%s/gallery", id))
+	return images, nil
+}
+
+// DeleteGalleryImage removes an image from a build gallery. Returns the image
+// asset ID when no other build or aircraft still uses it.
+func (s *BuildStore) DeleteGalleryImage(ctx context.Context, id string, ownerUserID string, imageID string) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to start gallery transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	targetBuildID, err := s.editableBuildIDTx(ctx, tx, id, ownerUserID)
+	if err != nil {
+		return "", err
+	}
+	imageAssetID, err := buildGallery.remove(ctx, tx, targetBuildID, imageID)
+	if err != nil {
+		return "", err
+	}
+	orphanID, err := orphanedAssetID(ctx, tx, imageAssetID)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit gallery delete: %w", err)
+	}
+	return orphanID, nil
+}
+
+// GetGalleryImageForOwner locates a gallery image for an owner-visible build.
+func (s *BuildStore) GetGalleryImageForOwner(ctx context.Context, id string, ownerUserID string, imageID string) (*models.ImageRef, error) {
+	targetBuildID, err := s.ownerGalleryBuildID(ctx, id, ownerUserID)
+	if err != nil || targetBuildID == "" {
+		return nil, err
+	}
+	return buildGallery.imageRef(ctx, s.db, targetBuildID, imageID, true)
+}
+
+// GetPublicGalleryImage locates a gallery image for a published build.
+func (s *BuildStore) GetPublicGalleryImage(ctx context.Context, id string, imageID string) (*models.ImageRef, error) {
+	var published bool
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM builds WHERE id = $1 AND status = 'PUBLISHED')`,
+		id,
+	).Scan(&published); err != nil {
+		return nil, fmt.Errorf("failed to check public build: %w", err)
+	}
+	if !published {
+		return nil, nil
+	}
+	return buildGallery.imageRef(ctx, s.db, id, imageID, false)
+}
+
+// GetGalleryImageForModeration locates a gallery image for admin moderation views.
+func (s *BuildStore) GetGalleryImageForModeration(ctx context.Context, id string, imageID string) (*models.ImageRef, error) {
+	return buildGallery.imageRef(ctx, s.db, id, imageID, true)
+}
+
+// ownerGalleryBuildID resolves which build row holds the gallery an owner sees:
+// the revision draft for published builds being edited, otherwise the build itself.
+func (s *BuildStore) ownerGalleryBuildID(ctx context.Context, id string, ownerUserID string) (string, error) {
+	var targetBuildID string
+	err := s.db.QueryRowContext(
+		ctx,
+		`
+			SELECT COALESCE(
+				CASE
+					WHEN b.status = 'PUBLISHED' THEN r.id
+					ELSE NULL
+				END,
+				b.id
+			)
+			FROM builds b
+			LEFT JOIN builds r
+			  ON r.revision_of_build_id = b.id
+			 AND r.owner_user_id = b.owner_user_id
+			 AND r.status IN ('DRAFT', 'PENDING_REVIEW', 'UNPUBLISHED', 'DECLINED')
+			WHERE b.id = $1
+			  AND b.owner_user_id = $2
+			  AND b.status IN ('DRAFT', 'PENDING_REVIEW', 'PUBLISHED', 'UNPUBLISHED', 'DECLINED')
+			ORDER BY r.updated_at DESC NULLS LAST
+			LIMIT 1
+		`,
+		id,
+		ownerUserID,
+	).Scan(&targetBuildID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve build gallery: %w", err)
+	}
+	return targetBuildID, nil
+}
+
+// editableBuildIDTx returns the build row an owner edit should touch, creating
+// a revision draft when the build is published.
+func (s *BuildStore) editableBuildIDTx(ctx context.Context, tx *sql.Tx, id string, ownerUserID string) (string, error) {
+	var currentStatus models.BuildStatus
+	if err := tx.QueryRowContext(
+		ctx,
+		`SELECT status FROM builds WHERE id = $1 AND owner_user_id = $2 AND status IN ('DRAFT', 'PENDING_REVIEW', 'PUBLISHED', 'UNPUBLISHED', 'DECLINED')`,
+		id,
+		ownerUserID,
+	).Scan(&currentStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("build not found")
+		}
+		return "", fmt.Errorf("failed to load build before editing gallery: %w", err)
+	}
+
+	if currentStatus != models.BuildStatusPublished {
+		return id, nil
+	}
+	targetBuildID, err := s.ensurePublishedRevisionDraftTx(ctx, tx, id, ownerUserID)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(targetBuildID) == "" {
+		return "", fmt.Errorf("build not found")
+	}
+	return targetBuildID, nil
 }
 
 // Delete removes a non-temp build for the owner.
@@ -1123,6 +1358,9 @@ func (s *BuildStore) GetForModeration(ctx context.Context, id string) (*models.B
 		return nil, err
 	}
 	s.setAdminMainImageURLs([]*models.Build{build})
+	if err := s.attachGallery(ctx, build, build.ID, fmt.Sprintf("/api/admin/builds/%s/gallery", build.ID), true); err != nil {
+		return nil, err
+	}
 	if err := s.attachReactionSummary(ctx, []*models.Build{build}, ""); err != nil {
 		return nil, err
 	}
@@ -1219,32 +1457,31 @@ func (s *BuildStore) SetImageForModeration(ctx context.Context, id string, image
 		return "", fmt.Errorf("build not found")
 	}
 
-	if previousAssetID.Valid {
-		return previousAssetID.String, nil
+	if err := buildGallery.replaceCover(ctx, s.db, id, previousAssetID.String, imageAssetID); err != nil {
+		return "", err
 	}
-	return "", nil
+	return orphanedAssetID(ctx, s.db, previousAssetID.String)
 }
 
-// GetImageForModeration loads approved image bytes for admin moderation views.
-func (s *BuildStore) GetImageForModeration(ctx context.Context, id string) ([]byte, error) {
+// GetImageForModeration locates the image for admin moderation views, including one awaiting review.
+func (s *BuildStore) GetImageForModeration(ctx context.Context, id string) (*models.ImageRef, error) {
 	query := `
-		SELECT ia.image_bytes
+		SELECT ia.id, NULL::bytea, NULL::text
 		FROM builds b
-		JOIN image_assets ia ON ia.id = b.image_asset_id AND ia.status = 'APPROVED'
+		JOIN image_assets ia ON ia.id = b.image_asset_id AND ia.status IN ('APPROVED', 'PENDING_REVIEW')
 		WHERE b.id = $1
 		  AND b.status IN ('DRAFT', 'PENDING_REVIEW', 'PUBLISHED', 'UNPUBLISHED', 'DECLINED')
 		  AND b.image_asset_id IS NOT NULL
 	`
 
-	var imageData []byte
-	err := s.db.QueryRowContext(ctx, query, id).Scan(&imageData)
+	ref, err := scanImageRef(s.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get moderation build image: %w", err)
 	}
-	return imageData, nil
+	return ref, nil
 }
 
 // DeleteImageForModeration removes a build image and returns any previous asset ID.
@@ -1261,22 +1498,10 @@ func (s *BuildStore) DeleteImageForModeration(ctx context.Context, id string) (s
 		return "", fmt.Errorf("failed to fetch existing build image reference: %w", err)
 	}
 
-	result, err := s.db.ExecContext(
-		ctx,
-		`UPDATE builds SET image_asset_id = NULL, updated_at = NOW() WHERE id = $1 AND status IN ('DRAFT', 'PENDING_REVIEW', 'PUBLISHED', 'UNPUBLISHED', 'DECLINED')`,
-		id,
-	)
-	if err != nil {
+	if err := buildGallery.removeCover(ctx, s.db, id, previousAssetID.String); err != nil {
 		return "", fmt.Errorf("failed to delete moderation build image: %w", err)
 	}
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return "", fmt.Errorf("build not found")
-	}
-	if previousAssetID.Valid {
-		return previousAssetID.String, nil
-	}
-	return "", nil
+	return orphanedAssetID(ctx, s.db, previousAssetID.String)
 }
 
 // ApproveForModeration publishes a build from the pending moderation queue.
@@ -1349,6 +1574,23 @@ func (s *BuildStore) ApproveForModeration(ctx context.Context, id string) (*mode
 			return nil, fmt.Errorf("failed to copy approved revision parts: %w", err)
 		}
 
+		if _, err := tx.ExecContext(ctx, `DELETE FROM build_images WHERE build_id = $1`, approvedBuildID); err != nil {
+			return nil, fmt.Errorf("failed to clear published build gallery during approval: %w", err)
+		}
+		if _, err := tx.ExecContext(
+			ctx,
+			`
+				INSERT INTO build_images (build_id, id, image_asset_id, caption, position, created_at)
+				SELECT $1, id, image_asset_id, caption, position, created_at
+				FROM build_images
+				WHERE build_id = $2
+			`,
+			approvedBuildID,
+			id,
+		); err != nil {
+			return nil, fmt.Errorf("failed to copy approved revision gallery: %w", err)
+		}
+
 		if _, err := tx.ExecContext(ctx, `DELETE FROM builds WHERE id = $1`, id); err != nil {
 			return nil, fmt.Errorf("failed to clean up approved build revision: %w", err)
 		}
@@ -1475,6 +1717,20 @@ func (s *BuildStore) ensurePublishedRevisionDraftTx(ctx context.Context, tx *sql
 		return "", fmt.Errorf("failed to copy parts into revision draft: %w", err)
 	}
 
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+			INSERT INTO build_images (build_id, id, image_asset_id, caption, position, created_at)
+			SELECT $1, id, image_asset_id, caption, position, created_at
+			FROM build_images
+			WHERE build_id = $2
+		`,
+		revisionBuildID,
+		publishedBuildID,
+	); err != nil {
+		return "", fmt.Errorf("failed to copy gallery into revision draft: %w", err)
+	}
+
 	return revisionBuildID, nil
 }
 
@@ -1687,6 +1943,25 @@ func (s *BuildStore) attachParts(ctx context.Context, builds []*models.Build) er
 	return nil
 }
 
+func (s *BuildStore) attachGallery(ctx context.Context, build *models.Build, galleryBuildID string, urlBase string, includePending bool) error {
+	images, err := buildGallery.list(ctx, s.db, galleryBuildID)
+	if err != nil {
+		return err
+	}
+	if !includePending {
+		approved := images[:0]
+		for _, image := range images {
+			if !image.Pending {
+				approved = append(approved, image)
+			}
+		}
+		images = approved
+	}
+	setGalleryImageURLs(images, urlBase)
+	build.Gallery = images
+	return nil
+}
+
 func (s *BuildStore) attachStagedRevisionParts(ctx context.Context, builds []*models.Build) error {
 	stagedIDs := make([]string, 0, len(builds))
 	for _, build := range builds {
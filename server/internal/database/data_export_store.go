@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// DataExportStore handles account data export jobs
+type DataExportStore struct {
+	db *DB
+}
+
+// NewDataExportStore creates a new data export store
+func NewDataExportStore(db *DB) *DataExportStore {
+	return &DataExportStore{db: db}
+}
+
+const dataExportColumns = `id, user_id, status, storage_key, file_size, error, expires_at, created_at, started_at, completed_at`
+
+// Create queues a new export job for a user
+func (s *DataExportStore) Create(ctx context.Context, userID string) (*models.DataExport, error) {
+	query := `
+		INSERT INTO data_exports (user_id, status)
+		VALUES ($1, $2)
+		RETURNING ` + dataExportColumns
+
+	export, err := scanDataExport(s.db.QueryRowContext(ctx, query, userID, models.DataExportPending))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data export: %w", err)
+	}
+	return export, nil
+}
+
+// Get returns one of a user's exports, or nil if it does not exist
+func (s *DataExportStore) Get(ctx context.Context, id string, userID string) (*models.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE id = $1 AND user_id = $2`
+
+	export, err := scanDataExport(s.db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return export, nil
+}
+
+// ListByUser returns a user's exports, newest first
+func (s *DataExportStore) ListByUser(ctx context.Context, userID string, limit int) ([]models.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + `
+		FROM data_exports
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	return s.list(ctx, query, userID, limit)
+}
+
+// ListArchived returns the user's exports that still have a stored archive
+func (s *DataExportStore) ListArchived(ctx context.Context, userID string) ([]models.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + `
+		FROM data_exports
+		WHERE user_id = $1 AND storage_key IS NOT NULL
+		ORDER BY created_at
+	`
+	return s.list(ctx, query, userID)
+}
+
+// GetActive returns the user's pending or running export, or nil
+func (s *DataExportStore) GetActive(ctx context.Context, userID string) (*models.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + `
+		FROM data_exports
+		WHERE user_id = $1 AND status IN ($2, $3)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	export, err := scanDataExport(s.db.QueryRowContext(ctx, query, userID, models.DataExportPending, models.DataExportRunning))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active data export: %w", err)
+	}
+	return export, nil
+}
+
+// MarkRunning records that the export has started building
+func (s *DataExportStore) MarkRunning(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE data_exports SET status = $2, started_at = NOW()
+		WHERE id = $1
+	`, id, models.DataExportRunning)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export running: %w", err)
+	}
+	return nil
+}
+
+// MarkReady records the stored archive and when it expires. It fails if the
+// job is gone, e.g. because the account was deleted while it ran.
+func (s *DataExportStore) MarkReady(ctx context.Context, id string, storageKey string, fileSize int64, expiresAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE data_exports
+		SET status = $2, storage_key = $3, file_size = $4, expires_at = $5, error = NULL, completed_at = NOW()
+		WHERE id = $1
+	`, id, models.DataExportReady, storageKey, fileSize, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export ready: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("data export %s not found", id)
+	}
+	return nil
+}
+
+// MarkFailed records why the export could not be built
+func (s *DataExportStore) MarkFailed(ctx context.Context, id string, message string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE data_exports SET status = $2, error = $3, completed_at = NOW()
+		WHERE id = $1
+	`, id, models.DataExportFailed, message)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export failed: %w", err)
+	}
+	return nil
+}
+
+// MarkExpired records that the archive has been deleted
+func (s *DataExportStore) MarkExpired(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE data_exports SET status = $2, storage_key = NULL
+		WHERE id = $1
+	`, id, models.DataExportExpired)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export expired: %w", err)
+	}
+	return nil
+}
+
+// ListExpired returns ready exports whose archives are past their expiry
+func (s *DataExportStore) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.DataExport, error) {
+	query := `
+		SELECT ` + dataExportColumns + `
+		FROM data_exports
+		WHERE status = $1 AND expires_at <= $2
+		ORDER BY expires_at
+		LIMIT $3
+	`
+	return s.list(ctx, query, models.DataExportReady, now, limit)
+}
+
+// FailStale fails pending or running exports created before cutoff, e.g.
+// jobs that were in flight when the server restarted
+func (s *DataExportStore) FailStale(ctx context.Context, cutoff time.Time, message string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE data_exports SET status = $1, error = $2, completed_at = NOW()
+		WHERE status IN ($3, $4) AND created_at < $5
+	`, models.DataExportFailed, message, models.DataExportPending, models.DataExportRunning, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale data exports: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (s *DataExportStore) list(ctx context.Context, query string, args ...interface{}) ([]models.DataExport, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data exports: %w", err)
+	}
+	defer rows.Close()
+
+	exports := []models.DataExport{}
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan data export: %w", err)
+		}
+		exports = append(exports, *export)
+	}
+	return exports, rows.Err()
+}
+
+func scanDataExport(row interface{ Scan(...any) error }) (*models.DataExport, error) {
+	export := &models.DataExport{}
+	var status string
+	var storageKey, exportError sql.NullString
+	var expiresAt, startedAt, completedAt sql.NullTime
+
+	if err := row.Scan(
+		&export.ID, &export.UserID, &status, &storageKey, &export.FileSize, &exportError,
+		&expiresAt, &export.CreatedAt, &startedAt, &completedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	export.Status = models.DataExportStatus(status)
+	export.StorageKey = storageKey.String
+	export.Error = exportError.String
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+	if startedAt.Valid {
+		export.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	return export, nil
+}
@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ErrDataImportDuplicate is returned when the user already imported, or is
+// importing, the same export archive
+var ErrDataImportDuplicate = errors.New("export archive already imported")
+
+// DataImportStore handles account data import jobs
+type DataImportStore struct {
+	db *DB
+}
+
+// NewDataImportStore creates a new data import store
+func NewDataImportStore(db *DB) *DataImportStore {
+	return &DataImportStore{db: db}
+}
+
+const dataImportColumns = `id, user_id, COALESCE(source_export_id, ''), status, result, error, created_at, started_at, completed_at`
+
+// Create queues a new import job for a user. Only failed imports of the
+// same export archive may be retried; otherwise ErrDataImportDuplicate is
+// returned.
+func (s *DataImportStore) Create(ctx context.Context, userID string, sourceExportID string) (*models.DataImport, error) {
+	query := `
+		INSERT INTO data_imports (user_id, source_export_id, status)
+		VALUES ($1, $2, $3)
+		RETURNING ` + dataImportColumns
+
+	job, err := scanDataImport(s.db.QueryRowContext(ctx, query, userID, sourceExportID, models.DataImportPending))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && string(pqErr.Code) == "23505" {
+		return nil, ErrDataImportDuplicate
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data import: %w", err)
+	}
+	return job, nil
+}
+
+// Get returns one of a user's imports, or nil if it does not exist
+func (s *DataImportStore) Get(ctx context.Context, id string, userID string) (*models.DataImport, error) {
+	query := `SELECT ` + dataImportColumns + ` FROM data_imports WHERE id = $1 AND user_id = $2`
+
+	job, err := scanDataImport(s.db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data import: %w", err)
+	}
+	return job, nil
+}
+
+// ListByUser returns a user's imports, newest first
+func (s *DataImportStore) ListByUser(ctx context.Context, userID string, limit int) ([]models.DataImport, error) {
+	query := `
+		SELECT ` + dataImportColumns + `
+		FROM data_imports
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data imports: %w", err)
+	}
+	return collectDataImports(rows)
+}
+
+// GetActive returns the user's pending or running import, or nil
+func (s *DataImportStore) GetActive(ctx context.Context, userID string) (*models.DataImport, error) {
+	query := `
+		SELECT ` + dataImportColumns + `
+		FROM data_imports
+		WHERE user_id = $1 AND status IN ($2, $3)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	job, err := scanDataImport(s.db.QueryRowContext(ctx, query, userID, models.DataImportPending, models.DataImportRunning))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active data import: %w", err)
+	}
+	return job, nil
+}
+
+// MarkRunning records that the import has started
+func (s *DataImportStore) MarkRunning(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE data_imports SET status = $2, started_at = NOW()
+		WHERE id = $1
+	`, id, models.DataImportRunning)
+	if err != nil {
+		return fmt.Errorf("failed to mark data import running: %w", err)
+	}
+	return nil
+}
+
+// MarkCompleted records what the import restored
+func (s *DataImportStore) MarkCompleted(ctx context.Context, id string, result *models.DataImportResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode data import result: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE data_imports SET status = $2, result = $3, error = NULL, completed_at = NOW()
+		WHERE id = $1
+	`, id, models.DataImportCompleted, data)
+	if err != nil {
+		return fmt.Errorf("failed to mark data import completed: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records why the import stopped. Records restored before the
+// failure are kept and described by result, which may be nil.
+func (s *DataImportStore) MarkFailed(ctx context.Context, id string, message string, result *models.DataImportResult) error {
+	var data []byte
+	if result != nil {
+		var err error
+		if data, err = json.Marshal(result); err != nil {
+			return fmt.Errorf("failed to encode data import result: %w", err)
+		}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE data_imports SET status = $2, error = $3, result = $4, completed_at = NOW()
+		WHERE id = $1
+	`, id, models.DataImportFailed, message, data)
+	if err != nil {
+		return fmt.Errorf("failed to mark data import failed: %w", err)
+	}
+	return nil
+}
+
+// FailStale fails pending or running imports created before cutoff, e.g.
+// jobs that were in flight when the server restarted, and returns them
+func (s *DataImportStore) FailStale(ctx context.Context, cutoff time.Time, message string) ([]models.DataImport, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE data_imports SET status = $1, error = $2, completed_at = NOW()
+		WHERE status IN ($3, $4) AND created_at < $5
+		RETURNING `+dataImportColumns,
+		models.DataImportFailed, message, models.DataImportPending, models.DataImportRunning, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail stale data imports: %w", err)
+	}
+	return collectDataImports(rows)
+}
+
+func collectDataImports(rows *sql.Rows) ([]models.DataImport, error) {
+	defer rows.Close()
+
+	jobs := []models.DataImport{}
+	for rows.Next() {
+		job, err := scanDataImport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan data import: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanDataImport(row interface{ Scan(...any) error }) (*models.DataImport, error) {
+	job := &models.DataImport{}
+	var status string
+	var result []byte
+	var importError sql.NullString
+	var startedAt, completedAt sql.NullTime
+
+	if err := row.Scan(
+		&job.ID, &job.UserID, &job.SourceExportID, &status, &result, &importError,
+		&job.CreatedAt, &startedAt, &completedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	job.Status = models.DataImportStatus(status)
+	job.Error = importError.String
+	if len(result) > 0 {
+		job.Result = &models.DataImportResult{}
+		if err := json.Unmarshal(result, job.Result); err != nil {
+			return nil, fmt.Errorf("failed to decode data import result: %w", err)
+		}
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+	return job, nil
+}
@@ -70,6 +70,28 @@ func New(config Config) (*DB, error) {
 	return &DB{DB: db, config: config}, nil
 }
 
+// TryAdvisoryLock runs fn while holding the Postgres session advisory lock
+// key, so only one instance does the work. It returns false without running
+// fn when another session holds the lock.
+func (db *DB) TryAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection for advisory lock: %w", err)
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key)
+
+	return true, fn(ctx)
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	return db.DB.Close()
@@ -136,6 +158,22 @@ func (db *DB) Migrate(ctx context.Context) error {
 		migrationGearItemImageURLOverrides,                 // Adds back optional external image_url overrides for gear items
 		migrationGearCatalogAttributionAndShoppingLinks,    // Adds image source attribution + shopping links fields
 		migrationInventoryBatteryCategory,                  // Reclassifies catalog-linked battery inventory from accessories -> batteries
+		migrationAircraftBatteries,                         // Battery packs assigned to aircraft + aircraft on battery logs
+		migrationRadioBackupModelSummary,                   // Parsed EdgeTX model list stored with radio backups
+		migrationObjectStorageKeys,                         // Object storage keys for image assets and radio backups
+		migrationImageDerivatives,                          // Resized, metadata-stripped image variants (thumb/card/full)
+		migrationImageGalleries,                            // Ordered, captioned image galleries for builds and aircraft
+		migrationImageReviewQueue,                          // PENDING_REVIEW image assets and content admin review decisions
+		migrationImagePerceptualHash,                       // dHash of every image asset for near-duplicate detection
+		migrationInventoryUnits,                            // Per-unit inventory records, migrated out of the specs details blob
+		migrationMaintenanceEvents,                         // Crash/repair/maintenance log per aircraft with component swaps
+		migrationFlightSessions,                            // Flight log with packs used and installed components per session
+		migrationOrderItems,                                // Links orders to the inventory items they contain
+		migrationPersonalAccessTokens,                      // Hashed, scoped, expiring API tokens users create for scripts
+		migrationDataExports,                               // Asynchronous account data export (takeout) jobs
+		migrationDataImports,                               // Restores of export archives into an account
+		migrationPasswordlessLogin,                         // Email sign-in links, WebAuthn challenges and passkey credentials
+		migrationDataImportSources,                         // The export each import came from, so an archive is restored once
 	}
 
 	for i, migration := range migrations {
@@ -1228,3 +1266,361 @@ WHERE i.catalog_id = gc.id
   AND gc.gear_type = 'battery'
   AND i.category = 'accessories';
 `
+
+const migrationAircraftBatteries = `
+CREATE TABLE IF NOT EXISTS aircraft_batteries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    aircraft_id UUID NOT NULL REFERENCES aircraft(id) ON DELETE CASCADE,
+    battery_id UUID NOT NULL REFERENCES batteries(id) ON DELETE CASCADE,
+    notes TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(aircraft_id, battery_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_aircraft_batteries_aircraft_id ON aircraft_batteries(aircraft_id);
+CREATE INDEX IF NOT EXISTS idx_aircraft_batteries_battery_id ON aircraft_batteries(battery_id);
+
+ALTER TABLE battery_logs ADD COLUMN IF NOT EXISTS aircraft_id UUID REFERENCES aircraft(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_battery_logs_aircraft_id ON battery_logs(aircraft_id);
+`
+
+const migrationRadioBackupModelSummary = `
+ALTER TABLE radio_backups ADD COLUMN IF NOT EXISTS model_summary JSONB;
+`
+
+// Image bytes and backup files may live in object storage instead of Postgres/local disk.
+// image_bytes stays populated for rows that have not been migrated.
+const migrationObjectStorageKeys = `
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS storage_key VARCHAR(1024);
+ALTER TABLE image_assets ALTER COLUMN image_bytes DROP NOT NULL;
+
+ALTER TABLE radio_backups ADD COLUMN IF NOT EXISTS storage_key VARCHAR(1024);
+`
+
+// Fixed-size variants generated on upload. Bytes live inline or in object storage like image_assets.
+const migrationImageDerivatives = `
+CREATE TABLE IF NOT EXISTS image_derivatives (
+    image_id UUID NOT NULL REFERENCES image_assets(id) ON DELETE CASCADE,
+    size VARCHAR(16) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    byte_size INTEGER NOT NULL,
+    image_bytes BYTEA,
+    storage_key VARCHAR(1024),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (image_id, size)
+);
+`
+
+// Gallery rows keep their id when a published build's gallery is copied into a
+// revision draft (and back on approval), so ids are unique per parent only.
+// The parent's image_asset_id stays the cover; existing images become the first entry.
+const migrationImageGalleries = `
+CREATE TABLE IF NOT EXISTS build_images (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    build_id UUID NOT NULL REFERENCES builds(id) ON DELETE CASCADE,
+    image_asset_id UUID NOT NULL REFERENCES image_assets(id) ON DELETE CASCADE,
+    caption TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (build_id, id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_build_images_image_asset_id ON build_images(image_asset_id);
+
+CREATE TABLE IF NOT EXISTS aircraft_images (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    aircraft_id UUID NOT NULL REFERENCES aircraft(id) ON DELETE CASCADE,
+    image_asset_id UUID NOT NULL REFERENCES image_assets(id) ON DELETE CASCADE,
+    caption TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (aircraft_id, id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_aircraft_images_image_asset_id ON aircraft_images(image_asset_id);
+
+INSERT INTO build_images (build_id, image_asset_id, position)
+SELECT b.id, b.image_asset_id, 0
+FROM builds b
+WHERE b.image_asset_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM build_images bi WHERE bi.build_id = b.id);
+
+INSERT INTO aircraft_images (aircraft_id, image_asset_id, position)
+SELECT a.id, a.image_asset_id, 0
+FROM aircraft a
+WHERE a.image_asset_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM aircraft_images ai WHERE ai.aircraft_id = a.id);
+`
+
+// Images held back by automated moderation are stored as PENDING_REVIEW until a
+// content admin decides. Rejected images keep their row (with the bytes
+// dropped) so the decision and its reason stay on record.
+const migrationImageReviewQueue = `
+ALTER TABLE image_assets DROP CONSTRAINT IF EXISTS image_assets_status_check;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_image_assets_status'
+    ) THEN
+        ALTER TABLE image_assets
+        ADD CONSTRAINT chk_image_assets_status
+        CHECK (status IN ('APPROVED', 'REJECTED', 'PENDING_REVIEW'));
+    END IF;
+END $$;
+
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS queued_reason TEXT;
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS review_reason TEXT;
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS reviewed_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_image_assets_review_queue ON image_assets(created_at) WHERE status = 'PENDING_REVIEW';
+`
+
+// perceptual_hash is a 64-bit dHash of the image. Hashes are compared by
+// Hamming distance (bit_count of the XOR); NULL means not hashed yet or not
+// decodable. hash_attempted_at is set when an image couldn't be decoded, so
+// the backfill doesn't retry it on every start.
+const migrationImagePerceptualHash = `
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS perceptual_hash BIGINT;
+ALTER TABLE image_assets ADD COLUMN IF NOT EXISTS hash_attempted_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_image_assets_unhashed;
+CREATE INDEX IF NOT EXISTS idx_image_assets_hash_pending ON image_assets(id)
+    WHERE perceptual_hash IS NULL AND hash_attempted_at IS NULL;
+`
+
+// inventory_units holds one row per individually tracked piece of an inventory
+// item. Per-item details used to live in specs under
+// "__ff_inventory_item_details"; they are copied into unit rows (in array
+// order) and the key is removed, so re-running this migration is a no-op.
+const migrationInventoryUnits = `
+CREATE TABLE IF NOT EXISTS inventory_units (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    inventory_item_id UUID NOT NULL REFERENCES inventory_items(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    serial_number VARCHAR(255),
+    purchase_price DECIMAL(10,2),
+    purchase_seller VARCHAR(255),
+    condition VARCHAR(20) NOT NULL DEFAULT 'new'
+        CHECK (condition IN ('new', 'used', 'damaged', 'dead', 'repaired')),
+    aircraft_id UUID REFERENCES aircraft(id) ON DELETE SET NULL,
+    build_id VARCHAR(100),
+    notes TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_units_item ON inventory_units(inventory_item_id, position);
+CREATE INDEX IF NOT EXISTS idx_inventory_units_user ON inventory_units(user_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_units_aircraft ON inventory_units(aircraft_id) WHERE aircraft_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_units_serial ON inventory_units(user_id, LOWER(serial_number)) WHERE serial_number IS NOT NULL;
+
+INSERT INTO inventory_units (inventory_item_id, user_id, purchase_price, purchase_seller, build_id, position)
+SELECT i.id,
+       i.user_id,
+       CASE
+           WHEN jsonb_typeof(d.detail->'purchasePrice') = 'number'
+                AND (d.detail->>'purchasePrice')::numeric >= 0
+                THEN (d.detail->>'purchasePrice')::numeric
+           ELSE NULL
+       END,
+       NULLIF(TRIM(d.detail->>'purchaseSeller'), ''),
+       NULLIF(TRIM(d.detail->>'buildId'), ''),
+       d.ordinality - 1
+FROM inventory_items i
+CROSS JOIN LATERAL jsonb_array_elements(i.specs->'__ff_inventory_item_details') WITH ORDINALITY AS d(detail, ordinality)
+WHERE jsonb_typeof(i.specs->'__ff_inventory_item_details') = 'array';
+
+UPDATE inventory_items
+SET specs = specs - '__ff_inventory_item_details'
+WHERE specs ? '__ff_inventory_item_details';
+`
+
+const migrationMaintenanceEvents = `
+CREATE TABLE IF NOT EXISTS maintenance_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    aircraft_id UUID NOT NULL REFERENCES aircraft(id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('crash', 'repair', 'maintenance')),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    repair_cost DECIMAL(10,2),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_events_aircraft ON maintenance_events(aircraft_id, occurred_at DESC);
+
+CREATE TABLE IF NOT EXISTS maintenance_event_components (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES maintenance_events(id) ON DELETE CASCADE,
+    category VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('damaged', 'repaired', 'replaced')),
+    old_inventory_item_id UUID REFERENCES inventory_items(id) ON DELETE SET NULL,
+    new_inventory_item_id UUID REFERENCES inventory_items(id) ON DELETE SET NULL,
+    unit_id UUID REFERENCES inventory_units(id) ON DELETE SET NULL,
+    unit_condition VARCHAR(20),
+    replaced_cost DECIMAL(10,2),
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_event_components_event ON maintenance_event_components(event_id, created_at);
+`
+
+const migrationFlightSessions = `
+CREATE TABLE IF NOT EXISTS flight_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    aircraft_id UUID NOT NULL REFERENCES aircraft(id) ON DELETE CASCADE,
+    flown_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    location VARCHAR(255),
+    flight_count INTEGER NOT NULL DEFAULT 1 CHECK (flight_count >= 0),
+    duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (duration_seconds >= 0),
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_flight_sessions_user ON flight_sessions(user_id, flown_at DESC);
+CREATE INDEX IF NOT EXISTS idx_flight_sessions_aircraft ON flight_sessions(aircraft_id, flown_at DESC);
+
+CREATE TABLE IF NOT EXISTS flight_session_batteries (
+    session_id UUID NOT NULL REFERENCES flight_sessions(id) ON DELETE CASCADE,
+    battery_id UUID NOT NULL REFERENCES batteries(id) ON DELETE CASCADE,
+    cycles INTEGER NOT NULL DEFAULT 1,
+    battery_log_id UUID REFERENCES battery_logs(id) ON DELETE SET NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (session_id, battery_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_flight_session_batteries_battery ON flight_session_batteries(battery_id);
+
+-- Components installed when the session was flown, for per-component flight time
+CREATE TABLE IF NOT EXISTS flight_session_components (
+    session_id UUID NOT NULL REFERENCES flight_sessions(id) ON DELETE CASCADE,
+    category VARCHAR(50) NOT NULL,
+    inventory_item_id UUID NOT NULL REFERENCES inventory_items(id) ON DELETE CASCADE,
+    PRIMARY KEY (session_id, category)
+);
+
+CREATE INDEX IF NOT EXISTS idx_flight_session_components_item ON flight_session_components(inventory_item_id);
+`
+
+// Migration linking orders to the inventory items they contain. An item is
+// in at most one order; received_at is set when that order is delivered.
+const migrationOrderItems = `
+CREATE TABLE IF NOT EXISTS order_items (
+    inventory_item_id UUID PRIMARY KEY REFERENCES inventory_items(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order ON order_items(order_id);
+
+-- Polling picks the least recently checked open orders
+CREATE INDEX IF NOT EXISTS idx_orders_last_checked ON orders(last_checked_at NULLS FIRST) WHERE archived = false;
+`
+
+// Migration adding personal access tokens. Only the SHA-256 of the token is
+// stored; token_prefix keeps its first characters for display.
+const migrationPersonalAccessTokens = `
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user ON personal_access_tokens(user_id);
+`
+
+const migrationDataExports = `
+CREATE TABLE IF NOT EXISTS data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    storage_key TEXT,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user ON data_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires ON data_exports(expires_at) WHERE status = 'ready';
+`
+
+const migrationDataImports = `
+CREATE TABLE IF NOT EXISTS data_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    result JSONB,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_imports_user ON data_imports(user_id, created_at DESC);
+`
+
+const migrationPasswordlessLogin = `
+-- WebAuthn credential IDs can be up to 1023 bytes, longer than a Google sub
+ALTER TABLE user_identities ALTER COLUMN provider_subject TYPE TEXT;
+
+CREATE TABLE IF NOT EXISTS email_login_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    consumed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_login_tokens_email ON email_login_tokens(email, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS webauthn_challenges (
+    challenge VARCHAR(255) PRIMARY KEY,
+    purpose VARCHAR(20) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS passkey_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    identity_id UUID NOT NULL UNIQUE REFERENCES user_identities(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id TEXT NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    name VARCHAR(100) NOT NULL,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_passkey_credentials_user ON passkey_credentials(user_id);
+`
+
+const migrationDataImportSources = `
+ALTER TABLE data_imports ADD COLUMN IF NOT EXISTS source_export_id VARCHAR(255);
+
+-- A failed import may be retried; any other import of the same archive is a duplicate
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_imports_source ON data_imports(user_id, source_export_id) WHERE status <> 'failed';
+`
@@ -487,6 +487,72 @@ func (s *FCConfigStore) ListTuningSnapshots(ctx context.Context, aircraftID stri
 	return snapshots, nil
 }
 
+// ListAllTuningSnapshots lists every tuning snapshot across a user's aircraft,
+// including tuning data and diff backups, oldest first
+func (s *FCConfigStore) ListAllTuningSnapshots(ctx context.Context, userID string) ([]models.AircraftTuningSnapshot, error) {
+	query := `
+		SELECT ts.id, ts.aircraft_id, ts.flight_controller_id, ts.flight_controller_config_id,
+			   ts.firmware_name, ts.firmware_version, ts.board_target, ts.board_name,
+			   ts.tuning_data, ts.parse_status, ts.parse_warnings, ts.notes, ts.diff_backup,
+			   ts.created_at, ts.updated_at
+		FROM aircraft_tuning_snapshots ts
+		INNER JOIN aircraft a ON a.id = ts.aircraft_id
+		WHERE a.user_id = $1
+		ORDER BY ts.created_at ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tuning snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]models.AircraftTuningSnapshot, 0)
+	for rows.Next() {
+		snapshot := models.AircraftTuningSnapshot{}
+		var fcID, configID, firmwareVersion, boardTarget, boardName, notes, diffBackup sql.NullString
+		var tuningData, parseWarnings []byte
+
+		err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.AircraftID,
+			&fcID,
+			&configID,
+			&snapshot.FirmwareName,
+			&firmwareVersion,
+			&boardTarget,
+			&boardName,
+			&tuningData,
+			&snapshot.ParseStatus,
+			&parseWarnings,
+			&notes,
+			&diffBackup,
+			&snapshot.CreatedAt,
+			&snapshot.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tuning snapshot: %w", err)
+		}
+
+		snapshot.FlightControllerID = fcID.String
+		snapshot.FlightControllerConfigID = configID.String
+		snapshot.FirmwareVersion = firmwareVersion.String
+		snapshot.BoardTarget = boardTarget.String
+		snapshot.BoardName = boardName.String
+		snapshot.Notes = notes.String
+		snapshot.DiffBackup = diffBackup.String
+		snapshot.TuningData = tuningData
+
+		if len(parseWarnings) > 0 {
+			_ = json.Unmarshal(parseWarnings, &snapshot.ParseWarnings)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
 // GetAircraftByFC finds an aircraft that has the given FC (inventory item) assigned
 func (s *FCConfigStore) GetAircraftByFC(ctx context.Context, userID string, inventoryItemID string) (*models.Aircraft, error) {
 	query := `
@@ -0,0 +1,395 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ErrFlightAircraftNotFound is returned when a session names an aircraft the user doesn't own.
+var ErrFlightAircraftNotFound = errors.New("aircraft not found")
+
+// ErrFlightBatteryNotFound is returned when a session names a battery the user doesn't own.
+var ErrFlightBatteryNotFound = errors.New("battery not found")
+
+// FlightStore handles flight session database operations
+type FlightStore struct {
+	db *DB
+}
+
+// NewFlightStore creates a new flight store
+func NewFlightStore(db *DB) *FlightStore {
+	return &FlightStore{db: db}
+}
+
+const flightSessionColumns = `fs.id, fs.user_id, fs.aircraft_id, COALESCE(a.name, ''), fs.flown_at, fs.location,
+	fs.flight_count, fs.duration_seconds, fs.notes, fs.created_at, fs.updated_at`
+
+// Create logs a flight session and snapshots the components installed on the
+// aircraft. Battery log IDs are filled in later with SetBatteryLog.
+func (s *FlightStore) Create(ctx context.Context, userID string, params models.CreateFlightSessionParams) (*models.FlightSession, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start flight session transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var aircraftName string
+	err = tx.QueryRowContext(ctx, `SELECT name FROM aircraft WHERE id = $1 AND user_id = $2`, params.AircraftID, userID).Scan(&aircraftName)
+	if err == sql.ErrNoRows {
+		return nil, ErrFlightAircraftNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify aircraft: %w", err)
+	}
+
+	batteryIDs := make([]string, 0, len(params.Batteries))
+	for _, battery := range params.Batteries {
+		batteryIDs = append(batteryIDs, battery.BatteryID)
+	}
+	if len(batteryIDs) > 0 {
+		var owned int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM batteries WHERE id::text = ANY($1) AND user_id = $2
+		`, pq.Array(batteryIDs), userID).Scan(&owned); err != nil {
+			return nil, fmt.Errorf("failed to verify batteries: %w", err)
+		}
+		if owned != len(batteryIDs) {
+			return nil, ErrFlightBatteryNotFound
+		}
+	}
+
+	flownAt := time.Now()
+	if params.FlownAt != nil && !params.FlownAt.IsZero() {
+		flownAt = *params.FlownAt
+	}
+
+	session := &models.FlightSession{UserID: userID, AircraftName: aircraftName}
+	var location, notes sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO flight_sessions (user_id, aircraft_id, flown_at, location, flight_count, duration_seconds, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, aircraft_id, flown_at, location, flight_count, duration_seconds, notes, created_at, updated_at
+	`, userID, params.AircraftID, flownAt, nullString(params.Location), params.FlightCount, params.DurationSeconds, nullString(params.Notes)).Scan(
+		&session.ID, &session.AircraftID, &session.FlownAt, &location,
+		&session.FlightCount, &session.DurationSeconds, &notes, &session.CreatedAt, &session.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flight session: %w", err)
+	}
+	session.Location = location.String
+	session.Notes = notes.String
+
+	for i, battery := range params.Batteries {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO flight_session_batteries (session_id, battery_id, cycles, position)
+			VALUES ($1, $2, $3, $4)
+		`, session.ID, battery.BatteryID, battery.Cycles, i); err != nil {
+			return nil, fmt.Errorf("failed to record flight session battery: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO flight_session_components (session_id, category, inventory_item_id)
+		SELECT $1, category, inventory_item_id
+		FROM aircraft_components
+		WHERE aircraft_id = $2 AND inventory_item_id IS NOT NULL
+		ON CONFLICT DO NOTHING
+	`, session.ID, params.AircraftID); err != nil {
+		return nil, fmt.Errorf("failed to snapshot flight session components: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit flight session: %w", err)
+	}
+
+	sessions := []models.FlightSession{*session}
+	if err := s.attachBatteries(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return &sessions[0], nil
+}
+
+// SetBatteryLog links the battery log created for a pack to its session
+func (s *FlightStore) SetBatteryLog(ctx context.Context, sessionID string, batteryID string, logID string) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE flight_session_batteries SET battery_log_id = $3
+		WHERE session_id = $1 AND battery_id = $2
+	`, sessionID, batteryID, logID); err != nil {
+		return fmt.Errorf("failed to link battery log: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a flight session by ID
+func (s *FlightStore) Get(ctx context.Context, id string, userID string) (*models.FlightSession, error) {
+	session, err := scanFlightSession(s.db.QueryRowContext(ctx, `
+		SELECT `+flightSessionColumns+`
+		FROM flight_sessions fs
+		LEFT JOIN aircraft a ON a.id = fs.aircraft_id
+		WHERE fs.id::text = $1 AND fs.user_id = $2
+	`, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight session: %w", err)
+	}
+
+	sessions := []models.FlightSession{*session}
+	if err := s.attachBatteries(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return &sessions[0], nil
+}
+
+// List lists a user's flight sessions, newest first
+func (s *FlightStore) List(ctx context.Context, userID string, params models.FlightSessionListParams) (*models.FlightSessionListResponse, error) {
+	where := `fs.user_id = $1`
+	args := []interface{}{userID}
+	if params.AircraftID != "" {
+		where += ` AND fs.aircraft_id::text = $2`
+		args = append(args, params.AircraftID)
+	}
+
+	var totalCount int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM flight_sessions fs WHERE `+where, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count flight sessions: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query := fmt.Sprintf(`
+		SELECT `+flightSessionColumns+`
+		FROM flight_sessions fs
+		LEFT JOIN aircraft a ON a.id = fs.aircraft_id
+		WHERE %s
+		ORDER BY fs.flown_at DESC, fs.created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, limit, params.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flight sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]models.FlightSession, 0)
+	for rows.Next() {
+		session, err := scanFlightSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan flight session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate flight sessions: %w", err)
+	}
+
+	if err := s.attachBatteries(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return &models.FlightSessionListResponse{Sessions: sessions, TotalCount: totalCount}, nil
+}
+
+// Delete removes a flight session
+func (s *FlightStore) Delete(ctx context.Context, id string, userID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM flight_sessions WHERE id::text = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete flight session: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("flight session not found")
+	}
+	return nil
+}
+
+// AircraftStats totals an aircraft's sessions and the flight time of each
+// installed component across every session it was flown in, on any aircraft
+func (s *FlightStore) AircraftStats(ctx context.Context, aircraftID string, userID string) (*models.AircraftFlightStats, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM aircraft WHERE id = $1 AND user_id = $2)`, aircraftID, userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to verify aircraft: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	stats := &models.AircraftFlightStats{AircraftID: aircraftID, Components: []models.ComponentFlightTime{}}
+	totals, err := scanFlightTotals(s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(flight_count), 0), COALESCE(SUM(duration_seconds), 0), MAX(flown_at)
+		FROM flight_sessions
+		WHERE aircraft_id = $1 AND user_id = $2
+	`, aircraftID, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to total aircraft flights: %w", err)
+	}
+	stats.FlightTotals = *totals
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ac.category, ac.inventory_item_id, COALESCE(ii.name, ''),
+		       COALESCE(SUM(fs.flight_count), 0), COALESCE(SUM(fs.duration_seconds), 0)
+		FROM aircraft_components ac
+		LEFT JOIN inventory_items ii ON ii.id = ac.inventory_item_id
+		LEFT JOIN flight_session_components fc ON fc.inventory_item_id = ac.inventory_item_id
+		LEFT JOIN flight_sessions fs ON fs.id = fc.session_id AND fs.user_id = $2
+		WHERE ac.aircraft_id = $1 AND ac.inventory_item_id IS NOT NULL
+		GROUP BY ac.category, ac.inventory_item_id, ii.name
+		ORDER BY ac.category
+	`, aircraftID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total component flight time: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var component models.ComponentFlightTime
+		var category string
+		if err := rows.Scan(&category, &component.InventoryItemID, &component.ItemName, &component.FlightCount, &component.DurationSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan component flight time: %w", err)
+		}
+		component.Category = models.ComponentCategory(category)
+		stats.Components = append(stats.Components, component)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate component flight time: %w", err)
+	}
+	return stats, nil
+}
+
+// PilotStats totals a user's flying overall and per aircraft
+func (s *FlightStore) PilotStats(ctx context.Context, userID string) (*models.PilotFlightStats, error) {
+	stats := &models.PilotFlightStats{ByAircraft: []models.AircraftFlightTotals{}}
+
+	totals, err := scanFlightTotals(s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(flight_count), 0), COALESCE(SUM(duration_seconds), 0), MAX(flown_at)
+		FROM flight_sessions
+		WHERE user_id = $1
+	`, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to total pilot flights: %w", err)
+	}
+	stats.FlightTotals = *totals
+
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT fb.battery_id)
+		FROM flight_session_batteries fb
+		JOIN flight_sessions fs ON fs.id = fb.session_id
+		WHERE fs.user_id = $1
+	`, userID).Scan(&stats.PacksUsed); err != nil {
+		return nil, fmt.Errorf("failed to count packs used: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT fs.aircraft_id, COALESCE(a.name, ''),
+		       COUNT(*), COALESCE(SUM(fs.flight_count), 0), COALESCE(SUM(fs.duration_seconds), 0), MAX(fs.flown_at)
+		FROM flight_sessions fs
+		LEFT JOIN aircraft a ON a.id = fs.aircraft_id
+		WHERE fs.user_id = $1
+		GROUP BY fs.aircraft_id, a.name
+		ORDER BY SUM(fs.duration_seconds) DESC, a.name
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total flights per aircraft: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var aircraft models.AircraftFlightTotals
+		var lastFlownAt sql.NullTime
+		if err := rows.Scan(
+			&aircraft.AircraftID, &aircraft.AircraftName,
+			&aircraft.SessionCount, &aircraft.FlightCount, &aircraft.DurationSeconds, &lastFlownAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan aircraft flight totals: %w", err)
+		}
+		if lastFlownAt.Valid {
+			aircraft.LastFlownAt = &lastFlownAt.Time
+		}
+		stats.ByAircraft = append(stats.ByAircraft, aircraft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate aircraft flight totals: %w", err)
+	}
+
+	stats.AircraftFlown = len(stats.ByAircraft)
+	return stats, nil
+}
+
+func (s *FlightStore) attachBatteries(ctx context.Context, sessions []models.FlightSession) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.FlightSession, len(sessions))
+	ids := make([]string, 0, len(sessions))
+	for i := range sessions {
+		sessions[i].Batteries = []models.FlightSessionBattery{}
+		byID[sessions[i].ID] = &sessions[i]
+		ids = append(ids, sessions[i].ID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT fb.session_id, fb.battery_id, COALESCE(b.name, ''), COALESCE(b.battery_code, ''), fb.cycles, fb.battery_log_id
+		FROM flight_session_batteries fb
+		LEFT JOIN batteries b ON b.id = fb.battery_id
+		WHERE fb.session_id::text = ANY($1)
+		ORDER BY fb.session_id, fb.position
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list flight session batteries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionID string
+		var battery models.FlightSessionBattery
+		var logID sql.NullString
+		if err := rows.Scan(&sessionID, &battery.BatteryID, &battery.BatteryName, &battery.BatteryCode, &battery.Cycles, &logID); err != nil {
+			return fmt.Errorf("failed to scan flight session battery: %w", err)
+		}
+		battery.BatteryLogID = logID.String
+		if session := byID[sessionID]; session != nil {
+			session.Batteries = append(session.Batteries, battery)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate flight session batteries: %w", err)
+	}
+	return nil
+}
+
+func scanFlightSession(row interface{ Scan(...any) error }) (*models.FlightSession, error) {
+	var session models.FlightSession
+	var location, notes sql.NullString
+	if err := row.Scan(
+		&session.ID, &session.UserID, &session.AircraftID, &session.AircraftName, &session.FlownAt, &location,
+		&session.FlightCount, &session.DurationSeconds, &notes, &session.CreatedAt, &session.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	session.Location = location.String
+	session.Notes = notes.String
+	return &session, nil
+}
+
+func scanFlightTotals(row *sql.Row) (*models.FlightTotals, error) {
+	var totals models.FlightTotals
+	var lastFlownAt sql.NullTime
+	if err := row.Scan(&totals.SessionCount, &totals.FlightCount, &totals.DurationSeconds, &lastFlownAt); err != nil {
+		return nil, err
+	}
+	if lastFlownAt.Valid {
+		totals.LastFlownAt = &lastFlownAt.Time
+	}
+	return &totals, nil
+}
@@ -0,0 +1,335 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+var ErrGalleryFull = fmt.Errorf("gallery is limited to %d images", models.MaxGalleryImages)
+var ErrGalleryImageNotFound = errors.New("gallery image not found")
+
+// sqlQueryer is satisfied by both *DB and *sql.Tx.
+type sqlQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// galleryTable describes an ordered image gallery hanging off a parent table.
+// The parent's image_asset_id column holds the cover image.
+type galleryTable struct {
+	table        string
+	parentTable  string
+	parentColumn string
+}
+
+var (
+	buildGallery    = galleryTable{table: "build_images", parentTable: "builds", parentColumn: "build_id"}
+	aircraftGallery = galleryTable{table: "aircraft_images", parentTable: "aircraft", parentColumn: "aircraft_id"}
+)
+
+// list returns the approved and awaiting-review images for a parent in display order.
+func (g galleryTable) list(ctx context.Context, q sqlQueryer, parentID string) ([]models.GalleryImage, error) {
+	query := fmt.Sprintf(`
+		SELECT gi.id, gi.image_asset_id, COALESCE(gi.caption, ''), gi.position, gi.created_at,
+		       COALESCE(gi.image_asset_id = p.image_asset_id, false), ia.status = 'PENDING_REVIEW'
+		FROM %[1]s gi
+		JOIN %[2]s p ON p.id = gi.%[3]s
+		JOIN image_assets ia ON ia.id = gi.image_asset_id AND ia.status IN ('APPROVED', 'PENDING_REVIEW')
+		WHERE gi.%[3]s = $1
+		ORDER BY gi.position, gi.created_at
+	`, g.table, g.parentTable, g.parentColumn)
+
+	rows, err := q.QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gallery images: %w", err)
+	}
+	defer rows.Close()
+
+	images := make([]models.GalleryImage, 0)
+	for rows.Next() {
+		var image models.GalleryImage
+		if err := rows.Scan(&image.ID, &image.ImageAssetID, &image.Caption, &image.Position, &image.CreatedAt, &image.IsCover, &image.Pending); err != nil {
+			return nil, fmt.Errorf("failed to scan gallery image: %w", err)
+		}
+		images = append(images, image)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate gallery images: %w", err)
+	}
+	return images, nil
+}
+
+// get returns one gallery image, or nil when the parent has no such image.
+func (g galleryTable) get(ctx context.Context, q sqlQueryer, parentID string, imageID string) (*models.GalleryImage, error) {
+	images, err := g.list(ctx, q, parentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		if images[i].ID == imageID {
+			return &images[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// imageRef locates the asset behind a gallery image. Images awaiting review
+// are only included when includePending is set; they are served as a placeholder.
+func (g galleryTable) imageRef(ctx context.Context, q sqlQueryer, parentID string, imageID string, includePending bool) (*models.ImageRef, error) {
+	query := fmt.Sprintf(`
+		SELECT ia.id, NULL::bytea, NULL::text
+		FROM %[1]s gi
+		JOIN image_assets ia ON ia.id = gi.image_asset_id
+		 AND (ia.status = 'APPROVED' OR ($3 AND ia.status = 'PENDING_REVIEW'))
+		WHERE gi.%[2]s = $1 AND gi.id::text = $2
+	`, g.table, g.parentColumn)
+
+	ref, err := scanImageRef(q.QueryRowContext(ctx, query, parentID, imageID, includePending))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gallery image: %w", err)
+	}
+	return ref, nil
+}
+
+// add appends an image to the end of the gallery. The first image becomes the cover.
+func (g galleryTable) add(ctx context.Context, tx *sql.Tx, parentID string, imageAssetID string, caption string) (*models.GalleryImage, error) {
+	// Lock the parent row so concurrent uploads cannot both pass the size check
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`SELECT 1 FROM %s WHERE id = $1 FOR UPDATE`, g.parentTable), parentID); err != nil {
+		return nil, fmt.Errorf("failed to lock gallery parent: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s = $1`, g.table, g.parentColumn), parentID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count gallery images: %w", err)
+	}
+	if count >= models.MaxGalleryImages {
+		return nil, ErrGalleryFull
+	}
+
+	image := models.GalleryImage{ImageAssetID: imageAssetID, Caption: caption}
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s, image_asset_id, caption, position)
+		SELECT $1, $2, $3, COALESCE(MAX(position) + 1, 0) FROM %[1]s WHERE %[2]s = $1
+		RETURNING id, position, created_at
+	`, g.table, g.parentColumn)
+	if err := tx.QueryRowContext(ctx, query, parentID, imageAssetID, nullString(caption)).Scan(&image.ID, &image.Position, &image.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to add gallery image: %w", err)
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf(`UPDATE %s SET image_asset_id = $2, updated_at = NOW() WHERE id = $1 AND image_asset_id IS NULL`, g.parentTable),
+		parentID,
+		imageAssetID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set gallery cover: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	image.IsCover = rows > 0
+
+	return &image, nil
+}
+
+// update changes a caption and/or makes the image the cover.
+// Setting IsCover to false is ignored; pick another image as cover instead.
+func (g galleryTable) update(ctx context.Context, tx *sql.Tx, parentID string, imageID string, params models.UpdateGalleryImageParams) error {
+	var imageAssetID string
+	if err := tx.QueryRowContext(
+		ctx,
+		fmt.Sprintf(`SELECT image_asset_id FROM %s WHERE %s = $1 AND id::text = $2`, g.table, g.parentColumn),
+		parentID,
+		imageID,
+	).Scan(&imageAssetID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrGalleryImageNotFound
+		}
+		return fmt.Errorf("failed to load gallery image: %w", err)
+	}
+
+	if params.Caption != nil {
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf(`UPDATE %s SET caption = $3 WHERE %s = $1 AND id::text = $2`, g.table, g.parentColumn),
+			parentID,
+			imageID,
+			nullString(*params.Caption),
+		); err != nil {
+			return fmt.Errorf("failed to update gallery caption: %w", err)
+		}
+	}
+
+	if params.IsCover != nil && *params.IsCover {
+		if _, err := tx.ExecContext(
+			ctx,
+			fmt.Sprintf(`UPDATE %s SET image_asset_id = $2, updated_at = NOW() WHERE id = $1`, g.parentTable),
+			parentID,
+			imageAssetID,
+		); err != nil {
+			return fmt.Errorf("failed to set gallery cover: %w", err)
+		}
+	} else if params.Caption != nil {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET updated_at = NOW() WHERE id = $1`, g.parentTable), parentID); err != nil {
+			return fmt.Errorf("failed to touch gallery parent: %w", err)
+		}
+	}
+	return nil
+}
+
+// reorder rewrites positions to match imageIDs, which must list every image once.
+func (g galleryTable) reorder(ctx context.Context, tx *sql.Tx, parentID string, imageIDs []string) error {
+	current, err := g.list(ctx, tx, parentID)
+	if err != nil {
+		return err
+	}
+	if err := models.ValidateGalleryOrder(current, imageIDs); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET position = $3 WHERE %s = $1 AND id::text = $2`, g.table, g.parentColumn)
+	for position, imageID := range imageIDs {
+		if _, err := tx.ExecContext(ctx, query, parentID, strings.TrimSpace(imageID), position); err != nil {
+			return fmt.Errorf("failed to reorder gallery images: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET updated_at = NOW() WHERE id = $1`, g.parentTable), parentID); err != nil {
+		return fmt.Errorf("failed to touch gallery parent: %w", err)
+	}
+	return nil
+}
+
+// remove deletes a gallery image and returns its asset ID. Removing the cover
+// promotes the next image in order.
+func (g galleryTable) remove(ctx context.Context, tx *sql.Tx, parentID string, imageID string) (string, error) {
+	var imageAssetID string
+	if err := tx.QueryRowContext(
+		ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND id::text = $2 RETURNING image_asset_id`, g.table, g.parentColumn),
+		parentID,
+		imageID,
+	).Scan(&imageAssetID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrGalleryImageNotFound
+		}
+		return "", fmt.Errorf("failed to remove gallery image: %w", err)
+	}
+
+	if err := g.promoteCover(ctx, tx, parentID, imageAssetID); err != nil {
+		return "", err
+	}
+	return imageAssetID, nil
+}
+
+// replaceCover swaps the gallery entry for previousAssetID with newAssetID,
+// keeping its position. Without a previous entry the new image goes first.
+func (g galleryTable) replaceCover(ctx context.Context, q sqlQueryer, parentID string, previousAssetID string, newAssetID string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s, image_asset_id, caption, position)
+		SELECT $1, $3,
+		       (SELECT caption FROM %[1]s WHERE %[2]s = $1 AND image_asset_id::text = $2 LIMIT 1),
+		       COALESCE(
+		           (SELECT position FROM %[1]s WHERE %[2]s = $1 AND image_asset_id::text = $2 LIMIT 1),
+		           (SELECT MIN(position) - 1 FROM %[1]s WHERE %[2]s = $1),
+		           0
+		       )
+	`, g.table, g.parentColumn)
+	if _, err := q.ExecContext(ctx, query, parentID, previousAssetID, newAssetID); err != nil {
+		return fmt.Errorf("failed to add cover to gallery: %w", err)
+	}
+
+	if previousAssetID != "" && previousAssetID != newAssetID {
+		if _, err := q.ExecContext(
+			ctx,
+			fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND image_asset_id = $2`, g.table, g.parentColumn),
+			parentID,
+			previousAssetID,
+		); err != nil {
+			return fmt.Errorf("failed to remove previous cover from gallery: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeCover drops the gallery entry for the cover asset and promotes the next image.
+func (g galleryTable) removeCover(ctx context.Context, q sqlQueryer, parentID string, coverAssetID string) error {
+	if coverAssetID == "" {
+		return nil
+	}
+	if _, err := q.ExecContext(
+		ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND image_asset_id = $2`, g.table, g.parentColumn),
+		parentID,
+		coverAssetID,
+	); err != nil {
+		return fmt.Errorf("failed to remove cover from gallery: %w", err)
+	}
+	return g.promoteCover(ctx, q, parentID, coverAssetID)
+}
+
+// promoteCover points the parent at the first remaining image when its cover was removedAssetID.
+func (g galleryTable) promoteCover(ctx context.Context, q sqlQueryer, parentID string, removedAssetID string) error {
+	query := fmt.Sprintf(`
+		UPDATE %[1]s
+		SET image_asset_id = (
+		        SELECT image_asset_id FROM %[2]s
+		        WHERE %[3]s = $1
+		        ORDER BY position, created_at
+		        LIMIT 1
+		    ),
+		    updated_at = NOW()
+		WHERE id = $1 AND image_asset_id = $2
+	`, g.parentTable, g.table, g.parentColumn)
+	if _, err := q.ExecContext(ctx, query, parentID, removedAssetID); err != nil {
+		return fmt.Errorf("failed to promote gallery cover: %w", err)
+	}
+	return nil
+}
+
+// imageAssetInUse reports whether any build, aircraft or gallery still points at
+// an asset. Published builds and their revision drafts share assets, so an asset
+// removed from one may still be live on the other.
+func imageAssetInUse(ctx context.Context, q sqlQueryer, imageAssetID string) (bool, error) {
+	var inUse bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM build_images WHERE image_asset_id = $1)
+		    OR EXISTS (SELECT 1 FROM aircraft_images WHERE image_asset_id = $1)
+		    OR EXISTS (SELECT 1 FROM builds WHERE image_asset_id = $1)
+		    OR EXISTS (SELECT 1 FROM aircraft WHERE image_asset_id = $1)
+	`, imageAssetID).Scan(&inUse)
+	if err != nil {
+		return false, fmt.Errorf("failed to check image asset references: %w", err)
+	}
+	return inUse, nil
+}
+
+// orphanedAssetID returns imageAssetID when nothing references it any more, so
+// callers only delete assets that are really gone.
+func orphanedAssetID(ctx context.Context, q sqlQueryer, imageAssetID string) (string, error) {
+	if imageAssetID == "" {
+		return "", nil
+	}
+	inUse, err := imageAssetInUse(ctx, q, imageAssetID)
+	if err != nil || inUse {
+		return "", err
+	}
+	return imageAssetID, nil
+}
+
+func galleryImageURL(base string, imageID string) string {
+	return fmt.Sprintf("%s/%s/image", base, imageID)
+}
+
+func setGalleryImageURLs(images []models.GalleryImage, base string) {
+	for i := range images {
+		images[i].ImageURL = galleryImageURL(base, images[i].ID)
+	}
+}
@@ -276,6 +276,12 @@ func (s *GearCatalogStore) Search(ctx context.Context, params models.GearCatalog
 		argIdx++
 	}
 
+	if bestFor := strings.TrimSpace(params.BestFor); bestFor != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(best_for) AS bf WHERE LOWER(bf) = LOWER($%d))", argIdx))
+		args = append(args, bestFor)
+		argIdx++
+	}
+
 	if params.Status != "" {
 		normalizedStatus := models.NormalizeCatalogStatus(params.Status)
 		if !models.IsValidCatalogStatus(normalizedStatus) {
@@ -1472,25 +1478,23 @@ func (s *GearCatalogStore) ApproveImage(ctx context.Context, id string, adminUse
 	return nil
 }
 
-// GetImage retrieves the binary image data for a gear catalog item
-func (s *GearCatalogStore) GetImage(ctx context.Context, id string) ([]byte, string, error) {
+// GetImage locates the uploaded image for a gear catalog item. Returns nil if it has none.
+func (s *GearCatalogStore) GetImage(ctx context.Context, id string) (*models.ImageRef, error) {
 	query := `
-		SELECT COALESCE(ia.image_bytes, gc.image_data), gc.image_type
+		SELECT ia.id, gc.image_data, gc.image_type
 		FROM gear_catalog gc
 		LEFT JOIN image_assets ia ON ia.id = gc.image_asset_id AND ia.status = 'APPROVED'
 		WHERE gc.id = $1 AND ((gc.image_asset_id IS NOT NULL AND ia.id IS NOT NULL) OR gc.image_data IS NOT NULL)
 	`
-	var imageData []byte
-	var imageType sql.NullString
-	err := s.db.QueryRowContext(ctx, query, id).Scan(&imageData, &imageType)
+	ref, err := scanImageRef(s.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, "", nil
+			return nil, nil
 		}
-		return nil, "", fmt.Errorf("failed to get gear image: %w", err)
+		return nil, fmt.Errorf("failed to get gear image: %w", err)
 	}
 
-	return imageData, imageType.String, nil
+	return ref, nil
 }
 
 // HasImage checks if a gear catalog item has an uploaded image
@@ -1,26 +1,51 @@
 package database
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/johnrirwin/flyingforge/internal/images"
 	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
 )
 
 // ImageAssetStore persists moderated image assets in PostgreSQL.
+// When an object store is configured, image bytes are kept there and
+// Postgres holds only the metadata and object key.
 type ImageAssetStore struct {
-	db *DB
+	db    *DB
+	blobs storage.ObjectStore
+}
+
+// NewImageAssetStore creates a new image asset store. blobs may be nil to keep
+// image bytes in Postgres.
+func NewImageAssetStore(db *DB, blobs storage.ObjectStore) *ImageAssetStore {
+	return &ImageAssetStore{db: db, blobs: blobs}
+}
+
+// imageObjectKey is the object storage key for an image asset.
+func imageObjectKey(ownerUserID, imageID string) string {
+	return fmt.Sprintf("images/%s/%s", ownerUserID, imageID)
 }
 
-// NewImageAssetStore creates a new image asset store.
-func NewImageAssetStore(db *DB) *ImageAssetStore {
-	return &ImageAssetStore{db: db}
+// derivativeObjectKey is the object storage key for a resized variant of an image asset.
+func derivativeObjectKey(ownerUserID, imageID string, size models.ImageSize) string {
+	return fmt.Sprintf("images/%s/%s-%s", ownerUserID, imageID, size)
 }
 
-// Save stores approved image bytes and moderation metadata.
+const imageAssetColumns = `id, owner_user_id, entity_type, entity_id, image_bytes, storage_key, status, moderation_labels, moderation_max_confidence, perceptual_hash, created_at, updated_at`
+
+// Save stores moderated image bytes and moderation metadata. Images are stored
+// APPROVED unless the request holds them for review.
 func (s *ImageAssetStore) Save(ctx context.Context, req images.SaveRequest) (*models.ImageAsset, error) {
 	if len(req.ImageBytes) == 0 {
 		return nil, fmt.Errorf("image bytes are required")
@@ -31,6 +56,12 @@ func (s *ImageAssetStore) Save(ctx context.Context, req images.SaveRequest) (*mo
 	if req.EntityType == "" {
 		req.EntityType = models.ImageEntityOther
 	}
+	if req.Status == "" {
+		req.Status = models.ImageModerationApproved
+	}
+	if req.Status != models.ImageModerationApproved && req.Status != models.ImageModerationPendingReview {
+		return nil, fmt.Errorf("cannot store an image with status %s", req.Status)
+	}
 
 	labelsJSON, err := json.Marshal(req.ModerationLabels)
 	if err != nil {
@@ -42,99 +73,484 @@ func (s *ImageAssetStore) Save(ctx context.Context, req images.SaveRequest) (*mo
 		entityID = sql.NullString{String: req.EntityID, Valid: true}
 	}
 
+	// Upload to object storage first so a row never points at a missing object
+	imageID := uuid.NewString()
+	var imageBytes []byte
+	storageKey := sql.NullString{}
+	var uploaded []string
+	cleanup := func() {
+		for _, key := range uploaded {
+			_ = s.blobs.Delete(ctx, key)
+		}
+	}
+	if s.blobs != nil {
+		storageKey = sql.NullString{String: imageObjectKey(req.OwnerUserID, imageID), Valid: true}
+		contentType := http.DetectContentType(req.ImageBytes)
+		if err := s.blobs.Put(ctx, storageKey.String, bytes.NewReader(req.ImageBytes), int64(len(req.ImageBytes)), contentType); err != nil {
+			return nil, fmt.Errorf("store image object: %w", err)
+		}
+		uploaded = append(uploaded, storageKey.String)
+
+		for i := range req.Derivatives {
+			derivative := &req.Derivatives[i]
+			key := derivativeObjectKey(req.OwnerUserID, imageID, derivative.Size)
+			if err := s.blobs.Put(ctx, key, bytes.NewReader(derivative.ImageBytes), int64(len(derivative.ImageBytes)), derivative.ContentType); err != nil {
+				cleanup()
+				return nil, fmt.Errorf("store image %s object: %w", derivative.Size, err)
+			}
+			uploaded = append(uploaded, key)
+			derivative.StorageKey = key
+		}
+	} else {
+		imageBytes = req.ImageBytes
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("begin image asset transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO image_assets (
+			id,
 			owner_user_id,
 			entity_type,
 			entity_id,
 			image_bytes,
+			storage_key,
 			status,
 			moderation_labels,
-			moderation_max_confidence
+			moderation_max_confidence,
+			queued_reason,
+			perceptual_hash
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, owner_user_id, entity_type, entity_id, image_bytes, status, moderation_labels, moderation_max_confidence, created_at, updated_at
-	`
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + imageAssetColumns
 
-	var asset models.ImageAsset
-	var status string
-	var scanEntityID sql.NullString
-	err = s.db.QueryRowContext(
+	asset, err := scanImageAsset(tx.QueryRowContext(
 		ctx,
 		query,
+		imageID,
 		req.OwnerUserID,
 		string(req.EntityType),
 		entityID,
-		req.ImageBytes,
-		string(models.ImageModerationApproved),
+		imageBytes,
+		storageKey,
+		string(req.Status),
 		labelsJSON,
 		req.ModerationMaxConfidence,
-	).Scan(
-		&asset.ID,
-		&asset.OwnerUserID,
-		&asset.EntityType,
-		&scanEntityID,
-		&asset.ImageBytes,
-		&status,
-		&asset.ModerationLabels,
-		&asset.ModerationMaxConfidence,
-		&asset.CreatedAt,
-		&asset.UpdatedAt,
-	)
+		nullString(req.QueuedReason),
+		nullHash(req.PerceptualHash),
+	))
 	if err != nil {
+		cleanup()
 		return nil, fmt.Errorf("save image asset: %w", err)
 	}
-	asset.Status = models.ImageModerationStatus(status)
-	if scanEntityID.Valid {
-		asset.EntityID = scanEntityID.String
+
+	for _, derivative := range req.Derivatives {
+		var inline []byte
+		if derivative.StorageKey == "" {
+			inline = derivative.ImageBytes
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO image_derivatives (image_id, size, content_type, width, height, byte_size, image_bytes, storage_key)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, imageID, string(derivative.Size), derivative.ContentType, derivative.Width, derivative.Height,
+			len(derivative.ImageBytes), inline, nullString(derivative.StorageKey))
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("save image %s derivative: %w", derivative.Size, err)
+		}
 	}
 
-	return &asset, nil
+	if err := tx.Commit(); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("commit image asset: %w", err)
+	}
+	asset.ImageBytes = req.ImageBytes
+
+	return asset, nil
 }
 
-// Load retrieves an image asset by ID.
+// Load retrieves an image asset by ID, reading its bytes from object storage if needed.
 func (s *ImageAssetStore) Load(ctx context.Context, imageID string) (*models.ImageAsset, error) {
-	query := `
-		SELECT id, owner_user_id, entity_type, entity_id, image_bytes, status, moderation_labels, moderation_max_confidence, created_at, updated_at
+	stream, err := s.Open(ctx, imageID)
+	if err != nil || stream == nil {
+		return nil, err
+	}
+	defer stream.Body.Close()
+
+	if stream.Asset.StorageKey != "" {
+		data, err := io.ReadAll(stream.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read image object: %w", err)
+		}
+		stream.Asset.ImageBytes = data
+	}
+	return stream.Asset, nil
+}
+
+// ListByOwner returns metadata for a user's stored images, oldest first.
+// Rejected images are skipped and image bytes are not loaded.
+func (s *ImageAssetStore) ListByOwner(ctx context.Context, ownerUserID string) ([]models.ImageAsset, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, owner_user_id, entity_type, entity_id, status, created_at, updated_at
 		FROM image_assets
-		WHERE id = $1
-	`
+		WHERE owner_user_id = $1 AND status <> 'REJECTED'
+		ORDER BY created_at, id
+	`, ownerUserID)
+	if err != nil {
+		return nil, fmt.Errorf("list owner images: %w", err)
+	}
+	defer rows.Close()
+
+	assets := []models.ImageAsset{}
+	for rows.Next() {
+		var asset models.ImageAsset
+		var status string
+		var entityID sql.NullString
+		if err := rows.Scan(&asset.ID, &asset.OwnerUserID, &asset.EntityType, &entityID, &status, &asset.CreatedAt, &asset.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan owner image: %w", err)
+		}
+		asset.EntityID = entityID.String
+		asset.Status = models.ImageModerationStatus(status)
+		assets = append(assets, asset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list owner images: %w", err)
+	}
+	return assets, nil
+}
+
+// Open returns an image asset with a streaming body. Returns nil, nil if the asset does not exist.
+func (s *ImageAssetStore) Open(ctx context.Context, imageID string) (*images.ImageStream, error) {
+	asset, err := s.loadRow(ctx, imageID)
+	if err != nil || asset == nil {
+		return nil, err
+	}
+
+	if asset.StorageKey == "" {
+		return &images.ImageStream{
+			Asset: asset,
+			Body:  io.NopCloser(bytes.NewReader(asset.ImageBytes)),
+			Size:  int64(len(asset.ImageBytes)),
+		}, nil
+	}
 
+	if s.blobs == nil {
+		return nil, fmt.Errorf("image %s is in object storage but no object store is configured", imageID)
+	}
+	body, info, err := s.blobs.Get(ctx, asset.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("open image object: %w", err)
+	}
+	return &images.ImageStream{Asset: asset, Body: body, Size: info.Size}, nil
+}
+
+// OpenDerivative streams a resized variant of an image asset.
+// Returns nil, nil if the asset or the requested size does not exist.
+func (s *ImageAssetStore) OpenDerivative(ctx context.Context, imageID string, size models.ImageSize) (*images.ImageStream, error) {
+	asset, err := s.loadRow(ctx, imageID)
+	if err != nil || asset == nil {
+		return nil, err
+	}
+
+	var derivative models.ImageDerivative
+	var byteSize int64
+	var scanStorageKey sql.NullString
+	err = s.db.QueryRowContext(ctx, `
+		SELECT content_type, width, height, byte_size, image_bytes, storage_key
+		FROM image_derivatives
+		WHERE image_id = $1 AND size = $2
+	`, imageID, string(size)).Scan(
+		&derivative.ContentType,
+		&derivative.Width,
+		&derivative.Height,
+		&byteSize,
+		&derivative.ImageBytes,
+		&scanStorageKey,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load image derivative: %w", err)
+	}
+
+	// Derivatives are never rewritten, so the image ID and size identify the bytes
+	stream := &images.ImageStream{
+		Asset:       asset,
+		Size:        byteSize,
+		ContentType: derivative.ContentType,
+		ETag:        fmt.Sprintf(`"%s-%s"`, imageID, size),
+	}
+	if !scanStorageKey.Valid {
+		stream.Body = io.NopCloser(bytes.NewReader(derivative.ImageBytes))
+		return stream, nil
+	}
+
+	if s.blobs == nil {
+		return nil, fmt.Errorf("image %s is in object storage but no object store is configured", imageID)
+	}
+	body, _, err := s.blobs.Get(ctx, scanStorageKey.String)
+	if err != nil {
+		return nil, fmt.Errorf("open image derivative object: %w", err)
+	}
+	stream.Body = body
+	return stream, nil
+}
+
+// PresignURL returns a short-lived direct download URL for an image kept in object storage.
+func (s *ImageAssetStore) PresignURL(ctx context.Context, imageID string, ttl time.Duration) (string, error) {
+	asset, err := s.loadRow(ctx, imageID)
+	if err != nil {
+		return "", err
+	}
+	if asset == nil {
+		return "", fmt.Errorf("image not found")
+	}
+	if asset.StorageKey == "" || s.blobs == nil {
+		return "", images.ErrDirectURLUnsupported
+	}
+
+	url, err := s.blobs.PresignGet(ctx, asset.StorageKey, ttl, "")
+	if errors.Is(err, storage.ErrPresignUnsupported) {
+		return "", images.ErrDirectURLUnsupported
+	}
+	return url, err
+}
+
+func (s *ImageAssetStore) loadRow(ctx context.Context, imageID string) (*models.ImageAsset, error) {
+	query := `SELECT ` + imageAssetColumns + ` FROM image_assets WHERE id = $1`
+
+	asset, err := scanImageAsset(s.db.QueryRowContext(ctx, query, imageID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load image asset: %w", err)
+	}
+	return asset, nil
+}
+
+// scanImageRef scans an (asset id, inline bytes, content type) row. Inline bytes
+// are only kept when the entity has no approved image asset.
+func scanImageRef(row *sql.Row) (*models.ImageRef, error) {
+	var assetID, imageType sql.NullString
+	var data []byte
+	if err := row.Scan(&assetID, &data, &imageType); err != nil {
+		return nil, err
+	}
+
+	ref := &models.ImageRef{AssetID: assetID.String, ContentType: imageType.String}
+	if !assetID.Valid {
+		ref.Data = data
+	}
+	return ref, nil
+}
+
+func scanImageAsset(row *sql.Row) (*models.ImageAsset, error) {
 	var asset models.ImageAsset
 	var status string
-	var scanEntityID sql.NullString
-	err := s.db.QueryRowContext(ctx, query, imageID).Scan(
+	var scanEntityID, scanStorageKey sql.NullString
+	var scanHash sql.NullInt64
+	err := row.Scan(
 		&asset.ID,
 		&asset.OwnerUserID,
 		&asset.EntityType,
 		&scanEntityID,
 		&asset.ImageBytes,
+		&scanStorageKey,
 		&status,
 		&asset.ModerationLabels,
 		&asset.ModerationMaxConfidence,
+		&scanHash,
 		&asset.CreatedAt,
 		&asset.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
-		return nil, fmt.Errorf("load image asset: %w", err)
+		return nil, err
 	}
 
 	asset.Status = models.ImageModerationStatus(status)
-	if scanEntityID.Valid {
-		asset.EntityID = scanEntityID.String
+	asset.EntityID = scanEntityID.String
+	asset.StorageKey = scanStorageKey.String
+	if scanHash.Valid {
+		hash := uint64(scanHash.Int64)
+		asset.PerceptualHash = &hash
 	}
-
 	return &asset, nil
 }
 
-// Delete removes an image asset by ID.
+// Delete removes an image asset by ID, including its stored objects.
 func (s *ImageAssetStore) Delete(ctx context.Context, imageID string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM image_assets WHERE id = $1`, imageID)
+	// Derivative rows cascade with the asset, so collect their object keys first
+	derivativeKeys := []string{}
+	rows, err := s.db.QueryContext(ctx, `SELECT storage_key FROM image_derivatives WHERE image_id = $1 AND storage_key IS NOT NULL`, imageID)
+	if err != nil {
+		return fmt.Errorf("list image derivatives: %w", err)
+	}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan image derivative: %w", err)
+		}
+		derivativeKeys = append(derivativeKeys, key)
+	}
+	rows.Close()
+
+	var storageKey sql.NullString
+	err = s.db.QueryRowContext(ctx, `DELETE FROM image_assets WHERE id = $1 RETURNING storage_key`, imageID).Scan(&storageKey)
+	if err == sql.ErrNoRows {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("delete image asset: %w", err)
 	}
+
+	if s.blobs == nil {
+		return nil
+	}
+	if storageKey.Valid {
+		derivativeKeys = append(derivativeKeys, storageKey.String)
+	}
+	for _, key := range derivativeKeys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete image object: %w", err)
+		}
+	}
 	return nil
 }
+
+// CountInDatabase counts image assets whose bytes are still stored in Postgres.
+func (s *ImageAssetStore) CountInDatabase(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM image_assets WHERE storage_key IS NULL AND image_bytes IS NOT NULL`,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count image assets: %w", err)
+	}
+	return count, nil
+}
+
+// MoveToObjectStore copies up to limit database-held images into the object store
+// and clears their bytes from Postgres. Returns how many images were moved.
+// Derivatives are moved separately by MoveDerivativesToObjectStore.
+func (s *ImageAssetStore) MoveToObjectStore(ctx context.Context, limit int) (int, error) {
+	if s.blobs == nil {
+		return 0, fmt.Errorf("no object store configured")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, owner_user_id, image_bytes
+		FROM image_assets
+		WHERE storage_key IS NULL AND image_bytes IS NOT NULL
+		ORDER BY created_at ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("list image assets to move: %w", err)
+	}
+
+	type pendingImage struct {
+		id, ownerUserID string
+		data            []byte
+	}
+	var pending []pendingImage
+	for rows.Next() {
+		var img pendingImage
+		if err := rows.Scan(&img.id, &img.ownerUserID, &img.data); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan image asset: %w", err)
+		}
+		pending = append(pending, img)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list image assets to move: %w", err)
+	}
+
+	moved := 0
+	for _, img := range pending {
+		key := imageObjectKey(img.ownerUserID, img.id)
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(img.data), int64(len(img.data)), http.DetectContentType(img.data)); err != nil {
+			return moved, fmt.Errorf("store image %s: %w", img.id, err)
+		}
+		_, err := s.db.ExecContext(ctx, `
+			UPDATE image_assets
+			SET storage_key = $2, image_bytes = NULL, updated_at = NOW()
+			WHERE id = $1 AND storage_key IS NULL
+		`, img.id, key)
+		if err != nil {
+			return moved, fmt.Errorf("update image %s: %w", img.id, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// MoveDerivativesToObjectStore copies up to limit database-held image derivatives
+// into the object store. Returns how many derivatives were moved.
+func (s *ImageAssetStore) MoveDerivativesToObjectStore(ctx context.Context, limit int) (int, error) {
+	if s.blobs == nil {
+		return 0, fmt.Errorf("no object store configured")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.image_id, a.owner_user_id, d.size, d.content_type, d.image_bytes
+		FROM image_derivatives d
+		JOIN image_assets a ON a.id = d.image_id
+		WHERE d.storage_key IS NULL AND d.image_bytes IS NOT NULL
+		ORDER BY d.created_at ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("list image derivatives to move: %w", err)
+	}
+
+	type pendingDerivative struct {
+		imageID, ownerUserID, size, contentType string
+		data                                    []byte
+	}
+	var pending []pendingDerivative
+	for rows.Next() {
+		var d pendingDerivative
+		if err := rows.Scan(&d.imageID, &d.ownerUserID, &d.size, &d.contentType, &d.data); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan image derivative: %w", err)
+		}
+		pending = append(pending, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list image derivatives to move: %w", err)
+	}
+
+	moved := 0
+	for _, d := range pending {
+		key := derivativeObjectKey(d.ownerUserID, d.imageID, models.ImageSize(d.size))
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(d.data), int64(len(d.data)), d.contentType); err != nil {
+			return moved, fmt.Errorf("store image %s %s: %w", d.imageID, d.size, err)
+		}
+		_, err := s.db.ExecContext(ctx, `
+			UPDATE image_derivatives
+			SET storage_key = $3, image_bytes = NULL
+			WHERE image_id = $1 AND size = $2 AND storage_key IS NULL
+		`, d.imageID, d.size, key)
+		if err != nil {
+			return moved, fmt.Errorf("update image %s %s: %w", d.imageID, d.size, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// imageUsagesCTE lists hashed images where they are shown: the current gear
+// catalog image and every build gallery image. Temp builds are left out.
+const imageUsagesCTE = `
+	WITH usages AS (
+		SELECT a.id AS image_id, a.perceptual_hash, 'gear' AS entity_type, gc.id::text AS entity_id,
+		       TRIM(gc.brand || ' ' || gc.model || COALESCE(' ' || gc.variant, '')) AS name,
+		       COALESCE(gc.created_by_user_id::text, '') AS owner_user_id, a.created_at
+		FROM gear_catalog gc
+		JOIN image_assets a ON a.id = gc.image_asset_id
+		WHERE a.perceptual_hash IS NOT NULL AND a.status IN ('APPROVED', 'PENDING_REVIEW')
+		UNION ALL
+		SELECT a.id, a.perceptual_hash, 'build', b.id::text, b.title,
+		       COALESCE(b.owner_user_id::text, ''), a.created_at
+		FROM builds b
+		JOIN build_images bi ON bi.build_id = b.id
+		JOIN image_assets a ON a.id = bi.image_asset_id
+		WHERE a.perceptual_hash IS NOT NULL AND a.status IN ('APPROVED', 'PENDING_REVIEW')
+		  AND b.status <> 'TEMP'
+	)`
+
+const imageUsageColumns = `image_id, entity_type, entity_id, name, owner_user_id, created_at`
+
+// ListDuplicates pairs images on different entities whose perceptual hashes
+// are within params.MaxDistance bits. It compares every pair of usages, which
+// is fine at catalog scale for an admin-only report.
+func (s *ImageAssetStore) ListDuplicates(ctx context.Context, params models.ImageDuplicateListParams) ([]models.ImageDuplicatePair, error) {
+	query := imageUsagesCTE + `
+		SELECT u1.image_id, u1.entity_type, u1.entity_id, u1.name, u1.owner_user_id, u1.created_at,
+		       u2.image_id, u2.entity_type, u2.entity_id, u2.name, u2.owner_user_id, u2.created_at,
+		       bit_count((u1.perceptual_hash # u2.perceptual_hash)::bit(64)) AS distance
+		FROM usages u1
+		JOIN usages u2 ON (u1.created_at, u1.image_id) < (u2.created_at, u2.image_id)
+		WHERE bit_count((u1.perceptual_hash # u2.perceptual_hash)::bit(64)) <= $1
+		  AND NOT (u1.entity_type = u2.entity_type AND u1.entity_id = u2.entity_id)
+		  AND NOT (u1.entity_type = 'build' AND u2.entity_type = 'build'
+		           AND u1.owner_user_id <> '' AND u1.owner_user_id = u2.owner_user_id)
+		  AND ($2 = '' OR u1.entity_type = $2 OR u2.entity_type = $2)
+		ORDER BY distance ASC, u2.created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := s.db.QueryContext(ctx, query, params.MaxDistance, string(params.EntityType), params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate images: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := make([]models.ImageDuplicatePair, 0)
+	for rows.Next() {
+		var pair models.ImageDuplicatePair
+		err := rows.Scan(
+			&pair.Original.ImageID, &pair.Original.EntityType, &pair.Original.EntityID,
+			&pair.Original.Name, &pair.Original.OwnerUserID, &pair.Original.CreatedAt,
+			&pair.Duplicate.ImageID, &pair.Duplicate.EntityType, &pair.Duplicate.EntityID,
+			&pair.Duplicate.Name, &pair.Duplicate.OwnerUserID, &pair.Duplicate.CreatedAt,
+			&pair.Distance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate image: %w", err)
+		}
+		pair.Original.ImageURL = reviewImageURL(pair.Original.ImageID)
+		pair.Duplicate.ImageURL = reviewImageURL(pair.Duplicate.ImageID)
+		pairs = append(pairs, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate duplicate images: %w", err)
+	}
+	return pairs, nil
+}
+
+// FindSimilar lists images on entities of entityType, other than
+// excludeEntityID, within maxDistance bits of imageID's hash.
+func (s *ImageAssetStore) FindSimilar(ctx context.Context, imageID string, entityType models.ImageEntityType, excludeEntityID string, maxDistance, limit int) ([]models.ImageDuplicateMatch, error) {
+	query := imageUsagesCTE + `
+		SELECT ` + imageUsageColumns + `, distance
+		FROM (
+			SELECT u.*, bit_count((u.perceptual_hash # a.perceptual_hash)::bit(64)) AS distance
+			FROM usages u
+			JOIN image_assets a ON a.id::text = $1 AND a.perceptual_hash IS NOT NULL
+			WHERE u.image_id <> a.id
+			  AND u.entity_type = $2
+			  AND u.entity_id <> $3
+		) matches
+		WHERE distance <= $4
+		ORDER BY distance ASC, created_at ASC
+		LIMIT $5`
+
+	rows, err := s.db.QueryContext(ctx, query, imageID, string(entityType), excludeEntityID, maxDistance, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar images: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]models.ImageDuplicateMatch, 0)
+	for rows.Next() {
+		var match models.ImageDuplicateMatch
+		err := rows.Scan(&match.ImageID, &match.EntityType, &match.EntityID, &match.Name,
+			&match.OwnerUserID, &match.CreatedAt, &match.Distance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan similar image: %w", err)
+		}
+		match.ImageURL = reviewImageURL(match.ImageID)
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate similar images: %w", err)
+	}
+	return matches, nil
+}
+
+// ListUnhashed returns IDs of stored images that have no perceptual hash yet
+// and haven't been marked unhashable.
+func (s *ImageAssetStore) ListUnhashed(ctx context.Context, afterID string, limit int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id::text
+		FROM image_assets
+		WHERE perceptual_hash IS NULL
+		  AND hash_attempted_at IS NULL
+		  AND status <> 'REJECTED'
+		  AND id::text > $1
+		ORDER BY id::text
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unhashed images: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, limit)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan unhashed image: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list unhashed images: %w", err)
+	}
+	return ids, nil
+}
+
+// SetPerceptualHash stores the perceptual hash of an image asset.
+func (s *ImageAssetStore) SetPerceptualHash(ctx context.Context, imageID string, hash uint64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE image_assets SET perceptual_hash = $2 WHERE id::text = $1`, imageID, int64(hash))
+	if err != nil {
+		return fmt.Errorf("failed to set perceptual hash: %w", err)
+	}
+	return nil
+}
+
+// MarkUnhashable records that an image could not be decoded for hashing.
+func (s *ImageAssetStore) MarkUnhashable(ctx context.Context, imageID string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE image_assets SET hash_attempted_at = NOW() WHERE id::text = $1`, imageID)
+	if err != nil {
+		return fmt.Errorf("failed to mark image unhashable: %w", err)
+	}
+	return nil
+}
+
+// nullHash converts a perceptual hash to the signed BIGINT Postgres stores.
+func nullHash(hash *uint64) sql.NullInt64 {
+	if hash == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*hash), Valid: true}
+}
+
+var _ images.DuplicateStorage = (*ImageAssetStore)(nil)
@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const imageReviewColumns = `id, owner_user_id, entity_type, entity_id, status, COALESCE(queued_reason, ''),
+	moderation_labels, moderation_max_confidence, COALESCE(review_reason, ''),
+	reviewed_by_user_id, reviewed_at, created_at`
+
+// ListReviews returns image assets in the review queue. PENDING_REVIEW lists
+// the open queue, oldest first; other statuses list decided reviews, newest first.
+func (s *ImageAssetStore) ListReviews(ctx context.Context, params models.ImageReviewListParams) ([]models.ImageReview, int, error) {
+	where := `WHERE status = $1`
+	order := `ORDER BY created_at ASC`
+	if params.Status != models.ImageModerationPendingReview {
+		where += ` AND reviewed_at IS NOT NULL`
+		order = `ORDER BY reviewed_at DESC`
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM image_assets `+where, string(params.Status)).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count image reviews: %w", err)
+	}
+
+	query := `SELECT ` + imageReviewColumns + ` FROM image_assets ` + where + ` ` + order + ` LIMIT $2 OFFSET $3`
+	rows, err := s.db.QueryContext(ctx, query, string(params.Status), params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list image reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]models.ImageReview, 0)
+	for rows.Next() {
+		review, err := scanImageReview(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan image review: %w", err)
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate image reviews: %w", err)
+	}
+	return reviews, total, nil
+}
+
+// GetReview returns the review record for an image asset, or nil if it does not exist.
+func (s *ImageAssetStore) GetReview(ctx context.Context, imageID string) (*models.ImageReview, error) {
+	review, err := scanImageReview(s.db.QueryRowContext(ctx, `SELECT `+imageReviewColumns+` FROM image_assets WHERE id::text = $1`, imageID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image review: %w", err)
+	}
+	return review, nil
+}
+
+// ApproveReview marks a PENDING_REVIEW image approved, making it visible
+// wherever it is already attached.
+func (s *ImageAssetStore) ApproveReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin image review: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, found, err := lockPendingReview(ctx, tx, imageID)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	review, err := scanImageReview(tx.QueryRowContext(ctx, `
+		UPDATE image_assets
+		SET status = $1,
+		    review_reason = $2,
+		    reviewed_by_user_id = $3,
+		    reviewed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $4
+		RETURNING `+imageReviewColumns,
+		string(models.ImageModerationApproved), nullString(reason), nullString(reviewerUserID), imageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve image: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit image review: %w", err)
+	}
+	return review, nil
+}
+
+// RejectReview marks a PENDING_REVIEW image rejected. It is detached from every
+// entity (the next gallery image becomes the cover) and its bytes and
+// derivatives are dropped; the row stays as the record of the decision.
+func (s *ImageAssetStore) RejectReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin image review: %w", err)
+	}
+	defer tx.Rollback()
+
+	objectKeys, found, err := lockPendingReview(ctx, tx, imageID)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	detach := []string{
+		`DELETE FROM build_images WHERE image_asset_id = $1`,
+		`DELETE FROM aircraft_images WHERE image_asset_id = $1`,
+		`UPDATE builds b
+		 SET image_asset_id = (
+		         SELECT bi.image_asset_id FROM build_images bi
+		         WHERE bi.build_id = b.id
+		         ORDER BY bi.position, bi.created_at
+		         LIMIT 1
+		     ),
+		     updated_at = NOW()
+		 WHERE b.image_asset_id = $1`,
+		`UPDATE aircraft a
+		 SET image_asset_id = (
+		         SELECT ai.image_asset_id FROM aircraft_images ai
+		         WHERE ai.aircraft_id = a.id
+		         ORDER BY ai.position, ai.created_at
+		         LIMIT 1
+		     ),
+		     updated_at = NOW()
+		 WHERE a.image_asset_id = $1`,
+		`UPDATE users
+		 SET avatar_image_asset_id = NULL,
+		     custom_avatar_url = NULL,
+		     avatar_type = '` + string(models.AvatarTypeGoogle) + `',
+		     updated_at = NOW()
+		 WHERE avatar_image_asset_id = $1`,
+		`UPDATE gear_catalog
+		 SET image_asset_id = NULL,
+		     image_type = CASE WHEN image_data IS NULL THEN NULL ELSE image_type END,
+		     image_status = CASE WHEN image_data IS NULL THEN '` + string(models.ImageStatusMissing) + `' ELSE image_status END,
+		     updated_at = NOW()
+		 WHERE image_asset_id = $1`,
+		`DELETE FROM image_derivatives WHERE image_id = $1`,
+	}
+	for _, statement := range detach {
+		if _, err := tx.ExecContext(ctx, statement, imageID); err != nil {
+			return nil, fmt.Errorf("failed to detach rejected image: %w", err)
+		}
+	}
+
+	review, err := scanImageReview(tx.QueryRowContext(ctx, `
+		UPDATE image_assets
+		SET status = $1,
+		    image_bytes = ''::bytea,
+		    storage_key = NULL,
+		    review_reason = $2,
+		    reviewed_by_user_id = $3,
+		    reviewed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $4
+		RETURNING `+imageReviewColumns,
+		string(models.ImageModerationRejected), nullString(reason), nullString(reviewerUserID), imageID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject image: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit image review: %w", err)
+	}
+
+	if s.blobs != nil {
+		for _, key := range objectKeys {
+			if err := s.blobs.Delete(ctx, key); err != nil {
+				return review, fmt.Errorf("delete rejected image object: %w", err)
+			}
+		}
+	}
+	return review, nil
+}
+
+// lockPendingReview locks an image asset that is awaiting review and returns the
+// object storage keys of its bytes and derivatives. found is false when the
+// image does not exist.
+func lockPendingReview(ctx context.Context, tx *sql.Tx, imageID string) (keys []string, found bool, err error) {
+	var status string
+	var storageKey sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT status, storage_key FROM image_assets WHERE id::text = $1 FOR UPDATE`, imageID).Scan(&status, &storageKey)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to lock image for review: %w", err)
+	}
+	if models.ImageModerationStatus(status) != models.ImageModerationPendingReview {
+		return nil, true, images.ErrNotPendingReview
+	}
+
+	keys = []string{}
+	if storageKey.Valid {
+		keys = append(keys, storageKey.String)
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT storage_key FROM image_derivatives WHERE image_id::text = $1 AND storage_key IS NOT NULL`, imageID)
+	if err != nil {
+		return nil, true, fmt.Errorf("list image derivatives: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, true, fmt.Errorf("scan image derivative: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, true, fmt.Errorf("list image derivatives: %w", err)
+	}
+	return keys, true, nil
+}
+
+func scanImageReview(row interface{ Scan(...any) error }) (*models.ImageReview, error) {
+	var review models.ImageReview
+	var status string
+	var labels []byte
+	var entityID, reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+	err := row.Scan(
+		&review.ImageID,
+		&review.OwnerUserID,
+		&review.EntityType,
+		&entityID,
+		&status,
+		&review.QueuedReason,
+		&labels,
+		&review.MaxConfidence,
+		&review.ReviewReason,
+		&reviewedBy,
+		&reviewedAt,
+		&review.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = models.ImageModerationStatus(status)
+	review.EntityID = entityID.String
+	review.ReviewedByUserID = reviewedBy.String
+	if reviewedAt.Valid {
+		t := reviewedAt.Time.UTC()
+		review.ReviewedAt = &t
+	}
+	review.Labels = []models.ModerationLabel{}
+	if len(labels) > 0 {
+		_ = json.Unmarshal(labels, &review.Labels)
+	}
+	if review.Status != models.ImageModerationRejected {
+		review.ImageURL = reviewImageURL(review.ImageID)
+	}
+	return &review, nil
+}
+
+// reviewImageURL is the admin route that serves an image's real bytes,
+// whatever its review status.
+func reviewImageURL(imageID string) string {
+	return fmt.Sprintf("/api/admin/images/%s/image", imageID)
+}
+
+var _ images.ReviewStorage = (*ImageAssetStore)(nil)
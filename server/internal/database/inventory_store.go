@@ -222,6 +222,13 @@ func (s *InventoryStore) Get(ctx context.Context, id string, userID string) (*mo
 		item.PurchasePrice = &purchasePrice.Float64
 	}
 
+	if err := s.attachUnits(ctx, []*models.InventoryItem{item}); err != nil {
+		return nil, err
+	}
+	if err := s.attachOrders(ctx, []*models.InventoryItem{item}); err != nil {
+		return nil, err
+	}
+
 	return item, nil
 }
 
@@ -341,6 +348,20 @@ func (s *InventoryStore) List(ctx context.Context, userID string, params models.
 		items = append(items, item)
 		categories[item.Category]++
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate inventory: %w", err)
+	}
+
+	itemPtrs := make([]*models.InventoryItem, len(items))
+	for i := range items {
+		itemPtrs[i] = &items[i]
+	}
+	if err := s.attachUnits(ctx, itemPtrs); err != nil {
+		return nil, err
+	}
+	if err := s.attachOrders(ctx, itemPtrs); err != nil {
+		return nil, err
+	}
 
 	return &models.InventoryResponse{
 		Items:      items,
@@ -480,7 +501,12 @@ func (s *InventoryStore) GetSummary(ctx context.Context, userID string) (*models
 		ByCategory: make(map[models.EquipmentCategory]int),
 	}
 
-	query := `SELECT category, quantity, purchase_price, specs FROM inventory_items`
+	unitsByItem, err := s.summaryUnits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, category, quantity, purchase_price FROM inventory_items`
 	args := []interface{}{}
 	if userID != "" {
 		query += " WHERE user_id = $1"
@@ -494,12 +520,12 @@ func (s *InventoryStore) GetSummary(ctx context.Context, userID string) (*models
 	defer rows.Close()
 
 	for rows.Next() {
+		var id string
 		var category models.EquipmentCategory
 		var quantity int
 		var purchasePrice sql.NullFloat64
-		var specs json.RawMessage
 
-		if err := rows.Scan(&category, &quantity, &purchasePrice, &specs); err != nil {
+		if err := rows.Scan(&id, &category, &quantity, &purchasePrice); err != nil {
 			return nil, fmt.Errorf("failed to scan inventory summary row: %w", err)
 		}
 
@@ -512,7 +538,7 @@ func (s *InventoryStore) GetSummary(ctx context.Context, userID string) (*models
 			pricePtr = &price
 		}
 
-		summary.TotalValue += models.CalculateInventoryItemTotalValue(quantity, pricePtr, specs)
+		summary.TotalValue += models.CalculateInventoryItemTotalValue(quantity, pricePtr, unitsByItem[id])
 	}
 
 	if err := rows.Err(); err != nil {
@@ -522,6 +548,42 @@ func (s *InventoryStore) GetSummary(ctx context.Context, userID string) (*models
 	return summary, nil
 }
 
+// summaryUnits loads the price fields of tracked units, keyed by inventory item.
+func (s *InventoryStore) summaryUnits(ctx context.Context, userID string) (map[string][]models.InventoryUnit, error) {
+	query := `SELECT inventory_item_id, purchase_price, purchase_seller FROM inventory_units`
+	args := []interface{}{}
+	if userID != "" {
+		query += " WHERE user_id = $1"
+		args = append(args, userID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory unit summary: %w", err)
+	}
+	defer rows.Close()
+
+	unitsByItem := make(map[string][]models.InventoryUnit)
+	for rows.Next() {
+		var unit models.InventoryUnit
+		var purchasePrice sql.NullFloat64
+		var purchaseSeller sql.NullString
+		if err := rows.Scan(&unit.InventoryItemID, &purchasePrice, &purchaseSeller); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory unit summary row: %w", err)
+		}
+		if purchasePrice.Valid {
+			price := purchasePrice.Float64
+			unit.PurchasePrice = &price
+		}
+		unit.PurchaseSeller = purchaseSeller.String
+		unitsByItem[unit.InventoryItemID] = append(unitsByItem[unit.InventoryItemID], unit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate inventory unit summary rows: %w", err)
+	}
+	return unitsByItem, nil
+}
+
 // GetByCatalogID finds an inventory item by catalog ID for a user
 func (s *InventoryStore) GetByCatalogID(ctx context.Context, userID, catalogID string) (*models.InventoryItem, error) {
 	if catalogID == "" {
@@ -584,6 +646,13 @@ func (s *InventoryStore) GetByCatalogID(ctx context.Context, userID, catalogID s
 		item.PurchasePrice = &purchasePrice.Float64
 	}
 
+	if err := s.attachUnits(ctx, []*models.InventoryItem{item}); err != nil {
+		return nil, err
+	}
+	if err := s.attachOrders(ctx, []*models.InventoryItem{item}); err != nil {
+		return nil, err
+	}
+
 	return item, nil
 }
 
@@ -0,0 +1,358 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+var (
+	ErrInventoryItemNotFound         = errors.New("inventory item not found")
+	ErrInventoryUnitNotFound         = errors.New("inventory unit not found")
+	ErrInventoryUnitAircraftNotFound = errors.New("aircraft not found")
+)
+
+const inventoryUnitColumns = `id, inventory_item_id, serial_number, purchase_price, purchase_seller,
+	condition, aircraft_id, build_id, notes, created_at, updated_at`
+
+// ListUnits returns the tracked units of an inventory item in their display order.
+func (s *InventoryStore) ListUnits(ctx context.Context, itemID string, userID string) ([]models.InventoryUnit, error) {
+	query := `SELECT ` + inventoryUnitColumns + ` FROM inventory_units WHERE inventory_item_id::text = $1`
+	args := []interface{}{itemID}
+	if userID != "" {
+		query += ` AND user_id = $2`
+		args = append(args, userID)
+	}
+	query += ` ORDER BY position, created_at`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory units: %w", err)
+	}
+	defer rows.Close()
+
+	units := make([]models.InventoryUnit, 0)
+	for rows.Next() {
+		unit, err := scanInventoryUnit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inventory unit: %w", err)
+		}
+		units = append(units, *unit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate inventory units: %w", err)
+	}
+	return units, nil
+}
+
+// attachUnits loads the tracked units of every item in one query.
+func (s *InventoryStore) attachUnits(ctx context.Context, items []*models.InventoryItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.InventoryItem, len(items))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+		ids = append(ids, item.ID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+inventoryUnitColumns+`
+		FROM inventory_units
+		WHERE inventory_item_id::text = ANY($1)
+		ORDER BY inventory_item_id, position, created_at
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list inventory units: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		unit, err := scanInventoryUnit(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan inventory unit: %w", err)
+		}
+		if item := byID[unit.InventoryItemID]; item != nil {
+			item.Units = append(item.Units, *unit)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate inventory units: %w", err)
+	}
+	return nil
+}
+
+// AddUnit starts tracking one piece of an inventory item. When every piece is
+// already tracked the unit is a new piece, so the item's quantity goes up by one.
+func (s *InventoryStore) AddUnit(ctx context.Context, userID string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin inventory unit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ownerID, quantity, err := lockInventoryItem(ctx, tx, itemID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUnitAircraft(ctx, tx, params.AircraftID, ownerID); err != nil {
+		return nil, err
+	}
+
+	var tracked, nextPosition int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(MAX(position) + 1, 0)
+		FROM inventory_units
+		WHERE inventory_item_id::text = $1
+	`, itemID).Scan(&tracked, &nextPosition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count inventory units: %w", err)
+	}
+
+	unit, err := insertInventoryUnit(ctx, tx, itemID, ownerID, params, nextPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	if tracked >= quantity {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE inventory_items SET quantity = $2, updated_at = NOW() WHERE id::text = $1
+		`, itemID, tracked+1); err != nil {
+			return nil, fmt.Errorf("failed to update inventory quantity: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit inventory unit: %w", err)
+	}
+	return unit, nil
+}
+
+// UpdateUnit updates a tracked unit of an inventory item.
+func (s *InventoryStore) UpdateUnit(ctx context.Context, userID string, itemID string, params models.UpdateInventoryUnitParams) (*models.InventoryUnit, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin inventory unit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ownerID, _, err := lockInventoryItem(ctx, tx, itemID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []string
+	var args []interface{}
+	argIndex := 1
+	set := func(column string, value interface{}) {
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, argIndex))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if params.SerialNumber != nil {
+		set("serial_number", nullString(*params.SerialNumber))
+	}
+	if params.PurchasePrice != nil {
+		set("purchase_price", *params.PurchasePrice)
+	}
+	if params.PurchaseSeller != nil {
+		set("purchase_seller", nullString(*params.PurchaseSeller))
+	}
+	if params.Condition != nil {
+		set("condition", string(*params.Condition))
+	}
+	if params.AircraftID != nil {
+		if err := checkUnitAircraft(ctx, tx, *params.AircraftID, ownerID); err != nil {
+			return nil, err
+		}
+		set("aircraft_id", nullString(*params.AircraftID))
+	}
+	if params.BuildID != nil {
+		set("build_id", nullString(*params.BuildID))
+	}
+	if params.Notes != nil {
+		set("notes", nullString(*params.Notes))
+	}
+	sets = append(sets, "updated_at = NOW()")
+
+	query := fmt.Sprintf(`
+		UPDATE inventory_units
+		SET %s
+		WHERE id::text = $%d AND inventory_item_id::text = $%d
+		RETURNING %s
+	`, strings.Join(sets, ", "), argIndex, argIndex+1, inventoryUnitColumns)
+	args = append(args, params.ID, itemID)
+
+	unit, err := scanInventoryUnit(tx.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrInventoryUnitNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update inventory unit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit inventory unit: %w", err)
+	}
+	return unit, nil
+}
+
+// DeleteUnit stops tracking a unit. The item's quantity is left alone; the
+// piece just isn't tracked individually anymore.
+func (s *InventoryStore) DeleteUnit(ctx context.Context, userID string, itemID string, unitID string) error {
+	query := `DELETE FROM inventory_units WHERE id::text = $1 AND inventory_item_id::text = $2`
+	args := []interface{}{unitID, itemID}
+	if userID != "" {
+		query += ` AND user_id = $3`
+		args = append(args, userID)
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete inventory unit: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrInventoryUnitNotFound
+	}
+	return nil
+}
+
+// CountUnits returns how many units of an inventory item are tracked.
+func (s *InventoryStore) CountUnits(ctx context.Context, itemID string) (int, error) {
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM inventory_units WHERE inventory_item_id::text = $1`, itemID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count inventory units: %w", err)
+	}
+	return count, nil
+}
+
+// AddLegacyUnits creates units from per-item details sent by clients that
+// still write them into specs. Details map onto units by position and only
+// details past the last tracked unit become new units; existing units are
+// never changed or removed, since their details are authoritative.
+func (s *InventoryStore) AddLegacyUnits(ctx context.Context, userID string, itemID string, details []models.AddInventoryUnitParams) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin inventory unit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ownerID, _, err := lockInventoryItem(ctx, tx, itemID, userID)
+	if err != nil {
+		return err
+	}
+
+	var existing int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM inventory_units WHERE inventory_item_id::text = $1`, itemID).Scan(&existing); err != nil {
+		return fmt.Errorf("failed to count inventory units: %w", err)
+	}
+	for i := existing; i < len(details); i++ {
+		if _, err := insertInventoryUnit(ctx, tx, itemID, ownerID, details[i], i); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit inventory units: %w", err)
+	}
+	return nil
+}
+
+// lockInventoryItem locks an inventory item row and returns its owner and quantity.
+func lockInventoryItem(ctx context.Context, tx *sql.Tx, itemID string, userID string) (string, int, error) {
+	query := `SELECT COALESCE(user_id::text, ''), quantity FROM inventory_items WHERE id::text = $1`
+	args := []interface{}{itemID}
+	if userID != "" {
+		query += ` AND user_id = $2`
+		args = append(args, userID)
+	}
+	query += ` FOR UPDATE`
+
+	var ownerID string
+	var quantity int
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&ownerID, &quantity)
+	if err == sql.ErrNoRows {
+		return "", 0, ErrInventoryItemNotFound
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to lock inventory item: %w", err)
+	}
+	return ownerID, quantity, nil
+}
+
+// checkUnitAircraft verifies that a unit is being assigned to one of the
+// item owner's aircraft. An empty aircraftID unassigns and always passes.
+func checkUnitAircraft(ctx context.Context, tx *sql.Tx, aircraftID string, ownerID string) error {
+	if aircraftID == "" {
+		return nil
+	}
+
+	var exists bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM aircraft WHERE id::text = $1 AND COALESCE(user_id::text, '') = $2)
+	`, aircraftID, ownerID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check unit aircraft: %w", err)
+	}
+	if !exists {
+		return ErrInventoryUnitAircraftNotFound
+	}
+	return nil
+}
+
+func insertInventoryUnit(ctx context.Context, tx *sql.Tx, itemID string, ownerID string, params models.AddInventoryUnitParams, position int) (*models.InventoryUnit, error) {
+	condition := params.Condition
+	if condition == "" {
+		condition = models.InventoryUnitNew
+	}
+
+	unit, err := scanInventoryUnit(tx.QueryRowContext(ctx, `
+		INSERT INTO inventory_units (
+			inventory_item_id, user_id, serial_number, purchase_price, purchase_seller,
+			condition, aircraft_id, build_id, notes, position
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+inventoryUnitColumns,
+		itemID, nullString(ownerID), nullString(params.SerialNumber), params.PurchasePrice,
+		nullString(params.PurchaseSeller), string(condition), nullString(params.AircraftID),
+		nullString(params.BuildID), nullString(params.Notes), position,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert inventory unit: %w", err)
+	}
+	return unit, nil
+}
+
+func scanInventoryUnit(row interface{ Scan(...any) error }) (*models.InventoryUnit, error) {
+	var unit models.InventoryUnit
+	var condition string
+	var serialNumber, purchaseSeller, aircraftID, buildID, notes sql.NullString
+	var purchasePrice sql.NullFloat64
+	err := row.Scan(
+		&unit.ID, &unit.InventoryItemID, &serialNumber, &purchasePrice, &purchaseSeller,
+		&condition, &aircraftID, &buildID, &notes, &unit.CreatedAt, &unit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	unit.SerialNumber = serialNumber.String
+	unit.PurchaseSeller = purchaseSeller.String
+	unit.Condition = models.InventoryUnitCondition(condition)
+	unit.AircraftID = aircraftID.String
+	unit.BuildID = buildID.String
+	unit.Notes = notes.String
+	if purchasePrice.Valid {
+		unit.PurchasePrice = &purchasePrice.Float64
+	}
+	return &unit, nil
+}
@@ -0,0 +1,468 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ErrOrderNotFound is returned when an order doesn't exist or belongs to another user.
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrOrderItemNotFound is returned when an order is linked to an inventory item the user doesn't own.
+var ErrOrderItemNotFound = errors.New("inventory item not found")
+
+// OrderStore handles order and shipment tracking database operations
+type OrderStore struct {
+	db *DB
+}
+
+// NewOrderStore creates a new order store
+func NewOrderStore(db *DB) *OrderStore {
+	return &OrderStore{db: db}
+}
+
+const orderColumns = `id, COALESCE(user_id::text, ''), carrier, tracking_number, label, status, status_details,
+	estimated_date, delivered_at, last_checked_at, archived, created_at, updated_at`
+
+// finalOrderStatuses are the statuses carrier updates can't move an order out of
+var finalOrderStatuses = pq.Array([]string{string(models.OrderStatusDelivered), string(models.OrderStatusReturned)})
+
+// Create records an order and links it to the given inventory items
+func (s *OrderStore) Create(ctx context.Context, userID string, params models.CreateOrderParams) (*models.Order, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start order transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var orderID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, carrier, tracking_number, label, estimated_date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, string(params.Carrier), params.TrackingNumber, nullString(params.Label), params.EstimatedDate).Scan(&orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	if err := linkOrderItems(ctx, tx, orderID, userID, params.InventoryItemIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit order: %w", err)
+	}
+	return s.Get(ctx, orderID, userID)
+}
+
+// Get retrieves an order with its linked items
+func (s *OrderStore) Get(ctx context.Context, id string, userID string) (*models.Order, error) {
+	order, err := scanOrder(s.db.QueryRowContext(ctx, `
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE id::text = $1 AND user_id = $2
+	`, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	orders := []models.Order{*order}
+	if err := s.attachItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// List lists a user's orders, soonest expected first, with delivered orders last
+func (s *OrderStore) List(ctx context.Context, userID string, params models.OrderListParams) (*models.OrderListResponse, error) {
+	where := `user_id = $1`
+	args := []interface{}{userID}
+	if !params.IncludeArchived {
+		where += ` AND archived = false`
+	}
+	if params.Status != "" {
+		args = append(args, string(params.Status))
+		where += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+
+	var totalCount int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM orders WHERE `+where, args...).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query := fmt.Sprintf(`
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE %s
+		ORDER BY (status = ANY($%d)), estimated_date ASC NULLS LAST, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2, len(args)+3)
+	args = append(args, finalOrderStatuses, limit, params.Offset)
+
+	orders, err := s.queryOrders(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return &models.OrderListResponse{Orders: orders, TotalCount: totalCount}, nil
+}
+
+// Update edits an order. Setting the status to delivered stamps delivered_at
+// if it isn't set yet; any other status clears it.
+func (s *OrderStore) Update(ctx context.Context, userID string, params models.UpdateOrderParams) (*models.Order, error) {
+	sets := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Carrier != nil {
+		sets = append(sets, fmt.Sprintf("carrier = $%d", argIndex))
+		args = append(args, string(*params.Carrier))
+		argIndex++
+	}
+	if params.TrackingNumber != nil {
+		sets = append(sets, fmt.Sprintf("tracking_number = $%d", argIndex))
+		args = append(args, *params.TrackingNumber)
+		argIndex++
+	}
+	if params.Label != nil {
+		sets = append(sets, fmt.Sprintf("label = $%d", argIndex))
+		args = append(args, nullString(*params.Label))
+		argIndex++
+	}
+	if params.Status != nil {
+		sets = append(sets, fmt.Sprintf("status = $%d", argIndex))
+		sets = append(sets, fmt.Sprintf("delivered_at = CASE WHEN $%d = '%s' THEN COALESCE(delivered_at, NOW()) END", argIndex, models.OrderStatusDelivered))
+		args = append(args, string(*params.Status))
+		argIndex++
+	}
+	if params.StatusDetails != nil {
+		sets = append(sets, fmt.Sprintf("status_details = $%d", argIndex))
+		args = append(args, nullString(*params.StatusDetails))
+		argIndex++
+	}
+	if params.EstimatedDate != nil {
+		sets = append(sets, fmt.Sprintf("estimated_date = $%d", argIndex))
+		args = append(args, *params.EstimatedDate)
+		argIndex++
+	}
+	if params.Archived != nil {
+		sets = append(sets, fmt.Sprintf("archived = $%d", argIndex))
+		args = append(args, *params.Archived)
+		argIndex++
+	}
+
+	if len(sets) > 0 {
+		sets = append(sets, "updated_at = NOW()")
+		query := fmt.Sprintf(`
+			UPDATE orders SET %s
+			WHERE id::text = $%d AND user_id = $%d
+		`, strings.Join(sets, ", "), argIndex, argIndex+1)
+		args = append(args, params.ID, userID)
+
+		result, err := s.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update order: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return nil, ErrOrderNotFound
+		}
+	}
+
+	order, err := s.Get(ctx, params.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// Delete removes an order. Its items stay in the inventory.
+func (s *OrderStore) Delete(ctx context.Context, id string, userID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM orders WHERE id::text = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete order: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrOrderNotFound
+	}
+	return nil
+}
+
+// SetItems replaces the inventory items linked to an order. Items linked to
+// another order are moved to this one.
+func (s *OrderStore) SetItems(ctx context.Context, orderID string, userID string, itemIDs []string) (*models.Order, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start order items transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM orders WHERE id::text = $1 AND user_id = $2 FOR UPDATE`, orderID, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify order: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM order_items WHERE order_id = $1 AND NOT (inventory_item_id::text = ANY($2))
+	`, id, pq.Array(itemIDs)); err != nil {
+		return nil, fmt.Errorf("failed to unlink order items: %w", err)
+	}
+	if err := linkOrderItems(ctx, tx, id, userID, itemIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit order items: %w", err)
+	}
+	return s.Get(ctx, id, userID)
+}
+
+// linkOrderItems links items to an order. Items added to an order that has
+// already been delivered are received straight away.
+func linkOrderItems(ctx context.Context, tx *sql.Tx, orderID string, userID string, itemIDs []string) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO order_items (inventory_item_id, order_id, received_at)
+		SELECT ii.id, o.id, CASE WHEN o.status = $4 THEN COALESCE(o.delivered_at, NOW()) END
+		FROM inventory_items ii
+		JOIN orders o ON o.id = $2
+		WHERE ii.id::text = ANY($1) AND ii.user_id = $3
+		ON CONFLICT (inventory_item_id) DO UPDATE
+		SET order_id = EXCLUDED.order_id, received_at = EXCLUDED.received_at
+		WHERE order_items.order_id <> EXCLUDED.order_id
+	`, pq.Array(itemIDs), orderID, userID, string(models.OrderStatusDelivered)); err != nil {
+		return fmt.Errorf("failed to link order items: %w", err)
+	}
+
+	// Items already linked to this order aren't touched by the upsert, so
+	// count what the order holds rather than what was written
+	var linked int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM order_items WHERE order_id = $1 AND inventory_item_id::text = ANY($2)
+	`, orderID, pq.Array(itemIDs)).Scan(&linked); err != nil {
+		return fmt.Errorf("failed to verify order items: %w", err)
+	}
+	if linked != len(itemIDs) {
+		return ErrOrderItemNotFound
+	}
+	return nil
+}
+
+// ListDueForCheck returns open orders, across all users, shipped with one of
+// the given carriers whose status hasn't been checked since checkedBefore,
+// least recently checked first
+func (s *OrderStore) ListDueForCheck(ctx context.Context, carriers []string, checkedBefore time.Time, limit int) ([]models.Order, error) {
+	return s.queryOrders(ctx, `
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE archived = false
+		  AND NOT (status = ANY($1))
+		  AND carrier = ANY($2)
+		  AND (last_checked_at IS NULL OR last_checked_at < $3)
+		ORDER BY last_checked_at NULLS FIRST, created_at
+		LIMIT $4
+	`, finalOrderStatuses, pq.Array(carriers), checkedBefore, limit)
+}
+
+// SaveCarrierStatus stores the status from a carrier check. An order that
+// reached a final status since it was read is left alone; ok is false then.
+func (s *OrderStore) SaveCarrierStatus(ctx context.Context, order *models.Order) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE orders
+		SET status = $2, status_details = $3, estimated_date = $4, delivered_at = $5,
+		    last_checked_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND NOT (status = ANY($6))
+	`, order.ID, string(order.Status), nullString(order.StatusDetails), order.EstimatedDate, order.DeliveredAt, finalOrderStatuses)
+	if err != nil {
+		return false, fmt.Errorf("failed to save carrier status: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// MarkChecked records a carrier check that didn't produce a status, so a
+// failing tracking number doesn't hold up the rest of the queue
+func (s *OrderStore) MarkChecked(ctx context.Context, orderID string) error {
+	if _, err := s.db.ExecContext(ctx, `UPDATE orders SET last_checked_at = NOW() WHERE id = $1`, orderID); err != nil {
+		return fmt.Errorf("failed to mark order checked: %w", err)
+	}
+	return nil
+}
+
+// MarkItemsReceived stamps every not-yet-received item of an order as received
+func (s *OrderStore) MarkItemsReceived(ctx context.Context, orderID string, receivedAt time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE order_items SET received_at = $2
+		WHERE order_id::text = $1 AND received_at IS NULL
+	`, orderID, receivedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark order items received: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return int(affected), nil
+}
+
+func (s *OrderStore) queryOrders(ctx context.Context, query string, args ...interface{}) ([]models.Order, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]models.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, *order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+	return orders, nil
+}
+
+// attachItems loads the linked items of every order in one query
+func (s *OrderStore) attachItems(ctx context.Context, orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for i := range orders {
+		orders[i].Items = []models.OrderItem{}
+		byID[orders[i].ID] = &orders[i]
+		ids = append(ids, orders[i].ID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT oi.order_id, oi.inventory_item_id, ii.name, oi.received_at
+		FROM order_items oi
+		JOIN inventory_items ii ON ii.id = oi.inventory_item_id
+		WHERE oi.order_id::text = ANY($1)
+		ORDER BY oi.order_id, ii.name
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list order items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var item models.OrderItem
+		var receivedAt sql.NullTime
+		if err := rows.Scan(&orderID, &item.InventoryItemID, &item.Name, &receivedAt); err != nil {
+			return fmt.Errorf("failed to scan order item: %w", err)
+		}
+		if receivedAt.Valid {
+			item.ReceivedAt = &receivedAt.Time
+		}
+		if order := byID[orderID]; order != nil {
+			order.Items = append(order.Items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate order items: %w", err)
+	}
+	return nil
+}
+
+// attachOrders loads the order each item is arriving in, if any
+func (s *InventoryStore) attachOrders(ctx context.Context, items []*models.InventoryItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.InventoryItem, len(items))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+		ids = append(ids, item.ID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT oi.inventory_item_id, o.id, COALESCE(o.label, ''), o.status, oi.received_at
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE oi.inventory_item_id::text = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list inventory orders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID, status string
+		var receivedAt sql.NullTime
+		order := &models.InventoryItemOrder{}
+		if err := rows.Scan(&itemID, &order.OrderID, &order.Label, &status, &receivedAt); err != nil {
+			return fmt.Errorf("failed to scan inventory order: %w", err)
+		}
+		order.Status = models.OrderStatus(status)
+		if receivedAt.Valid {
+			order.ReceivedAt = &receivedAt.Time
+		}
+		if item := byID[itemID]; item != nil {
+			item.Order = order
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate inventory orders: %w", err)
+	}
+	return nil
+}
+
+func scanOrder(row interface{ Scan(...any) error }) (*models.Order, error) {
+	order := &models.Order{}
+	var carrier, status string
+	var label, statusDetails sql.NullString
+	var estimatedDate, deliveredAt, lastCheckedAt sql.NullTime
+	err := row.Scan(
+		&order.ID, &order.UserID, &carrier, &order.TrackingNumber, &label, &status, &statusDetails,
+		&estimatedDate, &deliveredAt, &lastCheckedAt, &order.Archived, &order.CreatedAt, &order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.Carrier = models.OrderCarrier(carrier)
+	order.Status = models.OrderStatus(status)
+	order.Label = label.String
+	order.StatusDetails = statusDetails.String
+	if estimatedDate.Valid {
+		order.EstimatedDate = &estimatedDate.Time
+	}
+	if deliveredAt.Valid {
+		order.DeliveredAt = &deliveredAt.Time
+	}
+	if lastCheckedAt.Valid {
+		order.LastCheckedAt = &lastCheckedAt.Time
+	}
+	return order, nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/johnrirwin/flyingforge/internal/models"
@@ -262,22 +263,29 @@ func (s *RadioStore) DeleteRadio(ctx context.Context, id string, userID string)
 // CreateBackup creates a new backup record
 func (s *RadioStore) CreateBackup(ctx context.Context, radioID string, params models.CreateRadioBackupParams, storagePath string) (*models.RadioBackup, error) {
 	query := `
-		INSERT INTO radio_backups (radio_id, backup_name, backup_type, file_name, file_size, checksum, storage_path)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO radio_backups (radio_id, backup_name, backup_type, file_name, file_size, checksum, storage_path, storage_key, model_summary)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at
 	`
 
 	backup := &models.RadioBackup{
-		RadioID:     radioID,
-		BackupName:  params.BackupName,
-		BackupType:  params.BackupType,
-		FileName:    params.FileName,
-		FileSize:    params.FileSize,
-		Checksum:    params.Checksum,
-		StoragePath: storagePath,
+		RadioID:      radioID,
+		BackupName:   params.BackupName,
+		BackupType:   params.BackupType,
+		FileName:     params.FileName,
+		FileSize:     params.FileSize,
+		Checksum:     params.Checksum,
+		StoragePath:  storagePath,
+		StorageKey:   params.StorageKey,
+		ModelSummary: params.ModelSummary,
+	}
+
+	summaryJSON, err := marshalModelSummary(params.ModelSummary)
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.db.QueryRowContext(ctx, query,
+	err = s.db.QueryRowContext(ctx, query,
 		backup.RadioID,
 		backup.BackupName,
 		string(backup.BackupType),
@@ -285,6 +293,8 @@ func (s *RadioStore) CreateBackup(ctx context.Context, radioID string, params mo
 		backup.FileSize,
 		nullString(backup.Checksum),
 		backup.StoragePath,
+		nullString(backup.StorageKey),
+		summaryJSON,
 	).Scan(&backup.ID, &backup.CreatedAt)
 
 	if err != nil {
@@ -297,13 +307,14 @@ func (s *RadioStore) CreateBackup(ctx context.Context, radioID string, params mo
 // GetBackup retrieves a backup by ID
 func (s *RadioStore) GetBackup(ctx context.Context, id string, radioID string) (*models.RadioBackup, error) {
 	query := `
-		SELECT id, radio_id, backup_name, backup_type, file_name, file_size, checksum, storage_path, created_at
+		SELECT id, radio_id, backup_name, backup_type, file_name, file_size, checksum, storage_path, storage_key, created_at, model_summary
 		FROM radio_backups
 		WHERE id = $1 AND radio_id = $2
 	`
 
 	backup := &models.RadioBackup{}
-	var checksum sql.NullString
+	var checksum, storageKey sql.NullString
+	var summaryJSON []byte
 
 	err := s.db.QueryRowContext(ctx, query, id, radioID).Scan(
 		&backup.ID,
@@ -314,7 +325,9 @@ func (s *RadioStore) GetBackup(ctx context.Context, id string, radioID string) (
 		&backup.FileSize,
 		&checksum,
 		&backup.StoragePath,
+		&storageKey,
 		&backup.CreatedAt,
+		&summaryJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -327,6 +340,10 @@ func (s *RadioStore) GetBackup(ctx context.Context, id string, radioID string) (
 	if checksum.Valid {
 		backup.Checksum = checksum.String
 	}
+	backup.StorageKey = storageKey.String
+	if backup.ModelSummary, err = unmarshalModelSummary(summaryJSON); err != nil {
+		return nil, err
+	}
 
 	return backup, nil
 }
@@ -351,7 +368,7 @@ func (s *RadioStore) ListBackups(ctx context.Context, radioID string, params mod
 	}
 
 	query := `
-		SELECT id, radio_id, backup_name, backup_type, file_name, file_size, checksum, storage_path, created_at
+		SELECT id, radio_id, backup_name, backup_type, file_name, file_size, checksum, storage_path, storage_key, created_at, model_summary
 		FROM radio_backups
 		WHERE radio_id = $1
 		ORDER BY created_at DESC
@@ -367,7 +384,8 @@ func (s *RadioStore) ListBackups(ctx context.Context, radioID string, params mod
 	backups := []models.RadioBackup{}
 	for rows.Next() {
 		backup := models.RadioBackup{}
-		var checksum sql.NullString
+		var checksum, storageKey sql.NullString
+		var summaryJSON []byte
 
 		if err := rows.Scan(
 			&backup.ID,
@@ -378,7 +396,9 @@ func (s *RadioStore) ListBackups(ctx context.Context, radioID string, params mod
 			&backup.FileSize,
 			&checksum,
 			&backup.StoragePath,
+			&storageKey,
 			&backup.CreatedAt,
+			&summaryJSON,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan backup: %w", err)
 		}
@@ -386,6 +406,10 @@ func (s *RadioStore) ListBackups(ctx context.Context, radioID string, params mod
 		if checksum.Valid {
 			backup.Checksum = checksum.String
 		}
+		backup.StorageKey = storageKey.String
+		if backup.ModelSummary, err = unmarshalModelSummary(summaryJSON); err != nil {
+			return nil, err
+		}
 
 		backups = append(backups, backup)
 	}
@@ -396,6 +420,161 @@ func (s *RadioStore) ListBackups(ctx context.Context, radioID string, params mod
 	}, nil
 }
 
+// UpdateBackupModelSummary stores a (re)parsed model list for a backup
+func (s *RadioStore) UpdateBackupModelSummary(ctx context.Context, id string, radioID string, summary *models.RadioBackupModelSummary) error {
+	summaryJSON, err := marshalModelSummary(summary)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE radio_backups SET model_summary = $1 WHERE id = $2 AND radio_id = $3`,
+		summaryJSON, id, radioID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update backup model summary: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("backup not found")
+	}
+	return nil
+}
+
+// ListLatestModelSources returns, for each of a user's radios, the newest backup
+// with a parsed, non-empty EdgeTX model list
+func (s *RadioStore) ListLatestModelSources(ctx context.Context, userID string) ([]models.RadioModelSource, error) {
+	query := `
+		SELECT DISTINCT ON (r.id)
+		       r.id, r.manufacturer, r.model, r.firmware_family, r.created_at, r.updated_at,
+		       b.id, b.backup_name, b.created_at, b.model_summary
+		FROM radios r
+		JOIN radio_backups b ON b.radio_id = r.id
+		WHERE r.user_id = $1
+		  AND b.model_summary IS NOT NULL
+		  AND CASE WHEN jsonb_typeof(b.model_summary->'models') = 'array'
+		           THEN jsonb_array_length(b.model_summary->'models') ELSE 0 END > 0
+		ORDER BY r.id, b.created_at DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list radio model sources: %w", err)
+	}
+	defer rows.Close()
+
+	sources := []models.RadioModelSource{}
+	for rows.Next() {
+		var source models.RadioModelSource
+		var firmwareFamily sql.NullString
+		var summaryJSON []byte
+
+		if err := rows.Scan(
+			&source.Radio.ID,
+			&source.Radio.Manufacturer,
+			&source.Radio.Model,
+			&firmwareFamily,
+			&source.Radio.CreatedAt,
+			&source.Radio.UpdatedAt,
+			&source.BackupID,
+			&source.BackupName,
+			&source.BackupCreatedAt,
+			&summaryJSON,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan radio model source: %w", err)
+		}
+
+		source.Radio.UserID = userID
+		source.Radio.FirmwareFamily = models.FirmwareFamily(firmwareFamily.String)
+		summary, err := unmarshalModelSummary(summaryJSON)
+		if err != nil {
+			return nil, err
+		}
+		if summary != nil {
+			source.Models = summary.Models
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, rows.Err()
+}
+
+func marshalModelSummary(summary *models.RadioBackupModelSummary) (interface{}, error) {
+	if summary == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal model summary: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalModelSummary(data []byte) (*models.RadioBackupModelSummary, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	summary := &models.RadioBackupModelSummary{}
+	if err := json.Unmarshal(data, summary); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal model summary: %w", err)
+	}
+	return summary, nil
+}
+
+// ListLocalBackups pages through backups whose files are still on local disk
+func (s *RadioStore) ListLocalBackups(ctx context.Context, limit int, offset int) ([]models.RadioBackup, error) {
+	query := `
+		SELECT id, radio_id, backup_name, backup_type, file_name, file_size, storage_path, created_at
+		FROM radio_backups
+		WHERE storage_key IS NULL AND storage_path <> ''
+		ORDER BY created_at ASC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local backups: %w", err)
+	}
+	defer rows.Close()
+
+	backups := []models.RadioBackup{}
+	for rows.Next() {
+		backup := models.RadioBackup{}
+		if err := rows.Scan(
+			&backup.ID,
+			&backup.RadioID,
+			&backup.BackupName,
+			&backup.BackupType,
+			&backup.FileName,
+			&backup.FileSize,
+			&backup.StoragePath,
+			&backup.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan backup: %w", err)
+		}
+		backups = append(backups, backup)
+	}
+
+	return backups, rows.Err()
+}
+
+// SetBackupStorageKey records that a backup's file now lives in object storage
+func (s *RadioStore) SetBackupStorageKey(ctx context.Context, id string, storageKey string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE radio_backups SET storage_key = $2, storage_path = '' WHERE id = $1`,
+		id, storageKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update backup storage key: %w", err)
+	}
+	return nil
+}
+
 // DeleteBackup deletes a backup record (caller should handle file deletion)
 func (s *RadioStore) DeleteBackup(ctx context.Context, id string, radioID string) (*models.RadioBackup, error) {
 	// Get backup first to return storage path
@@ -3,13 +3,20 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
 
+// ErrLastSignInMethod is returned when removing a passkey would leave the
+// user without a way to sign in
+var ErrLastSignInMethod = errors.New("last sign-in method")
+
 // UserStore handles user database operations
 type UserStore struct {
 	db *DB
@@ -739,6 +746,395 @@ func (s *UserStore) RevokeAllUserRefreshTokens(ctx context.Context, userID strin
 	return err
 }
 
+// Personal access token operations
+
+const personalAccessTokenColumns = `
+	t.id, t.user_id, t.name, t.token_prefix, t.token_hash, t.scopes,
+	t.expires_at, t.last_used_at, t.created_at, t.revoked_at
+`
+
+// CreatePersonalAccessToken stores a new personal access token hash
+func (s *UserStore) CreatePersonalAccessToken(ctx context.Context, userID, name, tokenPrefix, tokenHash string, scopes []string, expiresAt time.Time) (*models.PersonalAccessToken, error) {
+	query := `
+		INSERT INTO personal_access_tokens AS t (user_id, name, token_prefix, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + personalAccessTokenColumns
+
+	return scanPersonalAccessToken(s.db.QueryRowContext(ctx, query, userID, name, tokenPrefix, tokenHash, pq.Array(scopes), expiresAt))
+}
+
+// ListPersonalAccessTokens returns a user's unrevoked tokens, newest first,
+// including expired ones so they can be cleaned up
+func (s *UserStore) ListPersonalAccessTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error) {
+	query := `
+		SELECT ` + personalAccessTokenColumns + `
+		FROM personal_access_tokens t
+		WHERE t.user_id = $1 AND t.revoked_at IS NULL
+		ORDER BY t.created_at DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []models.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// GetActivePersonalAccessTokenByHash returns an unrevoked, unexpired token
+// whose owner is active, or nil
+func (s *UserStore) GetActivePersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	query := `
+		SELECT ` + personalAccessTokenColumns + `
+		FROM personal_access_tokens t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = $1
+		  AND t.revoked_at IS NULL
+		  AND t.expires_at > NOW()
+		  AND u.status = $2
+	`
+
+	token, err := scanPersonalAccessToken(s.db.QueryRowContext(ctx, query, tokenHash, models.UserStatusActive))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+// TouchPersonalAccessToken records that a token was used. Writes are skipped
+// when it was already marked within the last minute.
+func (s *UserStore) TouchPersonalAccessToken(ctx context.Context, tokenID string) error {
+	query := `
+		UPDATE personal_access_tokens
+		SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`
+	_, err := s.db.ExecContext(ctx, query, tokenID)
+	return err
+}
+
+// RevokePersonalAccessToken revokes one of a user's tokens and reports whether it existed
+func (s *UserStore) RevokePersonalAccessToken(ctx context.Context, tokenID, userID string) (bool, error) {
+	query := `UPDATE personal_access_tokens SET revoked_at = NOW() WHERE id::text = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := s.db.ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func scanPersonalAccessToken(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.PersonalAccessToken, error) {
+	token := &models.PersonalAccessToken{}
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := scanner.Scan(
+		&token.ID, &token.UserID, &token.Name, &token.TokenPrefix, &token.TokenHash, pq.Array(&token.Scopes),
+		&token.ExpiresAt, &lastUsedAt, &token.CreatedAt, &revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+// Email sign-in link operations
+
+// CreateEmailLoginToken stores the hash of a new sign-in link token
+func (s *UserStore) CreateEmailLoginToken(ctx context.Context, email, tokenHash string, expiresAt time.Time) (*models.EmailLoginToken, error) {
+	query := `
+		INSERT INTO email_login_tokens (email, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, email, token_hash, expires_at, created_at, consumed_at
+	`
+	return scanEmailLoginToken(s.db.QueryRowContext(ctx, query, email, tokenHash, expiresAt))
+}
+
+// CountEmailLoginTokensSince counts the sign-in links sent to an address since a time
+func (s *UserStore) CountEmailLoginTokensSince(ctx context.Context, email string, since time.Time) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM email_login_tokens WHERE email = $1 AND created_at > $2
+	`, email, since).Scan(&count)
+	return count, err
+}
+
+// ConsumeEmailLoginToken marks an unused, unexpired token as used and
+// returns it, or nil if there is no such token
+func (s *UserStore) ConsumeEmailLoginToken(ctx context.Context, tokenHash string) (*models.EmailLoginToken, error) {
+	query := `
+		UPDATE email_login_tokens
+		SET consumed_at = NOW()
+		WHERE token_hash = $1
+		  AND consumed_at IS NULL
+		  AND expires_at > NOW()
+		RETURNING id, email, token_hash, expires_at, created_at, consumed_at
+	`
+	token, err := scanEmailLoginToken(s.db.QueryRowContext(ctx, query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+func scanEmailLoginToken(row *sql.Row) (*models.EmailLoginToken, error) {
+	token := &models.EmailLoginToken{}
+	var consumedAt sql.NullTime
+
+	err := row.Scan(&token.ID, &token.Email, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &consumedAt)
+	if err != nil {
+		return nil, err
+	}
+	if consumedAt.Valid {
+		token.ConsumedAt = &consumedAt.Time
+	}
+	return token, nil
+}
+
+// WebAuthn challenge operations
+
+// CreateWebAuthnChallenge stores a challenge issued for a passkey ceremony.
+// userID is empty for sign-in, where the user is not known yet.
+func (s *UserStore) CreateWebAuthnChallenge(ctx context.Context, challenge, purpose, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO webauthn_challenges (challenge, purpose, user_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := s.db.ExecContext(ctx, query, challenge, purpose, nullString(userID), expiresAt)
+	return err
+}
+
+// ConsumeWebAuthnChallenge deletes an unexpired challenge so it can only be
+// answered once. It returns the user the challenge was issued to and
+// whether it was found.
+func (s *UserStore) ConsumeWebAuthnChallenge(ctx context.Context, challenge, purpose string) (string, bool, error) {
+	query := `
+		DELETE FROM webauthn_challenges
+		WHERE challenge = $1 AND purpose = $2 AND expires_at > NOW()
+		RETURNING user_id
+	`
+	var userID sql.NullString
+	err := s.db.QueryRowContext(ctx, query, challenge, purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return userID.String, true, nil
+}
+
+// CleanupExpiredLoginState deletes expired WebAuthn challenges and sign-in
+// links that expired before linkCutoff. Recent links are kept so they still
+// count toward the per-address send limit.
+func (s *UserStore) CleanupExpiredLoginState(ctx context.Context, linkCutoff time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM webauthn_challenges WHERE expires_at <= NOW()`); err != nil {
+		return fmt.Errorf("cleanup webauthn challenges: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM email_login_tokens WHERE expires_at <= $1`, linkCutoff); err != nil {
+		return fmt.Errorf("cleanup email login tokens: %w", err)
+	}
+	return nil
+}
+
+// Passkey operations
+
+const passkeyCredentialColumns = `
+	id, identity_id, user_id, credential_id, public_key, sign_count,
+	name, transports, created_at, last_used_at
+`
+
+// CreatePasskeyCredential stores a passkey together with the passkey
+// identity it signs in to, so a failed insert can't leave an identity behind
+func (s *UserStore) CreatePasskeyCredential(ctx context.Context, credential *models.PasskeyCredential, email string) (*models.PasskeyCredential, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start passkey transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var identityID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO user_identities (user_id, provider, provider_subject, provider_email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, credential.UserID, models.AuthProviderPasskey, credential.CredentialID, nullString(email)).Scan(&identityID)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("passkey already registered")
+		}
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO passkey_credentials (identity_id, user_id, credential_id, public_key, sign_count, name, transports)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + passkeyCredentialColumns
+
+	created, err := scanPasskeyCredential(tx.QueryRowContext(ctx, query,
+		identityID, credential.UserID, credential.CredentialID, credential.PublicKey,
+		int64(credential.SignCount), credential.Name, pq.Array(credential.Transports),
+	))
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("passkey already registered")
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit passkey: %w", err)
+	}
+	return created, nil
+}
+
+// GetPasskeyCredential returns the passkey with a credential ID, or nil
+func (s *UserStore) GetPasskeyCredential(ctx context.Context, credentialID string) (*models.PasskeyCredential, error) {
+	query := `SELECT ` + passkeyCredentialColumns + ` FROM passkey_credentials WHERE credential_id = $1`
+
+	credential, err := scanPasskeyCredential(s.db.QueryRowContext(ctx, query, credentialID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return credential, err
+}
+
+// ListPasskeyCredentials returns a user's passkeys, newest first
+func (s *UserStore) ListPasskeyCredentials(ctx context.Context, userID string) ([]models.PasskeyCredential, error) {
+	query := `
+		SELECT ` + passkeyCredentialColumns + `
+		FROM passkey_credentials
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	defer rows.Close()
+
+	credentials := []models.PasskeyCredential{}
+	for rows.Next() {
+		credential, err := scanPasskeyCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, *credential)
+	}
+	return credentials, rows.Err()
+}
+
+// UpdatePasskeySignCount records a sign-in with a passkey
+func (s *UserStore) UpdatePasskeySignCount(ctx context.Context, id string, signCount uint32) error {
+	query := `UPDATE passkey_credentials SET sign_count = $2, last_used_at = NOW() WHERE id = $1`
+	_, err := s.db.ExecContext(ctx, query, id, int64(signCount))
+	return err
+}
+
+// DeletePasskeyCredential removes one of a user's passkeys by deleting its
+// identity, and reports whether it existed. Unless the user has another
+// identity from one of the signInProviders, ErrLastSignInMethod is returned
+// instead; with no providers given, the passkey is always deleted.
+func (s *UserStore) DeletePasskeyCredential(ctx context.Context, id, userID string, signInProviders []models.AuthProvider) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to start passkey transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the user so two deletions can't each leave the other as the last
+	// way in
+	var lockedID string
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&lockedID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var identityID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT identity_id FROM passkey_credentials WHERE id::text = $1 AND user_id = $2
+	`, id, userID).Scan(&identityID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(signInProviders) > 0 {
+		providers := make([]string, 0, len(signInProviders))
+		for _, provider := range signInProviders {
+			providers = append(providers, string(provider))
+		}
+		var others int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM user_identities
+			WHERE user_id = $1 AND id <> $2 AND provider = ANY($3)
+		`, userID, identityID, pq.Array(providers)).Scan(&others)
+		if err != nil {
+			return false, err
+		}
+		if others == 0 {
+			return false, ErrLastSignInMethod
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_identities WHERE id = $1`, identityID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit passkey deletion: %w", err)
+	}
+	return true, nil
+}
+
+func scanPasskeyCredential(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.PasskeyCredential, error) {
+	credential := &models.PasskeyCredential{}
+	var signCount int64
+	var lastUsedAt sql.NullTime
+
+	err := scanner.Scan(
+		&credential.ID, &credential.IdentityID, &credential.UserID, &credential.CredentialID,
+		&credential.PublicKey, &signCount, &credential.Name, pq.Array(&credential.Transports),
+		&credential.CreatedAt, &lastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.SignCount = uint32(signCount)
+	if lastUsedAt.Valid {
+		credential.LastUsedAt = &lastUsedAt.Time
+	}
+	return credential, nil
+}
+
 // UpdateSocialSettings updates a user's social settings
 func (s *UserStore) UpdateSocialSettings(ctx context.Context, userID string, params models.UpdateSocialSettingsParams) error {
 	var sets []string
@@ -1111,8 +1507,10 @@ func (s *UserStore) GetFeaturedPilots(ctx context.Context, excludeUserID string,
 
 // HardDelete permanently removes a user and all associated data.
 // Related data in other tables is handled by database CASCADE constraints:
-//   - user_identities, refresh_tokens, inventory_items, aircraft, radios,
-//     batteries, battery_logs, follows, orders, fc_configs: CASCADE delete
+//   - user_identities, refresh_tokens, personal_access_tokens, passkey_credentials, webauthn_challenges,
+//     inventory_items, aircraft, radios, batteries, battery_logs, follows, orders, fc_configs,
+//     data_exports, data_imports: CASCADE delete
+//   - email_login_tokens are keyed by address, not user, and expire on their own
 //   - gear_catalog.created_by_user_id: SET NULL (preserves catalog items)
 func (s *UserStore) HardDelete(ctx context.Context, userID string) error {
 	query := `DELETE FROM users WHERE id = $1`
@@ -0,0 +1,130 @@
+package fcconfig
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/johnrirwin/flyingforge/internal/betaflight"
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ServiceError represents a service-level error
+type ServiceError struct {
+	Message string
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+// Store defines the FC config storage operations the service needs
+type Store interface {
+	SaveConfig(ctx context.Context, userID string, config *models.FlightControllerConfig) error
+	GetAircraftByFC(ctx context.Context, userID string, inventoryItemID string) (*models.Aircraft, error)
+	SaveTuningSnapshot(ctx context.Context, userID string, snapshot *models.AircraftTuningSnapshot) error
+}
+
+// InventoryReader looks up the flight controller a config belongs to
+type InventoryReader interface {
+	Get(ctx context.Context, id string, userID string) (*models.InventoryItem, error)
+}
+
+// Service saves flight controller configs
+type Service struct {
+	store     Store
+	inventory InventoryReader
+	parser    *betaflight.Parser
+	logger    *logging.Logger
+}
+
+// NewService creates a new FC config service
+func NewService(store *database.FCConfigStore, inventory *database.InventoryStore, logger *logging.Logger) *Service {
+	return &Service{
+		store:     store,
+		inventory: inventory,
+		parser:    betaflight.NewParser(),
+		logger:    logger,
+	}
+}
+
+// SaveConfig parses a CLI dump and saves it against one of the user's flight
+// controllers. If that FC is installed on an aircraft, a tuning snapshot is
+// created for the aircraft too.
+func (s *Service) SaveConfig(ctx context.Context, userID string, params models.SaveFCConfigParams) (*models.FlightControllerConfig, error) {
+	if params.InventoryItemID == "" {
+		return nil, &ServiceError{Message: "Inventory item ID is required"}
+	}
+	if params.RawCLIDump == "" {
+		return nil, &ServiceError{Message: "CLI dump is required"}
+	}
+
+	// Verify the inventory item exists and belongs to the user
+	inventoryItem, err := s.inventory.Get(ctx, params.InventoryItemID, userID)
+	if err != nil {
+		s.logger.Error("Failed to verify inventory item", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+	if inventoryItem == nil {
+		return nil, &ServiceError{Message: "Inventory item not found or access denied"}
+	}
+
+	if params.Name == "" {
+		params.Name = "Untitled Config"
+	}
+
+	result := s.parser.Parse(params.RawCLIDump)
+	config := &models.FlightControllerConfig{
+		InventoryItemID: params.InventoryItemID,
+		Name:            params.Name,
+		Notes:           params.Notes,
+		RawCLIDump:      params.RawCLIDump,
+		FirmwareName:    result.FirmwareName,
+		FirmwareVersion: result.FirmwareVersion,
+		BoardTarget:     result.BoardTarget,
+		BoardName:       result.BoardName,
+		MCUType:         result.MCUType,
+		ParseStatus:     result.ParseStatus,
+		ParseWarnings:   result.ParseWarnings,
+		ParsedTuning:    result.ParsedTuning,
+	}
+
+	if err := s.store.SaveConfig(ctx, userID, config); err != nil {
+		s.logger.Error("Failed to save FC config", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+
+	// If the FC is installed on an aircraft, auto-create a tuning snapshot
+	aircraft, err := s.store.GetAircraftByFC(ctx, userID, config.InventoryItemID)
+	if err == nil && aircraft != nil {
+		if err := s.createTuningSnapshot(ctx, userID, aircraft.ID, config); err != nil {
+			s.logger.Warn("Failed to create tuning snapshot from FC config", logging.WithField("error", err.Error()))
+		}
+	}
+
+	return config, nil
+}
+
+// createTuningSnapshot creates a tuning snapshot from a saved FC config
+func (s *Service) createTuningSnapshot(ctx context.Context, userID string, aircraftID string, config *models.FlightControllerConfig) error {
+	tuningData, err := json.Marshal(config.ParsedTuning)
+	if err != nil {
+		tuningData = []byte("{}")
+	}
+
+	snapshot := &models.AircraftTuningSnapshot{
+		AircraftID:               aircraftID,
+		FlightControllerConfigID: config.ID,
+		FirmwareName:             config.FirmwareName,
+		FirmwareVersion:          config.FirmwareVersion,
+		BoardTarget:              config.BoardTarget,
+		BoardName:                config.BoardName,
+		TuningData:               tuningData,
+		ParseStatus:              config.ParseStatus,
+		ParseWarnings:            config.ParseWarnings,
+		Notes:                    "Auto-created from FC config: " + config.Name,
+	}
+
+	return s.store.SaveTuningSnapshot(ctx, userID, snapshot)
+}
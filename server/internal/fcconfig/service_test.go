@@ -0,0 +1,102 @@
+package fcconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/betaflight"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// mockStore implements the Store interface for testing
+type mockStore struct {
+	saved     *models.FlightControllerConfig
+	aircraft  *models.Aircraft
+	snapshots []*models.AircraftTuningSnapshot
+}
+
+func (m *mockStore) SaveConfig(ctx context.Context, userID string, config *models.FlightControllerConfig) error {
+	config.ID = "cfg-1"
+	m.saved = config
+	return nil
+}
+
+func (m *mockStore) GetAircraftByFC(ctx context.Context, userID string, inventoryItemID string) (*models.Aircraft, error) {
+	return m.aircraft, nil
+}
+
+func (m *mockStore) SaveTuningSnapshot(ctx context.Context, userID string, snapshot *models.AircraftTuningSnapshot) error {
+	m.snapshots = append(m.snapshots, snapshot)
+	return nil
+}
+
+// mockInventory returns the items it holds
+type mockInventory struct {
+	items map[string]*models.InventoryItem
+}
+
+func (m *mockInventory) Get(ctx context.Context, id string, userID string) (*models.InventoryItem, error) {
+	return m.items[id], nil
+}
+
+func newTestService(store Store, items ...models.InventoryItem) *Service {
+	inventory := &mockInventory{items: make(map[string]*models.InventoryItem)}
+	for i := range items {
+		inventory.items[items[i].ID] = &items[i]
+	}
+	return &Service{
+		store:     store,
+		inventory: inventory,
+		parser:    betaflight.NewParser(),
+		logger:    testutil.NullLogger(),
+	}
+}
+
+func TestSaveConfig_Validation(t *testing.T) {
+	svc := newTestService(&mockStore{}, models.InventoryItem{ID: "fc-1"})
+	tests := []models.SaveFCConfigParams{
+		{RawCLIDump: "diff all"},
+		{InventoryItemID: "fc-1"},
+		{InventoryItemID: "someone-elses-fc", RawCLIDump: "diff all"},
+	}
+	for _, params := range tests {
+		_, err := svc.SaveConfig(context.Background(), "user-1", params)
+		var svcErr *ServiceError
+		if !errors.As(err, &svcErr) {
+			t.Errorf("SaveConfig(%+v) error = %v, want ServiceError", params, err)
+		}
+	}
+}
+
+func TestSaveConfig_SnapshotsInstalledFC(t *testing.T) {
+	store := &mockStore{aircraft: &models.Aircraft{ID: "air-1"}}
+	svc := newTestService(store, models.InventoryItem{ID: "fc-1"})
+
+	config, err := svc.SaveConfig(context.Background(), "user-1", models.SaveFCConfigParams{
+		InventoryItemID: "fc-1",
+		RawCLIDump:      "# Betaflight / STM32F7X2 (S7X2) 4.4.2\nset gyro_lpf1_static_hz = 250",
+	})
+	if err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if config.Name != "Untitled Config" || store.saved != config {
+		t.Errorf("config = %+v, want the default name saved", config)
+	}
+	if len(store.snapshots) != 1 || store.snapshots[0].AircraftID != "air-1" || store.snapshots[0].FlightControllerConfigID != "cfg-1" {
+		t.Errorf("snapshots = %+v, want one for the aircraft", store.snapshots)
+	}
+}
+
+func TestSaveConfig_NoAircraftNoSnapshot(t *testing.T) {
+	store := &mockStore{}
+	svc := newTestService(store, models.InventoryItem{ID: "fc-1"})
+
+	if _, err := svc.SaveConfig(context.Background(), "user-1", models.SaveFCConfigParams{InventoryItemID: "fc-1", RawCLIDump: "diff all"}); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if len(store.snapshots) != 0 {
+		t.Errorf("snapshots = %+v, want none for a loose FC", store.snapshots)
+	}
+}
@@ -0,0 +1,244 @@
+package flights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ServiceError represents a service-level error
+type ServiceError struct {
+	Message string
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+// maxSessionFlights caps the flights logged in a single session
+const maxSessionFlights = 200
+
+// maxSessionDurationSeconds caps a session's total air time at a full day
+const maxSessionDurationSeconds = 24 * 60 * 60
+
+// Store defines the interface for flight session storage operations
+type Store interface {
+	Create(ctx context.Context, userID string, params models.CreateFlightSessionParams) (*models.FlightSession, error)
+	SetBatteryLog(ctx context.Context, sessionID, batteryID, logID string) error
+	Get(ctx context.Context, id, userID string) (*models.FlightSession, error)
+	List(ctx context.Context, userID string, params models.FlightSessionListParams) (*models.FlightSessionListResponse, error)
+	Delete(ctx context.Context, id, userID string) error
+	AircraftStats(ctx context.Context, aircraftID, userID string) (*models.AircraftFlightStats, error)
+	PilotStats(ctx context.Context, userID string) (*models.PilotFlightStats, error)
+}
+
+// BatteryLogger records pack usage; battery.Service satisfies it
+type BatteryLogger interface {
+	CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error)
+	DeleteLog(ctx context.Context, logID string, userID string) error
+}
+
+// Service handles flight session operations
+type Service struct {
+	store  Store
+	packs  BatteryLogger
+	logger *logging.Logger
+}
+
+// NewService creates a new flight session service
+func NewService(store *database.FlightStore, packs BatteryLogger, logger *logging.Logger) *Service {
+	return &Service{
+		store:  store,
+		packs:  packs,
+		logger: logger,
+	}
+}
+
+// LogSession records a flight session and a battery log entry for every pack
+// used. If a battery log can't be created the session is rolled back.
+func (s *Service) LogSession(ctx context.Context, userID string, params models.CreateFlightSessionParams) (*models.FlightSession, error) {
+	if err := normalizeCreateParams(&params); err != nil {
+		return nil, err
+	}
+
+	session, err := s.store.Create(ctx, userID, params)
+	if errors.Is(err, database.ErrFlightAircraftNotFound) || errors.Is(err, database.ErrFlightBatteryNotFound) {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	if err != nil {
+		s.logger.Error("Failed to create flight session", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+
+	logNotes := "Flight session"
+	if session.Location != "" {
+		logNotes = "Flight session at " + session.Location
+	}
+	flownAt := session.FlownAt
+
+	createdLogs := make([]string, 0, len(session.Batteries))
+	for i := range session.Batteries {
+		pack := &session.Batteries[i]
+		batteryLog, err := s.packs.CreateLog(ctx, userID, models.CreateBatteryLogParams{
+			BatteryID:  pack.BatteryID,
+			AircraftID: session.AircraftID,
+			LoggedAt:   &flownAt,
+			CycleDelta: pack.Cycles,
+			Notes:      logNotes,
+		})
+		if err == nil {
+			err = s.store.SetBatteryLog(ctx, session.ID, pack.BatteryID, batteryLog.ID)
+			createdLogs = append(createdLogs, batteryLog.ID)
+		}
+		if err != nil {
+			s.logger.Error("Failed to log battery for flight session", logging.WithFields(map[string]interface{}{
+				"session_id": session.ID,
+				"battery_id": pack.BatteryID,
+				"error":      err.Error(),
+			}))
+			s.rollbackSession(ctx, userID, session.ID, createdLogs)
+			return nil, fmt.Errorf("failed to log battery %s: %w", pack.BatteryID, err)
+		}
+		pack.BatteryLogID = batteryLog.ID
+	}
+
+	s.logger.Info("Logged flight session", logging.WithFields(map[string]interface{}{
+		"id":          session.ID,
+		"aircraft_id": session.AircraftID,
+		"flights":     session.FlightCount,
+		"packs":       len(session.Batteries),
+	}))
+	return session, nil
+}
+
+func (s *Service) rollbackSession(ctx context.Context, userID string, sessionID string, logIDs []string) {
+	for _, logID := range logIDs {
+		if err := s.packs.DeleteLog(ctx, logID, userID); err != nil {
+			s.logger.Warn("Failed to remove battery log while rolling back flight session", logging.WithFields(map[string]interface{}{
+				"log_id": logID,
+				"error":  err.Error(),
+			}))
+		}
+	}
+	if err := s.store.Delete(ctx, sessionID, userID); err != nil {
+		s.logger.Warn("Failed to remove flight session during rollback", logging.WithFields(map[string]interface{}{
+			"id":    sessionID,
+			"error": err.Error(),
+		}))
+	}
+}
+
+// Get retrieves a flight session
+func (s *Service) Get(ctx context.Context, id string, userID string) (*models.FlightSession, error) {
+	return s.store.Get(ctx, id, userID)
+}
+
+// List lists a user's flight sessions, optionally for one aircraft
+func (s *Service) List(ctx context.Context, userID string, params models.FlightSessionListParams) (*models.FlightSessionListResponse, error) {
+	params.AircraftID = strings.TrimSpace(params.AircraftID)
+	if params.Limit <= 0 || params.Limit > 200 {
+		params.Limit = 50
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+	return s.store.List(ctx, userID, params)
+}
+
+// Delete removes a flight session along with the battery logs it created
+func (s *Service) Delete(ctx context.Context, id string, userID string) error {
+	if id == "" {
+		return &ServiceError{Message: "id is required"}
+	}
+
+	session, err := s.store.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return &ServiceError{Message: "flight session not found"}
+	}
+
+	for _, pack := range session.Batteries {
+		if pack.BatteryLogID == "" {
+			continue
+		}
+		if err := s.packs.DeleteLog(ctx, pack.BatteryLogID, userID); err != nil {
+			// The pack's log may already have been deleted from the battery page
+			s.logger.Warn("Failed to delete battery log for flight session", logging.WithFields(map[string]interface{}{
+				"id":     id,
+				"log_id": pack.BatteryLogID,
+				"error":  err.Error(),
+			}))
+		}
+	}
+
+	if err := s.store.Delete(ctx, id, userID); err != nil {
+		s.logger.Error("Failed to delete flight session", logging.WithField("error", err.Error()))
+		return err
+	}
+
+	s.logger.Info("Deleted flight session", logging.WithField("id", id))
+	return nil
+}
+
+// AircraftStats returns an aircraft's cumulative flight time and the flight
+// time of each installed component
+func (s *Service) AircraftStats(ctx context.Context, aircraftID string, userID string) (*models.AircraftFlightStats, error) {
+	aircraftID = strings.TrimSpace(aircraftID)
+	if aircraftID == "" {
+		return nil, &ServiceError{Message: "aircraftId is required"}
+	}
+	stats, err := s.store.AircraftStats(ctx, aircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, &ServiceError{Message: "aircraft not found"}
+	}
+	return stats, nil
+}
+
+// PilotStats returns a pilot's flying totals
+func (s *Service) PilotStats(ctx context.Context, userID string) (*models.PilotFlightStats, error) {
+	return s.store.PilotStats(ctx, userID)
+}
+
+func normalizeCreateParams(params *models.CreateFlightSessionParams) error {
+	params.AircraftID = strings.TrimSpace(params.AircraftID)
+	if params.AircraftID == "" {
+		return &ServiceError{Message: "aircraftId is required"}
+	}
+	params.Location = strings.TrimSpace(params.Location)
+	params.Notes = strings.TrimSpace(params.Notes)
+	params.Batteries = models.NormalizeFlightBatteries(params.Batteries)
+
+	if params.FlightCount < 0 {
+		return &ServiceError{Message: "flightCount cannot be negative"}
+	}
+	if params.FlightCount == 0 {
+		// One flight per pack cycle, or a single flight when no packs are listed
+		for _, battery := range params.Batteries {
+			params.FlightCount += battery.Cycles
+		}
+		if params.FlightCount == 0 {
+			params.FlightCount = 1
+		}
+	}
+	if params.FlightCount > maxSessionFlights {
+		return &ServiceError{Message: fmt.Sprintf("flightCount cannot exceed %d", maxSessionFlights)}
+	}
+
+	if params.DurationSeconds < 0 {
+		return &ServiceError{Message: "durationSeconds cannot be negative"}
+	}
+	if params.DurationSeconds > maxSessionDurationSeconds {
+		return &ServiceError{Message: "durationSeconds cannot exceed 24 hours"}
+	}
+	return nil
+}
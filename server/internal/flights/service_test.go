@@ -0,0 +1,249 @@
+package flights
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// mockStore implements the Store interface for testing
+type mockStore struct {
+	created   *models.CreateFlightSessionParams
+	session   *models.FlightSession
+	linked    map[string]string
+	deleted   []string
+	aircraft  *models.AircraftFlightStats
+	createErr error
+}
+
+func (m *mockStore) Create(ctx context.Context, userID string, params models.CreateFlightSessionParams) (*models.FlightSession, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = &params
+	session := &models.FlightSession{
+		ID:              "session-1",
+		UserID:          userID,
+		AircraftID:      params.AircraftID,
+		Location:        params.Location,
+		FlightCount:     params.FlightCount,
+		DurationSeconds: params.DurationSeconds,
+		Notes:           params.Notes,
+	}
+	for _, battery := range params.Batteries {
+		session.Batteries = append(session.Batteries, models.FlightSessionBattery{
+			BatteryID: battery.BatteryID,
+			Cycles:    battery.Cycles,
+		})
+	}
+	m.session = session
+	return session, nil
+}
+
+func (m *mockStore) SetBatteryLog(ctx context.Context, sessionID, batteryID, logID string) error {
+	if m.linked == nil {
+		m.linked = make(map[string]string)
+	}
+	m.linked[batteryID] = logID
+	return nil
+}
+
+func (m *mockStore) Get(ctx context.Context, id, userID string) (*models.FlightSession, error) {
+	if m.session == nil || m.session.ID != id {
+		return nil, nil
+	}
+	return m.session, nil
+}
+
+func (m *mockStore) List(ctx context.Context, userID string, params models.FlightSessionListParams) (*models.FlightSessionListResponse, error) {
+	return &models.FlightSessionListResponse{}, nil
+}
+
+func (m *mockStore) Delete(ctx context.Context, id, userID string) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockStore) AircraftStats(ctx context.Context, aircraftID, userID string) (*models.AircraftFlightStats, error) {
+	return m.aircraft, nil
+}
+
+func (m *mockStore) PilotStats(ctx context.Context, userID string) (*models.PilotFlightStats, error) {
+	return &models.PilotFlightStats{}, nil
+}
+
+// mockBatteryLogger implements BatteryLogger for testing
+type mockBatteryLogger struct {
+	logs    []models.CreateBatteryLogParams
+	deleted []string
+	failOn  string
+}
+
+func (m *mockBatteryLogger) CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error) {
+	if params.BatteryID == m.failOn {
+		return nil, errors.New("battery not found")
+	}
+	m.logs = append(m.logs, params)
+	return &models.BatteryLog{ID: "log-" + params.BatteryID, BatteryID: params.BatteryID}, nil
+}
+
+func (m *mockBatteryLogger) DeleteLog(ctx context.Context, logID string, userID string) error {
+	m.deleted = append(m.deleted, logID)
+	return nil
+}
+
+func newTestService(store *mockStore, packs *mockBatteryLogger) *Service {
+	return &Service{
+		store:  store,
+		packs:  packs,
+		logger: testutil.NullLogger(),
+	}
+}
+
+func TestLogSession_CreatesBatteryLogs(t *testing.T) {
+	store := &mockStore{}
+	packs := &mockBatteryLogger{}
+	svc := newTestService(store, packs)
+
+	session, err := svc.LogSession(context.Background(), "user-1", models.CreateFlightSessionParams{
+		AircraftID:      " ac-1 ",
+		Location:        " Local field ",
+		DurationSeconds: 900,
+		Batteries: []models.FlightBatteryParams{
+			{BatteryID: "bat-1"},
+			{BatteryID: "bat-2", Cycles: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("LogSession() error = %v", err)
+	}
+
+	if store.created.AircraftID != "ac-1" || store.created.Location != "Local field" {
+		t.Errorf("params not trimmed: %+v", store.created)
+	}
+	if session.FlightCount != 3 {
+		t.Errorf("FlightCount = %d, want 3 (one per pack cycle)", session.FlightCount)
+	}
+	if len(packs.logs) != 2 {
+		t.Fatalf("battery logs created = %d, want 2", len(packs.logs))
+	}
+	if packs.logs[1].CycleDelta != 2 || packs.logs[1].AircraftID != "ac-1" {
+		t.Errorf("battery log params = %+v", packs.logs[1])
+	}
+	if packs.logs[0].Notes != "Flight session at Local field" {
+		t.Errorf("battery log notes = %q", packs.logs[0].Notes)
+	}
+	if store.linked["bat-2"] != "log-bat-2" || session.Batteries[1].BatteryLogID != "log-bat-2" {
+		t.Errorf("battery log not linked to session: %+v", session.Batteries)
+	}
+}
+
+func TestLogSession_DefaultsToSingleFlight(t *testing.T) {
+	store := &mockStore{}
+	svc := newTestService(store, &mockBatteryLogger{})
+
+	session, err := svc.LogSession(context.Background(), "user-1", models.CreateFlightSessionParams{AircraftID: "ac-1"})
+	if err != nil {
+		t.Fatalf("LogSession() error = %v", err)
+	}
+	if session.FlightCount != 1 {
+		t.Errorf("FlightCount = %d, want 1", session.FlightCount)
+	}
+}
+
+func TestLogSession_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		params models.CreateFlightSessionParams
+	}{
+		{"missing aircraft", models.CreateFlightSessionParams{}},
+		{"negative flights", models.CreateFlightSessionParams{AircraftID: "ac-1", FlightCount: -1}},
+		{"too many flights", models.CreateFlightSessionParams{AircraftID: "ac-1", FlightCount: maxSessionFlights + 1}},
+		{"negative duration", models.CreateFlightSessionParams{AircraftID: "ac-1", DurationSeconds: -5}},
+		{"duration over a day", models.CreateFlightSessionParams{AircraftID: "ac-1", DurationSeconds: maxSessionDurationSeconds + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStore{}
+			svc := newTestService(store, &mockBatteryLogger{})
+
+			_, err := svc.LogSession(context.Background(), "user-1", tt.params)
+			var svcErr *ServiceError
+			if !errors.As(err, &svcErr) {
+				t.Fatalf("LogSession() error = %v, want ServiceError", err)
+			}
+			if store.created != nil {
+				t.Error("store should not be called for invalid params")
+			}
+		})
+	}
+}
+
+func TestLogSession_RollsBackOnBatteryFailure(t *testing.T) {
+	store := &mockStore{}
+	packs := &mockBatteryLogger{failOn: "bat-2"}
+	svc := newTestService(store, packs)
+
+	_, err := svc.LogSession(context.Background(), "user-1", models.CreateFlightSessionParams{
+		AircraftID: "ac-1",
+		Batteries: []models.FlightBatteryParams{
+			{BatteryID: "bat-1"},
+			{BatteryID: "bat-2"},
+		},
+	})
+	if err == nil {
+		t.Fatal("LogSession() expected error")
+	}
+	if len(packs.deleted) != 1 || packs.deleted[0] != "log-bat-1" {
+		t.Errorf("deleted battery logs = %v, want [log-bat-1]", packs.deleted)
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != "session-1" {
+		t.Errorf("deleted sessions = %v, want [session-1]", store.deleted)
+	}
+}
+
+func TestDelete_RemovesBatteryLogs(t *testing.T) {
+	store := &mockStore{session: &models.FlightSession{
+		ID: "session-1",
+		Batteries: []models.FlightSessionBattery{
+			{BatteryID: "bat-1", BatteryLogID: "log-1"},
+			{BatteryID: "bat-2"},
+		},
+	}}
+	packs := &mockBatteryLogger{}
+	svc := newTestService(store, packs)
+
+	if err := svc.Delete(context.Background(), "session-1", "user-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(packs.deleted) != 1 || packs.deleted[0] != "log-1" {
+		t.Errorf("deleted battery logs = %v, want [log-1]", packs.deleted)
+	}
+	if len(store.deleted) != 1 {
+		t.Errorf("session not deleted")
+	}
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	svc := newTestService(&mockStore{}, &mockBatteryLogger{})
+
+	err := svc.Delete(context.Background(), "missing", "user-1")
+	var svcErr *ServiceError
+	if !errors.As(err, &svcErr) || svcErr.Message != "flight session not found" {
+		t.Fatalf("Delete() error = %v, want flight session not found", err)
+	}
+}
+
+func TestAircraftStats_NotFound(t *testing.T) {
+	svc := newTestService(&mockStore{}, &mockBatteryLogger{})
+
+	_, err := svc.AircraftStats(context.Background(), "ac-1", "user-1")
+	var svcErr *ServiceError
+	if !errors.As(err, &svcErr) || svcErr.Message != "aircraft not found" {
+		t.Fatalf("AircraftStats() error = %v, want aircraft not found", err)
+	}
+}
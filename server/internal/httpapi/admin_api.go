@@ -63,6 +63,11 @@ func (api *AdminAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(http
 		mux.HandleFunc("/api/admin/builds", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminBuilds))))
 		mux.HandleFunc("/api/admin/builds/", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminBuildByID))))
 	}
+	if api.imageSvc != nil {
+		mux.HandleFunc("/api/admin/images", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminImages))))
+		mux.HandleFunc("/api/admin/images/duplicates", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminImageDuplicates))))
+		mux.HandleFunc("/api/admin/images/", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminImageByID))))
+	}
 	if api.announcementSvc != nil {
 		mux.HandleFunc("/api/admin/announcements", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminAnnouncements))))
 		mux.HandleFunc("/api/admin/announcements/", corsMiddleware(api.authMiddleware.RequireAuth(api.requireContentModerator(api.handleAdminAnnouncementByID))))
@@ -602,6 +607,17 @@ func (api *AdminAPI) handleAdminBuildByID(w http.ResponseWriter, r *http.Request
 		case "image":
 			api.handleAdminBuildImage(w, r, buildID)
 			return
+		case "gallery":
+			if len(parts) != 4 || parts[3] != "image" {
+				api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+				return
+			}
+			if r.Method != http.MethodGet {
+				api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+				return
+			}
+			api.getAdminBuildGalleryImage(w, r, buildID, strings.TrimSpace(parts[2]))
+			return
 		case "publish":
 			if r.Method != http.MethodPost {
 				api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
@@ -965,7 +981,7 @@ func (api *AdminAPI) uploadAdminBuildImage(w http.ResponseWriter, r *http.Reques
 		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to upload image"})
 		return
 	}
-	if decision.Status != models.ImageModerationApproved {
+	if !decision.Accepted() {
 		statusCode := http.StatusUnprocessableEntity
 		if decision.Status == models.ImageModerationPendingReview {
 			statusCode = http.StatusServiceUnavailable
@@ -977,31 +993,56 @@ func (api *AdminAPI) uploadAdminBuildImage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	api.writeJSON(w, http.StatusOK, map[string]string{"message": "Image uploaded successfully"})
+	statusCode, message := uploadOutcome(decision)
+	api.writeJSON(w, statusCode, map[string]string{"message": message})
 }
 
 func (api *AdminAPI) getAdminBuildImage(w http.ResponseWriter, r *http.Request, buildID string) {
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	imageData, imageType, err := api.buildSvc.GetImageForModeration(ctx, buildID)
+	size, ok := imageSizeParam(r)
+	if !ok {
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "size must be thumb, card or full"})
+		return
+	}
+
+	stream, err := api.buildSvc.GetImageForModeration(ctx, buildID, size)
 	if err != nil {
 		api.logger.Error("Failed to get moderation build image", logging.WithField("error", err.Error()))
 		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get image"})
 		return
 	}
-	if len(imageData) == 0 {
+	if stream == nil {
 		api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "no image for this build"})
 		return
 	}
-	if imageType == "" {
-		imageType = http.DetectContentType(imageData)
+
+	_ = writeImageStream(w, r, stream, "no-cache, no-store, must-revalidate")
+}
+
+func (api *AdminAPI) getAdminBuildGalleryImage(w http.ResponseWriter, r *http.Request, buildID string, imageID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	size, ok := imageSizeParam(r)
+	if !ok {
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "size must be thumb, card or full"})
+		return
 	}
 
-	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Content-Length", strconv.Itoa(len(imageData)))
-	_, _ = w.Write(imageData)
+	stream, err := api.buildSvc.GetGalleryImageForModeration(ctx, buildID, imageID, size)
+	if err != nil {
+		api.logger.Error("Failed to get moderation build gallery image", logging.WithField("error", err.Error()))
+		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get image"})
+		return
+	}
+	if stream == nil {
+		api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "image not found"})
+		return
+	}
+
+	_ = writeImageStream(w, r, stream, "no-cache, no-store, must-revalidate")
 }
 
 func (api *AdminAPI) deleteAdminBuildImage(w http.ResponseWriter, r *http.Request, buildID string) {
@@ -1022,6 +1063,203 @@ func (api *AdminAPI) deleteAdminBuildImage(w http.ResponseWriter, r *http.Reques
 	api.writeJSON(w, http.StatusOK, map[string]string{"message": "Image deleted successfully"})
 }
 
+// handleAdminImages handles GET /api/admin/images (the image review queue).
+func (api *AdminAPI) handleAdminImages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	query := r.URL.Query()
+	status := models.ImageModerationStatus(strings.ToUpper(strings.TrimSpace(query.Get("status"))))
+	switch status {
+	case "", models.ImageModerationPendingReview, models.ImageModerationApproved, models.ImageModerationRejected:
+		// valid
+	default:
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid status"})
+		return
+	}
+
+	params := models.ImageReviewListParams{
+		Status: status,
+		Limit:  parseIntQuery(query.Get("limit"), 20),
+		Offset: parseIntQuery(query.Get("offset"), 0),
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	response, err := api.imageSvc.ListReviews(ctx, params)
+	if err != nil {
+		api.writeImageReviewError(w, "list image reviews", err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// handleAdminImageDuplicates handles GET /api/admin/images/duplicates: pairs of
+// near-identical images across gear catalog items and builds.
+func (api *AdminAPI) handleAdminImageDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	query := r.URL.Query()
+	entityType := models.ImageEntityType(strings.ToLower(strings.TrimSpace(query.Get("entityType"))))
+	switch entityType {
+	case "", models.ImageEntityGear, models.ImageEntityBuild:
+		// valid
+	default:
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "entityType must be gear or build"})
+		return
+	}
+
+	params := models.ImageDuplicateListParams{
+		EntityType:  entityType,
+		MaxDistance: parseIntQuery(query.Get("maxDistance"), images.DefaultDuplicateDistance),
+		Limit:       parseIntQuery(query.Get("limit"), 50),
+		Offset:      parseIntQuery(query.Get("offset"), 0),
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	response, err := api.imageSvc.ListDuplicates(ctx, params)
+	if err != nil {
+		if errors.Is(err, images.ErrDuplicatesUnsupported) {
+			api.writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "duplicate detection unavailable"})
+			return
+		}
+		api.logger.Error("Failed to list duplicate images", logging.WithField("error", err.Error()))
+		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to list duplicate images"})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// handleAdminImageByID handles /api/admin/images/{id}[/image|/approve|/reject].
+func (api *AdminAPI) handleAdminImageByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/images/"), "/")
+	parts := strings.Split(path, "/")
+	imageID := strings.TrimSpace(parts[0])
+	if imageID == "" {
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "image ID required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		review, err := api.imageSvc.GetReview(ctx, imageID)
+		if err != nil {
+			api.writeImageReviewError(w, "get image review", err)
+			return
+		}
+		if review == nil {
+			api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "image not found"})
+			return
+		}
+		api.writeJSON(w, http.StatusOK, review)
+		return
+	}
+	if len(parts) != 2 {
+		api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	switch parts[1] {
+	case "image":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		api.getAdminReviewImage(w, r, ctx, imageID)
+	case "approve", "reject":
+		if r.Method != http.MethodPost {
+			api.writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		var params models.ImageReviewDecisionParams
+		if err := decodeJSONAllowEmpty(r, &params); err != nil {
+			api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+			return
+		}
+
+		reviewerID := auth.GetUserID(r.Context())
+		var review *models.ImageReview
+		var err error
+		if parts[1] == "approve" {
+			review, err = api.imageSvc.ApproveReview(ctx, imageID, reviewerID, params.Reason)
+		} else {
+			review, err = api.imageSvc.RejectReview(ctx, imageID, reviewerID, params.Reason)
+		}
+		if err != nil {
+			api.writeImageReviewError(w, parts[1]+" image", err)
+			return
+		}
+		if review == nil {
+			api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "image not found"})
+			return
+		}
+
+		api.logger.Info("Image review decided", logging.WithFields(map[string]interface{}{
+			"imageId":    review.ImageID,
+			"status":     string(review.Status),
+			"reviewerId": reviewerID,
+		}))
+		api.writeJSON(w, http.StatusOK, review)
+	default:
+		api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown image action"})
+	}
+}
+
+// getAdminReviewImage serves the real bytes of an image, including one awaiting review.
+func (api *AdminAPI) getAdminReviewImage(w http.ResponseWriter, r *http.Request, ctx context.Context, imageID string) {
+	size, ok := imageSizeParam(r)
+	if !ok {
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "size must be thumb, card or full"})
+		return
+	}
+
+	stream, err := api.imageSvc.OpenForReview(ctx, imageID, size)
+	if err != nil {
+		api.logger.Error("Failed to open image for review", logging.WithField("error", err.Error()))
+		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get image"})
+		return
+	}
+	if stream == nil || stream.Asset == nil || stream.Asset.Status == models.ImageModerationRejected {
+		if stream != nil {
+			stream.Body.Close()
+		}
+		api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "image not found"})
+		return
+	}
+
+	_ = writeImageStream(w, r, stream, "no-cache, no-store, must-revalidate")
+}
+
+func (api *AdminAPI) writeImageReviewError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, images.ErrNotPendingReview):
+		api.writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, images.ErrReviewReasonRequired), errors.Is(err, images.ErrReviewReasonTooLong):
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, images.ErrReviewUnsupported):
+		api.writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "image review unavailable"})
+	default:
+		api.logger.Error("Failed to "+action, logging.WithField("error", err.Error()))
+		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to " + action})
+	}
+}
+
 // handleAdminUsers handles GET /api/admin/users for searching users.
 func (api *AdminAPI) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -1420,7 +1658,7 @@ func (api *AdminAPI) uploadGearImage(w http.ResponseWriter, r *http.Request, id
 		})
 		return
 	}
-	if decision.Status != models.ImageModerationApproved {
+	if !decision.Accepted() {
 		statusCode := http.StatusUnprocessableEntity
 		if decision.Status == models.ImageModerationPendingReview {
 			statusCode = http.StatusServiceUnavailable
@@ -1449,10 +1687,7 @@ func (api *AdminAPI) uploadGearImage(w http.ResponseWriter, r *http.Request, id
 		logging.WithField("size", len(imageData)),
 	)
 
-	api.writeJSON(w, http.StatusOK, map[string]string{
-		"status":  string(models.ImageModerationApproved),
-		"message": "Image uploaded successfully",
-	})
+	api.writeGearUploadResponse(w, ctx, decision, asset)
 }
 
 func (api *AdminAPI) persistApprovedGearUpload(w http.ResponseWriter, r *http.Request, ctx context.Context, id string, userID string) {
@@ -1521,10 +1756,21 @@ func (api *AdminAPI) persistApprovedGearUpload(w http.ResponseWriter, r *http.Re
 		logging.WithField("adminId", userID),
 	)
 
-	api.writeJSON(w, http.StatusOK, map[string]string{
-		"status":  string(models.ImageModerationApproved),
-		"message": "Image uploaded successfully",
-	})
+	api.writeGearUploadResponse(w, ctx, images.AssetDecision(asset), asset)
+}
+
+// writeGearUploadResponse reports a stored catalog image, warning when it
+// nearly matches the image of another catalog item.
+func (api *AdminAPI) writeGearUploadResponse(w http.ResponseWriter, ctx context.Context, decision *models.ModerationDecision, asset *models.ImageAsset) {
+	statusCode, message := uploadOutcome(decision)
+	response := map[string]interface{}{
+		"status":  string(decision.Status),
+		"message": message,
+	}
+	if duplicates := uploadDuplicateWarnings(ctx, api.imageSvc, api.logger, asset); len(duplicates) > 0 {
+		response["duplicates"] = duplicates
+	}
+	api.writeJSON(w, statusCode, response)
 }
 
 func (api *AdminAPI) attachAdminGearImageAsset(ctx context.Context, gearID string, adminUserID string, contentType string, assetID string) error {
@@ -1549,7 +1795,17 @@ func (api *AdminAPI) getGearImage(w http.ResponseWriter, r *http.Request, id str
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	imageData, imageType, err := api.catalogStore.GetImage(ctx, id)
+	size, ok := imageSizeParam(r)
+	if !ok {
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "size must be thumb, card or full"})
+		return
+	}
+
+	ref, err := api.catalogStore.GetImage(ctx, id)
+	var stream *images.ImageStream
+	if err == nil {
+		stream, err = api.imageSvc.OpenRef(ctx, ref, size)
+	}
 	if err != nil {
 		api.logger.Error("Failed to get gear image", logging.WithFields(map[string]interface{}{
 			"gearId": id,
@@ -1559,19 +1815,13 @@ func (api *AdminAPI) getGearImage(w http.ResponseWriter, r *http.Request, id str
 		return
 	}
 
-	if imageData == nil {
+	if stream == nil {
 		api.writeJSON(w, http.StatusNotFound, map[string]string{"error": "no image for this gear item"})
 		return
 	}
-	if imageType == "" {
-		imageType = http.DetectContentType(imageData)
-	}
 
 	// No caching for admin endpoint - admins need to see latest image
-	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Content-Length", strconv.Itoa(len(imageData)))
-	w.Write(imageData)
+	_ = writeImageStream(w, r, stream, "no-cache, no-store, must-revalidate")
 }
 
 // deleteGearImage handles DELETE /api/admin/gear/{id}/image
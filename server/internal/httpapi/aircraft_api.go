@@ -3,6 +3,7 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
@@ -37,6 +38,7 @@ func (api *AircraftAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(h
 	// Aircraft routes (require authentication)
 	mux.HandleFunc("/api/aircraft", corsMiddleware(api.authMiddleware.RequireAuth(api.handleAircraft)))
 	mux.HandleFunc("/api/aircraft/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleAircraftItem)))
+	mux.HandleFunc("/api/aircraft/radio-links", corsMiddleware(api.authMiddleware.RequireAuth(api.getRadioLinks)))
 }
 
 // handleAircraft handles list and create operations
@@ -142,6 +144,25 @@ func (api *AircraftAPI) handleAircraftItem(w http.ResponseWriter, r *http.Reques
 		case "image":
 			api.handleImage(w, r, aircraftID)
 			return
+		case "gallery":
+			api.handleGallery(w, r, aircraftID, parts[2:])
+			return
+		case "batteries":
+			if len(parts) > 2 && parts[2] != "" {
+				api.handleBatteryItem(w, r, aircraftID, parts[2])
+				return
+			}
+			api.handleBatteries(w, r, aircraftID)
+			return
+		case "compatible-batteries":
+			api.getCompatibleBatteries(w, r, aircraftID)
+			return
+		case "maintenance":
+			api.handleMaintenance(w, r, aircraftID, parts[2:])
+			return
+		case "cost":
+			api.getCostOfOwnership(w, r, aircraftID)
+			return
 		default:
 			http.Error(w, "Unknown resource", http.StatusNotFound)
 			return
@@ -332,7 +353,7 @@ func (api *AircraftAPI) setComponent(w http.ResponseWriter, r *http.Request, air
 			"category":    params.Category,
 			"error":       err.Error(),
 		}))
-		api.writeJSON(w, http.StatusInternalServerError, map[string]string{
+		api.writeJSON(w, aircraftErrorStatus(err), map[string]string{
 			"error": err.Error(),
 		})
 		return
@@ -347,6 +368,171 @@ func (api *AircraftAPI) setComponent(w http.ResponseWriter, r *http.Request, air
 	api.writeJSON(w, http.StatusOK, component)
 }
 
+// handleBatteries handles the battery packs assigned to an aircraft
+func (api *AircraftAPI) handleBatteries(w http.ResponseWriter, r *http.Request, aircraftID string) {
+	switch r.Method {
+	case http.MethodGet:
+		api.listBatteries(w, r, aircraftID)
+	case http.MethodPost, http.MethodPut:
+		api.assignBattery(w, r, aircraftID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleBatteryItem handles a single assigned battery pack
+func (api *AircraftAPI) handleBatteryItem(w http.ResponseWriter, r *http.Request, aircraftID string, batteryID string) {
+	switch r.Method {
+	case http.MethodDelete:
+		api.unassignBattery(w, r, aircraftID, batteryID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listBatteries lists the battery packs assigned to an aircraft with compatibility
+func (api *AircraftAPI) listBatteries(w http.ResponseWriter, r *http.Request, aircraftID string) {
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	batteries, err := api.aircraftSvc.ListBatteries(ctx, aircraftID, userID)
+	if err != nil {
+		api.logger.Error("List aircraft batteries failed", logging.WithFields(map[string]interface{}{
+			"aircraft_id": aircraftID,
+			"error":       err.Error(),
+		}))
+		api.writeJSON(w, aircraftErrorStatus(err), map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"batteries": batteries,
+		"count":     len(batteries),
+	})
+}
+
+// assignBattery assigns a battery pack to an aircraft after checking compatibility
+func (api *AircraftAPI) assignBattery(w http.ResponseWriter, r *http.Request, aircraftID string) {
+	userID := auth.GetUserID(r.Context())
+
+	var params models.AssignAircraftBatteryParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	params.AircraftID = aircraftID
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	assigned, err := api.aircraftSvc.AssignBattery(ctx, userID, params)
+	if err != nil {
+		api.logger.Error("Assign battery failed", logging.WithFields(map[string]interface{}{
+			"aircraft_id": aircraftID,
+			"battery_id":  params.BatteryID,
+			"error":       err.Error(),
+		}))
+		api.writeJSON(w, aircraftErrorStatus(err), map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, assigned)
+}
+
+// unassignBattery removes a battery pack from an aircraft
+func (api *AircraftAPI) unassignBattery(w http.ResponseWriter, r *http.Request, aircraftID string, batteryID string) {
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := api.aircraftSvc.UnassignBattery(ctx, aircraftID, batteryID, userID); err != nil {
+		api.logger.Error("Unassign battery failed", logging.WithFields(map[string]interface{}{
+			"aircraft_id": aircraftID,
+			"battery_id":  batteryID,
+			"error":       err.Error(),
+		}))
+		api.writeJSON(w, aircraftErrorStatus(err), map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getCompatibleBatteries checks all of the user's batteries against an aircraft
+func (api *AircraftAPI) getCompatibleBatteries(w http.ResponseWriter, r *http.Request, aircraftID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	result, err := api.aircraftSvc.FindCompatibleBatteries(ctx, aircraftID, userID)
+	if err != nil {
+		api.logger.Error("Find compatible batteries failed", logging.WithFields(map[string]interface{}{
+			"aircraft_id": aircraftID,
+			"error":       err.Error(),
+		}))
+		api.writeJSON(w, aircraftErrorStatus(err), map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, result)
+}
+
+// getRadioLinks links the EdgeTX models on the user's radios to their aircraft
+// and reports model match collisions across the fleet
+func (api *AircraftAPI) getRadioLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	links, err := api.aircraftSvc.GetRadioLinks(ctx, userID)
+	if err != nil {
+		api.logger.Error("Get radio links failed", logging.WithField("error", err.Error()))
+		api.writeJSON(w, aircraftErrorStatus(err), map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, links)
+}
+
+// aircraftErrorStatus maps missing records to 404, service validation errors
+// to 400 and everything else to 500
+func aircraftErrorStatus(err error) int {
+	var notFoundErr *aircraft.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return http.StatusNotFound
+	}
+	var svcErr *aircraft.ServiceError
+	if errors.As(err, &svcErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // removeComponent removes a component from an aircraft
 func (api *AircraftAPI) removeComponent(w http.ResponseWriter, r *http.Request, aircraftID string) {
 	userID := auth.GetUserID(r.Context())
@@ -597,7 +783,7 @@ func (api *AircraftAPI) uploadImage(w http.ResponseWriter, r *http.Request, airc
 		})
 		return
 	}
-	if decision.Status != models.ImageModerationApproved {
+	if !decision.Accepted() {
 		statusCode := http.StatusUnprocessableEntity
 		if decision.Status == models.ImageModerationPendingReview {
 			statusCode = http.StatusServiceUnavailable
@@ -610,12 +796,253 @@ func (api *AircraftAPI) uploadImage(w http.ResponseWriter, r *http.Request, airc
 		return
 	}
 
-	api.writeJSON(w, http.StatusOK, map[string]string{
+	statusCode, message := uploadOutcome(decision)
+	api.writeJSON(w, statusCode, map[string]string{
 		"status":  string(decision.Status),
-		"message": "Image uploaded successfully",
+		"message": message,
 	})
 }
 
+// handleGallery routes /api/aircraft/{id}/gallery[/...]
+func (api *AircraftAPI) handleGallery(w http.ResponseWriter, r *http.Request, aircraftID string, rest []string) {
+	userID := auth.GetUserID(r.Context())
+	if len(rest) > 0 && rest[len(rest)-1] == "" {
+		rest = rest[:len(rest)-1]
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	switch {
+	case len(rest) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			gallery, err := api.aircraftSvc.ListGallery(ctx, aircraftID, userID)
+			if err != nil {
+				api.writeGalleryError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, map[string]interface{}{"images": gallery})
+		case http.MethodPost:
+			params, reqErr := readGalleryUpload(w, r)
+			if reqErr != nil {
+				api.writeJSON(w, reqErr.status, map[string]string{"error": reqErr.message})
+				return
+			}
+			decision, image, err := api.aircraftSvc.AddGalleryImage(ctx, aircraftID, userID, params)
+			if writeGalleryModeration(w, err, decision) {
+				return
+			}
+			if err != nil {
+				api.writeGalleryError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusCreated, map[string]interface{}{
+				"status": string(decision.Status),
+				"image":  image,
+			})
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case len(rest) == 1 && rest[0] == "order":
+		if r.Method != http.MethodPut {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var params models.ReorderGalleryParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+			return
+		}
+		gallery, err := api.aircraftSvc.ReorderGallery(ctx, aircraftID, userID, params.ImageIDs)
+		if err != nil {
+			api.writeGalleryError(w, aircraftID, err)
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{"images": gallery})
+	case len(rest) == 1:
+		imageID := strings.TrimSpace(rest[0])
+		switch r.Method {
+		case http.MethodPatch, http.MethodPut:
+			var params models.UpdateGalleryImageParams
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+				return
+			}
+			image, err := api.aircraftSvc.UpdateGalleryImage(ctx, aircraftID, userID, imageID, params)
+			if err != nil {
+				api.writeGalleryError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, image)
+		case http.MethodDelete:
+			if err := api.aircraftSvc.DeleteGalleryImage(ctx, aircraftID, userID, imageID); err != nil {
+				api.writeGalleryError(w, aircraftID, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case len(rest) == 2 && rest[1] == "image":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		size, ok := imageSizeParam(r)
+		if !ok {
+			http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+			return
+		}
+		stream, err := api.aircraftSvc.GetGalleryImage(ctx, aircraftID, userID, strings.TrimSpace(rest[0]), size)
+		if err != nil {
+			api.logger.Error("Failed to get aircraft gallery image", logging.WithFields(map[string]interface{}{
+				"aircraft_id": aircraftID,
+				"error":       err.Error(),
+			}))
+			http.Error(w, "Image not found", http.StatusNotFound)
+			return
+		}
+		if stream == nil {
+			http.Error(w, "Image not found", http.StatusNotFound)
+			return
+		}
+		_ = writeImageStream(w, r, stream, "private, max-age=3600")
+	default:
+		http.Error(w, "Unknown resource", http.StatusNotFound)
+	}
+}
+
+// handleMaintenance handles /api/aircraft/{id}/maintenance[/{eventId}]
+func (api *AircraftAPI) handleMaintenance(w http.ResponseWriter, r *http.Request, aircraftID string, rest []string) {
+	userID := auth.GetUserID(r.Context())
+	if len(rest) > 0 && rest[len(rest)-1] == "" {
+		rest = rest[:len(rest)-1]
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	switch len(rest) {
+	case 0:
+		switch r.Method {
+		case http.MethodGet:
+			events, err := api.aircraftSvc.ListMaintenance(ctx, aircraftID, userID)
+			if err != nil {
+				api.writeMaintenanceError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, map[string]interface{}{
+				"events": events,
+				"count":  len(events),
+			})
+		case http.MethodPost:
+			var params models.CreateMaintenanceEventParams
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+				return
+			}
+			event, err := api.aircraftSvc.LogMaintenance(ctx, aircraftID, userID, params)
+			if err != nil {
+				api.writeMaintenanceError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusCreated, event)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case 1:
+		eventID := strings.TrimSpace(rest[0])
+		switch r.Method {
+		case http.MethodGet:
+			event, err := api.aircraftSvc.GetMaintenanceEvent(ctx, aircraftID, userID, eventID)
+			if err != nil {
+				api.writeMaintenanceError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, event)
+		case http.MethodPut, http.MethodPatch:
+			var params models.UpdateMaintenanceEventParams
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+				return
+			}
+			params.ID = eventID
+			event, err := api.aircraftSvc.UpdateMaintenanceEvent(ctx, aircraftID, userID, params)
+			if err != nil {
+				api.writeMaintenanceError(w, aircraftID, err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, event)
+		case http.MethodDelete:
+			if err := api.aircraftSvc.DeleteMaintenanceEvent(ctx, aircraftID, userID, eventID); err != nil {
+				api.writeMaintenanceError(w, aircraftID, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, "Unknown resource", http.StatusNotFound)
+	}
+}
+
+// getCostOfOwnership totals what an aircraft has cost in parts and repairs
+func (api *AircraftAPI) getCostOfOwnership(w http.ResponseWriter, r *http.Request, aircraftID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	cost, err := api.aircraftSvc.GetCostOfOwnership(ctx, aircraftID, userID)
+	if err != nil {
+		api.writeMaintenanceError(w, aircraftID, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, cost)
+}
+
+func (api *AircraftAPI) writeMaintenanceError(w http.ResponseWriter, aircraftID string, err error) {
+	var svcErr *aircraft.ServiceError
+	if errors.As(err, &svcErr) {
+		status := http.StatusBadRequest
+		if strings.HasSuffix(svcErr.Message, "not found") {
+			status = http.StatusNotFound
+		}
+		api.writeJSON(w, status, map[string]string{"error": svcErr.Message})
+		return
+	}
+	api.logger.Error("Aircraft maintenance request failed", logging.WithFields(map[string]interface{}{
+		"aircraft_id": aircraftID,
+		"error":       err.Error(),
+	}))
+	api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load maintenance log"})
+}
+
+func (api *AircraftAPI) writeGalleryError(w http.ResponseWriter, aircraftID string, err error) {
+	var svcErr *aircraft.ServiceError
+	if errors.As(err, &svcErr) {
+		status := http.StatusBadRequest
+		if strings.HasSuffix(svcErr.Message, "not found") {
+			status = http.StatusNotFound
+		}
+		api.writeJSON(w, status, map[string]string{"error": svcErr.Message})
+		return
+	}
+	api.logger.Error("Aircraft gallery request failed", logging.WithFields(map[string]interface{}{
+		"aircraft_id": aircraftID,
+		"error":       err.Error(),
+	}))
+	api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update gallery"})
+}
+
 // getImage retrieves an aircraft's image
 func (api *AircraftAPI) getImage(w http.ResponseWriter, r *http.Request, aircraftID string) {
 	userID := auth.GetUserID(r.Context())
@@ -623,7 +1050,13 @@ func (api *AircraftAPI) getImage(w http.ResponseWriter, r *http.Request, aircraf
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	imageData, imageType, err := api.aircraftSvc.GetImage(ctx, aircraftID, userID)
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
+	stream, err := api.aircraftSvc.GetImage(ctx, aircraftID, userID, size)
 	if err != nil {
 		api.logger.Error("Failed to get aircraft image", logging.WithFields(map[string]interface{}{
 			"aircraft_id": aircraftID,
@@ -633,15 +1066,12 @@ func (api *AircraftAPI) getImage(w http.ResponseWriter, r *http.Request, aircraf
 		return
 	}
 
-	if imageData == nil {
+	if stream == nil {
 		http.Error(w, "No image for this aircraft", http.StatusNotFound)
 		return
 	}
 
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Cache-Control", "private, max-age=3600")
-	w.WriteHeader(http.StatusOK)
-	w.Write(imageData)
+	_ = writeImageStream(w, r, stream, "private, max-age=3600")
 }
 
 // deleteImage removes an aircraft's image
@@ -0,0 +1,30 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/aircraft"
+)
+
+func TestAircraftErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", &aircraft.NotFoundError{Message: "battery not found"}, http.StatusNotFound},
+		{"wrapped not found", fmt.Errorf("assign: %w", &aircraft.NotFoundError{Message: "aircraft not found"}), http.StatusNotFound},
+		{"validation", &aircraft.ServiceError{Message: "battery not assigned to aircraft"}, http.StatusBadRequest},
+		{"internal", errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aircraftErrorStatus(tt.err); got != tt.want {
+				t.Errorf("aircraftErrorStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
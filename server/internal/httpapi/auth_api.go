@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/johnrirwin/flyingforge/internal/auth"
 	"github.com/johnrirwin/flyingforge/internal/logging"
@@ -41,6 +42,13 @@ func (api *AuthAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(http.
 	mux.HandleFunc("/api/auth/refresh", corsMiddleware(api.handleRefresh))
 	mux.HandleFunc("/api/auth/logout", corsMiddleware(api.authMiddleware.RequireAuth(api.handleLogout)))
 	mux.HandleFunc("/api/auth/me", corsMiddleware(api.authMiddleware.RequireAuth(api.handleGetMe)))
+	mux.HandleFunc("/api/auth/email", corsMiddleware(api.handleEmailLogin))
+	mux.HandleFunc("/api/auth/email/verify", corsMiddleware(api.handleEmailLoginVerify))
+	mux.HandleFunc("/api/auth/passkey/options", corsMiddleware(api.handlePasskeyLoginOptions))
+	mux.HandleFunc("/api/auth/passkey", corsMiddleware(api.handlePasskeyLogin))
+	mux.HandleFunc("/api/auth/passkeys/options", corsMiddleware(api.authMiddleware.RequireAuth(api.handlePasskeyRegistrationOptions)))
+	mux.HandleFunc("/api/auth/passkeys", corsMiddleware(api.authMiddleware.RequireAuth(api.handlePasskeys)))
+	mux.HandleFunc("/api/auth/passkeys/", corsMiddleware(api.authMiddleware.RequireAuth(api.handlePasskey)))
 }
 
 func (api *AuthAPI) handleGoogleLogin(w http.ResponseWriter, r *http.Request) {
@@ -73,6 +81,156 @@ func (api *AuthAPI) handleGoogleLogin(w http.ResponseWriter, r *http.Request) {
 	api.writeJSON(w, http.StatusOK, response)
 }
 
+// handleEmailLogin handles POST /api/auth/email, which emails a sign-in link
+func (api *AuthAPI) handleEmailLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var params models.EmailLoginParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		api.writeError(w, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+
+	if err := api.authService.RequestEmailLogin(r.Context(), params); err != nil {
+		api.writeAuthError(w, err, "Email sign-in link failed", "failed to send sign-in link")
+		return
+	}
+
+	api.writeJSON(w, http.StatusAccepted, map[string]string{"status": "sent"})
+}
+
+// handleEmailLoginVerify handles POST /api/auth/email/verify with the token from a sign-in link
+func (api *AuthAPI) handleEmailLoginVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var params models.EmailLoginVerifyParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		api.writeError(w, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+
+	response, err := api.authService.LoginWithEmailLink(r.Context(), params)
+	if err != nil {
+		api.writeAuthError(w, err, "Email sign-in failed", "email sign-in failed")
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// handlePasskeyLoginOptions handles POST /api/auth/passkey/options
+func (api *AuthAPI) handlePasskeyLoginOptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	options, err := api.authService.BeginPasskeyLogin(r.Context())
+	if err != nil {
+		api.writeAuthError(w, err, "Passkey sign-in options failed", "failed to start passkey sign-in")
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, options)
+}
+
+// handlePasskeyLogin handles POST /api/auth/passkey with a signed assertion
+func (api *AuthAPI) handlePasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var params models.PasskeyLoginParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		api.writeError(w, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+
+	response, err := api.authService.LoginWithPasskey(r.Context(), params)
+	if err != nil {
+		api.writeAuthError(w, err, "Passkey sign-in failed", "passkey sign-in failed")
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// handlePasskeyRegistrationOptions handles POST /api/auth/passkeys/options
+func (api *AuthAPI) handlePasskeyRegistrationOptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	options, err := api.authService.BeginPasskeyRegistration(r.Context(), auth.GetUserID(r.Context()))
+	if err != nil {
+		api.writeAuthError(w, err, "Passkey registration options failed", "failed to start passkey registration")
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, options)
+}
+
+// handlePasskeys handles GET and POST /api/auth/passkeys
+func (api *AuthAPI) handlePasskeys(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		passkeys, err := api.authService.ListPasskeys(r.Context(), userID)
+		if err != nil {
+			api.writeAuthError(w, err, "Failed to list passkeys", "failed to list passkeys")
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{"passkeys": passkeys})
+
+	case http.MethodPost:
+		var params models.PasskeyRegistrationParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			api.writeError(w, http.StatusBadRequest, "invalid_request", "invalid request body")
+			return
+		}
+
+		passkey, err := api.authService.FinishPasskeyRegistration(r.Context(), userID, params)
+		if err != nil {
+			api.writeAuthError(w, err, "Passkey registration failed", "passkey registration failed")
+			return
+		}
+		api.writeJSON(w, http.StatusCreated, passkey)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePasskey handles DELETE /api/auth/passkeys/{id}
+func (api *AuthAPI) handlePasskey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	passkeyID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/auth/passkeys/"), "/")
+	if passkeyID == "" || strings.Contains(passkeyID, "/") {
+		api.writeError(w, http.StatusNotFound, "not_found", "passkey not found")
+		return
+	}
+
+	if err := api.authService.DeletePasskey(r.Context(), auth.GetUserID(r.Context()), passkeyID); err != nil {
+		api.writeAuthError(w, err, "Failed to delete passkey", "failed to delete passkey")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (api *AuthAPI) handleRefresh(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -223,6 +381,34 @@ func (api *AuthAPI) writeJSON(w http.ResponseWriter, status int, data interface{
 	json.NewEncoder(w).Encode(data)
 }
 
+// writeAuthError maps an AuthError to its HTTP status, and logs anything
+// else as an internal error
+func (api *AuthAPI) writeAuthError(w http.ResponseWriter, err error, logMessage, publicMessage string) {
+	authErr, ok := err.(*auth.AuthError)
+	if !ok {
+		api.logger.Error(logMessage, logging.WithField("error", err.Error()))
+		api.writeError(w, http.StatusInternalServerError, "internal_error", publicMessage)
+		return
+	}
+
+	status := http.StatusUnauthorized
+	switch authErr.Code {
+	case "invalid_input":
+		status = http.StatusBadRequest
+	case "account_disabled":
+		status = http.StatusForbidden
+	case "not_found", "user_not_found":
+		status = http.StatusNotFound
+	case "identity_linked":
+		status = http.StatusConflict
+	case "rate_limited":
+		status = http.StatusTooManyRequests
+	case "not_configured":
+		status = http.StatusServiceUnavailable
+	}
+	api.writeError(w, status, authErr.Code, authErr.Message)
+}
+
 func (api *AuthAPI) writeError(w http.ResponseWriter, status int, code, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -14,6 +14,9 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
 
+// maxBatteryImportBytes caps the size of an import upload
+const maxBatteryImportBytes = 5 << 20
+
 // BatteryAPI handles HTTP API requests for battery management
 type BatteryAPI struct {
 	batterySvc     *battery.Service
@@ -136,6 +139,18 @@ func (api *BatteryAPI) handleBatteryItem(w http.ResponseWriter, r *http.Request)
 
 	batteryID := parts[0]
 
+	// Fleet-wide routes: /api/batteries/export and /api/batteries/import
+	if len(parts) == 1 {
+		switch batteryID {
+		case "export":
+			api.exportBatteries(w, r)
+			return
+		case "import":
+			api.importBatteries(w, r)
+			return
+		}
+	}
+
 	// Check for sub-resources
 	if len(parts) > 1 {
 		switch parts[1] {
@@ -253,6 +268,75 @@ func (api *BatteryAPI) deleteBattery(w http.ResponseWriter, r *http.Request, id
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// exportBatteries exports all batteries and logs as CSV (flattened) or JSON (nested)
+func (api *BatteryAPI) exportBatteries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+	format := models.BatteryExportFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = models.BatteryExportFormatJSON
+	}
+	if format != models.BatteryExportFormatCSV && format != models.BatteryExportFormatJSON {
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "format must be csv or json"})
+		return
+	}
+
+	export, err := api.batterySvc.Export(r.Context(), userID)
+	if err != nil {
+		api.logger.Error("Battery export failed", logging.WithField("error", err.Error()))
+		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("flyingforge-batteries-%s.%s", export.ExportedAt.Format("20060102"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == models.BatteryExportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := battery.WriteExportCSV(w, export); err != nil {
+			api.logger.Error("Battery CSV export write failed", logging.WithField("error", err.Error()))
+		}
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, export)
+}
+
+// importBatteries upserts batteries (and appends logs) from a CSV or JSON body.
+// The format comes from ?format= or the Content-Type header.
+func (api *BatteryAPI) importBatteries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+	format := models.BatteryExportFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		contentType := strings.ToLower(r.Header.Get("Content-Type"))
+		if strings.HasPrefix(contentType, "text/csv") || strings.HasPrefix(contentType, "application/csv") {
+			format = models.BatteryExportFormatCSV
+		} else {
+			format = models.BatteryExportFormatJSON
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxBatteryImportBytes)
+	result, err := api.batterySvc.Import(r.Context(), userID, format, body)
+	if err != nil {
+		api.logger.Error("Battery import failed", logging.WithField("error", err.Error()))
+		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, result)
+}
+
 // handleLogs handles battery log operations
 func (api *BatteryAPI) handleLogs(w http.ResponseWriter, r *http.Request, batteryID string) {
 	switch r.Method {
@@ -84,6 +84,17 @@ func (api *BuildAPI) handlePublicBuildItem(w http.ResponseWriter, r *http.Reques
 			}
 			api.getPublicBuildImage(w, r, buildID)
 			return
+		case "gallery":
+			if len(parts) != 4 || parts[3] != "image" {
+				api.writeError(w, http.StatusNotFound, "not_found", "unknown build action")
+				return
+			}
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			api.getPublicBuildGalleryImage(w, r, buildID, strings.TrimSpace(parts[2]))
+			return
 		default:
 			api.writeError(w, http.StatusNotFound, "not_found", "unknown build action")
 			return
@@ -297,6 +308,9 @@ func (api *BuildAPI) handleBuildItem(w http.ResponseWriter, r *http.Request) {
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			}
 			return
+		case "gallery":
+			api.handleBuildGallery(w, r, buildID, userID, parts[2:])
+			return
 		case "reaction":
 			switch r.Method {
 			case http.MethodPost, http.MethodPut:
@@ -571,7 +585,7 @@ func (api *BuildAPI) uploadBuildImage(w http.ResponseWriter, r *http.Request, bu
 		api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to set build image")
 		return
 	}
-	if decision.Status != models.ImageModerationApproved {
+	if !decision.Accepted() {
 		statusCode := http.StatusUnprocessableEntity
 		if decision.Status == models.ImageModerationPendingReview {
 			statusCode = http.StatusServiceUnavailable
@@ -584,14 +598,21 @@ func (api *BuildAPI) uploadBuildImage(w http.ResponseWriter, r *http.Request, bu
 		return
 	}
 
-	api.writeJSON(w, http.StatusOK, map[string]string{
+	statusCode, message := uploadOutcome(decision)
+	api.writeJSON(w, statusCode, map[string]string{
 		"status":  string(decision.Status),
-		"message": "Image uploaded successfully",
+		"message": message,
 	})
 }
 
 func (api *BuildAPI) getBuildImage(w http.ResponseWriter, r *http.Request, buildID string, userID string) {
-	imageData, imageType, err := api.service.GetImage(r.Context(), buildID, userID)
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
+	stream, err := api.service.GetImage(r.Context(), buildID, userID, size)
 	if err != nil {
 		api.logger.Error("Get build image failed", logging.WithFields(map[string]interface{}{
 			"build_id": buildID,
@@ -600,21 +621,22 @@ func (api *BuildAPI) getBuildImage(w http.ResponseWriter, r *http.Request, build
 		http.Error(w, "image not found", http.StatusNotFound)
 		return
 	}
-	if len(imageData) == 0 {
+	if stream == nil {
 		http.Error(w, "no image for this build", http.StatusNotFound)
 		return
 	}
 
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Content-Length", strconv.Itoa(len(imageData)))
-	w.Header().Set("Cache-Control", "private, max-age=300")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(imageData)
+	_ = writeImageStream(w, r, stream, "private, max-age=300")
 }
 
 func (api *BuildAPI) getPublicBuildImage(w http.ResponseWriter, r *http.Request, buildID string) {
-	imageData, imageType, err := api.service.GetPublicImage(r.Context(), buildID)
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
+	stream, err := api.service.GetPublicImage(r.Context(), buildID, size)
 	if err != nil {
 		api.logger.Error("Get public build image failed", logging.WithFields(map[string]interface{}{
 			"build_id": buildID,
@@ -623,17 +645,12 @@ func (api *BuildAPI) getPublicBuildImage(w http.ResponseWriter, r *http.Request,
 		http.Error(w, "image not found", http.StatusNotFound)
 		return
 	}
-	if len(imageData) == 0 {
+	if stream == nil {
 		http.Error(w, "no image for this build", http.StatusNotFound)
 		return
 	}
 
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Content-Length", strconv.Itoa(len(imageData)))
-	w.Header().Set("Cache-Control", "public, max-age=300")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(imageData)
+	_ = writeImageStream(w, r, stream, "public, max-age=300")
 }
 
 func (api *BuildAPI) deleteBuildImage(w http.ResponseWriter, r *http.Request, buildID string, userID string) {
@@ -656,12 +673,167 @@ func (api *BuildAPI) deleteBuildImage(w http.ResponseWriter, r *http.Request, bu
 	})
 }
 
+// handleBuildGallery routes /api/builds/{id}/gallery[/...] for the build owner.
+func (api *BuildAPI) handleBuildGallery(w http.ResponseWriter, r *http.Request, buildID string, userID string, rest []string) {
+	switch {
+	case len(rest) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			gallery, err := api.service.ListGallery(r.Context(), userID, buildID)
+			if err != nil {
+				api.writeGalleryError(w, buildID, "list build gallery", err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, map[string]interface{}{"images": gallery})
+		case http.MethodPost:
+			api.addBuildGalleryImage(w, r, buildID, userID)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case len(rest) == 1 && rest[0] == "order":
+		if r.Method != http.MethodPut {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var params models.ReorderGalleryParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			api.writeError(w, http.StatusBadRequest, "invalid_body", "invalid request body")
+			return
+		}
+		gallery, err := api.service.ReorderGallery(r.Context(), userID, buildID, params.ImageIDs)
+		if err != nil {
+			api.writeGalleryError(w, buildID, "reorder build gallery", err)
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{"images": gallery})
+	case len(rest) == 1:
+		imageID := strings.TrimSpace(rest[0])
+		switch r.Method {
+		case http.MethodPatch, http.MethodPut:
+			var params models.UpdateGalleryImageParams
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				api.writeError(w, http.StatusBadRequest, "invalid_body", "invalid request body")
+				return
+			}
+			image, err := api.service.UpdateGalleryImage(r.Context(), userID, buildID, imageID, params)
+			if err != nil {
+				api.writeGalleryError(w, buildID, "update build gallery image", err)
+				return
+			}
+			api.writeJSON(w, http.StatusOK, image)
+		case http.MethodDelete:
+			if err := api.service.DeleteGalleryImage(r.Context(), userID, buildID, imageID); err != nil {
+				api.writeGalleryError(w, buildID, "delete build gallery image", err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case len(rest) == 2 && rest[1] == "image":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		size, ok := imageSizeParam(r)
+		if !ok {
+			http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+			return
+		}
+		stream, err := api.service.GetGalleryImage(r.Context(), buildID, userID, strings.TrimSpace(rest[0]), size)
+		if err != nil {
+			api.logger.Error("Get build gallery image failed", logging.WithFields(map[string]interface{}{
+				"build_id": buildID,
+				"error":    err.Error(),
+			}))
+			http.Error(w, "image not found", http.StatusNotFound)
+			return
+		}
+		if stream == nil {
+			http.Error(w, "image not found", http.StatusNotFound)
+			return
+		}
+		_ = writeImageStream(w, r, stream, "private, max-age=300")
+	default:
+		api.writeError(w, http.StatusNotFound, "not_found", "unknown gallery action")
+	}
+}
+
+func (api *BuildAPI) addBuildGalleryImage(w http.ResponseWriter, r *http.Request, buildID string, userID string) {
+	params, reqErr := readGalleryUpload(w, r)
+	if reqErr != nil {
+		api.writeError(w, reqErr.status, reqErr.code, reqErr.message)
+		return
+	}
+
+	decision, image, err := api.service.AddGalleryImage(r.Context(), userID, buildID, params)
+	if writeGalleryModeration(w, err, decision) {
+		return
+	}
+	if err != nil {
+		api.writeGalleryError(w, buildID, "add build gallery image", err)
+		return
+	}
+	if image == nil {
+		api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to add gallery image")
+		return
+	}
+
+	api.writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status": string(decision.Status),
+		"image":  image,
+	})
+}
+
+func (api *BuildAPI) getPublicBuildGalleryImage(w http.ResponseWriter, r *http.Request, buildID string, imageID string) {
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
+	stream, err := api.service.GetPublicGalleryImage(r.Context(), buildID, imageID, size)
+	if err != nil {
+		api.logger.Error("Get public build gallery image failed", logging.WithFields(map[string]interface{}{
+			"build_id": buildID,
+			"error":    err.Error(),
+		}))
+		http.Error(w, "image not found", http.StatusNotFound)
+		return
+	}
+	if stream == nil {
+		http.Error(w, "image not found", http.StatusNotFound)
+		return
+	}
+
+	_ = writeImageStream(w, r, stream, "public, max-age=300")
+}
+
+func (api *BuildAPI) writeGalleryError(w http.ResponseWriter, buildID string, action string, err error) {
+	var svcErr *builds.ServiceError
+	if errors.As(err, &svcErr) {
+		switch strings.ToLower(strings.TrimSpace(svcErr.Message)) {
+		case "build not found", "gallery image not found":
+			api.writeError(w, http.StatusNotFound, "not_found", svcErr.Message)
+		default:
+			api.writeError(w, http.StatusBadRequest, "invalid_request", svcErr.Message)
+		}
+		return
+	}
+	api.logger.Error("Failed to "+action, logging.WithFields(map[string]interface{}{
+		"build_id": buildID,
+		"error":    err.Error(),
+	}))
+	api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to "+action)
+}
+
 func (api *BuildAPI) parseListParams(r *http.Request) models.BuildListParams {
 	query := r.URL.Query()
 
 	params := models.BuildListParams{
-		Sort:        models.BuildSort(strings.TrimSpace(query.Get("sort"))),
-		FrameFilter: strings.TrimSpace(query.Get("frameFilter")),
+		Sort:          models.BuildSort(strings.TrimSpace(query.Get("sort"))),
+		FrameFilter:   strings.TrimSpace(query.Get("frameFilter")),
+		CatalogItemID: strings.TrimSpace(query.Get("catalogItemId")),
 	}
 	if params.Sort == "" {
 		params.Sort = models.BuildSortNewest
@@ -24,6 +24,8 @@ const (
 	batteryTrackingBaseTimeout    = 15 * time.Second
 	batteryTrackingPerItemTimeout = 1200 * time.Millisecond
 	batteryTrackingMaxTimeout     = 90 * time.Second
+	maxInventoryImportBytes       = 5 << 20
+	inventoryImportTimeout        = 2 * time.Minute
 )
 
 var (
@@ -52,6 +54,7 @@ type EquipmentAPI struct {
 	equipmentSvc   *equipment.Service
 	inventorySvc   inventory.InventoryManager
 	batterySvc     batteryCreator
+	importer       *inventory.Importer
 	authMiddleware *auth.Middleware
 	logger         *logging.Logger
 }
@@ -67,6 +70,11 @@ func NewEquipmentAPI(equipmentSvc *equipment.Service, inventorySvc inventory.Inv
 	}
 }
 
+// SetImporter enables the inventory CSV/JSON import routes
+func (api *EquipmentAPI) SetImporter(importer *inventory.Importer) {
+	api.importer = importer
+}
+
 // RegisterRoutes registers equipment and inventory routes on the given mux
 func (api *EquipmentAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(http.HandlerFunc) http.HandlerFunc) {
 	if api.authMiddleware == nil {
@@ -84,6 +92,10 @@ func (api *EquipmentAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(
 	mux.HandleFunc("/api/inventory", corsMiddleware(api.authMiddleware.RequireAuth(api.handleInventory)))
 	mux.HandleFunc("/api/inventory/summary", corsMiddleware(api.authMiddleware.RequireAuth(api.handleInventorySummary)))
 	mux.HandleFunc("/api/inventory/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleInventoryItem)))
+	if api.importer != nil {
+		mux.HandleFunc("/api/inventory/import/preview", corsMiddleware(api.authMiddleware.RequireAuth(api.handleInventoryImportPreview)))
+		mux.HandleFunc("/api/inventory/import", corsMiddleware(api.authMiddleware.RequireAuth(api.handleInventoryImportCommit)))
+	}
 }
 
 // Equipment handlers
@@ -638,14 +650,28 @@ func parsePositiveInt(text string) (int, bool) {
 }
 
 func (api *EquipmentAPI) handleInventoryItem(w http.ResponseWriter, r *http.Request) {
-	// Extract item ID from path: /api/inventory/{id}
-	path := r.URL.Path
-	id := path[len("/api/inventory/"):]
+	// Extract item ID from path: /api/inventory/{id} or /api/inventory/{id}/units[/{unitId}]
+	path := strings.TrimSuffix(r.URL.Path[len("/api/inventory/"):], "/")
+	parts := strings.Split(path, "/")
+	id := parts[0]
 	if id == "" || id == "summary" {
 		http.Error(w, "Item ID required", http.StatusBadRequest)
 		return
 	}
 
+	if len(parts) > 1 {
+		if parts[1] != "units" || len(parts) > 3 {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		if len(parts) == 2 {
+			api.handleInventoryUnits(w, r, id)
+			return
+		}
+		api.handleInventoryUnit(w, r, id, parts[2])
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		api.getInventoryItem(w, r, id)
@@ -720,7 +746,7 @@ func (api *EquipmentAPI) updateInventoryItem(w http.ResponseWriter, r *http.Requ
 			"id":    id,
 			"error": err.Error(),
 		}))
-		api.writeJSON(w, http.StatusInternalServerError, map[string]string{
+		api.writeJSON(w, inventoryErrorStatus(err), map[string]string{
 			"error": err.Error(),
 		})
 		return
@@ -756,6 +782,151 @@ func (api *EquipmentAPI) updateInventoryItem(w http.ResponseWriter, r *http.Requ
 	api.writeJSON(w, http.StatusOK, item)
 }
 
+// handleInventoryUnits handles /api/inventory/{id}/units
+func (api *EquipmentAPI) handleInventoryUnits(w http.ResponseWriter, r *http.Request, itemID string) {
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		units, err := api.inventorySvc.ListUnits(ctx, userID, itemID)
+		if err != nil {
+			api.writeJSON(w, inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{"units": units})
+	case http.MethodPost:
+		var params models.AddInventoryUnitParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		unit, err := api.inventorySvc.AddUnit(ctx, userID, itemID, params)
+		if err != nil {
+			api.logger.Error("Add inventory unit failed", logging.WithFields(map[string]interface{}{
+				"id":    itemID,
+				"error": err.Error(),
+			}))
+			api.writeJSON(w, inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		api.writeJSON(w, http.StatusCreated, unit)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleInventoryUnit handles /api/inventory/{id}/units/{unitId}
+func (api *EquipmentAPI) handleInventoryUnit(w http.ResponseWriter, r *http.Request, itemID, unitID string) {
+	userID := auth.GetUserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPatch:
+		var params models.UpdateInventoryUnitParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		params.ID = unitID
+		unit, err := api.inventorySvc.UpdateUnit(ctx, userID, itemID, params)
+		if err != nil {
+			api.logger.Error("Update inventory unit failed", logging.WithFields(map[string]interface{}{
+				"id":      itemID,
+				"unit_id": unitID,
+				"error":   err.Error(),
+			}))
+			api.writeJSON(w, inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		api.writeJSON(w, http.StatusOK, unit)
+	case http.MethodDelete:
+		if err := api.inventorySvc.RemoveUnit(ctx, userID, itemID, unitID); err != nil {
+			api.writeJSON(w, inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleInventoryImportPreview parses an uploaded CSV or JSON file and returns
+// each row with its gear catalog matches for review. Nothing is written.
+func (api *EquipmentAPI) handleInventoryImportPreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+	format := models.InventoryImportFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		contentType := strings.ToLower(r.Header.Get("Content-Type"))
+		if strings.HasPrefix(contentType, "text/csv") || strings.HasPrefix(contentType, "application/csv") {
+			format = models.InventoryImportFormatCSV
+		} else {
+			format = models.InventoryImportFormatJSON
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), inventoryImportTimeout)
+	defer cancel()
+
+	body := http.MaxBytesReader(w, r.Body, maxInventoryImportBytes)
+	preview, err := api.importer.Preview(ctx, userID, format, body)
+	if err != nil {
+		api.logger.Error("Inventory import preview failed", logging.WithField("error", err.Error()))
+		api.writeJSON(w, inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, preview)
+}
+
+// handleInventoryImportCommit adds the reviewed rows, linking, creating or
+// skipping catalog entries as the user decided
+func (api *EquipmentAPI) handleInventoryImportCommit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+
+	var params models.InventoryImportCommitParams
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxInventoryImportBytes)).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), inventoryImportTimeout)
+	defer cancel()
+
+	result, err := api.importer.Commit(ctx, userID, params)
+	if err != nil {
+		api.logger.Error("Inventory import failed", logging.WithField("error", err.Error()))
+		api.writeJSON(w, inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, result)
+}
+
+func inventoryErrorStatus(err error) int {
+	if _, ok := err.(*inventory.ServiceError); ok {
+		return http.StatusBadRequest
+	}
+	if strings.Contains(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
 func expectedBatteryTrackerCreatesForUpdate(previousItem, updatedItem *models.InventoryItem) int {
 	if updatedItem == nil || updatedItem.Category != models.CategoryBatteries || updatedItem.Quantity <= 0 {
 		return 0
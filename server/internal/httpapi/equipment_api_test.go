@@ -3,25 +3,31 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/johnrirwin/flyingforge/internal/auth"
+	inventorysvc "github.com/johnrirwin/flyingforge/internal/inventory"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
 
 type mockInventoryManager struct {
-	added       []models.AddInventoryParams
-	addItem     *models.InventoryItem
-	addErr      error
-	getItem     *models.InventoryItem
-	getItemErr  error
-	updateItem  *models.InventoryItem
-	updateErr   error
-	updateCalls []models.UpdateInventoryParams
+	added        []models.AddInventoryParams
+	addItem      *models.InventoryItem
+	addErr       error
+	getItem      *models.InventoryItem
+	getItemErr   error
+	updateItem   *models.InventoryItem
+	updateErr    error
+	updateCalls  []models.UpdateInventoryParams
+	units        []models.InventoryUnit
+	addedUnits   []models.AddInventoryUnitParams
+	updatedUnits []models.UpdateInventoryUnitParams
+	unitErr      error
 }
 
 func (m *mockInventoryManager) AddItem(ctx context.Context, userID string, params models.AddInventoryParams) (*models.InventoryItem, error) {
@@ -80,6 +86,30 @@ func (m *mockInventoryManager) GetSummary(ctx context.Context, userID string) (*
 	return nil, nil
 }
 
+func (m *mockInventoryManager) ListUnits(ctx context.Context, userID string, itemID string) ([]models.InventoryUnit, error) {
+	return m.units, nil
+}
+
+func (m *mockInventoryManager) AddUnit(ctx context.Context, userID string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error) {
+	m.addedUnits = append(m.addedUnits, params)
+	if m.unitErr != nil {
+		return nil, m.unitErr
+	}
+	return &models.InventoryUnit{ID: "unit-1", InventoryItemID: itemID, SerialNumber: params.SerialNumber, Condition: params.Condition}, nil
+}
+
+func (m *mockInventoryManager) UpdateUnit(ctx context.Context, userID string, itemID string, params models.UpdateInventoryUnitParams) (*models.InventoryUnit, error) {
+	m.updatedUnits = append(m.updatedUnits, params)
+	if m.unitErr != nil {
+		return nil, m.unitErr
+	}
+	return &models.InventoryUnit{ID: params.ID, InventoryItemID: itemID}, nil
+}
+
+func (m *mockInventoryManager) RemoveUnit(ctx context.Context, userID string, itemID string, unitID string) error {
+	return m.unitErr
+}
+
 type mockBatteryCreator struct {
 	createCalls []models.CreateBatteryParams
 	createErr   error
@@ -485,3 +515,67 @@ func TestUpdateInventoryItem_DecreasingBatteryQuantityCreatesNoTrackers(t *testi
 		t.Fatalf("did not expect warning, got: %v", response["warning"])
 	}
 }
+
+func TestHandleInventoryItem_RoutesUnitRequests(t *testing.T) {
+	inventory := &mockInventoryManager{}
+	api := &EquipmentAPI{
+		inventorySvc: inventory,
+		logger:       logging.New(logging.LevelError),
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/inventory/inv-1/units", strings.NewReader(`{"serialNumber":"SN-42","condition":"used"}`))
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-123"))
+	w := httptest.NewRecorder()
+	api.handleInventoryItem(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if len(inventory.addedUnits) != 1 || inventory.addedUnits[0].SerialNumber != "SN-42" {
+		t.Fatalf("added units = %+v, want one with serial SN-42", inventory.addedUnits)
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/inventory/inv-1/units/unit-1", strings.NewReader(`{"condition":"damaged"}`))
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-123"))
+	w = httptest.NewRecorder()
+	api.handleInventoryItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(inventory.updatedUnits) != 1 || inventory.updatedUnits[0].ID != "unit-1" {
+		t.Fatalf("updated units = %+v, want one for unit-1", inventory.updatedUnits)
+	}
+	if got := inventory.updatedUnits[0].Condition; got == nil || *got != models.InventoryUnitDamaged {
+		t.Fatalf("condition = %v, want damaged", got)
+	}
+}
+
+func TestHandleInventoryItem_UnitErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "validation", err: &inventorysvc.ServiceError{Message: "condition must be one of new, used, damaged, dead, repaired"}, want: http.StatusBadRequest},
+		{name: "missing unit", err: errors.New("inventory unit not found"), want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &EquipmentAPI{
+				inventorySvc: &mockInventoryManager{unitErr: tt.err},
+				logger:       logging.New(logging.LevelError),
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/inventory/inv-1/units/unit-9", nil)
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-123"))
+			w := httptest.NewRecorder()
+			api.handleInventoryItem(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
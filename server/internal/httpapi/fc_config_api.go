@@ -3,6 +3,7 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/auth"
 	"github.com/johnrirwin/flyingforge/internal/betaflight"
 	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/fcconfig"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
@@ -18,7 +20,7 @@ import (
 // FCConfigAPI handles HTTP API requests for flight controller configs
 type FCConfigAPI struct {
 	fcConfigStore  *database.FCConfigStore
-	inventoryStore *database.InventoryStore
+	fcConfigSvc    *fcconfig.Service
 	parser         *betaflight.Parser
 	authMiddleware *auth.Middleware
 	logger         *logging.Logger
@@ -28,7 +30,7 @@ type FCConfigAPI struct {
 func NewFCConfigAPI(fcConfigStore *database.FCConfigStore, inventoryStore *database.InventoryStore, authMiddleware *auth.Middleware, logger *logging.Logger) *FCConfigAPI {
 	return &FCConfigAPI{
 		fcConfigStore:  fcConfigStore,
-		inventoryStore: inventoryStore,
+		fcConfigSvc:    fcconfig.NewService(fcConfigStore, inventoryStore, logger),
 		parser:         betaflight.NewParser(),
 		authMiddleware: authMiddleware,
 		logger:         logger,
@@ -125,71 +127,20 @@ func (api *FCConfigAPI) createFCConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if req.InventoryItemID == "" {
-		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Inventory item ID is required"})
-		return
-	}
-
-	if req.RawCLIDump == "" {
-		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "CLI dump is required"})
-		return
-	}
-
-	// Verify the inventory item exists and belongs to the authenticated user
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	inventoryItem, err := api.inventoryStore.Get(ctx, req.InventoryItemID, userID)
+	config, err := api.fcConfigSvc.SaveConfig(ctx, userID, req)
 	if err != nil {
-		api.logger.Error("Failed to verify inventory item", logging.WithField("error", err.Error()))
-		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to verify inventory item"})
-		return
-	}
-
-	if inventoryItem == nil {
-		api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Inventory item not found or access denied"})
-		return
-	}
-
-	if req.Name == "" {
-		req.Name = "Untitled Config"
-	}
-
-	// Parse the CLI dump
-	result := api.parser.Parse(req.RawCLIDump)
-
-	// Create the config
-	config := &models.FlightControllerConfig{
-		InventoryItemID: req.InventoryItemID,
-		Name:            req.Name,
-		Notes:           req.Notes,
-		RawCLIDump:      req.RawCLIDump,
-		FirmwareName:    result.FirmwareName,
-		FirmwareVersion: result.FirmwareVersion,
-		BoardTarget:     result.BoardTarget,
-		BoardName:       result.BoardName,
-		MCUType:         result.MCUType,
-		ParseStatus:     result.ParseStatus,
-		ParseWarnings:   result.ParseWarnings,
-		ParsedTuning:    result.ParsedTuning,
-	}
-
-	if err := api.fcConfigStore.SaveConfig(ctx, userID, config); err != nil {
-		api.logger.Error("Failed to save FC config", logging.WithField("error", err.Error()))
+		var svcErr *fcconfig.ServiceError
+		if errors.As(err, &svcErr) {
+			api.writeJSON(w, http.StatusBadRequest, map[string]string{"error": svcErr.Message})
+			return
+		}
 		api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to save config"})
 		return
 	}
 
-	// If linked to an inventory item, check if there's an aircraft with that FC
-	// and auto-create a tuning snapshot
-	if config.InventoryItemID != "" {
-		aircraft, err := api.fcConfigStore.GetAircraftByFC(ctx, userID, config.InventoryItemID)
-		if err == nil && aircraft != nil {
-			api.createTuningSnapshotFromConfig(ctx, userID, aircraft.ID, config)
-		}
-	}
-
 	api.writeJSON(w, http.StatusCreated, config)
 }
 
@@ -455,29 +406,6 @@ func (api *FCConfigAPI) createTuningSnapshot(w http.ResponseWriter, r *http.Requ
 	api.writeJSON(w, http.StatusCreated, snapshot)
 }
 
-// createTuningSnapshotFromConfig creates a tuning snapshot from an existing FC config
-func (api *FCConfigAPI) createTuningSnapshotFromConfig(ctx context.Context, userID string, aircraftID string, config *models.FlightControllerConfig) error {
-	tuningData, err := json.Marshal(config.ParsedTuning)
-	if err != nil {
-		tuningData = []byte("{}")
-	}
-
-	snapshot := &models.AircraftTuningSnapshot{
-		AircraftID:               aircraftID,
-		FlightControllerConfigID: config.ID,
-		FirmwareName:             config.FirmwareName,
-		FirmwareVersion:          config.FirmwareVersion,
-		BoardTarget:              config.BoardTarget,
-		BoardName:                config.BoardName,
-		TuningData:               tuningData,
-		ParseStatus:              config.ParseStatus,
-		ParseWarnings:            config.ParseWarnings,
-		Notes:                    "Auto-created from FC config: " + config.Name,
-	}
-
-	return api.fcConfigStore.SaveTuningSnapshot(ctx, userID, snapshot)
-}
-
 // writeJSON writes a JSON response
 func (api *FCConfigAPI) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
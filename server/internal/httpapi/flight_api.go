@@ -0,0 +1,217 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/auth"
+	"github.com/johnrirwin/flyingforge/internal/flights"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// FlightAPI handles HTTP API requests for the flight log
+type FlightAPI struct {
+	flightSvc      *flights.Service
+	authMiddleware *auth.Middleware
+	logger         *logging.Logger
+}
+
+// NewFlightAPI creates a new flight log API handler
+func NewFlightAPI(flightSvc *flights.Service, authMiddleware *auth.Middleware, logger *logging.Logger) *FlightAPI {
+	return &FlightAPI{
+		flightSvc:      flightSvc,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers flight log routes on the given mux
+func (api *FlightAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(http.HandlerFunc) http.HandlerFunc) {
+	// Flight log routes (require authentication)
+	mux.HandleFunc("/api/flights", corsMiddleware(api.authMiddleware.RequireAuth(api.handleFlights)))
+	mux.HandleFunc("/api/flights/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleFlightItem)))
+}
+
+// handleFlights handles list and log operations
+func (api *FlightAPI) handleFlights(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		api.listFlights(w, r)
+	case http.MethodPost:
+		api.logFlight(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listFlights returns the authenticated user's flight sessions, newest first
+func (api *FlightAPI) listFlights(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+	query := r.URL.Query()
+
+	params := models.FlightSessionListParams{
+		AircraftID: query.Get("aircraftId"),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			params.Limit = l
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			params.Offset = o
+		}
+	}
+
+	response, err := api.flightSvc.List(r.Context(), userID, params)
+	if err != nil {
+		api.writeFlightError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// logFlight records a flight session and logs a cycle on each pack used
+func (api *FlightAPI) logFlight(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	var params models.CreateFlightSessionParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := api.flightSvc.LogSession(r.Context(), userID, params)
+	if err != nil {
+		api.writeFlightError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusCreated, session)
+}
+
+// handleFlightItem handles single session and stats routes:
+//
+//	/api/flights/{id}
+//	/api/flights/stats
+//	/api/flights/stats/aircraft/{aircraftId}
+func (api *FlightAPI) handleFlightItem(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/flights/")
+	parts := strings.Split(path, "/")
+
+	if len(parts) == 0 || parts[0] == "" {
+		http.Error(w, "Flight session ID required", http.StatusBadRequest)
+		return
+	}
+
+	if parts[0] == "stats" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case len(parts) == 1:
+			api.getPilotStats(w, r)
+		case len(parts) == 3 && parts[1] == "aircraft" && parts[2] != "":
+			api.getAircraftStats(w, r, parts[2])
+		default:
+			http.Error(w, "Unknown resource", http.StatusNotFound)
+		}
+		return
+	}
+
+	if len(parts) > 1 {
+		http.Error(w, "Unknown resource", http.StatusNotFound)
+		return
+	}
+
+	sessionID := parts[0]
+	switch r.Method {
+	case http.MethodGet:
+		api.getFlight(w, r, sessionID)
+	case http.MethodDelete:
+		api.deleteFlight(w, r, sessionID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getFlight retrieves a single flight session
+func (api *FlightAPI) getFlight(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	session, err := api.flightSvc.Get(r.Context(), id, userID)
+	if err != nil {
+		api.writeFlightError(w, err)
+		return
+	}
+	if session == nil {
+		http.Error(w, "Flight session not found", http.StatusNotFound)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, session)
+}
+
+// deleteFlight deletes a flight session and the battery logs it created
+func (api *FlightAPI) deleteFlight(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	if err := api.flightSvc.Delete(r.Context(), id, userID); err != nil {
+		api.writeFlightError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getPilotStats returns the authenticated user's flying totals
+func (api *FlightAPI) getPilotStats(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	stats, err := api.flightSvc.PilotStats(r.Context(), userID)
+	if err != nil {
+		api.writeFlightError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, stats)
+}
+
+// getAircraftStats returns an aircraft's flight time and per-component hours
+func (api *FlightAPI) getAircraftStats(w http.ResponseWriter, r *http.Request, aircraftID string) {
+	userID := auth.GetUserID(r.Context())
+
+	stats, err := api.flightSvc.AircraftStats(r.Context(), aircraftID, userID)
+	if err != nil {
+		api.writeFlightError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, stats)
+}
+
+func (api *FlightAPI) writeFlightError(w http.ResponseWriter, err error) {
+	var svcErr *flights.ServiceError
+	if errors.As(err, &svcErr) {
+		status := http.StatusBadRequest
+		if strings.HasSuffix(svcErr.Message, "not found") {
+			status = http.StatusNotFound
+		}
+		api.writeJSON(w, status, map[string]string{"error": svcErr.Message})
+		return
+	}
+	api.logger.Error("Flight log request failed", logging.WithField("error", err.Error()))
+	api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to process flight log request"})
+}
+
+func (api *FlightAPI) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// galleryRequestError is a client error found while reading a gallery upload.
+type galleryRequestError struct {
+	status  int
+	code    string
+	message string
+}
+
+// readGalleryUpload parses a gallery image upload: JSON {uploadId, caption} for
+// images pre-approved via /api/images/upload, or a multipart form with an
+// "image" file and optional "caption" field.
+func readGalleryUpload(w http.ResponseWriter, r *http.Request) (models.AddGalleryImageParams, *galleryRequestError) {
+	var params models.AddGalleryImageParams
+
+	contentType := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Type")))
+	if strings.HasPrefix(contentType, "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			return params, &galleryRequestError{http.StatusBadRequest, "invalid_body", "invalid request body"}
+		}
+		params.UploadID = strings.TrimSpace(params.UploadID)
+		if params.UploadID == "" {
+			return params, &galleryRequestError{http.StatusBadRequest, "invalid_upload", "uploadId is required"}
+		}
+		return params, nil
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 3*1024*1024)
+	if err := r.ParseMultipartForm(3 * 1024 * 1024); err != nil {
+		return params, &galleryRequestError{http.StatusBadRequest, "invalid_upload", "file too large or invalid form"}
+	}
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		return params, &galleryRequestError{http.StatusBadRequest, "missing_image", "image file required"}
+	}
+	defer file.Close()
+
+	imageData, err := io.ReadAll(file)
+	if err != nil {
+		return params, &galleryRequestError{http.StatusInternalServerError, "read_error", "failed to read image"}
+	}
+	if len(imageData) > 2*1024*1024 {
+		return params, &galleryRequestError{http.StatusBadRequest, "invalid_upload", "image must be less than 2MB"}
+	}
+	detectedContentType, ok := detectAllowedImageContentType(imageData)
+	if !ok {
+		return params, &galleryRequestError{http.StatusBadRequest, "invalid_upload", "image must be JPEG or PNG"}
+	}
+
+	params.ImageType = detectedContentType
+	params.ImageData = imageData
+	params.Caption = r.FormValue("caption")
+	return params, nil
+}
+
+// writeGalleryModeration writes the response for an upload that did not end up
+// in the gallery: an expired/unapproved upload token, or a decision that was
+// neither approved nor queued for review. Returns false when there was nothing
+// to report.
+func writeGalleryModeration(w http.ResponseWriter, err error, decision *models.ModerationDecision) bool {
+	var reason, message string
+	status := http.StatusUnprocessableEntity
+	switch {
+	case err == images.ErrPendingUploadNotFound:
+		reason, message = "Image approval token expired or missing", "image approval token expired or missing"
+	case err == images.ErrUploadNotApproved:
+		reason, message = "Image is not approved", "image is not approved"
+	case err == nil && decision != nil && !decision.Accepted():
+		reason, message = decision.Reason, decision.Reason
+		if decision.Status == models.ImageModerationPendingReview {
+			status = http.StatusServiceUnavailable
+		}
+	default:
+		return false
+	}
+
+	moderationStatus := models.ImageModerationRejected
+	if decision != nil {
+		moderationStatus = decision.Status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status": string(moderationStatus),
+		"reason": reason,
+		"error":  message,
+	})
+	return true
+}
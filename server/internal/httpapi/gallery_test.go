@@ -0,0 +1,73 @@
+package httpapi
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+func TestReadGalleryUpload(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/builds/b1/gallery", strings.NewReader(`{"uploadId":" up-1 ","caption":"Side view"}`))
+	req.Header.Set("Content-Type", "application/json")
+	params, reqErr := readGalleryUpload(httptest.NewRecorder(), req)
+	if reqErr != nil || params.UploadID != "up-1" || params.Caption != "Side view" {
+		t.Fatalf("JSON upload = %+v, %+v", params, reqErr)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/builds/b1/gallery", strings.NewReader(`{"caption":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, reqErr := readGalleryUpload(httptest.NewRecorder(), req); reqErr == nil || reqErr.status != http.StatusBadRequest {
+		t.Fatalf("expected missing uploadId error, got %+v", reqErr)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("caption", "Top")
+	file, _ := form.CreateFormFile("image", "photo.png")
+	_, _ = file.Write([]byte("\x89PNG\r\n\x1a\n0000"))
+	_ = form.Close()
+	req = httptest.NewRequest(http.MethodPost, "/api/builds/b1/gallery", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	params, reqErr = readGalleryUpload(httptest.NewRecorder(), req)
+	if reqErr != nil || params.ImageType != "image/png" || params.Caption != "Top" || len(params.ImageData) == 0 {
+		t.Fatalf("multipart upload = %+v, %+v", params, reqErr)
+	}
+}
+
+func TestWriteGalleryModeration(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	if writeGalleryModeration(rec, nil, &models.ModerationDecision{Status: models.ImageModerationApproved}) {
+		t.Fatal("approved decision should not be reported")
+	}
+
+	rec = httptest.NewRecorder()
+	if !writeGalleryModeration(rec, nil, &models.ModerationDecision{Status: models.ImageModerationPendingReview, Reason: "Try later"}) {
+		t.Fatal("pending decision should be reported")
+	}
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "Try later") {
+		t.Fatalf("pending response = %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if writeGalleryModeration(rec, nil, &models.ModerationDecision{Status: models.ImageModerationPendingReview, Queued: true}) {
+		t.Fatal("image queued for review was added and should not be reported")
+	}
+
+	rec = httptest.NewRecorder()
+	if !writeGalleryModeration(rec, images.ErrPendingUploadNotFound, nil) {
+		t.Fatal("expired upload token should be reported")
+	}
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"status":"REJECTED"`) {
+		t.Fatalf("expired token response = %d %s", rec.Code, rec.Body.String())
+	}
+}
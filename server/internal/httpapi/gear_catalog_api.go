@@ -70,6 +70,7 @@ func (api *GearCatalogAPI) handleSearch(w http.ResponseWriter, r *http.Request)
 		Query:    query.Get("q"),
 		GearType: models.GearType(query.Get("gearType")),
 		Brand:    query.Get("brand"),
+		BestFor:  query.Get("bestFor"),
 	}
 
 	if limit := query.Get("limit"); limit != "" {
@@ -358,7 +359,21 @@ func (api *GearCatalogAPI) getGearImage(w http.ResponseWriter, r *http.Request,
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	imageData, imageType, err := api.catalogStore.GetImage(ctx, id)
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
+	ref, err := api.catalogStore.GetImage(ctx, id)
+	var stream *images.ImageStream
+	if err == nil && ref != nil {
+		if api.imageSvc == nil {
+			http.Error(w, "Image storage unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		stream, err = api.imageSvc.OpenRef(ctx, ref, size)
+	}
 	if err != nil {
 		api.logger.Error("Failed to get gear image", logging.WithFields(map[string]interface{}{
 			"gearId": id,
@@ -368,19 +383,13 @@ func (api *GearCatalogAPI) getGearImage(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	if imageData == nil {
+	if stream == nil {
 		http.Error(w, "No image for this gear item", http.StatusNotFound)
 		return
 	}
-	if imageType == "" {
-		imageType = http.DetectContentType(imageData)
-	}
 
 	// Set caching headers (images cached for 60 seconds - allows quick refresh after admin updates)
-	w.Header().Set("Cache-Control", "public, max-age=60")
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Content-Length", strconv.Itoa(len(imageData)))
-	w.Write(imageData)
+	_ = writeImageStream(w, r, stream, "public, max-age=60")
 }
 
 // uploadGearImage persists a previously moderated user-submitted gear image.
@@ -477,8 +486,12 @@ func (api *GearCatalogAPI) uploadGearImage(w http.ResponseWriter, r *http.Reques
 		_ = api.imageSvc.Delete(ctx, previousAssetID)
 	}
 
-	api.writeJSON(w, http.StatusOK, map[string]string{
-		"status":  string(models.ImageModerationApproved),
+	response := map[string]interface{}{
+		"status":  string(images.AssetDecision(asset).Status),
 		"message": "Image uploaded and queued for admin review",
-	})
+	}
+	if duplicates := uploadDuplicateWarnings(ctx, api.imageSvc, api.logger, asset); len(duplicates) > 0 {
+		response["duplicates"] = duplicates
+	}
+	api.writeJSON(w, http.StatusOK, response)
 }
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -145,7 +144,7 @@ func (api *ImageAPI) handleGetImage(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
-	if r.Method != http.MethodGet {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
@@ -157,33 +156,46 @@ func (api *ImageAPI) handleGetImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	asset, err := api.imageSvc.Load(ctx, id)
+	stream, err := api.imageSvc.OpenVariant(ctx, id, size)
 	if err != nil {
 		api.logger.Error("failed to load image asset", logging.WithField("error", err.Error()))
 		http.Error(w, "image not found", http.StatusNotFound)
 		return
 	}
-	if asset == nil || asset.Status != models.ImageModerationApproved || asset.EntityType != models.ImageEntityAvatar {
+	if stream == nil {
 		http.Error(w, "image not found", http.StatusNotFound)
 		return
 	}
 
-	contentType, ok := detectAllowedImageContentType(asset.ImageBytes)
-	if !ok {
+	asset := stream.Asset
+	if asset.Status != models.ImageModerationApproved || asset.EntityType != models.ImageEntityAvatar {
+		stream.Body.Close()
+		http.Error(w, "image not found", http.StatusNotFound)
+		return
+	}
+	if !isServableImageContentType(stream.ContentType) {
+		stream.Body.Close()
 		w.Header().Set("X-Content-Type-Options", "nosniff")
 		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
 		return
 	}
 
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("Content-Length", strconv.Itoa(len(asset.ImageBytes)))
-	w.Header().Set("Cache-Control", "public, max-age=300")
-	w.WriteHeader(http.StatusOK)
-	w.Write(asset.ImageBytes)
+	// Image IDs are never reused, so responses can be cached and revalidated by ETag
+	if err := writeImageStream(w, r, stream, "public, max-age=86400"); err != nil {
+		api.logger.Error("failed to stream image", logging.WithFields(map[string]interface{}{
+			"imageID": id,
+			"error":   err.Error(),
+		}))
+	}
 }
 
 func (api *ImageAPI) writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -191,3 +203,24 @@ func (api *ImageAPI) writeJSON(w http.ResponseWriter, status int, data interface
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
+
+// uploadOutcome is the status code and message for an image upload that was
+// kept: 200 once approved, 202 while it waits in the moderation review queue.
+func uploadOutcome(decision *models.ModerationDecision) (int, string) {
+	if decision.Queued {
+		return http.StatusAccepted, "Image uploaded and awaiting review"
+	}
+	return http.StatusOK, "Image uploaded successfully"
+}
+
+// uploadDuplicateWarnings lists images on other entities that nearly match a
+// freshly stored asset, so the response can warn about a likely reused photo.
+// The upload itself has already succeeded; lookup failures are only logged.
+func uploadDuplicateWarnings(ctx context.Context, imageSvc *images.Service, logger *logging.Logger, asset *models.ImageAsset) []models.ImageDuplicateMatch {
+	matches, err := imageSvc.FindUploadDuplicates(ctx, asset)
+	if err != nil {
+		logger.Warn("Near-duplicate image lookup failed", logging.WithField("error", err.Error()))
+		return nil
+	}
+	return matches
+}
@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// imageSizeParam reads the ?size= query parameter (thumb, card or full).
+// An empty value selects the original image.
+func imageSizeParam(r *http.Request) (models.ImageSize, bool) {
+	return models.ParseImageSize(r.URL.Query().Get("size"))
+}
+
+// writeImageStream serves an image with its ETag, answering a matching
+// If-None-Match with 304. Review placeholders are never cached, since the
+// entity's image URL does not change when the image is approved. It closes
+// the stream body.
+func writeImageStream(w http.ResponseWriter, r *http.Request, stream *images.ImageStream, cacheControl string) error {
+	defer stream.Body.Close()
+
+	if stream.Placeholder {
+		cacheControl = "no-store"
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", cacheControl)
+	if stream.ETag != "" {
+		w.Header().Set("ETag", stream.ETag)
+		if etagMatches(r.Header.Get("If-None-Match"), stream.ETag) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	w.Header().Set("Content-Type", stream.ContentType)
+	if stream.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(stream.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	_, err := io.Copy(w, stream.Body)
+	return err
+}
+
+// etagMatches reports whether an If-None-Match header lists etag (weak comparison).
+func etagMatches(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// isServableImageContentType allows upload formats plus the WebP derivatives.
+func isServableImageContentType(contentType string) bool {
+	if contentType == "image/webp" {
+		return true
+	}
+	_, ok := allowedImageContentTypes[contentType]
+	return ok
+}
@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+func TestWriteImageStream(t *testing.T) {
+	t.Parallel()
+
+	newStream := func() *images.ImageStream {
+		return &images.ImageStream{
+			Body:        io.NopCloser(strings.NewReader("webp bytes")),
+			Size:        10,
+			ContentType: "image/webp",
+			ETag:        `"img-1-thumb"`,
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/images/img-1?size=thumb", nil)
+	if err := writeImageStream(rec, req, newStream(), "public, max-age=86400"); err != nil {
+		t.Fatalf("writeImageStream() error = %v", err)
+	}
+	if rec.Code != http.StatusOK || rec.Body.String() != "webp bytes" {
+		t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("ETag") != `"img-1-thumb"` || rec.Header().Get("Content-Type") != "image/webp" ||
+		rec.Header().Get("Content-Length") != "10" || rec.Header().Get("Cache-Control") != "public, max-age=86400" {
+		t.Errorf("unexpected headers: %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/images/img-1?size=thumb", nil)
+	req.Header.Set("If-None-Match", `"other", W/"img-1-thumb"`)
+	if err := writeImageStream(rec, req, newStream(), "public, max-age=86400"); err != nil {
+		t.Fatalf("writeImageStream() error = %v", err)
+	}
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != `"img-1-thumb"` {
+		t.Errorf("conditional request: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestImageSizeParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query  string
+		want   models.ImageSize
+		wantOK bool
+	}{
+		{"", models.ImageSizeOriginal, true},
+		{"?size=thumb", models.ImageSizeThumb, true},
+		{"?size=CARD", models.ImageSizeCard, true},
+		{"?size=full", models.ImageSizeFull, true},
+		{"?size=huge", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/images/img-1"+tt.query, nil)
+		got, ok := imageSizeParam(req)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("imageSizeParam(%q) = %q, %v; want %q, %v", tt.query, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
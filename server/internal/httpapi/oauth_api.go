@@ -13,6 +13,7 @@ import (
 
 	"github.com/johnrirwin/flyingforge/internal/auth"
 	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
 )
 
 const oauthCORSDefaultAllowedHeaders = "Authorization, Content-Type, Accept, Last-Event-ID, MCP-Session-Id"
@@ -429,18 +430,39 @@ func (api *OAuthAPI) renderAuthorizeConsentPage(w http.ResponseWriter, authReq *
 	}
 }
 
+// writeScopeDescriptions says exactly what each MCP write scope lets a client
+// change, in the order the consent screen lists them
+var writeScopeDescriptions = map[string]string{
+	models.MCPScopeInventoryWrite: "Add gear to your inventory.",
+	models.MCPScopeBatteriesWrite: "Log charge cycles and health readings on your batteries.",
+	models.MCPScopeAircraftWrite:  "Change the components installed on your aircraft and save flight controller configs.",
+	models.MCPScopeBuildsWrite:    "Create draft builds. Drafts stay private until you publish them.",
+	models.MCPScopeFlightsWrite:   "Log flight sessions, which also log cycles on the batteries you flew.",
+}
+
 func describeAuthorizationAccess(scope string) []string {
 	scopeSet := map[string]struct{}{}
 	for _, value := range strings.Fields(strings.TrimSpace(scope)) {
 		scopeSet[value] = struct{}{}
 	}
 
-	descriptions := make([]string, 0, len(scopeSet))
-	if _, ok := scopeSet["flyingforge.read"]; ok {
+	writeDescriptions := []string{}
+	for _, writeScope := range models.MCPWriteScopes() {
+		if _, ok := scopeSet[writeScope]; ok {
+			writeDescriptions = append(writeDescriptions, writeScopeDescriptions[writeScope])
+			delete(scopeSet, writeScope)
+		}
+	}
+
+	descriptions := make([]string, 0, len(scopeSet)+len(writeDescriptions))
+	if _, ok := scopeSet[models.MCPScopeRead]; ok {
 		descriptions = append(descriptions, "View your aircraft, receiver summaries, tuning, radios, and backup metadata.")
-		descriptions = append(descriptions, "Use read-only access only; this app cannot modify your FlyingForge data.")
-		delete(scopeSet, "flyingforge.read")
+		if len(writeDescriptions) == 0 {
+			descriptions = append(descriptions, "Use read-only access only; this app cannot modify your FlyingForge data.")
+		}
+		delete(scopeSet, models.MCPScopeRead)
 	}
+	descriptions = append(descriptions, writeDescriptions...)
 
 	remainingScopes := make([]string, 0, len(scopeSet))
 	for value := range scopeSet {
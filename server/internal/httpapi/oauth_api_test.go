@@ -772,6 +772,24 @@ func TestDescribeAuthorizationAccess_SortsUnknownScopes(t *testing.T) {
 	}
 }
 
+func TestDescribeAuthorizationAccess_ListsWriteScopes(t *testing.T) {
+	descriptions := describeAuthorizationAccess("flyingforge.read flights:write inventory:write")
+	want := []string{
+		"View your aircraft, receiver summaries, tuning, radios, and backup metadata.",
+		"Add gear to your inventory.",
+		"Log flight sessions, which also log cycles on the batteries you flew.",
+	}
+
+	if len(descriptions) != len(want) {
+		t.Fatalf("expected %d descriptions, got %d: %#v", len(want), len(descriptions), descriptions)
+	}
+	for i, expected := range want {
+		if descriptions[i] != expected {
+			t.Fatalf("description %d: expected %q, got %q", i, expected, descriptions[i])
+		}
+	}
+}
+
 func makeSessionCookie(t *testing.T, jwtSecret, userID string, expiresAt time.Time) *http.Cookie {
 	t.Helper()
 
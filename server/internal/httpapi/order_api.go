@@ -0,0 +1,245 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/auth"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/orders"
+)
+
+// OrderAPI handles HTTP API requests for orders and shipment tracking
+type OrderAPI struct {
+	orderSvc       *orders.Service
+	authMiddleware *auth.Middleware
+	logger         *logging.Logger
+}
+
+// NewOrderAPI creates a new order API handler
+func NewOrderAPI(orderSvc *orders.Service, authMiddleware *auth.Middleware, logger *logging.Logger) *OrderAPI {
+	return &OrderAPI{
+		orderSvc:       orderSvc,
+		authMiddleware: authMiddleware,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes registers order routes on the given mux
+func (api *OrderAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(http.HandlerFunc) http.HandlerFunc) {
+	// Order routes (require authentication)
+	mux.HandleFunc("/api/orders", corsMiddleware(api.authMiddleware.RequireAuth(api.handleOrders)))
+	mux.HandleFunc("/api/orders/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleOrderItem)))
+}
+
+// handleOrders handles list and create operations
+func (api *OrderAPI) handleOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		api.listOrders(w, r)
+	case http.MethodPost:
+		api.createOrder(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listOrders returns the authenticated user's orders
+func (api *OrderAPI) listOrders(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+	query := r.URL.Query()
+
+	params := models.OrderListParams{
+		Status:          models.OrderStatus(query.Get("status")),
+		IncludeArchived: query.Get("includeArchived") == "true",
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			params.Limit = l
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			params.Offset = o
+		}
+	}
+
+	response, err := api.orderSvc.List(r.Context(), userID, params)
+	if err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// createOrder records an order and links the inventory items it contains
+func (api *OrderAPI) createOrder(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	var params models.CreateOrderParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := api.orderSvc.Create(r.Context(), userID, params)
+	if err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusCreated, order)
+}
+
+// handleOrderItem handles single order routes:
+//
+//	/api/orders/{id}
+//	/api/orders/{id}/items
+//	/api/orders/{id}/refresh
+func (api *OrderAPI) handleOrderItem(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/orders/")
+	parts := strings.Split(path, "/")
+
+	if len(parts) == 0 || parts[0] == "" {
+		http.Error(w, "Order ID required", http.StatusBadRequest)
+		return
+	}
+	orderID := parts[0]
+
+	if len(parts) == 2 {
+		switch {
+		case parts[1] == "items" && r.Method == http.MethodPut:
+			api.setOrderItems(w, r, orderID)
+		case parts[1] == "refresh" && r.Method == http.MethodPost:
+			api.refreshOrder(w, r, orderID)
+		case parts[1] == "items" || parts[1] == "refresh":
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "Unknown resource", http.StatusNotFound)
+		}
+		return
+	}
+	if len(parts) > 2 {
+		http.Error(w, "Unknown resource", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		api.getOrder(w, r, orderID)
+	case http.MethodPut, http.MethodPatch:
+		api.updateOrder(w, r, orderID)
+	case http.MethodDelete:
+		api.deleteOrder(w, r, orderID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getOrder retrieves a single order
+func (api *OrderAPI) getOrder(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	order, err := api.orderSvc.Get(r.Context(), id, userID)
+	if err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+	if order == nil {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, order)
+}
+
+// updateOrder edits an order's details or sets its status by hand
+func (api *OrderAPI) updateOrder(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	var params models.UpdateOrderParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	params.ID = id
+
+	order, err := api.orderSvc.Update(r.Context(), userID, params)
+	if err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, order)
+}
+
+// deleteOrder removes an order; its items stay in the inventory
+func (api *OrderAPI) deleteOrder(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	if err := api.orderSvc.Delete(r.Context(), id, userID); err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setOrderItems replaces the inventory items linked to an order
+func (api *OrderAPI) setOrderItems(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	var body struct {
+		InventoryItemIDs []string `json:"inventoryItemIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := api.orderSvc.SetItems(r.Context(), id, userID, body.InventoryItemIDs)
+	if err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, order)
+}
+
+// refreshOrder checks the carrier for an order's status right away
+func (api *OrderAPI) refreshOrder(w http.ResponseWriter, r *http.Request, id string) {
+	userID := auth.GetUserID(r.Context())
+
+	order, err := api.orderSvc.Refresh(r.Context(), id, userID)
+	if err != nil {
+		api.writeOrderError(w, err)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, order)
+}
+
+func (api *OrderAPI) writeOrderError(w http.ResponseWriter, err error) {
+	var svcErr *orders.ServiceError
+	if errors.As(err, &svcErr) {
+		status := http.StatusBadRequest
+		if strings.HasSuffix(svcErr.Message, "not found") {
+			status = http.StatusNotFound
+		}
+		api.writeJSON(w, status, map[string]string{"error": svcErr.Message})
+		return
+	}
+	api.logger.Error("Order request failed", logging.WithField("error", err.Error()))
+	api.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to process order request"})
+}
+
+func (api *OrderAPI) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
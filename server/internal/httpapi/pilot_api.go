@@ -9,6 +9,7 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/auth"
 	"github.com/johnrirwin/flyingforge/internal/builds"
 	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/images"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
@@ -19,17 +20,19 @@ type PilotAPI struct {
 	aircraftStore  *database.AircraftStore
 	fcConfigStore  *database.FCConfigStore
 	buildSvc       *builds.Service
+	imageSvc       *images.Service
 	authMiddleware *auth.Middleware
 	logger         *logging.Logger
 }
 
 // NewPilotAPI creates a new pilot API handler
-func NewPilotAPI(userStore *database.UserStore, aircraftStore *database.AircraftStore, fcConfigStore *database.FCConfigStore, buildSvc *builds.Service, authMiddleware *auth.Middleware, logger *logging.Logger) *PilotAPI {
+func NewPilotAPI(userStore *database.UserStore, aircraftStore *database.AircraftStore, fcConfigStore *database.FCConfigStore, buildSvc *builds.Service, imageSvc *images.Service, authMiddleware *auth.Middleware, logger *logging.Logger) *PilotAPI {
 	return &PilotAPI{
 		userStore:      userStore,
 		aircraftStore:  aircraftStore,
 		fcConfigStore:  fcConfigStore,
 		buildSvc:       buildSvc,
+		imageSvc:       imageSvc,
 		authMiddleware: authMiddleware,
 		logger:         logger,
 	}
@@ -315,24 +318,35 @@ func (api *PilotAPI) handleAircraftImage(w http.ResponseWriter, r *http.Request)
 
 	ctx := r.Context()
 
+	size, ok := imageSizeParam(r)
+	if !ok {
+		http.Error(w, "size must be thumb, card or full", http.StatusBadRequest)
+		return
+	}
+
 	// Get public image (checks owner's visibility settings)
-	imageData, imageType, err := api.aircraftStore.GetPublicImage(ctx, aircraftID)
+	ref, err := api.aircraftStore.GetPublicImage(ctx, aircraftID)
+	var stream *images.ImageStream
+	if err == nil && ref != nil {
+		if api.imageSvc == nil {
+			http.Error(w, "Image storage unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		stream, err = api.imageSvc.OpenRef(ctx, ref, size)
+	}
 	if err != nil {
 		api.logger.Error("Failed to get aircraft image", logging.WithField("error", err.Error()))
 		http.Error(w, "Failed to get image", http.StatusInternalServerError)
 		return
 	}
 
-	if imageData == nil {
+	if stream == nil {
 		http.Error(w, "Image not found or not public", http.StatusNotFound)
 		return
 	}
 
 	// Set cache headers
-	w.Header().Set("Content-Type", imageType)
-	w.Header().Set("Cache-Control", "public, max-age=3600")
-	w.WriteHeader(http.StatusOK)
-	w.Write(imageData)
+	_ = writeImageStream(w, r, stream, "public, max-age=3600")
 }
 
 func (api *PilotAPI) writeJSON(w http.ResponseWriter, status int, data interface{}) {
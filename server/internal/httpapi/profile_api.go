@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,12 +15,51 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/images"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/takeout"
 )
 
+// archiveTransferTimeout bounds an export download or import upload. The
+// server's timeouts are sized for JSON requests and would cut a large
+// archive off partway through.
+const archiveTransferTimeout = time.Hour
+
+// FlightStatsSource provides a pilot's flying totals for the profile
+type FlightStatsSource interface {
+	PilotStats(ctx context.Context, userID string) (*models.PilotFlightStats, error)
+}
+
+// PersonalAccessTokenManager issues, lists and revokes a user's personal access tokens
+type PersonalAccessTokenManager interface {
+	CreatePersonalAccessToken(ctx context.Context, userID string, params models.CreatePersonalAccessTokenParams) (*models.PersonalAccessTokenCreateResponse, error)
+	ListPersonalAccessTokens(ctx context.Context, userID string) ([]models.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, userID, tokenID string) error
+}
+
+// DataExporter runs account data exports and serves their archives
+type DataExporter interface {
+	RequestExport(ctx context.Context, userID string) (*models.DataExport, error)
+	ListExports(ctx context.Context, userID string) ([]models.DataExport, error)
+	GetExport(ctx context.Context, userID string, id string) (*models.DataExport, error)
+	OpenArchive(ctx context.Context, userID string, id string) (io.ReadCloser, *models.DataExport, error)
+	GetDownloadURL(ctx context.Context, userID string, id string) (*models.DataExportDownloadURL, error)
+	DeleteArchives(ctx context.Context, userID string) error
+}
+
+// DataImporter restores export archives into a user's account
+type DataImporter interface {
+	RequestImport(ctx context.Context, userID string, archive io.ReaderAt, size int64) (*models.DataImport, error)
+	ListImports(ctx context.Context, userID string) ([]models.DataImport, error)
+	GetImport(ctx context.Context, userID string, id string) (*models.DataImport, error)
+}
+
 // ProfileAPI handles profile HTTP endpoints
 type ProfileAPI struct {
 	userStore      *database.UserStore
 	imageSvc       *images.Service
+	flightStats    FlightStatsSource
+	accessTokens   PersonalAccessTokenManager
+	exports        DataExporter
+	imports        DataImporter
 	authMiddleware *auth.Middleware
 	logger         *logging.Logger
 }
@@ -33,11 +74,324 @@ func NewProfileAPI(userStore *database.UserStore, imageSvc *images.Service, auth
 	}
 }
 
+// SetFlightStats includes pilot flight stats in the profile response
+func (api *ProfileAPI) SetFlightStats(source FlightStatsSource) {
+	api.flightStats = source
+}
+
+// SetPersonalAccessTokens enables the /api/me/tokens endpoints
+func (api *ProfileAPI) SetPersonalAccessTokens(manager PersonalAccessTokenManager) {
+	api.accessTokens = manager
+}
+
+// SetDataExports enables the /api/me/exports endpoints
+func (api *ProfileAPI) SetDataExports(exporter DataExporter) {
+	api.exports = exporter
+}
+
+// SetDataImports enables the /api/me/imports endpoints
+func (api *ProfileAPI) SetDataImports(importer DataImporter) {
+	api.imports = importer
+}
+
 // RegisterRoutes registers profile routes on the given mux
 func (api *ProfileAPI) RegisterRoutes(mux *http.ServeMux, corsMiddleware func(http.HandlerFunc) http.HandlerFunc) {
 	mux.HandleFunc("/api/me/profile", corsMiddleware(api.authMiddleware.RequireAuth(api.handleProfile)))
 	mux.HandleFunc("/api/me/avatar", corsMiddleware(api.authMiddleware.RequireAuth(api.handleAvatar)))
 	mux.HandleFunc("/api/users/avatar", corsMiddleware(api.authMiddleware.RequireAuth(api.handleAvatar)))
+	if api.accessTokens != nil {
+		mux.HandleFunc("/api/me/tokens", corsMiddleware(api.authMiddleware.RequireAuth(api.handleAccessTokens)))
+		mux.HandleFunc("/api/me/tokens/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleAccessToken)))
+	}
+	if api.exports != nil {
+		mux.HandleFunc("/api/me/exports", corsMiddleware(api.authMiddleware.RequireAuth(api.handleExports)))
+		mux.HandleFunc("/api/me/exports/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleExport)))
+	}
+	if api.imports != nil {
+		mux.HandleFunc("/api/me/imports", corsMiddleware(api.authMiddleware.RequireAuth(api.handleImports)))
+		mux.HandleFunc("/api/me/imports/", corsMiddleware(api.authMiddleware.RequireAuth(api.handleImport)))
+	}
+}
+
+// handleAccessTokens handles GET and POST /api/me/tokens
+func (api *ProfileAPI) handleAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := api.accessTokens.ListPersonalAccessTokens(r.Context(), userID)
+		if err != nil {
+			api.logger.Error("Failed to list personal access tokens", logging.WithField("error", err.Error()))
+			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to list tokens")
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"tokens": tokens,
+			"scopes": auth.PersonalAccessTokenScopes(),
+		})
+	case http.MethodPost:
+		var params models.CreatePersonalAccessTokenParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			api.writeError(w, http.StatusBadRequest, "invalid_request", "invalid request body")
+			return
+		}
+
+		response, err := api.accessTokens.CreatePersonalAccessToken(r.Context(), userID, params)
+		if err != nil {
+			if authErr, ok := err.(*auth.AuthError); ok {
+				api.writeError(w, http.StatusBadRequest, authErr.Code, authErr.Message)
+				return
+			}
+			api.logger.Error("Failed to create personal access token", logging.WithField("error", err.Error()))
+			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to create token")
+			return
+		}
+		api.writeJSON(w, http.StatusCreated, response)
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAccessToken handles DELETE /api/me/tokens/{id}
+func (api *ProfileAPI) handleAccessToken(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tokenID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/me/tokens/"), "/")
+	if tokenID == "" || strings.Contains(tokenID, "/") {
+		api.writeError(w, http.StatusNotFound, "not_found", "token not found")
+		return
+	}
+
+	if err := api.accessTokens.RevokePersonalAccessToken(r.Context(), auth.GetUserID(r.Context()), tokenID); err != nil {
+		if authErr, ok := err.(*auth.AuthError); ok && authErr.Code == "not_found" {
+			api.writeError(w, http.StatusNotFound, "not_found", "token not found")
+			return
+		}
+		api.logger.Error("Failed to revoke personal access token", logging.WithField("error", err.Error()))
+		api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to revoke token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleExports handles GET and POST /api/me/exports
+func (api *ProfileAPI) handleExports(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		exports, err := api.exports.ListExports(r.Context(), userID)
+		if err != nil {
+			api.logger.Error("Failed to list data exports", logging.WithField("error", err.Error()))
+			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to list exports")
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{"exports": exports})
+	case http.MethodPost:
+		export, err := api.exports.RequestExport(r.Context(), userID)
+		if err != nil {
+			api.logger.Error("Failed to request data export", logging.WithField("error", err.Error()))
+			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to start export")
+			return
+		}
+		api.writeJSON(w, http.StatusAccepted, export)
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleExport handles GET /api/me/exports/{id}, /download and /download-url
+func (api *ProfileAPI) handleExport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/me/exports/"), "/"), "/")
+	exportID := parts[0]
+	if exportID == "" || len(parts) > 2 {
+		api.writeError(w, http.StatusNotFound, "not_found", "export not found")
+		return
+	}
+
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	switch action {
+	case "":
+		export, err := api.exports.GetExport(r.Context(), userID, exportID)
+		if err != nil {
+			api.writeExportError(w, err)
+			return
+		}
+		api.writeJSON(w, http.StatusOK, export)
+	case "download":
+		api.handleExportDownload(w, r, userID, exportID)
+	case "download-url":
+		url, err := api.exports.GetDownloadURL(r.Context(), userID, exportID)
+		if err != nil {
+			api.writeExportError(w, err)
+			return
+		}
+		api.writeJSON(w, http.StatusOK, url)
+	default:
+		api.writeError(w, http.StatusNotFound, "not_found", "export not found")
+	}
+}
+
+// handleExportDownload streams a ready export's ZIP archive
+func (api *ProfileAPI) handleExportDownload(w http.ResponseWriter, r *http.Request, userID string, exportID string) {
+	file, export, err := api.exports.OpenArchive(r.Context(), userID, exportID)
+	if err != nil {
+		api.writeExportError(w, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": takeout.ArchiveFileName(export)}))
+	w.Header().Set("Content-Length", strconv.FormatInt(export.FileSize, 10))
+
+	// Not every ResponseWriter supports deadlines; those have none to lift
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(archiveTransferTimeout))
+
+	if _, err := io.Copy(w, file); err != nil {
+		// Headers are already sent, so the failure can only be logged
+		api.logger.Error("Failed to stream data export", logging.WithFields(map[string]interface{}{
+			"exportId": exportID,
+			"error":    err.Error(),
+		}))
+	}
+}
+
+func (api *ProfileAPI) writeExportError(w http.ResponseWriter, err error) {
+	if svcErr, ok := err.(*takeout.ServiceError); ok {
+		if strings.Contains(svcErr.Message, "not found") {
+			api.writeError(w, http.StatusNotFound, "not_found", svcErr.Message)
+			return
+		}
+		api.writeError(w, http.StatusConflict, "not_ready", svcErr.Message)
+		return
+	}
+	api.logger.Error("Data export request failed", logging.WithField("error", err.Error()))
+	api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to get export")
+}
+
+// handleImports handles GET and POST /api/me/imports
+func (api *ProfileAPI) handleImports(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		imports, err := api.imports.ListImports(r.Context(), userID)
+		if err != nil {
+			api.logger.Error("Failed to list data imports", logging.WithField("error", err.Error()))
+			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to list imports")
+			return
+		}
+		api.writeJSON(w, http.StatusOK, map[string]interface{}{"imports": imports})
+	case http.MethodPost:
+		api.handleCreateImport(w, r, userID)
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreateImport accepts an export archive as the multipart "file" field
+// and queues it for import
+func (api *ProfileAPI) handleCreateImport(w http.ResponseWriter, r *http.Request, userID string) {
+	// The write deadline runs from the request's arrival, so it is lifted
+	// along with the read deadline or the reply to a slow upload would be lost
+	controller := http.NewResponseController(w)
+	_ = controller.SetReadDeadline(time.Now().Add(archiveTransferTimeout))
+	_ = controller.SetWriteDeadline(time.Now().Add(archiveTransferTimeout))
+
+	// Allow a little over the archive limit for multipart overhead; parts
+	// beyond 32MB are spooled to disk rather than held in memory
+	r.Body = http.MaxBytesReader(w, r.Body, takeout.MaxImportArchiveSize+1024*1024)
+	if err := r.ParseMultipartForm(32 * 1024 * 1024); err != nil {
+		api.writeError(w, http.StatusBadRequest, "invalid_request", "invalid upload payload")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		api.writeError(w, http.StatusBadRequest, "invalid_request", "file is required")
+		return
+	}
+	defer file.Close()
+
+	job, err := api.imports.RequestImport(r.Context(), userID, file, header.Size)
+	if err != nil {
+		if svcErr, ok := err.(*takeout.ServiceError); ok {
+			status := http.StatusBadRequest
+			if strings.Contains(svcErr.Message, "in progress") || strings.Contains(svcErr.Message, "already been imported") {
+				status = http.StatusConflict
+			}
+			api.writeError(w, status, "invalid_archive", svcErr.Message)
+			return
+		}
+		api.logger.Error("Failed to request data import", logging.WithField("error", err.Error()))
+		api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to start import")
+		return
+	}
+	api.writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleImport handles GET /api/me/imports/{id}
+func (api *ProfileAPI) handleImport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := auth.GetUserID(r.Context())
+	importID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/me/imports/"), "/")
+	if importID == "" || strings.Contains(importID, "/") {
+		api.writeError(w, http.StatusNotFound, "not_found", "import not found")
+		return
+	}
+
+	job, err := api.imports.GetImport(r.Context(), userID, importID)
+	if err != nil {
+		if _, ok := err.(*takeout.ServiceError); ok {
+			api.writeError(w, http.StatusNotFound, "not_found", err.Error())
+			return
+		}
+		api.logger.Error("Failed to get data import", logging.WithField("error", err.Error()))
+		api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to get import")
+		return
+	}
+	api.writeJSON(w, http.StatusOK, job)
 }
 
 // handleProfile handles GET, PUT, and DELETE /api/me/profile
@@ -87,6 +441,16 @@ func (api *ProfileAPI) handleGetProfile(w http.ResponseWriter, r *http.Request)
 		"updatedAt":          user.UpdatedAt,
 	}
 
+	// Flight stats are best-effort; the profile still loads without them
+	if api.flightStats != nil {
+		stats, err := api.flightStats.PilotStats(r.Context(), userID)
+		if err != nil {
+			api.logger.Warn("Failed to get flight stats", logging.WithField("error", err.Error()))
+		} else {
+			response["flightStats"] = stats
+		}
+	}
+
 	api.writeJSON(w, http.StatusOK, response)
 }
 
@@ -297,7 +661,7 @@ func (api *ProfileAPI) handleAvatar(w http.ResponseWriter, r *http.Request) {
 			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to save avatar")
 			return
 		}
-		if decision == nil || decision.Status != models.ImageModerationApproved {
+		if !decision.Accepted() {
 			if decision != nil && decision.Status == models.ImageModerationPendingReview {
 				api.writeError(w, http.StatusServiceUnavailable, "not_approved", "unable to verify right now")
 				return
@@ -366,6 +730,17 @@ func (api *ProfileAPI) handleDeleteProfile(w http.ResponseWriter, r *http.Reques
 	api.logger.Info("User account deletion requested",
 		logging.WithField("userID", userID))
 
+	// Export archives live outside the database, so remove them first
+	if api.exports != nil {
+		if err := api.exports.DeleteArchives(r.Context(), userID); err != nil {
+			api.logger.Error("Failed to delete data export archives",
+				logging.WithField("error", err.Error()),
+				logging.WithField("userID", userID))
+			api.writeError(w, http.StatusInternalServerError, "internal_error", "failed to delete account")
+			return
+		}
+	}
+
 	// Delete the user (cascades to related data via DB constraints)
 	if err := api.userStore.HardDelete(r.Context(), userID); err != nil {
 		api.logger.Error("Failed to delete user account",
@@ -0,0 +1,342 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/auth"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/takeout"
+)
+
+type accessTokenManagerStub struct {
+	createUserID string
+	createParams models.CreatePersonalAccessTokenParams
+	createErr    error
+
+	revokeUserID  string
+	revokeTokenID string
+	revokeErr     error
+}
+
+func (s *accessTokenManagerStub) CreatePersonalAccessToken(_ context.Context, userID string, params models.CreatePersonalAccessTokenParams) (*models.PersonalAccessTokenCreateResponse, error) {
+	s.createUserID = userID
+	s.createParams = params
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+	return &models.PersonalAccessTokenCreateResponse{
+		Token:       models.PersonalAccessTokenPrefix + "secret",
+		AccessToken: &models.PersonalAccessToken{ID: "pat-1", UserID: userID, Name: params.Name, Scopes: params.Scopes},
+	}, nil
+}
+
+func (s *accessTokenManagerStub) ListPersonalAccessTokens(_ context.Context, _ string) ([]models.PersonalAccessToken, error) {
+	return []models.PersonalAccessToken{}, nil
+}
+
+func (s *accessTokenManagerStub) RevokePersonalAccessToken(_ context.Context, userID, tokenID string) error {
+	s.revokeUserID = userID
+	s.revokeTokenID = tokenID
+	return s.revokeErr
+}
+
+func newAccessTokenTestAPI(manager PersonalAccessTokenManager) *ProfileAPI {
+	api := NewProfileAPI(nil, nil, nil, logging.New(logging.LevelError))
+	api.SetPersonalAccessTokens(manager)
+	return api
+}
+
+func TestProfileAPICreatesPersonalAccessToken(t *testing.T) {
+	manager := &accessTokenManagerStub{}
+	api := newAccessTokenTestAPI(manager)
+
+	body := bytes.NewBufferString(`{"name":"Bench","scopes":["batteries:write"],"expiresInDays":30}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/me/tokens", body)
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-1"))
+	rec := httptest.NewRecorder()
+	api.handleAccessTokens(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if manager.createUserID != "user-1" || manager.createParams.ExpiresInDays != 30 {
+		t.Fatalf("unexpected create call: %q %+v", manager.createUserID, manager.createParams)
+	}
+
+	var response models.PersonalAccessTokenCreateResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Token == "" || response.AccessToken == nil || response.AccessToken.ID != "pat-1" {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+}
+
+func TestProfileAPIRejectsInvalidPersonalAccessToken(t *testing.T) {
+	api := newAccessTokenTestAPI(&accessTokenManagerStub{createErr: &auth.AuthError{Code: "invalid_input", Message: "name is required"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/me/tokens", bytes.NewBufferString(`{"scopes":["flyingforge.read"]}`))
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-1"))
+	rec := httptest.NewRecorder()
+	api.handleAccessTokens(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestProfileAPIRevokesPersonalAccessToken(t *testing.T) {
+	manager := &accessTokenManagerStub{}
+	api := newAccessTokenTestAPI(manager)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/me/tokens/pat-1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-1"))
+	rec := httptest.NewRecorder()
+	api.handleAccessToken(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if manager.revokeUserID != "user-1" || manager.revokeTokenID != "pat-1" {
+		t.Fatalf("unexpected revoke call: %q %q", manager.revokeUserID, manager.revokeTokenID)
+	}
+
+	manager.revokeErr = &auth.AuthError{Code: "not_found", Message: "personal access token not found"}
+	rec = httptest.NewRecorder()
+	api.handleAccessToken(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown token, got %d", rec.Code)
+	}
+}
+
+type dataExporterStub struct {
+	requestedBy string
+	archive     io.ReadCloser // Served by OpenArchive when set
+	archiveSize int64
+}
+
+func (s *dataExporterStub) RequestExport(_ context.Context, userID string) (*models.DataExport, error) {
+	s.requestedBy = userID
+	return &models.DataExport{ID: "export-1", Status: models.DataExportPending}, nil
+}
+
+func (s *dataExporterStub) ListExports(_ context.Context, _ string) ([]models.DataExport, error) {
+	return []models.DataExport{}, nil
+}
+
+func (s *dataExporterStub) GetExport(_ context.Context, _ string, id string) (*models.DataExport, error) {
+	if id != "export-1" {
+		return nil, &takeout.ServiceError{Message: "export not found"}
+	}
+	return &models.DataExport{ID: id, Status: models.DataExportRunning}, nil
+}
+
+func (s *dataExporterStub) OpenArchive(_ context.Context, _ string, id string) (io.ReadCloser, *models.DataExport, error) {
+	if s.archive != nil {
+		return s.archive, &models.DataExport{ID: id, Status: models.DataExportReady, FileSize: s.archiveSize}, nil
+	}
+	return nil, nil, &takeout.ServiceError{Message: "export is running, not ready to download"}
+}
+
+func (s *dataExporterStub) GetDownloadURL(_ context.Context, _ string, _ string) (*models.DataExportDownloadURL, error) {
+	return nil, &takeout.ServiceError{Message: "direct download is not available with the configured storage backend"}
+}
+
+func (s *dataExporterStub) DeleteArchives(_ context.Context, _ string) error {
+	return nil
+}
+
+func TestProfileAPIDataExportEndpoints(t *testing.T) {
+	exporter := &dataExporterStub{}
+	api := NewProfileAPI(nil, nil, nil, logging.New(logging.LevelError))
+	api.SetDataExports(exporter)
+
+	serve := func(method, path string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-1"))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, "/api/me/exports", api.handleExports); rec.Code != http.StatusAccepted || exporter.requestedBy != "user-1" {
+		t.Fatalf("expected 202 for user-1, got %d (%q)", rec.Code, exporter.requestedBy)
+	}
+	if rec := serve(http.MethodGet, "/api/me/exports/export-1", api.handleExport); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/api/me/exports/export-2", api.handleExport); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown export, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/api/me/exports/export-1/download", api.handleExport); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 while the export is running, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/api/me/exports/export-1/other", api.handleExport); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown action, got %d", rec.Code)
+	}
+}
+
+// slowReader trickles its content out one chunk per delay
+type slowReader struct {
+	chunks [][]byte
+	delay  time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p, r.chunks[0])
+	r.chunks[0] = r.chunks[0][n:]
+	if len(r.chunks[0]) == 0 {
+		r.chunks = r.chunks[1:]
+	}
+	return n, nil
+}
+
+func (r *slowReader) Close() error { return nil }
+
+func TestProfileAPIExportDownloadOutlivesServerWriteTimeout(t *testing.T) {
+	chunk := bytes.Repeat([]byte("z"), 1024)
+	exporter := &dataExporterStub{
+		archive:     &slowReader{chunks: [][]byte{chunk, chunk, chunk, chunk}, delay: 100 * time.Millisecond},
+		archiveSize: 4 * int64(len(chunk)),
+	}
+	api := NewProfileAPI(nil, nil, nil, logging.New(logging.LevelError))
+	api.SetDataExports(exporter)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.handleExport(w, r.WithContext(context.WithValue(r.Context(), auth.UserIDKey, "user-1")))
+	}))
+	server.Config.WriteTimeout = 150 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/me/exports/export-1/download")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected the whole archive past the write timeout, got %d bytes: %v", len(body), err)
+	}
+	if int64(len(body)) != exporter.archiveSize {
+		t.Fatalf("expected %d bytes, got %d", exporter.archiveSize, len(body))
+	}
+}
+
+type dataImporterStub struct {
+	uploaded []byte
+}
+
+func (s *dataImporterStub) RequestImport(_ context.Context, _ string, archive io.ReaderAt, size int64) (*models.DataImport, error) {
+	s.uploaded = make([]byte, size)
+	if _, err := archive.ReadAt(s.uploaded, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.HasPrefix(s.uploaded, []byte("PK")) {
+		return nil, &takeout.ServiceError{Message: "archive is not a valid ZIP file"}
+	}
+	return &models.DataImport{ID: "import-1", Status: models.DataImportPending}, nil
+}
+
+func (s *dataImporterStub) ListImports(_ context.Context, _ string) ([]models.DataImport, error) {
+	return []models.DataImport{}, nil
+}
+
+func (s *dataImporterStub) GetImport(_ context.Context, _ string, id string) (*models.DataImport, error) {
+	if id != "import-1" {
+		return nil, &takeout.ServiceError{Message: "import not found"}
+	}
+	return &models.DataImport{ID: id, Status: models.DataImportRunning}, nil
+}
+
+func TestProfileAPIImportUploadOutlivesServerTimeouts(t *testing.T) {
+	importer := &dataImporterStub{}
+	api := NewProfileAPI(nil, nil, nil, logging.New(logging.LevelError))
+	api.SetDataImports(importer)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.handleImports(w, r.WithContext(context.WithValue(r.Context(), auth.UserIDKey, "user-1")))
+	}))
+	server.Config.ReadTimeout = 150 * time.Millisecond
+	server.Config.WriteTimeout = 150 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	// Trickle the archive in slower than either timeout allows
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		part, _ := form.CreateFormFile("file", "flyingforge-export.zip")
+		_, _ = part.Write([]byte("PK\x03\x04"))
+		for i := 0; i < 4; i++ {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = part.Write(bytes.Repeat([]byte("z"), 1024))
+		}
+		_ = form.Close()
+		_ = writer.Close()
+	}()
+
+	resp, err := http.Post(server.URL+"/api/me/imports", form.FormDataContentType(), body)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || len(importer.uploaded) != 4+4*1024 {
+		t.Fatalf("expected the whole upload to be accepted, got %d with %d bytes", resp.StatusCode, len(importer.uploaded))
+	}
+}
+
+func TestProfileAPIDataImportEndpoints(t *testing.T) {
+	importer := &dataImporterStub{}
+	api := NewProfileAPI(nil, nil, nil, logging.New(logging.LevelError))
+	api.SetDataImports(importer)
+
+	upload := func(content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "flyingforge-export.zip")
+		_, _ = part.Write([]byte(content))
+		_ = form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/me/imports", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-1"))
+		rec := httptest.NewRecorder()
+		api.handleImports(rec, req)
+		return rec
+	}
+
+	if rec := upload("PK\x03\x04archive"); rec.Code != http.StatusAccepted || string(importer.uploaded) != "PK\x03\x04archive" {
+		t.Fatalf("expected 202 with the uploaded archive, got %d (%q)", rec.Code, importer.uploaded)
+	}
+	if rec := upload("not a zip"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid archive, got %d", rec.Code)
+	}
+
+	get := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-1"))
+		rec := httptest.NewRecorder()
+		api.handleImport(rec, req)
+		return rec.Code
+	}
+	if code := get("/api/me/imports/import-1"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := get("/api/me/imports/import-2"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown import, got %d", code)
+	}
+}
@@ -127,7 +127,7 @@ func (api *RadioAPI) handleCreateRadio(w http.ResponseWriter, r *http.Request, u
 func (api *RadioAPI) handleRadioItem(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserID(r.Context())
 
-	// Parse the path: /api/radios/{radioId}[/backups[/{backupId}[/download]]]
+	// Parse the path: /api/radios/{radioId}[/backups[/{backupId}[/download|/models]]]
 	path := strings.TrimPrefix(r.URL.Path, "/api/radios/")
 	parts := strings.Split(path, "/")
 
@@ -167,6 +167,39 @@ func (api *RadioAPI) handleRadioItem(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			// Short-lived direct download URL from the object store
+			if len(parts) == 4 && parts[3] == "download-url" {
+				// /api/radios/{radioId}/backups/{backupId}/download-url
+				if r.Method == http.MethodGet {
+					api.handleGetBackupDownloadURL(w, r, radioID, backupID, userID)
+				} else {
+					http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				}
+				return
+			}
+
+			// Check for parsed EdgeTX models
+			if len(parts) == 4 && parts[3] == "models" {
+				// /api/radios/{radioId}/backups/{backupId}/models
+				if r.Method == http.MethodGet {
+					api.handleGetBackupModels(w, r, radioID, backupID, userID)
+				} else {
+					http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				}
+				return
+			}
+
+			// Compare with another backup of the same radio
+			if len(parts) == 4 && parts[3] == "diff" {
+				// /api/radios/{radioId}/backups/{backupId}/diff?base={otherBackupId}
+				if r.Method == http.MethodGet {
+					api.handleDiffBackups(w, r, radioID, backupID, userID)
+				} else {
+					http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				}
+				return
+			}
+
 			// /api/radios/{radioId}/backups/{backupId}
 			switch r.Method {
 			case http.MethodGet:
@@ -364,6 +397,47 @@ func (api *RadioAPI) handleGetBackup(w http.ResponseWriter, r *http.Request, rad
 	api.writeJSON(w, http.StatusOK, backup)
 }
 
+// handleGetBackupModels returns the EdgeTX model list parsed from a backup
+func (api *RadioAPI) handleGetBackupModels(w http.ResponseWriter, r *http.Request, radioID string, backupID string, userID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	summary, err := api.radioSvc.GetBackupModels(ctx, backupID, radioID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if _, ok := err.(*radiosvc.ServiceError); ok {
+			status = http.StatusBadRequest
+		}
+		api.writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, summary)
+}
+
+// handleDiffBackups compares a backup against the backup given in ?base=
+func (api *RadioAPI) handleDiffBackups(w http.ResponseWriter, r *http.Request, radioID string, backupID string, userID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	baseID := strings.TrimSpace(r.URL.Query().Get("base"))
+	diff, err := api.radioSvc.DiffBackups(ctx, radioID, baseID, backupID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if _, ok := err.(*radiosvc.ServiceError); ok {
+			status = http.StatusBadRequest
+		}
+		api.writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, diff)
+}
+
 // handleDownloadBackup downloads a backup file
 func (api *RadioAPI) handleDownloadBackup(w http.ResponseWriter, r *http.Request, radioID string, backupID string, userID string) {
 	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
@@ -402,6 +476,26 @@ func (api *RadioAPI) handleDownloadBackup(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// handleGetBackupDownloadURL returns a presigned URL for downloading a backup directly
+func (api *RadioAPI) handleGetBackupDownloadURL(w http.ResponseWriter, r *http.Request, radioID string, backupID string, userID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	download, err := api.radioSvc.GetBackupDownloadURL(ctx, backupID, radioID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if _, ok := err.(*radiosvc.ServiceError); ok {
+			status = http.StatusBadRequest
+		}
+		api.writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, download)
+}
+
 // handleDeleteBackup deletes a backup
 func (api *RadioAPI) handleDeleteBackup(w http.ResponseWriter, r *http.Request, radioID string, backupID string, userID string) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
@@ -16,13 +16,16 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/builds"
 	"github.com/johnrirwin/flyingforge/internal/database"
 	"github.com/johnrirwin/flyingforge/internal/equipment"
+	"github.com/johnrirwin/flyingforge/internal/flights"
 	"github.com/johnrirwin/flyingforge/internal/images"
 	"github.com/johnrirwin/flyingforge/internal/inventory"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/mcp"
 	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/orders"
 	"github.com/johnrirwin/flyingforge/internal/radio"
 	"github.com/johnrirwin/flyingforge/internal/ratelimit"
+	"github.com/johnrirwin/flyingforge/internal/takeout"
 )
 
 type Server struct {
@@ -34,6 +37,10 @@ type Server struct {
 	buildSvc            *builds.Service
 	radioSvc            *radio.Service
 	batterySvc          *battery.Service
+	flightSvc           *flights.Service
+	orderSvc            *orders.Service
+	exportSvc           *takeout.Service
+	importSvc           *takeout.Importer
 	authSvc             *auth.Service
 	oauthSvc            *auth.OAuthServerService
 	authMiddleware      *auth.Middleware
@@ -51,7 +58,7 @@ type Server struct {
 	enableManualRefresh bool
 }
 
-func New(agg *aggregator.Aggregator, announcementSvc *announcements.Service, equipmentSvc *equipment.Service, inventorySvc inventory.InventoryManager, aircraftSvc *aircraft.Service, buildSvc *builds.Service, radioSvc *radio.Service, batterySvc *battery.Service, authSvc *auth.Service, oauthSvc *auth.OAuthServerService, authMiddleware *auth.Middleware, mcpHandler *mcp.HTTPHandler, userStore *database.UserStore, aircraftStore *database.AircraftStore, fcConfigStore *database.FCConfigStore, inventoryStore *database.InventoryStore, gearCatalogStore *database.GearCatalogStore, imageSvc *images.Service, refreshLimiter ratelimit.RateLimiter, enableManualRefresh bool, logger *logging.Logger) *Server {
+func New(agg *aggregator.Aggregator, announcementSvc *announcements.Service, equipmentSvc *equipment.Service, inventorySvc inventory.InventoryManager, aircraftSvc *aircraft.Service, buildSvc *builds.Service, radioSvc *radio.Service, batterySvc *battery.Service, flightSvc *flights.Service, orderSvc *orders.Service, exportSvc *takeout.Service, importSvc *takeout.Importer, authSvc *auth.Service, oauthSvc *auth.OAuthServerService, authMiddleware *auth.Middleware, mcpHandler *mcp.HTTPHandler, userStore *database.UserStore, aircraftStore *database.AircraftStore, fcConfigStore *database.FCConfigStore, inventoryStore *database.InventoryStore, gearCatalogStore *database.GearCatalogStore, imageSvc *images.Service, refreshLimiter ratelimit.RateLimiter, enableManualRefresh bool, logger *logging.Logger) *Server {
 	return &Server{
 		agg:                 agg,
 		announcementSvc:     announcementSvc,
@@ -61,6 +68,10 @@ func New(agg *aggregator.Aggregator, announcementSvc *announcements.Service, equ
 		buildSvc:            buildSvc,
 		radioSvc:            radioSvc,
 		batterySvc:          batterySvc,
+		flightSvc:           flightSvc,
+		orderSvc:            orderSvc,
+		exportSvc:           exportSvc,
+		importSvc:           importSvc,
 		authSvc:             authSvc,
 		oauthSvc:            oauthSvc,
 		authMiddleware:      authMiddleware,
@@ -104,6 +115,9 @@ func (s *Server) Start(addr string) error {
 
 	// Equipment and inventory routes
 	equipmentAPI := NewEquipmentAPI(s.equipmentSvc, s.inventorySvc, s.batterySvc, s.authMiddleware, s.logger)
+	if s.inventorySvc != nil && s.gearCatalogStore != nil {
+		equipmentAPI.SetImporter(inventory.NewImporter(s.inventorySvc, s.gearCatalogStore, s.logger))
+	}
 	equipmentAPI.RegisterRoutes(mux, s.corsMiddleware)
 
 	// Aircraft routes
@@ -130,9 +144,33 @@ func (s *Server) Start(addr string) error {
 		batteryAPI.RegisterRoutes(mux, s.corsMiddleware)
 	}
 
+	// Flight log routes
+	if s.flightSvc != nil && s.authMiddleware != nil {
+		flightAPI := NewFlightAPI(s.flightSvc, s.authMiddleware, s.logger)
+		flightAPI.RegisterRoutes(mux, s.corsMiddleware)
+	}
+
+	// Order tracking routes
+	if s.orderSvc != nil && s.authMiddleware != nil {
+		orderAPI := NewOrderAPI(s.orderSvc, s.authMiddleware, s.logger)
+		orderAPI.RegisterRoutes(mux, s.corsMiddleware)
+	}
+
 	// Profile routes (user profile management)
 	if s.userStore != nil && s.authMiddleware != nil && s.imageSvc != nil {
 		profileAPI := NewProfileAPI(s.userStore, s.imageSvc, s.authMiddleware, s.logger)
+		if s.authSvc != nil {
+			profileAPI.SetPersonalAccessTokens(s.authSvc)
+		}
+		if s.flightSvc != nil {
+			profileAPI.SetFlightStats(s.flightSvc)
+		}
+		if s.exportSvc != nil {
+			profileAPI.SetDataExports(s.exportSvc)
+		}
+		if s.importSvc != nil {
+			profileAPI.SetDataImports(s.importSvc)
+		}
 		profileAPI.RegisterRoutes(mux, s.corsMiddleware)
 	}
 
@@ -144,7 +182,7 @@ func (s *Server) Start(addr string) error {
 
 	// Pilot routes (social/pilot directory)
 	if s.userStore != nil && s.aircraftStore != nil && s.authMiddleware != nil {
-		pilotAPI := NewPilotAPI(s.userStore, s.aircraftStore, s.fcConfigStore, s.buildSvc, s.authMiddleware, s.logger)
+		pilotAPI := NewPilotAPI(s.userStore, s.aircraftStore, s.fcConfigStore, s.buildSvc, s.imageSvc, s.authMiddleware, s.logger)
 		pilotAPI.RegisterRoutes(mux, s.corsMiddleware)
 	}
 
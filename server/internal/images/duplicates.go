@@ -0,0 +1,129 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ErrDuplicatesUnsupported is returned when the storage backend does not index perceptual hashes.
+var ErrDuplicatesUnsupported = errors.New("near-duplicate detection is not supported by this storage backend")
+
+const (
+	maxDuplicateDistance     = 16
+	uploadDuplicateWarnLimit = 5
+)
+
+// DuplicateStorage is implemented by storage backends that index the
+// perceptual hash of every asset. Only images shown on a gear catalog item or
+// a build gallery are compared.
+type DuplicateStorage interface {
+	ListDuplicates(ctx context.Context, params models.ImageDuplicateListParams) ([]models.ImageDuplicatePair, error)
+	// FindSimilar lists images within maxDistance of imageID that are shown on
+	// an entity of entityType other than excludeEntityID, closest first.
+	FindSimilar(ctx context.Context, imageID string, entityType models.ImageEntityType, excludeEntityID string, maxDistance, limit int) ([]models.ImageDuplicateMatch, error)
+	// ListUnhashed returns IDs of stored images without a hash that haven't
+	// been marked unhashable, ordered by ID and starting after afterID.
+	ListUnhashed(ctx context.Context, afterID string, limit int) ([]string, error)
+	SetPerceptualHash(ctx context.Context, imageID string, hash uint64) error
+	// MarkUnhashable records that an image could not be decoded, so the
+	// backfill doesn't load it again.
+	MarkUnhashable(ctx context.Context, imageID string) error
+}
+
+func (s *Service) duplicates() DuplicateStorage {
+	duplicates, _ := s.storage.(DuplicateStorage)
+	return duplicates
+}
+
+// ListDuplicates returns pairs of near-identical images on different gear
+// catalog items or builds, closest first. Builds by the same pilot are not
+// paired with each other. A MaxDistance of 0 matches identical hashes only.
+func (s *Service) ListDuplicates(ctx context.Context, params models.ImageDuplicateListParams) (*models.ImageDuplicateListResponse, error) {
+	duplicates := s.duplicates()
+	if duplicates == nil {
+		return nil, ErrDuplicatesUnsupported
+	}
+
+	if params.MaxDistance < 0 {
+		params.MaxDistance = 0
+	}
+	if params.MaxDistance > maxDuplicateDistance {
+		params.MaxDistance = maxDuplicateDistance
+	}
+	if params.Limit <= 0 {
+		params.Limit = 50
+	}
+	if params.Limit > 200 {
+		params.Limit = 200
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	pairs, err := duplicates.ListDuplicates(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ImageDuplicateListResponse{Pairs: pairs, MaxDistance: params.MaxDistance}, nil
+}
+
+// FindUploadDuplicates lists images on other entities of the same type that
+// nearly match a freshly stored asset. Upload flows use it to warn, never to
+// block, so it returns nothing when the backend can't answer.
+func (s *Service) FindUploadDuplicates(ctx context.Context, asset *models.ImageAsset) ([]models.ImageDuplicateMatch, error) {
+	duplicates := s.duplicates()
+	if duplicates == nil || asset == nil || asset.PerceptualHash == nil {
+		return nil, nil
+	}
+	return duplicates.FindSimilar(ctx, asset.ID, asset.EntityType, asset.EntityID, DefaultDuplicateDistance, uploadDuplicateWarnLimit)
+}
+
+// BackfillPerceptualHashes hashes stored images that predate perceptual
+// hashing, batchSize at a time. Images that can't be decoded are marked
+// unhashable and skipped. Returns how many images were hashed.
+func (s *Service) BackfillPerceptualHashes(ctx context.Context, batchSize int) (int, error) {
+	duplicates := s.duplicates()
+	if duplicates == nil {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	hashed := 0
+	afterID := ""
+	for {
+		ids, err := duplicates.ListUnhashed(ctx, afterID, batchSize)
+		if err != nil {
+			return hashed, err
+		}
+		if len(ids) == 0 {
+			return hashed, nil
+		}
+
+		for _, id := range ids {
+			asset, err := s.storage.Load(ctx, id)
+			if err != nil {
+				return hashed, fmt.Errorf("load image %s: %w", id, err)
+			}
+			if asset == nil {
+				continue
+			}
+			hash, err := PerceptualHashBytes(asset.ImageBytes)
+			if err != nil {
+				if err := duplicates.MarkUnhashable(ctx, id); err != nil {
+					return hashed, err
+				}
+				continue
+			}
+			if err := duplicates.SetPerceptualHash(ctx, id, hash); err != nil {
+				return hashed, err
+			}
+			hashed++
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
@@ -0,0 +1,55 @@
+package images
+
+import (
+	"image"
+	"math/bits"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// DefaultDuplicateDistance is the largest Hamming distance between two
+// perceptual hashes that still counts as the same picture. Re-encodes,
+// resizes and light crops of a photo typically land within a few bits;
+// unrelated photos are around 32 bits apart.
+const DefaultDuplicateDistance = 6
+
+// PerceptualHash computes a 64-bit difference hash (dHash) of img: the image
+// is reduced to a 9x8 grayscale grid and each bit records whether a cell is
+// brighter than its right-hand neighbour. Unlike a byte hash it survives
+// re-encoding, resizing and metadata stripping.
+func PerceptualHash(img image.Image) uint64 {
+	grid := image.NewGray(image.Rect(0, 0, 9, 8))
+	xdraw.CatmullRom.Scale(grid, grid.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if grid.GrayAt(x, y).Y > grid.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// PerceptualHashBytes decodes JPEG or PNG bytes and returns their perceptual hash.
+func PerceptualHashBytes(data []byte) (uint64, error) {
+	img, format, err := decodeImage(data)
+	if err != nil {
+		return 0, err
+	}
+	if format != "jpeg" && format != "png" {
+		return 0, ErrUnsupportedImage
+	}
+	nrgba := toNRGBA(img)
+	if format == "jpeg" {
+		nrgba = applyOrientation(nrgba, jpegOrientation(data))
+	}
+	return PerceptualHash(nrgba), nil
+}
+
+// HashDistance is the Hamming distance between two perceptual hashes.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,105 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// patternImage draws a few blocks of different brightness so the hash has
+// structure in both directions.
+func patternImage(w, h int, invert bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8((x*255/w + (y*4/h)*60) % 256)
+			if (x*3/w+y*2/h)%2 == 1 {
+				v = 255 - v
+			}
+			if invert {
+				v = 255 - v
+			}
+			img.Set(x, y, color.NRGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHash_SurvivesResizeAndReencode(t *testing.T) {
+	original := patternImage(800, 600, false)
+
+	var jpegBuf bytes.Buffer
+	if err := jpeg.Encode(&jpegBuf, scaleToFit(original, 320), &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatal(err)
+	}
+	resized, err := PerceptualHashBytes(jpegBuf.Bytes())
+	if err != nil {
+		t.Fatalf("hash resized jpeg: %v", err)
+	}
+
+	if d := HashDistance(PerceptualHash(original), resized); d > DefaultDuplicateDistance {
+		t.Fatalf("resized re-encode distance = %d, want <= %d", d, DefaultDuplicateDistance)
+	}
+}
+
+func TestPerceptualHash_DifferentImagesAreFar(t *testing.T) {
+	a := PerceptualHash(patternImage(400, 300, false))
+	b := PerceptualHash(patternImage(400, 300, true))
+	if d := HashDistance(a, b); d <= DefaultDuplicateDistance {
+		t.Fatalf("different images distance = %d, want > %d", d, DefaultDuplicateDistance)
+	}
+}
+
+func TestPerceptualHashBytes_AppliesOrientation(t *testing.T) {
+	upright := testJPEG(t, 64, 48)
+	rotated := withEXIFOrientation(t, upright, 3)
+
+	uprightHash, err := PerceptualHashBytes(upright)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotatedHash, err := PerceptualHashBytes(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uprightHash == rotatedHash {
+		t.Fatalf("expected EXIF orientation to change the hash")
+	}
+
+	processed, err := ProcessImage(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processed.PerceptualHash != rotatedHash {
+		t.Fatalf("ProcessImage hash = %016x, want %016x", processed.PerceptualHash, rotatedHash)
+	}
+}
+
+func TestPerceptualHashBytes_Unsupported(t *testing.T) {
+	if _, err := PerceptualHashBytes([]byte("not an image")); err != ErrUnsupportedImage {
+		t.Fatalf("expected ErrUnsupportedImage, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, patternImage(16, 16, false)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PerceptualHashBytes(buf.Bytes()); err != nil {
+		t.Fatalf("png should hash: %v", err)
+	}
+}
+
+func TestHashDistance(t *testing.T) {
+	if d := HashDistance(0, 0); d != 0 {
+		t.Fatalf("identical distance = %d", d)
+	}
+	if d := HashDistance(0, ^uint64(0)); d != 64 {
+		t.Fatalf("inverse distance = %d", d)
+	}
+	if d := HashDistance(0b1011, 0b0001); d != 2 {
+		t.Fatalf("distance = %d, want 2", d)
+	}
+}
@@ -0,0 +1,45 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sync"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+var (
+	placeholderOnce sync.Once
+	placeholderPNG  []byte
+)
+
+// reviewPlaceholder is served in place of an image that is awaiting review.
+// The real asset is kept on the stream so callers can still check ownership
+// and status.
+func reviewPlaceholder(asset *models.ImageAsset) *ImageStream {
+	placeholderOnce.Do(func() {
+		// A flat 4:3 tile at thumbnail size; clients scale it like any other image
+		img := image.NewRGBA(image.Rect(0, 0, 320, 240))
+		fill := color.RGBA{R: 0xE5, G: 0xE7, B: 0xEB, A: 0xFF}
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				img.SetRGBA(x, y, fill)
+			}
+		}
+		var buf bytes.Buffer
+		_ = png.Encode(&buf, img)
+		placeholderPNG = buf.Bytes()
+	})
+
+	return &ImageStream{
+		Asset:       asset,
+		Body:        io.NopCloser(bytes.NewReader(placeholderPNG)),
+		Size:        int64(len(placeholderPNG)),
+		ContentType: "image/png",
+		ETag:        `"pending-review"`,
+		Placeholder: true,
+	}
+}
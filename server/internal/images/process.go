@@ -0,0 +1,280 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	originalJPEGQuality   = 90
+	derivativeJPEGQuality = 82
+
+	// MaxImagePixels caps the width times height of an image the pipeline
+	// decodes. A decoded image takes four bytes per pixel, so a small but
+	// highly compressed file could otherwise claim gigabytes of memory.
+	MaxImagePixels = 40_000_000
+)
+
+// ErrUnsupportedImage is returned when image bytes are not a format the pipeline can decode.
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// ErrImageTooLarge is returned when an image's dimensions exceed MaxImagePixels.
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// ProcessedImage is an upload after metadata stripping, with its derivatives.
+type ProcessedImage struct {
+	// Original is the upload re-encoded in its own format at full resolution,
+	// with EXIF/XMP/text metadata dropped and EXIF orientation applied.
+	Original    []byte
+	ContentType string
+	Derivatives []models.ImageDerivative
+	// PerceptualHash is the dHash of the upright image, for near-duplicate lookups.
+	PerceptualHash uint64
+}
+
+// ProcessImage strips metadata from JPEG or PNG bytes, normalizes orientation
+// and renders every derivative size.
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	src, format, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img := toNRGBA(src)
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	// Re-encoding drops every metadata segment (EXIF GPS, XMP, comments, PNG text chunks)
+	processed := &ProcessedImage{PerceptualHash: PerceptualHash(img)}
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: originalJPEGQuality})
+		processed.ContentType = "image/jpeg"
+	case "png":
+		err = png.Encode(&buf, img)
+		processed.ContentType = "image/png"
+	default:
+		return nil, ErrUnsupportedImage
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+	processed.Original = buf.Bytes()
+
+	for _, size := range models.ImageSizes {
+		derivative, err := renderDerivative(img, size)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", size, err)
+		}
+		processed.Derivatives = append(processed.Derivatives, *derivative)
+	}
+	return processed, nil
+}
+
+// checkDimensions reads only the image header and returns ErrImageTooLarge
+// when the image has more than MaxImagePixels pixels.
+func checkDimensions(data []byte) error {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return ErrUnsupportedImage
+	}
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// decodeImage decodes image bytes once their header shows they are within
+// MaxImagePixels.
+func decodeImage(data []byte) (image.Image, string, error) {
+	if err := checkDimensions(data); err != nil {
+		return nil, "", err
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", ErrUnsupportedImage
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image: %w", err)
+	}
+	return img, format, nil
+}
+
+// renderDerivative scales img to fit size and encodes it. WebP is used for
+// images with transparency and whenever it comes out smaller than JPEG; the
+// pure-Go WebP encoder is lossless, so it wins for graphics and screenshots
+// while photos usually stay JPEG.
+func renderDerivative(img *image.NRGBA, size models.ImageSize) (*models.ImageDerivative, error) {
+	scaled := scaleToFit(img, size.MaxEdge())
+	bounds := scaled.Bounds()
+
+	var webpBuf bytes.Buffer
+	if err := nativewebp.Encode(&webpBuf, scaled, nil); err != nil {
+		return nil, err
+	}
+	derivative := &models.ImageDerivative{
+		Size:        size,
+		ContentType: "image/webp",
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		ImageBytes:  webpBuf.Bytes(),
+	}
+	if !scaled.Opaque() {
+		return derivative, nil
+	}
+
+	var jpegBuf bytes.Buffer
+	if err := jpeg.Encode(&jpegBuf, scaled, &jpeg.Options{Quality: derivativeJPEGQuality}); err != nil {
+		return nil, err
+	}
+	if jpegBuf.Len() < webpBuf.Len() {
+		derivative.ContentType = "image/jpeg"
+		derivative.ImageBytes = jpegBuf.Bytes()
+	}
+	return derivative, nil
+}
+
+// scaleToFit shrinks img so its longest edge is at most maxEdge.
+func scaleToFit(img *image.NRGBA, maxEdge int) *image.NRGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if maxEdge <= 0 || (w <= maxEdge && h <= maxEdge) {
+		return img
+	}
+
+	dw, dh := maxEdge, h*maxEdge/w
+	if h > w {
+		dw, dh = w*maxEdge/h, maxEdge
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, max(dw, 1), max(dh, 1)))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// applyOrientation rotates/flips img so it displays upright without the EXIF
+// orientation tag. Values follow the EXIF spec (1 = already upright).
+func applyOrientation(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // rotate 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 90 counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			si := img.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], img.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag from a JPEG's APP1 segment.
+// Returns 1 (upright) when the tag is missing or unreadable.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xD9 || marker == 0xDA { // end of image / start of scan
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation finds tag 0x0112 in IFD0 of a TIFF-structured EXIF block.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value < 1 || value > 8 {
+				return 1
+			}
+			return value
+		}
+	}
+	return 1
+}
@@ -0,0 +1,206 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// withEXIFOrientation inserts an APP1 EXIF segment with an orientation tag
+// (and a GPS-looking payload) right after the JPEG SOI marker.
+func withEXIFOrientation(t *testing.T, jpegBytes []byte, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))      // one IFD0 entry
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x0112)) // Orientation
+	binary.Write(&tiff, binary.LittleEndian, uint16(3))      // SHORT
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0)) // no next IFD
+	tiff.WriteString("GPS 51.5074N 0.1278W")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegBytes[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegBytes[2:]...)
+}
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 80, 255})
+		}
+	}
+	// Mark the top-left corner so rotation can be checked
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessImage_StripsEXIFAndAppliesOrientation(t *testing.T) {
+	// Orientation 6: the camera was rotated, the image must turn 90 degrees clockwise
+	data := withEXIFOrientation(t, testJPEG(t, 1200, 600), 6)
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("jpegOrientation() = %d, want 6", got)
+	}
+
+	processed, err := ProcessImage(data)
+	if err != nil {
+		t.Fatalf("ProcessImage() error = %v", err)
+	}
+	if processed.ContentType != "image/jpeg" {
+		t.Errorf("ContentType = %s, want image/jpeg", processed.ContentType)
+	}
+	if bytes.Contains(processed.Original, []byte("Exif")) || bytes.Contains(processed.Original, []byte("GPS")) {
+		t.Error("processed original still contains EXIF metadata")
+	}
+
+	original, err := jpeg.Decode(bytes.NewReader(processed.Original))
+	if err != nil {
+		t.Fatalf("decode processed original: %v", err)
+	}
+	if b := original.Bounds(); b.Dx() != 600 || b.Dy() != 1200 {
+		t.Fatalf("processed original is %dx%d, want 600x1200", b.Dx(), b.Dy())
+	}
+	// The red top-left corner moves to the top-right after a clockwise turn
+	if r, g, _, _ := original.At(595, 4).RGBA(); r>>8 < 200 || g>>8 > 60 {
+		t.Errorf("top-right pixel = (%d, %d), want red", r>>8, g>>8)
+	}
+	if jpegOrientation(processed.Original) != 1 {
+		t.Error("processed original should have no orientation tag")
+	}
+
+	if len(processed.Derivatives) != len(models.ImageSizes) {
+		t.Fatalf("got %d derivatives, want %d", len(processed.Derivatives), len(models.ImageSizes))
+	}
+	wantHeights := map[models.ImageSize]int{
+		models.ImageSizeThumb: 320,
+		models.ImageSizeCard:  800,
+		models.ImageSizeFull:  1200, // never upscaled
+	}
+	for _, d := range processed.Derivatives {
+		if d.Height != wantHeights[d.Size] || d.Width != d.Height/2 {
+			t.Errorf("%s derivative is %dx%d, want height %d", d.Size, d.Width, d.Height, wantHeights[d.Size])
+		}
+		if bytes.Contains(d.ImageBytes, []byte("Exif")) {
+			t.Errorf("%s derivative contains EXIF metadata", d.Size)
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(d.ImageBytes))
+		if d.ContentType == "image/jpeg" && (err != nil || format != "jpeg" || cfg.Height != d.Height) {
+			t.Errorf("%s derivative does not decode as a %dpx jpeg: %s, %v", d.Size, d.Height, format, err)
+		}
+	}
+}
+
+func TestProcessImage_TransparentPNGUsesWebP(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 400; x++ {
+			img.Set(x, y, color.NRGBA{0, 120, 255, uint8(x % 256)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	processed, err := ProcessImage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ProcessImage() error = %v", err)
+	}
+	if processed.ContentType != "image/png" {
+		t.Errorf("ContentType = %s, want image/png", processed.ContentType)
+	}
+	for _, d := range processed.Derivatives {
+		if d.ContentType != "image/webp" || !bytes.HasPrefix(d.ImageBytes, []byte("RIFF")) {
+			t.Errorf("%s derivative = %s, want webp to keep transparency", d.Size, d.ContentType)
+		}
+	}
+}
+
+func TestProcessImage_Unsupported(t *testing.T) {
+	if _, err := ProcessImage([]byte("not an image")); !errors.Is(err, ErrUnsupportedImage) {
+		t.Fatalf("ProcessImage() error = %v, want ErrUnsupportedImage", err)
+	}
+}
+
+// oversizedPNG returns a tiny PNG whose header claims width x height pixels,
+// the shape of a decompression bomb.
+func oversizedPNG(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// IHDR data follows the 8-byte signature, chunk length and type
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestProcessImage_RejectsOversizedDimensions(t *testing.T) {
+	if _, err := ProcessImage(oversizedPNG(t, 8000, 8000)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("ProcessImage() error = %v, want ErrImageTooLarge", err)
+	}
+	if _, err := PerceptualHashBytes(oversizedPNG(t, 100000, 100000)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("PerceptualHashBytes() error = %v, want ErrImageTooLarge", err)
+	}
+	if err := checkDimensions(oversizedPNG(t, 6000, 4000)); err != nil {
+		t.Fatalf("expected a 24 megapixel header to pass, got %v", err)
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 2x1 image: red, blue
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	src.SetNRGBA(0, 0, red)
+	src.SetNRGBA(1, 0, blue)
+
+	tests := []struct {
+		orientation int
+		w, h        int
+		first       color.NRGBA // pixel at (0,0)
+	}{
+		{1, 2, 1, red},
+		{2, 2, 1, blue},
+		{3, 2, 1, blue},
+		{6, 1, 2, red},
+		{8, 1, 2, blue},
+	}
+	for _, tt := range tests {
+		got := applyOrientation(src, tt.orientation)
+		if got.Bounds().Dx() != tt.w || got.Bounds().Dy() != tt.h || got.NRGBAAt(0, 0) != tt.first {
+			t.Errorf("orientation %d: %dx%d first=%v", tt.orientation, got.Bounds().Dx(), got.Bounds().Dy(), got.NRGBAAt(0, 0))
+		}
+	}
+}
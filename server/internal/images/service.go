@@ -1,10 +1,18 @@
 package images
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/johnrirwin/flyingforge/internal/models"
 )
@@ -14,8 +22,19 @@ var (
 	ErrPendingUploadNotFound = errors.New("approved upload not found")
 	// ErrUploadNotApproved is returned when trying to persist a non-approved upload token.
 	ErrUploadNotApproved = errors.New("upload is not approved")
+	// ErrReviewUnsupported is returned when the storage backend keeps no review queue.
+	ErrReviewUnsupported = errors.New("image review is not supported by this storage backend")
+	// ErrNotPendingReview is returned when reviewing an image that was already decided.
+	ErrNotPendingReview = errors.New("image is not awaiting review")
+	// ErrReviewReasonRequired is returned when rejecting an image without a reason.
+	ErrReviewReasonRequired = errors.New("a reason is required to reject an image")
+	// ErrReviewReasonTooLong is returned when a review reason exceeds maxReviewReasonLength.
+	ErrReviewReasonTooLong = fmt.Errorf("reason must be %d characters or fewer", maxReviewReasonLength)
 )
 
+// maxReviewReasonLength caps the reason a reviewer can record, in characters.
+const maxReviewReasonLength = 500
+
 // Moderator defines the moderation abstraction used by image flows.
 type Moderator interface {
 	ModerateImageBytes(ctx context.Context, imageBytes []byte) (*models.ModerationDecision, error)
@@ -29,6 +48,10 @@ type SaveRequest struct {
 	ImageBytes              []byte
 	ModerationLabels        []models.ModerationLabel
 	ModerationMaxConfidence float64
+	Derivatives             []models.ImageDerivative
+	Status                  models.ImageModerationStatus // APPROVED when empty
+	QueuedReason            string                       // Why a PENDING_REVIEW image was held back
+	PerceptualHash          *uint64                      // nil when the image could not be decoded
 }
 
 // Storage abstracts image persistence so DB storage can later be swapped for S3.
@@ -38,7 +61,46 @@ type Storage interface {
 	Delete(ctx context.Context, imageID string) error
 }
 
-// PendingUpload is an approved but not-yet-persisted image token.
+// ImageStream is an open image body. Size is -1 when unknown. ContentType is
+// empty when it has to be sniffed from the body.
+type ImageStream struct {
+	Asset       *models.ImageAsset
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+	ETag        string
+	Placeholder bool // Stand-in for an image awaiting review; must not be cached
+}
+
+// StreamingStorage is implemented by storage backends that can stream image
+// bytes without buffering them and hand out short-lived direct download URLs.
+type StreamingStorage interface {
+	Open(ctx context.Context, imageID string) (*ImageStream, error)
+	PresignURL(ctx context.Context, imageID string, ttl time.Duration) (string, error)
+}
+
+// DerivativeStorage is implemented by storage backends that keep resized variants.
+// OpenDerivative returns nil, nil when the image or the requested size does not exist.
+type DerivativeStorage interface {
+	OpenDerivative(ctx context.Context, imageID string, size models.ImageSize) (*ImageStream, error)
+}
+
+// ReviewStorage is implemented by storage backends that can keep PENDING_REVIEW
+// images for a content admin to decide on. Get/Approve/Reject return nil, nil
+// when the image does not exist; Approve/Reject return ErrNotPendingReview
+// when it was already decided.
+type ReviewStorage interface {
+	ListReviews(ctx context.Context, params models.ImageReviewListParams) ([]models.ImageReview, int, error)
+	GetReview(ctx context.Context, imageID string) (*models.ImageReview, error)
+	ApproveReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error)
+	RejectReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error)
+}
+
+// ErrDirectURLUnsupported is returned when the storage backend cannot issue download URLs.
+var ErrDirectURLUnsupported = errors.New("direct image URLs are not supported by this storage backend")
+
+// PendingUpload is a moderated but not-yet-persisted image token. Its decision
+// is APPROVED, or PENDING_REVIEW when the image is bound for the review queue.
 type PendingUpload struct {
 	ID          string
 	OwnerUserID string
@@ -73,10 +135,12 @@ func NewService(moderator Moderator, storage Storage, pending PendingStore, time
 	}
 }
 
-// ModerateUpload runs synchronous moderation and, if approved, stores a pending token.
+// ModerateUpload runs synchronous moderation and, if the image can be stored,
+// keeps a pending token. Images held back for review get a token too; they are
+// queued for a content admin when the token is saved.
 func (s *Service) ModerateUpload(ctx context.Context, ownerUserID string, entityType models.ImageEntityType, imageBytes []byte) (*models.ModerationDecision, string, error) {
 	decision := s.moderate(ctx, imageBytes)
-	if decision.Status != models.ImageModerationApproved {
+	if !s.storable(decision) {
 		return decision, "", nil
 	}
 	if s.pending == nil {
@@ -99,27 +163,37 @@ func (s *Service) ModerateUpload(ctx context.Context, ownerUserID string, entity
 		}, "", nil
 	}
 
-	return decision, uploadID, nil
+	return queuedDecision(decision), uploadID, nil
 }
 
 // ModerateAndPersist runs moderation and immediately persists approved images.
+// Images held back for review are persisted as PENDING_REVIEW when the storage
+// backend keeps a review queue. The asset is nil when nothing was stored.
 func (s *Service) ModerateAndPersist(ctx context.Context, req SaveRequest) (*models.ModerationDecision, *models.ImageAsset, error) {
 	decision := s.moderate(ctx, req.ImageBytes)
-	if decision.Status != models.ImageModerationApproved {
+	if !s.storable(decision) {
 		return decision, nil, nil
 	}
 
 	req.ModerationLabels = decision.Labels
 	req.ModerationMaxConfidence = decision.MaxConfidence
+	req.Status = decision.Status
+	if decision.Status == models.ImageModerationPendingReview {
+		req.QueuedReason = decision.Reason
+	}
+	if err := prepareSave(&req); err != nil {
+		return decision, nil, err
+	}
 	asset, err := s.storage.Save(ctx, req)
 	if err != nil {
 		return decision, nil, err
 	}
 
-	return decision, asset, nil
+	return queuedDecision(decision), asset, nil
 }
 
-// PersistApprovedUpload stores a previously approved pending upload.
+// PersistApprovedUpload stores a previously moderated pending upload. Uploads
+// held back for review are stored as PENDING_REVIEW.
 func (s *Service) PersistApprovedUpload(ctx context.Context, ownerUserID, uploadID string, entityType models.ImageEntityType, entityID string) (*models.ImageAsset, error) {
 	if s.pending == nil {
 		return nil, ErrPendingUploadNotFound
@@ -129,21 +203,29 @@ func (s *Service) PersistApprovedUpload(ctx context.Context, ownerUserID, upload
 	if !ok {
 		return nil, ErrPendingUploadNotFound
 	}
-	if pendingUpload.Decision.Status != models.ImageModerationApproved {
+	if !s.storable(&pendingUpload.Decision) {
 		return nil, ErrUploadNotApproved
 	}
 	if pendingUpload.EntityType != entityType {
 		return nil, ErrUploadNotApproved
 	}
 
-	asset, err := s.storage.Save(ctx, SaveRequest{
+	req := SaveRequest{
 		OwnerUserID:             ownerUserID,
 		EntityType:              entityType,
 		EntityID:                entityID,
 		ImageBytes:              pendingUpload.ImageBytes,
 		ModerationLabels:        pendingUpload.Decision.Labels,
 		ModerationMaxConfidence: pendingUpload.Decision.MaxConfidence,
-	})
+		Status:                  pendingUpload.Decision.Status,
+	}
+	if req.Status == models.ImageModerationPendingReview {
+		req.QueuedReason = pendingUpload.Decision.Reason
+	}
+	if err := prepareSave(&req); err != nil {
+		return nil, err
+	}
+	asset, err := s.storage.Save(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -152,17 +234,273 @@ func (s *Service) PersistApprovedUpload(ctx context.Context, ownerUserID, upload
 	return asset, nil
 }
 
+// AssetDecision describes a stored asset the way upload flows report it:
+// approved, or queued for review.
+func AssetDecision(asset *models.ImageAsset) *models.ModerationDecision {
+	if asset != nil && asset.Status == models.ImageModerationPendingReview {
+		return queuedDecision(&models.ModerationDecision{Status: models.ImageModerationPendingReview})
+	}
+	return &models.ModerationDecision{
+		Status: models.ImageModerationApproved,
+		Reason: "Approved",
+	}
+}
+
+// storable reports whether an image with this decision is kept: approved
+// images always are, PENDING_REVIEW ones only when there is a review queue.
+func (s *Service) storable(decision *models.ModerationDecision) bool {
+	switch decision.Status {
+	case models.ImageModerationApproved:
+		return true
+	case models.ImageModerationPendingReview:
+		return s.reviews() != nil
+	default:
+		return false
+	}
+}
+
+// queuedDecision is the decision returned to the uploader for a stored image.
+// Labels and the automated reason stay with the asset for reviewers.
+func queuedDecision(decision *models.ModerationDecision) *models.ModerationDecision {
+	if decision.Status != models.ImageModerationPendingReview {
+		return decision
+	}
+	return &models.ModerationDecision{
+		Status: models.ImageModerationPendingReview,
+		Reason: "Awaiting review",
+		Queued: true,
+	}
+}
+
+func (s *Service) reviews() ReviewStorage {
+	reviews, _ := s.storage.(ReviewStorage)
+	return reviews
+}
+
+// prepareSave strips metadata from the image and renders its derivatives.
+// Bytes in a format the pipeline can't decode are stored unchanged.
+func prepareSave(req *SaveRequest) error {
+	processed, err := ProcessImage(req.ImageBytes)
+	if errors.Is(err, ErrUnsupportedImage) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	req.ImageBytes = processed.Original
+	req.Derivatives = processed.Derivatives
+	req.PerceptualHash = &processed.PerceptualHash
+	return nil
+}
+
 // Load proxies image loading to the configured storage backend.
 func (s *Service) Load(ctx context.Context, imageID string) (*models.ImageAsset, error) {
 	return s.storage.Load(ctx, imageID)
 }
 
+// Open streams an image. Backends without streaming support are read fully.
+// Returns nil, nil when the image does not exist.
+func (s *Service) Open(ctx context.Context, imageID string) (*ImageStream, error) {
+	if streaming, ok := s.storage.(StreamingStorage); ok {
+		return streaming.Open(ctx, imageID)
+	}
+
+	asset, err := s.storage.Load(ctx, imageID)
+	if err != nil || asset == nil {
+		return nil, err
+	}
+	return &ImageStream{
+		Asset: asset,
+		Body:  io.NopCloser(bytes.NewReader(asset.ImageBytes)),
+		Size:  int64(len(asset.ImageBytes)),
+	}, nil
+}
+
+// OpenVariant streams a derivative size of an image, or the original for
+// models.ImageSizeOriginal. Images stored before derivatives existed fall back
+// to the original. Images awaiting review are replaced by a placeholder.
+// Returns nil, nil when the image does not exist or was rejected.
+func (s *Service) OpenVariant(ctx context.Context, imageID string, size models.ImageSize) (*ImageStream, error) {
+	stream, err := s.openVariant(ctx, imageID, size)
+	if err != nil || stream == nil || stream.Asset == nil {
+		return stream, err
+	}
+
+	switch stream.Asset.Status {
+	case models.ImageModerationPendingReview:
+		stream.Body.Close()
+		return reviewPlaceholder(stream.Asset), nil
+	case models.ImageModerationRejected:
+		stream.Body.Close()
+		return nil, nil
+	}
+	return stream, nil
+}
+
+// OpenForReview streams an image whatever its moderation status, for content
+// admins deciding on it. Returns nil, nil when the image does not exist.
+func (s *Service) OpenForReview(ctx context.Context, imageID string, size models.ImageSize) (*ImageStream, error) {
+	return s.openVariant(ctx, imageID, size)
+}
+
+func (s *Service) openVariant(ctx context.Context, imageID string, size models.ImageSize) (*ImageStream, error) {
+	if size != models.ImageSizeOriginal {
+		if derivatives, ok := s.storage.(DerivativeStorage); ok {
+			stream, err := derivatives.OpenDerivative(ctx, imageID, size)
+			if err != nil || stream != nil {
+				return stream, err
+			}
+		}
+	}
+
+	stream, err := s.Open(ctx, imageID)
+	if err != nil || stream == nil {
+		return nil, err
+	}
+	if stream.ETag == "" {
+		stream.ETag = fmt.Sprintf(`"%s"`, imageID)
+	}
+	sniffContentType(stream)
+	return stream, nil
+}
+
+// OpenRef streams an entity's image. Inline legacy bytes are served as-is
+// whatever size is requested. Returns nil, nil when ref is nil.
+func (s *Service) OpenRef(ctx context.Context, ref *models.ImageRef, size models.ImageSize) (*ImageStream, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	if ref.AssetID != "" {
+		return s.OpenVariant(ctx, ref.AssetID, size)
+	}
+	if len(ref.Data) == 0 {
+		return nil, nil
+	}
+
+	sum := sha256.Sum256(ref.Data)
+	stream := &ImageStream{
+		Body:        io.NopCloser(bytes.NewReader(ref.Data)),
+		Size:        int64(len(ref.Data)),
+		ContentType: ref.ContentType,
+		ETag:        fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16])),
+	}
+	sniffContentType(stream)
+	return stream, nil
+}
+
+// sniffContentType fills in a missing content type from the head of the body
+// without consuming it.
+func sniffContentType(stream *ImageStream) {
+	if stream.ContentType != "" {
+		return
+	}
+	reader := bufio.NewReaderSize(stream.Body, 512)
+	head, _ := reader.Peek(512)
+	stream.ContentType = http.DetectContentType(head)
+	stream.Body = struct {
+		io.Reader
+		io.Closer
+	}{reader, stream.Body}
+}
+
+// DirectURL returns a short-lived URL that serves the image straight from object storage.
+func (s *Service) DirectURL(ctx context.Context, imageID string, ttl time.Duration) (string, error) {
+	streaming, ok := s.storage.(StreamingStorage)
+	if !ok {
+		return "", ErrDirectURLUnsupported
+	}
+	return streaming.PresignURL(ctx, imageID, ttl)
+}
+
 // Delete proxies image deletion to the configured storage backend.
 func (s *Service) Delete(ctx context.Context, imageID string) error {
 	return s.storage.Delete(ctx, imageID)
 }
 
+// ListReviews returns a page of the review queue, PENDING_REVIEW by default.
+// Other statuses list images a reviewer already decided on.
+func (s *Service) ListReviews(ctx context.Context, params models.ImageReviewListParams) (*models.ImageReviewListResponse, error) {
+	reviews := s.reviews()
+	if reviews == nil {
+		return nil, ErrReviewUnsupported
+	}
+
+	if params.Status == "" {
+		params.Status = models.ImageModerationPendingReview
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	items, total, err := reviews.ListReviews(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ImageReviewListResponse{Images: items, TotalCount: total}, nil
+}
+
+// GetReview returns one image in the review queue. Returns nil, nil when it does not exist.
+func (s *Service) GetReview(ctx context.Context, imageID string) (*models.ImageReview, error) {
+	reviews := s.reviews()
+	if reviews == nil {
+		return nil, ErrReviewUnsupported
+	}
+	return reviews.GetReview(ctx, strings.TrimSpace(imageID))
+}
+
+// ApproveReview makes a PENDING_REVIEW image visible. The reason is optional.
+func (s *Service) ApproveReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error) {
+	reviews := s.reviews()
+	if reviews == nil {
+		return nil, ErrReviewUnsupported
+	}
+	reason, err := normalizeReviewReason(reason)
+	if err != nil {
+		return nil, err
+	}
+	return reviews.ApproveReview(ctx, strings.TrimSpace(imageID), reviewerUserID, reason)
+}
+
+// RejectReview rejects a PENDING_REVIEW image. The storage backend detaches it
+// from its entity and drops its bytes; the review record is kept.
+func (s *Service) RejectReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error) {
+	reviews := s.reviews()
+	if reviews == nil {
+		return nil, ErrReviewUnsupported
+	}
+	reason, err := normalizeReviewReason(reason)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, ErrReviewReasonRequired
+	}
+	return reviews.RejectReview(ctx, strings.TrimSpace(imageID), reviewerUserID, reason)
+}
+
+func normalizeReviewReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxReviewReasonLength {
+		return "", ErrReviewReasonTooLong
+	}
+	return reason, nil
+}
+
 func (s *Service) moderate(ctx context.Context, imageBytes []byte) *models.ModerationDecision {
+	// Turned away before the moderator or the processing pipeline decodes it
+	if errors.Is(checkDimensions(imageBytes), ErrImageTooLarge) {
+		return &models.ModerationDecision{
+			Status: models.ImageModerationRejected,
+			Reason: fmt.Sprintf("Image is too large; the limit is %d megapixels", MaxImagePixels/1_000_000),
+		}
+	}
+
 	timeout := s.timeout
 	if timeout <= 0 {
 		timeout = 5 * time.Second
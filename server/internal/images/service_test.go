@@ -1,12 +1,15 @@
 package images
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/moderation"
 )
 
 type fakeModerator struct {
@@ -24,7 +27,8 @@ func (f *fakeModerator) ModerateImageBytes(ctx context.Context, imageBytes []byt
 }
 
 type fakeStorage struct {
-	saved []*models.ImageAsset
+	saved    []*models.ImageAsset
+	requests []SaveRequest
 }
 
 type failingPendingStore struct{}
@@ -46,13 +50,18 @@ func (f *failingPendingStore) Delete(uploadID string) {
 
 func (f *fakeStorage) Save(ctx context.Context, req SaveRequest) (*models.ImageAsset, error) {
 	_ = ctx
+	f.requests = append(f.requests, req)
+	status := req.Status
+	if status == "" {
+		status = models.ImageModerationApproved
+	}
 	asset := &models.ImageAsset{
 		ID:          "asset-1",
 		OwnerUserID: req.OwnerUserID,
 		EntityType:  req.EntityType,
 		EntityID:    req.EntityID,
 		ImageBytes:  req.ImageBytes,
-		Status:      models.ImageModerationApproved,
+		Status:      status,
 	}
 	f.saved = append(f.saved, asset)
 	return asset, nil
@@ -74,6 +83,104 @@ func (f *fakeStorage) Delete(ctx context.Context, imageID string) error {
 	return nil
 }
 
+// fakeReviewStorage is a fakeStorage that keeps a review queue.
+type fakeReviewStorage struct {
+	fakeStorage
+	rejected []string
+}
+
+func (f *fakeReviewStorage) ListReviews(ctx context.Context, params models.ImageReviewListParams) ([]models.ImageReview, int, error) {
+	_ = ctx
+	reviews := []models.ImageReview{}
+	for _, asset := range f.saved {
+		if asset.Status == params.Status {
+			reviews = append(reviews, models.ImageReview{ImageID: asset.ID, Status: asset.Status})
+		}
+	}
+	return reviews, len(reviews), nil
+}
+
+func (f *fakeReviewStorage) GetReview(ctx context.Context, imageID string) (*models.ImageReview, error) {
+	asset, _ := f.Load(ctx, imageID)
+	if asset == nil {
+		return nil, nil
+	}
+	return &models.ImageReview{ImageID: asset.ID, Status: asset.Status}, nil
+}
+
+func (f *fakeReviewStorage) ApproveReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error) {
+	return f.decide(ctx, imageID, models.ImageModerationApproved, reason)
+}
+
+func (f *fakeReviewStorage) RejectReview(ctx context.Context, imageID string, reviewerUserID string, reason string) (*models.ImageReview, error) {
+	f.rejected = append(f.rejected, imageID)
+	return f.decide(ctx, imageID, models.ImageModerationRejected, reason)
+}
+
+func (f *fakeReviewStorage) decide(ctx context.Context, imageID string, status models.ImageModerationStatus, reason string) (*models.ImageReview, error) {
+	asset, _ := f.Load(ctx, imageID)
+	if asset == nil {
+		return nil, nil
+	}
+	if asset.Status != models.ImageModerationPendingReview {
+		return nil, ErrNotPendingReview
+	}
+	asset.Status = status
+	return &models.ImageReview{ImageID: asset.ID, Status: status, ReviewReason: reason}, nil
+}
+
+// fakeDuplicateStorage is a fakeStorage that indexes perceptual hashes.
+type fakeDuplicateStorage struct {
+	fakeStorage
+	hashes     map[string]uint64
+	unhashable map[string]bool
+}
+
+func (f *fakeDuplicateStorage) ListDuplicates(ctx context.Context, params models.ImageDuplicateListParams) ([]models.ImageDuplicatePair, error) {
+	return []models.ImageDuplicatePair{}, nil
+}
+
+func (f *fakeDuplicateStorage) FindSimilar(ctx context.Context, imageID string, entityType models.ImageEntityType, excludeEntityID string, maxDistance, limit int) ([]models.ImageDuplicateMatch, error) {
+	target, ok := f.hashes[imageID]
+	if !ok {
+		return nil, nil
+	}
+	matches := []models.ImageDuplicateMatch{}
+	for _, asset := range f.saved {
+		hash, ok := f.hashes[asset.ID]
+		if !ok || asset.ID == imageID || asset.EntityType != entityType || asset.EntityID == excludeEntityID {
+			continue
+		}
+		if d := HashDistance(target, hash); d <= maxDistance {
+			matches = append(matches, models.ImageDuplicateMatch{
+				ImageUsage: models.ImageUsage{ImageID: asset.ID, EntityType: asset.EntityType, EntityID: asset.EntityID},
+				Distance:   d,
+			})
+		}
+	}
+	return matches, nil
+}
+
+func (f *fakeDuplicateStorage) ListUnhashed(ctx context.Context, afterID string, limit int) ([]string, error) {
+	ids := []string{}
+	for _, asset := range f.saved {
+		if _, ok := f.hashes[asset.ID]; !ok && !f.unhashable[asset.ID] && asset.ID > afterID && len(ids) < limit {
+			ids = append(ids, asset.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeDuplicateStorage) SetPerceptualHash(ctx context.Context, imageID string, hash uint64) error {
+	f.hashes[imageID] = hash
+	return nil
+}
+
+func (f *fakeDuplicateStorage) MarkUnhashable(ctx context.Context, imageID string) error {
+	f.unhashable[imageID] = true
+	return nil
+}
+
 func TestServiceModerateUpload(t *testing.T) {
 	svc := NewService(
 		&fakeModerator{
@@ -99,6 +206,29 @@ func TestServiceModerateUpload(t *testing.T) {
 	}
 }
 
+func TestServiceModerateUploadRejectsOversizedDimensions(t *testing.T) {
+	storage := &fakeStorage{}
+	svc := NewService(
+		&fakeModerator{decision: &models.ModerationDecision{Status: models.ImageModerationApproved}},
+		storage,
+		NewInMemoryPendingStore(5*time.Minute),
+		5*time.Second,
+	)
+
+	decision, uploadID, err := svc.ModerateUpload(context.Background(), "user-1", models.ImageEntityAvatar, oversizedPNG(t, 8000, 8000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Status != models.ImageModerationRejected || uploadID != "" {
+		t.Fatalf("expected an oversized image to be rejected, got %+v (%q)", decision, uploadID)
+	}
+
+	decision, asset, err := svc.ModerateAndPersist(context.Background(), SaveRequest{OwnerUserID: "user-1", EntityType: models.ImageEntityAvatar, ImageBytes: oversizedPNG(t, 8000, 8000)})
+	if err != nil || asset != nil || decision.Status != models.ImageModerationRejected || len(storage.saved) != 0 {
+		t.Fatalf("expected an oversized image not to be stored, got %+v, %+v, %v", decision, asset, err)
+	}
+}
+
 func TestServiceModerateUploadTimeoutFallback(t *testing.T) {
 	svc := NewService(
 		&fakeModerator{err: errors.New("boom")},
@@ -176,3 +306,218 @@ func TestServiceModerateUploadPendingStoreFailure(t *testing.T) {
 		t.Fatalf("expected empty upload id")
 	}
 }
+
+func TestServiceOpenFallsBackToLoad(t *testing.T) {
+	store := &fakeStorage{saved: []*models.ImageAsset{{ID: "asset-1", ImageBytes: []byte("image bytes")}}}
+	svc := NewService(&fakeModerator{}, store, NewInMemoryPendingStore(5*time.Minute), 5*time.Second)
+
+	stream, err := svc.Open(context.Background(), "asset-1")
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	defer stream.Body.Close()
+	data, _ := io.ReadAll(stream.Body)
+	if string(data) != "image bytes" || stream.Size != int64(len(data)) {
+		t.Fatalf("unexpected stream %q (size %d)", data, stream.Size)
+	}
+
+	missing, err := svc.Open(context.Background(), "missing")
+	if err != nil || missing != nil {
+		t.Fatalf("expected nil stream for missing image, got %v, %v", missing, err)
+	}
+
+	if _, err := svc.DirectURL(context.Background(), "asset-1", time.Minute); !errors.Is(err, ErrDirectURLUnsupported) {
+		t.Fatalf("expected ErrDirectURLUnsupported, got %v", err)
+	}
+}
+
+func TestServiceModerateAndPersistStoresDerivatives(t *testing.T) {
+	store := &fakeStorage{}
+	svc := NewService(
+		&fakeModerator{decision: &models.ModerationDecision{Status: models.ImageModerationApproved}},
+		store,
+		NewInMemoryPendingStore(5*time.Minute),
+		5*time.Second,
+	)
+
+	upload := withEXIFOrientation(t, testJPEG(t, 640, 480), 3)
+	_, asset, err := svc.ModerateAndPersist(context.Background(), SaveRequest{
+		OwnerUserID: "user-1",
+		EntityType:  models.ImageEntityBuild,
+		ImageBytes:  upload,
+	})
+	if err != nil || asset == nil {
+		t.Fatalf("moderate and persist: %v, %v", asset, err)
+	}
+
+	req := store.requests[0]
+	if bytes.Equal(req.ImageBytes, upload) || bytes.Contains(req.ImageBytes, []byte("Exif")) {
+		t.Fatalf("expected metadata-stripped bytes to be stored")
+	}
+	if len(req.Derivatives) != len(models.ImageSizes) {
+		t.Fatalf("expected %d derivatives, got %d", len(models.ImageSizes), len(req.Derivatives))
+	}
+	if req.PerceptualHash == nil {
+		t.Fatalf("expected a perceptual hash to be stored")
+	}
+}
+
+func TestServiceOpenRefInlineBytes(t *testing.T) {
+	svc := NewService(&fakeModerator{}, &fakeStorage{}, NewInMemoryPendingStore(5*time.Minute), 5*time.Second)
+
+	ref := &models.ImageRef{Data: []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}}
+	stream, err := svc.OpenRef(context.Background(), ref, models.ImageSizeThumb)
+	if err != nil {
+		t.Fatalf("open ref error: %v", err)
+	}
+	defer stream.Body.Close()
+	if stream.ContentType != "image/png" || stream.ETag == "" || stream.Size != 8 {
+		t.Fatalf("unexpected stream: type=%s etag=%s size=%d", stream.ContentType, stream.ETag, stream.Size)
+	}
+	data, _ := io.ReadAll(stream.Body)
+	if len(data) != 8 {
+		t.Fatalf("content type sniffing consumed the body: %d bytes left", len(data))
+	}
+
+	if stream, err := svc.OpenRef(context.Background(), nil, models.ImageSizeThumb); stream != nil || err != nil {
+		t.Fatalf("expected nil stream for nil ref, got %v, %v", stream, err)
+	}
+}
+
+func TestServiceModerateAndPersistQueuesReviewBand(t *testing.T) {
+	store := &fakeReviewStorage{}
+	detector := &moderation.FakeDetector{Labels: []models.ModerationLabel{{Name: "Suggestive", Confidence: 55}}}
+	svc := NewService(moderation.NewServiceWithReview(detector, 70, 40), store, NewInMemoryPendingStore(5*time.Minute), 5*time.Second)
+
+	decision, asset, err := svc.ModerateAndPersist(context.Background(), SaveRequest{
+		OwnerUserID: "user-1",
+		EntityType:  models.ImageEntityBuild,
+		ImageBytes:  testJPEG(t, 64, 48),
+	})
+	if err != nil || asset == nil {
+		t.Fatalf("moderate and persist: %v, %v", asset, err)
+	}
+	if decision.Status != models.ImageModerationPendingReview || !decision.Queued || !decision.Accepted() {
+		t.Fatalf("decision=%+v", decision)
+	}
+	if req := store.requests[0]; req.Status != models.ImageModerationPendingReview || req.QueuedReason != "Needs review" {
+		t.Fatalf("saved status=%s reason=%q", req.Status, req.QueuedReason)
+	}
+
+	// The entity shows a placeholder until review; reviewers see the real image
+	stream, err := svc.OpenVariant(context.Background(), asset.ID, models.ImageSizeOriginal)
+	if err != nil || stream == nil || !stream.Placeholder || stream.ContentType != "image/png" {
+		t.Fatalf("expected placeholder stream, got %+v, %v", stream, err)
+	}
+	stream.Body.Close()
+	stream, err = svc.OpenForReview(context.Background(), asset.ID, models.ImageSizeOriginal)
+	if err != nil || stream == nil || stream.Placeholder {
+		t.Fatalf("expected real image for review, got %+v, %v", stream, err)
+	}
+	stream.Body.Close()
+
+	if _, err := svc.RejectReview(context.Background(), asset.ID, "admin-1", "  "); !errors.Is(err, ErrReviewReasonRequired) {
+		t.Fatalf("expected reason required, got %v", err)
+	}
+	review, err := svc.ApproveReview(context.Background(), asset.ID, "admin-1", "")
+	if err != nil || review == nil || review.Status != models.ImageModerationApproved {
+		t.Fatalf("approve: %+v, %v", review, err)
+	}
+	if _, err := svc.ApproveReview(context.Background(), asset.ID, "admin-1", ""); !errors.Is(err, ErrNotPendingReview) {
+		t.Fatalf("expected ErrNotPendingReview on second decision, got %v", err)
+	}
+	stream, err = svc.OpenVariant(context.Background(), asset.ID, models.ImageSizeOriginal)
+	if err != nil || stream == nil || stream.Placeholder {
+		t.Fatalf("expected approved image, got %+v, %v", stream, err)
+	}
+	stream.Body.Close()
+}
+
+func TestServiceModerateUploadQueuesWhenDetectorFails(t *testing.T) {
+	store := &fakeReviewStorage{}
+	detector := &moderation.FakeDetector{Err: errors.New("throttled")}
+	svc := NewService(moderation.NewService(detector, 70), store, NewInMemoryPendingStore(5*time.Minute), 5*time.Second)
+
+	decision, uploadID, err := svc.ModerateUpload(context.Background(), "user-1", models.ImageEntityAircraft, []byte("abc"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Status != models.ImageModerationPendingReview || !decision.Queued || uploadID == "" {
+		t.Fatalf("decision=%+v uploadID=%q", decision, uploadID)
+	}
+
+	asset, err := svc.PersistApprovedUpload(context.Background(), "user-1", uploadID, models.ImageEntityAircraft, "aircraft-1")
+	if err != nil || asset == nil || asset.Status != models.ImageModerationPendingReview {
+		t.Fatalf("persist: %+v, %v", asset, err)
+	}
+	if store.requests[0].QueuedReason != "Unable to verify right now" {
+		t.Fatalf("queued reason=%q", store.requests[0].QueuedReason)
+	}
+	if got := AssetDecision(asset); !got.Queued {
+		t.Fatalf("AssetDecision=%+v", got)
+	}
+}
+
+func TestServiceRejectedModerationIsNotStored(t *testing.T) {
+	store := &fakeReviewStorage{}
+	detector := &moderation.FakeDetector{Labels: []models.ModerationLabel{{Name: "Explicit", Confidence: 90}}}
+	svc := NewService(moderation.NewServiceWithReview(detector, 70, 40), store, NewInMemoryPendingStore(5*time.Minute), 5*time.Second)
+
+	decision, asset, err := svc.ModerateAndPersist(context.Background(), SaveRequest{OwnerUserID: "user-1", ImageBytes: []byte("abc")})
+	if err != nil || asset != nil || decision.Accepted() {
+		t.Fatalf("decision=%+v asset=%v err=%v", decision, asset, err)
+	}
+	if len(store.requests) != 0 {
+		t.Fatalf("expected no save, got %d", len(store.requests))
+	}
+}
+
+func TestServiceBackfillAndFindUploadDuplicates(t *testing.T) {
+	photo := testJPEG(t, 640, 480)
+	store := &fakeDuplicateStorage{hashes: map[string]uint64{}, unhashable: map[string]bool{}}
+	store.saved = []*models.ImageAsset{
+		{ID: "a-1", EntityType: models.ImageEntityGear, EntityID: "gear-1", ImageBytes: photo},
+		{ID: "a-2", EntityType: models.ImageEntityGear, EntityID: "gear-2", ImageBytes: []byte("not an image")},
+		{ID: "a-3", EntityType: models.ImageEntityGear, EntityID: "gear-3", ImageBytes: withEXIFOrientation(t, photo, 3)},
+	}
+	svc := NewService(&fakeModerator{}, store, NewInMemoryPendingStore(5*time.Minute), 5*time.Second)
+
+	hashed, err := svc.BackfillPerceptualHashes(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if hashed != 2 || len(store.hashes) != 2 {
+		t.Fatalf("expected 2 images hashed, got %d (%v)", hashed, store.hashes)
+	}
+	if !store.unhashable["a-2"] {
+		t.Fatal("expected the undecodable image to be marked unhashable")
+	}
+	if ids, _ := store.ListUnhashed(context.Background(), "", 10); len(ids) != 0 {
+		t.Fatalf("expected nothing left to backfill, got %v", ids)
+	}
+
+	// Same photo on another catalog item
+	hash := store.hashes["a-1"]
+	upload := &models.ImageAsset{ID: "a-4", EntityType: models.ImageEntityGear, EntityID: "gear-4", PerceptualHash: &hash}
+	store.saved = append(store.saved, upload)
+	store.hashes["a-4"] = hash
+
+	matches, err := svc.FindUploadDuplicates(context.Background(), upload)
+	if err != nil {
+		t.Fatalf("find duplicates: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ImageID != "a-1" || matches[0].Distance != 0 {
+		t.Fatalf("expected a-1 as an exact match, got %+v", matches)
+	}
+
+	// Re-uploading to the same catalog item is not a duplicate
+	upload.EntityID = "gear-1"
+	matches, err = svc.FindUploadDuplicates(context.Background(), upload)
+	if err != nil || len(matches) != 0 {
+		t.Fatalf("expected no matches on the same item, got %+v, %v", matches, err)
+	}
+
+	if matches, err := NewService(&fakeModerator{}, &fakeStorage{}, nil, time.Second).FindUploadDuplicates(context.Background(), upload); matches != nil || err != nil {
+		t.Fatalf("expected no warnings without duplicate storage, got %+v, %v", matches, err)
+	}
+}
@@ -0,0 +1,471 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// maxImportRows caps the rows in one import so a preview stays responsive
+const maxImportRows = 1000
+
+// autoLinkSimilarity is the near-match score at which a preview suggests
+// linking without an exact canonical-key match
+const autoLinkSimilarity = 0.8
+
+// CatalogMatcher looks up and submits gear catalog entries;
+// database.GearCatalogStore satisfies it
+type CatalogMatcher interface {
+	Get(ctx context.Context, id string) (*models.GearCatalogItem, error)
+	GetByCanonicalKey(ctx context.Context, canonicalKey string) (*models.GearCatalogItem, error)
+	FindNearMatches(ctx context.Context, gearType models.GearType, brand, model string, threshold float64) ([]models.NearMatch, error)
+	Create(ctx context.Context, userID string, params models.CreateGearCatalogParams) (*models.GearCatalogCreateResponse, error)
+}
+
+// Importer bulk-adds inventory from CSV or JSON files. Preview parses a file
+// and matches each row against the gear catalog without writing anything;
+// Commit adds the rows the user confirmed.
+type Importer struct {
+	inventory InventoryManager
+	catalog   CatalogMatcher
+	logger    *logging.Logger
+}
+
+// NewImporter creates a new inventory importer
+func NewImporter(inventory InventoryManager, catalog CatalogMatcher, logger *logging.Logger) *Importer {
+	return &Importer{
+		inventory: inventory,
+		catalog:   catalog,
+		logger:    logger,
+	}
+}
+
+// importItem is the JSON shape of an imported item; brand, model and variant
+// are optional overrides for catalog matching
+type importItem struct {
+	models.AddInventoryParams
+	Brand   string `json:"brand,omitempty"`
+	Model   string `json:"model,omitempty"`
+	Variant string `json:"variant,omitempty"`
+}
+
+// Preview parses an import file and attaches catalog matches to every row
+func (im *Importer) Preview(ctx context.Context, userID string, format models.InventoryImportFormat, r io.Reader) (*models.InventoryImportPreview, error) {
+	var (
+		rows []models.InventoryImportRow
+		err  error
+	)
+	switch format {
+	case models.InventoryImportFormatCSV:
+		rows, err = parseInventoryCSV(r)
+	case models.InventoryImportFormatJSON:
+		rows, err = parseInventoryJSON(r)
+	default:
+		return nil, &ServiceError{Message: "format must be csv or json"}
+	}
+	if err != nil {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	if len(rows) > maxImportRows {
+		return nil, &ServiceError{Message: fmt.Sprintf("import is limited to %d rows", maxImportRows)}
+	}
+
+	preview := &models.InventoryImportPreview{Rows: rows}
+	matches := make(map[string]*models.InventoryImportRow) // Rows already matched, by canonical key
+	for i := range preview.Rows {
+		row := &preview.Rows[i]
+		if row.Error != "" {
+			row.SuggestedAction = models.InventoryImportUnlinked
+			preview.Invalid++
+			continue
+		}
+
+		if err := im.matchRow(ctx, row, matches); err != nil {
+			return nil, err
+		}
+		switch {
+		case row.ExactMatch != nil:
+			preview.Exact++
+		case len(row.NearMatches) > 0:
+			preview.Near++
+		default:
+			preview.Unmatched++
+		}
+	}
+
+	im.logger.Info("Previewed inventory import", logging.WithFields(map[string]interface{}{
+		"user_id":   userID,
+		"rows":      len(preview.Rows),
+		"exact":     preview.Exact,
+		"near":      preview.Near,
+		"unmatched": preview.Unmatched,
+		"invalid":   preview.Invalid,
+	}))
+	return preview, nil
+}
+
+// matchRow fills in a row's exact and near catalog matches and picks a
+// suggested action. Rows without a brand or model are left unlinked.
+func (im *Importer) matchRow(ctx context.Context, row *models.InventoryImportRow, matches map[string]*models.InventoryImportRow) error {
+	row.NearMatches = []models.NearMatch{}
+	row.SuggestedAction = models.InventoryImportUnlinked
+
+	if row.Item.CatalogID != "" {
+		item, err := im.catalog.Get(ctx, row.Item.CatalogID)
+		if err != nil {
+			return err
+		}
+		row.Item.CatalogID = ""
+		if item != nil && item.Status != models.CatalogStatusRemoved {
+			row.ExactMatch = item
+			row.SuggestedAction = models.InventoryImportLink
+			row.SuggestedCatalogID = item.ID
+			return nil
+		}
+	}
+
+	if row.Catalog.Brand == "" || row.Catalog.Model == "" {
+		return nil
+	}
+
+	key := models.BuildCanonicalKey(row.Catalog.GearType, row.Catalog.Brand, row.Catalog.Model, row.Catalog.Variant)
+	if seen, ok := matches[key]; ok {
+		row.ExactMatch = seen.ExactMatch
+		row.NearMatches = seen.NearMatches
+		row.SuggestedAction = seen.SuggestedAction
+		row.SuggestedCatalogID = seen.SuggestedCatalogID
+		return nil
+	}
+	matches[key] = row
+
+	exact, err := im.catalog.GetByCanonicalKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if exact != nil && exact.Status != models.CatalogStatusRemoved {
+		row.ExactMatch = exact
+		row.SuggestedAction = models.InventoryImportLink
+		row.SuggestedCatalogID = exact.ID
+		return nil
+	}
+
+	near, err := im.catalog.FindNearMatches(ctx, row.Catalog.GearType, row.Catalog.Brand, row.Catalog.Model, 0)
+	if err != nil {
+		return err
+	}
+	if near != nil {
+		row.NearMatches = near
+	}
+	if len(near) > 0 && near[0].Similarity >= autoLinkSimilarity {
+		row.SuggestedAction = models.InventoryImportLink
+		row.SuggestedCatalogID = near[0].Item.ID
+		return nil
+	}
+	row.SuggestedAction = models.InventoryImportCreate
+	return nil
+}
+
+// Commit adds the confirmed rows to the inventory. Failed rows are reported
+// in the result without aborting the rest of the import.
+func (im *Importer) Commit(ctx context.Context, userID string, params models.InventoryImportCommitParams) (*models.InventoryImportResult, error) {
+	if len(params.Rows) == 0 {
+		return nil, &ServiceError{Message: "no rows to import"}
+	}
+	if len(params.Rows) > maxImportRows {
+		return nil, &ServiceError{Message: fmt.Sprintf("import is limited to %d rows", maxImportRows)}
+	}
+
+	result := &models.InventoryImportResult{}
+	for _, decision := range params.Rows {
+		if err := im.commitRow(ctx, userID, decision, result); err != nil {
+			var svcErr *ServiceError
+			if !errors.As(err, &svcErr) {
+				im.logger.Error("Failed to import inventory row", logging.WithFields(map[string]interface{}{
+					"row":   decision.Row,
+					"error": err.Error(),
+				}))
+			}
+			result.Errors = append(result.Errors, models.InventoryImportRowError{
+				Row:   decision.Row,
+				Name:  decision.Item.Name,
+				Error: err.Error(),
+			})
+		}
+	}
+	result.Failed = len(result.Errors)
+
+	im.logger.Info("Imported inventory", logging.WithFields(map[string]interface{}{
+		"user_id":         userID,
+		"added":           result.Added,
+		"linked":          result.Linked,
+		"catalog_created": result.CatalogCreated,
+		"failed":          result.Failed,
+	}))
+	return result, nil
+}
+
+func (im *Importer) commitRow(ctx context.Context, userID string, decision models.InventoryImportDecision, result *models.InventoryImportResult) error {
+	action, ok := models.NormalizeInventoryImportAction(decision.Action)
+	if !ok {
+		return &ServiceError{Message: "action must be one of link, create, unlinked"}
+	}
+
+	item := decision.Item
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return &ServiceError{Message: "name is required"}
+	}
+	category, ok := models.NormalizeEquipmentCategory(string(item.Category))
+	if !ok {
+		return &ServiceError{Message: fmt.Sprintf("unknown category %q", item.Category)}
+	}
+	item.Category = category
+	if item.Quantity <= 0 {
+		item.Quantity = 1
+	}
+	item.CatalogID = ""
+
+	catalogCreated := false
+	switch action {
+	case models.InventoryImportLink:
+		catalogID := strings.TrimSpace(decision.CatalogID)
+		if catalogID == "" {
+			return &ServiceError{Message: "catalogId is required to link an item"}
+		}
+		catalogItem, err := im.catalog.Get(ctx, catalogID)
+		if err != nil {
+			return err
+		}
+		if catalogItem == nil || catalogItem.Status == models.CatalogStatusRemoved {
+			return &ServiceError{Message: "catalog item not found"}
+		}
+		item.CatalogID = catalogItem.ID
+
+	case models.InventoryImportCreate:
+		catalogParams := models.InventoryImportCatalogParams(item, "", "", "")
+		if decision.Catalog != nil {
+			catalogParams = *decision.Catalog
+			catalogParams.Brand = strings.TrimSpace(catalogParams.Brand)
+			catalogParams.Model = strings.TrimSpace(catalogParams.Model)
+			if catalogParams.GearType == "" {
+				catalogParams.GearType = models.GearTypeFromEquipmentCategory(item.Category)
+			}
+		}
+		if catalogParams.Brand == "" || catalogParams.Model == "" {
+			return &ServiceError{Message: "brand and model are required to create a catalog entry"}
+		}
+		created, err := im.catalog.Create(ctx, userID, catalogParams)
+		if err != nil {
+			return err
+		}
+		item.CatalogID = created.Item.ID
+		catalogCreated = !created.Existing
+	}
+
+	if _, err := im.inventory.AddItem(ctx, userID, item); err != nil {
+		return err
+	}
+
+	result.Added++
+	switch {
+	case catalogCreated:
+		result.CatalogCreated++
+	case item.CatalogID != "":
+		result.Linked++
+	default:
+		result.Unlinked++
+	}
+	return nil
+}
+
+// parseInventoryCSV reads one item per row. Columns are matched by header
+// name; name and category are required.
+func parseInventoryCSV(r io.Reader) ([]models.InventoryImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[normalizeCSVHeader(h)] = i
+	}
+	for _, required := range []string{"name", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV is missing required column %q", required)
+		}
+	}
+
+	rows := []models.InventoryImportRow{}
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, models.InventoryImportRow{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if isBlankRow(fields) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[idx])
+		}
+		rows = append(rows, parseCSVItem(line, get))
+	}
+
+	return rows, nil
+}
+
+func parseCSVItem(line int, get func(string) string) models.InventoryImportRow {
+	var errs []string
+	item := models.AddInventoryParams{
+		Name:           get("name"),
+		Manufacturer:   get("manufacturer"),
+		Notes:          get("notes"),
+		PurchaseSeller: get("purchase_seller"),
+		ProductURL:     get("product_url"),
+		CatalogID:      get("catalog_id"),
+		Quantity:       1,
+	}
+
+	if v := get("quantity"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			errs = append(errs, fmt.Sprintf("invalid quantity %q", v))
+		} else {
+			item.Quantity = n
+		}
+	}
+	if v := strings.TrimPrefix(get("purchase_price"), "$"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil || price < 0 {
+			errs = append(errs, fmt.Sprintf("invalid purchase_price %q", v))
+		} else {
+			item.PurchasePrice = &price
+		}
+	}
+
+	return newImportRow(line, importItem{
+		AddInventoryParams: item,
+		Brand:              get("brand"),
+		Model:              get("model"),
+		Variant:            get("variant"),
+	}, get("category"), errs)
+}
+
+// parseInventoryJSON reads either a bare array of items or {"items": [...]}
+func parseInventoryJSON(r io.Reader) ([]models.InventoryImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("JSON file is empty")
+	}
+
+	var items []json.RawMessage
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else {
+		var wrapper struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		items = wrapper.Items
+	}
+
+	rows := make([]models.InventoryImportRow, 0, len(items))
+	for i, raw := range items {
+		var item importItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			rows = append(rows, models.InventoryImportRow{Row: i + 1, Error: fmt.Sprintf("invalid item: %v", err)})
+			continue
+		}
+
+		var errs []string
+		if item.Quantity < 0 {
+			errs = append(errs, fmt.Sprintf("invalid quantity %d", item.Quantity))
+		} else if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.PurchasePrice != nil && *item.PurchasePrice < 0 {
+			errs = append(errs, "purchasePrice cannot be negative")
+		}
+		rows = append(rows, newImportRow(i+1, item, string(item.Category), errs))
+	}
+
+	return rows, nil
+}
+
+// newImportRow validates the name and category shared by both formats and
+// derives the catalog entry used for matching
+func newImportRow(row int, item importItem, category string, errs []string) models.InventoryImportRow {
+	item.Name = strings.TrimSpace(item.Name)
+	item.Manufacturer = strings.TrimSpace(item.Manufacturer)
+	item.CatalogID = strings.TrimSpace(item.CatalogID)
+	if item.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if normalized, ok := models.NormalizeEquipmentCategory(category); ok {
+		item.Category = normalized
+	} else {
+		item.Category = models.EquipmentCategory(category)
+		errs = append(errs, fmt.Sprintf("unknown category %q", category))
+	}
+
+	return models.InventoryImportRow{
+		Row:         row,
+		Item:        item.AddInventoryParams,
+		Catalog:     models.InventoryImportCatalogParams(item.AddInventoryParams, item.Brand, item.Model, item.Variant),
+		NearMatches: []models.NearMatch{},
+		Error:       strings.Join(errs, "; "),
+	}
+}
+
+func normalizeCSVHeader(h string) string {
+	h = strings.TrimPrefix(h, "\ufeff") // Excel BOM
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.ReplaceAll(h, " ", "_")
+}
+
+func isBlankRow(fields []string) bool {
+	for _, f := range fields {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
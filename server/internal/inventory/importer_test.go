@@ -0,0 +1,163 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// stubCatalog implements CatalogMatcher for testing
+type stubCatalog struct {
+	items   map[string]*models.GearCatalogItem // By canonical key
+	near    []models.NearMatch
+	created []models.CreateGearCatalogParams
+}
+
+func (c *stubCatalog) Get(ctx context.Context, id string) (*models.GearCatalogItem, error) {
+	for _, item := range c.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *stubCatalog) GetByCanonicalKey(ctx context.Context, canonicalKey string) (*models.GearCatalogItem, error) {
+	return c.items[canonicalKey], nil
+}
+
+func (c *stubCatalog) FindNearMatches(ctx context.Context, gearType models.GearType, brand, model string, threshold float64) ([]models.NearMatch, error) {
+	return c.near, nil
+}
+
+func (c *stubCatalog) Create(ctx context.Context, userID string, params models.CreateGearCatalogParams) (*models.GearCatalogCreateResponse, error) {
+	c.created = append(c.created, params)
+	return &models.GearCatalogCreateResponse{Item: &models.GearCatalogItem{ID: "catalog-new", GearType: params.GearType}}, nil
+}
+
+func newTestCatalog() *stubCatalog {
+	motor := &models.GearCatalogItem{ID: "catalog-motor", GearType: models.GearTypeMotor, Brand: "T-Motor", Model: "F60 Pro V", Status: models.CatalogStatusPublished}
+	return &stubCatalog{items: map[string]*models.GearCatalogItem{
+		models.BuildCanonicalKey(models.GearTypeMotor, "T-Motor", "F60 Pro V", ""): motor,
+	}}
+}
+
+func TestImporterPreview_CSVMatchesCatalog(t *testing.T) {
+	catalog := newTestCatalog()
+	catalog.near = []models.NearMatch{{Item: models.GearCatalogItem{ID: "catalog-vtx"}, Similarity: 0.5}}
+	importer := NewImporter(NewInMemoryService(testutil.NullLogger()), catalog, testutil.NullLogger())
+
+	csv := "Name,Category,Manufacturer,Quantity,Purchase Price\n" +
+		"T-Motor F60 Pro V,Motors,T-Motor,4,$22.50\n" +
+		"Walksnail Avatar VTX,vtx,Walksnail,1,\n" +
+		"Zip ties,accessory,,50,\n" +
+		"Mystery part,widgets,,1,\n"
+
+	preview, err := importer.Preview(context.Background(), "user-1", models.InventoryImportFormatCSV, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if len(preview.Rows) != 4 {
+		t.Fatalf("rows = %d, want 4", len(preview.Rows))
+	}
+
+	motor := preview.Rows[0]
+	if motor.Row != 2 || motor.Item.Quantity != 4 || motor.Item.PurchasePrice == nil || *motor.Item.PurchasePrice != 22.5 {
+		t.Errorf("motor row parsed as %+v", motor)
+	}
+	if motor.Catalog.Model != "F60 Pro V" {
+		t.Errorf("motor catalog model = %q, want brand prefix stripped", motor.Catalog.Model)
+	}
+	if motor.ExactMatch == nil || motor.SuggestedAction != models.InventoryImportLink || motor.SuggestedCatalogID != "catalog-motor" {
+		t.Errorf("motor should link to exact match, got %+v", motor)
+	}
+
+	vtx := preview.Rows[1]
+	if vtx.ExactMatch != nil || len(vtx.NearMatches) != 1 || vtx.SuggestedAction != models.InventoryImportCreate {
+		t.Errorf("vtx should suggest create with near matches, got %+v", vtx)
+	}
+
+	if preview.Rows[2].SuggestedAction != models.InventoryImportUnlinked {
+		t.Errorf("item without brand should stay unlinked, got %q", preview.Rows[2].SuggestedAction)
+	}
+	if preview.Rows[3].Error == "" {
+		t.Error("unknown category should be reported")
+	}
+
+	if preview.Exact != 1 || preview.Near != 1 || preview.Unmatched != 1 || preview.Invalid != 1 {
+		t.Errorf("preview counts = %+v", preview)
+	}
+}
+
+func TestImporterPreview_JSONSuggestsStrongNearMatch(t *testing.T) {
+	catalog := &stubCatalog{near: []models.NearMatch{{Item: models.GearCatalogItem{ID: "catalog-rx"}, Similarity: 0.92}}}
+	importer := NewImporter(NewInMemoryService(testutil.NullLogger()), catalog, testutil.NullLogger())
+
+	body := `{"items": [{"name": "EP2 receiver", "category": "receivers", "brand": "HappyModel", "model": "EP2"}]}`
+	preview, err := importer.Preview(context.Background(), "user-1", models.InventoryImportFormatJSON, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	row := preview.Rows[0]
+	if row.Item.Quantity != 1 {
+		t.Errorf("quantity = %d, want default of 1", row.Item.Quantity)
+	}
+	if row.SuggestedAction != models.InventoryImportLink || row.SuggestedCatalogID != "catalog-rx" {
+		t.Errorf("expected strong near match to be suggested, got %+v", row)
+	}
+}
+
+func TestImporterPreview_RejectsMissingColumns(t *testing.T) {
+	importer := NewImporter(NewInMemoryService(testutil.NullLogger()), &stubCatalog{}, testutil.NullLogger())
+
+	_, err := importer.Preview(context.Background(), "user-1", models.InventoryImportFormatCSV, strings.NewReader("name,quantity\nProps,4\n"))
+	if _, ok := err.(*ServiceError); !ok {
+		t.Fatalf("Preview() error = %v, want ServiceError", err)
+	}
+}
+
+func TestImporterCommit_AppliesDecisions(t *testing.T) {
+	ctx := context.Background()
+	inventorySvc := NewInMemoryService(testutil.NullLogger())
+	catalog := newTestCatalog()
+	importer := NewImporter(inventorySvc, catalog, testutil.NullLogger())
+
+	result, err := importer.Commit(ctx, "user-1", models.InventoryImportCommitParams{Rows: []models.InventoryImportDecision{
+		{Row: 2, Item: models.AddInventoryParams{Name: "T-Motor F60 Pro V", Category: models.CategoryMotors, Quantity: 4}, Action: models.InventoryImportLink, CatalogID: "catalog-motor"},
+		{Row: 3, Item: models.AddInventoryParams{Name: "Walksnail Avatar VTX", Category: models.CategoryVTX, Manufacturer: "Walksnail"}, Action: models.InventoryImportCreate},
+		{Row: 4, Item: models.AddInventoryParams{Name: "Zip ties", Category: "accessory", Quantity: 50}, Action: models.InventoryImportUnlinked},
+		{Row: 5, Item: models.AddInventoryParams{Name: "Ghost", Category: models.CategoryFrames}, Action: models.InventoryImportLink, CatalogID: "missing"},
+		{Row: 6, Item: models.AddInventoryParams{Name: "No brand", Category: models.CategoryFrames}, Action: models.InventoryImportCreate},
+	}})
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if result.Added != 3 || result.Linked != 1 || result.CatalogCreated != 1 || result.Unlinked != 1 || result.Failed != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(catalog.created) != 1 || catalog.created[0].Brand != "Walksnail" || catalog.created[0].Model != "Avatar VTX" {
+		t.Errorf("catalog entry = %+v", catalog.created)
+	}
+
+	inv, err := inventorySvc.GetInventory(ctx, "user-1", models.InventoryFilterParams{})
+	if err != nil {
+		t.Fatalf("GetInventory() error = %v", err)
+	}
+	linked := 0
+	for _, item := range inv.Items {
+		if item.CatalogID != "" {
+			linked++
+		}
+		if item.Name == "Zip ties" && item.Category != models.CategoryAccessories {
+			t.Errorf("category = %q, want accessories", item.Category)
+		}
+	}
+	if len(inv.Items) != 3 || linked != 2 {
+		t.Errorf("inventory = %+v", inv.Items)
+	}
+}
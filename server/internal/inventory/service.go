@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -21,6 +23,10 @@ type InventoryManager interface {
 	UpdateItem(ctx context.Context, userID string, params models.UpdateInventoryParams) (*models.InventoryItem, error)
 	RemoveItem(ctx context.Context, id string, userID string) error
 	GetSummary(ctx context.Context, userID string) (*models.InventorySummary, error)
+	ListUnits(ctx context.Context, userID string, itemID string) ([]models.InventoryUnit, error)
+	AddUnit(ctx context.Context, userID string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error)
+	UpdateUnit(ctx context.Context, userID string, itemID string, params models.UpdateInventoryUnitParams) (*models.InventoryUnit, error)
+	RemoveUnit(ctx context.Context, userID string, itemID string, unitID string) error
 }
 
 // Service handles inventory operations backed by PostgreSQL
@@ -47,6 +53,10 @@ func (s *Service) AddItem(ctx context.Context, userID string, params models.AddI
 		return nil, &ServiceError{Message: "name is required"}
 	}
 
+	// Older clients still send per-item details inside specs
+	var legacyUnits []models.AddInventoryUnitParams
+	params.Specs, legacyUnits, _ = models.ExtractLegacyInventoryUnits(params.Specs)
+
 	// Use atomic UPSERT when catalog_id is provided to prevent duplicates from race conditions
 	if params.CatalogID != "" {
 		s.logger.Debug("Adding inventory item from catalog (using UPSERT)", logging.WithFields(map[string]interface{}{
@@ -61,6 +71,9 @@ func (s *Service) AddItem(ctx context.Context, userID string, params models.AddI
 			s.logger.Error("Failed to add/increment inventory item", logging.WithField("error", err.Error()))
 			return nil, err
 		}
+		if err := s.addLegacyUnits(ctx, userID, item.ID, legacyUnits); err != nil {
+			return nil, err
+		}
 		enriched, err := s.store.Get(ctx, item.ID, userID)
 		if err == nil && enriched != nil {
 			item = enriched
@@ -84,6 +97,9 @@ func (s *Service) AddItem(ctx context.Context, userID string, params models.AddI
 		s.logger.Error("Failed to add inventory item", logging.WithField("error", err.Error()))
 		return nil, err
 	}
+	if err := s.addLegacyUnits(ctx, userID, item.ID, legacyUnits); err != nil {
+		return nil, err
+	}
 	enriched, err := s.store.Get(ctx, item.ID, userID)
 	if err == nil && enriched != nil {
 		item = enriched
@@ -131,6 +147,23 @@ func (s *Service) UpdateItem(ctx context.Context, userID string, params models.U
 		return nil, &ServiceError{Message: "item ID is required"}
 	}
 
+	var legacyUnits []models.AddInventoryUnitParams
+	var hasLegacyUnits bool
+	params.Specs, legacyUnits, hasLegacyUnits = models.ExtractLegacyInventoryUnits(params.Specs)
+
+	if params.Quantity != nil {
+		tracked, err := s.store.CountUnits(ctx, params.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(legacyUnits) > tracked {
+			tracked = len(legacyUnits)
+		}
+		if err := validateQuantity(*params.Quantity, tracked); err != nil {
+			return nil, err
+		}
+	}
+
 	s.logger.Debug("Updating inventory item", logging.WithField("id", params.ID))
 
 	item, err := s.store.Update(ctx, userID, params)
@@ -142,6 +175,19 @@ func (s *Service) UpdateItem(ctx context.Context, userID string, params models.U
 		return nil, err
 	}
 
+	if hasLegacyUnits {
+		if err := s.store.AddLegacyUnits(ctx, userID, item.ID, legacyUnits); err != nil {
+			s.logger.Error("Failed to add inventory units", logging.WithFields(map[string]interface{}{
+				"id":    params.ID,
+				"error": err.Error(),
+			}))
+			return nil, err
+		}
+		if item, err = s.store.Get(ctx, params.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	s.logger.Info("Updated inventory item", logging.WithField("id", params.ID))
 	return item, nil
 }
@@ -171,6 +217,89 @@ func (s *Service) GetSummary(ctx context.Context, userID string) (*models.Invent
 	return s.store.GetSummary(ctx, userID)
 }
 
+// ListUnits returns the individually tracked units of an inventory item
+func (s *Service) ListUnits(ctx context.Context, userID string, itemID string) ([]models.InventoryUnit, error) {
+	item, err := s.store.Get(ctx, itemID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, database.ErrInventoryItemNotFound
+	}
+	if item.Units == nil {
+		return []models.InventoryUnit{}, nil
+	}
+	return item.Units, nil
+}
+
+// AddUnit starts tracking one piece of an inventory item
+func (s *Service) AddUnit(ctx context.Context, userID string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error) {
+	if err := normalizeAddUnitParams(&params); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.store.AddUnit(ctx, userID, itemID, params)
+	if err != nil {
+		return nil, unitStoreError(err)
+	}
+
+	s.logger.Info("Added inventory unit", logging.WithFields(map[string]interface{}{
+		"item_id": itemID,
+		"unit_id": unit.ID,
+	}))
+	return unit, nil
+}
+
+// UpdateUnit updates a tracked unit, e.g. to record damage or move it to another aircraft
+func (s *Service) UpdateUnit(ctx context.Context, userID string, itemID string, params models.UpdateInventoryUnitParams) (*models.InventoryUnit, error) {
+	if params.ID == "" {
+		return nil, &ServiceError{Message: "unit ID is required"}
+	}
+	if err := normalizeUpdateUnitParams(&params); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.store.UpdateUnit(ctx, userID, itemID, params)
+	if err != nil {
+		return nil, unitStoreError(err)
+	}
+
+	s.logger.Info("Updated inventory unit", logging.WithFields(map[string]interface{}{
+		"item_id": itemID,
+		"unit_id": unit.ID,
+	}))
+	return unit, nil
+}
+
+// RemoveUnit stops tracking a unit; the item's quantity is unchanged
+func (s *Service) RemoveUnit(ctx context.Context, userID string, itemID string, unitID string) error {
+	if unitID == "" {
+		return &ServiceError{Message: "unit ID is required"}
+	}
+	return s.store.DeleteUnit(ctx, userID, itemID, unitID)
+}
+
+// addLegacyUnits tracks units described by per-item details from older clients.
+func (s *Service) addLegacyUnits(ctx context.Context, userID string, itemID string, units []models.AddInventoryUnitParams) error {
+	for _, unit := range units {
+		if _, err := s.store.AddUnit(ctx, userID, itemID, unit); err != nil {
+			s.logger.Error("Failed to add inventory unit", logging.WithFields(map[string]interface{}{
+				"id":    itemID,
+				"error": err.Error(),
+			}))
+			return err
+		}
+	}
+	return nil
+}
+
+func unitStoreError(err error) error {
+	if errors.Is(err, database.ErrInventoryUnitAircraftNotFound) {
+		return &ServiceError{Message: "aircraftId must be one of your aircraft"}
+	}
+	return err
+}
+
 // InMemoryService is an in-memory implementation for development/testing
 type InMemoryService struct {
 	items  map[string]models.InventoryItem
@@ -202,6 +331,9 @@ func (s *InMemoryService) AddItem(ctx context.Context, userID string, params mod
 		quantity = 1
 	}
 
+	var legacyUnits []models.AddInventoryUnitParams
+	params.Specs, legacyUnits, _ = models.ExtractLegacyInventoryUnits(params.Specs)
+
 	item := models.InventoryItem{
 		ID:                id,
 		UserID:            userID,
@@ -210,6 +342,7 @@ func (s *InMemoryService) AddItem(ctx context.Context, userID string, params mod
 		Manufacturer:      params.Manufacturer,
 		Quantity:          quantity,
 		Notes:             params.Notes,
+		CatalogID:         params.CatalogID,
 		BuildID:           params.BuildID,
 		PurchasePrice:     params.PurchasePrice,
 		PurchaseSeller:    params.PurchaseSeller,
@@ -220,6 +353,13 @@ func (s *InMemoryService) AddItem(ctx context.Context, userID string, params mod
 		UpdatedAt:         now,
 	}
 
+	for _, unit := range legacyUnits {
+		item.Units = append(item.Units, newInMemoryUnit(id, unit, now))
+	}
+	if len(item.Units) > item.Quantity {
+		item.Quantity = len(item.Units)
+	}
+
 	s.items[id] = item
 	s.logger.Info("Added inventory item (in-memory)", logging.WithField("id", id))
 
@@ -323,6 +463,18 @@ func (s *InMemoryService) UpdateItem(ctx context.Context, userID string, params
 		return nil, &ServiceError{Message: "inventory item not found"}
 	}
 
+	var legacyUnits []models.AddInventoryUnitParams
+	var hasLegacyUnits bool
+	params.Specs, legacyUnits, hasLegacyUnits = models.ExtractLegacyInventoryUnits(params.Specs)
+	if hasLegacyUnits {
+		item.Units = addInMemoryLegacyUnits(item.ID, item.Units, legacyUnits, time.Now())
+	}
+	if params.Quantity != nil {
+		if err := validateQuantity(*params.Quantity, len(item.Units)); err != nil {
+			return nil, err
+		}
+	}
+
 	if params.Name != nil {
 		item.Name = *params.Name
 	}
@@ -386,13 +538,183 @@ func (s *InMemoryService) GetSummary(ctx context.Context, userID string) (*model
 			continue
 		}
 		summary.TotalItems += item.Quantity
-		summary.TotalValue += models.CalculateInventoryItemTotalValue(item.Quantity, item.PurchasePrice, item.Specs)
+		summary.TotalValue += models.CalculateInventoryItemTotalValue(item.Quantity, item.PurchasePrice, item.Units)
 		summary.ByCategory[item.Category] += item.Quantity
 	}
 
 	return summary, nil
 }
 
+// ListUnits returns the individually tracked units of an inventory item
+func (s *InMemoryService) ListUnits(ctx context.Context, userID string, itemID string) ([]models.InventoryUnit, error) {
+	item, err := s.ownedItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.InventoryUnit{}, item.Units...), nil
+}
+
+// AddUnit starts tracking one piece of an inventory item
+func (s *InMemoryService) AddUnit(ctx context.Context, userID string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error) {
+	item, err := s.ownedItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeAddUnitParams(&params); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	unit := newInMemoryUnit(itemID, params, now)
+	item.Units = append(item.Units, unit)
+	if len(item.Units) > item.Quantity {
+		item.Quantity = len(item.Units)
+	}
+	item.UpdatedAt = now
+	s.items[itemID] = *item
+	return &unit, nil
+}
+
+// UpdateUnit updates a tracked unit
+func (s *InMemoryService) UpdateUnit(ctx context.Context, userID string, itemID string, params models.UpdateInventoryUnitParams) (*models.InventoryUnit, error) {
+	item, err := s.ownedItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeUpdateUnitParams(&params); err != nil {
+		return nil, err
+	}
+
+	for i := range item.Units {
+		unit := &item.Units[i]
+		if unit.ID != params.ID {
+			continue
+		}
+		if params.SerialNumber != nil {
+			unit.SerialNumber = *params.SerialNumber
+		}
+		if params.PurchasePrice != nil {
+			unit.PurchasePrice = params.PurchasePrice
+		}
+		if params.PurchaseSeller != nil {
+			unit.PurchaseSeller = *params.PurchaseSeller
+		}
+		if params.Condition != nil {
+			unit.Condition = *params.Condition
+		}
+		if params.AircraftID != nil {
+			unit.AircraftID = *params.AircraftID
+		}
+		if params.BuildID != nil {
+			unit.BuildID = *params.BuildID
+		}
+		if params.Notes != nil {
+			unit.Notes = *params.Notes
+		}
+		unit.UpdatedAt = time.Now()
+		updated := *unit
+		s.items[itemID] = *item
+		return &updated, nil
+	}
+	return nil, database.ErrInventoryUnitNotFound
+}
+
+// RemoveUnit stops tracking a unit; the item's quantity is unchanged
+func (s *InMemoryService) RemoveUnit(ctx context.Context, userID string, itemID string, unitID string) error {
+	item, err := s.ownedItem(userID, itemID)
+	if err != nil {
+		return err
+	}
+	for i, unit := range item.Units {
+		if unit.ID == unitID {
+			item.Units = append(item.Units[:i], item.Units[i+1:]...)
+			s.items[itemID] = *item
+			return nil
+		}
+	}
+	return database.ErrInventoryUnitNotFound
+}
+
+func (s *InMemoryService) ownedItem(userID string, itemID string) (*models.InventoryItem, error) {
+	item, ok := s.items[itemID]
+	if !ok || (userID != "" && item.UserID != userID) {
+		return nil, database.ErrInventoryItemNotFound
+	}
+	return &item, nil
+}
+
+func newInMemoryUnit(itemID string, params models.AddInventoryUnitParams, now time.Time) models.InventoryUnit {
+	condition := params.Condition
+	if condition == "" {
+		condition = models.InventoryUnitNew
+	}
+	return models.InventoryUnit{
+		ID:              generateID(),
+		InventoryItemID: itemID,
+		SerialNumber:    params.SerialNumber,
+		PurchasePrice:   params.PurchasePrice,
+		PurchaseSeller:  params.PurchaseSeller,
+		Condition:       condition,
+		AircraftID:      params.AircraftID,
+		BuildID:         params.BuildID,
+		Notes:           params.Notes,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// addInMemoryLegacyUnits mirrors InventoryStore.AddLegacyUnits.
+func addInMemoryLegacyUnits(itemID string, units []models.InventoryUnit, details []models.AddInventoryUnitParams, now time.Time) []models.InventoryUnit {
+	for i := len(units); i < len(details); i++ {
+		units = append(units, newInMemoryUnit(itemID, details[i], now))
+	}
+	return units
+}
+
+// validateQuantity rejects a quantity that would leave tracked units without a piece.
+func validateQuantity(quantity int, tracked int) error {
+	if quantity < tracked {
+		return &ServiceError{Message: fmt.Sprintf("quantity cannot be less than the %d individually tracked units", tracked)}
+	}
+	return nil
+}
+
+func normalizeAddUnitParams(params *models.AddInventoryUnitParams) error {
+	condition, ok := models.NormalizeInventoryUnitCondition(string(params.Condition))
+	if !ok {
+		return &ServiceError{Message: "condition must be one of new, used, damaged, dead, repaired"}
+	}
+	params.Condition = condition
+	params.SerialNumber = strings.TrimSpace(params.SerialNumber)
+	params.PurchaseSeller = strings.TrimSpace(params.PurchaseSeller)
+	params.AircraftID = strings.TrimSpace(params.AircraftID)
+	params.BuildID = strings.TrimSpace(params.BuildID)
+	params.Notes = strings.TrimSpace(params.Notes)
+	if params.PurchasePrice != nil && *params.PurchasePrice < 0 {
+		return &ServiceError{Message: "purchasePrice cannot be negative"}
+	}
+	return nil
+}
+
+func normalizeUpdateUnitParams(params *models.UpdateInventoryUnitParams) error {
+	if params.Condition != nil {
+		condition, ok := models.NormalizeInventoryUnitCondition(string(*params.Condition))
+		if !ok {
+			return &ServiceError{Message: "condition must be one of new, used, damaged, dead, repaired"}
+		}
+		params.Condition = &condition
+	}
+	for _, field := range []*string{params.SerialNumber, params.PurchaseSeller, params.AircraftID, params.BuildID, params.Notes} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	if params.PurchasePrice != nil && *params.PurchasePrice < 0 {
+		return &ServiceError{Message: "purchasePrice cannot be negative"}
+	}
+	return nil
+}
+
 // ServiceError represents an error from the inventory service
 type ServiceError struct {
 	Message string
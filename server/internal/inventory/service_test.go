@@ -53,6 +53,82 @@ func TestInMemoryServiceGetSummary_UsesQuantities(t *testing.T) {
 	}
 }
 
+func TestInMemoryServiceUnits_TrackPiecesAndGuardQuantity(t *testing.T) {
+	ctx := context.Background()
+	svc := NewInMemoryService(testutil.NullLogger())
+
+	item, err := svc.AddItem(ctx, "user-1", models.AddInventoryParams{
+		Name:          "2207 Motor",
+		Category:      models.CategoryMotors,
+		Quantity:      1,
+		PurchasePrice: floatPtr(20),
+	})
+	if err != nil {
+		t.Fatalf("AddItem() error = %v", err)
+	}
+
+	first, err := svc.AddUnit(ctx, "user-1", item.ID, models.AddInventoryUnitParams{SerialNumber: " SN-1 "})
+	if err != nil {
+		t.Fatalf("AddUnit() error = %v", err)
+	}
+	if first.SerialNumber != "SN-1" || first.Condition != models.InventoryUnitNew {
+		t.Fatalf("unit = %+v, want trimmed serial and new condition", first)
+	}
+
+	if _, err := svc.AddUnit(ctx, "user-1", item.ID, models.AddInventoryUnitParams{
+		SerialNumber:  "SN-2",
+		PurchasePrice: floatPtr(15),
+		Condition:     "Used",
+	}); err != nil {
+		t.Fatalf("AddUnit() error = %v", err)
+	}
+
+	got, err := svc.GetItem(ctx, item.ID, "user-1")
+	if err != nil {
+		t.Fatalf("GetItem() error = %v", err)
+	}
+	if got.Quantity != 2 {
+		t.Fatalf("quantity = %d, want 2 after tracking a second piece", got.Quantity)
+	}
+
+	quantity := 1
+	if _, err := svc.UpdateItem(ctx, "user-1", models.UpdateInventoryParams{ID: item.ID, Quantity: &quantity}); err == nil {
+		t.Fatal("UpdateItem() error = nil, want error for quantity below tracked units")
+	}
+
+	damaged := models.InventoryUnitDamaged
+	if _, err := svc.UpdateUnit(ctx, "user-1", item.ID, models.UpdateInventoryUnitParams{ID: first.ID, Condition: &damaged}); err != nil {
+		t.Fatalf("UpdateUnit() error = %v", err)
+	}
+	bogus := models.InventoryUnitCondition("melted")
+	if _, err := svc.UpdateUnit(ctx, "user-1", item.ID, models.UpdateInventoryUnitParams{ID: first.ID, Condition: &bogus}); err == nil {
+		t.Fatal("UpdateUnit() error = nil, want error for unknown condition")
+	}
+
+	summary, err := svc.GetSummary(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	if summary.TotalValue != 35 {
+		t.Fatalf("summary.TotalValue = %v, want 35", summary.TotalValue)
+	}
+
+	if err := svc.RemoveUnit(ctx, "user-1", item.ID, first.ID); err != nil {
+		t.Fatalf("RemoveUnit() error = %v", err)
+	}
+	units, err := svc.ListUnits(ctx, "user-1", item.ID)
+	if err != nil {
+		t.Fatalf("ListUnits() error = %v", err)
+	}
+	if len(units) != 1 || units[0].SerialNumber != "SN-2" {
+		t.Fatalf("units = %+v, want only SN-2", units)
+	}
+
+	if _, err := svc.ListUnits(ctx, "user-2", item.ID); err == nil {
+		t.Fatal("ListUnits() error = nil, want not found for another user")
+	}
+}
+
 func floatPtr(v float64) *float64 {
 	return &v
 }
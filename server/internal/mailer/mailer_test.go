@@ -0,0 +1,190 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/config"
+)
+
+// smtpSession is what the stand-in server received in one conversation
+type smtpSession struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPStandIn runs a minimal SMTP server on localhost that accepts one
+// conversation and reports what it received
+func startSMTPStandIn(t *testing.T) (string, int, <-chan smtpSession) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		var session smtpSession
+
+		reply("220 localhost ESMTP stand-in")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch verb {
+			case "EHLO", "HELO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				session.auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL":
+				session.from = line
+				reply("250 OK")
+			case "RCPT":
+				session.to = append(session.to, line)
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				session.data = data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				sessions <- session
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, sessions
+}
+
+func TestSMTPSenderDeliversThroughRelay(t *testing.T) {
+	host, port, sessions := startSMTPStandIn(t)
+
+	sender, err := NewSMTPSender(config.MailConfig{
+		SMTPHost:     host,
+		SMTPPort:     port,
+		SMTPUsername: "mailer",
+		SMTPPassword: "secret",
+		From:         "FlyingForge <no-reply@flyingforge.example>",
+		Timeout:      5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewSMTPSender: %v", err)
+	}
+
+	text := "Sign in to FlyingForge:\nhttps://flyingforge.example/auth/email?token=abc123\n"
+	err = sender.Send(context.Background(), Message{
+		To:      "pilot@example.com",
+		Subject: "Your FlyingForge sign-in link",
+		Text:    text,
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var session smtpSession
+	select {
+	case session = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stand-in server received no conversation")
+	}
+
+	auth, err := base64.StdEncoding.DecodeString(session.auth)
+	if err != nil || string(auth) != "\x00mailer\x00secret" {
+		t.Errorf("AUTH PLAIN = %q, want mailer credentials", auth)
+	}
+	if session.from != "MAIL FROM:<no-reply@flyingforge.example>" && !strings.HasPrefix(session.from, "MAIL FROM:<no-reply@flyingforge.example> ") {
+		t.Errorf("MAIL = %q", session.from)
+	}
+	if len(session.to) != 1 || session.to[0] != "RCPT TO:<pilot@example.com>" {
+		t.Errorf("RCPT = %v", session.to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("parse delivered message: %v", err)
+	}
+	if got := parsed.Header.Get("Subject"); got != "Your FlyingForge sign-in link" {
+		t.Errorf("Subject = %q", got)
+	}
+	if got := parsed.Header.Get("To"); got != "<pilot@example.com>" {
+		t.Errorf("To = %q", got)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != text {
+		t.Errorf("body = %q, want %q", got, text)
+	}
+}
+
+func TestSMTPSenderRejectsHeaderInjection(t *testing.T) {
+	sender, err := NewSMTPSender(config.MailConfig{SMTPHost: "127.0.0.1", SMTPPort: 1, From: "no-reply@flyingforge.example"})
+	if err != nil {
+		t.Fatalf("NewSMTPSender: %v", err)
+	}
+
+	err = sender.Send(context.Background(), Message{To: "pilot@example.com", Subject: "Hi\r\nBcc: victim@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "single line") {
+		t.Fatalf("expected multi-line subject to be rejected, got %v", err)
+	}
+
+	err = sender.Send(context.Background(), Message{To: "pilot@example.com\r\nBcc: victim@example.com", Subject: "Hi"})
+	if err == nil {
+		t.Fatal("expected malformed recipient to be rejected")
+	}
+}
+
+func TestNewSMTPSenderValidatesConfig(t *testing.T) {
+	if _, err := NewSMTPSender(config.MailConfig{From: "no-reply@flyingforge.example"}); err == nil {
+		t.Error("expected missing host to be rejected")
+	}
+	if _, err := NewSMTPSender(config.MailConfig{SMTPHost: "localhost", SMTPPort: 25, From: "not an address"}); err == nil {
+		t.Error("expected invalid sender address to be rejected")
+	}
+	sender, err := NewSMTPSender(config.MailConfig{SMTPHost: "localhost", SMTPPort: 25, From: "no-reply@flyingforge.example"})
+	if err != nil || sender.timeout != 10*time.Second {
+		t.Errorf("expected default timeout, got %v (err %v)", sender, err)
+	}
+}
@@ -0,0 +1,469 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+type GearCatalogReader interface {
+	Search(ctx context.Context, params models.GearCatalogSearchParams) (*models.GearCatalogSearchResponse, error)
+	Get(ctx context.Context, id string) (*models.GearCatalogItem, error)
+}
+
+type PublicBuildReader interface {
+	ListPublic(ctx context.Context, viewerUserID string, params models.BuildListParams) (*models.BuildListResponse, error)
+	GetPublic(ctx context.Context, id string, viewerUserID string) (*models.Build, error)
+}
+
+// BuildCost is the estimated MSRP of a build's catalog parts
+type BuildCost struct {
+	TotalMSRP     float64 `json:"totalMsrp"`
+	Currency      string  `json:"currency"`
+	PricedParts   int     `json:"pricedParts"`
+	UnpricedParts int     `json:"unpricedParts"`
+}
+
+// CatalogHandler handles public read-only MCP tool calls for the
+// crowd-sourced gear catalog and community builds. Only published catalog
+// items and published builds are ever returned.
+type CatalogHandler struct {
+	catalogReader GearCatalogReader
+	buildReader   PublicBuildReader
+	logger        *logging.Logger
+}
+
+// SetGearCatalogReader enables the search_gear_catalog and get_catalog_item tools
+func (h *Handler) SetGearCatalogReader(catalogReader GearCatalogReader) {
+	h.catalogReader = catalogReader
+}
+
+// SetPublicBuildReader enables the search_public_builds and get_public_build
+// tools, and find_builds_using_part when a gear catalog reader is also set
+func (h *Handler) SetPublicBuildReader(publicBuildReader PublicBuildReader) {
+	h.publicBuildReader = publicBuildReader
+}
+
+// NewCatalogHandler creates a new catalog handler.
+func NewCatalogHandler(catalogReader GearCatalogReader, buildReader PublicBuildReader, logger *logging.Logger) *CatalogHandler {
+	if catalogReader == nil && buildReader == nil {
+		return nil
+	}
+
+	return &CatalogHandler{
+		catalogReader: catalogReader,
+		buildReader:   buildReader,
+		logger:        logger,
+	}
+}
+
+// GetTools returns public read-only tool definitions for the catalog and builds.
+func (h *CatalogHandler) GetTools() []ToolDefinition {
+	if h == nil {
+		return nil
+	}
+
+	tools := []ToolDefinition{}
+	if h.catalogReader != nil {
+		tools = append(tools,
+			ToolDefinition{
+				Name:        "search_gear_catalog",
+				Title:       "Search the gear catalog",
+				Description: "Search published parts in the crowd-sourced FlyingForge gear catalog by name, gear type, brand or flying style.",
+				InputSchema: json.RawMessage(`{
+					"type": "object",
+					"properties": {
+						"query": {
+							"type": "string",
+							"description": "Search text matched against brand, model and variant (for example 'Nazgul' or '2207')."
+						},
+						"gearType": {
+							"type": "string",
+							"enum": ["motor", "esc", "fc", "aio", "stack", "frame", "vtx", "receiver", "antenna", "gps", "battery", "prop", "radio", "camera", "other"],
+							"description": "Optional gear type filter."
+						},
+						"brand": {
+							"type": "string",
+							"description": "Optional exact brand filter (case-insensitive)."
+						},
+						"bestFor": {
+							"type": "string",
+							"description": "Optional flying style the part is best for, such as freestyle, racing, cinematic or long-range."
+						},
+						"limit": {
+							"type": "integer",
+							"description": "Maximum number of results to return (default: 20, max: 100)."
+						},
+						"offset": {
+							"type": "integer",
+							"description": "Pagination offset."
+						}
+					}
+				}`),
+				SecuritySchemes: []SecurityScheme{{Type: "noauth"}},
+				Annotations:     &ToolAnnotations{ReadOnlyHint: true},
+			},
+			ToolDefinition{
+				Name:        "get_catalog_item",
+				Title:       "Get a catalog item",
+				Description: "Get one published gear catalog item with its specs, price and description.",
+				InputSchema: json.RawMessage(`{
+					"type": "object",
+					"properties": {
+						"id": {
+							"type": "string",
+							"description": "Catalog item ID from search_gear_catalog."
+						}
+					},
+					"required": ["id"]
+				}`),
+				SecuritySchemes: []SecurityScheme{{Type: "noauth"}},
+				Annotations:     &ToolAnnotations{ReadOnlyHint: true},
+			},
+		)
+	}
+	if h.buildReader != nil {
+		tools = append(tools,
+			ToolDefinition{
+				Name:        "search_public_builds",
+				Title:       "Search community builds",
+				Description: "Browse published community builds, newest first, optionally filtered by frame.",
+				InputSchema: json.RawMessage(`{
+					"type": "object",
+					"properties": {
+						"frame": {
+							"type": "string",
+							"description": "Optional frame filter matched against the frame's brand, model, variant or size (for example '5 inch' or 'Apex')."
+						},
+						"limit": {
+							"type": "integer",
+							"description": "Maximum number of results to return (default: 24, max: 100)."
+						},
+						"offset": {
+							"type": "integer",
+							"description": "Pagination offset."
+						}
+					}
+				}`),
+				SecuritySchemes: []SecurityScheme{{Type: "noauth"}},
+				Annotations:     &ToolAnnotations{ReadOnlyHint: true},
+			},
+			ToolDefinition{
+				Name:        "get_public_build",
+				Title:       "Get a community build",
+				Description: "Get one published community build with its parts and total MSRP.",
+				InputSchema: json.RawMessage(`{
+					"type": "object",
+					"properties": {
+						"id": {
+							"type": "string",
+							"description": "Build ID from search_public_builds."
+						}
+					},
+					"required": ["id"]
+				}`),
+				SecuritySchemes: []SecurityScheme{{Type: "noauth"}},
+				Annotations:     &ToolAnnotations{ReadOnlyHint: true},
+			},
+		)
+	}
+	if h.catalogReader != nil && h.buildReader != nil {
+		tools = append(tools, ToolDefinition{
+			Name:        "find_builds_using_part",
+			Title:       "Find builds using a part",
+			Description: "Find published community builds that use a given published gear catalog item.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"catalogItemId": {
+						"type": "string",
+						"description": "Catalog item ID from search_gear_catalog."
+					},
+					"limit": {
+						"type": "integer",
+						"description": "Maximum number of results to return (default: 24, max: 100)."
+					},
+					"offset": {
+						"type": "integer",
+						"description": "Pagination offset."
+					}
+				},
+				"required": ["catalogItemId"]
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "noauth"}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: true},
+		})
+	}
+	return tools
+}
+
+// HandleToolCall handles public catalog and build tool calls.
+func (h *CatalogHandler) HandleToolCall(ctx context.Context, name string, arguments json.RawMessage) (interface{}, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	switch {
+	case name == "search_gear_catalog" && h.catalogReader != nil:
+		return h.handleSearchGearCatalog(ctx, arguments)
+	case name == "get_catalog_item" && h.catalogReader != nil:
+		return h.handleGetCatalogItem(ctx, arguments)
+	case name == "search_public_builds" && h.buildReader != nil:
+		return h.handleSearchPublicBuilds(ctx, arguments)
+	case name == "get_public_build" && h.buildReader != nil:
+		return h.handleGetPublicBuild(ctx, arguments)
+	case name == "find_builds_using_part" && h.catalogReader != nil && h.buildReader != nil:
+		return h.handleFindBuildsUsingPart(ctx, arguments)
+	default:
+		return nil, nil
+	}
+}
+
+func (h *CatalogHandler) handleSearchGearCatalog(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var params struct {
+		Query    string `json:"query"`
+		GearType string `json:"gearType"`
+		Brand    string `json:"brand"`
+		BestFor  string `json:"bestFor"`
+		Limit    int    `json:"limit"`
+		Offset   int    `json:"offset"`
+	}
+
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &params); err != nil {
+			return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+		}
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	response, err := h.catalogReader.Search(ctx, models.GearCatalogSearchParams{
+		Query:    strings.TrimSpace(params.Query),
+		GearType: models.GearType(strings.TrimSpace(params.GearType)),
+		Brand:    strings.TrimSpace(params.Brand),
+		BestFor:  strings.TrimSpace(params.BestFor),
+		Status:   models.CatalogStatusPublished,
+		Limit:    params.Limit,
+		Offset:   params.Offset,
+	})
+	if err != nil {
+		return nil, &ToolError{Message: "Catalog search failed: " + err.Error()}
+	}
+	if response == nil {
+		response = &models.GearCatalogSearchResponse{}
+	}
+
+	items := make([]models.GearCatalogItem, 0, len(response.Items))
+	for _, item := range response.Items {
+		if models.NormalizeCatalogStatus(item.Status) != models.CatalogStatusPublished {
+			continue
+		}
+		items = append(items, publicCatalogItem(item))
+	}
+	response.Items = items
+
+	return ToolResultData{
+		StructuredContent: response,
+		Text:              fmt.Sprintf("Found %d published catalog items.", response.TotalCount),
+	}, nil
+}
+
+func (h *CatalogHandler) handleGetCatalogItem(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var params struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+
+	item, err := h.publishedCatalogItem(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToolResultData{
+		StructuredContent: item,
+		Text:              fmt.Sprintf("%s (%s).", item.DisplayName(), item.GearType),
+	}, nil
+}
+
+func (h *CatalogHandler) handleSearchPublicBuilds(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var params struct {
+		Frame  string `json:"frame"`
+		Limit  int    `json:"limit"`
+		Offset int    `json:"offset"`
+	}
+
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &params); err != nil {
+			return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+		}
+	}
+
+	response, err := h.buildReader.ListPublic(ctx, "", models.BuildListParams{
+		Sort:        models.BuildSortNewest,
+		FrameFilter: strings.TrimSpace(params.Frame),
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+	})
+	if err != nil {
+		return nil, &ToolError{Message: "Build search failed: " + err.Error()}
+	}
+
+	return publicBuildListResult(response, "published builds"), nil
+}
+
+func (h *CatalogHandler) handleGetPublicBuild(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var params struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+
+	buildID := strings.TrimSpace(params.ID)
+	if buildID == "" {
+		return nil, &ToolError{Message: "id is required"}
+	}
+
+	build, err := h.buildReader.GetPublic(ctx, buildID, "")
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to load build: " + err.Error()}
+	}
+	if build == nil || build.Status != models.BuildStatusPublished {
+		return nil, &ToolError{Message: "Build not found: " + buildID}
+	}
+
+	publicBuild := publicBuildView(*build)
+	cost := buildCost(publicBuild.Parts)
+
+	return ToolResultData{
+		StructuredContent: map[string]interface{}{
+			"build": publicBuild,
+			"cost":  cost,
+		},
+		Text: fmt.Sprintf("%s has %d parts totalling $%.2f MSRP (%d unpriced).",
+			publicBuild.Title, len(publicBuild.Parts), cost.TotalMSRP, cost.UnpricedParts),
+	}, nil
+}
+
+func (h *CatalogHandler) handleFindBuildsUsingPart(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+	var params struct {
+		CatalogItemID string `json:"catalogItemId"`
+		Limit         int    `json:"limit"`
+		Offset        int    `json:"offset"`
+	}
+
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+
+	item, err := h.publishedCatalogItem(ctx, params.CatalogItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.buildReader.ListPublic(ctx, "", models.BuildListParams{
+		Sort:          models.BuildSortNewest,
+		CatalogItemID: item.ID,
+		Limit:         params.Limit,
+		Offset:        params.Offset,
+	})
+	if err != nil {
+		return nil, &ToolError{Message: "Build search failed: " + err.Error()}
+	}
+
+	return publicBuildListResult(response, "published builds using "+item.DisplayName()), nil
+}
+
+// publishedCatalogItem loads a catalog item, treating anything not published
+// as missing so pending or removed submissions stay hidden
+func (h *CatalogHandler) publishedCatalogItem(ctx context.Context, id string) (*models.GearCatalogItem, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, &ToolError{Message: "catalog item id is required"}
+	}
+
+	item, err := h.catalogReader.Get(ctx, id)
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to load catalog item: " + err.Error()}
+	}
+	if item == nil || models.NormalizeCatalogStatus(item.Status) != models.CatalogStatusPublished {
+		return nil, &ToolError{Message: "Catalog item not found: " + id}
+	}
+
+	published := publicCatalogItem(*item)
+	return &published, nil
+}
+
+func publicBuildListResult(response *models.BuildListResponse, label string) ToolResultData {
+	if response == nil {
+		response = &models.BuildListResponse{}
+	}
+
+	builds := make([]models.Build, 0, len(response.Builds))
+	for _, build := range response.Builds {
+		if build.Status != models.BuildStatusPublished {
+			continue
+		}
+		builds = append(builds, publicBuildView(build))
+	}
+	response.Builds = builds
+
+	return ToolResultData{
+		StructuredContent: response,
+		Text:              fmt.Sprintf("Found %d %s.", response.TotalCount, label),
+	}
+}
+
+// publicCatalogItem drops the contributor and curator user IDs
+func publicCatalogItem(item models.GearCatalogItem) models.GearCatalogItem {
+	item.CreatedByUserID = ""
+	item.ImageCuratedByUserID = ""
+	item.DescriptionCuratedByUserID = ""
+	return item
+}
+
+// publicBuildView drops owner identifiers the public build pages do not need
+func publicBuildView(build models.Build) models.Build {
+	build.OwnerUserID = ""
+	build.SourceAircraftID = ""
+	build.ModerationReason = ""
+	return build
+}
+
+// buildCost counts each distinct catalog item once, matching the estimated
+// MSRP on the public build page
+func buildCost(parts []models.BuildPart) BuildCost {
+	cost := BuildCost{Currency: "USD"}
+	seen := make(map[string]struct{}, len(parts))
+	for _, part := range parts {
+		catalogItemID := strings.TrimSpace(part.CatalogItemID)
+		if catalogItemID == "" {
+			continue
+		}
+		if _, ok := seen[catalogItemID]; ok {
+			continue
+		}
+		seen[catalogItemID] = struct{}{}
+
+		if part.CatalogItem == nil || part.CatalogItem.MSRP == nil || *part.CatalogItem.MSRP <= 0 {
+			cost.UnpricedParts++
+			continue
+		}
+		cost.TotalMSRP += *part.CatalogItem.MSRP
+		cost.PricedParts++
+	}
+	return cost
+}
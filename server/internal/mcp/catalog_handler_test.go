@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+type stubGearCatalogReader struct {
+	items        map[string]models.GearCatalogItem
+	searchParams models.GearCatalogSearchParams
+}
+
+func (s *stubGearCatalogReader) Search(_ context.Context, params models.GearCatalogSearchParams) (*models.GearCatalogSearchResponse, error) {
+	s.searchParams = params
+	response := &models.GearCatalogSearchResponse{}
+	for _, item := range s.items {
+		response.Items = append(response.Items, item)
+	}
+	response.TotalCount = len(response.Items)
+	return response, nil
+}
+
+func (s *stubGearCatalogReader) Get(_ context.Context, id string) (*models.GearCatalogItem, error) {
+	item, ok := s.items[id]
+	if !ok {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+type stubPublicBuildReader struct {
+	builds     []models.Build
+	listParams models.BuildListParams
+}
+
+func (s *stubPublicBuildReader) ListPublic(_ context.Context, _ string, params models.BuildListParams) (*models.BuildListResponse, error) {
+	s.listParams = params
+	return &models.BuildListResponse{Builds: append([]models.Build(nil), s.builds...), TotalCount: len(s.builds)}, nil
+}
+
+func (s *stubPublicBuildReader) GetPublic(_ context.Context, id string, _ string) (*models.Build, error) {
+	for _, build := range s.builds {
+		if build.ID == id && build.Status == models.BuildStatusPublished {
+			return &build, nil
+		}
+	}
+	return nil, nil
+}
+
+func newCatalogTestHandler() (*Handler, *stubGearCatalogReader, *stubPublicBuildReader) {
+	catalogReader := &stubGearCatalogReader{items: map[string]models.GearCatalogItem{
+		"frame-1": {ID: "frame-1", GearType: models.GearTypeFrame, Brand: "ImpulseRC", Model: "Apex", Status: models.CatalogStatusPublished, CreatedByUserID: "user-9"},
+		"frame-2": {ID: "frame-2", GearType: models.GearTypeFrame, Brand: "Pending", Model: "Frame", Status: models.CatalogStatusPending},
+	}}
+	buildReader := &stubPublicBuildReader{builds: []models.Build{{
+		ID:          "build-1",
+		OwnerUserID: "user-1",
+		Status:      models.BuildStatusPublished,
+		Title:       "Apex HD",
+		Parts: []models.BuildPart{
+			{GearType: models.GearTypeFrame, CatalogItemID: "frame-1", CatalogItem: &models.BuildCatalogItem{ID: "frame-1", MSRP: msrp(90)}},
+			{GearType: models.GearTypeMotor, CatalogItemID: "motor-1", Position: 0, CatalogItem: &models.BuildCatalogItem{ID: "motor-1", MSRP: msrp(25)}},
+			{GearType: models.GearTypeMotor, CatalogItemID: "motor-1", Position: 1, CatalogItem: &models.BuildCatalogItem{ID: "motor-1", MSRP: msrp(25)}},
+			{GearType: models.GearTypeVTX, CatalogItemID: "vtx-1", CatalogItem: &models.BuildCatalogItem{ID: "vtx-1"}},
+		},
+	}}}
+
+	handler := newWriteTestHandler()
+	handler.SetGearCatalogReader(catalogReader)
+	handler.SetPublicBuildReader(buildReader)
+	return handler, catalogReader, buildReader
+}
+
+func TestCatalogToolsArePublicAndReadOnly(t *testing.T) {
+	handler, _, _ := newCatalogTestHandler()
+
+	found := map[string]bool{}
+	for _, tool := range handler.GetTools() {
+		switch tool.Name {
+		case "search_gear_catalog", "get_catalog_item", "search_public_builds", "get_public_build", "find_builds_using_part":
+		default:
+			continue
+		}
+		if handler.IsPrivateTool(tool.Name) {
+			t.Fatalf("expected %s to be public", tool.Name)
+		}
+		if tool.Annotations == nil || !tool.Annotations.ReadOnlyHint || tool.SecuritySchemes[0].Type != "noauth" {
+			t.Fatalf("expected %s to be a noauth read-only tool", tool.Name)
+		}
+		found[tool.Name] = true
+	}
+	if len(found) != 5 {
+		t.Fatalf("expected all catalog tools to be listed, got %v", found)
+	}
+
+	withoutBuilds := newWriteTestHandler()
+	withoutBuilds.SetGearCatalogReader(&stubGearCatalogReader{})
+	for _, tool := range withoutBuilds.GetTools() {
+		if tool.Name == "find_builds_using_part" || tool.Name == "search_public_builds" {
+			t.Fatalf("expected %s not to be listed without a build reader", tool.Name)
+		}
+	}
+}
+
+func TestSearchGearCatalogForcesPublishedStatus(t *testing.T) {
+	handler, catalogReader, _ := newCatalogTestHandler()
+
+	result, err := handler.HandleToolCall(context.Background(), "search_gear_catalog", json.RawMessage(`{"gearType":"frame","bestFor":" freestyle ","status":"pending"}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if catalogReader.searchParams.Status != models.CatalogStatusPublished || catalogReader.searchParams.BestFor != "freestyle" {
+		t.Fatalf("unexpected search params: %+v", catalogReader.searchParams)
+	}
+	response := result.(ToolResultData).StructuredContent.(*models.GearCatalogSearchResponse)
+	if len(response.Items) != 1 || response.Items[0].ID != "frame-1" {
+		t.Fatalf("expected only the published item, got %+v", response.Items)
+	}
+	if response.Items[0].CreatedByUserID != "" {
+		t.Fatalf("expected contributor ID to be omitted, got %q", response.Items[0].CreatedByUserID)
+	}
+}
+
+func TestGetCatalogItemHidesUnpublishedItems(t *testing.T) {
+	handler, _, _ := newCatalogTestHandler()
+
+	if _, err := handler.HandleToolCall(context.Background(), "get_catalog_item", json.RawMessage(`{"id":"frame-2"}`)); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected pending item to be reported as not found, got %v", err)
+	}
+
+	result, err := handler.HandleToolCall(context.Background(), "get_catalog_item", json.RawMessage(`{"id":"frame-1"}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if item := result.(ToolResultData).StructuredContent.(*models.GearCatalogItem); item.ID != "frame-1" || item.CreatedByUserID != "" {
+		t.Fatalf("unexpected catalog item: %+v", item)
+	}
+}
+
+func TestGetPublicBuildTotalsDistinctPartPrices(t *testing.T) {
+	handler, _, _ := newCatalogTestHandler()
+
+	result, err := handler.HandleToolCall(context.Background(), "get_public_build", json.RawMessage(`{"id":"build-1"}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	payload := result.(ToolResultData).StructuredContent.(map[string]interface{})
+	if build := payload["build"].(models.Build); build.OwnerUserID != "" || len(build.Parts) != 4 {
+		t.Fatalf("unexpected build: %+v", build)
+	}
+	cost := payload["cost"].(BuildCost)
+	if cost.TotalMSRP != 115 || cost.PricedParts != 2 || cost.UnpricedParts != 1 {
+		t.Fatalf("unexpected cost: %+v", cost)
+	}
+
+	if _, err := handler.HandleToolCall(context.Background(), "get_public_build", json.RawMessage(`{"id":"build-2"}`)); err == nil {
+		t.Fatal("expected unknown build to fail")
+	}
+}
+
+func TestFindBuildsUsingPartRequiresPublishedPart(t *testing.T) {
+	handler, _, buildReader := newCatalogTestHandler()
+
+	if _, err := handler.HandleToolCall(context.Background(), "find_builds_using_part", json.RawMessage(`{"catalogItemId":"frame-2"}`)); err == nil {
+		t.Fatal("expected unpublished part to be rejected")
+	}
+	if buildReader.listParams.CatalogItemID != "" {
+		t.Fatal("expected builds not to be searched for an unpublished part")
+	}
+
+	result, err := handler.HandleToolCall(context.Background(), "find_builds_using_part", json.RawMessage(`{"catalogItemId":" frame-1 "}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if buildReader.listParams.CatalogItemID != "frame-1" {
+		t.Fatalf("unexpected list params: %+v", buildReader.listParams)
+	}
+	response := result.(ToolResultData).StructuredContent.(*models.BuildListResponse)
+	if len(response.Builds) != 1 || response.Builds[0].OwnerUserID != "" {
+		t.Fatalf("unexpected builds: %+v", response.Builds)
+	}
+}
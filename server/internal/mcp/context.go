@@ -2,12 +2,19 @@ package mcp
 
 import "context"
 
-type authContextKey string
+type contextKey string
 
-const requestAuthKey authContextKey = "mcpRequestAuth"
+const (
+	requestAuthKey contextKey = "mcpRequestAuth"
+	notifierKey    contextKey = "mcpNotifier"
+	cancelScopeKey contextKey = "mcpCancelScope"
+	progressKey    contextKey = "mcpProgress"
+	sessionsKey    contextKey = "mcpSessions"
+)
 
 type RequestAuth struct {
 	UserID             string
+	Scopes             []string
 	Challenge          string
 	ChallengeMessage   string
 	ChallengeErrorCode string
@@ -17,7 +24,84 @@ func WithRequestAuth(ctx context.Context, auth RequestAuth) context.Context {
 	return context.WithValue(ctx, requestAuthKey, auth)
 }
 
+// HasScope reports whether the request's access token was granted scope.
+func (a RequestAuth) HasScope(scope string) bool {
+	for _, granted := range a.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
 func RequestAuthFromContext(ctx context.Context) RequestAuth {
 	auth, _ := ctx.Value(requestAuthKey).(RequestAuth)
 	return auth
 }
+
+// NotifyFunc sends a notification to the client over the transport that
+// carried the current request
+type NotifyFunc func(notification Notification)
+
+// WithNotifier lets request handling send notifications, such as progress,
+// back to the client
+func WithNotifier(ctx context.Context, notify NotifyFunc) context.Context {
+	return context.WithValue(ctx, notifierKey, notify)
+}
+
+// WithCancelScope names the client a request came from. Request IDs are only
+// unique per client, so notifications/cancelled only reaches in-flight
+// requests from the same scope. Requests without a scope cannot be cancelled.
+func WithCancelScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, cancelScopeKey, scope)
+}
+
+func cancelScopeFromContext(ctx context.Context) string {
+	scope, _ := ctx.Value(cancelScopeKey).(string)
+	return scope
+}
+
+// withSessionNotifications marks a request from a transport that can deliver
+// notifications outside a request, over a session's GET stream
+func withSessionNotifications(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionsKey, true)
+}
+
+func sessionNotificationsFromContext(ctx context.Context) bool {
+	enabled, _ := ctx.Value(sessionsKey).(bool)
+	return enabled
+}
+
+type progressState struct {
+	token  interface{}
+	notify NotifyFunc
+}
+
+// withProgressToken enables reportProgress for a request that supplied a
+// progress token, if the transport can send notifications
+func withProgressToken(ctx context.Context, token interface{}) context.Context {
+	notify, _ := ctx.Value(notifierKey).(NotifyFunc)
+	if token == nil || notify == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey, progressState{token: token, notify: notify})
+}
+
+// reportProgress sends notifications/progress when the caller asked for it.
+// A total of zero means the total is unknown.
+func reportProgress(ctx context.Context, progress, total float64, message string) {
+	state, ok := ctx.Value(progressKey).(progressState)
+	if !ok {
+		return
+	}
+	state.notify(Notification{
+		JSONRPC: "2.0",
+		Method:  "notifications/progress",
+		Params: ProgressParams{
+			ProgressToken: state.token,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		},
+	})
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+type FlightLogger interface {
+	LogSession(ctx context.Context, userID string, params models.CreateFlightSessionParams) (*models.FlightSession, error)
+}
+
+// SetFlightLogger enables the log_flight_session tool
+func (h *Handler) SetFlightLogger(flightLogger FlightLogger) {
+	h.flightLogger = flightLogger
+}
+
+func (h *Handler) getFlightTools() []ToolDefinition {
+	if h.flightLogger == nil {
+		return nil
+	}
+
+	return []ToolDefinition{
+		{
+			Name:        "log_flight_session",
+			Title:       "Log flight session",
+			Description: "Log a flying session for one of the linked user's aircraft. Each battery listed gets a cycle logged against it, and the session counts toward the aircraft's and its components' flight time.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"aircraftId": {
+						"type": "string",
+						"description": "The FlyingForge aircraft ID."
+					},
+					"batteryIds": {
+						"type": "array",
+						"items": { "type": "string" },
+						"description": "Battery IDs used during the session. List a battery once per pack flown."
+					},
+					"flightCount": {
+						"type": "integer",
+						"description": "Number of flights (default: one per battery listed, or 1)."
+					},
+					"durationMinutes": {
+						"type": "number",
+						"description": "Total air time across all flights, in minutes."
+					},
+					"location": {
+						"type": "string",
+						"description": "Where the session was flown, for example \"Local field\"."
+					},
+					"notes": {
+						"type": "string",
+						"description": "Optional session notes."
+					},
+					"flownAt": {
+						"type": "string",
+						"description": "RFC 3339 date/time of the session (default: now)."
+					}
+				},
+				"required": ["aircraftId"]
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "oauth2", Scopes: h.writeScopes("log_flight_session")}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: false},
+		},
+	}
+}
+
+func (h *Handler) handleLogFlightSession(ctx context.Context, userID string, arguments json.RawMessage) (interface{}, error) {
+	if h.flightLogger == nil {
+		return nil, &ToolError{Message: "Flight log service is unavailable"}
+	}
+
+	var params struct {
+		AircraftID      string   `json:"aircraftId"`
+		BatteryIDs      []string `json:"batteryIds"`
+		FlightCount     int      `json:"flightCount"`
+		DurationMinutes float64  `json:"durationMinutes"`
+		Location        string   `json:"location"`
+		Notes           string   `json:"notes"`
+		FlownAt         string   `json:"flownAt"`
+	}
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+	if strings.TrimSpace(params.AircraftID) == "" {
+		return nil, &ToolError{Message: "aircraftId is required"}
+	}
+
+	createParams := models.CreateFlightSessionParams{
+		AircraftID:      strings.TrimSpace(params.AircraftID),
+		Location:        params.Location,
+		FlightCount:     params.FlightCount,
+		DurationSeconds: int(params.DurationMinutes*60 + 0.5),
+		Notes:           params.Notes,
+	}
+	if flownAt := strings.TrimSpace(params.FlownAt); flownAt != "" {
+		parsed, err := time.Parse(time.RFC3339, flownAt)
+		if err != nil {
+			return nil, &ToolError{Message: "flownAt must be an RFC 3339 date/time"}
+		}
+		createParams.FlownAt = &parsed
+	}
+	for _, batteryID := range params.BatteryIDs {
+		createParams.Batteries = append(createParams.Batteries, models.FlightBatteryParams{BatteryID: batteryID})
+	}
+
+	session, err := h.flightLogger.LogSession(ctx, userID, createParams)
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to log flight session: " + err.Error()}
+	}
+	session.UserID = ""
+
+	return ToolResultData{
+		StructuredContent: session,
+		Text:              fmt.Sprintf("Logged %d flight(s) with %d battery pack(s).", session.FlightCount, len(session.Batteries)),
+	}, nil
+}
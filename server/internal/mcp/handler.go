@@ -7,6 +7,7 @@ import (
 	"github.com/johnrirwin/flyingforge/internal/aggregator"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/ratelimit"
 )
 
 type EquipmentReader interface {
@@ -37,8 +38,27 @@ type Handler struct {
 	aircraftSvc   AircraftReader
 	radioSvc      RadioReader
 	tuningReader  AircraftTuningReader
+	flightLogger  FlightLogger
 	privateScopes []string
 	logger        *logging.Logger
+
+	batteryLogger   BatteryCycleLogger
+	inventoryWriter InventoryWriter
+	componentWriter AircraftComponentWriter
+	fcConfigSaver   FCConfigSaver
+	buildCreator    BuildDraftCreator
+
+	fcConfigReader   FCConfigReader
+	buildReader      BuildReader
+	resourcesChanged func(ctx context.Context, userID string)
+
+	catalogSearcher CatalogSearcher
+	batteryReader   BatteryHistoryReader
+
+	catalogReader     GearCatalogReader
+	publicBuildReader PublicBuildReader
+
+	refreshLimiter ratelimit.RateLimiter
 }
 
 func NewHandler(
@@ -65,11 +85,22 @@ func NewHandler(
 	}
 }
 
+// mcpNewsRefreshKey is the refresh limiter key shared by every MCP client;
+// get_drone_news is public, so there is no client identity to key on
+const mcpNewsRefreshKey = "mcp"
+
+// SetNewsRefreshLimiter enables get_drone_news refresh. It is off unless
+// manual refresh is enabled and shares the limiter that guards /api/refresh.
+func (h *Handler) SetNewsRefreshLimiter(limiter ratelimit.RateLimiter) {
+	h.refreshLimiter = limiter
+}
+
 type GetNewsParams struct {
 	Limit   int      `json:"limit"`
 	Sources []string `json:"sources"`
 	Tag     string   `json:"tag"`
 	Query   string   `json:"query"`
+	Refresh bool     `json:"refresh"`
 }
 
 func (h *Handler) GetTools() []ToolDefinition {
@@ -97,7 +128,7 @@ func (h *Handler) GetTools() []ToolDefinition {
 					"query": {
 						"type": "string",
 						"description": "Search query to filter items"
-					}
+					}` + h.newsRefreshProperty() + `
 				}
 			}`),
 			SecuritySchemes: []SecurityScheme{{Type: "noauth"}},
@@ -116,7 +147,12 @@ func (h *Handler) GetTools() []ToolDefinition {
 	if equipmentHandler := NewEquipmentHandler(h.equipmentSvc, h.logger); equipmentHandler != nil {
 		tools = append(tools, equipmentHandler.GetTools()...)
 	}
+	if catalogHandler := NewCatalogHandler(h.catalogReader, h.publicBuildReader, h.logger); catalogHandler != nil {
+		tools = append(tools, catalogHandler.GetTools()...)
+	}
 	tools = append(tools, h.getPrivateReadOnlyTools()...)
+	tools = append(tools, h.getFlightTools()...)
+	tools = append(tools, h.getWriteTools()...)
 
 	return tools
 }
@@ -128,6 +164,12 @@ func (h *Handler) HandleToolCall(ctx context.Context, name string, arguments jso
 			return result, err
 		}
 	}
+	if catalogHandler := NewCatalogHandler(h.catalogReader, h.publicBuildReader, h.logger); catalogHandler != nil {
+		result, err := catalogHandler.HandleToolCall(ctx, name, arguments)
+		if result != nil || err != nil {
+			return result, err
+		}
+	}
 
 	if result, err := h.handlePrivateToolCall(ctx, name, arguments); result != nil || err != nil {
 		return result, err
@@ -151,7 +193,13 @@ func (h *Handler) IsPrivateTool(name string) bool {
 		"get_aircraft_tuning",
 		"list_my_radios",
 		"get_radio_details",
-		"list_radio_backups":
+		"list_radio_backups",
+		"log_flight_session",
+		"log_battery_cycle",
+		"add_inventory_item",
+		"set_aircraft_component",
+		"save_fc_config",
+		"create_build_draft":
 		return true
 	default:
 		return false
@@ -170,6 +218,28 @@ func (h *Handler) handleGetNews(ctx context.Context, arguments json.RawMessage)
 		params.Limit = 20
 	}
 
+	text := "Fetched the latest FlyingForge drone news items."
+	if params.Refresh && h.refreshLimiter == nil {
+		return nil, &ToolError{Message: "Refreshing news is not enabled on this server"}
+	}
+	if params.Refresh && !h.refreshLimiter.Allow(mcpNewsRefreshKey) {
+		params.Refresh = false
+		text = "News was refreshed recently, so these are the cached FlyingForge drone news items."
+	}
+
+	if params.Refresh {
+		err := h.agg.RefreshWithProgress(ctx, func(done, total int, source string) {
+			reportProgress(ctx, float64(done), float64(total), "Fetched "+source)
+		})
+		if ctx.Err() != nil {
+			return nil, &ToolError{Message: "News refresh was cancelled"}
+		}
+		if err != nil {
+			// Fetched items are already in memory; only persisting them failed
+			h.logger.Warn("News refresh failed", logging.WithField("error", err.Error()))
+		}
+	}
+
 	filterParams := models.FilterParams{
 		Limit:   params.Limit,
 		Sources: params.Sources,
@@ -180,10 +250,23 @@ func (h *Handler) handleGetNews(ctx context.Context, arguments json.RawMessage)
 	response := h.agg.GetItems(ctx, filterParams)
 	return ToolResultData{
 		StructuredContent: response,
-		Text:              "Fetched the latest FlyingForge drone news items.",
+		Text:              text,
 	}, nil
 }
 
+// newsRefreshProperty is get_drone_news's refresh argument, offered only
+// when refresh is enabled
+func (h *Handler) newsRefreshProperty() string {
+	if h.refreshLimiter == nil {
+		return ""
+	}
+	return `,
+					"refresh": {
+						"type": "boolean",
+						"description": "Fetch every news source before answering instead of using cached items. Slow, and rate limited across all clients; reports progress when the caller supplies a progress token."
+					}`
+}
+
 func (h *Handler) handleGetSources(ctx context.Context) (interface{}, error) {
 	sources := h.agg.GetSources()
 	payload := map[string]interface{}{
@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 
 	appauth "github.com/johnrirwin/flyingforge/internal/auth"
 	"github.com/johnrirwin/flyingforge/internal/logging"
@@ -20,8 +22,8 @@ type authCodeError interface {
 
 type HTTPAuthProvider interface {
 	Enabled() bool
-	AuthenticateBearerToken(ctx context.Context, token string) (string, error)
-	Challenge(errorCode, description string) string
+	AuthenticateBearerToken(ctx context.Context, token string) (*appauth.MCPPrincipal, error)
+	Challenge(errorCode, description string, scopes ...string) string
 	ProtectedResourceMetadata() *appauth.MCPProtectedResourceMetadata
 }
 
@@ -30,6 +32,9 @@ type HTTPHandler struct {
 	authProvider   HTTPAuthProvider
 	allowedOrigins map[string]struct{}
 	logger         *logging.Logger
+
+	sessions SessionStore
+	hub      *sessionHub
 }
 
 func NewHTTPHandler(protocol *Protocol, authProvider HTTPAuthProvider, allowedOrigins []string, logger *logging.Logger) *HTTPHandler {
@@ -46,6 +51,7 @@ func NewHTTPHandler(protocol *Protocol, authProvider HTTPAuthProvider, allowedOr
 		authProvider:   authProvider,
 		allowedOrigins: originSet,
 		logger:         logger,
+		hub:            newSessionHub(),
 	}
 }
 
@@ -64,11 +70,9 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		h.handlePost(w, r)
 	case http.MethodGet:
-		w.Header().Set("Allow", "POST, GET, DELETE, OPTIONS")
-		http.Error(w, "streaming GET not supported", http.StatusMethodNotAllowed)
+		h.handleGet(w, r)
 	case http.MethodDelete:
-		w.Header().Set("Allow", "POST, GET, DELETE, OPTIONS")
-		http.Error(w, "session termination is not supported", http.StatusMethodNotAllowed)
+		h.handleDelete(w, r)
 	default:
 		w.Header().Set("Allow", "POST, GET, DELETE, OPTIONS")
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -110,55 +114,141 @@ func (h *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	requests := payloadRequests(body)
+	isInitialize := body[0] == '{' && len(requests) == 1 && requests[0].Method == "initialize"
+	var session *Session
+	if !isInitialize {
+		var ok bool
+		if session, ok = h.resolveSession(w, r); !ok {
+			return
+		}
+	}
+
 	ctx := r.Context()
 	authState := RequestAuth{}
-	if h.requestNeedsAuthentication(body) {
-		authState = h.authenticateRequest(ctx, r.Header.Get("Authorization"))
+	needsAuth, scopes := h.authRequirement(requests)
+	switch {
+	case needsAuth, session != nil && session.UserID != "":
+		authState = h.authenticateRequest(ctx, r.Header.Get("Authorization"), scopes...)
+	case isInitialize && h.sessions != nil && bearerToken(r.Header.Get("Authorization")) != "":
+		// A token on initialize binds the new session without challenging
+		if initAuth := h.authenticateRequest(ctx, r.Header.Get("Authorization")); initAuth.Challenge == "" {
+			authState = initAuth
+		}
+	}
+	if !h.claimSession(w, r, session, authState) {
+		return
 	}
 	ctx = WithRequestAuth(ctx, authState)
+	if session != nil {
+		// notifications/cancelled arrives in its own POST, so only requests
+		// sent with a session can be matched to it and cancelled
+		ctx = WithCancelScope(ctx, session.ID)
+	}
+	if h.sessions != nil {
+		ctx = withSessionNotifications(ctx)
+	}
+	if authState.Challenge != "" {
+		w.Header().Set("WWW-Authenticate", authState.Challenge)
+	}
 
-	response := h.protocol.HandleMessage(ctx, body)
+	if flusher, ok := w.(http.Flusher); ok && acceptsEventStream(r) && requestsProgress(requests) {
+		h.streamPost(ctx, w, flusher, body)
+		return
+	}
+
+	response := h.protocol.HandlePayload(ctx, body)
+	if isInitialize {
+		initializeResponse, _ := response.(*Response)
+		h.startSession(w, r, initializeResponse, authState)
+	}
 	if response == nil {
-		if authState.Challenge != "" {
-			w.Header().Set("WWW-Authenticate", authState.Challenge)
-		}
 		w.WriteHeader(http.StatusAccepted)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if authState.Challenge != "" {
-		w.Header().Set("WWW-Authenticate", authState.Challenge)
-	}
 	writeJSON(w, http.StatusOK, response)
 }
 
-func (h *HTTPHandler) requestNeedsAuthentication(body []byte) bool {
-	if h.authProvider == nil || !h.authProvider.Enabled() {
-		return false
+// streamPost answers with an SSE stream so progress notifications can be
+// sent while the request runs. The JSON-RPC response is the last event. The
+// server's timeouts are lifted so a long tool call isn't cut off.
+func (h *HTTPHandler) streamPost(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, body []byte) {
+	clearStreamDeadlines(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var writeMu sync.Mutex
+	writeEvent := func(message interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		flusher.Flush()
 	}
 
-	var req Request
-	if err := json.Unmarshal(body, &req); err != nil {
-		return false
-	}
-	if req.Method != "tools/call" {
-		return false
+	ctx = WithNotifier(ctx, func(notification Notification) {
+		writeEvent(notification)
+	})
+	if response := h.protocol.HandlePayload(ctx, body); response != nil {
+		writeEvent(response)
 	}
+}
 
-	var params CallToolParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return false
+// authRequirement reports whether any request in a payload reads or changes
+// user data and so needs a linked account, plus the scopes needed beyond the
+// read scopes. Private tool calls, resource listing/reading and private
+// prompts need authentication.
+func (h *HTTPHandler) authRequirement(requests []Request) (bool, []string) {
+	if h.authProvider == nil || !h.authProvider.Enabled() || h.protocol == nil || h.protocol.handler == nil {
+		return false, nil
 	}
 
-	if h.protocol == nil || h.protocol.handler == nil {
-		return false
+	needsAuth := false
+	scopes := []string{}
+	for _, req := range requests {
+		required, scope := h.requestAuthRequirement(req)
+		needsAuth = needsAuth || required
+		if scope != "" && !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
 	}
+	return needsAuth, scopes
+}
 
-	return h.protocol.handler.IsPrivateTool(params.Name)
+func (h *HTTPHandler) requestAuthRequirement(req Request) (bool, string) {
+	switch req.Method {
+	case "resources/list", "resources/read":
+		return true, ""
+	case "prompts/get":
+		var params GetPromptParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return false, ""
+		}
+		return h.protocol.handler.IsPrivatePrompt(params.Name), ""
+	case "tools/call":
+		var params CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return false, ""
+		}
+		if !h.protocol.handler.IsPrivateTool(params.Name) {
+			return false, ""
+		}
+		return true, h.protocol.handler.ToolScope(params.Name)
+	default:
+		return false, ""
+	}
 }
 
-func (h *HTTPHandler) authenticateRequest(ctx context.Context, authorizationHeader string) RequestAuth {
+// authenticateRequest resolves the bearer token. When the token is valid but
+// lacks some of toolScopes, the user is still set and an insufficient_scope
+// challenge asks the client to re-authorize with the missing scopes.
+func (h *HTTPHandler) authenticateRequest(ctx context.Context, authorizationHeader string, toolScopes ...string) RequestAuth {
 	if h.authProvider == nil || !h.authProvider.Enabled() {
 		return RequestAuth{}
 	}
@@ -172,9 +262,22 @@ func (h *HTTPHandler) authenticateRequest(ctx context.Context, authorizationHead
 		}
 	}
 
-	userID, err := h.authProvider.AuthenticateBearerToken(ctx, token)
+	principal, err := h.authProvider.AuthenticateBearerToken(ctx, token)
 	if err == nil {
-		return RequestAuth{UserID: userID}
+		authState := RequestAuth{UserID: principal.UserID, Scopes: principal.Scopes}
+		missing := []string{}
+		for _, scope := range toolScopes {
+			if scope != "" && !principal.HasScope(scope) {
+				missing = append(missing, scope)
+			}
+		}
+		if len(missing) > 0 {
+			message := missingScopeMessage(missing[0])
+			authState.Challenge = h.authProvider.Challenge("insufficient_scope", message, missing...)
+			authState.ChallengeMessage = message
+			authState.ChallengeErrorCode = "insufficient_scope"
+		}
+		return authState
 	}
 
 	code := "invalid_token"
@@ -210,6 +313,59 @@ func (h *HTTPHandler) setCORSHeaders(w http.ResponseWriter, origin string) {
 	w.Header().Set("Vary", "Origin")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, GET, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, Last-Event-ID, MCP-Session-Id")
+	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+}
+
+// payloadRequests decodes a single message or a batch array. Elements that
+// are not valid requests are skipped; the protocol reports them.
+func payloadRequests(body []byte) []Request {
+	if len(body) > 0 && body[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return nil
+		}
+		requests := make([]Request, 0, len(messages))
+		for _, message := range messages {
+			var req Request
+			if err := json.Unmarshal(message, &req); err == nil {
+				requests = append(requests, req)
+			}
+		}
+		return requests
+	}
+
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil
+	}
+	return []Request{req}
+}
+
+// requestsProgress reports whether any tool call supplied a progress token
+func requestsProgress(requests []Request) bool {
+	for _, req := range requests {
+		if req.Method != "tools/call" || req.ID == nil {
+			continue
+		}
+		var params CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err == nil && params.Meta != nil && params.Meta.ProgressToken != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
 }
 
 func readAllJSON(r *http.Request) ([]byte, error) {
@@ -0,0 +1,339 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+)
+
+const sessionHeader = "Mcp-Session-Id"
+
+const (
+	// sessionPollInterval bounds how late a stream sees events appended by
+	// another instance sharing the session store
+	sessionPollInterval      = 2 * time.Second
+	sessionKeepAliveInterval = 25 * time.Second
+)
+
+// sessionHub wakes this instance's SSE streams when an event is queued
+type sessionHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func newSessionHub() *sessionHub {
+	return &sessionHub{subscribers: make(map[string]map[chan struct{}]struct{})}
+}
+
+func (h *sessionHub) subscribe(sessionID string) (chan struct{}, func()) {
+	wake := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subscribers[sessionID] == nil {
+		h.subscribers[sessionID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[sessionID][wake] = struct{}{}
+	h.mu.Unlock()
+
+	return wake, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[sessionID], wake)
+		if len(h.subscribers[sessionID]) == 0 {
+			delete(h.subscribers, sessionID)
+		}
+	}
+}
+
+func (h *sessionHub) signal(sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for wake := range h.subscribers[sessionID] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetSessionStore enables Streamable HTTP sessions: initialize issues an
+// Mcp-Session-Id, GET opens an SSE stream for server notifications and
+// DELETE ends the session. Sessions are bound to the first user that
+// authenticates on them. Without a store the endpoint stays stateless.
+func (h *HTTPHandler) SetSessionStore(sessions SessionStore) {
+	h.sessions = sessions
+}
+
+// Notify queues a JSON-RPC notification on one session's SSE stream
+func (h *HTTPHandler) Notify(ctx context.Context, sessionID string, method string, params interface{}) error {
+	if h.sessions == nil {
+		return ErrSessionNotFound
+	}
+
+	data, err := json.Marshal(Notification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if _, err := h.sessions.AppendEvent(ctx, sessionID, data); err != nil {
+		return err
+	}
+	h.hub.signal(sessionID)
+	return nil
+}
+
+// NotifyUser queues a notification on every session bound to the user
+func (h *HTTPHandler) NotifyUser(ctx context.Context, userID string, method string, params interface{}) error {
+	if h.sessions == nil || userID == "" {
+		return nil
+	}
+
+	sessionIDs, err := h.sessions.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, sessionID := range sessionIDs {
+		if err := h.Notify(ctx, sessionID, method, params); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResourcesChanged tells the user's sessions that resources/list would now
+// answer differently. It is the Handler's resource change notifier.
+func (h *HTTPHandler) ResourcesChanged(ctx context.Context, userID string) {
+	if err := h.NotifyUser(ctx, userID, "notifications/resources/list_changed", nil); err != nil {
+		h.logSessionError("Failed to queue MCP resource change notification", err)
+	}
+}
+
+// resolveSession checks the Mcp-Session-Id on a non-initialize request.
+// Requests without the header are served statelessly with a nil session;
+// unknown sessions get a 404 so the client starts over with initialize.
+func (h *HTTPHandler) resolveSession(w http.ResponseWriter, r *http.Request) (*Session, bool) {
+	sessionID := strings.TrimSpace(r.Header.Get(sessionHeader))
+	if h.sessions == nil || sessionID == "" {
+		return nil, true
+	}
+
+	session, err := h.sessions.Get(r.Context(), sessionID)
+	if err != nil {
+		h.logSessionError("Failed to load MCP session", err)
+		http.Error(w, "session lookup failed", http.StatusInternalServerError)
+		return nil, false
+	}
+	if session == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return nil, false
+	}
+	return session, true
+}
+
+// claimSession checks that a request on a session comes from the user the
+// session is bound to, so a leaked Mcp-Session-Id is useless without that
+// user's token. An unbound session is bound to the first user that
+// authenticates on it.
+func (h *HTTPHandler) claimSession(w http.ResponseWriter, r *http.Request, session *Session, authState RequestAuth) bool {
+	if session == nil {
+		return true
+	}
+
+	if session.UserID == "" && authState.UserID != "" {
+		bound, err := h.sessions.BindUser(r.Context(), session.ID, authState.UserID)
+		if errors.Is(err, ErrSessionNotFound) {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return false
+		}
+		if err != nil {
+			h.logSessionError("Failed to bind MCP session", err)
+			http.Error(w, "session lookup failed", http.StatusInternalServerError)
+			return false
+		}
+		session = bound
+	}
+
+	switch {
+	case session.UserID == "" || session.UserID == authState.UserID:
+		return true
+	case authState.UserID == "":
+		if authState.Challenge != "" {
+			w.Header().Set("WWW-Authenticate", authState.Challenge)
+		}
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return false
+	default:
+		http.Error(w, "session not found", http.StatusNotFound)
+		return false
+	}
+}
+
+// sessionAuth authenticates a GET or DELETE on a session bound to a user
+func (h *HTTPHandler) sessionAuth(r *http.Request, session *Session) RequestAuth {
+	if session == nil || session.UserID == "" {
+		return RequestAuth{}
+	}
+	return h.authenticateRequest(r.Context(), r.Header.Get("Authorization"))
+}
+
+// startSession issues a session for a successful initialize response, bound
+// to the user when the initialize request carried a valid token
+func (h *HTTPHandler) startSession(w http.ResponseWriter, r *http.Request, response *Response, authState RequestAuth) {
+	if h.sessions == nil || response == nil || response.Error != nil {
+		return
+	}
+
+	session, err := h.sessions.Create(r.Context(), protocolVersion, authState.UserID)
+	if err != nil {
+		h.logSessionError("Failed to create MCP session", err)
+		return
+	}
+	w.Header().Set(sessionHeader, session.ID)
+}
+
+// clearStreamDeadlines lifts the server's read and write timeouts for a
+// long-lived SSE response, which would otherwise be cut off mid-stream.
+// Writers that can't set deadlines have none to clear.
+func clearStreamDeadlines(w http.ResponseWriter) {
+	controller := http.NewResponseController(w)
+	_ = controller.SetReadDeadline(time.Time{})
+	_ = controller.SetWriteDeadline(time.Time{})
+}
+
+func (h *HTTPHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	if h.sessions == nil {
+		w.Header().Set("Allow", "POST, GET, DELETE, OPTIONS")
+		http.Error(w, "streaming GET not supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "Accept must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+
+	sessionID := strings.TrimSpace(r.Header.Get(sessionHeader))
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header is required", http.StatusBadRequest)
+		return
+	}
+	session, ok := h.resolveSession(w, r)
+	if !ok || !h.claimSession(w, r, session, h.sessionAuth(r, session)) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	cursor := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if cursor == "" {
+		// A fresh stream only carries events queued from now on
+		events, err := h.sessions.EventsAfter(ctx, sessionID, "")
+		if err != nil {
+			h.logSessionError("Failed to read MCP session events", err)
+			http.Error(w, "session lookup failed", http.StatusInternalServerError)
+			return
+		}
+		if len(events) > 0 {
+			cursor = events[len(events)-1].ID
+		}
+	}
+
+	wake, unsubscribe := h.hub.subscribe(sessionID)
+	defer unsubscribe()
+	clearStreamDeadlines(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	poll := time.NewTicker(sessionPollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(sessionKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		events, err := h.sessions.EventsAfter(ctx, sessionID, cursor)
+		if err != nil {
+			if !errors.Is(err, ErrSessionNotFound) && ctx.Err() == nil {
+				h.logSessionError("Failed to read MCP session events", err)
+			}
+			return
+		}
+		for _, event := range events {
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", event.ID, event.Data); err != nil {
+				return
+			}
+			cursor = event.ID
+		}
+		if len(events) > 0 {
+			flusher.Flush()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-poll.C:
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *HTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if h.sessions == nil {
+		w.Header().Set("Allow", "POST, GET, DELETE, OPTIONS")
+		http.Error(w, "session termination is not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := strings.TrimSpace(r.Header.Get(sessionHeader))
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header is required", http.StatusBadRequest)
+		return
+	}
+
+	session, ok := h.resolveSession(w, r)
+	if !ok || !h.claimSession(w, r, session, h.sessionAuth(r, session)) {
+		return
+	}
+
+	deleted, err := h.sessions.Delete(r.Context(), sessionID)
+	if err != nil {
+		h.logSessionError("Failed to delete MCP session", err)
+		http.Error(w, "session termination failed", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	// Open streams on this instance notice the session is gone and close
+	h.hub.signal(sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) logSessionError(message string, err error) {
+	if h.logger == nil {
+		return
+	}
+	h.logger.Error(message, logging.WithFields(map[string]interface{}{
+		"error": err.Error(),
+	}))
+}
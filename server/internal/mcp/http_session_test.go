@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSessionTestHandler() *HTTPHandler {
+	handler := newTestHTTPHandler(&fakeHTTPAuthProvider{userID: "user-1"})
+	handler.SetSessionStore(NewInMemorySessionStore(time.Hour))
+	return handler
+}
+
+func initializeSession(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	return initializeSessionWithToken(t, handler, "")
+}
+
+// initializeSessionWithToken starts a session, bound to the token's user
+// when a token is given
+func initializeSessionWithToken(t *testing.T, handler http.Handler, token string) string {
+	t.Helper()
+	requestBody := []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	request := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(requestBody))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+
+	sessionID := responseRecorder.Header().Get(sessionHeader)
+	if responseRecorder.Code != http.StatusOK || sessionID == "" {
+		t.Fatalf("expected initialize to issue a session, got %d and %q", responseRecorder.Code, sessionID)
+	}
+	return sessionID
+}
+
+func TestHTTPHandlerSessionLifecycle(t *testing.T) {
+	handler := newSessionTestHandler()
+	sessionID := initializeSession(t, handler)
+
+	ping := func(sessionID string) int {
+		request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+		if sessionID != "" {
+			request.Header.Set(sessionHeader, sessionID)
+		}
+		responseRecorder := httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, request)
+		return responseRecorder.Code
+	}
+
+	if code := ping(sessionID); code != http.StatusOK {
+		t.Fatalf("expected request in session to succeed, got %d", code)
+	}
+	if code := ping(""); code != http.StatusOK {
+		t.Fatalf("expected stateless request to succeed, got %d", code)
+	}
+	if code := ping("unknown"); code != http.StatusNotFound {
+		t.Fatalf("expected unknown session to return 404, got %d", code)
+	}
+
+	deleteRequest := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+	deleteRequest.Header.Set(sessionHeader, sessionID)
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, deleteRequest)
+	if responseRecorder.Code != http.StatusNoContent {
+		t.Fatalf("expected DELETE to end the session, got %d", responseRecorder.Code)
+	}
+	if code := ping(sessionID); code != http.StatusNotFound {
+		t.Fatalf("expected terminated session to return 404, got %d", code)
+	}
+}
+
+func TestHTTPHandlerWithoutSessionStoreRejectsGetAndDelete(t *testing.T) {
+	handler := newTestHTTPHandler(&fakeHTTPAuthProvider{userID: "user-1"})
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		responseRecorder := httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, httptest.NewRequest(method, "/mcp", nil))
+		if responseRecorder.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("expected %s to return 405 without sessions, got %d", method, responseRecorder.Code)
+		}
+	}
+}
+
+func TestHTTPHandlerStreamsAndResumesNotifications(t *testing.T) {
+	handler := newSessionTestHandler()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	sessionID := initializeSessionWithToken(t, handler, "token")
+	ctx := context.Background()
+	if err := handler.Notify(ctx, sessionID, "notifications/message", map[string]string{"data": "first"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	// A fresh stream skips the event queued before it opened
+	reader, closeStream := openSessionStream(t, server.URL, sessionID, "")
+	if err := handler.NotifyUser(ctx, "user-1", "notifications/resources/list_changed", nil); err != nil {
+		t.Fatalf("NotifyUser() error = %v", err)
+	}
+	id, data := readSessionEvent(t, reader)
+	closeStream()
+	if id != "2" || !strings.Contains(data, "notifications/resources/list_changed") {
+		t.Fatalf("expected list_changed as event 2, got %s %s", id, data)
+	}
+
+	// Resuming from event 0 replays everything retained
+	reader, closeStream = openSessionStream(t, server.URL, sessionID, "0")
+	defer closeStream()
+	if id, data := readSessionEvent(t, reader); id != "1" || !strings.Contains(data, "first") {
+		t.Fatalf("expected replayed event 1, got %s %s", id, data)
+	}
+	if id, _ := readSessionEvent(t, reader); id != "2" {
+		t.Fatalf("expected replayed event 2, got %s", id)
+	}
+}
+
+func TestHTTPHandlerStreamOutlivesServerTimeouts(t *testing.T) {
+	handler := newSessionTestHandler()
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ReadTimeout = 100 * time.Millisecond
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	sessionID := initializeSessionWithToken(t, handler, "token")
+	reader, closeStream := openSessionStream(t, server.URL, sessionID, "")
+	defer closeStream()
+
+	time.Sleep(300 * time.Millisecond)
+	if err := handler.NotifyUser(context.Background(), "user-1", "notifications/resources/list_changed", nil); err != nil {
+		t.Fatalf("NotifyUser() error = %v", err)
+	}
+	if _, data := readSessionEvent(t, reader); !strings.Contains(data, "list_changed") {
+		t.Fatalf("expected the stream to still deliver events, got %s", data)
+	}
+}
+
+func TestHTTPHandlerSessionIsBoundToItsUser(t *testing.T) {
+	provider := &fakeHTTPAuthProvider{userID: "user-1"}
+	handler := newTestHTTPHandler(provider)
+	handler.SetSessionStore(NewInMemorySessionStore(time.Hour))
+	sessionID := initializeSessionWithToken(t, handler, "token")
+
+	send := func(method, token string) *httptest.ResponseRecorder {
+		var body *strings.Reader
+		if method == http.MethodPost {
+			body = strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+		} else {
+			body = strings.NewReader("")
+		}
+		request := httptest.NewRequest(method, "/mcp", body)
+		request.Header.Set("Accept", "application/json, text/event-stream")
+		request.Header.Set(sessionHeader, sessionID)
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		responseRecorder := httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	if code := send(http.MethodPost, "token").Code; code != http.StatusOK {
+		t.Fatalf("expected the owner's request to succeed, got %d", code)
+	}
+	if response := send(http.MethodPost, ""); response.Code != http.StatusUnauthorized || response.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected a request without a token to be challenged, got %d", response.Code)
+	}
+
+	provider.userID = "user-2"
+	for _, method := range []string{http.MethodPost, http.MethodGet, http.MethodDelete} {
+		if code := send(method, "token").Code; code != http.StatusNotFound {
+			t.Fatalf("expected %s from another user to return 404, got %d", method, code)
+		}
+	}
+
+	provider.userID = "user-1"
+	if code := send(http.MethodDelete, "token").Code; code != http.StatusNoContent {
+		t.Fatalf("expected the owner to end the session, got %d", code)
+	}
+}
+
+func TestHTTPHandlerBindsSessionOnFirstAuthenticatedRequest(t *testing.T) {
+	handler := newSessionTestHandler()
+	sessionID := initializeSession(t, handler)
+
+	request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`))
+	request.Header.Set(sessionHeader, sessionID)
+	request.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	if ids, _ := handler.sessions.ListByUser(context.Background(), "user-1"); len(ids) != 1 || ids[0] != sessionID {
+		t.Fatalf("expected the session to be bound to user-1, got %v", ids)
+	}
+}
+
+func TestInitializeAdvertisesResourceListChangesWithSessions(t *testing.T) {
+	handler := newSessionTestHandler()
+	handler.protocol.handler.SetResourceChangeNotifier(handler.ResourcesChanged)
+
+	requestBody := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(requestBody)))
+	if !strings.Contains(responseRecorder.Body.String(), `"resources":{"subscribe":false,"listChanged":true}`) {
+		t.Fatalf("expected resources.listChanged over HTTP sessions, got %s", responseRecorder.Body.String())
+	}
+
+	stdio := handler.protocol.HandleMessage(context.Background(), []byte(requestBody))
+	if stdio.Result.(InitializeResult).Capabilities.Resources.ListChanged {
+		t.Fatal("expected no resources.listChanged without a session stream")
+	}
+}
+
+func openSessionStream(t *testing.T, serverURL, sessionID, lastEventID string) (*bufio.Reader, func()) {
+	t.Helper()
+	streamCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	request, _ := http.NewRequestWithContext(streamCtx, http.MethodGet, serverURL, nil)
+	request.Header.Set("Accept", "text/event-stream")
+	request.Header.Set("Authorization", "Bearer token")
+	request.Header.Set(sessionHeader, sessionID)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		cancel()
+		t.Fatalf("GET stream error = %v", err)
+	}
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/event-stream" {
+		cancel()
+		t.Fatalf("expected an SSE stream, got %d %q", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+	return bufio.NewReader(response.Body), func() {
+		cancel()
+		response.Body.Close()
+	}
+}
+
+func readSessionEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+	var id, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read SSE event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && data != "":
+			return id, data
+		}
+	}
+}
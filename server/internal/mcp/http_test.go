@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	appauth "github.com/johnrirwin/flyingforge/internal/auth"
@@ -62,23 +63,28 @@ func (e *fakeHTTPAuthError) ErrorCode() string { return e.code }
 
 type fakeHTTPAuthProvider struct {
 	userID string
+	scopes []string
 	err    error
 }
 
 func (p *fakeHTTPAuthProvider) Enabled() bool { return true }
 
-func (p *fakeHTTPAuthProvider) AuthenticateBearerToken(_ context.Context, token string) (string, error) {
+func (p *fakeHTTPAuthProvider) AuthenticateBearerToken(_ context.Context, token string) (*appauth.MCPPrincipal, error) {
 	if p.err != nil {
-		return "", p.err
+		return nil, p.err
 	}
 	if token == "" {
-		return "", &fakeHTTPAuthError{code: "invalid_token", msg: "missing token"}
+		return nil, &fakeHTTPAuthError{code: "invalid_token", msg: "missing token"}
 	}
-	return p.userID, nil
+	return &appauth.MCPPrincipal{UserID: p.userID, Scopes: p.scopes}, nil
 }
 
-func (p *fakeHTTPAuthProvider) Challenge(errorCode, description string) string {
-	return fmt.Sprintf(`Bearer realm="flyingforge", error="%s", error_description="%s"`, errorCode, description)
+func (p *fakeHTTPAuthProvider) Challenge(errorCode, description string, scopes ...string) string {
+	challenge := fmt.Sprintf(`Bearer realm="flyingforge", error="%s", error_description="%s"`, errorCode, description)
+	if len(scopes) > 0 {
+		challenge += fmt.Sprintf(`, scope="%s"`, strings.Join(scopes, " "))
+	}
+	return challenge
 }
 
 func (p *fakeHTTPAuthProvider) ProtectedResourceMetadata() *appauth.MCPProtectedResourceMetadata {
@@ -242,6 +248,54 @@ func TestHTTPHandlerChallengesPrivateToolWithoutToken(t *testing.T) {
 	}
 }
 
+func TestHTTPHandlerChallengesWriteToolWithoutScope(t *testing.T) {
+	handler := newTestHTTPHandler(&fakeHTTPAuthProvider{userID: "user-1", scopes: []string{"flyingforge.read"}})
+
+	requestBody := []byte(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"add_inventory_item","arguments":{"name":"Motor","category":"motors"}}}`)
+	request := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(requestBody))
+	request.Header.Set("Authorization", "Bearer read-only-token")
+	responseRecorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(responseRecorder, request)
+
+	challenge := responseRecorder.Header().Get("WWW-Authenticate")
+	if !strings.Contains(challenge, `error="insufficient_scope"`) || !strings.Contains(challenge, `scope="inventory:write"`) {
+		t.Fatalf("expected insufficient_scope challenge naming inventory:write, got %q", challenge)
+	}
+
+	var response struct {
+		Result CallToolResult `json:"result"`
+	}
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode tools/call response: %v", err)
+	}
+	if !response.Result.IsError || !strings.Contains(response.Result.Content[0].Text, "inventory:write") {
+		t.Fatalf("expected scope error result, got %+v", response.Result)
+	}
+}
+
+func TestHTTPHandlerChallengesBatchForEveryMissingScope(t *testing.T) {
+	handler := newTestHTTPHandler(&fakeHTTPAuthProvider{userID: "user-1", scopes: []string{"flyingforge.read"}})
+
+	requestBody := []byte(`[
+		{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"add_inventory_item","arguments":{}}},
+		{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"log_battery_cycle","arguments":{}}}
+	]`)
+	request := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(requestBody))
+	request.Header.Set("Authorization", "Bearer read-only-token")
+	responseRecorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(responseRecorder, request)
+
+	if challenge := responseRecorder.Header().Get("WWW-Authenticate"); !strings.Contains(challenge, `scope="inventory:write batteries:write"`) {
+		t.Fatalf("expected challenge naming both write scopes, got %q", challenge)
+	}
+	var responses []Response
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &responses); err != nil || len(responses) != 2 {
+		t.Fatalf("expected 2 batch responses, got %s (%v)", responseRecorder.Body.String(), err)
+	}
+}
+
 func TestHTTPHandlerProtectedResourceMetadata(t *testing.T) {
 	handler := newTestHTTPHandler(&fakeHTTPAuthProvider{userID: "user-1"})
 
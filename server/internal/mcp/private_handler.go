@@ -89,6 +89,8 @@ type radioBackupSummary struct {
 	FileSize   int64             `json:"fileSize"`
 	Checksum   string            `json:"checksum,omitempty"`
 	CreatedAt  time.Time         `json:"createdAt"`
+
+	ModelSummary *models.RadioBackupModelSummary `json:"modelSummary,omitempty"`
 }
 
 type listAircraftResponse struct {
@@ -224,7 +226,7 @@ func (h *Handler) getPrivateReadOnlyTools() []ToolDefinition {
 		{
 			Name:        "list_radio_backups",
 			Title:       "List radio backups",
-			Description: "List metadata for a radio's backups. Backup file bytes are never returned.",
+			Description: "List metadata for a radio's backups, including the parsed EdgeTX model list (name, RF protocol, receiver number, timers, logical switch count) for model and SD card backups. Backup file bytes are never returned.",
 			InputSchema: json.RawMessage(`{
 				"type": "object",
 				"properties": {
@@ -257,6 +259,9 @@ func (h *Handler) handlePrivateToolCall(ctx context.Context, name string, argume
 		}
 		return nil, nil
 	}
+	if err := h.requireToolScope(ctx, name); err != nil {
+		return nil, err
+	}
 
 	switch name {
 	case "list_my_aircraft":
@@ -273,6 +278,18 @@ func (h *Handler) handlePrivateToolCall(ctx context.Context, name string, argume
 		return h.handleGetRadioDetails(ctx, userID, arguments)
 	case "list_radio_backups":
 		return h.handleListRadioBackups(ctx, userID, arguments)
+	case "log_flight_session":
+		return h.handleLogFlightSession(ctx, userID, arguments)
+	case "log_battery_cycle":
+		return h.handleLogBatteryCycle(ctx, userID, arguments)
+	case "add_inventory_item":
+		return h.handleAddInventoryItem(ctx, userID, arguments)
+	case "set_aircraft_component":
+		return h.handleSetAircraftComponent(ctx, userID, arguments)
+	case "save_fc_config":
+		return h.handleSaveFCConfig(ctx, userID, arguments)
+	case "create_build_draft":
+		return h.handleCreateBuildDraft(ctx, userID, arguments)
 	default:
 		return nil, nil
 	}
@@ -675,5 +692,7 @@ func summarizeRadioBackup(backup models.RadioBackup) radioBackupSummary {
 		FileSize:   backup.FileSize,
 		Checksum:   backup.Checksum,
 		CreatedAt:  backup.CreatedAt,
+
+		ModelSummary: backup.ModelSummary,
 	}
 }
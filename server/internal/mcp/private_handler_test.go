@@ -66,6 +66,10 @@ func authenticatedContext() context.Context {
 	return WithRequestAuth(context.Background(), RequestAuth{UserID: "user-1"})
 }
 
+func scopedContext(scopes ...string) context.Context {
+	return WithRequestAuth(context.Background(), RequestAuth{UserID: "user-1", Scopes: append([]string{"flyingforge.read"}, scopes...)})
+}
+
 func TestGetAircraftDetailsOmitsRawReceiverSettings(t *testing.T) {
 	handler := NewHandler(
 		nil,
@@ -263,8 +267,22 @@ func TestListRadioBackupsOmitsStoragePath(t *testing.T) {
 						StoragePath: "/private/backups/secret.zip",
 						CreatedAt:   time.Unix(300, 0).UTC(),
 					},
+					{
+						ID:          "backup-2",
+						RadioID:     "radio-1",
+						BackupName:  "Models",
+						BackupType:  models.BackupTypeEdgeTXModels,
+						FileName:    "models.zip",
+						FileSize:    2048,
+						StoragePath: "/private/backups/models.zip",
+						CreatedAt:   time.Unix(400, 0).UTC(),
+						ModelSummary: &models.RadioBackupModelSummary{
+							Models:     []models.EdgeTXModel{{FileName: "MODELS/model01.yml", Name: "Nazgul", ModuleType: models.EdgeTXModuleInternal, InternalProtocol: "CRSF"}},
+							ModelCount: 1,
+						},
+					},
 				},
-				TotalCount: 1,
+				TotalCount: 2,
 			},
 		},
 		nil,
@@ -279,8 +297,14 @@ func TestListRadioBackupsOmitsStoragePath(t *testing.T) {
 
 	toolResult := result.(ToolResultData)
 	payload := toolResult.StructuredContent.(listRadioBackupsResponse)
-	if payload.TotalCount != 1 {
-		t.Fatalf("expected one backup, got %+v", payload)
+	if payload.TotalCount != 2 {
+		t.Fatalf("expected two backups, got %+v", payload)
+	}
+	if payload.Backups[0].ModelSummary != nil {
+		t.Fatalf("expected no model summary for full backup, got %+v", payload.Backups[0].ModelSummary)
+	}
+	if summary := payload.Backups[1].ModelSummary; summary == nil || summary.ModelCount != 1 || summary.Models[0].Name != "Nazgul" {
+		t.Fatalf("expected EdgeTX model summary, got %+v", summary)
 	}
 
 	serialized, err := json.Marshal(payload)
@@ -292,3 +316,62 @@ func TestListRadioBackupsOmitsStoragePath(t *testing.T) {
 		t.Fatalf("expected storage paths to be omitted, got %s", text)
 	}
 }
+
+type stubFlightLogger struct {
+	params models.CreateFlightSessionParams
+}
+
+func (s *stubFlightLogger) LogSession(_ context.Context, userID string, params models.CreateFlightSessionParams) (*models.FlightSession, error) {
+	s.params = params
+	session := &models.FlightSession{
+		ID:              "session-1",
+		UserID:          userID,
+		AircraftID:      params.AircraftID,
+		FlightCount:     len(params.Batteries),
+		DurationSeconds: params.DurationSeconds,
+	}
+	for _, battery := range params.Batteries {
+		session.Batteries = append(session.Batteries, models.FlightSessionBattery{BatteryID: battery.BatteryID, Cycles: 1})
+	}
+	return session, nil
+}
+
+func TestLogFlightSessionConvertsArguments(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil, []string{"flyingforge.read"}, testutil.NullLogger())
+	flightLogger := &stubFlightLogger{}
+	handler.SetFlightLogger(flightLogger)
+
+	if !handler.IsPrivateTool("log_flight_session") {
+		t.Fatal("expected log_flight_session to require a linked account")
+	}
+
+	result, err := handler.HandleToolCall(scopedContext("flights:write"), "log_flight_session", json.RawMessage(`{
+		"aircraftId": " aircraft-1 ",
+		"batteryIds": ["bat-1", "bat-2"],
+		"durationMinutes": 7.5,
+		"location": "Local field",
+		"flownAt": "2024-05-01T10:00:00Z"
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if flightLogger.params.AircraftID != "aircraft-1" || flightLogger.params.DurationSeconds != 450 {
+		t.Fatalf("unexpected session params: %+v", flightLogger.params)
+	}
+	if flightLogger.params.FlownAt == nil || !flightLogger.params.FlownAt.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected flownAt to be parsed, got %v", flightLogger.params.FlownAt)
+	}
+	if len(flightLogger.params.Batteries) != 2 {
+		t.Fatalf("expected two batteries, got %+v", flightLogger.params.Batteries)
+	}
+
+	session := result.(ToolResultData).StructuredContent.(*models.FlightSession)
+	if session.UserID != "" {
+		t.Fatalf("expected user ID to be omitted, got %q", session.UserID)
+	}
+
+	if _, err := handler.HandleToolCall(context.Background(), "log_flight_session", json.RawMessage(`{"aircraftId":"aircraft-1"}`)); err == nil {
+		t.Fatal("expected unauthenticated call to fail")
+	}
+}
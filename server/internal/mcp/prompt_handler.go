@@ -0,0 +1,313 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/betaflight"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// maxPromptCandidatesPerType caps how many catalog parts of each gear type
+// the build prompt injects
+const maxPromptCandidatesPerType = 5
+
+// promptInvalidParamsCode is returned for unknown prompts and bad arguments
+const promptInvalidParamsCode = -32602
+
+// buildPromptGearTypes are the parts a complete build is suggested from
+var buildPromptGearTypes = []models.GearType{
+	models.GearTypeFrame,
+	models.GearTypeMotor,
+	models.GearTypeStack,
+	models.GearTypeAIO,
+	models.GearTypeVTX,
+	models.GearTypeCamera,
+	models.GearTypeReceiver,
+	models.GearTypeProp,
+}
+
+type CatalogSearcher interface {
+	Search(ctx context.Context, params models.GearCatalogSearchParams) (*models.GearCatalogSearchResponse, error)
+}
+
+type BatteryHistoryReader interface {
+	GetDetails(ctx context.Context, id string, userID string) (*models.BatteryDetailsResponse, error)
+}
+
+type catalogCandidate struct {
+	ID       string          `json:"id"`
+	GearType models.GearType `json:"gearType"`
+	Name     string          `json:"name"`
+	MSRP     float64         `json:"msrp"`
+	BestFor  []string        `json:"bestFor,omitempty"`
+}
+
+// SetCatalogSearcher enables the suggest_build prompt
+func (h *Handler) SetCatalogSearcher(catalogSearcher CatalogSearcher) {
+	h.catalogSearcher = catalogSearcher
+}
+
+// SetBatteryHistoryReader enables the diagnose_battery prompt
+func (h *Handler) SetBatteryHistoryReader(batteryReader BatteryHistoryReader) {
+	h.batteryReader = batteryReader
+}
+
+// ListPrompts describes the prompts whose data sources are configured
+func (h *Handler) ListPrompts() []Prompt {
+	prompts := []Prompt{}
+	if h.tuningReader != nil {
+		prompts = append(prompts, Prompt{
+			Name:        "review_tune",
+			Title:       "Review my tune",
+			Description: "Review the latest Betaflight tune on one of your aircraft, with automated lint findings.",
+			Arguments: []PromptArgument{
+				{Name: "aircraftId", Description: "Aircraft ID from list_my_aircraft", Required: true},
+			},
+		})
+	}
+	if h.catalogSearcher != nil {
+		prompts = append(prompts, Prompt{
+			Name:        "suggest_build",
+			Title:       "Suggest a build",
+			Description: "Suggest a complete build under a budget from published gear catalog parts.",
+			Arguments: []PromptArgument{
+				{Name: "budget", Description: "Maximum total price in USD", Required: true},
+				{Name: "style", Description: "Flying style such as freestyle, racing, cinematic or long-range (default freestyle)"},
+			},
+		})
+	}
+	if h.batteryReader != nil {
+		prompts = append(prompts, Prompt{
+			Name:        "diagnose_battery",
+			Title:       "Diagnose this battery",
+			Description: "Diagnose the health of one of your batteries from its logged cycles, IR and voltages.",
+			Arguments: []PromptArgument{
+				{Name: "batteryId", Description: "Battery ID", Required: true},
+			},
+		})
+	}
+	return prompts
+}
+
+// IsPrivatePrompt reports whether a prompt reads the linked user's data
+func (h *Handler) IsPrivatePrompt(name string) bool {
+	switch name {
+	case "review_tune", "diagnose_battery":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetPrompt renders a prompt with live data injected into its message
+func (h *Handler) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*GetPromptResult, error) {
+	switch {
+	case name == "review_tune" && h.tuningReader != nil:
+		userID, err := h.requireResourceUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return h.getReviewTunePrompt(ctx, userID, arguments)
+	case name == "suggest_build" && h.catalogSearcher != nil:
+		return h.getSuggestBuildPrompt(ctx, arguments)
+	case name == "diagnose_battery" && h.batteryReader != nil:
+		userID, err := h.requireResourceUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return h.getDiagnoseBatteryPrompt(ctx, userID, arguments)
+	default:
+		return nil, invalidPromptParams("Unknown prompt: " + name)
+	}
+}
+
+func (h *Handler) getReviewTunePrompt(ctx context.Context, userID string, arguments map[string]string) (*GetPromptResult, error) {
+	aircraftID := strings.TrimSpace(arguments["aircraftId"])
+	if aircraftID == "" {
+		return nil, invalidPromptParams("aircraftId is required")
+	}
+
+	snapshot, err := h.tuningReader.GetLatestTuningSnapshot(ctx, aircraftID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, invalidPromptParams("No tuning snapshot is available for aircraft " + aircraftID)
+	}
+	tuning, err := parseTuningData(snapshot.TuningData)
+	if err != nil {
+		return nil, err
+	}
+
+	aircraftName := aircraftID
+	if h.aircraftSvc != nil {
+		if details, err := h.aircraftSvc.GetDetails(ctx, aircraftID, userID); err == nil && details != nil {
+			aircraftName = details.Aircraft.Name
+		}
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Review the Betaflight tune on my aircraft %q (%s %s on %s).\n\n",
+		aircraftName, snapshot.FirmwareName, snapshot.FirmwareVersion, firstNonEmpty(snapshot.BoardName, snapshot.BoardTarget, "an unknown board"))
+	text.WriteString("Parsed tuning from the latest snapshot:\n")
+	writeJSONBlock(&text, tuning)
+	text.WriteString("\nAutomated lint findings:\n")
+	findings := betaflight.Lint(tuning)
+	if len(findings) == 0 {
+		text.WriteString("- none\n")
+	}
+	for _, finding := range findings {
+		fmt.Fprintf(&text, "- [%s] %s\n", finding.Severity, finding.Message)
+	}
+	text.WriteString("\nExplain what stands out in this tune, which lint findings matter for how it flies, and suggest specific CLI changes to try, one at a time.")
+
+	return userPrompt("Tune review for "+aircraftName, text.String()), nil
+}
+
+func (h *Handler) getSuggestBuildPrompt(ctx context.Context, arguments map[string]string) (*GetPromptResult, error) {
+	budget, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(arguments["budget"]), "$"), 64)
+	if err != nil || budget <= 0 {
+		return nil, invalidPromptParams("budget must be a positive number")
+	}
+	style := strings.ToLower(strings.TrimSpace(arguments["style"]))
+	if style == "" {
+		style = "freestyle"
+	}
+
+	candidates := []catalogCandidate{}
+	for _, gearType := range buildPromptGearTypes {
+		response, err := h.catalogSearcher.Search(ctx, models.GearCatalogSearchParams{
+			GearType: gearType,
+			Status:   models.CatalogStatusPublished,
+			Limit:    100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, selectCatalogCandidates(response.Items, style, budget)...)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Suggest a complete %s FPV build that costs at most $%.2f in total.\n\n", style, budget)
+	if len(candidates) == 0 {
+		text.WriteString("No published catalog parts with a price fit this budget and style, so say so and suggest what to change.\n")
+	} else {
+		text.WriteString("Choose from these published catalog parts (prices are MSRP in USD):\n")
+		writeJSONBlock(&text, candidates)
+	}
+	text.WriteString("\nPick one frame, a flight controller and ESC (a stack or an AIO), four motors, a VTX, a camera, a receiver and props. Check that the parts are compatible, list each with its price, and give the total.")
+
+	return userPrompt(fmt.Sprintf("%s build under $%.0f", style, budget), text.String()), nil
+}
+
+func (h *Handler) getDiagnoseBatteryPrompt(ctx context.Context, userID string, arguments map[string]string) (*GetPromptResult, error) {
+	batteryID := strings.TrimSpace(arguments["batteryId"])
+	if batteryID == "" {
+		return nil, invalidPromptParams("batteryId is required")
+	}
+
+	details, err := h.batteryReader.GetDetails(ctx, batteryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, invalidPromptParams("Battery not found: " + batteryID)
+	}
+
+	details.Battery.UserID = ""
+	for i := range details.Logs {
+		details.Logs[i].UserID = ""
+	}
+	name := firstNonEmpty(details.Battery.Name, details.Battery.BatteryCode)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Diagnose the health of my %dS %dmAh %s battery %q.\n\n",
+		details.Battery.Cells, details.Battery.CapacityMah, details.Battery.Chemistry, name)
+	text.WriteString("Battery:\n")
+	writeJSONBlock(&text, details.Battery)
+	fmt.Fprintf(&text, "\nLog history (%d most recent entries, newest first):\n", len(details.Logs))
+	writeJSONBlock(&text, details.Logs)
+	text.WriteString("\nLook at how internal resistance, cell voltages and storage habits trend over the cycles. Say whether the pack is healthy, aging or should be retired, and what to change in how it is charged and stored.")
+
+	return userPrompt("Battery diagnosis for "+name, text.String()), nil
+}
+
+// selectCatalogCandidates keeps priced items that fit the budget and either
+// list the style or list no styles at all. Style matches sort first.
+func selectCatalogCandidates(items []models.GearCatalogItem, style string, budget float64) []catalogCandidate {
+	matched := []catalogCandidate{}
+	unrated := []catalogCandidate{}
+	for _, item := range items {
+		if item.MSRP == nil || *item.MSRP > budget {
+			continue
+		}
+		candidate := catalogCandidate{
+			ID:       item.ID,
+			GearType: item.GearType,
+			Name:     item.DisplayName(),
+			MSRP:     *item.MSRP,
+			BestFor:  item.BestFor,
+		}
+		switch {
+		case len(item.BestFor) == 0:
+			unrated = append(unrated, candidate)
+		case containsFold(item.BestFor, style):
+			matched = append(matched, candidate)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].MSRP < matched[j].MSRP })
+	sort.SliceStable(unrated, func(i, j int) bool { return unrated[i].MSRP < unrated[j].MSRP })
+	candidates := append(matched, unrated...)
+	if len(candidates) > maxPromptCandidatesPerType {
+		candidates = candidates[:maxPromptCandidatesPerType]
+	}
+	return candidates
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func writeJSONBlock(text *strings.Builder, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", value))
+	}
+	text.WriteString("```json\n")
+	text.Write(data)
+	text.WriteString("\n```\n")
+}
+
+func userPrompt(description, text string) *GetPromptResult {
+	return &GetPromptResult{
+		Description: description,
+		Messages: []PromptMessage{{
+			Role:    "user",
+			Content: ContentItem{Type: "text", Text: text},
+		}},
+	}
+}
+
+func invalidPromptParams(message string) error {
+	return &ResourceError{Code: promptInvalidParamsCode, Message: message}
+}
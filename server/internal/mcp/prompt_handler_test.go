@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+type stubCatalogSearcher struct {
+	items []models.GearCatalogItem
+}
+
+func (s *stubCatalogSearcher) Search(_ context.Context, params models.GearCatalogSearchParams) (*models.GearCatalogSearchResponse, error) {
+	response := &models.GearCatalogSearchResponse{}
+	for _, item := range s.items {
+		if item.GearType == params.GearType {
+			response.Items = append(response.Items, item)
+		}
+	}
+	return response, nil
+}
+
+type stubBatteryHistoryReader struct {
+	details *models.BatteryDetailsResponse
+}
+
+func (s *stubBatteryHistoryReader) GetDetails(_ context.Context, id string, _ string) (*models.BatteryDetailsResponse, error) {
+	if s.details == nil || id != s.details.ID {
+		return nil, nil
+	}
+	return s.details, nil
+}
+
+func msrp(value float64) *float64 {
+	return &value
+}
+
+func newPromptTestProtocol() *Protocol {
+	tuningData, _ := json.Marshal(models.ParsedTuning{
+		PIDs: &models.PIDProfile{Roll: models.AxisPID{P: 45, I: 80, D: 52}},
+	})
+	handler := NewHandler(
+		nil,
+		nil,
+		&stubAircraftReader{detailsResponse: &models.AircraftDetailsResponse{Aircraft: models.Aircraft{ID: "air-1", Name: "Demo Quad"}}},
+		nil,
+		&stubTuningReader{snapshot: &models.AircraftTuningSnapshot{ID: "snap-1", FirmwareName: "BTFL", FirmwareVersion: "4.4.2", TuningData: tuningData}},
+		[]string{"flyingforge.read"},
+		testutil.NullLogger(),
+	)
+	handler.SetCatalogSearcher(&stubCatalogSearcher{items: []models.GearCatalogItem{
+		{ID: "frame-1", GearType: models.GearTypeFrame, Brand: "ImpulseRC", Model: "Apex", BestFor: []string{"Freestyle"}, MSRP: msrp(90)},
+		{ID: "frame-2", GearType: models.GearTypeFrame, Brand: "Generic", Model: "Racer", BestFor: []string{"racing"}, MSRP: msrp(30)},
+		{ID: "frame-3", GearType: models.GearTypeFrame, Brand: "Luxury", Model: "Carbon", BestFor: []string{"freestyle"}, MSRP: msrp(400)},
+		{ID: "motor-1", GearType: models.GearTypeMotor, Brand: "T-Motor", Model: "F60"},
+	}})
+	handler.SetBatteryHistoryReader(&stubBatteryHistoryReader{details: &models.BatteryDetailsResponse{
+		Battery: models.Battery{ID: "bat-1", UserID: "user-1", BatteryCode: "BAT-A1B2", Cells: 6, CapacityMah: 1300, Chemistry: "LIPO"},
+		Logs:    []models.BatteryLog{{ID: "log-1", UserID: "user-1", BatteryID: "bat-1", IRMohmPerCell: json.RawMessage(`[4.1,4.3]`)}},
+	}})
+	return NewProtocol(handler, testutil.NullLogger())
+}
+
+func promptText(t *testing.T, result GetPromptResult) string {
+	t.Helper()
+	if len(result.Messages) != 1 || result.Messages[0].Role != "user" {
+		t.Fatalf("expected one user message, got %+v", result.Messages)
+	}
+	return result.Messages[0].Content.Text
+}
+
+func TestPromptsListAdvertisesConfiguredPrompts(t *testing.T) {
+	var result PromptsListResult
+	if rpcErr := callProtocol(t, newPromptTestProtocol(), context.Background(), `{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`, &result); rpcErr != nil {
+		t.Fatalf("expected prompts, got %+v", rpcErr)
+	}
+	names := []string{}
+	for _, prompt := range result.Prompts {
+		names = append(names, prompt.Name)
+	}
+	if strings.Join(names, " ") != "review_tune suggest_build diagnose_battery" {
+		t.Fatalf("unexpected prompts: %v", names)
+	}
+
+	if prompts := newWriteTestHandler().ListPrompts(); len(prompts) != 0 {
+		t.Fatalf("expected no prompts without data sources, got %+v", prompts)
+	}
+}
+
+func TestReviewTunePromptInjectsTuningAndLint(t *testing.T) {
+	protocol := newPromptTestProtocol()
+	request := `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"review_tune","arguments":{"aircraftId":"air-1"}}}`
+
+	var result GetPromptResult
+	if rpcErr := callProtocol(t, protocol, context.Background(), request, &result); rpcErr == nil || rpcErr.Code != resourceAuthRequiredCode {
+		t.Fatalf("expected auth error, got %+v", rpcErr)
+	}
+
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), request, &result); rpcErr != nil {
+		t.Fatalf("expected prompt, got %+v", rpcErr)
+	}
+	text := promptText(t, result)
+	for _, want := range []string{`"Demo Quad"`, "4.4.2", `"p": 45`, "roll D (52) is higher than P (45)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestSuggestBuildPromptFiltersCatalogByBudgetAndStyle(t *testing.T) {
+	var result GetPromptResult
+	request := `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"suggest_build","arguments":{"budget":"$250"}}}`
+	if rpcErr := callProtocol(t, newPromptTestProtocol(), context.Background(), request, &result); rpcErr != nil {
+		t.Fatalf("expected public prompt, got %+v", rpcErr)
+	}
+	text := promptText(t, result)
+	if !strings.Contains(text, "freestyle") || !strings.Contains(text, "frame-1") {
+		t.Fatalf("expected freestyle frame candidate, got:\n%s", text)
+	}
+	for _, excluded := range []string{"frame-2", "frame-3", "motor-1"} {
+		if strings.Contains(text, excluded) {
+			t.Fatalf("expected %s to be filtered out, got:\n%s", excluded, text)
+		}
+	}
+
+	request = `{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"suggest_build","arguments":{"budget":"cheap"}}}`
+	if rpcErr := callProtocol(t, newPromptTestProtocol(), context.Background(), request, &result); rpcErr == nil || rpcErr.Code != promptInvalidParamsCode {
+		t.Fatalf("expected invalid budget error, got %+v", rpcErr)
+	}
+}
+
+func TestDiagnoseBatteryPromptOmitsUserIDs(t *testing.T) {
+	protocol := newPromptTestProtocol()
+
+	var result GetPromptResult
+	request := `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"diagnose_battery","arguments":{"batteryId":"bat-1"}}}`
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), request, &result); rpcErr != nil {
+		t.Fatalf("expected prompt, got %+v", rpcErr)
+	}
+	text := promptText(t, result)
+	if !strings.Contains(text, "BAT-A1B2") || !strings.Contains(text, "4.1") || strings.Contains(text, "user-1") {
+		t.Fatalf("expected battery history without user IDs, got:\n%s", text)
+	}
+
+	request = `{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"diagnose_battery","arguments":{"batteryId":"someone-elses"}}}`
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), request, &result); rpcErr == nil || rpcErr.Code != promptInvalidParamsCode {
+		t.Fatalf("expected not found error, got %+v", rpcErr)
+	}
+}
+
+func TestHTTPAuthRequirementForPrompts(t *testing.T) {
+	handler := NewHTTPHandler(newPromptTestProtocol(), &fakeHTTPAuthProvider{userID: "user-1"}, nil, testutil.NullLogger())
+
+	if needsAuth, _ := handler.authRequirement(payloadRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"diagnose_battery"}}`))); !needsAuth {
+		t.Fatal("expected diagnose_battery to need a linked account")
+	}
+	if needsAuth, _ := handler.authRequirement(payloadRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"suggest_build"}}`))); needsAuth {
+		t.Fatal("expected suggest_build to be public")
+	}
+	if needsAuth, _ := handler.authRequirement(payloadRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))); needsAuth {
+		t.Fatal("expected prompts/list to be public")
+	}
+}
@@ -1,9 +1,12 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/johnrirwin/flyingforge/internal/logging"
 )
@@ -13,15 +16,56 @@ const protocolVersion = "2025-06-18"
 type Protocol struct {
 	handler *Handler
 	logger  *logging.Logger
+
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc
 }
 
 func NewProtocol(handler *Handler, logger *logging.Logger) *Protocol {
 	return &Protocol{
-		handler: handler,
-		logger:  logger,
+		handler:  handler,
+		logger:   logger,
+		inFlight: make(map[string]context.CancelFunc),
 	}
 }
 
+// HandlePayload handles one JSON-RPC message or a batch array. It returns a
+// *Response, a []*Response for batches, or nil when only notifications were
+// sent and nothing needs to go back.
+func (p *Protocol) HandlePayload(ctx context.Context, data []byte) interface{} {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		if response := p.HandleMessage(ctx, data); response != nil {
+			return response
+		}
+		return nil
+	}
+
+	var messages []json.RawMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return &Response{JSONRPC: "2.0", Error: &RPCError{Code: -32700, Message: "Parse error"}}
+	}
+	if len(messages) == 0 {
+		return &Response{JSONRPC: "2.0", Error: &RPCError{Code: -32600, Message: "Invalid Request"}}
+	}
+
+	responses := []*Response{}
+	for _, message := range messages {
+		message = bytes.TrimSpace(message)
+		if len(message) == 0 || message[0] != '{' {
+			responses = append(responses, &Response{JSONRPC: "2.0", Error: &RPCError{Code: -32600, Message: "Invalid Request"}})
+			continue
+		}
+		if response := p.HandleMessage(ctx, message); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
 func (p *Protocol) HandleMessage(ctx context.Context, data []byte) *Response {
 	var req Request
 	if err := json.Unmarshal(data, &req); err != nil {
@@ -41,13 +85,26 @@ func (p *Protocol) HandleMessage(ctx context.Context, data []byte) *Response {
 
 	switch req.Method {
 	case "initialize":
-		return p.handleInitialize(req)
-	case "initialized":
+		return p.handleInitialize(ctx, req)
+	case "initialized", "notifications/initialized":
+		return nil
+	case "notifications/cancelled":
+		p.handleCancelled(ctx, req)
 		return nil
 	case "tools/list":
 		return p.handleToolsList(req)
 	case "tools/call":
 		return p.handleToolsCall(ctx, req)
+	case "resources/list":
+		return p.handleResourcesList(ctx, req)
+	case "resources/templates/list":
+		return p.handleResourceTemplatesList(req)
+	case "resources/read":
+		return p.handleResourcesRead(ctx, req)
+	case "prompts/list":
+		return p.handlePromptsList(req)
+	case "prompts/get":
+		return p.handlePromptsGet(ctx, req)
 	case "ping":
 		return &Response{
 			JSONRPC: "2.0",
@@ -66,7 +123,7 @@ func (p *Protocol) HandleMessage(ctx context.Context, data []byte) *Response {
 	}
 }
 
-func (p *Protocol) handleInitialize(req Request) *Response {
+func (p *Protocol) handleInitialize(ctx context.Context, req Request) *Response {
 	result := InitializeResult{
 		ProtocolVersion: protocolVersion,
 		ServerInfo: ServerInfo{
@@ -74,7 +131,9 @@ func (p *Protocol) handleInitialize(req Request) *Response {
 			Version: "1.0.0",
 		},
 		Capabilities: Caps{
-			Tools: &ToolsCap{ListChanged: false},
+			Tools:     &ToolsCap{ListChanged: false},
+			Resources: &ResourcesCap{Subscribe: false, ListChanged: p.handler.notifiesResourceChanges() && sessionNotificationsFromContext(ctx)},
+			Prompts:   &PromptsCap{ListChanged: false},
 		},
 	}
 
@@ -106,7 +165,21 @@ func (p *Protocol) handleToolsCall(ctx context.Context, req Request) *Response {
 		}
 	}
 
-	result, err := p.handler.HandleToolCall(ctx, params.Name, params.Arguments)
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if key := p.inFlightKey(ctx, req.ID); key != "" {
+		p.trackRequest(key, cancel)
+		defer p.untrackRequest(key)
+	}
+	if params.Meta != nil {
+		callCtx = withProgressToken(callCtx, params.Meta.ProgressToken)
+	}
+
+	result, err := p.handler.HandleToolCall(callCtx, params.Name, params.Arguments)
+	if callCtx.Err() != nil && ctx.Err() == nil {
+		// Cancelled by the client, which no longer expects a response
+		return nil
+	}
 	if err != nil {
 		return &Response{
 			JSONRPC: "2.0",
@@ -129,6 +202,162 @@ func (p *Protocol) handleToolsCall(ctx context.Context, req Request) *Response {
 	}
 }
 
+// handleCancelled cancels an in-flight request from the same client. Unknown
+// or finished requests are ignored.
+func (p *Protocol) handleCancelled(ctx context.Context, req Request) {
+	var params CancelledParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.RequestID == nil {
+		return
+	}
+
+	key := p.inFlightKey(ctx, params.RequestID)
+	if key == "" {
+		return
+	}
+
+	p.mu.Lock()
+	cancel, ok := p.inFlight[key]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	p.logger.Debug("Cancelling MCP request", logging.WithFields(map[string]interface{}{
+		"id":     params.RequestID,
+		"reason": params.Reason,
+	}))
+	cancel()
+}
+
+// inFlightKey identifies a request within its client's cancel scope. The ID
+// is JSON-encoded so 1 and "1" stay distinct.
+func (p *Protocol) inFlightKey(ctx context.Context, id interface{}) string {
+	scope := cancelScopeFromContext(ctx)
+	if scope == "" || id == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(id)
+	if err != nil {
+		return ""
+	}
+	return scope + "\x00" + string(encoded)
+}
+
+func (p *Protocol) trackRequest(key string, cancel context.CancelFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[key] = cancel
+}
+
+func (p *Protocol) untrackRequest(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.inFlight, key)
+}
+
+func (p *Protocol) handleResourcesList(ctx context.Context, req Request) *Response {
+	resources, err := p.handler.ListResources(ctx)
+	if err != nil {
+		return p.resourceErrorResponse(ctx, req, err)
+	}
+
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  ResourcesListResult{Resources: resources},
+	}
+}
+
+func (p *Protocol) handleResourceTemplatesList(req Request) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  ResourceTemplatesListResult{ResourceTemplates: p.handler.ListResourceTemplates()},
+	}
+}
+
+func (p *Protocol) handleResourcesRead(ctx context.Context, req Request) *Response {
+	var params ReadResourceParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &RPCError{
+				Code:    -32602,
+				Message: "Invalid params: uri is required",
+			},
+		}
+	}
+
+	contents, err := p.handler.ReadResource(ctx, params.URI)
+	if err != nil {
+		return p.resourceErrorResponse(ctx, req, err)
+	}
+
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  ReadResourceResult{Contents: contents},
+	}
+}
+
+func (p *Protocol) handlePromptsList(req Request) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  PromptsListResult{Prompts: p.handler.ListPrompts()},
+	}
+}
+
+func (p *Protocol) handlePromptsGet(ctx context.Context, req Request) *Response {
+	var params GetPromptParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &RPCError{
+				Code:    -32602,
+				Message: "Invalid params: name is required",
+			},
+		}
+	}
+
+	result, err := p.handler.GetPrompt(ctx, params.Name, params.Arguments)
+	if err != nil {
+		return p.resourceErrorResponse(ctx, req, err)
+	}
+
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+// resourceErrorResponse turns a resource or prompt failure into a JSON-RPC
+// error. Auth failures carry the same WWW-Authenticate metadata as private
+// tool errors.
+func (p *Protocol) resourceErrorResponse(ctx context.Context, req Request, err error) *Response {
+	var resourceErr *ResourceError
+	if !errors.As(err, &resourceErr) {
+		p.logger.Error("MCP request failed", logging.WithFields(map[string]interface{}{
+			"method": req.Method,
+			"error":  err.Error(),
+		}))
+		resourceErr = &ResourceError{Code: -32603, Message: "Internal error"}
+	}
+
+	rpcErr := &RPCError{Code: resourceErr.Code, Message: resourceErr.Message}
+	if meta := authMetaFromContext(ctx); meta != nil {
+		rpcErr.Data = map[string]any{"_meta": meta}
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error:   rpcErr,
+	}
+}
+
 func normalizeToolResult(ctx context.Context, result interface{}) CallToolResult {
 	switch typed := result.(type) {
 	case ToolResultData:
@@ -0,0 +1,335 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/aggregator"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/ratelimit"
+	"github.com/johnrirwin/flyingforge/internal/sources"
+	"github.com/johnrirwin/flyingforge/internal/tagging"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+type stubNewsFetcher struct {
+	name    string
+	started chan struct{}
+	block   bool
+	fetches int
+}
+
+func (f *stubNewsFetcher) Name() string { return f.name }
+
+func (f *stubNewsFetcher) Fetch(ctx context.Context) ([]models.FeedItem, error) {
+	f.fetches++
+	if f.started != nil {
+		close(f.started)
+	}
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return []models.FeedItem{{ID: f.name, Title: f.name + " news", URL: "https://example.com/" + f.name}}, nil
+}
+
+func (f *stubNewsFetcher) SourceInfo() models.SourceInfo {
+	return models.SourceInfo{ID: f.name, Name: f.name}
+}
+
+func newNewsTestProtocol(fetchers ...sources.Fetcher) *Protocol {
+	agg := aggregator.New(fetchers, nil, tagging.New(), testutil.NullLogger())
+	handler := NewHandler(agg, stubEquipmentReader{}, nil, nil, nil, []string{"flyingforge.read"}, testutil.NullLogger())
+	handler.SetNewsRefreshLimiter(ratelimit.New(0))
+	return NewProtocol(handler, testutil.NullLogger())
+}
+
+// blockingEquipmentReader holds searches until released and records how
+// many ran at once
+type blockingEquipmentReader struct {
+	stubEquipmentReader
+	release chan struct{}
+
+	mu        sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (r *blockingEquipmentReader) Search(ctx context.Context, params models.EquipmentSearchParams) (*models.EquipmentSearchResponse, error) {
+	r.mu.Lock()
+	r.active++
+	r.maxActive = max(r.maxActive, r.active)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.active--
+		r.mu.Unlock()
+	}()
+
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return r.stubEquipmentReader.Search(ctx, params)
+}
+
+func (r *blockingEquipmentReader) waitForActive(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r.mu.Lock()
+		active := r.active
+		r.mu.Unlock()
+		if active == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d searches running, got %d", want, active)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandlePayloadBatch(t *testing.T) {
+	protocol := newNewsTestProtocol()
+
+	result := protocol.HandlePayload(context.Background(), []byte(`[
+		{"jsonrpc":"2.0","id":1,"method":"ping"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		42,
+		{"jsonrpc":"2.0","id":"b","method":"no/such/method"}
+	]`))
+	responses, ok := result.([]*Response)
+	if !ok || len(responses) != 3 {
+		t.Fatalf("expected 3 batch responses, got %#v", result)
+	}
+	if responses[0].ID != float64(1) || responses[0].Error != nil {
+		t.Fatalf("expected ping response first, got %+v", responses[0])
+	}
+	if responses[1].Error == nil || responses[1].Error.Code != -32600 {
+		t.Fatalf("expected invalid request for a non-object element, got %+v", responses[1])
+	}
+	if responses[2].ID != "b" || responses[2].Error == nil || responses[2].Error.Code != -32601 {
+		t.Fatalf("expected method not found, got %+v", responses[2])
+	}
+
+	if result := protocol.HandlePayload(context.Background(), []byte(`[{"jsonrpc":"2.0","method":"notifications/initialized"}]`)); result != nil {
+		t.Fatalf("expected no response for a batch of notifications, got %#v", result)
+	}
+	if response, ok := protocol.HandlePayload(context.Background(), []byte(`[]`)).(*Response); !ok || response.Error.Code != -32600 {
+		t.Fatalf("expected invalid request for an empty batch, got %#v", response)
+	}
+}
+
+func TestStdioServerReportsProgress(t *testing.T) {
+	server := NewServer(newNewsTestProtocol(&stubNewsFetcher{name: "reddit"}, &stubNewsFetcher{name: "youtube"}), testutil.NullLogger())
+
+	var out bytes.Buffer
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_drone_news","arguments":{"refresh":true},"_meta":{"progressToken":"news"}}}` + "\n")
+	if err := server.serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("serve() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 progress notifications and a response, got:\n%s", out.String())
+	}
+	for i, line := range lines[:2] {
+		var notification struct {
+			Method string         `json:"method"`
+			Params ProgressParams `json:"params"`
+		}
+		if err := json.Unmarshal([]byte(line), &notification); err != nil {
+			t.Fatalf("failed to decode notification: %v", err)
+		}
+		if notification.Method != "notifications/progress" || notification.Params.ProgressToken != "news" ||
+			notification.Params.Progress != float64(i+1) || notification.Params.Total != 2 {
+			t.Fatalf("unexpected progress notification: %s", line)
+		}
+	}
+	if !strings.Contains(lines[2], `"id":1`) || !strings.Contains(lines[2], "reddit news") {
+		t.Fatalf("expected refreshed news in the response, got %s", lines[2])
+	}
+}
+
+func TestStdioServerCancelsInFlightToolCall(t *testing.T) {
+	fetcher := &stubNewsFetcher{name: "slow", started: make(chan struct{}), block: true}
+	server := NewServer(newNewsTestProtocol(fetcher), testutil.NullLogger())
+
+	in, writer := io.Pipe()
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- server.serve(context.Background(), in, &out) }()
+
+	io.WriteString(writer, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"get_drone_news","arguments":{"refresh":true}}}`+"\n")
+	select {
+	case <-fetcher.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool call did not start")
+	}
+	io.WriteString(writer, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user aborted"}}`+"\n")
+	io.WriteString(writer, `{"jsonrpc":"2.0","id":8,"method":"ping"}`+"\n")
+	writer.Close()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serve() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled tool call kept running")
+	}
+	if output := out.String(); strings.Contains(output, `"id":7`) || !strings.Contains(output, `"id":8`) {
+		t.Fatalf("expected only the ping response, got:\n%s", output)
+	}
+}
+
+func TestHTTPHandlerStreamsProgressAndBatches(t *testing.T) {
+	protocol := newNewsTestProtocol(&stubNewsFetcher{name: "reddit"})
+	server := httptest.NewServer(NewHTTPHandler(protocol, nil, nil, testutil.NullLogger()))
+	defer server.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_drone_news","arguments":{"refresh":true},"_meta":{"progressToken":5}}}`
+	request, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer response.Body.Close()
+	if response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an SSE response, got %q", response.Header.Get("Content-Type"))
+	}
+
+	events := []string{}
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	if len(events) != 2 || !strings.Contains(events[0], "notifications/progress") || !strings.Contains(events[1], "reddit news") {
+		t.Fatalf("expected a progress event then the response, got %v", events)
+	}
+
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`
+	batchResponse, err := http.Post(server.URL, "application/json", strings.NewReader(batch))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer batchResponse.Body.Close()
+	var responses []Response
+	if err := json.NewDecoder(batchResponse.Body).Decode(&responses); err != nil || len(responses) != 2 {
+		t.Fatalf("expected 2 batch responses, got %v (%v)", responses, err)
+	}
+}
+
+func TestGetDroneNewsRefreshIsGatedAndRateLimited(t *testing.T) {
+	fetcher := &stubNewsFetcher{name: "reddit"}
+	agg := aggregator.New([]sources.Fetcher{fetcher}, nil, tagging.New(), testutil.NullLogger())
+	handler := NewHandler(agg, stubEquipmentReader{}, nil, nil, nil, []string{"flyingforge.read"}, testutil.NullLogger())
+	refresh := json.RawMessage(`{"refresh":true}`)
+
+	if _, err := handler.HandleToolCall(context.Background(), "get_drone_news", refresh); err == nil || fetcher.fetches != 0 {
+		t.Fatalf("expected refresh to be refused while manual refresh is off, got %v after %d fetches", err, fetcher.fetches)
+	}
+	if tools, _ := json.Marshal(handler.GetTools()); strings.Contains(string(tools), `"refresh"`) {
+		t.Fatal("expected the refresh argument to be hidden while manual refresh is off")
+	}
+
+	handler.SetNewsRefreshLimiter(ratelimit.New(time.Hour))
+	if _, err := handler.HandleToolCall(context.Background(), "get_drone_news", refresh); err != nil || fetcher.fetches != 1 {
+		t.Fatalf("expected the first refresh to fetch, got %v after %d fetches", err, fetcher.fetches)
+	}
+	result, err := handler.HandleToolCall(context.Background(), "get_drone_news", refresh)
+	if err != nil || fetcher.fetches != 1 {
+		t.Fatalf("expected the second refresh to be rate limited, got %v after %d fetches", err, fetcher.fetches)
+	}
+	if text := result.(ToolResultData).Text; !strings.Contains(text, "cached") {
+		t.Fatalf("expected the rate-limited answer to say it is cached, got %q", text)
+	}
+}
+
+func TestStdioServerLimitsConcurrentRequests(t *testing.T) {
+	reader := &blockingEquipmentReader{release: make(chan struct{})}
+	handler := NewHandler(nil, reader, nil, nil, nil, []string{"flyingforge.read"}, testutil.NullLogger())
+	server := NewServer(NewProtocol(handler, testutil.NullLogger()), testutil.NullLogger())
+
+	in, writer := io.Pipe()
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- server.serve(context.Background(), in, &out) }()
+
+	search := func(id int) {
+		fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"search_equipment","arguments":{"query":"rx"}}}`+"\n", id)
+	}
+	for id := 1; id <= maxStdioWorkers; id++ {
+		search(id)
+	}
+	reader.waitForActive(t, maxStdioWorkers)
+
+	// With every worker busy, a cancel still gets through and frees one
+	io.WriteString(writer, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`+"\n")
+	reader.waitForActive(t, maxStdioWorkers-1)
+	search(maxStdioWorkers + 1)
+	reader.waitForActive(t, maxStdioWorkers)
+
+	go func() {
+		search(maxStdioWorkers + 2)
+		writer.Close()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(reader.release)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serve() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stdio server did not finish")
+	}
+	if reader.maxActive != maxStdioWorkers {
+		t.Fatalf("expected at most %d concurrent searches, got %d", maxStdioWorkers, reader.maxActive)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != maxStdioWorkers+1 {
+		t.Fatalf("expected a response for every search but the cancelled one, got:\n%s", out.String())
+	}
+}
+
+func TestHTTPHandlerProgressStreamOutlivesServerTimeouts(t *testing.T) {
+	reader := &blockingEquipmentReader{release: make(chan struct{})}
+	handler := NewHandler(nil, reader, nil, nil, nil, []string{"flyingforge.read"}, testutil.NullLogger())
+	server := httptest.NewUnstartedServer(NewHTTPHandler(NewProtocol(handler, testutil.NullLogger()), nil, nil, testutil.NullLogger()))
+	server.Config.ReadTimeout = 100 * time.Millisecond
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	time.AfterFunc(300*time.Millisecond, func() { close(reader.release) })
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_equipment","arguments":{"query":"rx"},"_meta":{"progressToken":1}}}`
+	request, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil || !strings.Contains(string(data), "ExpressLRS Receiver") {
+		t.Fatalf("expected the slow call's response on the stream, got %q (%v)", data, err)
+	}
+}
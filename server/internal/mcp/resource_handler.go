@@ -0,0 +1,244 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const resourceURIPrefix = "flyingforge://"
+
+// maxListedResources caps how many resources of each kind resources/list
+// returns; anything else can still be read through the templates
+const maxListedResources = 100
+
+// JSON-RPC error codes for resource requests
+const (
+	resourceAuthRequiredCode = -32001
+	resourceNotFoundCode     = -32002
+)
+
+type FCConfigReader interface {
+	GetConfig(ctx context.Context, id string, userID string) (*models.FlightControllerConfig, error)
+	ListConfigs(ctx context.Context, userID string, params models.FCConfigListParams) (*models.FCConfigListResponse, error)
+}
+
+type BuildReader interface {
+	ListByOwner(ctx context.Context, ownerUserID string, params models.BuildListParams) (*models.BuildListResponse, error)
+	GetByOwner(ctx context.Context, id string, ownerUserID string) (*models.Build, error)
+}
+
+// ResourceError is returned by resource and prompt requests and carries the
+// JSON-RPC error code to answer with
+type ResourceError struct {
+	Code    int
+	Message string
+}
+
+func (e *ResourceError) Error() string {
+	return e.Message
+}
+
+// SetFCConfigReader enables flyingforge://fc-config resources
+func (h *Handler) SetFCConfigReader(fcConfigReader FCConfigReader) {
+	h.fcConfigReader = fcConfigReader
+}
+
+// SetBuildReader enables flyingforge://build resources
+func (h *Handler) SetBuildReader(buildReader BuildReader) {
+	h.buildReader = buildReader
+}
+
+// SetResourceChangeNotifier is called with the user's ID after a write tool
+// adds something resources/list returns, so clients can be told
+// notifications/resources/list_changed
+func (h *Handler) SetResourceChangeNotifier(notify func(ctx context.Context, userID string)) {
+	h.resourcesChanged = notify
+}
+
+func (h *Handler) notifiesResourceChanges() bool {
+	return h.resourcesChanged != nil
+}
+
+func (h *Handler) notifyResourcesChanged(ctx context.Context, userID string) {
+	if h.resourcesChanged != nil {
+		h.resourcesChanged(ctx, userID)
+	}
+}
+
+// ListResourceTemplates describes every resource URI the server can read
+func (h *Handler) ListResourceTemplates() []ResourceTemplate {
+	templates := []ResourceTemplate{}
+	if h.aircraftSvc != nil {
+		templates = append(templates, ResourceTemplate{
+			URITemplate: resourceURIPrefix + "aircraft/{id}",
+			Name:        "aircraft",
+			Title:       "Aircraft",
+			Description: "One of your aircraft with its installed components. Raw receiver settings are omitted.",
+			MimeType:    "application/json",
+		})
+	}
+	if h.fcConfigReader != nil {
+		templates = append(templates, ResourceTemplate{
+			URITemplate: resourceURIPrefix + "fc-config/{id}/raw",
+			Name:        "fc-config-raw",
+			Title:       "FC config CLI dump",
+			Description: "The raw Betaflight CLI dump saved in one of your flight controller configs.",
+			MimeType:    "text/plain",
+		})
+	}
+	if h.buildReader != nil {
+		templates = append(templates, ResourceTemplate{
+			URITemplate: resourceURIPrefix + "build/{id}",
+			Name:        "build",
+			Title:       "Build",
+			Description: "One of your builds, published or draft, with its parts.",
+			MimeType:    "application/json",
+		})
+	}
+	return templates
+}
+
+// ListResources lists the linked user's aircraft, FC configs and builds
+func (h *Handler) ListResources(ctx context.Context) ([]Resource, error) {
+	userID, err := h.requireResourceUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []Resource{}
+	if h.aircraftSvc != nil {
+		response, err := h.aircraftSvc.List(ctx, userID, models.AircraftListParams{Limit: maxListedResources})
+		if err != nil {
+			return nil, err
+		}
+		for _, aircraft := range response.Aircraft {
+			resources = append(resources, Resource{
+				URI:      resourceURIPrefix + "aircraft/" + aircraft.ID,
+				Name:     aircraft.Name,
+				Title:    aircraft.Name,
+				MimeType: "application/json",
+			})
+		}
+	}
+	if h.fcConfigReader != nil {
+		response, err := h.fcConfigReader.ListConfigs(ctx, userID, models.FCConfigListParams{Limit: maxListedResources})
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range response.Configs {
+			resources = append(resources, Resource{
+				URI:         resourceURIPrefix + "fc-config/" + config.ID + "/raw",
+				Name:        config.Name,
+				Title:       config.Name + " (CLI dump)",
+				Description: strings.TrimSpace(string(config.FirmwareName) + " " + config.FirmwareVersion + " " + config.BoardName),
+				MimeType:    "text/plain",
+			})
+		}
+	}
+	if h.buildReader != nil {
+		response, err := h.buildReader.ListByOwner(ctx, userID, models.BuildListParams{Limit: maxListedResources})
+		if err != nil {
+			return nil, err
+		}
+		for _, build := range response.Builds {
+			resources = append(resources, Resource{
+				URI:         resourceURIPrefix + "build/" + build.ID,
+				Name:        build.Title,
+				Title:       build.Title,
+				Description: string(build.Status) + " build",
+				MimeType:    "application/json",
+			})
+		}
+	}
+	return resources, nil
+}
+
+// ReadResource reads one flyingforge:// resource owned by the linked user
+func (h *Handler) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	userID, err := h.requireResourceUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, id, suffix := parseResourceURI(uri)
+	switch {
+	case kind == "aircraft" && suffix == "" && h.aircraftSvc != nil:
+		details, err := h.aircraftSvc.GetDetails(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if details == nil {
+			return nil, resourceNotFound(uri)
+		}
+		return jsonResourceContents(uri, aircraftDetailsToolResponse{
+			Aircraft:            summarizeAircraft(details.Aircraft),
+			Components:          summarizeAircraftComponents(details.Components),
+			HasReceiverSettings: details.ReceiverSettings != nil,
+		})
+
+	case kind == "fc-config" && suffix == "raw" && h.fcConfigReader != nil:
+		config, err := h.fcConfigReader.GetConfig(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, resourceNotFound(uri)
+		}
+		return []ResourceContents{{URI: uri, MimeType: "text/plain", Text: config.RawCLIDump}}, nil
+
+	case kind == "build" && suffix == "" && h.buildReader != nil:
+		build, err := h.buildReader.GetByOwner(ctx, id, userID)
+		if err != nil {
+			return nil, err
+		}
+		if build == nil {
+			return nil, resourceNotFound(uri)
+		}
+		build.OwnerUserID = ""
+		return jsonResourceContents(uri, build)
+
+	default:
+		return nil, resourceNotFound(uri)
+	}
+}
+
+// requireResourceUser applies the private tool auth rules to resources and
+// private prompts
+func (h *Handler) requireResourceUser(ctx context.Context) (string, error) {
+	userID, err := h.requireAuthenticatedUser(ctx)
+	if err != nil {
+		return "", &ResourceError{Code: resourceAuthRequiredCode, Message: err.Error()}
+	}
+	return userID, nil
+}
+
+// parseResourceURI splits flyingforge://{kind}/{id}[/{suffix}]. Unknown shapes
+// come back with an empty kind.
+func parseResourceURI(uri string) (kind, id, suffix string) {
+	if !strings.HasPrefix(uri, resourceURIPrefix) {
+		return "", "", ""
+	}
+	parts := strings.Split(strings.TrimPrefix(uri, resourceURIPrefix), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", ""
+	}
+	if len(parts) == 3 {
+		suffix = parts[2]
+	}
+	return parts[0], parts[1], suffix
+}
+
+func jsonResourceContents(uri string, value interface{}) ([]ResourceContents, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return []ResourceContents{{URI: uri, MimeType: "application/json", Text: string(data)}}, nil
+}
+
+func resourceNotFound(uri string) error {
+	return &ResourceError{Code: resourceNotFoundCode, Message: "Resource not found: " + uri}
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+type stubFCConfigReader struct {
+	configs map[string]*models.FlightControllerConfig
+}
+
+func (s *stubFCConfigReader) GetConfig(_ context.Context, id string, _ string) (*models.FlightControllerConfig, error) {
+	return s.configs[id], nil
+}
+
+func (s *stubFCConfigReader) ListConfigs(_ context.Context, _ string, _ models.FCConfigListParams) (*models.FCConfigListResponse, error) {
+	response := &models.FCConfigListResponse{}
+	for _, config := range s.configs {
+		response.Configs = append(response.Configs, *config)
+	}
+	return response, nil
+}
+
+type stubBuildReader struct {
+	build *models.Build
+}
+
+func (s *stubBuildReader) ListByOwner(_ context.Context, _ string, _ models.BuildListParams) (*models.BuildListResponse, error) {
+	return &models.BuildListResponse{Builds: []models.Build{*s.build}}, nil
+}
+
+func (s *stubBuildReader) GetByOwner(_ context.Context, id string, _ string) (*models.Build, error) {
+	if id != s.build.ID {
+		return nil, nil
+	}
+	copied := *s.build
+	return &copied, nil
+}
+
+func newResourceTestProtocol() *Protocol {
+	handler := NewHandler(
+		nil,
+		nil,
+		&stubAircraftReader{
+			listResponse: &models.AircraftListResponse{Aircraft: []models.Aircraft{{ID: "air-1", Name: "Demo Quad"}}},
+			detailsResponse: &models.AircraftDetailsResponse{
+				Aircraft:         models.Aircraft{ID: "air-1", Name: "Demo Quad"},
+				ReceiverSettings: &models.AircraftReceiverSettings{},
+			},
+		},
+		nil,
+		nil,
+		[]string{"flyingforge.read"},
+		testutil.NullLogger(),
+	)
+	handler.SetFCConfigReader(&stubFCConfigReader{configs: map[string]*models.FlightControllerConfig{
+		"cfg-1": {ID: "cfg-1", Name: "Field tune", RawCLIDump: "# diff all\nset gyro_lpf1_static_hz = 250"},
+	}})
+	handler.SetBuildReader(&stubBuildReader{build: &models.Build{ID: "build-1", Title: "5in freestyle", OwnerUserID: "user-1", Status: models.BuildStatusDraft}})
+	return NewProtocol(handler, testutil.NullLogger())
+}
+
+func callProtocol(t *testing.T, protocol *Protocol, ctx context.Context, request string, result interface{}) *RPCError {
+	t.Helper()
+	response := protocol.HandleMessage(ctx, []byte(request))
+	if response.Error != nil {
+		return response.Error
+	}
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	return nil
+}
+
+func TestResourcesListRequiresLinkedAccount(t *testing.T) {
+	protocol := newResourceTestProtocol()
+
+	var result ResourcesListResult
+	rpcErr := callProtocol(t, protocol, context.Background(), `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, &result)
+	if rpcErr == nil || rpcErr.Code != resourceAuthRequiredCode {
+		t.Fatalf("expected auth error, got %+v", rpcErr)
+	}
+
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), `{"jsonrpc":"2.0","id":2,"method":"resources/list"}`, &result); rpcErr != nil {
+		t.Fatalf("expected resources, got %+v", rpcErr)
+	}
+	uris := []string{}
+	for _, resource := range result.Resources {
+		uris = append(uris, resource.URI)
+	}
+	want := []string{"flyingforge://aircraft/air-1", "flyingforge://fc-config/cfg-1/raw", "flyingforge://build/build-1"}
+	if strings.Join(uris, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected %v, got %v", want, uris)
+	}
+}
+
+func TestResourceTemplatesListIsPublic(t *testing.T) {
+	var result ResourceTemplatesListResult
+	if rpcErr := callProtocol(t, newResourceTestProtocol(), context.Background(), `{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`, &result); rpcErr != nil {
+		t.Fatalf("expected templates, got %+v", rpcErr)
+	}
+	if len(result.ResourceTemplates) != 3 || result.ResourceTemplates[1].URITemplate != "flyingforge://fc-config/{id}/raw" {
+		t.Fatalf("unexpected templates: %+v", result.ResourceTemplates)
+	}
+}
+
+func TestResourcesReadReturnsContents(t *testing.T) {
+	protocol := newResourceTestProtocol()
+
+	var result ReadResourceResult
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"flyingforge://fc-config/cfg-1/raw"}}`, &result); rpcErr != nil {
+		t.Fatalf("expected FC config dump, got %+v", rpcErr)
+	}
+	if len(result.Contents) != 1 || result.Contents[0].MimeType != "text/plain" || !strings.HasPrefix(result.Contents[0].Text, "# diff all") {
+		t.Fatalf("unexpected FC config contents: %+v", result.Contents)
+	}
+
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"flyingforge://build/build-1"}}`, &result); rpcErr != nil {
+		t.Fatalf("expected build, got %+v", rpcErr)
+	}
+	if text := result.Contents[0].Text; !strings.Contains(text, "5in freestyle") || strings.Contains(text, "user-1") {
+		t.Fatalf("expected build JSON without the owner ID, got %s", text)
+	}
+
+	if rpcErr := callProtocol(t, protocol, authenticatedContext(), `{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"flyingforge://aircraft/air-1"}}`, &result); rpcErr != nil {
+		t.Fatalf("expected aircraft, got %+v", rpcErr)
+	}
+	var aircraft aircraftDetailsToolResponse
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &aircraft); err != nil {
+		t.Fatalf("failed to decode aircraft resource: %v", err)
+	}
+	if aircraft.Aircraft.Name != "Demo Quad" || !aircraft.HasReceiverSettings {
+		t.Fatalf("unexpected aircraft resource: %+v", aircraft)
+	}
+}
+
+func TestResourcesReadNotFound(t *testing.T) {
+	protocol := newResourceTestProtocol()
+	for _, uri := range []string{
+		"flyingforge://build/someone-elses",
+		"flyingforge://fc-config/cfg-1",
+		"flyingforge://radio/radio-1",
+		"https://example.com/build/build-1",
+	} {
+		var result ReadResourceResult
+		rpcErr := callProtocol(t, protocol, authenticatedContext(), `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`, &result)
+		if rpcErr == nil || rpcErr.Code != resourceNotFoundCode {
+			t.Fatalf("expected not found for %s, got %+v", uri, rpcErr)
+		}
+	}
+}
+
+func TestHTTPHandlerChallengesResourceReadWithoutToken(t *testing.T) {
+	protocol := newResourceTestProtocol()
+	handler := NewHTTPHandler(protocol, &fakeHTTPAuthProvider{userID: "user-1"}, nil, testutil.NullLogger())
+
+	requestBody := []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"flyingforge://build/build-1"}}`)
+	request := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(responseRecorder, request)
+
+	if got := responseRecorder.Header().Get("WWW-Authenticate"); got == "" {
+		t.Fatal("expected auth challenge header for resource read without token")
+	}
+	var response Response
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Error == nil || response.Error.Code != resourceAuthRequiredCode || response.Error.Data == nil {
+		t.Fatalf("expected auth error with challenge metadata, got %+v", response.Error)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(requestBody))
+	request.Header.Set("Authorization", "Bearer token")
+	responseRecorder = httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+	var authenticated Response
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &authenticated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if authenticated.Error != nil {
+		t.Fatalf("expected authenticated read to succeed, got %+v", authenticated.Error)
+	}
+}
@@ -2,15 +2,24 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/johnrirwin/flyingforge/internal/logging"
 )
 
+// stdioCancelScope is the cancel scope of the single stdio client
+const stdioCancelScope = "stdio"
+
+// maxStdioWorkers caps how many lines are handled at once. Reading stops
+// while every worker is busy, so a client flooding stdin is held back.
+const maxStdioWorkers = 8
+
 // Server runs the MCP protocol over stdio for local clients.
 type Server struct {
 	protocol *Protocol
@@ -25,42 +34,104 @@ func NewServer(protocol *Protocol, logger *logging.Logger) *Server {
 }
 
 func (s *Server) Run(ctx context.Context) error {
-	reader := bufio.NewReader(os.Stdin)
-	writer := os.Stdout
-
 	s.logger.Info("MCP stdio server started, waiting for requests...")
+	return s.serve(ctx, os.Stdin, os.Stdout)
+}
+
+// serve handles lines on up to maxStdioWorkers goroutines so a slow tool call
+// doesn't hold up the rest. notifications/cancelled lines are handled as soon
+// as they are read, without waiting for a worker, so they reach a running call
+// even when every worker is busy.
+// Responses and notifications are written whole, one per line, in completion
+// order; clients match responses to requests by ID.
+func (s *Server) serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writeMu sync.Mutex
+	var writeErr error
+	write := func(message interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			s.logger.Error("Failed to marshal MCP response", logging.WithField("error", err.Error()))
+			return
+		}
+		data = append(data, '\n')
+
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if writeErr != nil {
+			return
+		}
+		if _, err := out.Write(data); err != nil {
+			writeErr = err
+			cancel()
+		}
+	}
+
+	ctx = WithCancelScope(ctx, stdioCancelScope)
+	ctx = WithNotifier(ctx, func(notification Notification) {
+		write(notification)
+	})
 
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	workers := make(chan struct{}, maxStdioWorkers)
+
+	reader := bufio.NewReader(in)
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			line, err := reader.ReadBytes('\n')
+			writeMu.Lock()
+			err := writeErr
+			writeMu.Unlock()
 			if err != nil {
-				if err == io.EOF {
-					return nil
-				}
-				return fmt.Errorf("read error: %w", err)
-			}
-
-			if len(line) == 0 {
-				continue
-			}
-
-			response := s.protocol.HandleMessage(ctx, line)
-			if response == nil {
-				continue
+				return fmt.Errorf("write error: %w", err)
 			}
+			return ctx.Err()
+		default:
+		}
 
-			data, err := json.Marshal(response)
-			if err != nil {
-				s.logger.Error("Failed to marshal MCP response", logging.WithField("error", err.Error()))
+		line, err := reader.ReadBytes('\n')
+		switch {
+		case len(bytes.TrimSpace(line)) == 0:
+		case isCancelNotification(line):
+			s.protocol.HandlePayload(ctx, line)
+		default:
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
 				continue
 			}
-			data = append(data, '\n')
-			if _, err := writer.Write(data); err != nil {
-				return fmt.Errorf("write error: %w", err)
+			wg.Add(1)
+			go func(line []byte) {
+				defer func() {
+					<-workers
+					wg.Done()
+				}()
+				if response := s.protocol.HandlePayload(ctx, line); response != nil {
+					write(response)
+				}
+			}(line)
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
 			}
+			return fmt.Errorf("read error: %w", err)
 		}
 	}
 }
+
+// isCancelNotification reports whether a line is a single
+// notifications/cancelled message
+func isCancelNotification(line []byte) bool {
+	var message struct {
+		Method string      `json:"method"`
+		ID     interface{} `json:"id"`
+	}
+	if err := json.Unmarshal(line, &message); err != nil {
+		return false
+	}
+	return message.Method == "notifications/cancelled" && message.ID == nil
+}
@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSessionTTL       = time.Hour
+	defaultSessionMaxEvents = 100
+	defaultMaxSessions      = 1000
+)
+
+// ErrSessionNotFound is returned for unknown, expired or terminated sessions
+var ErrSessionNotFound = errors.New("mcp session not found")
+
+// Session is a Streamable HTTP session issued on initialize
+type Session struct {
+	ID              string `json:"id"`
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	// UserID is the user the session is bound to. It is set by the first
+	// authenticated request and every later request must match it.
+	UserID    string    `json:"userId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SessionEvent is a server-to-client message queued on a session's SSE
+// stream. IDs increase within a session so clients can resume with
+// Last-Event-ID.
+type SessionEvent struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// SessionStore keeps sessions and their recent events. Sessions expire after
+// a period without use; each Get extends it.
+type SessionStore interface {
+	Create(ctx context.Context, protocolVersion string, userID string) (*Session, error)
+	Get(ctx context.Context, id string) (*Session, error)
+	BindUser(ctx context.Context, id string, userID string) (*Session, error)
+	Delete(ctx context.Context, id string) (bool, error)
+	ListByUser(ctx context.Context, userID string) ([]string, error)
+	AppendEvent(ctx context.Context, sessionID string, data []byte) (*SessionEvent, error)
+	EventsAfter(ctx context.Context, sessionID string, lastEventID string) ([]SessionEvent, error)
+}
+
+type memorySession struct {
+	session   Session
+	expiresAt time.Time
+	events    []SessionEvent
+	lastSeq   int64
+}
+
+// InMemorySessionStore keeps sessions in process. Use the Redis store when
+// several instances serve /mcp.
+type InMemorySessionStore struct {
+	mu          sync.Mutex
+	sessions    map[string]*memorySession
+	ttl         time.Duration
+	maxEvents   int
+	maxSessions int
+}
+
+// NewInMemorySessionStore creates a session store with the provided idle TTL.
+func NewInMemorySessionStore(ttl time.Duration) *InMemorySessionStore {
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+
+	return &InMemorySessionStore{
+		sessions:    make(map[string]*memorySession),
+		ttl:         ttl,
+		maxEvents:   defaultSessionMaxEvents,
+		maxSessions: defaultMaxSessions,
+	}
+}
+
+// Create issues a new session, evicting the one closest to expiry when full.
+func (s *InMemorySessionStore) Create(_ context.Context, protocolVersion string, userID string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.cleanupLocked(now)
+	if len(s.sessions) >= s.maxSessions {
+		s.evictOldestLocked()
+	}
+
+	session := Session{
+		ID:              uuid.NewString(),
+		ProtocolVersion: protocolVersion,
+		UserID:          userID,
+		CreatedAt:       now,
+	}
+	s.sessions[session.ID] = &memorySession{session: session, expiresAt: now.Add(s.ttl)}
+
+	copied := session
+	return &copied, nil
+}
+
+// Get returns the session and extends its expiry, or nil when it is gone.
+func (s *InMemorySessionStore) Get(_ context.Context, id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.liveLocked(id, time.Now())
+	if entry == nil {
+		return nil, nil
+	}
+
+	copied := entry.session
+	return &copied, nil
+}
+
+// BindUser binds an unbound session to userID and returns the session as
+// stored, which may already belong to another user.
+func (s *InMemorySessionStore) BindUser(_ context.Context, id string, userID string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.liveLocked(id, time.Now())
+	if entry == nil {
+		return nil, ErrSessionNotFound
+	}
+	if entry.session.UserID == "" {
+		entry.session.UserID = userID
+	}
+
+	copied := entry.session
+	return &copied, nil
+}
+
+// Delete terminates a session and reports whether it existed.
+func (s *InMemorySessionStore) Delete(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.liveLocked(id, time.Now())
+	delete(s.sessions, id)
+	return entry != nil, nil
+}
+
+// ListByUser returns the IDs of the live sessions bound to userID.
+func (s *InMemorySessionStore) ListByUser(_ context.Context, userID string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanupLocked(time.Now())
+	ids := []string{}
+	for id, entry := range s.sessions {
+		if userID != "" && entry.session.UserID == userID {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// AppendEvent queues a message on a session, keeping the most recent events.
+func (s *InMemorySessionStore) AppendEvent(_ context.Context, sessionID string, data []byte) (*SessionEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.liveLocked(sessionID, time.Now())
+	if entry == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	entry.lastSeq++
+	event := SessionEvent{
+		ID:   strconv.FormatInt(entry.lastSeq, 10),
+		Data: append(json.RawMessage(nil), data...),
+	}
+	entry.events = append(entry.events, event)
+	if len(entry.events) > s.maxEvents {
+		entry.events = append([]SessionEvent(nil), entry.events[len(entry.events)-s.maxEvents:]...)
+	}
+
+	copied := event
+	return &copied, nil
+}
+
+// EventsAfter returns the retained events newer than lastEventID. An empty or
+// unrecognised ID returns everything retained.
+func (s *InMemorySessionStore) EventsAfter(_ context.Context, sessionID string, lastEventID string) ([]SessionEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.liveLocked(sessionID, time.Now())
+	if entry == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return eventsAfter(entry.events, lastEventID), nil
+}
+
+// liveLocked returns an unexpired session and extends its expiry.
+func (s *InMemorySessionStore) liveLocked(id string, now time.Time) *memorySession {
+	entry, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+	if now.After(entry.expiresAt) {
+		delete(s.sessions, id)
+		return nil
+	}
+	entry.expiresAt = now.Add(s.ttl)
+	return entry
+}
+
+func (s *InMemorySessionStore) cleanupLocked(now time.Time) {
+	for id, entry := range s.sessions {
+		if now.After(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func (s *InMemorySessionStore) evictOldestLocked() {
+	oldestID := ""
+	var oldest time.Time
+	for id, entry := range s.sessions {
+		if oldestID == "" || entry.expiresAt.Before(oldest) {
+			oldestID = id
+			oldest = entry.expiresAt
+		}
+	}
+	delete(s.sessions, oldestID)
+}
+
+// eventsAfter filters events to those with a sequence number above lastEventID
+func eventsAfter(events []SessionEvent, lastEventID string) []SessionEvent {
+	lastSeq, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil {
+		lastSeq = 0
+	}
+
+	result := []SessionEvent{}
+	for _, event := range events {
+		seq, err := strconv.ParseInt(event.ID, 10, 64)
+		if err != nil || seq <= lastSeq {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result
+}
+
+var _ SessionStore = (*InMemorySessionStore)(nil)
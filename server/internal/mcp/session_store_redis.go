@@ -0,0 +1,308 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultSessionRedisPrefix  = "mcp-session:"
+	defaultSessionRedisTimeout = 2 * time.Second
+)
+
+// RedisSessionStore keeps sessions in Redis so any instance can serve a
+// session's requests and SSE stream.
+type RedisSessionStore struct {
+	client    *redis.Client
+	ttl       time.Duration
+	prefix    string
+	maxEvents int
+}
+
+// NewRedisSessionStore creates a Redis-backed session store.
+func NewRedisSessionStore(client *redis.Client, ttl time.Duration) *RedisSessionStore {
+	return NewRedisSessionStoreWithPrefix(client, ttl, defaultSessionRedisPrefix)
+}
+
+// NewRedisSessionStoreWithPrefix creates a Redis-backed session store with explicit key prefix.
+func NewRedisSessionStoreWithPrefix(client *redis.Client, ttl time.Duration, prefix string) *RedisSessionStore {
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		prefix = defaultSessionRedisPrefix
+	}
+
+	return &RedisSessionStore{
+		client:    client,
+		ttl:       ttl,
+		prefix:    prefix,
+		maxEvents: defaultSessionMaxEvents,
+	}
+}
+
+func (s *RedisSessionStore) key(id string) string {
+	return s.prefix + id
+}
+
+func (s *RedisSessionStore) eventsKey(id string) string {
+	return s.prefix + id + ":events"
+}
+
+func (s *RedisSessionStore) seqKey(id string) string {
+	return s.prefix + id + ":seq"
+}
+
+// userIndexKey holds the IDs of the sessions bound to one user. Members are
+// pruned as their sessions are found gone.
+func (s *RedisSessionStore) userIndexKey(userID string) string {
+	return s.prefix + "user:" + userID
+}
+
+// Create issues a new session.
+func (s *RedisSessionStore) Create(ctx context.Context, protocolVersion string, userID string) (*Session, error) {
+	session := Session{
+		ID:              uuid.NewString(),
+		ProtocolVersion: protocolVersion,
+		UserID:          userID,
+		CreatedAt:       time.Now(),
+	}
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.key(session.ID), payload, s.ttl)
+	if userID != "" {
+		pipe.SAdd(ctx, s.userIndexKey(userID), session.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Get returns the session and extends its expiry, or nil when it is gone.
+func (s *RedisSessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	session, err := s.load(ctx, s.client, id)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	if err := s.touch(ctx, id); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// BindUser binds an unbound session to userID and returns the session as
+// stored, which may already belong to another user.
+func (s *RedisSessionStore) BindUser(ctx context.Context, id string, userID string) (*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	var bound *Session
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		session, err := s.load(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			return ErrSessionNotFound
+		}
+		bound = session
+		if session.UserID != "" {
+			return nil
+		}
+
+		session.UserID = userID
+		payload, err := json.Marshal(session)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, s.key(id), payload, s.ttl)
+			pipe.SAdd(ctx, s.userIndexKey(userID), id)
+			return nil
+		})
+		return err
+	}, s.key(id))
+	if err != nil {
+		return nil, err
+	}
+	return bound, nil
+}
+
+// Delete terminates a session and reports whether it existed.
+func (s *RedisSessionStore) Delete(ctx context.Context, id string) (bool, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	session, err := s.load(ctx, s.client, id)
+	if err != nil {
+		return false, err
+	}
+
+	pipe := s.client.TxPipeline()
+	deleted := pipe.Del(ctx, s.key(id))
+	pipe.Del(ctx, s.eventsKey(id), s.seqKey(id))
+	if session != nil && session.UserID != "" {
+		pipe.SRem(ctx, s.userIndexKey(session.UserID), id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	return deleted.Val() > 0, nil
+}
+
+// ListByUser returns the IDs of the live sessions bound to userID, pruning
+// expired ones from the user's index.
+func (s *RedisSessionStore) ListByUser(ctx context.Context, userID string) ([]string, error) {
+	if userID == "" {
+		return []string{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	members, err := s.client.SMembers(ctx, s.userIndexKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, id := range members {
+		exists, err := s.client.Exists(ctx, s.key(id)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			s.client.SRem(ctx, s.userIndexKey(userID), id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// AppendEvent queues a message on a session, keeping the most recent events.
+func (s *RedisSessionStore) AppendEvent(ctx context.Context, sessionID string, data []byte) (*SessionEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	exists, err := s.client.Exists(ctx, s.key(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	seq, err := s.client.Incr(ctx, s.seqKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	event := SessionEvent{ID: strconv.FormatInt(seq, 10), Data: append(json.RawMessage(nil), data...)}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.RPush(ctx, s.eventsKey(sessionID), payload)
+	pipe.LTrim(ctx, s.eventsKey(sessionID), int64(-s.maxEvents), -1)
+	pipe.Expire(ctx, s.eventsKey(sessionID), s.ttl)
+	pipe.Expire(ctx, s.seqKey(sessionID), s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// EventsAfter returns the retained events newer than lastEventID and, like
+// Get, extends the session's expiry so an open stream keeps it alive.
+func (s *RedisSessionStore) EventsAfter(ctx context.Context, sessionID string, lastEventID string) ([]SessionEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultSessionRedisTimeout)
+	defer cancel()
+
+	exists, err := s.client.Exists(ctx, s.key(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, ErrSessionNotFound
+	}
+	if err := s.touch(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	payloads, err := s.client.LRange(ctx, s.eventsKey(sessionID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]SessionEvent, 0, len(payloads))
+	for _, payload := range payloads {
+		var event SessionEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return eventsAfter(events, lastEventID), nil
+}
+
+func (s *RedisSessionStore) load(ctx context.Context, client redis.Cmdable, id string) (*Session, error) {
+	payload, err := client.Get(ctx, s.key(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// touch extends the expiry of a session and its events
+func (s *RedisSessionStore) touch(ctx context.Context, id string) error {
+	pipe := s.client.Pipeline()
+	pipe.Expire(ctx, s.key(id), s.ttl)
+	pipe.Expire(ctx, s.eventsKey(id), s.ttl)
+	pipe.Expire(ctx, s.seqKey(id), s.ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+var _ SessionStore = (*RedisSessionStore)(nil)
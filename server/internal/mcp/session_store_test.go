@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInMemorySessionStoreEventsResumeAfterLastEventID(t *testing.T) {
+	store := NewInMemorySessionStore(time.Hour)
+	ctx := context.Background()
+
+	session, err := store.Create(ctx, protocolVersion, "")
+	if err != nil || session.ID == "" {
+		t.Fatalf("Create() = %+v, %v", session, err)
+	}
+	for _, data := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+		if _, err := store.AppendEvent(ctx, session.ID, []byte(data)); err != nil {
+			t.Fatalf("AppendEvent() error = %v", err)
+		}
+	}
+
+	events, err := store.EventsAfter(ctx, session.ID, "1")
+	if err != nil {
+		t.Fatalf("EventsAfter() error = %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "2" || string(events[1].Data) != `{"n":3}` {
+		t.Fatalf("EventsAfter(1) = %+v, want events 2 and 3", events)
+	}
+	if all, _ := store.EventsAfter(ctx, session.ID, ""); len(all) != 3 {
+		t.Fatalf("EventsAfter(\"\") = %+v, want all 3 events", all)
+	}
+}
+
+func TestInMemorySessionStoreKeepsRecentEvents(t *testing.T) {
+	store := NewInMemorySessionStore(time.Hour)
+	store.maxEvents = 2
+	ctx := context.Background()
+
+	session, _ := store.Create(ctx, protocolVersion, "")
+	for i := 0; i < 3; i++ {
+		store.AppendEvent(ctx, session.ID, []byte(`{}`))
+	}
+
+	events, _ := store.EventsAfter(ctx, session.ID, "")
+	if len(events) != 2 || events[0].ID != "2" {
+		t.Fatalf("EventsAfter() = %+v, want the 2 newest events", events)
+	}
+}
+
+func TestInMemorySessionStoreExpiresAndDeletes(t *testing.T) {
+	store := NewInMemorySessionStore(time.Millisecond)
+	ctx := context.Background()
+
+	expired, _ := store.Create(ctx, protocolVersion, "")
+	time.Sleep(5 * time.Millisecond)
+	if session, _ := store.Get(ctx, expired.ID); session != nil {
+		t.Fatalf("Get() = %+v, want expired session to be gone", session)
+	}
+	if _, err := store.AppendEvent(ctx, expired.ID, []byte(`{}`)); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("AppendEvent() error = %v, want ErrSessionNotFound", err)
+	}
+
+	store.ttl = time.Hour
+	session, _ := store.Create(ctx, protocolVersion, "")
+	if deleted, _ := store.Delete(ctx, session.ID); !deleted {
+		t.Fatal("expected Delete() to report the live session")
+	}
+	if deleted, _ := store.Delete(ctx, session.ID); deleted {
+		t.Fatal("expected a second Delete() to report nothing deleted")
+	}
+}
+
+func TestInMemorySessionStoreEvictsWhenFull(t *testing.T) {
+	store := NewInMemorySessionStore(time.Hour)
+	store.maxSessions = 2
+	ctx := context.Background()
+
+	first, _ := store.Create(ctx, protocolVersion, "user-1")
+	store.Create(ctx, protocolVersion, "user-1")
+	store.Create(ctx, protocolVersion, "user-1")
+
+	if ids, _ := store.ListByUser(ctx, "user-1"); len(ids) != 2 {
+		t.Fatalf("ListByUser() = %v, want 2 sessions", ids)
+	}
+	if session, _ := store.Get(ctx, first.ID); session != nil {
+		t.Fatal("expected the oldest session to be evicted")
+	}
+}
+
+func TestInMemorySessionStoreBindsUserOnce(t *testing.T) {
+	store := NewInMemorySessionStore(time.Hour)
+	ctx := context.Background()
+
+	session, _ := store.Create(ctx, protocolVersion, "")
+	if ids, _ := store.ListByUser(ctx, ""); len(ids) != 0 {
+		t.Fatalf("ListByUser(\"\") = %v, want unbound sessions left out", ids)
+	}
+
+	bound, err := store.BindUser(ctx, session.ID, "user-1")
+	if err != nil || bound.UserID != "user-1" {
+		t.Fatalf("BindUser() = %+v, %v, want the session bound to user-1", bound, err)
+	}
+	if again, _ := store.BindUser(ctx, session.ID, "user-2"); again.UserID != "user-1" {
+		t.Fatalf("BindUser() = %+v, want the first binding kept", again)
+	}
+	if ids, _ := store.ListByUser(ctx, "user-1"); len(ids) != 1 || ids[0] != session.ID {
+		t.Fatalf("ListByUser() = %v, want the bound session", ids)
+	}
+	if _, err := store.BindUser(ctx, "unknown", "user-1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("BindUser() error = %v, want ErrSessionNotFound", err)
+	}
+}
@@ -0,0 +1,491 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+type BatteryCycleLogger interface {
+	CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error)
+}
+
+type InventoryWriter interface {
+	AddItem(ctx context.Context, userID string, params models.AddInventoryParams) (*models.InventoryItem, error)
+}
+
+type AircraftComponentWriter interface {
+	SetComponent(ctx context.Context, userID string, params models.SetComponentParams) (*models.AircraftComponent, error)
+}
+
+type FCConfigSaver interface {
+	SaveConfig(ctx context.Context, userID string, params models.SaveFCConfigParams) (*models.FlightControllerConfig, error)
+}
+
+type BuildDraftCreator interface {
+	CreateDraft(ctx context.Context, ownerUserID string, params models.CreateBuildParams) (*models.Build, error)
+}
+
+// writeToolScopes maps each tool that changes a user's data to the OAuth
+// scope the access token needs on top of the read scopes
+var writeToolScopes = map[string]string{
+	"log_flight_session":     models.MCPScopeFlightsWrite,
+	"log_battery_cycle":      models.MCPScopeBatteriesWrite,
+	"add_inventory_item":     models.MCPScopeInventoryWrite,
+	"set_aircraft_component": models.MCPScopeAircraftWrite,
+	"save_fc_config":         models.MCPScopeAircraftWrite,
+	"create_build_draft":     models.MCPScopeBuildsWrite,
+}
+
+// SetBatteryCycleLogger enables the log_battery_cycle tool
+func (h *Handler) SetBatteryCycleLogger(batteryLogger BatteryCycleLogger) {
+	h.batteryLogger = batteryLogger
+}
+
+// SetInventoryWriter enables the add_inventory_item tool
+func (h *Handler) SetInventoryWriter(inventoryWriter InventoryWriter) {
+	h.inventoryWriter = inventoryWriter
+}
+
+// SetAircraftComponentWriter enables the set_aircraft_component tool
+func (h *Handler) SetAircraftComponentWriter(componentWriter AircraftComponentWriter) {
+	h.componentWriter = componentWriter
+}
+
+// SetFCConfigSaver enables the save_fc_config tool
+func (h *Handler) SetFCConfigSaver(fcConfigSaver FCConfigSaver) {
+	h.fcConfigSaver = fcConfigSaver
+}
+
+// SetBuildDraftCreator enables the create_build_draft tool
+func (h *Handler) SetBuildDraftCreator(buildCreator BuildDraftCreator) {
+	h.buildCreator = buildCreator
+}
+
+// ToolScope returns the extra OAuth scope a tool needs, or "" for tools that
+// only need the read scopes
+func (h *Handler) ToolScope(name string) string {
+	return writeToolScopes[name]
+}
+
+// writeScopes lists the scopes advertised for a write tool
+func (h *Handler) writeScopes(name string) []string {
+	return append(append([]string(nil), h.privateScopes...), writeToolScopes[name])
+}
+
+// requireToolScope rejects calls to a write tool whose access token wasn't
+// granted the tool's scope
+func (h *Handler) requireToolScope(ctx context.Context, name string) error {
+	scope := h.ToolScope(name)
+	if scope == "" || RequestAuthFromContext(ctx).HasScope(scope) {
+		return nil
+	}
+	return &ToolError{Message: missingScopeMessage(scope)}
+}
+
+func missingScopeMessage(scope string) string {
+	return fmt.Sprintf("This tool requires the %s scope. Reconnect FlyingForge and approve %s access.", scope, scope)
+}
+
+func (h *Handler) getWriteTools() []ToolDefinition {
+	tools := []ToolDefinition{}
+
+	if h.batteryLogger != nil {
+		tools = append(tools, ToolDefinition{
+			Name:        "log_battery_cycle",
+			Title:       "Log battery cycle",
+			Description: "Log charge cycles and health readings for one of the linked user's batteries.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"batteryId": {
+						"type": "string",
+						"description": "The FlyingForge battery ID."
+					},
+					"aircraftId": {
+						"type": "string",
+						"description": "Optional aircraft the pack was flown on."
+					},
+					"cycles": {
+						"type": "integer",
+						"description": "Number of cycles to add (default: 1)."
+					},
+					"irMilliohmsPerCell": {
+						"type": "array",
+						"items": { "type": "number" },
+						"description": "Internal resistance per cell in milliohms. Must list one value per cell."
+					},
+					"minCellVoltage": {
+						"type": "number",
+						"description": "Lowest cell voltage seen."
+					},
+					"maxCellVoltage": {
+						"type": "number",
+						"description": "Highest cell voltage seen."
+					},
+					"storageVoltageOk": {
+						"type": "boolean",
+						"description": "Whether the pack was left at storage voltage."
+					},
+					"notes": {
+						"type": "string",
+						"description": "Optional notes."
+					},
+					"loggedAt": {
+						"type": "string",
+						"description": "RFC 3339 date/time of the cycle (default: now)."
+					}
+				},
+				"required": ["batteryId"]
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "oauth2", Scopes: h.writeScopes("log_battery_cycle")}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: false},
+		})
+	}
+
+	if h.inventoryWriter != nil {
+		tools = append(tools, ToolDefinition{
+			Name:        "add_inventory_item",
+			Title:       "Add inventory item",
+			Description: "Add gear to the linked user's FlyingForge inventory.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"name": {
+						"type": "string",
+						"description": "Item name, for example \"Tattu R-Line 6S 1200mAh\"."
+					},
+					"category": {
+						"type": "string",
+						"description": "Equipment category, for example motors, batteries, flight_controllers or propellers."
+					},
+					"manufacturer": {
+						"type": "string",
+						"description": "Optional manufacturer."
+					},
+					"quantity": {
+						"type": "integer",
+						"description": "How many the user owns (default: 1)."
+					},
+					"notes": {
+						"type": "string",
+						"description": "Optional notes."
+					},
+					"purchasePrice": {
+						"type": "number",
+						"description": "Optional price paid per item."
+					},
+					"purchaseSeller": {
+						"type": "string",
+						"description": "Optional seller the item was bought from."
+					},
+					"productUrl": {
+						"type": "string",
+						"description": "Optional product page URL."
+					},
+					"catalogId": {
+						"type": "string",
+						"description": "Optional gear catalog item ID to link."
+					}
+				},
+				"required": ["name", "category"]
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "oauth2", Scopes: h.writeScopes("add_inventory_item")}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: false},
+		})
+	}
+
+	if h.componentWriter != nil {
+		tools = append(tools, ToolDefinition{
+			Name:        "set_aircraft_component",
+			Title:       "Set aircraft component",
+			Description: "Install an inventory item on one of the linked user's aircraft, replacing whatever is in that slot.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"aircraftId": {
+						"type": "string",
+						"description": "The FlyingForge aircraft ID."
+					},
+					"category": {
+						"type": "string",
+						"description": "Component slot: fc, esc, aio, stack, receiver, vtx, motors, camera, frame, propellers, antenna or gps."
+					},
+					"inventoryItemId": {
+						"type": "string",
+						"description": "The inventory item to install."
+					},
+					"notes": {
+						"type": "string",
+						"description": "Optional notes about the install."
+					}
+				},
+				"required": ["aircraftId", "category", "inventoryItemId"]
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "oauth2", Scopes: h.writeScopes("set_aircraft_component")}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: false},
+		})
+	}
+
+	if h.fcConfigSaver != nil {
+		tools = append(tools, ToolDefinition{
+			Name:        "save_fc_config",
+			Title:       "Save FC config",
+			Description: "Save a Betaflight CLI dump or diff against one of the linked user's flight controllers. If the FC is installed on an aircraft, a tuning snapshot is recorded for it.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"inventoryItemId": {
+						"type": "string",
+						"description": "The inventory item ID of the flight controller."
+					},
+					"rawCliDump": {
+						"type": "string",
+						"description": "Full output of the Betaflight CLI dump or diff command."
+					},
+					"name": {
+						"type": "string",
+						"description": "Optional name for this backup."
+					},
+					"notes": {
+						"type": "string",
+						"description": "Optional notes."
+					}
+				},
+				"required": ["inventoryItemId", "rawCliDump"]
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "oauth2", Scopes: h.writeScopes("save_fc_config")}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: false},
+		})
+	}
+
+	if h.buildCreator != nil {
+		tools = append(tools, ToolDefinition{
+			Name:        "create_build_draft",
+			Title:       "Create build draft",
+			Description: "Start a private draft build for the linked user. Drafts are not published.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"title": {
+						"type": "string",
+						"description": "Build title."
+					},
+					"description": {
+						"type": "string",
+						"description": "Optional build description."
+					},
+					"sourceAircraftId": {
+						"type": "string",
+						"description": "Optional aircraft the build is based on."
+					},
+					"parts": {
+						"type": "array",
+						"description": "Optional catalog parts in the build.",
+						"items": {
+							"type": "object",
+							"properties": {
+								"gearType": { "type": "string", "description": "Part type, for example motor, fc, frame or vtx." },
+								"catalogItemId": { "type": "string", "description": "Gear catalog item ID." },
+								"position": { "type": "integer", "description": "Optional position for repeated parts." },
+								"notes": { "type": "string" }
+							},
+							"required": ["gearType", "catalogItemId"]
+						}
+					}
+				}
+			}`),
+			SecuritySchemes: []SecurityScheme{{Type: "oauth2", Scopes: h.writeScopes("create_build_draft")}},
+			Annotations:     &ToolAnnotations{ReadOnlyHint: false},
+		})
+	}
+
+	return tools
+}
+
+func (h *Handler) handleLogBatteryCycle(ctx context.Context, userID string, arguments json.RawMessage) (interface{}, error) {
+	if h.batteryLogger == nil {
+		return nil, &ToolError{Message: "Battery service is unavailable"}
+	}
+
+	var params struct {
+		BatteryID          string    `json:"batteryId"`
+		AircraftID         string    `json:"aircraftId"`
+		Cycles             int       `json:"cycles"`
+		IRMilliohmsPerCell []float64 `json:"irMilliohmsPerCell"`
+		MinCellVoltage     *float64  `json:"minCellVoltage"`
+		MaxCellVoltage     *float64  `json:"maxCellVoltage"`
+		StorageVoltageOK   *bool     `json:"storageVoltageOk"`
+		Notes              string    `json:"notes"`
+		LoggedAt           string    `json:"loggedAt"`
+	}
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+	if strings.TrimSpace(params.BatteryID) == "" {
+		return nil, &ToolError{Message: "batteryId is required"}
+	}
+	if params.Cycles < 0 {
+		return nil, &ToolError{Message: "cycles cannot be negative"}
+	}
+	if params.Cycles == 0 {
+		params.Cycles = 1
+	}
+
+	createParams := models.CreateBatteryLogParams{
+		BatteryID:  strings.TrimSpace(params.BatteryID),
+		AircraftID: params.AircraftID,
+		CycleDelta: params.Cycles,
+		MinCellV:   params.MinCellVoltage,
+		MaxCellV:   params.MaxCellVoltage,
+		StorageOk:  params.StorageVoltageOK,
+		Notes:      params.Notes,
+	}
+	if len(params.IRMilliohmsPerCell) > 0 {
+		irJSON, err := json.Marshal(params.IRMilliohmsPerCell)
+		if err != nil {
+			return nil, &ToolError{Message: "Invalid irMilliohmsPerCell"}
+		}
+		createParams.IRMohmPerCell = irJSON
+	}
+	if loggedAt := strings.TrimSpace(params.LoggedAt); loggedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, loggedAt)
+		if err != nil {
+			return nil, &ToolError{Message: "loggedAt must be an RFC 3339 date/time"}
+		}
+		createParams.LoggedAt = &parsed
+	}
+
+	log, err := h.batteryLogger.CreateLog(ctx, userID, createParams)
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to log battery cycle: " + err.Error()}
+	}
+	log.UserID = ""
+
+	return ToolResultData{
+		StructuredContent: log,
+		Text:              fmt.Sprintf("Logged %d cycle(s) on battery %s.", log.CycleDelta, log.BatteryID),
+	}, nil
+}
+
+func (h *Handler) handleAddInventoryItem(ctx context.Context, userID string, arguments json.RawMessage) (interface{}, error) {
+	if h.inventoryWriter == nil {
+		return nil, &ToolError{Message: "Inventory service is unavailable"}
+	}
+
+	var params models.AddInventoryParams
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	if params.Name == "" {
+		return nil, &ToolError{Message: "name is required"}
+	}
+	if strings.TrimSpace(string(params.Category)) == "" {
+		return nil, &ToolError{Message: "category is required"}
+	}
+	if params.Quantity <= 0 {
+		params.Quantity = 1
+	}
+	// Builds and catalog sources are linked from the app, not by assistants
+	params.BuildID = ""
+	params.SourceEquipmentID = ""
+
+	item, err := h.inventoryWriter.AddItem(ctx, userID, params)
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to add inventory item: " + err.Error()}
+	}
+	item.UserID = ""
+
+	return ToolResultData{
+		StructuredContent: item,
+		Text:              fmt.Sprintf("Added %d x %s to the inventory.", item.Quantity, item.Name),
+	}, nil
+}
+
+func (h *Handler) handleSetAircraftComponent(ctx context.Context, userID string, arguments json.RawMessage) (interface{}, error) {
+	if h.componentWriter == nil {
+		return nil, &ToolError{Message: "Aircraft service is unavailable"}
+	}
+
+	var params struct {
+		AircraftID      string `json:"aircraftId"`
+		Category        string `json:"category"`
+		InventoryItemID string `json:"inventoryItemId"`
+		Notes           string `json:"notes"`
+	}
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+	if strings.TrimSpace(params.AircraftID) == "" || strings.TrimSpace(params.Category) == "" || strings.TrimSpace(params.InventoryItemID) == "" {
+		return nil, &ToolError{Message: "aircraftId, category and inventoryItemId are required"}
+	}
+
+	component, err := h.componentWriter.SetComponent(ctx, userID, models.SetComponentParams{
+		AircraftID:      strings.TrimSpace(params.AircraftID),
+		Category:        models.ComponentCategory(strings.TrimSpace(params.Category)),
+		InventoryItemID: strings.TrimSpace(params.InventoryItemID),
+		Notes:           params.Notes,
+	})
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to set aircraft component: " + err.Error()}
+	}
+
+	return ToolResultData{
+		StructuredContent: summarizeAircraftComponents([]models.AircraftComponent{*component})[0],
+		Text:              fmt.Sprintf("Set the %s on aircraft %s.", component.Category, component.AircraftID),
+	}, nil
+}
+
+func (h *Handler) handleSaveFCConfig(ctx context.Context, userID string, arguments json.RawMessage) (interface{}, error) {
+	if h.fcConfigSaver == nil {
+		return nil, &ToolError{Message: "FC config service is unavailable"}
+	}
+
+	var params models.SaveFCConfigParams
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+	params.InventoryItemID = strings.TrimSpace(params.InventoryItemID)
+	params.Name = strings.TrimSpace(params.Name)
+
+	config, err := h.fcConfigSaver.SaveConfig(ctx, userID, params)
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to save FC config: " + err.Error()}
+	}
+	h.notifyResourcesChanged(ctx, userID)
+	config.UserID = ""
+	config.RawCLIDump = ""
+
+	return ToolResultData{
+		StructuredContent: config,
+		Text:              fmt.Sprintf("Saved FC config %q (%s %s, parse status %s).", config.Name, config.FirmwareName, config.FirmwareVersion, config.ParseStatus),
+	}, nil
+}
+
+func (h *Handler) handleCreateBuildDraft(ctx context.Context, userID string, arguments json.RawMessage) (interface{}, error) {
+	if h.buildCreator == nil {
+		return nil, &ToolError{Message: "Build service is unavailable"}
+	}
+
+	var params models.CreateBuildParams
+	if err := json.Unmarshal(arguments, &params); err != nil {
+		return nil, &ToolError{Message: "Invalid arguments: " + err.Error()}
+	}
+
+	build, err := h.buildCreator.CreateDraft(ctx, userID, params)
+	if err != nil {
+		return nil, &ToolError{Message: "Failed to create build draft: " + err.Error()}
+	}
+	h.notifyResourcesChanged(ctx, userID)
+	build.OwnerUserID = ""
+
+	return ToolResultData{
+		StructuredContent: build,
+		Text:              fmt.Sprintf("Created draft build %q with %d part(s).", build.Title, len(build.Parts)),
+	}, nil
+}
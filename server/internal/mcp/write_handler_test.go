@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+type stubBatteryCycleLogger struct {
+	params models.CreateBatteryLogParams
+}
+
+func (s *stubBatteryCycleLogger) CreateLog(_ context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error) {
+	s.params = params
+	return &models.BatteryLog{ID: "log-1", UserID: userID, BatteryID: params.BatteryID, CycleDelta: params.CycleDelta}, nil
+}
+
+type stubInventoryWriter struct {
+	calls  int
+	params models.AddInventoryParams
+}
+
+func (s *stubInventoryWriter) AddItem(_ context.Context, userID string, params models.AddInventoryParams) (*models.InventoryItem, error) {
+	s.calls++
+	s.params = params
+	return &models.InventoryItem{ID: "item-1", UserID: userID, Name: params.Name, Quantity: params.Quantity}, nil
+}
+
+type stubFCConfigSaver struct {
+	params models.SaveFCConfigParams
+}
+
+func (s *stubFCConfigSaver) SaveConfig(_ context.Context, userID string, params models.SaveFCConfigParams) (*models.FlightControllerConfig, error) {
+	s.params = params
+	return &models.FlightControllerConfig{ID: "cfg-1", UserID: userID, Name: params.Name, RawCLIDump: params.RawCLIDump}, nil
+}
+
+func newWriteTestHandler() *Handler {
+	return NewHandler(nil, nil, nil, nil, nil, []string{"flyingforge.read"}, testutil.NullLogger())
+}
+
+func TestWriteToolsRequireTheirScope(t *testing.T) {
+	handler := newWriteTestHandler()
+	inventoryWriter := &stubInventoryWriter{}
+	handler.SetInventoryWriter(inventoryWriter)
+
+	arguments := json.RawMessage(`{"name": " 2207 Motor ", "category": "motors"}`)
+
+	_, err := handler.HandleToolCall(scopedContext("batteries:write"), "add_inventory_item", arguments)
+	if err == nil || !strings.Contains(err.Error(), "inventory:write") {
+		t.Fatalf("expected missing inventory:write scope error, got %v", err)
+	}
+	if inventoryWriter.calls != 0 {
+		t.Fatal("expected inventory not to be written without the scope")
+	}
+
+	result, err := handler.HandleToolCall(scopedContext("inventory:write"), "add_inventory_item", arguments)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if inventoryWriter.params.Name != "2207 Motor" || inventoryWriter.params.Quantity != 1 {
+		t.Fatalf("unexpected inventory params: %+v", inventoryWriter.params)
+	}
+	if item := result.(ToolResultData).StructuredContent.(*models.InventoryItem); item.UserID != "" {
+		t.Fatalf("expected user ID to be omitted, got %q", item.UserID)
+	}
+}
+
+func TestReadToolsDoNotNeedWriteScopes(t *testing.T) {
+	handler := newWriteTestHandler()
+	for _, name := range []string{"list_my_aircraft", "get_aircraft_tuning", "list_radio_backups"} {
+		if scope := handler.ToolScope(name); scope != "" {
+			t.Fatalf("expected %s to need only read scopes, got %q", name, scope)
+		}
+	}
+}
+
+func TestWriteToolDefinitionsAdvertiseScopes(t *testing.T) {
+	handler := newWriteTestHandler()
+	handler.SetBatteryCycleLogger(&stubBatteryCycleLogger{})
+	handler.SetFCConfigSaver(&stubFCConfigSaver{})
+
+	found := map[string][]string{}
+	for _, tool := range handler.GetTools() {
+		if handler.ToolScope(tool.Name) == "" {
+			continue
+		}
+		if tool.Annotations == nil || tool.Annotations.ReadOnlyHint {
+			t.Fatalf("expected %s not to be marked read-only", tool.Name)
+		}
+		if !handler.IsPrivateTool(tool.Name) {
+			t.Fatalf("expected %s to require a linked account", tool.Name)
+		}
+		found[tool.Name] = tool.SecuritySchemes[0].Scopes
+	}
+
+	if len(found) != 2 {
+		t.Fatalf("expected only configured write tools to be listed, got %v", found)
+	}
+	if scopes := found["save_fc_config"]; len(scopes) != 2 || scopes[0] != "flyingforge.read" || scopes[1] != "aircraft:write" {
+		t.Fatalf("unexpected save_fc_config scopes: %v", scopes)
+	}
+}
+
+func TestLogBatteryCycleConvertsArguments(t *testing.T) {
+	handler := newWriteTestHandler()
+	batteryLogger := &stubBatteryCycleLogger{}
+	handler.SetBatteryCycleLogger(batteryLogger)
+
+	_, err := handler.HandleToolCall(scopedContext("batteries:write"), "log_battery_cycle", json.RawMessage(`{
+		"batteryId": " bat-1 ",
+		"irMilliohmsPerCell": [3.1, 3.4],
+		"storageVoltageOk": true,
+		"loggedAt": "2024-05-01T10:00:00Z"
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	params := batteryLogger.params
+	if params.BatteryID != "bat-1" || params.CycleDelta != 1 {
+		t.Fatalf("unexpected battery log params: %+v", params)
+	}
+	if string(params.IRMohmPerCell) != "[3.1,3.4]" {
+		t.Fatalf("expected IR readings as JSON, got %s", params.IRMohmPerCell)
+	}
+	if params.StorageOk == nil || !*params.StorageOk || params.LoggedAt == nil {
+		t.Fatalf("expected storage flag and log date to be set, got %+v", params)
+	}
+
+	if _, err := handler.HandleToolCall(scopedContext("batteries:write"), "log_battery_cycle", json.RawMessage(`{"batteryId":"bat-1","cycles":-2}`)); err == nil {
+		t.Fatal("expected negative cycles to be rejected")
+	}
+}
+
+func TestSaveFCConfigOmitsRawDump(t *testing.T) {
+	handler := newWriteTestHandler()
+	saver := &stubFCConfigSaver{}
+	handler.SetFCConfigSaver(saver)
+	changedFor := ""
+	handler.SetResourceChangeNotifier(func(_ context.Context, userID string) {
+		changedFor = userID
+	})
+
+	result, err := handler.HandleToolCall(scopedContext("aircraft:write"), "save_fc_config", json.RawMessage(`{
+		"inventoryItemId": " fc-1 ",
+		"rawCliDump": "# diff all\nset gyro_lpf1_static_hz = 250"
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if saver.params.InventoryItemID != "fc-1" {
+		t.Fatalf("unexpected FC config params: %+v", saver.params)
+	}
+
+	config := result.(ToolResultData).StructuredContent.(*models.FlightControllerConfig)
+	if config.RawCLIDump != "" || config.UserID != "" {
+		t.Fatalf("expected raw dump and user ID to be omitted, got %+v", config)
+	}
+	if changedFor != "user-1" {
+		t.Fatalf("expected the new config to be announced to user-1's sessions, got %q", changedFor)
+	}
+}
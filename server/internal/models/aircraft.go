@@ -67,6 +67,7 @@ type Aircraft struct {
 	// Related data (populated on details fetch)
 	Components       []AircraftComponent       `json:"components,omitempty"`
 	ReceiverSettings *AircraftReceiverSettings `json:"receiverSettings,omitempty"`
+	Gallery          []GalleryImage            `json:"gallery,omitempty"`
 }
 
 // AircraftComponent represents a component installed on an aircraft
@@ -189,6 +190,11 @@ type SetComponentParams struct {
 
 	// If inventory item doesn't exist, create it with these fields
 	NewGear *AddInventoryParams `json:"newGear,omitempty"`
+
+	// Record the swap on this maintenance event; the replaced unit is left
+	// in ReplacedUnitCondition (default damaged) and unassigned
+	MaintenanceEventID    string                 `json:"maintenanceEventId,omitempty"`
+	ReplacedUnitCondition InventoryUnitCondition `json:"replacedUnitCondition,omitempty"`
 }
 
 // SetReceiverSettingsParams defines parameters for setting receiver settings
@@ -215,4 +221,7 @@ type AircraftDetailsResponse struct {
 	Aircraft         Aircraft                  `json:"aircraft"`
 	Components       []AircraftComponent       `json:"components"`
 	ReceiverSettings *AircraftReceiverSettings `json:"receiverSettings,omitempty"`
+	Batteries        []AircraftBattery         `json:"batteries,omitempty"`
+	RadioBindings    []AircraftRadioBinding    `json:"radioBindings,omitempty"` // Models on the user's radios that bind this aircraft
+	RadioWarnings    []RadioLinkWarning        `json:"radioWarnings,omitempty"`
 }
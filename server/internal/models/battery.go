@@ -58,6 +58,8 @@ type BatteryLog struct {
 	ID            string          `json:"id"`
 	BatteryID     string          `json:"battery_id"`
 	UserID        string          `json:"user_id,omitempty"`
+	AircraftID    string          `json:"aircraft_id,omitempty"`   // Aircraft the pack was flown on (optional)
+	AircraftName  string          `json:"aircraft_name,omitempty"` // Populated on list
 	LoggedAt      time.Time       `json:"log_date"`
 	CycleDelta    int             `json:"cycle_count,omitempty"`        // Usually 1, but can be more
 	IRMohmPerCell json.RawMessage `json:"ir_milliohms,omitempty"`       // JSON array of IR values per cell
@@ -127,7 +129,8 @@ type BatteryDetailsResponse struct {
 // CreateBatteryLogParams defines parameters for creating a log entry
 type CreateBatteryLogParams struct {
 	BatteryID     string          `json:"battery_id"`
-	LoggedAt      *time.Time      `json:"log_date,omitempty"` // Defaults to now
+	AircraftID    string          `json:"aircraft_id,omitempty"` // Optional aircraft the pack was flown on
+	LoggedAt      *time.Time      `json:"log_date,omitempty"`    // Defaults to now
 	CycleDelta    int             `json:"cycle_count,omitempty"`
 	IRMohmPerCell json.RawMessage `json:"ir_milliohms,omitempty"`
 	MinCellV      *float64        `json:"min_cell_v,omitempty"`
@@ -149,3 +152,41 @@ const (
 	LabelSizeSmall    LabelSize = "small"
 	LabelSizeStandard LabelSize = "standard"
 )
+
+// BatteryExportFormat is the file format for battery export/import
+type BatteryExportFormat string
+
+const (
+	BatteryExportFormatCSV  BatteryExportFormat = "csv"  // One row per log, battery columns repeated
+	BatteryExportFormatJSON BatteryExportFormat = "json" // Batteries with nested logs
+)
+
+// BatteryExport is the nested export of a user's battery fleet
+type BatteryExport struct {
+	ExportedAt time.Time           `json:"exported_at"`
+	Batteries  []BatteryExportItem `json:"batteries"`
+}
+
+// BatteryExportItem is a battery with its full log history
+type BatteryExportItem struct {
+	Battery
+	Logs []BatteryLog `json:"logs"`
+}
+
+// BatteryImportResult summarizes a battery import
+type BatteryImportResult struct {
+	Created     int                     `json:"created"`
+	Updated     int                     `json:"updated"`
+	LogsCreated int                     `json:"logs_created"`
+	LogsSkipped int                     `json:"logs_skipped"` // Logs already present (same battery + log date)
+	Failed      int                     `json:"failed"`
+	Errors      []BatteryImportRowError `json:"errors,omitempty"`
+	Warnings    []BatteryImportRowError `json:"warnings,omitempty"` // Rows imported with a value dropped, such as an unknown aircraft
+}
+
+// BatteryImportRowError describes a row that could not be imported
+type BatteryImportRowError struct {
+	Row         int    `json:"row"` // CSV line number or 1-based JSON array index
+	BatteryCode string `json:"battery_code,omitempty"`
+	Error       string `json:"error"`
+}
@@ -0,0 +1,341 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameClass groups aircraft by prop/frame size for battery sizing
+type FrameClass string
+
+const (
+	FrameClassMicro     FrameClass = "micro"      // Whoops and toothpicks up to 2.5"
+	FrameClassThreeInch FrameClass = "three_inch" // 3" to 4" (cinewhoops, small freestyle)
+	FrameClassFiveInch  FrameClass = "five_inch"  // 5" to 6" (freestyle, racing)
+	FrameClassSevenInch FrameClass = "seven_inch" // 7" to 8" (long range)
+	FrameClassLarge     FrameClass = "large"      // 9" and up (X-class, cine lifters)
+)
+
+// RecommendedCells returns the typical cell range flown on a frame class.
+// Used for warnings only - ESC/FC specs are the hard limits.
+func (fc FrameClass) RecommendedCells() (int, int) {
+	switch fc {
+	case FrameClassMicro:
+		return 1, 4
+	case FrameClassThreeInch:
+		return 2, 6
+	case FrameClassFiveInch:
+		return 4, 6
+	case FrameClassSevenInch:
+		return 4, 8
+	case FrameClassLarge:
+		return 6, 8
+	default:
+		return 0, 0
+	}
+}
+
+// AircraftBattery represents a battery pack assigned to an aircraft
+type AircraftBattery struct {
+	ID         string    `json:"id"`
+	AircraftID string    `json:"aircraftId"`
+	BatteryID  string    `json:"batteryId"`
+	Notes      string    `json:"notes,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	// Populated on fetch
+	Battery       *Battery              `json:"battery,omitempty"`
+	Compatibility *BatteryCompatibility `json:"compatibility,omitempty"`
+}
+
+// AssignAircraftBatteryParams defines parameters for assigning a pack to an aircraft
+type AssignAircraftBatteryParams struct {
+	AircraftID string `json:"aircraftId"`
+	BatteryID  string `json:"batteryId"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+// AircraftPowerProfile describes the battery requirements of an aircraft,
+// derived from its ESC/FC/AIO specs and frame size.
+type AircraftPowerProfile struct {
+	MinCells   int        `json:"minCells,omitempty"`
+	MaxCells   int        `json:"maxCells,omitempty"`
+	Connector  string     `json:"connector,omitempty"`
+	FrameClass FrameClass `json:"frameClass,omitempty"`
+	Sources    []string   `json:"sources,omitempty"` // Component names the limits were read from
+}
+
+// BatteryCompatibility is the result of checking a battery against an aircraft power profile
+type BatteryCompatibility struct {
+	Compatible bool     `json:"compatible"`
+	Issues     []string `json:"issues,omitempty"`   // Blocking problems (cell count, connector)
+	Warnings   []string `json:"warnings,omitempty"` // Non-blocking concerns (frame class, unknown specs)
+}
+
+// CompatibleBattery pairs a user's battery with its compatibility for an aircraft
+type CompatibleBattery struct {
+	Battery       Battery              `json:"battery"`
+	Compatibility BatteryCompatibility `json:"compatibility"`
+	Assigned      bool                 `json:"assigned"`
+}
+
+// CompatibleBatteriesResponse answers "which of my packs can fly this aircraft"
+type CompatibleBatteriesResponse struct {
+	AircraftID string               `json:"aircraftId"`
+	Profile    AircraftPowerProfile `json:"profile"`
+	Batteries  []CompatibleBattery  `json:"batteries"`
+}
+
+// PowerComponentSpec is the spec data for one aircraft component used to derive a power profile
+type PowerComponentSpec struct {
+	Category ComponentCategory
+	Name     string
+	Specs    json.RawMessage
+}
+
+var (
+	cellRangePattern    = regexp.MustCompile(`(?i)(\d)\s*s?\s*(?:-|~|–|to)\s*(\d)\s*s\b`)
+	singleCellPattern   = regexp.MustCompile(`(?i)\b(\d)\s*s\b`)
+	voltageRangePattern = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*v?\s*(?:-|~|–|to)\s*(\d+(?:\.\d+)?)\s*v\b`)
+	connectorPattern    = regexp.MustCompile(`(?i)\b(xt\s*-?\s*(?:30|60|90)|xt\s*-?\s*30\s*u|ph\s*-?\s*2\.0|bt\s*-?\s*2\.0|a\s*-?\s*30|ec3|ec5|jst)\b`)
+	frameInchPattern    = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(?:"|”|''|-?\s*inch(?:es)?\b|in\b)`)
+	wheelbasePattern    = regexp.MustCompile(`(\d{2,4})`)
+)
+
+// DerivePowerProfile builds an aircraft power profile from its component specs.
+// Cell limits from ESC, AIO, stack and FC specs are intersected; the connector is
+// taken from the first power-handling component that declares one.
+func DerivePowerProfile(aircraftType AircraftType, components []PowerComponentSpec) AircraftPowerProfile {
+	profile := AircraftPowerProfile{}
+
+	for _, component := range components {
+		category := NormalizeComponentCategory(component.Category)
+		specs := flattenSpecs(component.Specs)
+
+		switch category {
+		case ComponentCategoryESC, ComponentCategoryAIO, ComponentCategoryStack, ComponentCategoryFC:
+			contributed := false
+			if minCells, maxCells, ok := parseSpecCellRange(specs); ok {
+				if profile.MinCells == 0 || minCells > profile.MinCells {
+					profile.MinCells = minCells
+				}
+				if profile.MaxCells == 0 || maxCells < profile.MaxCells {
+					profile.MaxCells = maxCells
+				}
+				contributed = true
+			}
+			if profile.Connector == "" {
+				if connector := parseSpecConnector(specs); connector != "" {
+					profile.Connector = connector
+					contributed = true
+				}
+			}
+			if contributed && component.Name != "" {
+				profile.Sources = append(profile.Sources, component.Name)
+			}
+		case ComponentCategoryFrame:
+			if profile.FrameClass == "" {
+				profile.FrameClass = parseFrameClass(component.Name, specs)
+			}
+		}
+	}
+
+	if profile.FrameClass == "" && aircraftType == AircraftTypeWhoop {
+		profile.FrameClass = FrameClassMicro
+	}
+
+	return profile
+}
+
+// CheckBatteryCompatibility validates a battery against an aircraft power profile
+func CheckBatteryCompatibility(profile AircraftPowerProfile, battery Battery) BatteryCompatibility {
+	result := BatteryCompatibility{}
+
+	if profile.MinCells > 0 && profile.MaxCells > 0 {
+		if battery.Cells < profile.MinCells || battery.Cells > profile.MaxCells {
+			result.Issues = append(result.Issues, fmt.Sprintf("%dS pack is outside the %s supported range", battery.Cells, formatCellRange(profile.MinCells, profile.MaxCells)))
+		}
+	} else {
+		result.Warnings = append(result.Warnings, "aircraft cell limits unknown (no ESC/FC voltage specs)")
+	}
+
+	if profile.Connector != "" {
+		if battery.Connector == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("battery connector unknown, aircraft uses %s", profile.Connector))
+		} else if NormalizeConnector(battery.Connector) != NormalizeConnector(profile.Connector) {
+			result.Issues = append(result.Issues, fmt.Sprintf("%s connector does not match aircraft %s", battery.Connector, profile.Connector))
+		}
+	}
+
+	if minCells, maxCells := profile.FrameClass.RecommendedCells(); minCells > 0 {
+		if battery.Cells < minCells || battery.Cells > maxCells {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%dS is unusual for a %s frame (typically %s)", battery.Cells, strings.ReplaceAll(string(profile.FrameClass), "_", " "), formatCellRange(minCells, maxCells)))
+		}
+	}
+
+	result.Compatible = len(result.Issues) == 0
+	return result
+}
+
+// NormalizeConnector canonicalizes connector names so "XT-60", "xt60" and "XT 60" compare equal
+func NormalizeConnector(connector string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(connector) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func formatCellRange(minCells, maxCells int) string {
+	if minCells == maxCells {
+		return fmt.Sprintf("%dS", minCells)
+	}
+	return fmt.Sprintf("%d-%dS", minCells, maxCells)
+}
+
+// flattenSpecs decodes a specs JSON object into lowercased key -> string value pairs
+func flattenSpecs(raw json.RawMessage) map[string]string {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil
+	}
+
+	flattened := make(map[string]string, len(decoded))
+	for key, value := range decoded {
+		if value == nil {
+			continue
+		}
+		normalizedKey := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(key)))
+		flattened[normalizedKey] = strings.TrimSpace(fmt.Sprintf("%v", value))
+	}
+	return flattened
+}
+
+func firstSpec(specs map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := specs[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func parseSpecCellRange(specs map[string]string) (int, int, bool) {
+	for _, key := range []string{"cells", "cellcount", "lipo", "inputvoltage", "voltage", "battery", "input"} {
+		value := specs[key]
+		if value == "" {
+			continue
+		}
+		if minCells, maxCells, ok := ParseCellRange(value); ok {
+			return minCells, maxCells, true
+		}
+		if key == "cells" || key == "cellcount" {
+			if cells, err := strconv.Atoi(value); err == nil && cells >= 1 && cells <= 8 {
+				return cells, cells, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// ParseCellRange extracts a cell range from spec text such as "3-6S", "2S~4S", "6S"
+// or a pack voltage range such as "7.4-25.2V".
+func ParseCellRange(text string) (int, int, bool) {
+	if match := cellRangePattern.FindStringSubmatch(text); len(match) == 3 {
+		minCells, _ := strconv.Atoi(match[1])
+		maxCells, _ := strconv.Atoi(match[2])
+		if minCells >= 1 && maxCells >= minCells && maxCells <= 8 {
+			return minCells, maxCells, true
+		}
+	}
+	if match := voltageRangePattern.FindStringSubmatch(text); len(match) == 3 {
+		minV, errMin := strconv.ParseFloat(match[1], 64)
+		maxV, errMax := strconv.ParseFloat(match[2], 64)
+		if errMin == nil && errMax == nil && minV > 0 && maxV >= minV {
+			minCells := int(math.Round(minV / 3.7))
+			maxCells := int(math.Floor(maxV/4.2 + 0.05))
+			if minCells >= 1 && maxCells >= minCells && maxCells <= 8 {
+				return minCells, maxCells, true
+			}
+		}
+	}
+	if match := singleCellPattern.FindStringSubmatch(text); len(match) == 2 {
+		cells, _ := strconv.Atoi(match[1])
+		if cells >= 1 && cells <= 8 {
+			return cells, cells, true
+		}
+	}
+	return 0, 0, false
+}
+
+func parseSpecConnector(specs map[string]string) string {
+	value := firstSpec(specs, "connector", "batteryconnector", "powerconnector", "batterylead", "plug")
+	if value == "" {
+		return ""
+	}
+	if match := connectorPattern.FindStringSubmatch(value); len(match) == 2 {
+		return NormalizeConnector(match[1])
+	}
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+func parseFrameClass(name string, specs map[string]string) FrameClass {
+	for _, text := range []string{firstSpec(specs, "size", "framesize", "propsize", "class"), name} {
+		if match := frameInchPattern.FindStringSubmatch(text); len(match) == 2 {
+			if inches, err := strconv.ParseFloat(match[1], 64); err == nil && inches > 0 {
+				return frameClassFromInches(inches)
+			}
+		}
+	}
+
+	if wheelbase := firstSpec(specs, "wheelbase", "wheelbasemm"); wheelbase != "" {
+		if match := wheelbasePattern.FindStringSubmatch(wheelbase); len(match) == 2 {
+			if mm, err := strconv.Atoi(match[1]); err == nil && mm > 0 {
+				return frameClassFromWheelbase(mm)
+			}
+		}
+	}
+	return ""
+}
+
+func frameClassFromInches(inches float64) FrameClass {
+	switch {
+	case inches <= 2.5:
+		return FrameClassMicro
+	case inches <= 4:
+		return FrameClassThreeInch
+	case inches <= 6:
+		return FrameClassFiveInch
+	case inches <= 8:
+		return FrameClassSevenInch
+	default:
+		return FrameClassLarge
+	}
+}
+
+func frameClassFromWheelbase(mm int) FrameClass {
+	switch {
+	case mm <= 120:
+		return FrameClassMicro
+	case mm <= 180:
+		return FrameClassThreeInch
+	case mm <= 260:
+		return FrameClassFiveInch
+	case mm <= 350:
+		return FrameClassSevenInch
+	default:
+		return FrameClassLarge
+	}
+}
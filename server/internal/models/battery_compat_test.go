@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseCellRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantMin int
+		wantMax int
+		wantOK  bool
+	}{
+		{input: "3-6S", wantMin: 3, wantMax: 6, wantOK: true},
+		{input: "2S~4S", wantMin: 2, wantMax: 4, wantOK: true},
+		{input: "3S to 6S LiPo", wantMin: 3, wantMax: 6, wantOK: true},
+		{input: "6S", wantMin: 6, wantMax: 6, wantOK: true},
+		{input: "7.4-25.2V", wantMin: 2, wantMax: 6, wantOK: true},
+		{input: "11.1V-25.2V", wantMin: 3, wantMax: 6, wantOK: true},
+		{input: "45A", wantOK: false},
+		{input: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotMin, gotMax, ok := ParseCellRange(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseCellRange(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if gotMin != tt.wantMin || gotMax != tt.wantMax {
+				t.Errorf("ParseCellRange(%q) = %d-%d, want %d-%d", tt.input, gotMin, gotMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestDerivePowerProfile(t *testing.T) {
+	t.Run("intersects ESC and FC cell ranges and reads connector", func(t *testing.T) {
+		profile := DerivePowerProfile(AircraftTypeQuad, []PowerComponentSpec{
+			{Category: ComponentCategoryESC, Name: "Tekko32 F4 45A", Specs: json.RawMessage(`{"input_voltage":"3-6S","connector":"XT-60"}`)},
+			{Category: ComponentCategoryFC, Name: "F722", Specs: json.RawMessage(`{"voltage":"2-4S"}`)},
+			{Category: ComponentCategoryFrame, Name: "Source One", Specs: json.RawMessage(`{"size":"5\""}`)},
+		})
+
+		if profile.MinCells != 3 || profile.MaxCells != 4 {
+			t.Errorf("cell range = %d-%d, want 3-4", profile.MinCells, profile.MaxCells)
+		}
+		if profile.Connector != "XT60" {
+			t.Errorf("connector = %q, want XT60", profile.Connector)
+		}
+		if profile.FrameClass != FrameClassFiveInch {
+			t.Errorf("frame class = %q, want %q", profile.FrameClass, FrameClassFiveInch)
+		}
+		if len(profile.Sources) != 2 {
+			t.Errorf("sources = %v, want 2 entries", profile.Sources)
+		}
+	})
+
+	t.Run("falls back to frame name and wheelbase", func(t *testing.T) {
+		profile := DerivePowerProfile(AircraftTypeQuad, []PowerComponentSpec{
+			{Category: ComponentCategoryFrame, Name: "Chimera 7 inch LR"},
+		})
+		if profile.FrameClass != FrameClassSevenInch {
+			t.Errorf("frame class = %q, want %q", profile.FrameClass, FrameClassSevenInch)
+		}
+
+		profile = DerivePowerProfile(AircraftTypeQuad, []PowerComponentSpec{
+			{Category: ComponentCategoryFrame, Name: "Generic", Specs: json.RawMessage(`{"wheelbase":"142mm"}`)},
+		})
+		if profile.FrameClass != FrameClassThreeInch {
+			t.Errorf("frame class = %q, want %q", profile.FrameClass, FrameClassThreeInch)
+		}
+	})
+
+	t.Run("whoop type implies micro frame", func(t *testing.T) {
+		profile := DerivePowerProfile(AircraftTypeWhoop, nil)
+		if profile.FrameClass != FrameClassMicro {
+			t.Errorf("frame class = %q, want %q", profile.FrameClass, FrameClassMicro)
+		}
+	})
+}
+
+func TestCheckBatteryCompatibility(t *testing.T) {
+	profile := AircraftPowerProfile{MinCells: 4, MaxCells: 6, Connector: "XT60", FrameClass: FrameClassFiveInch}
+
+	tests := []struct {
+		name           string
+		battery        Battery
+		wantCompatible bool
+		wantIssue      string
+		wantWarning    string
+	}{
+		{
+			name:           "matching 6S XT60",
+			battery:        Battery{Cells: 6, Connector: "xt-60"},
+			wantCompatible: true,
+		},
+		{
+			name:           "cell count out of range",
+			battery:        Battery{Cells: 3, Connector: "XT60"},
+			wantCompatible: false,
+			wantIssue:      "outside the 4-6S supported range",
+		},
+		{
+			name:           "connector mismatch",
+			battery:        Battery{Cells: 4, Connector: "XT30"},
+			wantCompatible: false,
+			wantIssue:      "does not match aircraft XT60",
+		},
+		{
+			name:           "unknown connector only warns",
+			battery:        Battery{Cells: 4},
+			wantCompatible: true,
+			wantWarning:    "battery connector unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckBatteryCompatibility(profile, tt.battery)
+			if got.Compatible != tt.wantCompatible {
+				t.Fatalf("Compatible = %v, want %v (issues: %v)", got.Compatible, tt.wantCompatible, got.Issues)
+			}
+			if tt.wantIssue != "" && !containsSubstring(got.Issues, tt.wantIssue) {
+				t.Errorf("Issues = %v, want one containing %q", got.Issues, tt.wantIssue)
+			}
+			if tt.wantWarning != "" && !containsSubstring(got.Warnings, tt.wantWarning) {
+				t.Errorf("Warnings = %v, want one containing %q", got.Warnings, tt.wantWarning)
+			}
+		})
+	}
+
+	t.Run("frame class mismatch is a warning", func(t *testing.T) {
+		got := CheckBatteryCompatibility(AircraftPowerProfile{FrameClass: FrameClassMicro}, Battery{Cells: 6})
+		if !got.Compatible {
+			t.Fatalf("Compatible = false, want true (issues: %v)", got.Issues)
+		}
+		if !containsSubstring(got.Warnings, "unusual for a micro frame") {
+			t.Errorf("Warnings = %v, want frame class warning", got.Warnings)
+		}
+	})
+}
+
+func containsSubstring(values []string, substr string) bool {
+	for _, v := range values {
+		if strings.Contains(v, substr) {
+			return true
+		}
+	}
+	return false
+}
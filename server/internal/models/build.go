@@ -167,30 +167,31 @@ func (p *BuildPilot) DisplayNameOrDefault() string {
 
 // Build is a curated or temporary parts list.
 type Build struct {
-	ID                   string        `json:"id"`
-	OwnerUserID          string        `json:"ownerUserId,omitempty"`
-	ImageAssetID         string        `json:"-"`
-	ModerationReason     string        `json:"moderationReason,omitempty"`
-	Status               BuildStatus   `json:"status"`
-	StagedRevisionID     string        `json:"stagedRevisionId,omitempty"`
-	StagedRevisionStatus BuildStatus   `json:"stagedRevisionStatus,omitempty"`
-	Token                string        `json:"-"`
-	ExpiresAt            *time.Time    `json:"expiresAt,omitempty"`
-	Title                string        `json:"title"`
-	Description          string        `json:"description,omitempty"`
-	YouTubeURL           string        `json:"youtubeUrl,omitempty"`
-	FlightYouTubeURL     string        `json:"flightYoutubeUrl,omitempty"`
-	SourceAircraftID     string        `json:"sourceAircraftId,omitempty"`
-	CreatedAt            time.Time     `json:"createdAt"`
-	UpdatedAt            time.Time     `json:"updatedAt"`
-	PublishedAt          *time.Time    `json:"publishedAt,omitempty"`
-	Parts                []BuildPart   `json:"parts,omitempty"`
-	Verified             bool          `json:"verified"`
-	MainImageURL         string        `json:"mainImageUrl,omitempty"`
-	Pilot                *BuildPilot   `json:"pilot,omitempty"`
-	LikeCount            int           `json:"likeCount"`
-	DislikeCount         int           `json:"dislikeCount"`
-	ViewerReaction       BuildReaction `json:"viewerReaction,omitempty"`
+	ID                   string         `json:"id"`
+	OwnerUserID          string         `json:"ownerUserId,omitempty"`
+	ImageAssetID         string         `json:"-"`
+	ModerationReason     string         `json:"moderationReason,omitempty"`
+	Status               BuildStatus    `json:"status"`
+	StagedRevisionID     string         `json:"stagedRevisionId,omitempty"`
+	StagedRevisionStatus BuildStatus    `json:"stagedRevisionStatus,omitempty"`
+	Token                string         `json:"-"`
+	ExpiresAt            *time.Time     `json:"expiresAt,omitempty"`
+	Title                string         `json:"title"`
+	Description          string         `json:"description,omitempty"`
+	YouTubeURL           string         `json:"youtubeUrl,omitempty"`
+	FlightYouTubeURL     string         `json:"flightYoutubeUrl,omitempty"`
+	SourceAircraftID     string         `json:"sourceAircraftId,omitempty"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	UpdatedAt            time.Time      `json:"updatedAt"`
+	PublishedAt          *time.Time     `json:"publishedAt,omitempty"`
+	Parts                []BuildPart    `json:"parts,omitempty"`
+	Verified             bool           `json:"verified"`
+	MainImageURL         string         `json:"mainImageUrl,omitempty"`
+	Gallery              []GalleryImage `json:"gallery,omitempty"`
+	Pilot                *BuildPilot    `json:"pilot,omitempty"`
+	LikeCount            int            `json:"likeCount"`
+	DislikeCount         int            `json:"dislikeCount"`
+	ViewerReaction       BuildReaction  `json:"viewerReaction,omitempty"`
 }
 
 // CreateBuildParams defines payload for new authenticated builds.
@@ -232,10 +233,11 @@ type BuildDeclineParams struct {
 
 // BuildListParams describes list query options.
 type BuildListParams struct {
-	Sort        BuildSort `json:"sort,omitempty"`
-	FrameFilter string    `json:"frameFilter,omitempty"`
-	Limit       int       `json:"limit,omitempty"`
-	Offset      int       `json:"offset,omitempty"`
+	Sort          BuildSort `json:"sort,omitempty"`
+	FrameFilter   string    `json:"frameFilter,omitempty"`
+	CatalogItemID string    `json:"catalogItemId,omitempty"` // Only builds with this catalog part
+	Limit         int       `json:"limit,omitempty"`
+	Offset        int       `json:"offset,omitempty"`
 }
 
 // BuildModerationListParams describes admin moderation list query options.
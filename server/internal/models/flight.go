@@ -0,0 +1,126 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// FlightSession is one outing with an aircraft: where and when it flew, how
+// many flights, for how long and which packs were used
+type FlightSession struct {
+	ID              string                 `json:"id"`
+	UserID          string                 `json:"userId,omitempty"`
+	AircraftID      string                 `json:"aircraftId"`
+	AircraftName    string                 `json:"aircraftName,omitempty"` // Populated on fetch
+	FlownAt         time.Time              `json:"flownAt"`
+	Location        string                 `json:"location,omitempty"` // Free-form label, e.g. "Local field"
+	FlightCount     int                    `json:"flightCount"`
+	DurationSeconds int                    `json:"durationSeconds"` // Total air time across all flights
+	Notes           string                 `json:"notes,omitempty"`
+	Batteries       []FlightSessionBattery `json:"batteries"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	UpdatedAt       time.Time              `json:"updatedAt"`
+}
+
+// FlightSessionBattery is a pack used during a session and the battery log created for it
+type FlightSessionBattery struct {
+	BatteryID    string `json:"batteryId"`
+	BatteryName  string `json:"batteryName,omitempty"`
+	BatteryCode  string `json:"batteryCode,omitempty"`
+	Cycles       int    `json:"cycles"`
+	BatteryLogID string `json:"batteryLogId,omitempty"`
+}
+
+// FlightBatteryParams names a pack used during a session. Cycles defaults to 1.
+type FlightBatteryParams struct {
+	BatteryID string `json:"batteryId"`
+	Cycles    int    `json:"cycles,omitempty"`
+}
+
+// CreateFlightSessionParams defines parameters for logging a flight session
+type CreateFlightSessionParams struct {
+	AircraftID      string                `json:"aircraftId"`
+	FlownAt         *time.Time            `json:"flownAt,omitempty"` // Defaults to now
+	Location        string                `json:"location,omitempty"`
+	FlightCount     int                   `json:"flightCount,omitempty"` // Defaults to one flight per pack cycle
+	DurationSeconds int                   `json:"durationSeconds,omitempty"`
+	Notes           string                `json:"notes,omitempty"`
+	Batteries       []FlightBatteryParams `json:"batteries,omitempty"`
+}
+
+// FlightSessionListParams defines filters for listing flight sessions
+type FlightSessionListParams struct {
+	AircraftID string `json:"aircraftId,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+}
+
+// FlightSessionListResponse is the response for listing flight sessions
+type FlightSessionListResponse struct {
+	Sessions   []FlightSession `json:"sessions"`
+	TotalCount int             `json:"totalCount"`
+}
+
+// FlightTotals are cumulative counts over a set of flight sessions
+type FlightTotals struct {
+	SessionCount    int        `json:"sessionCount"`
+	FlightCount     int        `json:"flightCount"`
+	DurationSeconds int        `json:"durationSeconds"`
+	LastFlownAt     *time.Time `json:"lastFlownAt,omitempty"`
+}
+
+// ComponentFlightTime is the cumulative flight time of an inventory item
+// across every session it was installed for, e.g. motor hours
+type ComponentFlightTime struct {
+	Category        ComponentCategory `json:"category"`
+	InventoryItemID string            `json:"inventoryItemId"`
+	ItemName        string            `json:"itemName,omitempty"`
+	FlightCount     int               `json:"flightCount"`
+	DurationSeconds int               `json:"durationSeconds"`
+}
+
+// AircraftFlightStats is an aircraft's flight time plus that of its installed components
+type AircraftFlightStats struct {
+	AircraftID string `json:"aircraftId"`
+	FlightTotals
+	Components []ComponentFlightTime `json:"components"`
+}
+
+// AircraftFlightTotals are a pilot's totals on one aircraft
+type AircraftFlightTotals struct {
+	AircraftID   string `json:"aircraftId"`
+	AircraftName string `json:"aircraftName,omitempty"`
+	FlightTotals
+}
+
+// PilotFlightStats summarizes a pilot's flying across all aircraft
+type PilotFlightStats struct {
+	FlightTotals
+	AircraftFlown int                    `json:"aircraftFlown"`
+	PacksUsed     int                    `json:"packsUsed"`
+	ByAircraft    []AircraftFlightTotals `json:"byAircraft"`
+}
+
+// NormalizeFlightBatteries trims battery IDs, drops blanks, defaults cycles
+// to 1 and merges repeated packs, keeping first-seen order.
+func NormalizeFlightBatteries(batteries []FlightBatteryParams) []FlightBatteryParams {
+	normalized := make([]FlightBatteryParams, 0, len(batteries))
+	index := make(map[string]int, len(batteries))
+	for _, battery := range batteries {
+		id := strings.TrimSpace(battery.BatteryID)
+		if id == "" {
+			continue
+		}
+		cycles := battery.Cycles
+		if cycles <= 0 {
+			cycles = 1
+		}
+		if i, ok := index[id]; ok {
+			normalized[i].Cycles += cycles
+			continue
+		}
+		index[id] = len(normalized)
+		normalized = append(normalized, FlightBatteryParams{BatteryID: id, Cycles: cycles})
+	}
+	return normalized
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeFlightBatteries(t *testing.T) {
+	got := NormalizeFlightBatteries([]FlightBatteryParams{
+		{BatteryID: " bat-1 "},
+		{BatteryID: ""},
+		{BatteryID: "bat-2", Cycles: 2},
+		{BatteryID: "bat-1", Cycles: 1},
+		{BatteryID: "bat-3", Cycles: -4},
+	})
+
+	want := []FlightBatteryParams{
+		{BatteryID: "bat-1", Cycles: 2},
+		{BatteryID: "bat-2", Cycles: 2},
+		{BatteryID: "bat-3", Cycles: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NormalizeFlightBatteries() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNormalizeFlightBatteries_Empty(t *testing.T) {
+	if got := NormalizeFlightBatteries(nil); len(got) != 0 {
+		t.Fatalf("NormalizeFlightBatteries(nil) = %+v, want empty", got)
+	}
+}
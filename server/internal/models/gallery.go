@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxGalleryImages caps how many images a build or aircraft gallery can hold.
+	MaxGalleryImages = 12
+	// MaxGalleryCaptionLength is the longest caption accepted, in characters.
+	MaxGalleryCaptionLength = 280
+)
+
+// GalleryImage is one image in a build or aircraft gallery.
+// The cover is the image whose asset is the parent's ImageAssetID.
+type GalleryImage struct {
+	ID           string    `json:"id"`
+	ImageAssetID string    `json:"-"`
+	Caption      string    `json:"caption,omitempty"`
+	Position     int       `json:"position"`
+	IsCover      bool      `json:"isCover"`
+	Pending      bool      `json:"pending,omitempty"` // Awaiting moderation review; ImageURL serves a placeholder
+	ImageURL     string    `json:"imageUrl,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// AddGalleryImageParams defines parameters for adding an image to a gallery.
+type AddGalleryImageParams struct {
+	UploadID  string `json:"uploadId,omitempty"` // approved token from /api/images/upload
+	Caption   string `json:"caption,omitempty"`
+	ImageType string `json:"-"` // "image/jpeg" or "image/png" for multipart uploads
+	ImageData []byte `json:"-"`
+}
+
+// UpdateGalleryImageParams defines payload for editing a gallery image.
+type UpdateGalleryImageParams struct {
+	Caption *string `json:"caption,omitempty"`
+	IsCover *bool   `json:"isCover,omitempty"`
+}
+
+// ReorderGalleryParams lists every gallery image ID in the desired order.
+type ReorderGalleryParams struct {
+	ImageIDs []string `json:"imageIds"`
+}
+
+// NormalizeGalleryCaption trims a caption and enforces the length limit.
+func NormalizeGalleryCaption(caption string) (string, error) {
+	caption = strings.TrimSpace(caption)
+	if utf8.RuneCountInString(caption) > MaxGalleryCaptionLength {
+		return "", fmt.Errorf("caption must be %d characters or fewer", MaxGalleryCaptionLength)
+	}
+	return caption, nil
+}
+
+// ValidateGalleryOrder checks that imageIDs names every image in current exactly once.
+func ValidateGalleryOrder(current []GalleryImage, imageIDs []string) error {
+	if len(imageIDs) != len(current) {
+		return fmt.Errorf("imageIds must list all %d gallery images", len(current))
+	}
+
+	known := make(map[string]bool, len(current))
+	for _, image := range current {
+		known[image.ID] = false
+	}
+	for _, id := range imageIDs {
+		seen, ok := known[strings.TrimSpace(id)]
+		if !ok {
+			return fmt.Errorf("unknown gallery image %q", id)
+		}
+		if seen {
+			return fmt.Errorf("gallery image %q listed more than once", id)
+		}
+		known[strings.TrimSpace(id)] = true
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeGalleryCaption(t *testing.T) {
+	got, err := NormalizeGalleryCaption("  Maiden flight  ")
+	if err != nil || got != "Maiden flight" {
+		t.Fatalf("NormalizeGalleryCaption() = %q, %v", got, err)
+	}
+
+	// Length is counted in characters, not bytes
+	if _, err := NormalizeGalleryCaption(strings.Repeat("é", MaxGalleryCaptionLength)); err != nil {
+		t.Fatalf("caption at the limit rejected: %v", err)
+	}
+	if _, err := NormalizeGalleryCaption(strings.Repeat("a", MaxGalleryCaptionLength+1)); err == nil {
+		t.Fatal("expected error for caption over the limit")
+	}
+}
+
+func TestValidateGalleryOrder(t *testing.T) {
+	current := []GalleryImage{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	tests := []struct {
+		name    string
+		ids     []string
+		wantErr string
+	}{
+		{name: "full permutation", ids: []string{"c", "a", "b"}},
+		{name: "missing image", ids: []string{"a", "b"}, wantErr: "must list all 3"},
+		{name: "unknown image", ids: []string{"a", "b", "z"}, wantErr: "unknown gallery image"},
+		{name: "duplicate image", ids: []string{"a", "a", "b"}, wantErr: "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGalleryOrder(current, tt.ids)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateGalleryOrder() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateGalleryOrder() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
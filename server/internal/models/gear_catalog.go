@@ -303,6 +303,7 @@ type GearCatalogSearchParams struct {
 	Query    string            `json:"query,omitempty"`
 	GearType GearType          `json:"gearType,omitempty"`
 	Brand    string            `json:"brand,omitempty"`
+	BestFor  string            `json:"bestFor,omitempty"` // Drone type such as freestyle or long-range
 	Status   CatalogItemStatus `json:"status,omitempty"`
 	Limit    int               `json:"limit,omitempty"`
 	Offset   int               `json:"offset,omitempty"`
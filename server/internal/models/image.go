@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -38,6 +39,9 @@ type ModerationDecision struct {
 	Reason        string                `json:"reason,omitempty"`
 	Labels        []ModerationLabel     `json:"labels,omitempty"`
 	MaxConfidence float64               `json:"maxConfidence,omitempty"`
+	// Queued is set when a PENDING_REVIEW image was stored for a content admin
+	// to review. The owning entity shows a placeholder until then.
+	Queued bool `json:"queued,omitempty"`
 }
 
 // ImageAsset stores approved image bytes + moderation metadata.
@@ -47,9 +51,148 @@ type ImageAsset struct {
 	EntityType              ImageEntityType
 	EntityID                string
 	ImageBytes              []byte
+	StorageKey              string // Object storage key; empty when bytes are kept in Postgres
 	Status                  ImageModerationStatus
 	ModerationLabels        json.RawMessage
 	ModerationMaxConfidence float64
+	PerceptualHash          *uint64 // dHash for near-duplicate detection; nil when not hashed
 	CreatedAt               time.Time
 	UpdatedAt               time.Time
 }
+
+// ImageReview is an image asset in the human moderation review queue.
+type ImageReview struct {
+	ImageID          string                `json:"imageId"`
+	OwnerUserID      string                `json:"ownerUserId"`
+	EntityType       ImageEntityType       `json:"entityType"`
+	EntityID         string                `json:"entityId,omitempty"`
+	Status           ImageModerationStatus `json:"status"`
+	QueuedReason     string                `json:"queuedReason,omitempty"` // Why automated moderation held the image back
+	Labels           []ModerationLabel     `json:"labels"`
+	MaxConfidence    float64               `json:"maxConfidence"`
+	ReviewReason     string                `json:"reviewReason,omitempty"`
+	ReviewedByUserID string                `json:"reviewedByUserId,omitempty"`
+	ReviewedAt       *time.Time            `json:"reviewedAt,omitempty"`
+	ImageURL         string                `json:"imageUrl"`
+	CreatedAt        time.Time             `json:"createdAt"`
+}
+
+// ImageReviewListParams describes admin review queue query options.
+type ImageReviewListParams struct {
+	Status ImageModerationStatus `json:"status,omitempty"`
+	Limit  int                   `json:"limit,omitempty"`
+	Offset int                   `json:"offset,omitempty"`
+}
+
+// ImageReviewListResponse is returned by the admin review queue endpoint.
+type ImageReviewListResponse struct {
+	Images     []ImageReview `json:"images"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// ImageReviewDecisionParams is the body of an approve/reject review action.
+type ImageReviewDecisionParams struct {
+	Reason string `json:"reason"`
+}
+
+// ImageUsage is an image asset in the place it is shown: a gear catalog item
+// or a build gallery.
+type ImageUsage struct {
+	ImageID     string          `json:"imageId"`
+	EntityType  ImageEntityType `json:"entityType"`
+	EntityID    string          `json:"entityId"`
+	Name        string          `json:"name"` // Catalog brand/model or build title
+	OwnerUserID string          `json:"ownerUserId,omitempty"`
+	ImageURL    string          `json:"imageUrl"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// ImageDuplicateMatch is an image that nearly matches another one. Distance is
+// the Hamming distance between their perceptual hashes (0 = identical).
+type ImageDuplicateMatch struct {
+	ImageUsage
+	Distance int `json:"distance"`
+}
+
+// ImageDuplicatePair is two near-identical images on different entities.
+// Original is the earlier upload.
+type ImageDuplicatePair struct {
+	Original  ImageUsage `json:"original"`
+	Duplicate ImageUsage `json:"duplicate"`
+	Distance  int        `json:"distance"`
+}
+
+// ImageDuplicateListParams describes admin near-duplicate query options.
+type ImageDuplicateListParams struct {
+	EntityType  ImageEntityType `json:"entityType,omitempty"`  // Only pairs involving this entity type
+	MaxDistance int             `json:"maxDistance,omitempty"` // Largest Hamming distance; 0 = identical only
+	Limit       int             `json:"limit,omitempty"`
+	Offset      int             `json:"offset,omitempty"`
+}
+
+// ImageDuplicateListResponse is returned by the admin near-duplicate endpoint.
+type ImageDuplicateListResponse struct {
+	Pairs       []ImageDuplicatePair `json:"pairs"`
+	MaxDistance int                  `json:"maxDistance"`
+}
+
+// ImageSize names a fixed-size variant generated for every uploaded image.
+type ImageSize string
+
+const (
+	ImageSizeOriginal ImageSize = ""      // The stored upload (metadata stripped)
+	ImageSizeThumb    ImageSize = "thumb" // Lists and avatars
+	ImageSizeCard     ImageSize = "card"  // Cards and previews
+	ImageSizeFull     ImageSize = "full"  // Detail views
+)
+
+// ImageSizes lists the derivative sizes in ascending order.
+var ImageSizes = []ImageSize{ImageSizeThumb, ImageSizeCard, ImageSizeFull}
+
+// MaxEdge returns the longest edge in pixels for a derivative size.
+// Images smaller than this are never upscaled.
+func (s ImageSize) MaxEdge() int {
+	switch s {
+	case ImageSizeThumb:
+		return 320
+	case ImageSizeCard:
+		return 800
+	case ImageSizeFull:
+		return 2048
+	default:
+		return 0
+	}
+}
+
+// ParseImageSize parses a ?size= query value. An empty value selects the original.
+func ParseImageSize(value string) (ImageSize, bool) {
+	size := ImageSize(strings.ToLower(strings.TrimSpace(value)))
+	if size == ImageSizeOriginal || size.MaxEdge() > 0 {
+		return size, true
+	}
+	return "", false
+}
+
+// ImageDerivative is a resized variant of an ImageAsset.
+type ImageDerivative struct {
+	ImageID     string
+	Size        ImageSize
+	ContentType string
+	Width       int
+	Height      int
+	ImageBytes  []byte
+	StorageKey  string // Object storage key; empty when bytes are kept in Postgres
+}
+
+// ImageRef locates an entity's image: a moderated image asset, or bytes stored
+// inline on the entity by uploads that predate image assets.
+type ImageRef struct {
+	AssetID     string
+	Data        []byte
+	ContentType string
+}
+
+// Accepted reports whether the image was kept: approved, or queued for review.
+func (d *ModerationDecision) Accepted() bool {
+	return d != nil && (d.Status == ImageModerationApproved || d.Queued)
+}
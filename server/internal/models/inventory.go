@@ -24,6 +24,12 @@ type InventoryItem struct {
 	PurchasePrice  *float64 `json:"purchasePrice,omitempty"`
 	PurchaseSeller string   `json:"purchaseSeller,omitempty"`
 
+	// Individually tracked pieces (serial number, condition, assigned aircraft)
+	Units []InventoryUnit `json:"units,omitempty"`
+
+	// Order the item is arriving in; pending until the order is delivered
+	Order *InventoryItemOrder `json:"order,omitempty"`
+
 	// Links and images
 	ProductURL string `json:"productUrl,omitempty"`
 	ImageURL   string `json:"imageUrl,omitempty"`
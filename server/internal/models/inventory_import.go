@@ -0,0 +1,154 @@
+package models
+
+import "strings"
+
+// InventoryImportFormat is the file format for an inventory import
+type InventoryImportFormat string
+
+const (
+	InventoryImportFormatCSV  InventoryImportFormat = "csv"  // One row per item, columns matched by header name
+	InventoryImportFormatJSON InventoryImportFormat = "json" // Array of items, or {"items": [...]}
+)
+
+// InventoryImportAction is what to do with an imported row's catalog link
+type InventoryImportAction string
+
+const (
+	InventoryImportLink     InventoryImportAction = "link"     // Link to an existing catalog item
+	InventoryImportCreate   InventoryImportAction = "create"   // Submit a new catalog entry and link to it
+	InventoryImportUnlinked InventoryImportAction = "unlinked" // Add the item without a catalog link
+)
+
+// NormalizeInventoryImportAction validates an import action, defaulting empty to unlinked
+func NormalizeInventoryImportAction(action InventoryImportAction) (InventoryImportAction, bool) {
+	switch InventoryImportAction(strings.ToLower(strings.TrimSpace(string(action)))) {
+	case InventoryImportLink:
+		return InventoryImportLink, true
+	case InventoryImportCreate:
+		return InventoryImportCreate, true
+	case InventoryImportUnlinked, "":
+		return InventoryImportUnlinked, true
+	default:
+		return "", false
+	}
+}
+
+// InventoryImportRow is one parsed row of an import file with its catalog matches,
+// returned for review before anything is written
+type InventoryImportRow struct {
+	Row                int                     `json:"row"` // CSV line number or 1-based JSON array index
+	Item               AddInventoryParams      `json:"item"`
+	Catalog            CreateGearCatalogParams `json:"catalog"`              // Catalog entry to submit if the user picks create
+	ExactMatch         *GearCatalogItem        `json:"exactMatch,omitempty"` // Same canonical key
+	NearMatches        []NearMatch             `json:"nearMatches"`
+	SuggestedAction    InventoryImportAction   `json:"suggestedAction"`
+	SuggestedCatalogID string                  `json:"suggestedCatalogId,omitempty"`
+	Error              string                  `json:"error,omitempty"` // Parse error; the row can't be committed as-is
+}
+
+// InventoryImportPreview is the review step of an import
+type InventoryImportPreview struct {
+	Rows      []InventoryImportRow `json:"rows"`
+	Exact     int                  `json:"exact"`     // Rows with a canonical-key match
+	Near      int                  `json:"near"`      // Rows with only near matches
+	Unmatched int                  `json:"unmatched"` // Rows with no catalog match
+	Invalid   int                  `json:"invalid"`
+}
+
+// InventoryImportDecision is the user's confirmed choice for one reviewed row
+type InventoryImportDecision struct {
+	Row       int                      `json:"row"`
+	Item      AddInventoryParams       `json:"item"`
+	Action    InventoryImportAction    `json:"action"`
+	CatalogID string                   `json:"catalogId,omitempty"` // Required for link
+	Catalog   *CreateGearCatalogParams `json:"catalog,omitempty"`   // Used for create; derived from the item when omitted
+}
+
+// InventoryImportCommitParams are the confirmed rows of an import
+type InventoryImportCommitParams struct {
+	Rows []InventoryImportDecision `json:"rows"`
+}
+
+// InventoryImportResult summarizes a committed inventory import
+type InventoryImportResult struct {
+	Added          int                       `json:"added"`
+	Linked         int                       `json:"linked"`         // Added with a link to an existing catalog item
+	CatalogCreated int                       `json:"catalogCreated"` // New catalog entries submitted
+	Unlinked       int                       `json:"unlinked"`
+	Failed         int                       `json:"failed"`
+	Errors         []InventoryImportRowError `json:"errors,omitempty"`
+}
+
+// InventoryImportRowError describes a row that could not be imported
+type InventoryImportRowError struct {
+	Row   int    `json:"row"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// equipmentCategoryAliases are singular and shorthand category spellings
+// seen in spreadsheets
+var equipmentCategoryAliases = map[string]EquipmentCategory{
+	"frame":             CategoryFrames,
+	"vtxs":              CategoryVTX,
+	"flight_controller": CategoryFC,
+	"escs":              CategoryESC,
+	"stack":             CategoryStacks,
+	"motor":             CategoryMotors,
+	"propeller":         CategoryPropellers,
+	"props":             CategoryPropellers,
+	"receiver":          CategoryReceivers,
+	"rx":                CategoryReceivers,
+	"battery":           CategoryBatteries,
+	"camera":            CategoryCameras,
+	"antenna":           CategoryAntennas,
+	"accessory":         CategoryAccessories,
+	"other":             CategoryAccessories,
+}
+
+// NormalizeEquipmentCategory maps spreadsheet spellings like "Flight Controllers",
+// "motor" or "FC" onto an equipment category
+func NormalizeEquipmentCategory(value string) (EquipmentCategory, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer(" ", "_", "-", "_").Replace(normalized)
+	if normalized == "" {
+		return "", false
+	}
+	for _, category := range AllCategories() {
+		if EquipmentCategory(normalized) == category {
+			return category, true
+		}
+	}
+	if category, ok := equipmentCategoryAliases[normalized]; ok {
+		return category, true
+	}
+	for _, gearType := range AllGearTypes() {
+		if GearType(normalized) == gearType {
+			return gearType.ToEquipmentCategory(), true
+		}
+	}
+	return "", false
+}
+
+// InventoryImportCatalogParams derives the catalog entry for an imported item:
+// brand falls back to the manufacturer, and model to the item name with the
+// brand prefix removed
+func InventoryImportCatalogParams(item AddInventoryParams, brand, model, variant string) CreateGearCatalogParams {
+	brand = strings.TrimSpace(brand)
+	if brand == "" {
+		brand = strings.TrimSpace(item.Manufacturer)
+	}
+	model = strings.TrimSpace(model)
+	if model == "" {
+		model = strings.TrimSpace(item.Name)
+		if brand != "" && len(model) > len(brand) && strings.EqualFold(model[:len(brand)], brand) {
+			model = strings.TrimSpace(model[len(brand):])
+		}
+	}
+	return CreateGearCatalogParams{
+		GearType: GearTypeFromEquipmentCategory(item.Category),
+		Brand:    brand,
+		Model:    model,
+		Variant:  strings.TrimSpace(variant),
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestNormalizeEquipmentCategory(t *testing.T) {
+	tests := []struct {
+		input string
+		want  EquipmentCategory
+		ok    bool
+	}{
+		{"motors", CategoryMotors, true},
+		{"Flight Controllers", CategoryFC, true},
+		{"fc", CategoryFC, true},
+		{"Motor", CategoryMotors, true},
+		{"props", CategoryPropellers, true},
+		{"radio", CategoryAccessories, true},
+		{"", "", false},
+		{"widgets", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeEquipmentCategory(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizeEquipmentCategory(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestInventoryImportCatalogParams(t *testing.T) {
+	item := AddInventoryParams{Name: "iFlight XING2 2207", Category: CategoryMotors, Manufacturer: "iFlight"}
+
+	got := InventoryImportCatalogParams(item, "", "", " 1855KV ")
+	if got.GearType != GearTypeMotor || got.Brand != "iFlight" || got.Model != "XING2 2207" || got.Variant != "1855KV" {
+		t.Fatalf("InventoryImportCatalogParams() = %+v", got)
+	}
+
+	got = InventoryImportCatalogParams(item, "iFlight", "XING 2", "")
+	if got.Model != "XING 2" {
+		t.Fatalf("explicit model should win, got %+v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// InventoryUnitCondition is the state of one physical piece of gear.
+type InventoryUnitCondition string
+
+const (
+	InventoryUnitNew      InventoryUnitCondition = "new"
+	InventoryUnitUsed     InventoryUnitCondition = "used"
+	InventoryUnitDamaged  InventoryUnitCondition = "damaged"
+	InventoryUnitDead     InventoryUnitCondition = "dead"
+	InventoryUnitRepaired InventoryUnitCondition = "repaired"
+)
+
+// NormalizeInventoryUnitCondition parses a condition value. An empty value is
+// "new"; ok is false for anything else that isn't a known condition.
+func NormalizeInventoryUnitCondition(value string) (InventoryUnitCondition, bool) {
+	condition := InventoryUnitCondition(strings.ToLower(strings.TrimSpace(value)))
+	switch condition {
+	case "":
+		return InventoryUnitNew, true
+	case InventoryUnitNew, InventoryUnitUsed, InventoryUnitDamaged, InventoryUnitDead, InventoryUnitRepaired:
+		return condition, true
+	default:
+		return "", false
+	}
+}
+
+// InventoryUnit is one physical piece of an inventory item, e.g. one of four
+// identical motors. An item's quantity counts every piece; units only exist
+// for the pieces someone is tracking individually.
+type InventoryUnit struct {
+	ID              string                 `json:"id"`
+	InventoryItemID string                 `json:"inventoryItemId"`
+	SerialNumber    string                 `json:"serialNumber,omitempty"`
+	PurchasePrice   *float64               `json:"purchasePrice,omitempty"`
+	PurchaseSeller  string                 `json:"purchaseSeller,omitempty"`
+	Condition       InventoryUnitCondition `json:"condition"`
+	AircraftID      string                 `json:"aircraftId,omitempty"`
+	BuildID         string                 `json:"buildId,omitempty"`
+	Notes           string                 `json:"notes,omitempty"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	UpdatedAt       time.Time              `json:"updatedAt"`
+}
+
+// AddInventoryUnitParams represents the parameters for tracking a new unit.
+type AddInventoryUnitParams struct {
+	SerialNumber   string                 `json:"serialNumber,omitempty"`
+	PurchasePrice  *float64               `json:"purchasePrice,omitempty"`
+	PurchaseSeller string                 `json:"purchaseSeller,omitempty"`
+	Condition      InventoryUnitCondition `json:"condition,omitempty"`
+	AircraftID     string                 `json:"aircraftId,omitempty"`
+	BuildID        string                 `json:"buildId,omitempty"`
+	Notes          string                 `json:"notes,omitempty"`
+}
+
+// UpdateInventoryUnitParams represents the parameters for updating a unit.
+// An empty AircraftID unassigns the unit.
+type UpdateInventoryUnitParams struct {
+	ID             string                  `json:"id"`
+	SerialNumber   *string                 `json:"serialNumber,omitempty"`
+	PurchasePrice  *float64                `json:"purchasePrice,omitempty"`
+	PurchaseSeller *string                 `json:"purchaseSeller,omitempty"`
+	Condition      *InventoryUnitCondition `json:"condition,omitempty"`
+	AircraftID     *string                 `json:"aircraftId,omitempty"`
+	BuildID        *string                 `json:"buildId,omitempty"`
+	Notes          *string                 `json:"notes,omitempty"`
+}
@@ -5,7 +5,9 @@ import (
 	"strings"
 )
 
-// InventoryItemDetailsSpecKey is the specs key that stores per-item purchase/build metadata.
+// InventoryItemDetailsSpecKey is the specs key older clients used to store
+// per-item purchase/build metadata. Those details are InventoryUnit rows now;
+// the key is only read so they can be moved out of specs.
 const InventoryItemDetailsSpecKey = "__ff_inventory_item_details"
 
 type inventoryItemDetailSpec struct {
@@ -14,76 +16,83 @@ type inventoryItemDetailSpec struct {
 	BuildID        string   `json:"buildId"`
 }
 
-func (d inventoryItemDetailSpec) isEmpty() bool {
-	return d.PurchasePrice == nil &&
-		strings.TrimSpace(d.PurchaseSeller) == "" &&
-		strings.TrimSpace(d.BuildID) == ""
-}
-
 // CalculateInventoryItemTotalValue returns the total known value for an inventory row.
 //
 // Behavior:
-//   - When per-item detail specs exist, sum known per-item purchasePrice values.
-//   - If per-item detail count is lower than quantity, fallback to top-level purchasePrice
-//     for the remaining quantity.
-//   - Without per-item detail specs, use top-level purchasePrice * quantity.
-func CalculateInventoryItemTotalValue(quantity int, purchasePrice *float64, specs json.RawMessage) float64 {
+//   - Tracked units with a purchasePrice count at that price.
+//   - Tracked units without a price or seller were bought with the item and
+//     use the top-level purchasePrice; units from another seller with no
+//     price add nothing.
+//   - Pieces beyond the tracked units use the top-level purchasePrice.
+func CalculateInventoryItemTotalValue(quantity int, purchasePrice *float64, units []InventoryUnit) float64 {
 	if quantity <= 0 {
 		return 0
 	}
 
-	details, hasDetails := extractInventoryItemDetailSpecs(specs)
-	if hasDetails {
-		total := 0.0
-		fallbackCount := 0
-		for _, detail := range details {
-			if detail.PurchasePrice != nil && *detail.PurchasePrice >= 0 {
-				total += *detail.PurchasePrice
-				continue
-			}
-
-			if purchasePrice != nil && *purchasePrice >= 0 && detail.isEmpty() {
-				fallbackCount++
-			}
+	hasFallback := purchasePrice != nil && *purchasePrice >= 0
+	total := 0.0
+	fallbackCount := 0
+	for _, unit := range units {
+		if unit.PurchasePrice != nil && *unit.PurchasePrice >= 0 {
+			total += *unit.PurchasePrice
+			continue
 		}
-
-		if purchasePrice != nil && *purchasePrice >= 0 {
-			if len(details) < quantity {
-				fallbackCount += quantity - len(details)
-			}
-			total += *purchasePrice * float64(fallbackCount)
+		if strings.TrimSpace(unit.PurchaseSeller) == "" {
+			fallbackCount++
 		}
-
-		return total
 	}
-
-	if purchasePrice == nil || *purchasePrice < 0 {
-		return 0
+	if len(units) < quantity {
+		fallbackCount += quantity - len(units)
 	}
 
-	return *purchasePrice * float64(quantity)
+	if hasFallback {
+		total += *purchasePrice * float64(fallbackCount)
+	}
+	return total
 }
 
-func extractInventoryItemDetailSpecs(specs json.RawMessage) ([]inventoryItemDetailSpec, bool) {
+// ExtractLegacyInventoryUnits moves per-item details out of specs. It returns
+// specs without the details key and the units the details described; ok is
+// false, and specs are returned unchanged, when there are no details.
+func ExtractLegacyInventoryUnits(specs json.RawMessage) (json.RawMessage, []AddInventoryUnitParams, bool) {
 	trimmed := strings.TrimSpace(string(specs))
 	if trimmed == "" || trimmed == "null" {
-		return nil, false
+		return specs, nil, false
 	}
 
 	var payload map[string]json.RawMessage
 	if err := json.Unmarshal(specs, &payload); err != nil {
-		return nil, false
+		return specs, nil, false
 	}
 
 	rawDetails, ok := payload[InventoryItemDetailsSpecKey]
 	if !ok {
-		return nil, false
+		return specs, nil, false
 	}
 
 	var details []inventoryItemDetailSpec
 	if err := json.Unmarshal(rawDetails, &details); err != nil {
-		return nil, false
+		return specs, nil, false
+	}
+
+	delete(payload, InventoryItemDetailsSpecKey)
+	cleaned, err := json.Marshal(payload)
+	if err != nil {
+		return specs, nil, false
 	}
 
-	return details, true
+	units := make([]AddInventoryUnitParams, 0, len(details))
+	for _, detail := range details {
+		unit := AddInventoryUnitParams{
+			PurchaseSeller: strings.TrimSpace(detail.PurchaseSeller),
+			BuildID:        strings.TrimSpace(detail.BuildID),
+			Condition:      InventoryUnitNew,
+		}
+		if detail.PurchasePrice != nil && *detail.PurchasePrice >= 0 {
+			price := *detail.PurchasePrice
+			unit.PurchasePrice = &price
+		}
+		units = append(units, unit)
+	}
+	return cleaned, units, true
 }
@@ -6,10 +6,10 @@ import (
 )
 
 func TestCalculateInventoryItemTotalValue(t *testing.T) {
-	t.Run("uses top-level purchase price when detail specs are absent", func(t *testing.T) {
+	t.Run("uses top-level purchase price when no units are tracked", func(t *testing.T) {
 		price := 25.0
 
-		got := CalculateInventoryItemTotalValue(3, &price, json.RawMessage(`{"kv":"1950"}`))
+		got := CalculateInventoryItemTotalValue(3, &price, nil)
 		want := 75.0
 
 		if got != want {
@@ -17,16 +17,14 @@ func TestCalculateInventoryItemTotalValue(t *testing.T) {
 		}
 	})
 
-	t.Run("uses per-item detail prices when present", func(t *testing.T) {
+	t.Run("uses per-unit prices when present", func(t *testing.T) {
 		price := 25.0
-		specs := json.RawMessage(`{
-			"__ff_inventory_item_details": [
-				{"purchasePrice": 20},
-				{"purchasePrice": 30}
-			]
-		}`)
-
-		got := CalculateInventoryItemTotalValue(2, &price, specs)
+		units := []InventoryUnit{
+			{PurchasePrice: floatPtr(20)},
+			{PurchasePrice: floatPtr(30)},
+		}
+
+		got := CalculateInventoryItemTotalValue(2, &price, units)
 		want := 50.0
 
 		if got != want {
@@ -34,15 +32,13 @@ func TestCalculateInventoryItemTotalValue(t *testing.T) {
 		}
 	})
 
-	t.Run("falls back for missing detail entries", func(t *testing.T) {
+	t.Run("falls back for untracked pieces", func(t *testing.T) {
 		price := 40.0
-		specs := json.RawMessage(`{
-			"__ff_inventory_item_details": [
-				{"purchasePrice": 35}
-			]
-		}`)
+		units := []InventoryUnit{
+			{PurchasePrice: floatPtr(35)},
+		}
 
-		got := CalculateInventoryItemTotalValue(2, &price, specs)
+		got := CalculateInventoryItemTotalValue(2, &price, units)
 		want := 75.0 // 35 + fallback 40
 
 		if got != want {
@@ -50,16 +46,14 @@ func TestCalculateInventoryItemTotalValue(t *testing.T) {
 		}
 	})
 
-	t.Run("falls back for empty detail rows when top-level price exists", func(t *testing.T) {
+	t.Run("falls back for units without price or seller", func(t *testing.T) {
 		price := 40.0
-		specs := json.RawMessage(`{
-			"__ff_inventory_item_details": [
-				{"purchasePrice": 35},
-				{}
-			]
-		}`)
-
-		got := CalculateInventoryItemTotalValue(2, &price, specs)
+		units := []InventoryUnit{
+			{PurchasePrice: floatPtr(35)},
+			{SerialNumber: "M-0042", Condition: InventoryUnitDamaged},
+		}
+
+		got := CalculateInventoryItemTotalValue(2, &price, units)
 		want := 75.0 // 35 + fallback 40
 
 		if got != want {
@@ -67,16 +61,14 @@ func TestCalculateInventoryItemTotalValue(t *testing.T) {
 		}
 	})
 
-	t.Run("does not fallback for non-empty detail rows that omit price", func(t *testing.T) {
+	t.Run("does not fallback for units from another seller that omit price", func(t *testing.T) {
 		price := 40.0
-		specs := json.RawMessage(`{
-			"__ff_inventory_item_details": [
-				{"purchasePrice": 35},
-				{"purchaseSeller": "RDQ"}
-			]
-		}`)
-
-		got := CalculateInventoryItemTotalValue(2, &price, specs)
+		units := []InventoryUnit{
+			{PurchasePrice: floatPtr(35)},
+			{PurchaseSeller: "RDQ"},
+		}
+
+		got := CalculateInventoryItemTotalValue(2, &price, units)
 		want := 35.0
 
 		if got != want {
@@ -84,3 +76,57 @@ func TestCalculateInventoryItemTotalValue(t *testing.T) {
 		}
 	})
 }
+
+func TestExtractLegacyInventoryUnits(t *testing.T) {
+	specs := json.RawMessage(`{
+		"kv": "1950",
+		"__ff_inventory_item_details": [
+			{"purchasePrice": 35, "purchaseSeller": " RDQ "},
+			{"buildId": "build-1"},
+			{"purchasePrice": -1}
+		]
+	}`)
+
+	cleaned, units, ok := ExtractLegacyInventoryUnits(specs)
+	if !ok {
+		t.Fatalf("expected legacy details to be found")
+	}
+	if string(cleaned) != `{"kv":"1950"}` {
+		t.Fatalf("cleaned specs = %s", cleaned)
+	}
+	if len(units) != 3 {
+		t.Fatalf("expected 3 units, got %d", len(units))
+	}
+	if units[0].PurchasePrice == nil || *units[0].PurchasePrice != 35 || units[0].PurchaseSeller != "RDQ" {
+		t.Fatalf("unexpected first unit: %+v", units[0])
+	}
+	if units[1].BuildID != "build-1" || units[1].Condition != InventoryUnitNew {
+		t.Fatalf("unexpected second unit: %+v", units[1])
+	}
+	if units[2].PurchasePrice != nil {
+		t.Fatalf("expected negative price to be dropped, got %v", *units[2].PurchasePrice)
+	}
+
+	for _, raw := range []string{``, `null`, `{"kv":"1950"}`, `{"__ff_inventory_item_details": "nope"}`} {
+		unchanged, units, ok := ExtractLegacyInventoryUnits(json.RawMessage(raw))
+		if ok || units != nil || string(unchanged) != raw {
+			t.Fatalf("ExtractLegacyInventoryUnits(%q) = %s, %v, %v", raw, unchanged, units, ok)
+		}
+	}
+}
+
+func TestNormalizeInventoryUnitCondition(t *testing.T) {
+	if got, ok := NormalizeInventoryUnitCondition(""); !ok || got != InventoryUnitNew {
+		t.Fatalf("empty condition = %q, %v", got, ok)
+	}
+	if got, ok := NormalizeInventoryUnitCondition(" Dead "); !ok || got != InventoryUnitDead {
+		t.Fatalf("dead condition = %q, %v", got, ok)
+	}
+	if _, ok := NormalizeInventoryUnitCondition("crispy"); ok {
+		t.Fatalf("expected unknown condition to be rejected")
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// MaintenanceEventType is what happened to an aircraft
+type MaintenanceEventType string
+
+const (
+	MaintenanceEventCrash       MaintenanceEventType = "crash"
+	MaintenanceEventRepair      MaintenanceEventType = "repair"
+	MaintenanceEventMaintenance MaintenanceEventType = "maintenance"
+)
+
+// NormalizeMaintenanceEventType trims and validates an event type.
+func NormalizeMaintenanceEventType(value MaintenanceEventType) (MaintenanceEventType, bool) {
+	eventType := MaintenanceEventType(strings.ToLower(strings.TrimSpace(string(value))))
+	switch eventType {
+	case MaintenanceEventCrash, MaintenanceEventRepair, MaintenanceEventMaintenance:
+		return eventType, true
+	default:
+		return "", false
+	}
+}
+
+// MaintenanceComponentAction is what an event did to one component
+type MaintenanceComponentAction string
+
+const (
+	MaintenanceComponentDamaged  MaintenanceComponentAction = "damaged"
+	MaintenanceComponentRepaired MaintenanceComponentAction = "repaired"
+	MaintenanceComponentReplaced MaintenanceComponentAction = "replaced"
+)
+
+// NormalizeMaintenanceComponentAction trims and validates a component action.
+// An empty action is "damaged".
+func NormalizeMaintenanceComponentAction(value MaintenanceComponentAction) (MaintenanceComponentAction, bool) {
+	action := MaintenanceComponentAction(strings.ToLower(strings.TrimSpace(string(value))))
+	switch action {
+	case "":
+		return MaintenanceComponentDamaged, true
+	case MaintenanceComponentDamaged, MaintenanceComponentRepaired, MaintenanceComponentReplaced:
+		return action, true
+	default:
+		return "", false
+	}
+}
+
+// UnitCondition is the condition a tracked unit is left in by the action.
+func (a MaintenanceComponentAction) UnitCondition() InventoryUnitCondition {
+	if a == MaintenanceComponentRepaired {
+		return InventoryUnitRepaired
+	}
+	return InventoryUnitDamaged
+}
+
+// MaintenanceEvent is a crash, repair or routine maintenance entry in an aircraft's log
+type MaintenanceEvent struct {
+	ID          string                      `json:"id"`
+	AircraftID  string                      `json:"aircraftId"`
+	Type        MaintenanceEventType        `json:"type"`
+	Title       string                      `json:"title"`
+	Description string                      `json:"description,omitempty"`
+	OccurredAt  time.Time                   `json:"occurredAt"`
+	RepairCost  *float64                    `json:"repairCost,omitempty"`
+	Components  []MaintenanceEventComponent `json:"components"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+	UpdatedAt   time.Time                   `json:"updatedAt"`
+}
+
+// MaintenanceEventComponent records one component an event damaged, repaired or replaced
+type MaintenanceEventComponent struct {
+	ID                 string                     `json:"id"`
+	EventID            string                     `json:"eventId"`
+	Category           ComponentCategory          `json:"category"`
+	Action             MaintenanceComponentAction `json:"action"`
+	OldInventoryItemID string                     `json:"oldInventoryItemId,omitempty"`
+	NewInventoryItemID string                     `json:"newInventoryItemId,omitempty"`
+	UnitID             string                     `json:"unitId,omitempty"`
+	UnitCondition      InventoryUnitCondition     `json:"unitCondition,omitempty"`
+	ReplacedCost       *float64                   `json:"replacedCost,omitempty"` // Purchase price of the part swapped out, captured at swap time
+	Notes              string                     `json:"notes,omitempty"`
+	CreatedAt          time.Time                  `json:"createdAt"`
+}
+
+// MaintenanceComponentParams lists a component affected by a new event.
+// UnitID picks the tracked unit to mark; when empty, the unit of the installed
+// item assigned to the aircraft is used if there is exactly one.
+type MaintenanceComponentParams struct {
+	Category      ComponentCategory          `json:"category"`
+	Action        MaintenanceComponentAction `json:"action,omitempty"`
+	UnitID        string                     `json:"unitId,omitempty"`
+	UnitCondition InventoryUnitCondition     `json:"unitCondition,omitempty"`
+	Notes         string                     `json:"notes,omitempty"`
+}
+
+// CreateMaintenanceEventParams defines parameters for logging a maintenance event
+type CreateMaintenanceEventParams struct {
+	Type        MaintenanceEventType         `json:"type"`
+	Title       string                       `json:"title"`
+	Description string                       `json:"description,omitempty"`
+	OccurredAt  *time.Time                   `json:"occurredAt,omitempty"`
+	RepairCost  *float64                     `json:"repairCost,omitempty"`
+	Components  []MaintenanceComponentParams `json:"components,omitempty"`
+}
+
+// UpdateMaintenanceEventParams defines parameters for editing a maintenance event
+type UpdateMaintenanceEventParams struct {
+	ID          string                `json:"id"`
+	Type        *MaintenanceEventType `json:"type,omitempty"`
+	Title       *string               `json:"title,omitempty"`
+	Description *string               `json:"description,omitempty"`
+	OccurredAt  *time.Time            `json:"occurredAt,omitempty"`
+	RepairCost  *float64              `json:"repairCost,omitempty"`
+}
+
+// AircraftCostOfOwnership totals what an aircraft has cost so far
+type AircraftCostOfOwnership struct {
+	AircraftID            string  `json:"aircraftId"`
+	ComponentCost         float64 `json:"componentCost"`         // Installed components at purchase price
+	ReplacedComponentCost float64 `json:"replacedComponentCost"` // Parts swapped out during maintenance events
+	RepairCost            float64 `json:"repairCost"`
+	TotalCost             float64 `json:"totalCost"`
+	EventCount            int     `json:"eventCount"`
+	CrashCount            int     `json:"crashCount"`
+}
+
+// CalculateAircraftCostOfOwnership adds up installed component prices, the
+// parts replaced during maintenance events and the events' repair costs.
+// Components without a known purchase price add nothing.
+func CalculateAircraftCostOfOwnership(aircraftID string, components []AircraftComponent, events []MaintenanceEvent) AircraftCostOfOwnership {
+	cost := AircraftCostOfOwnership{AircraftID: aircraftID, EventCount: len(events)}
+
+	for _, component := range components {
+		if component.InventoryItem == nil || component.InventoryItem.PurchasePrice == nil || *component.InventoryItem.PurchasePrice < 0 {
+			continue
+		}
+		cost.ComponentCost += *component.InventoryItem.PurchasePrice
+	}
+
+	for _, event := range events {
+		if event.Type == MaintenanceEventCrash {
+			cost.CrashCount++
+		}
+		if event.RepairCost != nil && *event.RepairCost > 0 {
+			cost.RepairCost += *event.RepairCost
+		}
+		for _, component := range event.Components {
+			if component.ReplacedCost != nil && *component.ReplacedCost > 0 {
+				cost.ReplacedComponentCost += *component.ReplacedCost
+			}
+		}
+	}
+
+	cost.TotalCost = cost.ComponentCost + cost.ReplacedComponentCost + cost.RepairCost
+	return cost
+}
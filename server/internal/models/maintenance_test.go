@@ -0,0 +1,80 @@
+package models
+
+import "testing"
+
+func TestNormalizeMaintenanceEventType(t *testing.T) {
+	tests := []struct {
+		value  MaintenanceEventType
+		want   MaintenanceEventType
+		wantOK bool
+	}{
+		{" Crash ", MaintenanceEventCrash, true},
+		{"repair", MaintenanceEventRepair, true},
+		{"maintenance", MaintenanceEventMaintenance, true},
+		{"", "", false},
+		{"flyaway", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeMaintenanceEventType(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NormalizeMaintenanceEventType(%q) = %q, %v; want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNormalizeMaintenanceComponentAction(t *testing.T) {
+	if got, ok := NormalizeMaintenanceComponentAction(""); !ok || got != MaintenanceComponentDamaged {
+		t.Fatalf("empty action = %q, %v; want damaged", got, ok)
+	}
+	if got, ok := NormalizeMaintenanceComponentAction("REPAIRED"); !ok || got != MaintenanceComponentRepaired {
+		t.Fatalf("REPAIRED = %q, %v; want repaired", got, ok)
+	}
+	if _, ok := NormalizeMaintenanceComponentAction("lost"); ok {
+		t.Fatal("lost should not be a valid action")
+	}
+	if got := MaintenanceComponentRepaired.UnitCondition(); got != InventoryUnitRepaired {
+		t.Fatalf("repaired unit condition = %q, want repaired", got)
+	}
+	if got := MaintenanceComponentReplaced.UnitCondition(); got != InventoryUnitDamaged {
+		t.Fatalf("replaced unit condition = %q, want damaged", got)
+	}
+}
+
+func TestCalculateAircraftCostOfOwnership(t *testing.T) {
+	components := []AircraftComponent{
+		{Category: ComponentCategoryFrame, InventoryItem: &InventoryItem{PurchasePrice: floatPtr(60)}},
+		{Category: ComponentCategoryMotors, InventoryItem: &InventoryItem{PurchasePrice: floatPtr(80)}},
+		{Category: ComponentCategoryVTX, InventoryItem: &InventoryItem{}},
+		{Category: ComponentCategoryCamera},
+	}
+	events := []MaintenanceEvent{
+		{
+			Type:       MaintenanceEventCrash,
+			RepairCost: floatPtr(12.5),
+			Components: []MaintenanceEventComponent{
+				{Action: MaintenanceComponentReplaced, ReplacedCost: floatPtr(55)},
+				{Action: MaintenanceComponentDamaged},
+			},
+		},
+		{Type: MaintenanceEventMaintenance},
+	}
+
+	got := CalculateAircraftCostOfOwnership("ac-1", components, events)
+
+	if got.ComponentCost != 140 {
+		t.Errorf("ComponentCost = %v, want 140", got.ComponentCost)
+	}
+	if got.ReplacedComponentCost != 55 {
+		t.Errorf("ReplacedComponentCost = %v, want 55", got.ReplacedComponentCost)
+	}
+	if got.RepairCost != 12.5 {
+		t.Errorf("RepairCost = %v, want 12.5", got.RepairCost)
+	}
+	if got.TotalCost != 207.5 {
+		t.Errorf("TotalCost = %v, want 207.5", got.TotalCost)
+	}
+	if got.EventCount != 2 || got.CrashCount != 1 {
+		t.Errorf("EventCount, CrashCount = %d, %d; want 2, 1", got.EventCount, got.CrashCount)
+	}
+}
@@ -10,6 +10,29 @@ const (
 	OAuthResponseTypeCode            = "code"
 )
 
+// MCP OAuth scopes. The read scope covers every private read tool; each write
+// scope unlocks the MCP tools that change one area of a user's data.
+const (
+	MCPScopeRead           = "flyingforge.read"
+	MCPScopeInventoryWrite = "inventory:write"
+	MCPScopeBatteriesWrite = "batteries:write"
+	MCPScopeAircraftWrite  = "aircraft:write"
+	MCPScopeBuildsWrite    = "builds:write"
+	MCPScopeFlightsWrite   = "flights:write"
+)
+
+// MCPWriteScopes returns every scope an OAuth client can be granted on top of
+// the read scope
+func MCPWriteScopes() []string {
+	return []string{
+		MCPScopeInventoryWrite,
+		MCPScopeBatteriesWrite,
+		MCPScopeAircraftWrite,
+		MCPScopeBuildsWrite,
+		MCPScopeFlightsWrite,
+	}
+}
+
 // OAuthClient stores dynamic client registration metadata for MCP clients.
 type OAuthClient struct {
 	ID                      string    `json:"id"`
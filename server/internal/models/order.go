@@ -0,0 +1,195 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// OrderCarrier is the shipping carrier an order's tracking number belongs to
+type OrderCarrier string
+
+const (
+	CarrierUPS   OrderCarrier = "ups"
+	CarrierUSPS  OrderCarrier = "usps"
+	CarrierFedEx OrderCarrier = "fedex"
+	CarrierDHL   OrderCarrier = "dhl"
+	CarrierOther OrderCarrier = "other" // Not polled; status is updated by hand
+)
+
+// NormalizeOrderCarrier validates a carrier, defaulting empty to other
+func NormalizeOrderCarrier(carrier OrderCarrier) (OrderCarrier, bool) {
+	switch OrderCarrier(strings.ToLower(strings.TrimSpace(string(carrier)))) {
+	case CarrierUPS:
+		return CarrierUPS, true
+	case CarrierUSPS:
+		return CarrierUSPS, true
+	case CarrierFedEx:
+		return CarrierFedEx, true
+	case CarrierDHL:
+		return CarrierDHL, true
+	case CarrierOther, "":
+		return CarrierOther, true
+	default:
+		return "", false
+	}
+}
+
+// OrderStatus is where a shipment is in its journey
+type OrderStatus string
+
+const (
+	OrderStatusUnknown        OrderStatus = "unknown"
+	OrderStatusLabelCreated   OrderStatus = "label_created" // Carrier has the label but not the package
+	OrderStatusInTransit      OrderStatus = "in_transit"
+	OrderStatusOutForDelivery OrderStatus = "out_for_delivery"
+	OrderStatusDelivered      OrderStatus = "delivered"
+	OrderStatusException      OrderStatus = "exception" // Delayed, held or failed delivery attempt
+	OrderStatusReturned       OrderStatus = "returned"  // Returned to sender
+)
+
+// orderStatusAliases are carrier spellings of the statuses above
+var orderStatusAliases = map[string]OrderStatus{
+	"pre_transit":          OrderStatusLabelCreated,
+	"info_received":        OrderStatusLabelCreated,
+	"label_printed":        OrderStatusLabelCreated,
+	"transit":              OrderStatusInTransit,
+	"accepted":             OrderStatusInTransit,
+	"picked_up":            OrderStatusInTransit,
+	"available_for_pickup": OrderStatusOutForDelivery,
+	"failure":              OrderStatusException,
+	"failed_attempt":       OrderStatusException,
+	"delayed":              OrderStatusException,
+	"return_to_sender":     OrderStatusReturned,
+}
+
+// NormalizeOrderStatus maps a status, including common carrier spellings like
+// "Pre-Transit" or "picked up", onto an order status
+func NormalizeOrderStatus(value string) (OrderStatus, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer(" ", "_", "-", "_").Replace(normalized)
+	switch status := OrderStatus(normalized); status {
+	case OrderStatusUnknown, OrderStatusLabelCreated, OrderStatusInTransit, OrderStatusOutForDelivery,
+		OrderStatusDelivered, OrderStatusException, OrderStatusReturned:
+		return status, true
+	}
+	if status, ok := orderStatusAliases[normalized]; ok {
+		return status, true
+	}
+	return "", false
+}
+
+// IsFinal reports whether the shipment has stopped moving. Final orders are no
+// longer polled and carrier updates can't move them.
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusDelivered || s == OrderStatusReturned
+}
+
+// Order is a purchase being shipped to the user, optionally linked to the
+// inventory items it contains
+type Order struct {
+	ID             string       `json:"id"`
+	UserID         string       `json:"userId,omitempty"`
+	Carrier        OrderCarrier `json:"carrier"`
+	TrackingNumber string       `json:"trackingNumber"`
+	Label          string       `json:"label,omitempty"` // e.g. "Motors from RDQ"
+	Status         OrderStatus  `json:"status"`
+	StatusDetails  string       `json:"statusDetails,omitempty"` // Latest carrier scan description
+	EstimatedDate  *time.Time   `json:"estimatedDate,omitempty"`
+	DeliveredAt    *time.Time   `json:"deliveredAt,omitempty"`
+	LastCheckedAt  *time.Time   `json:"lastCheckedAt,omitempty"`
+	Archived       bool         `json:"archived"`
+	Items          []OrderItem  `json:"items"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+// OrderItem is an inventory item waiting on an order
+type OrderItem struct {
+	InventoryItemID string     `json:"inventoryItemId"`
+	Name            string     `json:"name,omitempty"`
+	ReceivedAt      *time.Time `json:"receivedAt,omitempty"` // Set when the order is delivered
+}
+
+// InventoryItemOrder is the order an inventory item is linked to
+type InventoryItemOrder struct {
+	OrderID    string      `json:"orderId"`
+	Label      string      `json:"label,omitempty"`
+	Status     OrderStatus `json:"status"`
+	ReceivedAt *time.Time  `json:"receivedAt,omitempty"`
+}
+
+// Pending reports whether the item is still on its way
+func (o *InventoryItemOrder) Pending() bool {
+	return o != nil && o.ReceivedAt == nil
+}
+
+// CreateOrderParams defines parameters for creating an order
+type CreateOrderParams struct {
+	Carrier          OrderCarrier `json:"carrier,omitempty"` // Defaults to other
+	TrackingNumber   string       `json:"trackingNumber"`
+	Label            string       `json:"label,omitempty"`
+	EstimatedDate    *time.Time   `json:"estimatedDate,omitempty"`
+	InventoryItemIDs []string     `json:"inventoryItemIds,omitempty"`
+}
+
+// UpdateOrderParams defines parameters for updating an order. Setting Status
+// by hand is how orders with carrier "other" move along.
+type UpdateOrderParams struct {
+	ID             string        `json:"id"`
+	Carrier        *OrderCarrier `json:"carrier,omitempty"`
+	TrackingNumber *string       `json:"trackingNumber,omitempty"`
+	Label          *string       `json:"label,omitempty"`
+	Status         *OrderStatus  `json:"status,omitempty"`
+	StatusDetails  *string       `json:"statusDetails,omitempty"`
+	EstimatedDate  *time.Time    `json:"estimatedDate,omitempty"`
+	Archived       *bool         `json:"archived,omitempty"`
+}
+
+// OrderListParams defines filters for listing orders
+type OrderListParams struct {
+	Status          OrderStatus `json:"status,omitempty"`
+	IncludeArchived bool        `json:"includeArchived,omitempty"`
+	Limit           int         `json:"limit,omitempty"`
+	Offset          int         `json:"offset,omitempty"`
+}
+
+// OrderListResponse is the response for listing orders
+type OrderListResponse struct {
+	Orders     []Order `json:"orders"`
+	TotalCount int     `json:"totalCount"`
+}
+
+// CarrierStatus is a carrier's view of a shipment
+type CarrierStatus struct {
+	Status        OrderStatus
+	Details       string
+	EstimatedDate *time.Time
+	DeliveredAt   *time.Time
+}
+
+// ApplyCarrierStatus moves the order to the carrier's status. Final orders
+// keep their status, and a delivery without a carrier timestamp is stamped
+// with now. It reports whether the order just became delivered.
+func (o *Order) ApplyCarrierStatus(status CarrierStatus, now time.Time) (delivered bool) {
+	if o.Status.IsFinal() {
+		return false
+	}
+	if status.Status != "" {
+		o.Status = status.Status
+	}
+	if status.Details != "" {
+		o.StatusDetails = status.Details
+	}
+	if status.EstimatedDate != nil {
+		o.EstimatedDate = status.EstimatedDate
+	}
+	if o.Status != OrderStatusDelivered {
+		return false
+	}
+	deliveredAt := now
+	if status.DeliveredAt != nil && !status.DeliveredAt.IsZero() {
+		deliveredAt = *status.DeliveredAt
+	}
+	o.DeliveredAt = &deliveredAt
+	return true
+}
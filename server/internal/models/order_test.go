@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeOrderStatus(t *testing.T) {
+	tests := map[string]OrderStatus{
+		"in_transit":       OrderStatusInTransit,
+		" Pre-Transit ":    OrderStatusLabelCreated,
+		"Out For Delivery": OrderStatusOutForDelivery,
+		"picked up":        OrderStatusInTransit,
+		"RETURN_TO_SENDER": OrderStatusReturned,
+	}
+	for value, want := range tests {
+		got, ok := NormalizeOrderStatus(value)
+		if !ok || got != want {
+			t.Errorf("NormalizeOrderStatus(%q) = %q, %v, want %q", value, got, ok, want)
+		}
+	}
+	if _, ok := NormalizeOrderStatus("teleported"); ok {
+		t.Error("expected unknown status to be rejected")
+	}
+}
+
+func TestApplyCarrierStatus(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	order := &Order{Status: OrderStatusInTransit, StatusDetails: "Departed facility"}
+	if delivered := order.ApplyCarrierStatus(CarrierStatus{Status: OrderStatusOutForDelivery}, now); delivered {
+		t.Error("out for delivery reported as delivered")
+	}
+	if order.Status != OrderStatusOutForDelivery || order.StatusDetails != "Departed facility" {
+		t.Errorf("order = %+v, want status moved and details kept", order)
+	}
+
+	if delivered := order.ApplyCarrierStatus(CarrierStatus{Status: OrderStatusDelivered}, now); !delivered {
+		t.Error("expected delivery")
+	}
+	if order.DeliveredAt == nil || !order.DeliveredAt.Equal(now) {
+		t.Errorf("DeliveredAt = %v, want now when the carrier gives no timestamp", order.DeliveredAt)
+	}
+
+	// Carriers can't move a delivered order
+	if delivered := order.ApplyCarrierStatus(CarrierStatus{Status: OrderStatusInTransit, Details: "Late scan"}, now); delivered {
+		t.Error("final order reported delivered again")
+	}
+	if order.Status != OrderStatusDelivered || order.StatusDetails == "Late scan" {
+		t.Errorf("order = %+v, want delivered order unchanged", order)
+	}
+}
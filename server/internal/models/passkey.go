@@ -0,0 +1,116 @@
+package models
+
+import "time"
+
+// PasskeyCredential is a WebAuthn public key a user registered. Each one
+// belongs to a passkey UserIdentity keyed by its credential ID, and is
+// deleted with it.
+type PasskeyCredential struct {
+	ID           string     `json:"id"`
+	IdentityID   string     `json:"-"`
+	UserID       string     `json:"userId"`
+	CredentialID string     `json:"credentialId"` // base64url, as in WebAuthn JSON
+	PublicKey    []byte     `json:"-"`            // COSE_Key from the authenticator
+	SignCount    uint32     `json:"-"`
+	Name         string     `json:"name"`
+	Transports   []string   `json:"transports,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// The types below follow the WebAuthn Level 3 JSON serialization, so options
+// can be passed to PublicKeyCredential.parseCreationOptionsFromJSON and
+// parseRequestOptionsFromJSON, and credentials sent back with toJSON().
+// Binary fields are base64url strings.
+
+// PasskeyRelyingParty identifies FlyingForge to the authenticator
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PasskeyUserEntity describes the account a passkey is created for
+type PasskeyUserEntity struct {
+	ID          string `json:"id"` // User handle: base64url of the user ID
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// PasskeyCredentialParam is an accepted public key algorithm
+type PasskeyCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"` // COSE algorithm identifier
+}
+
+// PasskeyCredentialDescriptor refers to an existing credential
+type PasskeyCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// PasskeyAuthenticatorSelection states which authenticators may be used
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// PasskeyCreationOptions starts passkey registration
+type PasskeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RP                     PasskeyRelyingParty           `json:"rp"`
+	User                   PasskeyUserEntity             `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int                           `json:"timeout"` // milliseconds
+	ExcludeCredentials     []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                        `json:"attestation"`
+}
+
+// PasskeyRequestOptions starts passkey sign-in. AllowCredentials is empty so
+// the authenticator offers any discoverable FlyingForge passkey.
+type PasskeyRequestOptions struct {
+	Challenge        string                        `json:"challenge"`
+	Timeout          int                           `json:"timeout"` // milliseconds
+	RPID             string                        `json:"rpId"`
+	AllowCredentials []PasskeyCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                        `json:"userVerification"`
+}
+
+// PasskeyAttestationResponse is the authenticator's answer to a creation request
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON"`
+	AttestationObject string   `json:"attestationObject"`
+	Transports        []string `json:"transports,omitempty"`
+}
+
+// PasskeyAttestationCredential is a newly created credential
+type PasskeyAttestationCredential struct {
+	ID       string                     `json:"id"`
+	RawID    string                     `json:"rawId"`
+	Type     string                     `json:"type"`
+	Response PasskeyAttestationResponse `json:"response"`
+}
+
+// PasskeyRegistrationParams finishes passkey registration
+type PasskeyRegistrationParams struct {
+	Name       string                       `json:"name"`
+	Credential PasskeyAttestationCredential `json:"credential"`
+}
+
+// PasskeyAssertionResponse is the authenticator's answer to a sign-in request
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// PasskeyLoginParams finishes passkey sign-in with a signed assertion
+type PasskeyLoginParams struct {
+	ID       string                   `json:"id"`
+	RawID    string                   `json:"rawId"`
+	Type     string                   `json:"type"`
+	Response PasskeyAssertionResponse `json:"response"`
+}
@@ -33,6 +33,11 @@ const (
 	BackupTypeOther         BackupType = "other"
 )
 
+// HasEdgeTXModels reports whether backups of this type contain EdgeTX MODELS/*.yml files
+func (t BackupType) HasEdgeTXModels() bool {
+	return t == BackupTypeEdgeTXModels || t == BackupTypeSDCardPack
+}
+
 // RadioModel represents a known radio model for selection
 type RadioModel struct {
 	ID           string            `json:"id"`
@@ -76,8 +81,12 @@ type RadioBackup struct {
 	FileName    string     `json:"fileName"`
 	FileSize    int64      `json:"fileSize"`
 	Checksum    string     `json:"checksum,omitempty"`
-	StoragePath string     `json:"-"` // Internal storage path, not exposed in JSON
+	StoragePath string     `json:"-"` // Legacy local file path, not exposed in JSON
+	StorageKey  string     `json:"-"` // Object storage key; empty for backups still on local disk
 	CreatedAt   time.Time  `json:"createdAt"`
+
+	// Parsed EdgeTX model list (edgetx-models and sd-card-pack backups only)
+	ModelSummary *RadioBackupModelSummary `json:"modelSummary,omitempty"`
 }
 
 // CreateRadioParams defines parameters for creating a radio
@@ -101,6 +110,16 @@ type CreateRadioBackupParams struct {
 	FileName   string     `json:"fileName"`
 	FileSize   int64      `json:"fileSize"`
 	Checksum   string     `json:"checksum,omitempty"`
+
+	// Set by the service after storing and parsing the uploaded archive
+	StorageKey   string                   `json:"-"`
+	ModelSummary *RadioBackupModelSummary `json:"-"`
+}
+
+// RadioBackupDownloadURL is a short-lived direct download link for a backup file
+type RadioBackupDownloadURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 // RadioListParams defines parameters for listing radios
@@ -131,3 +150,137 @@ type RadioBackupListResponse struct {
 type RadioModelsResponse struct {
 	Models []RadioModel `json:"models"`
 }
+
+// EdgeTXModuleType describes which RF modules a model uses
+type EdgeTXModuleType string
+
+const (
+	EdgeTXModuleNone     EdgeTXModuleType = "none"
+	EdgeTXModuleInternal EdgeTXModuleType = "internal"
+	EdgeTXModuleExternal EdgeTXModuleType = "external"
+	EdgeTXModuleBoth     EdgeTXModuleType = "internal+external"
+)
+
+// EdgeTXModel summarizes one EdgeTX MODELS/*.yml file from a backup
+type EdgeTXModel struct {
+	FileName           string           `json:"fileName"`
+	Name               string           `json:"name"`
+	ModuleType         EdgeTXModuleType `json:"moduleType"`
+	InternalProtocol   string           `json:"internalProtocol,omitempty"` // e.g. CRSF, ACCESS, Multi
+	ExternalProtocol   string           `json:"externalProtocol,omitempty"`
+	ReceiverNumber     *int             `json:"receiverNumber,omitempty"` // Model match / receiver slot
+	Timers             []EdgeTXTimer    `json:"timers,omitempty"`
+	LogicalSwitchCount int              `json:"logicalSwitchCount"`
+}
+
+// EdgeTXTimer summarizes an enabled model timer
+type EdgeTXTimer struct {
+	Index        int    `json:"index"`
+	Name         string `json:"name,omitempty"`
+	Mode         string `json:"mode"`
+	StartSeconds int    `json:"startSeconds,omitempty"` // Countdown start; 0 counts up
+	Switch       string `json:"switch,omitempty"`
+	Persistent   bool   `json:"persistent,omitempty"`
+}
+
+// RadioBackupModelSummary is the parsed model list stored with a backup
+type RadioBackupModelSummary struct {
+	Models        []EdgeTXModel `json:"models"`
+	ModelCount    int           `json:"modelCount"`
+	ParseWarnings []string      `json:"parseWarnings,omitempty"` // Files that could not be parsed
+	ParsedAt      time.Time     `json:"parsedAt"`
+}
+
+// RadioBackupDiffMode describes how two backups were compared
+type RadioBackupDiffMode string
+
+const (
+	RadioBackupDiffModeEdgeTX   RadioBackupDiffMode = "edgetx-models" // Per-model YAML comparison
+	RadioBackupDiffModeManifest RadioBackupDiffMode = "manifest"      // File-level comparison by hash
+)
+
+// RadioBackupRef identifies a backup in a diff
+type RadioBackupRef struct {
+	ID         string     `json:"id"`
+	BackupName string     `json:"backupName"`
+	BackupType BackupType `json:"backupType"`
+	FileName   string     `json:"fileName"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// RadioBackupDiff is the comparison of two backups of the same radio
+type RadioBackupDiff struct {
+	RadioID    string              `json:"radioId"`
+	Base       RadioBackupRef      `json:"base"`
+	Target     RadioBackupRef      `json:"target"`
+	Mode       RadioBackupDiffMode `json:"mode"`
+	HasChanges bool                `json:"hasChanges"`
+	Models     *EdgeTXModelsDiff   `json:"models,omitempty"`
+	Files      *BackupManifestDiff `json:"files,omitempty"`
+	Warnings   []string            `json:"warnings,omitempty"`
+}
+
+// EdgeTXModelsDiff lists models added, removed and changed between two backups.
+// Models are matched by file name (e.g. model01.yml).
+type EdgeTXModelsDiff struct {
+	Added          []EdgeTXModelRef    `json:"added"`
+	Removed        []EdgeTXModelRef    `json:"removed"`
+	Changed        []EdgeTXModelChange `json:"changed"`
+	UnchangedCount int                 `json:"unchangedCount"`
+}
+
+// EdgeTXModelRef identifies a model file in a diff
+type EdgeTXModelRef struct {
+	FileName string `json:"fileName"`
+	Name     string `json:"name"`
+}
+
+// EdgeTXModelChange lists the field changes for one model
+type EdgeTXModelChange struct {
+	EdgeTXModelRef
+	Sections  []string            `json:"sections"` // Changed sections, e.g. mixes, inputs, outputs, failsafe
+	Changes   []EdgeTXFieldChange `json:"changes"`
+	Truncated bool                `json:"truncated,omitempty"` // Changes list was capped
+}
+
+// EdgeTXFieldChangeKind describes a single field change
+type EdgeTXFieldChangeKind string
+
+const (
+	EdgeTXFieldAdded   EdgeTXFieldChangeKind = "added"
+	EdgeTXFieldRemoved EdgeTXFieldChangeKind = "removed"
+	EdgeTXFieldChanged EdgeTXFieldChangeKind = "changed"
+)
+
+// EdgeTXFieldChange is one changed value in a model YAML file
+type EdgeTXFieldChange struct {
+	Section string                `json:"section"`
+	Path    string                `json:"path"` // Dotted YAML path, e.g. mixData.2.weight
+	Kind    EdgeTXFieldChangeKind `json:"kind"`
+	Before  string                `json:"before,omitempty"`
+	After   string                `json:"after,omitempty"`
+}
+
+// BackupManifestEntry is one file in a backup manifest
+type BackupManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupManifestChange is a file whose content differs between two backups
+type BackupManifestChange struct {
+	Path       string `json:"path"`
+	BeforeSize int64  `json:"beforeSize"`
+	AfterSize  int64  `json:"afterSize"`
+	BeforeHash string `json:"beforeHash"`
+	AfterHash  string `json:"afterHash"`
+}
+
+// BackupManifestDiff is a file-level comparison of two backups
+type BackupManifestDiff struct {
+	Added          []BackupManifestEntry  `json:"added"`
+	Removed        []BackupManifestEntry  `json:"removed"`
+	Changed        []BackupManifestChange `json:"changed"`
+	UnchangedCount int                    `json:"unchangedCount"`
+}
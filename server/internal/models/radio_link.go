@@ -0,0 +1,259 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RadioBindingMatchType describes how an EdgeTX model was linked to an aircraft
+type RadioBindingMatchType string
+
+const (
+	RadioBindingMatchModelMatchAndName RadioBindingMatchType = "model_match_and_name" // Receiver number and name agree
+	RadioBindingMatchModelMatch        RadioBindingMatchType = "model_match"          // Receiver number only
+	RadioBindingMatchName              RadioBindingMatchType = "name"                 // Name only (no model match set on one side)
+)
+
+// RadioLinkWarningKind identifies a radio/aircraft link problem
+type RadioLinkWarningKind string
+
+const (
+	// Two or more aircraft share a model match number, so one radio model binds them all
+	RadioLinkWarningModelMatchCollision RadioLinkWarningKind = "model_match_collision"
+	// A radio model is named after an aircraft but its receiver number differs
+	RadioLinkWarningModelMatchMismatch RadioLinkWarningKind = "model_match_mismatch"
+)
+
+// RadioModelSource is the newest parsed EdgeTX model list for one radio
+type RadioModelSource struct {
+	Radio           Radio
+	BackupID        string
+	BackupName      string
+	BackupCreatedAt time.Time
+	Models          []EdgeTXModel
+}
+
+// AircraftRadioBinding links an aircraft to a model slot on one of the user's radios
+type AircraftRadioBinding struct {
+	AircraftID      string                `json:"aircraftId"`
+	AircraftName    string                `json:"aircraftName"`
+	RadioID         string                `json:"radioId"`
+	RadioName       string                `json:"radioName"`
+	BackupID        string                `json:"backupId"`
+	BackupCreatedAt time.Time             `json:"backupCreatedAt"`
+	ModelFileName   string                `json:"modelFileName"`
+	ModelName       string                `json:"modelName"`
+	ModelSlot       int                   `json:"modelSlot"`
+	ReceiverNumber  *int                  `json:"receiverNumber,omitempty"`
+	MatchType       RadioBindingMatchType `json:"matchType"`
+	Description     string                `json:"description"` // e.g. "Bound on radio RadioMaster TX16S, model slot 3"
+}
+
+// RadioLinkWarning reports a model match problem across the user's fleet
+type RadioLinkWarning struct {
+	Kind        RadioLinkWarningKind `json:"kind"`
+	ModelMatch  *int                 `json:"modelMatch,omitempty"`
+	AircraftIDs []string             `json:"aircraftIds"`
+	RadioID     string               `json:"radioId,omitempty"`
+	Message     string               `json:"message"`
+}
+
+// RadioLinksResponse is the fleet-wide set of radio model links
+type RadioLinksResponse struct {
+	Bindings []AircraftRadioBinding `json:"bindings"`
+	Warnings []RadioLinkWarning     `json:"warnings"`
+}
+
+// ForAircraft returns the bindings and warnings that involve one aircraft
+func (r *RadioLinksResponse) ForAircraft(aircraftID string) ([]AircraftRadioBinding, []RadioLinkWarning) {
+	bindings := []AircraftRadioBinding{}
+	for _, b := range r.Bindings {
+		if b.AircraftID == aircraftID {
+			bindings = append(bindings, b)
+		}
+	}
+	warnings := []RadioLinkWarning{}
+	for _, w := range r.Warnings {
+		for _, id := range w.AircraftIDs {
+			if id == aircraftID {
+				warnings = append(warnings, w)
+				break
+			}
+		}
+	}
+	return bindings, warnings
+}
+
+// ModelMatchNumber returns the receiver's model match number. The frontend
+// has used modelMatch, modelMatchNum and modelId over time, so check all three.
+func (e *ReceiverSettingsData) ModelMatchNumber() *int {
+	if e == nil {
+		return nil
+	}
+	for _, v := range []*int{e.ModelMatch, e.ModelMatchNum, e.ModelID} {
+		if v != nil {
+			n := *v
+			return &n
+		}
+	}
+	return nil
+}
+
+// ReceiverModelMatch parses an aircraft's stored receiver settings for its model match number
+func ReceiverModelMatch(settings *AircraftReceiverSettings) *int {
+	if settings == nil || len(settings.Settings) == 0 {
+		return nil
+	}
+	var data ReceiverSettingsData
+	if err := json.Unmarshal(settings.Settings, &data); err != nil {
+		return nil
+	}
+	return data.ModelMatchNumber()
+}
+
+// RadioDisplayName returns "Manufacturer Model" for a radio
+func RadioDisplayName(radio Radio) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", radio.Manufacturer, radio.Model))
+}
+
+// EdgeTXModelSlot returns the model's slot number: the number in its file name
+// (model03.yml is slot 3), or its 1-based position if the name has no number
+func EdgeTXModelSlot(fileName string, index int) int {
+	base := path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	end := len(base)
+	start := end
+	for start > 0 && unicode.IsDigit(rune(base[start-1])) {
+		start--
+	}
+	if start < end {
+		if n, err := strconv.Atoi(base[start:end]); err == nil && n > 0 {
+			return n
+		}
+	}
+	return index + 1
+}
+
+// LinkRadioModels matches each radio's EdgeTX models to the user's aircraft.
+// A model links to an aircraft when its receiver number equals the aircraft's
+// model match, or - when either side has no number - when the names match.
+// Shared model match numbers are reported as collisions.
+func LinkRadioModels(aircraft []Aircraft, sources []RadioModelSource) *RadioLinksResponse {
+	resp := &RadioLinksResponse{
+		Bindings: []AircraftRadioBinding{},
+		Warnings: []RadioLinkWarning{},
+	}
+
+	matches := make(map[string]*int, len(aircraft))
+	byModelMatch := make(map[int][]Aircraft)
+	for _, a := range aircraft {
+		mm := ReceiverModelMatch(a.ReceiverSettings)
+		matches[a.ID] = mm
+		if mm != nil {
+			byModelMatch[*mm] = append(byModelMatch[*mm], a)
+		}
+	}
+
+	for _, source := range sources {
+		radioName := RadioDisplayName(source.Radio)
+		for i, model := range source.Models {
+			slot := EdgeTXModelSlot(model.FileName, i)
+			for _, a := range aircraft {
+				mm := matches[a.ID]
+				nameMatch := aircraftNameMatches(a, model.Name)
+				numberKnown := mm != nil && model.ReceiverNumber != nil
+				numberMatch := numberKnown && *mm == *model.ReceiverNumber
+
+				var matchType RadioBindingMatchType
+				switch {
+				case numberMatch && nameMatch:
+					matchType = RadioBindingMatchModelMatchAndName
+				case numberMatch:
+					matchType = RadioBindingMatchModelMatch
+				case nameMatch && !numberKnown:
+					matchType = RadioBindingMatchName
+				case nameMatch:
+					resp.Warnings = append(resp.Warnings, RadioLinkWarning{
+						Kind:        RadioLinkWarningModelMatchMismatch,
+						ModelMatch:  mm,
+						AircraftIDs: []string{a.ID},
+						RadioID:     source.Radio.ID,
+						Message: fmt.Sprintf("Model %q (slot %d) on %s uses receiver number %d, but %s has model match %d",
+							model.Name, slot, radioName, *model.ReceiverNumber, a.Name, *mm),
+					})
+					continue
+				default:
+					continue
+				}
+
+				resp.Bindings = append(resp.Bindings, AircraftRadioBinding{
+					AircraftID:      a.ID,
+					AircraftName:    a.Name,
+					RadioID:         source.Radio.ID,
+					RadioName:       radioName,
+					BackupID:        source.BackupID,
+					BackupCreatedAt: source.BackupCreatedAt,
+					ModelFileName:   model.FileName,
+					ModelName:       model.Name,
+					ModelSlot:       slot,
+					ReceiverNumber:  model.ReceiverNumber,
+					MatchType:       matchType,
+					Description:     fmt.Sprintf("Bound on radio %s, model slot %d", radioName, slot),
+				})
+			}
+		}
+	}
+
+	numbers := make([]int, 0, len(byModelMatch))
+	for n := range byModelMatch {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	for _, n := range numbers {
+		group := byModelMatch[n]
+		if len(group) < 2 {
+			continue
+		}
+		ids := make([]string, 0, len(group))
+		names := make([]string, 0, len(group))
+		for _, a := range group {
+			ids = append(ids, a.ID)
+			names = append(names, a.Name)
+		}
+		mm := n
+		resp.Warnings = append(resp.Warnings, RadioLinkWarning{
+			Kind:        RadioLinkWarningModelMatchCollision,
+			ModelMatch:  &mm,
+			AircraftIDs: ids,
+			Message:     fmt.Sprintf("Model match %d is shared by %s; a radio model using it will control all of them", n, strings.Join(names, ", ")),
+		})
+	}
+
+	return resp
+}
+
+// aircraftNameMatches compares an EdgeTX model name with an aircraft's name and
+// nickname, ignoring case, spaces and punctuation (EdgeTX names are short and plain)
+func aircraftNameMatches(a Aircraft, modelName string) bool {
+	model := normalizeRadioName(modelName)
+	if model == "" {
+		return false
+	}
+	return model == normalizeRadioName(a.Name) || model == normalizeRadioName(a.Nickname)
+}
+
+func normalizeRadioName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testAircraftWithModelMatch(id, name string, settings string) Aircraft {
+	a := Aircraft{ID: id, Name: name}
+	if settings != "" {
+		a.ReceiverSettings = &AircraftReceiverSettings{AircraftID: id, Settings: json.RawMessage(settings)}
+	}
+	return a
+}
+
+func TestLinkRadioModels(t *testing.T) {
+	rx := func(n int) *int { return &n }
+	aircraft := []Aircraft{
+		testAircraftWithModelMatch("a1", "Nazgul 5", `{"modelMatch": 3}`),
+		testAircraftWithModelMatch("a2", "Cinewhoop", `{"modelMatchNum": 3}`),
+		testAircraftWithModelMatch("a3", "Wing", ""),
+		testAircraftWithModelMatch("a4", "Long Range", `{"modelId": 9}`),
+	}
+	sources := []RadioModelSource{{
+		Radio:    Radio{ID: "r1", Manufacturer: ManufacturerRadioMaster, Model: "TX16S Mark II"},
+		BackupID: "b1",
+		Models: []EdgeTXModel{
+			{FileName: "MODELS/model03.yml", Name: "NAZGUL5", ReceiverNumber: rx(3)},
+			{FileName: "MODELS/model07.yml", Name: "wing"},
+			{FileName: "MODELS/model08.yml", Name: "LongRange", ReceiverNumber: rx(4)},
+		},
+	}}
+
+	links := LinkRadioModels(aircraft, sources)
+
+	if len(links.Bindings) != 3 {
+		t.Fatalf("Bindings = %+v, want 3", links.Bindings)
+	}
+	nazgul, whoop, wing := links.Bindings[0], links.Bindings[1], links.Bindings[2]
+	if nazgul.AircraftID != "a1" || nazgul.MatchType != RadioBindingMatchModelMatchAndName || nazgul.ModelSlot != 3 {
+		t.Errorf("Bindings[0] = %+v, want a1 matched by model match and name in slot 3", nazgul)
+	}
+	if nazgul.Description != "Bound on radio RadioMaster TX16S Mark II, model slot 3" {
+		t.Errorf("Description = %q", nazgul.Description)
+	}
+	if whoop.AircraftID != "a2" || whoop.MatchType != RadioBindingMatchModelMatch {
+		t.Errorf("Bindings[1] = %+v, want a2 matched by shared model match", whoop)
+	}
+	if wing.AircraftID != "a3" || wing.MatchType != RadioBindingMatchName || wing.ModelSlot != 7 {
+		t.Errorf("Bindings[2] = %+v, want a3 matched by name in slot 7", wing)
+	}
+
+	if len(links.Warnings) != 2 {
+		t.Fatalf("Warnings = %+v, want mismatch and collision", links.Warnings)
+	}
+	if w := links.Warnings[0]; w.Kind != RadioLinkWarningModelMatchMismatch || w.AircraftIDs[0] != "a4" {
+		t.Errorf("Warnings[0] = %+v, want model match mismatch for a4", w)
+	}
+	collision := links.Warnings[1]
+	if collision.Kind != RadioLinkWarningModelMatchCollision || *collision.ModelMatch != 3 || len(collision.AircraftIDs) != 2 {
+		t.Errorf("Warnings[1] = %+v, want collision on model match 3", collision)
+	}
+	if !strings.Contains(collision.Message, "Nazgul 5, Cinewhoop") {
+		t.Errorf("collision message = %q, want aircraft names", collision.Message)
+	}
+
+	bindings, warnings := links.ForAircraft("a2")
+	if len(bindings) != 1 || len(warnings) != 1 || warnings[0].Kind != RadioLinkWarningModelMatchCollision {
+		t.Errorf("ForAircraft(a2) = %+v, %+v, want one binding and the collision", bindings, warnings)
+	}
+}
+
+func TestEdgeTXModelSlot(t *testing.T) {
+	tests := []struct {
+		fileName string
+		index    int
+		want     int
+	}{
+		{"MODELS/model01.yml", 5, 1},
+		{"MODELS\\model12.yaml", 0, 12},
+		{"quad.yml", 2, 3},
+		{"model00.yml", 0, 1},
+	}
+	for _, tt := range tests {
+		if got := EdgeTXModelSlot(tt.fileName, tt.index); got != tt.want {
+			t.Errorf("EdgeTXModelSlot(%q, %d) = %d, want %d", tt.fileName, tt.index, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package models
+
+import "time"
+
+// DataExportStatus is where an account data export job is in its lifecycle
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportRunning DataExportStatus = "running"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+	DataExportExpired DataExportStatus = "expired" // Archive deleted after ExpiresAt
+)
+
+// DataExport is an asynchronous job that assembles a user's data into a ZIP archive
+type DataExport struct {
+	ID          string           `json:"id"`
+	UserID      string           `json:"-"`
+	Status      DataExportStatus `json:"status"`
+	StorageKey  string           `json:"-"`
+	FileSize    int64            `json:"fileSize,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	DownloadURL string           `json:"downloadUrl,omitempty"` // Set once the archive is ready
+	ExpiresAt   *time.Time       `json:"expiresAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	StartedAt   *time.Time       `json:"startedAt,omitempty"`
+	CompletedAt *time.Time       `json:"completedAt,omitempty"`
+}
+
+// DataExportDownloadURL is a short-lived direct download link for an export archive
+type DataExportDownloadURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// TakeoutFormat identifies a FlyingForge export archive
+const TakeoutFormat = "flyingforge-takeout"
+
+// TakeoutFormatVersion is bumped whenever the archive layout changes incompatibly
+const TakeoutFormatVersion = 1
+
+// TakeoutManifest is manifest.json at the root of an export archive. It
+// lists every other file in the archive and the record each one belongs to.
+type TakeoutManifest struct {
+	Format    string         `json:"format"`
+	Version   int            `json:"version"`
+	ExportID  string         `json:"exportId"`
+	UserID    string         `json:"userId"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Counts    map[string]int `json:"counts"`
+	Files     []TakeoutFile  `json:"files"`
+	Warnings  []string       `json:"warnings,omitempty"` // Records whose files could not be read
+}
+
+// TakeoutFileKind describes what a file in an export archive holds
+type TakeoutFileKind string
+
+const (
+	TakeoutFileProfile           TakeoutFileKind = "profile"
+	TakeoutFileInventory         TakeoutFileKind = "inventory"
+	TakeoutFileAircraft          TakeoutFileKind = "aircraft"
+	TakeoutFileMaintenanceEvents TakeoutFileKind = "maintenance_events"
+	TakeoutFileFCConfigs         TakeoutFileKind = "fc_configs"
+	TakeoutFileFCConfigDump      TakeoutFileKind = "fc_config_dump"
+	TakeoutFileTuningSnapshots   TakeoutFileKind = "tuning_snapshots"
+	TakeoutFileBatteries         TakeoutFileKind = "batteries"
+	TakeoutFileBatteryLogs       TakeoutFileKind = "battery_logs"
+	TakeoutFileFlightSessions    TakeoutFileKind = "flight_sessions"
+	TakeoutFileOrders            TakeoutFileKind = "orders"
+	TakeoutFileRadios            TakeoutFileKind = "radios"
+	TakeoutFileRadioBackup       TakeoutFileKind = "radio_backup"
+	TakeoutFileBuilds            TakeoutFileKind = "builds"
+	TakeoutFileCatalog           TakeoutFileKind = "catalog"
+	TakeoutFileImage             TakeoutFileKind = "image"
+)
+
+// TakeoutFile is one manifest entry. Binary files name the record they
+// belong to so an importer can re-link them.
+type TakeoutFile struct {
+	Path        string                `json:"path"`
+	Kind        TakeoutFileKind       `json:"kind"`
+	Size        int64                 `json:"size"`
+	SHA256      string                `json:"sha256"`
+	ContentType string                `json:"contentType,omitempty"`
+	RecordID    string                `json:"recordId,omitempty"`   // FC config, radio backup or image ID
+	ParentID    string                `json:"parentId,omitempty"`   // Radio ID for backups
+	EntityType  ImageEntityType       `json:"entityType,omitempty"` // Images only
+	EntityID    string                `json:"entityId,omitempty"`   // Images only
+	ImageStatus ImageModerationStatus `json:"imageStatus,omitempty"`
+	Cover       bool                  `json:"cover,omitempty"`    // Aircraft or build cover image
+	Caption     string                `json:"caption,omitempty"`  // Gallery caption
+	Position    int                   `json:"position,omitempty"` // Gallery position
+}
+
+// TakeoutRadio is a radio with its backups in radios.json
+type TakeoutRadio struct {
+	Radio   Radio         `json:"radio"`
+	Backups []RadioBackup `json:"backups"`
+}
+
+// DataImportStatus is where an account data import job is in its lifecycle
+type DataImportStatus string
+
+const (
+	DataImportPending   DataImportStatus = "pending"
+	DataImportRunning   DataImportStatus = "running"
+	DataImportCompleted DataImportStatus = "completed"
+	DataImportFailed    DataImportStatus = "failed"
+)
+
+// DataImport is an asynchronous job that restores an export archive into a user's account
+type DataImport struct {
+	ID             string            `json:"id"`
+	UserID         string            `json:"-"`
+	SourceExportID string            `json:"sourceExportId,omitempty"` // Manifest export ID of the uploaded archive
+	Status         DataImportStatus  `json:"status"`
+	Error          string            `json:"error,omitempty"`
+	Result         *DataImportResult `json:"result,omitempty"` // Set once the import completes
+	CreatedAt      time.Time         `json:"createdAt"`
+	StartedAt      *time.Time        `json:"startedAt,omitempty"`
+	CompletedAt    *time.Time        `json:"completedAt,omitempty"`
+}
+
+// DataImportResult summarizes what an import restored. Records that could
+// not be restored are skipped and described in Warnings.
+type DataImportResult struct {
+	SourceExportID string         `json:"sourceExportId"`
+	SourceUserID   string         `json:"sourceUserId"`
+	Imported       map[string]int `json:"imported"`
+	CatalogCreated int            `json:"catalogCreated"` // Pending catalog entries created for unmatched gear
+	ImagesQueued   int            `json:"imagesQueued"`   // Images held back for moderation review
+	ImagesRejected int            `json:"imagesRejected"`
+	Warnings       []string       `json:"warnings,omitempty"`
+}
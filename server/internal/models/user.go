@@ -23,6 +23,8 @@ type AuthProvider string
 const (
 	AuthProviderGoogle   AuthProvider = "google"
 	AuthProviderMCPOAuth AuthProvider = "mcp_oauth"
+	AuthProviderEmail    AuthProvider = "email"   // Subject is the normalized email address
+	AuthProviderPasskey  AuthProvider = "passkey" // Subject is the base64url WebAuthn credential ID
 )
 
 // AvatarType represents which avatar to use
@@ -108,7 +110,7 @@ func (u *User) EffectiveDisplayName() string {
 	return u.Email
 }
 
-// UserIdentity represents a linked identity provider (Google, etc.)
+// UserIdentity represents a linked identity provider (Google, email, passkey, etc.)
 type UserIdentity struct {
 	ID              string       `json:"id"`
 	UserID          string       `json:"userId"`
@@ -142,6 +144,27 @@ type GoogleLoginParams struct {
 	RedirectURI string `json:"redirectUri,omitempty"`
 }
 
+// EmailLoginParams requests a sign-in link by email
+type EmailLoginParams struct {
+	Email string `json:"email"`
+}
+
+// EmailLoginVerifyParams completes an email sign-in with the token from the link
+type EmailLoginVerifyParams struct {
+	Token string `json:"token"`
+}
+
+// EmailLoginToken is a single-use, expiring sign-in link. Only a hash of the
+// token is stored.
+type EmailLoginToken struct {
+	ID         string     `json:"id"`
+	Email      string     `json:"email"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ConsumedAt *time.Time `json:"consumedAt,omitempty"`
+}
+
 // GoogleClaims represents the claims from a Google ID token
 type GoogleClaims struct {
 	Subject       string `json:"sub"`
@@ -403,3 +426,36 @@ type RefreshToken struct {
 	CreatedAt time.Time  `json:"createdAt"`
 	RevokedAt *time.Time `json:"revokedAt,omitempty"`
 }
+
+// PersonalAccessTokenPrefix starts every personal access token so the auth
+// middleware can tell them apart from JWTs
+const PersonalAccessTokenPrefix = "ffpat_"
+
+// PersonalAccessToken is a named, scoped API token a user creates for
+// scripts. Like refresh tokens, only a hash of the secret is stored.
+type PersonalAccessToken struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"userId"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"tokenPrefix"` // First characters of the token, for recognising it in lists
+	TokenHash   string     `json:"-"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreatePersonalAccessTokenParams is the request to create a personal access token
+type CreatePersonalAccessTokenParams struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays,omitempty"`
+}
+
+// PersonalAccessTokenCreateResponse carries the new token's secret, which is
+// only ever returned here
+type PersonalAccessTokenCreateResponse struct {
+	Token       string               `json:"token"`
+	AccessToken *PersonalAccessToken `json:"accessToken"`
+}
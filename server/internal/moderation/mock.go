@@ -27,3 +27,22 @@ func (m *MockModerator) ModerateImageBytes(ctx context.Context, imageBytes []byt
 		Reason: "Approved",
 	}, nil
 }
+
+// FakeDetector is a local stand-in for Rekognition. It returns the configured
+// labels, or Err, and records how many images it was asked to scan.
+type FakeDetector struct {
+	Labels []models.ModerationLabel
+	Err    error
+	Calls  int
+}
+
+// DetectModerationLabels returns the configured labels/error.
+func (f *FakeDetector) DetectModerationLabels(ctx context.Context, imageBytes []byte) ([]models.ModerationLabel, error) {
+	_ = ctx
+	_ = imageBytes
+	f.Calls++
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Labels, nil
+}
@@ -11,24 +11,38 @@ type Detector interface {
 	DetectModerationLabels(ctx context.Context, imageBytes []byte) ([]models.ModerationLabel, error)
 }
 
-// Service evaluates moderation labels into APPROVED/REJECTED decisions.
+// Service evaluates moderation labels into APPROVED/REJECTED/PENDING_REVIEW decisions.
 type Service struct {
 	detector         Detector
 	rejectConfidence float64
+	reviewConfidence float64
 }
 
 // NewService creates a moderation service using the configured detector.
+// Images are either approved or rejected; nothing is sent for human review.
 func NewService(detector Detector, rejectConfidence float64) *Service {
+	return NewServiceWithReview(detector, rejectConfidence, 0)
+}
+
+// NewServiceWithReview creates a moderation service with a review band: images
+// whose strongest label is at least reviewConfidence but below rejectConfidence
+// are held for human review. A band that is empty or out of range is disabled.
+func NewServiceWithReview(detector Detector, rejectConfidence float64, reviewConfidence float64) *Service {
 	if rejectConfidence <= 0 {
 		rejectConfidence = 70
 	}
+	if reviewConfidence <= 0 || reviewConfidence >= rejectConfidence {
+		reviewConfidence = 0
+	}
 	return &Service{
 		detector:         detector,
 		rejectConfidence: rejectConfidence,
+		reviewConfidence: reviewConfidence,
 	}
 }
 
-// ModerateImageBytes moderates image bytes and returns an APPROVED/REJECTED decision.
+// ModerateImageBytes moderates image bytes and returns an APPROVED, REJECTED or
+// PENDING_REVIEW decision.
 func (s *Service) ModerateImageBytes(ctx context.Context, imageBytes []byte) (*models.ModerationDecision, error) {
 	labels, err := s.detector.DetectModerationLabels(ctx, imageBytes)
 	if err != nil {
@@ -53,9 +67,13 @@ func (s *Service) ModerateImageBytes(ctx context.Context, imageBytes []byte) (*m
 	}
 	decision.MaxConfidence = maxConfidence
 
-	if shouldReject {
+	switch {
+	case shouldReject:
 		decision.Status = models.ImageModerationRejected
 		decision.Reason = "Not allowed"
+	case s.reviewConfidence > 0 && maxConfidence >= s.reviewConfidence:
+		decision.Status = models.ImageModerationPendingReview
+		decision.Reason = "Needs review"
 	}
 
 	return decision, nil
@@ -91,3 +91,40 @@ func TestServiceModerateImageBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceModerateImageBytesReviewBand(t *testing.T) {
+	tests := []struct {
+		name       string
+		confidence float64
+		wantStatus models.ImageModerationStatus
+	}{
+		{name: "below band is approved", confidence: 39.9, wantStatus: models.ImageModerationApproved},
+		{name: "inside band needs review", confidence: 55, wantStatus: models.ImageModerationPendingReview},
+		{name: "at reject threshold is rejected", confidence: 70, wantStatus: models.ImageModerationRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := &FakeDetector{Labels: []models.ModerationLabel{{Name: "Suggestive", Confidence: tt.confidence}}}
+			svc := NewServiceWithReview(detector, 70, 40)
+
+			decision, err := svc.ModerateImageBytes(context.Background(), []byte("abc"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision.Status != tt.wantStatus {
+				t.Fatalf("status=%s want=%s", decision.Status, tt.wantStatus)
+			}
+			if detector.Calls != 1 {
+				t.Fatalf("detector calls=%d want=1", detector.Calls)
+			}
+		})
+	}
+
+	// A band at or above the reject threshold is ignored
+	svc := NewServiceWithReview(&FakeDetector{Labels: []models.ModerationLabel{{Confidence: 75}}}, 70, 80)
+	decision, err := svc.ModerateImageBytes(context.Background(), []byte("abc"))
+	if err != nil || decision.Status != models.ImageModerationRejected {
+		t.Fatalf("decision=%+v err=%v", decision, err)
+	}
+}
@@ -0,0 +1,355 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// ServiceError represents a service-level error
+type ServiceError struct {
+	Message string
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+const (
+	maxTrackingNumberLength = 100
+	maxLabelLength          = 255
+	maxOrderItems           = 100
+)
+
+// Store defines the interface for order storage operations
+type Store interface {
+	Create(ctx context.Context, userID string, params models.CreateOrderParams) (*models.Order, error)
+	Get(ctx context.Context, id, userID string) (*models.Order, error)
+	List(ctx context.Context, userID string, params models.OrderListParams) (*models.OrderListResponse, error)
+	Update(ctx context.Context, userID string, params models.UpdateOrderParams) (*models.Order, error)
+	Delete(ctx context.Context, id, userID string) error
+	SetItems(ctx context.Context, orderID, userID string, itemIDs []string) (*models.Order, error)
+	ListDueForCheck(ctx context.Context, carriers []string, checkedBefore time.Time, limit int) ([]models.Order, error)
+	SaveCarrierStatus(ctx context.Context, order *models.Order) (bool, error)
+	MarkChecked(ctx context.Context, orderID string) error
+	MarkItemsReceived(ctx context.Context, orderID string, receivedAt time.Time) (int, error)
+}
+
+// Service handles orders and shipment tracking
+type Service struct {
+	store    Store
+	trackers map[models.OrderCarrier]Tracker
+	logger   *logging.Logger
+	now      func() time.Time
+}
+
+// NewService creates a new order service. Carriers without a registered
+// tracker are only updated by hand.
+func NewService(store *database.OrderStore, logger *logging.Logger) *Service {
+	return &Service{
+		store:    store,
+		trackers: make(map[models.OrderCarrier]Tracker),
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// RegisterTracker sets the adapter used to poll a carrier
+func (s *Service) RegisterTracker(carrier models.OrderCarrier, tracker Tracker) {
+	s.trackers[carrier] = tracker
+}
+
+// Create records an order, linking it to the inventory items it contains
+func (s *Service) Create(ctx context.Context, userID string, params models.CreateOrderParams) (*models.Order, error) {
+	carrier, ok := models.NormalizeOrderCarrier(params.Carrier)
+	if !ok {
+		return nil, &ServiceError{Message: fmt.Sprintf("unsupported carrier %q", params.Carrier)}
+	}
+	params.Carrier = carrier
+
+	params.TrackingNumber = strings.TrimSpace(params.TrackingNumber)
+	if params.TrackingNumber == "" {
+		return nil, &ServiceError{Message: "trackingNumber is required"}
+	}
+	if len(params.TrackingNumber) > maxTrackingNumberLength {
+		return nil, &ServiceError{Message: fmt.Sprintf("trackingNumber cannot exceed %d characters", maxTrackingNumberLength)}
+	}
+	params.Label = strings.TrimSpace(params.Label)
+	if len(params.Label) > maxLabelLength {
+		return nil, &ServiceError{Message: fmt.Sprintf("label cannot exceed %d characters", maxLabelLength)}
+	}
+
+	itemIDs, err := normalizeItemIDs(params.InventoryItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	params.InventoryItemIDs = itemIDs
+
+	order, err := s.store.Create(ctx, userID, params)
+	if errors.Is(err, database.ErrOrderItemNotFound) {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	if err != nil {
+		s.logger.Error("Failed to create order", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+
+	s.logger.Info("Created order", logging.WithFields(map[string]interface{}{
+		"id":      order.ID,
+		"carrier": order.Carrier,
+		"items":   len(order.Items),
+	}))
+	return order, nil
+}
+
+// Get retrieves an order
+func (s *Service) Get(ctx context.Context, id string, userID string) (*models.Order, error) {
+	return s.store.Get(ctx, id, userID)
+}
+
+// List lists a user's orders
+func (s *Service) List(ctx context.Context, userID string, params models.OrderListParams) (*models.OrderListResponse, error) {
+	if params.Status != "" {
+		status, ok := models.NormalizeOrderStatus(string(params.Status))
+		if !ok {
+			return nil, &ServiceError{Message: fmt.Sprintf("unknown status %q", params.Status)}
+		}
+		params.Status = status
+	}
+	if params.Limit <= 0 || params.Limit > 200 {
+		params.Limit = 50
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+	return s.store.List(ctx, userID, params)
+}
+
+// Update edits an order. Marking it delivered by hand receives its items just
+// like a carrier delivery does.
+func (s *Service) Update(ctx context.Context, userID string, params models.UpdateOrderParams) (*models.Order, error) {
+	params.ID = strings.TrimSpace(params.ID)
+	if params.ID == "" {
+		return nil, &ServiceError{Message: "id is required"}
+	}
+	if params.Carrier != nil {
+		carrier, ok := models.NormalizeOrderCarrier(*params.Carrier)
+		if !ok {
+			return nil, &ServiceError{Message: fmt.Sprintf("unsupported carrier %q", *params.Carrier)}
+		}
+		params.Carrier = &carrier
+	}
+	if params.TrackingNumber != nil {
+		trackingNumber := strings.TrimSpace(*params.TrackingNumber)
+		if trackingNumber == "" {
+			return nil, &ServiceError{Message: "trackingNumber cannot be empty"}
+		}
+		if len(trackingNumber) > maxTrackingNumberLength {
+			return nil, &ServiceError{Message: fmt.Sprintf("trackingNumber cannot exceed %d characters", maxTrackingNumberLength)}
+		}
+		params.TrackingNumber = &trackingNumber
+	}
+	if params.Label != nil {
+		label := strings.TrimSpace(*params.Label)
+		if len(label) > maxLabelLength {
+			return nil, &ServiceError{Message: fmt.Sprintf("label cannot exceed %d characters", maxLabelLength)}
+		}
+		params.Label = &label
+	}
+	if params.Status != nil {
+		status, ok := models.NormalizeOrderStatus(string(*params.Status))
+		if !ok {
+			return nil, &ServiceError{Message: fmt.Sprintf("unknown status %q", *params.Status)}
+		}
+		params.Status = &status
+	}
+
+	order, err := s.store.Update(ctx, userID, params)
+	if errors.Is(err, database.ErrOrderNotFound) {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	if err != nil {
+		s.logger.Error("Failed to update order", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+
+	if order.Status == models.OrderStatusDelivered {
+		s.receiveItems(ctx, order)
+	}
+	return order, nil
+}
+
+// Delete removes an order. Its items stay in the inventory.
+func (s *Service) Delete(ctx context.Context, id string, userID string) error {
+	if id == "" {
+		return &ServiceError{Message: "id is required"}
+	}
+	err := s.store.Delete(ctx, id, userID)
+	if errors.Is(err, database.ErrOrderNotFound) {
+		return &ServiceError{Message: err.Error()}
+	}
+	if err != nil {
+		s.logger.Error("Failed to delete order", logging.WithField("error", err.Error()))
+		return err
+	}
+	s.logger.Info("Deleted order", logging.WithField("id", id))
+	return nil
+}
+
+// SetItems replaces the inventory items an order contains
+func (s *Service) SetItems(ctx context.Context, orderID string, userID string, itemIDs []string) (*models.Order, error) {
+	if orderID == "" {
+		return nil, &ServiceError{Message: "id is required"}
+	}
+	itemIDs, err := normalizeItemIDs(itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.store.SetItems(ctx, orderID, userID, itemIDs)
+	if errors.Is(err, database.ErrOrderNotFound) || errors.Is(err, database.ErrOrderItemNotFound) {
+		return nil, &ServiceError{Message: err.Error()}
+	}
+	if err != nil {
+		s.logger.Error("Failed to set order items", logging.WithField("error", err.Error()))
+		return nil, err
+	}
+	return order, nil
+}
+
+// Refresh checks an order's carrier status now instead of waiting for the
+// polling job
+func (s *Service) Refresh(ctx context.Context, id string, userID string) (*models.Order, error) {
+	order, err := s.store.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, &ServiceError{Message: "order not found"}
+	}
+	if order.Status.IsFinal() {
+		return order, nil
+	}
+
+	tracker := s.trackers[order.Carrier]
+	if tracker == nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("tracking is not available for carrier %q", order.Carrier)}
+	}
+	if err := s.check(ctx, order, tracker); errors.Is(err, ErrTrackingNotFound) {
+		return nil, &ServiceError{Message: "the carrier has no record of this tracking number yet"}
+	} else if err != nil {
+		return nil, err
+	}
+	return s.store.Get(ctx, id, userID)
+}
+
+// PollDue checks up to limit open orders that haven't been checked within
+// recheckAfter and returns how many were checked. A failing tracking number
+// is logged and skipped until its next turn.
+func (s *Service) PollDue(ctx context.Context, recheckAfter time.Duration, limit int) (int, error) {
+	if len(s.trackers) == 0 {
+		return 0, nil
+	}
+	carriers := make([]string, 0, len(s.trackers))
+	for carrier := range s.trackers {
+		carriers = append(carriers, string(carrier))
+	}
+	sort.Strings(carriers)
+
+	due, err := s.store.ListDueForCheck(ctx, carriers, s.now().Add(-recheckAfter), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	checked := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		order := &due[i]
+		if err := s.check(ctx, order, s.trackers[order.Carrier]); err != nil {
+			s.logger.Warn("Failed to check order status", logging.WithFields(map[string]interface{}{
+				"id":      order.ID,
+				"carrier": order.Carrier,
+				"error":   err.Error(),
+			}))
+			continue
+		}
+		checked++
+	}
+	return checked, nil
+}
+
+// check asks the carrier for the order's status, saves it, and receives the
+// order's items if it was just delivered
+func (s *Service) check(ctx context.Context, order *models.Order, tracker Tracker) error {
+	status, err := tracker.Track(ctx, order.TrackingNumber)
+	if err != nil {
+		if markErr := s.store.MarkChecked(ctx, order.ID); markErr != nil {
+			s.logger.Warn("Failed to mark order checked", logging.WithFields(map[string]interface{}{
+				"id":    order.ID,
+				"error": markErr.Error(),
+			}))
+		}
+		return err
+	}
+
+	delivered := order.ApplyCarrierStatus(*status, s.now())
+	saved, err := s.store.SaveCarrierStatus(ctx, order)
+	if err != nil {
+		return err
+	}
+	if saved && delivered {
+		s.logger.Info("Order delivered", logging.WithField("id", order.ID))
+		s.receiveItems(ctx, order)
+	}
+	return nil
+}
+
+// receiveItems marks a delivered order's items received. Failures are logged;
+// the next delivery update or manual save retries them.
+func (s *Service) receiveItems(ctx context.Context, order *models.Order) {
+	receivedAt := s.now()
+	if order.DeliveredAt != nil {
+		receivedAt = *order.DeliveredAt
+	}
+	received, err := s.store.MarkItemsReceived(ctx, order.ID, receivedAt)
+	if err != nil {
+		s.logger.Error("Failed to mark order items received", logging.WithFields(map[string]interface{}{
+			"id":    order.ID,
+			"error": err.Error(),
+		}))
+		return
+	}
+	if received > 0 {
+		s.logger.Info("Received order items", logging.WithFields(map[string]interface{}{
+			"id":    order.ID,
+			"items": received,
+		}))
+	}
+}
+
+// normalizeItemIDs trims and de-duplicates inventory item IDs, keeping order
+func normalizeItemIDs(itemIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(itemIDs))
+	normalized := make([]string, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		normalized = append(normalized, id)
+	}
+	if len(normalized) > maxOrderItems {
+		return nil, &ServiceError{Message: fmt.Sprintf("an order cannot contain more than %d items", maxOrderItems)}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,290 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// mockStore implements the Store interface for testing
+type mockStore struct {
+	orders   map[string]*models.Order
+	created  *models.CreateOrderParams
+	carriers []string
+	checked  []string
+	received map[string]time.Time
+}
+
+func newMockStore(orders ...models.Order) *mockStore {
+	m := &mockStore{orders: make(map[string]*models.Order), received: make(map[string]time.Time)}
+	for i := range orders {
+		m.orders[orders[i].ID] = &orders[i]
+	}
+	return m
+}
+
+func (m *mockStore) Create(ctx context.Context, userID string, params models.CreateOrderParams) (*models.Order, error) {
+	m.created = &params
+	order := &models.Order{
+		ID:             "order-1",
+		UserID:         userID,
+		Carrier:        params.Carrier,
+		TrackingNumber: params.TrackingNumber,
+		Label:          params.Label,
+		Status:         models.OrderStatusUnknown,
+	}
+	m.orders[order.ID] = order
+	return order, nil
+}
+
+func (m *mockStore) Get(ctx context.Context, id, userID string) (*models.Order, error) {
+	order := m.orders[id]
+	if order == nil {
+		return nil, nil
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (m *mockStore) List(ctx context.Context, userID string, params models.OrderListParams) (*models.OrderListResponse, error) {
+	return &models.OrderListResponse{}, nil
+}
+
+func (m *mockStore) Update(ctx context.Context, userID string, params models.UpdateOrderParams) (*models.Order, error) {
+	order := m.orders[params.ID]
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+	if params.Status != nil {
+		order.Status = *params.Status
+		if order.Status == models.OrderStatusDelivered && order.DeliveredAt == nil {
+			now := time.Now()
+			order.DeliveredAt = &now
+		}
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (m *mockStore) Delete(ctx context.Context, id, userID string) error {
+	delete(m.orders, id)
+	return nil
+}
+
+func (m *mockStore) SetItems(ctx context.Context, orderID, userID string, itemIDs []string) (*models.Order, error) {
+	return m.Get(ctx, orderID, userID)
+}
+
+func (m *mockStore) ListDueForCheck(ctx context.Context, carriers []string, checkedBefore time.Time, limit int) ([]models.Order, error) {
+	m.carriers = carriers
+	due := make([]models.Order, 0)
+	for _, order := range m.orders {
+		if !order.Status.IsFinal() {
+			due = append(due, *order)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockStore) SaveCarrierStatus(ctx context.Context, order *models.Order) (bool, error) {
+	stored := m.orders[order.ID]
+	if stored.Status.IsFinal() {
+		return false, nil
+	}
+	*stored = *order
+	return true, nil
+}
+
+func (m *mockStore) MarkChecked(ctx context.Context, orderID string) error {
+	m.checked = append(m.checked, orderID)
+	return nil
+}
+
+func (m *mockStore) MarkItemsReceived(ctx context.Context, orderID string, receivedAt time.Time) (int, error) {
+	m.received[orderID] = receivedAt
+	return 1, nil
+}
+
+// fakeTracker returns a fixed status for every tracking number
+type fakeTracker struct {
+	status *models.CarrierStatus
+	err    error
+}
+
+func (f *fakeTracker) Track(ctx context.Context, trackingNumber string) (*models.CarrierStatus, error) {
+	return f.status, f.err
+}
+
+func newTestService(store Store) *Service {
+	return &Service{
+		store:    store,
+		trackers: make(map[models.OrderCarrier]Tracker),
+		logger:   testutil.NullLogger(),
+		now:      time.Now,
+	}
+}
+
+func TestCreate_NormalizesParams(t *testing.T) {
+	store := newMockStore()
+	svc := newTestService(store)
+
+	_, err := svc.Create(context.Background(), "user-1", models.CreateOrderParams{
+		Carrier:          " UPS ",
+		TrackingNumber:   " 1Z999 ",
+		InventoryItemIDs: []string{"item-1", " item-1 ", "", "item-2"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if store.created.Carrier != models.CarrierUPS || store.created.TrackingNumber != "1Z999" {
+		t.Errorf("carrier/tracking = %q/%q, want ups/1Z999", store.created.Carrier, store.created.TrackingNumber)
+	}
+	if len(store.created.InventoryItemIDs) != 2 {
+		t.Errorf("InventoryItemIDs = %v, want de-duplicated", store.created.InventoryItemIDs)
+	}
+}
+
+func TestCreate_Validation(t *testing.T) {
+	svc := newTestService(newMockStore())
+	tests := []models.CreateOrderParams{
+		{TrackingNumber: ""},
+		{Carrier: "pigeon", TrackingNumber: "123"},
+	}
+	for _, params := range tests {
+		_, err := svc.Create(context.Background(), "user-1", params)
+		var svcErr *ServiceError
+		if !errors.As(err, &svcErr) {
+			t.Errorf("Create(%+v) error = %v, want ServiceError", params, err)
+		}
+	}
+}
+
+func TestPollDue_DeliveryReceivesItems(t *testing.T) {
+	deliveredAt := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	store := newMockStore(models.Order{ID: "order-1", Carrier: models.CarrierUPS, TrackingNumber: "1Z", Status: models.OrderStatusInTransit})
+	svc := newTestService(store)
+	svc.RegisterTracker(models.CarrierUPS, &fakeTracker{status: &models.CarrierStatus{
+		Status:      models.OrderStatusDelivered,
+		Details:     "Left at front door",
+		DeliveredAt: &deliveredAt,
+	}})
+
+	checked, err := svc.PollDue(context.Background(), time.Hour, 10)
+	if err != nil {
+		t.Fatalf("PollDue() error = %v", err)
+	}
+	if checked != 1 {
+		t.Errorf("checked = %d, want 1", checked)
+	}
+	if len(store.carriers) != 1 || store.carriers[0] != "ups" {
+		t.Errorf("carriers = %v, want only registered carriers", store.carriers)
+	}
+	order := store.orders["order-1"]
+	if order.Status != models.OrderStatusDelivered || order.StatusDetails != "Left at front door" {
+		t.Errorf("order = %+v, want delivered with details", order)
+	}
+	if got := store.received["order-1"]; !got.Equal(deliveredAt) {
+		t.Errorf("items received at %v, want %v", got, deliveredAt)
+	}
+}
+
+func TestPollDue_TrackerErrorMarksChecked(t *testing.T) {
+	store := newMockStore(models.Order{ID: "order-1", Carrier: models.CarrierUSPS, TrackingNumber: "9400", Status: models.OrderStatusUnknown})
+	svc := newTestService(store)
+	svc.RegisterTracker(models.CarrierUSPS, &fakeTracker{err: errors.New("timeout")})
+
+	checked, err := svc.PollDue(context.Background(), time.Hour, 10)
+	if err != nil {
+		t.Fatalf("PollDue() error = %v", err)
+	}
+	if checked != 0 {
+		t.Errorf("checked = %d, want 0", checked)
+	}
+	if len(store.checked) != 1 {
+		t.Errorf("MarkChecked calls = %v, want the failing order", store.checked)
+	}
+}
+
+func TestPollDue_NoTrackers(t *testing.T) {
+	store := newMockStore(models.Order{ID: "order-1", Carrier: models.CarrierOther, Status: models.OrderStatusUnknown})
+	svc := newTestService(store)
+
+	checked, err := svc.PollDue(context.Background(), time.Hour, 10)
+	if err != nil || checked != 0 {
+		t.Fatalf("PollDue() = %d, %v, want 0, nil", checked, err)
+	}
+	if store.carriers != nil {
+		t.Error("expected no store lookup without trackers")
+	}
+}
+
+func TestUpdate_ManualDeliveryReceivesItems(t *testing.T) {
+	store := newMockStore(models.Order{ID: "order-1", Carrier: models.CarrierOther, Status: models.OrderStatusInTransit})
+	svc := newTestService(store)
+
+	status := models.OrderStatus("Delivered")
+	order, err := svc.Update(context.Background(), "user-1", models.UpdateOrderParams{ID: "order-1", Status: &status})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if order.Status != models.OrderStatusDelivered {
+		t.Errorf("status = %q, want delivered", order.Status)
+	}
+	if _, ok := store.received["order-1"]; !ok {
+		t.Error("expected items to be received")
+	}
+}
+
+func TestRefresh_UntrackedCarrier(t *testing.T) {
+	store := newMockStore(models.Order{ID: "order-1", Carrier: models.CarrierOther, Status: models.OrderStatusUnknown})
+	svc := newTestService(store)
+
+	_, err := svc.Refresh(context.Background(), "order-1", "user-1")
+	var svcErr *ServiceError
+	if !errors.As(err, &svcErr) {
+		t.Fatalf("Refresh() error = %v, want ServiceError", err)
+	}
+}
+
+func TestPollDue_WithHTTPTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fedex/7712" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "Out For Delivery", "details": "On vehicle", "estimatedDelivery": "2026-03-05T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	store := newMockStore(
+		models.Order{ID: "order-1", Carrier: models.CarrierFedEx, TrackingNumber: "7712", Status: models.OrderStatusInTransit},
+		models.Order{ID: "order-2", Carrier: models.CarrierFedEx, TrackingNumber: "0000", Status: models.OrderStatusInTransit},
+	)
+	svc := newTestService(store)
+	svc.RegisterTracker(models.CarrierFedEx, NewHTTPTracker(server.URL, "", models.CarrierFedEx, time.Second))
+
+	checked, err := svc.PollDue(context.Background(), time.Hour, 10)
+	if err != nil {
+		t.Fatalf("PollDue() error = %v", err)
+	}
+	if checked != 1 {
+		t.Errorf("checked = %d, want 1", checked)
+	}
+	order := store.orders["order-1"]
+	if order.Status != models.OrderStatusOutForDelivery || order.EstimatedDate == nil {
+		t.Errorf("order = %+v, want out_for_delivery with an estimate", order)
+	}
+	if len(store.received) != 0 {
+		t.Error("items should not be received before delivery")
+	}
+	if len(store.checked) != 1 || store.checked[0] != "order-2" {
+		t.Errorf("MarkChecked calls = %v, want the unknown tracking number", store.checked)
+	}
+}
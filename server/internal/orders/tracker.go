@@ -0,0 +1,103 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// Tracker looks up a shipment with one carrier. Adapters are registered per
+// carrier with Service.RegisterTracker.
+type Tracker interface {
+	Track(ctx context.Context, trackingNumber string) (*models.CarrierStatus, error)
+}
+
+// ErrTrackingNotFound is returned when the carrier has no record of a tracking number
+var ErrTrackingNotFound = errors.New("tracking number not found")
+
+// maxTrackingResponseBytes caps how much of a tracking API response is read
+const maxTrackingResponseBytes = 1 << 20
+
+// HTTPTracker tracks one carrier's shipments through a JSON tracking API,
+// such as a tracking aggregator or a self-hosted proxy in front of the
+// carriers' own APIs:
+//
+//	GET {baseURL}/{carrier}/{trackingNumber}
+//	Authorization: Bearer {apiKey}
+//
+//	{"status": "in_transit", "details": "Arrived at facility", "estimatedDelivery": "...", "deliveredAt": "..."}
+//
+// Dates are RFC 3339. Statuses are matched with models.NormalizeOrderStatus;
+// ones it doesn't recognize leave the order's status unchanged.
+type HTTPTracker struct {
+	baseURL string
+	apiKey  string
+	carrier models.OrderCarrier
+	client  *http.Client
+}
+
+type trackingResponse struct {
+	Status            string     `json:"status"`
+	Details           string     `json:"details"`
+	EstimatedDelivery *time.Time `json:"estimatedDelivery"`
+	DeliveredAt       *time.Time `json:"deliveredAt"`
+}
+
+// NewHTTPTracker creates a tracker for one carrier
+func NewHTTPTracker(baseURL string, apiKey string, carrier models.OrderCarrier, timeout time.Duration) *HTTPTracker {
+	return &HTTPTracker{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		carrier: carrier,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Track fetches the shipment's current status
+func (t *HTTPTracker) Track(ctx context.Context, trackingNumber string) (*models.CarrierStatus, error) {
+	endpoint := fmt.Sprintf("%s/%s/%s", t.baseURL, url.PathEscape(string(t.carrier)), url.PathEscape(trackingNumber))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracking request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tracking status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrTrackingNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tracking API returned status %d", resp.StatusCode)
+	}
+
+	var body trackingResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTrackingResponseBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode tracking status: %w", err)
+	}
+
+	status, _ := models.NormalizeOrderStatus(body.Status)
+	return &models.CarrierStatus{
+		Status:        status,
+		Details:       strings.TrimSpace(body.Details),
+		EstimatedDate: body.EstimatedDelivery,
+		DeliveredAt:   body.DeliveredAt,
+	}, nil
+}
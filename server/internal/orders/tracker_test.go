@@ -0,0 +1,73 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+func TestHTTPTracker_Track(t *testing.T) {
+	var gotAuth, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.EscapedPath()
+		w.Write([]byte(`{"status": "delivered", "details": " Front porch ", "deliveredAt": "2026-03-04T15:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	tracker := NewHTTPTracker(server.URL+"/", "secret", models.CarrierUSPS, time.Second)
+	status, err := tracker.Track(context.Background(), "9400 1000")
+	if err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want bearer key", gotAuth)
+	}
+	if gotPath != "/usps/9400%201000" {
+		t.Errorf("path = %q, want escaped tracking number", gotPath)
+	}
+	if status.Status != models.OrderStatusDelivered || status.Details != "Front porch" {
+		t.Errorf("status = %+v, want delivered with trimmed details", status)
+	}
+	if status.DeliveredAt == nil || !status.DeliveredAt.Equal(time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("DeliveredAt = %v", status.DeliveredAt)
+	}
+}
+
+func TestHTTPTracker_UnrecognizedStatusKeepsCurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "customs_clearance", "details": "Held at customs"}`))
+	}))
+	defer server.Close()
+
+	status, err := NewHTTPTracker(server.URL, "", models.CarrierDHL, time.Second).Track(context.Background(), "JD01")
+	if err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if status.Status != "" || status.Details != "Held at customs" {
+		t.Errorf("status = %+v, want empty status with details", status)
+	}
+}
+
+func TestHTTPTracker_Errors(t *testing.T) {
+	code := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+
+	tracker := NewHTTPTracker(server.URL, "", models.CarrierUPS, time.Second)
+	if _, err := tracker.Track(context.Background(), "1Z"); !errors.Is(err, ErrTrackingNotFound) {
+		t.Errorf("404 error = %v, want ErrTrackingNotFound", err)
+	}
+
+	code = http.StatusBadGateway
+	if _, err := tracker.Track(context.Background(), "1Z"); err == nil || errors.Is(err, ErrTrackingNotFound) {
+		t.Errorf("502 error = %v, want a generic error", err)
+	}
+}
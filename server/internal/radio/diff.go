@@ -0,0 +1,357 @@
+package radio
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	// maxEdgeTXFieldChanges caps the field changes reported per model
+	maxEdgeTXFieldChanges = 200
+	// maxManifestEntries caps how many files are hashed from one archive
+	maxManifestEntries = 1000
+	// maxManifestTotalSize caps the uncompressed bytes hashed from one archive
+	maxManifestTotalSize = MaxBackupFileSize * 4
+)
+
+// edgeTXSections maps top-level model YAML keys to the section names shown in diffs
+var edgeTXSections = map[string]string{
+	"header":           "header",
+	"timers":           "timers",
+	"expoData":         "inputs",
+	"inputNames":       "inputs",
+	"mixData":          "mixes",
+	"limitData":        "outputs",
+	"failsafeChannels": "failsafe",
+	"moduleData":       "modules",
+	"logicalSw":        "logicalSwitches",
+	"customFn":         "specialFunctions",
+	"flightModeData":   "flightModes",
+	"curves":           "curves",
+	"points":           "curves",
+	"telemetrySensors": "telemetry",
+}
+
+// edgeTXIgnoredKeys are top-level keys that change without the model changing
+var edgeTXIgnoredKeys = map[string]bool{
+	"semver": true,
+}
+
+// diffEdgeTXModelFiles compares two sets of model files, matching them by file name
+func diffEdgeTXModelFiles(base, target []edgeTXModelFileData) *models.EdgeTXModelsDiff {
+	diff := &models.EdgeTXModelsDiff{
+		Added:   []models.EdgeTXModelRef{},
+		Removed: []models.EdgeTXModelRef{},
+		Changed: []models.EdgeTXModelChange{},
+	}
+
+	baseByKey := indexEdgeTXModelFiles(base)
+	targetByKey := indexEdgeTXModelFiles(target)
+
+	for key, b := range baseByKey {
+		if _, ok := targetByKey[key]; !ok {
+			diff.Removed = append(diff.Removed, edgeTXModelRef(b))
+		}
+	}
+
+	for key, t := range targetByKey {
+		b, ok := baseByKey[key]
+		if !ok {
+			diff.Added = append(diff.Added, edgeTXModelRef(t))
+			continue
+		}
+		if bytes.Equal(b.data, t.data) {
+			diff.UnchangedCount++
+			continue
+		}
+
+		changes := diffEdgeTXModelYAML(b.data, t.data)
+		if len(changes) == 0 {
+			diff.UnchangedCount++
+			continue
+		}
+
+		change := models.EdgeTXModelChange{
+			EdgeTXModelRef: edgeTXModelRef(t),
+			Sections:       changedSections(changes),
+			Changes:        changes,
+		}
+		if len(change.Changes) > maxEdgeTXFieldChanges {
+			change.Changes = change.Changes[:maxEdgeTXFieldChanges]
+			change.Truncated = true
+		}
+		diff.Changed = append(diff.Changed, change)
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].FileName < diff.Added[j].FileName })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].FileName < diff.Removed[j].FileName })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].FileName < diff.Changed[j].FileName })
+	return diff
+}
+
+// indexEdgeTXModelFiles keys model files by lower-cased base name, so a
+// MODELS-only backup can be compared with a full SD card pack
+func indexEdgeTXModelFiles(files []edgeTXModelFileData) map[string]edgeTXModelFileData {
+	index := make(map[string]edgeTXModelFileData, len(files))
+	for _, f := range files {
+		index[strings.ToLower(path.Base(strings.ReplaceAll(f.name, "\\", "/")))] = f
+	}
+	return index
+}
+
+func edgeTXModelRef(f edgeTXModelFileData) models.EdgeTXModelRef {
+	ref := models.EdgeTXModelRef{FileName: path.Base(strings.ReplaceAll(f.name, "\\", "/"))}
+	if model, err := ParseEdgeTXModel(ref.FileName, f.data); err == nil {
+		ref.Name = model.Name
+	}
+	return ref
+}
+
+// diffEdgeTXModelYAML compares two model files value by value
+func diffEdgeTXModelYAML(base, target []byte) []models.EdgeTXFieldChange {
+	before := flattenYAML(base)
+	after := flattenYAML(target)
+
+	paths := make(map[string]bool, len(before)+len(after))
+	for p := range before {
+		paths[p] = true
+	}
+	for p := range after {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	changes := []models.EdgeTXFieldChange{}
+	for _, p := range sorted {
+		if edgeTXIgnoredKeys[topLevelKey(p)] {
+			continue
+		}
+		b, inBase := before[p]
+		a, inTarget := after[p]
+		change := models.EdgeTXFieldChange{Section: edgeTXSection(p), Path: p, Before: b, After: a}
+		switch {
+		case inBase && !inTarget:
+			change.Kind = models.EdgeTXFieldRemoved
+		case !inBase && inTarget:
+			change.Kind = models.EdgeTXFieldAdded
+		case a != b:
+			change.Kind = models.EdgeTXFieldChanged
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// flattenYAML maps dotted paths to scalar values. Unparseable input flattens to
+// a single "raw" entry so a diff still reports that the file changed.
+func flattenYAML(data []byte) map[string]string {
+	out := make(map[string]string)
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		sum := sha256.Sum256(data)
+		out["raw"] = hex.EncodeToString(sum[:])
+		return out
+	}
+	flattenYAMLNode(&root, "", out)
+	return out
+}
+
+func flattenYAMLNode(node *yaml.Node, prefix string, out map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			flattenYAMLNode(child, prefix, out)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			flattenYAMLNode(node.Content[i+1], join(node.Content[i].Value), out)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			flattenYAMLNode(child, join(strconv.Itoa(i)), out)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			flattenYAMLNode(node.Alias, prefix, out)
+		}
+	case yaml.ScalarNode:
+		out[prefix] = node.Value
+	}
+}
+
+func topLevelKey(p string) string {
+	if i := strings.Index(p, "."); i >= 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// edgeTXSection returns the diff section for a flattened path
+func edgeTXSection(p string) string {
+	// Failsafe mode lives under each module but is reported with the failsafe values
+	if strings.HasPrefix(p, "moduleData.") && strings.HasSuffix(p, ".failsafeMode") {
+		return "failsafe"
+	}
+	if section, ok := edgeTXSections[topLevelKey(p)]; ok {
+		return section
+	}
+	return "other"
+}
+
+func changedSections(changes []models.EdgeTXFieldChange) []string {
+	seen := make(map[string]bool)
+	sections := []string{}
+	for _, c := range changes {
+		if !seen[c.Section] {
+			seen[c.Section] = true
+			sections = append(sections, c.Section)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// buildManifest hashes every file in a zip backup, or the backup itself if it is
+// not an archive. The bool result reports whether the backup was an archive.
+// Archives with more than maxManifestEntries files or maxManifestTotalSize
+// uncompressed bytes are rejected.
+func buildManifest(ctx context.Context, fileName string, r io.ReaderAt, size int64) ([]models.BackupManifestEntry, bool, error) {
+	header := make([]byte, 4)
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return nil, false, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if !bytes.HasPrefix(header, []byte("PK\x03\x04")) {
+		hasher := sha256.New()
+		if _, err := io.Copy(hasher, io.NewSectionReader(r, 0, size)); err != nil {
+			return nil, false, fmt.Errorf("failed to hash backup: %w", err)
+		}
+		return []models.BackupManifestEntry{{
+			Path:   path.Base(fileName),
+			Size:   size,
+			SHA256: hex.EncodeToString(hasher.Sum(nil)),
+		}}, false, nil
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	entries := []models.BackupManifestEntry{}
+	var total int64
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, true, err
+		}
+		if len(entries) >= maxManifestEntries {
+			return nil, true, fmt.Errorf("archive has more than %d files", maxManifestEntries)
+		}
+		entry, err := hashZipEntry(f, maxManifestTotalSize-total)
+		if err != nil {
+			return nil, true, err
+		}
+		total += entry.Size
+		entries = append(entries, entry)
+	}
+	return entries, true, nil
+}
+
+// hashZipEntry hashes one archive entry, reading at most limit bytes
+func hashZipEntry(f *zip.File, limit int64) (models.BackupManifestEntry, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return models.BackupManifestEntry{}, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	hasher := sha256.New()
+	n, err := io.Copy(hasher, io.LimitReader(rc, limit+1))
+	if err != nil {
+		return models.BackupManifestEntry{}, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if n > limit {
+		return models.BackupManifestEntry{}, fmt.Errorf("archive exceeds %d uncompressed bytes", int64(maxManifestTotalSize))
+	}
+
+	return models.BackupManifestEntry{
+		Path:   strings.TrimPrefix(strings.ReplaceAll(f.Name, "\\", "/"), "./"),
+		Size:   n,
+		SHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// diffManifests compares two manifests by path and content hash
+func diffManifests(base, target []models.BackupManifestEntry) *models.BackupManifestDiff {
+	diff := &models.BackupManifestDiff{
+		Added:   []models.BackupManifestEntry{},
+		Removed: []models.BackupManifestEntry{},
+		Changed: []models.BackupManifestChange{},
+	}
+
+	baseByPath := make(map[string]models.BackupManifestEntry, len(base))
+	for _, e := range base {
+		baseByPath[e.Path] = e
+	}
+	targetByPath := make(map[string]models.BackupManifestEntry, len(target))
+	for _, e := range target {
+		targetByPath[e.Path] = e
+	}
+
+	for p, b := range baseByPath {
+		if _, ok := targetByPath[p]; !ok {
+			diff.Removed = append(diff.Removed, b)
+		}
+	}
+	for p, t := range targetByPath {
+		b, ok := baseByPath[p]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, t)
+		case b.SHA256 != t.SHA256:
+			diff.Changed = append(diff.Changed, models.BackupManifestChange{
+				Path:       p,
+				BeforeSize: b.Size,
+				AfterSize:  t.Size,
+				BeforeHash: b.SHA256,
+				AfterHash:  t.SHA256,
+			})
+		default:
+			diff.UnchangedCount++
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Path < diff.Added[j].Path })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Path < diff.Removed[j].Path })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Path < diff.Changed[j].Path })
+	return diff
+}
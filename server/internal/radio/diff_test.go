@@ -0,0 +1,199 @@
+package radio
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const testEdgeTXMixModel = `semver: 2.9.4
+header:
+  name: "Nazgul5"
+expoData:
+  0:
+    srcRaw: I0
+    weight: 100
+mixData:
+  0:
+    destCh: 0
+    weight: 100
+  1:
+    destCh: 4
+    srcRaw: SA
+    weight: 100
+limitData:
+  0:
+    min: 0
+    max: 0
+moduleData:
+  0:
+    type: TYPE_CROSSFIRE
+    failsafeMode: NOT_SET
+`
+
+func buildTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readTestModelFiles(t *testing.T, files map[string]string) []edgeTXModelFileData {
+	t.Helper()
+	data := buildTestZip(t, files)
+	modelFiles, _, err := readEdgeTXModelFiles("backup.zip", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("readEdgeTXModelFiles() error = %v", err)
+	}
+	return modelFiles
+}
+
+func TestDiffEdgeTXModelFiles(t *testing.T) {
+	changed := strings.NewReplacer(
+		"semver: 2.9.4", "semver: 2.10.0",
+		"    srcRaw: SA\n    weight: 100", "    srcRaw: SB\n    weight: 50",
+		"    max: 0", "    max: 20",
+		"failsafeMode: NOT_SET", "failsafeMode: NO_PULSES",
+	).Replace(testEdgeTXMixModel)
+
+	base := readTestModelFiles(t, map[string]string{
+		"MODELS/model01.yml": testEdgeTXMixModel,
+		"MODELS/model02.yml": testEdgeTXExternalModel,
+		"MODELS/model03.yml": testEdgeTXModel,
+	})
+	target := readTestModelFiles(t, map[string]string{
+		"SDCARD/MODELS/model01.yml": changed,
+		"SDCARD/MODELS/model03.yml": testEdgeTXModel,
+		"SDCARD/MODELS/model04.yml": testEdgeTXExternalModel,
+	})
+
+	diff := diffEdgeTXModelFiles(base, target)
+
+	if len(diff.Added) != 1 || diff.Added[0].FileName != "model04.yml" || diff.Added[0].Name != "Wing" {
+		t.Errorf("Added = %+v, want model04.yml (Wing)", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].FileName != "model02.yml" {
+		t.Errorf("Removed = %+v, want model02.yml", diff.Removed)
+	}
+	if diff.UnchangedCount != 1 {
+		t.Errorf("UnchangedCount = %d, want 1", diff.UnchangedCount)
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("Changed = %+v, want model01.yml", diff.Changed)
+	}
+
+	change := diff.Changed[0]
+	if strings.Join(change.Sections, ",") != "failsafe,mixes,outputs" {
+		t.Errorf("Sections = %v, want failsafe, mixes, outputs (semver ignored)", change.Sections)
+	}
+	got := make(map[string]models.EdgeTXFieldChange)
+	for _, c := range change.Changes {
+		got[c.Path] = c
+	}
+	if c := got["mixData.1.weight"]; c.Before != "100" || c.After != "50" || c.Kind != models.EdgeTXFieldChanged {
+		t.Errorf("mixData.1.weight = %+v, want 100 -> 50", c)
+	}
+	if c := got["moduleData.0.failsafeMode"]; c.Section != "failsafe" || c.After != "NO_PULSES" {
+		t.Errorf("failsafeMode = %+v, want failsafe section change", c)
+	}
+	if len(change.Changes) != 4 {
+		t.Errorf("Changes = %+v, want 4 field changes", change.Changes)
+	}
+}
+
+func TestDiffEdgeTXModelYAML_AddedAndRemoved(t *testing.T) {
+	base := "mixData:\n  0:\n    weight: 100\n  1:\n    weight: 50\n"
+	target := "mixData:\n  0:\n    weight: 100\nexpoData:\n  0:\n    weight: 80\n"
+
+	changes := diffEdgeTXModelYAML([]byte(base), []byte(target))
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want 2", changes)
+	}
+	if changes[0].Path != "expoData.0.weight" || changes[0].Kind != models.EdgeTXFieldAdded || changes[0].Section != "inputs" {
+		t.Errorf("changes[0] = %+v, want added input", changes[0])
+	}
+	if changes[1].Path != "mixData.1.weight" || changes[1].Kind != models.EdgeTXFieldRemoved || changes[1].Before != "50" {
+		t.Errorf("changes[1] = %+v, want removed mix", changes[1])
+	}
+}
+
+func TestBuildManifestAndDiff(t *testing.T) {
+	base := buildTestZip(t, map[string]string{
+		"RADIO/radio.yml":     "board: tx16s\n",
+		"SOUNDS/en/hello.wav": "RIFF",
+		"SCRIPTS/old.lua":     "return {}",
+	})
+	target := buildTestZip(t, map[string]string{
+		"RADIO/radio.yml":     "board: tx16s\nbacklight: 5\n",
+		"SOUNDS/en/hello.wav": "RIFF",
+		"SCRIPTS/new.lua":     "return {}",
+	})
+
+	baseManifest, isArchive, err := buildManifest(context.Background(), "base.zip", bytes.NewReader(base), int64(len(base)))
+	if err != nil || !isArchive {
+		t.Fatalf("buildManifest() = %v, %v, want archive", isArchive, err)
+	}
+	targetManifest, _, err := buildManifest(context.Background(), "target.zip", bytes.NewReader(target), int64(len(target)))
+	if err != nil {
+		t.Fatalf("buildManifest() error = %v", err)
+	}
+
+	diff := diffManifests(baseManifest, targetManifest)
+	if len(diff.Added) != 1 || diff.Added[0].Path != "SCRIPTS/new.lua" {
+		t.Errorf("Added = %+v, want SCRIPTS/new.lua", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Path != "SCRIPTS/old.lua" {
+		t.Errorf("Removed = %+v, want SCRIPTS/old.lua", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Path != "RADIO/radio.yml" || diff.Changed[0].BeforeHash == diff.Changed[0].AfterHash {
+		t.Errorf("Changed = %+v, want RADIO/radio.yml with differing hashes", diff.Changed)
+	}
+	if diff.UnchangedCount != 1 {
+		t.Errorf("UnchangedCount = %d, want 1", diff.UnchangedCount)
+	}
+}
+
+func TestBuildManifest_Limits(t *testing.T) {
+	files := make(map[string]string, maxManifestEntries+1)
+	for i := 0; i <= maxManifestEntries; i++ {
+		files[fmt.Sprintf("SOUNDS/%d.wav", i)] = "RIFF"
+	}
+	data := buildTestZip(t, files)
+	if _, _, err := buildManifest(context.Background(), "sd.zip", bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("buildManifest() error = nil, want error for too many files")
+	}
+
+	data = buildTestZip(t, map[string]string{"RADIO/radio.yml": "board: tx16s\n"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := buildManifest(ctx, "sd.zip", bytes.NewReader(data), int64(len(data))); err != context.Canceled {
+		t.Errorf("buildManifest() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestBuildManifest_SingleFile(t *testing.T) {
+	data := []byte("binary eeprom image")
+
+	manifest, isArchive, err := buildManifest(context.Background(), "uploads/radio.bin", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("buildManifest() error = %v", err)
+	}
+	if isArchive || len(manifest) != 1 || manifest[0].Path != "radio.bin" || manifest[0].Size != int64(len(data)) {
+		t.Errorf("manifest = %+v, want one radio.bin entry", manifest)
+	}
+}
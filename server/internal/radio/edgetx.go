@@ -0,0 +1,337 @@
+package radio
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const (
+	// maxEdgeTXModelFileSize caps a single MODELS/*.yml file read from an archive
+	maxEdgeTXModelFileSize = 1 * 1024 * 1024
+	// maxEdgeTXModelFiles caps how many model files are parsed from one archive
+	maxEdgeTXModelFiles = 1000
+)
+
+// edgeTXModuleInternal and edgeTXModuleExternal are the moduleData indexes used by EdgeTX
+const (
+	edgeTXModuleInternal = 0
+	edgeTXModuleExternal = 1
+)
+
+// edgeTXModelFile is the subset of an EdgeTX model YAML file we summarize.
+// EdgeTX writes arrays as maps keyed by index, so those fields are map[int].
+type edgeTXModelFile struct {
+	Header struct {
+		Name    string    `yaml:"name"`
+		ModelID yaml.Node `yaml:"modelId"`
+	} `yaml:"header"`
+	Timers     map[int]edgeTXTimerData  `yaml:"timers"`
+	LogicalSw  map[int]edgeTXLogicalSw  `yaml:"logicalSw"`
+	ModuleData map[int]edgeTXModuleData `yaml:"moduleData"`
+}
+
+type edgeTXTimerData struct {
+	Start      int    `yaml:"start"`
+	Switch     string `yaml:"swtch"`
+	Mode       string `yaml:"mode"`
+	Persistent int    `yaml:"persistent"`
+	Name       string `yaml:"name"`
+}
+
+type edgeTXLogicalSw struct {
+	Func string `yaml:"func"`
+}
+
+type edgeTXModuleData struct {
+	Type    string `yaml:"type"`
+	SubType string `yaml:"subType"`
+}
+
+// edgeTXProtocolNames maps EdgeTX module types (TYPE_ prefix stripped) to the names pilots use
+var edgeTXProtocolNames = map[string]string{
+	"PPM":               "PPM",
+	"XJT_PXX1":          "XJT",
+	"ISRM_PXX2":         "ACCESS",
+	"DSM2":              "DSM",
+	"CROSSFIRE":         "CRSF",
+	"MULTIMODULE":       "Multi",
+	"R9M_PXX1":          "R9M",
+	"R9M_PXX2":          "R9M ACCESS",
+	"R9M_LITE_PXX1":     "R9M Lite",
+	"R9M_LITE_PXX2":     "R9M Lite ACCESS",
+	"R9M_LITE_PRO_PXX1": "R9M Lite Pro",
+	"R9M_LITE_PRO_PXX2": "R9M Lite Pro ACCESS",
+	"GHOST":             "Ghost",
+	"SBUS":              "SBUS",
+	"XJT_LITE_PXX2":     "ACCESS",
+	"FLYSKY_AFHDS2A":    "AFHDS2A",
+	"FLYSKY_AFHDS3":     "AFHDS3",
+	"LEMON_DSMP":        "DSMP",
+}
+
+// ParseEdgeTXBackup parses every EdgeTX model file in a backup.
+// Zip archives are searched for MODELS/*.yml; a bare .yml upload is parsed directly.
+// Files that fail to parse are reported as warnings rather than failing the backup.
+func ParseEdgeTXBackup(fileName string, r io.ReaderAt, size int64) (*models.RadioBackupModelSummary, error) {
+	files, warnings, err := readEdgeTXModelFiles(fileName, r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.RadioBackupModelSummary{
+		Models:        []models.EdgeTXModel{},
+		ParseWarnings: warnings,
+		ParsedAt:      time.Now().UTC(),
+	}
+	for _, f := range files {
+		model, err := ParseEdgeTXModel(f.name, f.data)
+		if err != nil {
+			summary.ParseWarnings = append(summary.ParseWarnings, fmt.Sprintf("%s: %v", f.name, err))
+			continue
+		}
+		summary.Models = append(summary.Models, model)
+	}
+	summary.ModelCount = len(summary.Models)
+
+	return summary, nil
+}
+
+// ParseEdgeTXModel summarizes a single EdgeTX model YAML file
+func ParseEdgeTXModel(fileName string, data []byte) (models.EdgeTXModel, error) {
+	var file edgeTXModelFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return models.EdgeTXModel{}, fmt.Errorf("invalid model YAML: %w", err)
+	}
+
+	model := models.EdgeTXModel{
+		FileName:   fileName,
+		Name:       strings.TrimSpace(file.Header.Name),
+		ModuleType: models.EdgeTXModuleNone,
+	}
+	if model.Name == "" {
+		model.Name = strings.TrimSuffix(path.Base(fileName), path.Ext(fileName))
+	}
+
+	internal := edgeTXProtocolName(file.ModuleData[edgeTXModuleInternal])
+	external := edgeTXProtocolName(file.ModuleData[edgeTXModuleExternal])
+	model.InternalProtocol = internal
+	model.ExternalProtocol = external
+	switch {
+	case internal != "" && external != "":
+		model.ModuleType = models.EdgeTXModuleBoth
+	case internal != "":
+		model.ModuleType = models.EdgeTXModuleInternal
+	case external != "":
+		model.ModuleType = models.EdgeTXModuleExternal
+	}
+
+	// modelId holds the receiver number per module; report the active module's
+	activeModule := edgeTXModuleInternal
+	if internal == "" && external != "" {
+		activeModule = edgeTXModuleExternal
+	}
+	if receiverNumber, ok := edgeTXModelID(&file.Header.ModelID, activeModule); ok {
+		model.ReceiverNumber = &receiverNumber
+	}
+
+	for _, idx := range sortedKeys(file.Timers) {
+		t := file.Timers[idx]
+		mode := strings.ToUpper(strings.TrimSpace(t.Mode))
+		if mode == "" || mode == "OFF" {
+			continue
+		}
+		model.Timers = append(model.Timers, models.EdgeTXTimer{
+			Index:        idx + 1,
+			Name:         strings.TrimSpace(t.Name),
+			Mode:         mode,
+			StartSeconds: t.Start,
+			Switch:       strings.TrimSpace(t.Switch),
+			Persistent:   t.Persistent != 0,
+		})
+	}
+
+	for _, ls := range file.LogicalSw {
+		fn := strings.ToUpper(strings.TrimSpace(ls.Func))
+		if fn != "" && fn != "FUNC_NONE" {
+			model.LogicalSwitchCount++
+		}
+	}
+
+	return model, nil
+}
+
+// edgeTXProtocolName returns a display protocol for a module, or "" if it is off
+func edgeTXProtocolName(module edgeTXModuleData) string {
+	moduleType := strings.ToUpper(strings.TrimSpace(module.Type))
+	moduleType = strings.TrimPrefix(moduleType, "TYPE_")
+	if moduleType == "" || moduleType == "NONE" {
+		return ""
+	}
+
+	name, ok := edgeTXProtocolNames[moduleType]
+	if !ok {
+		name = moduleType
+	}
+
+	// Multi-protocol modules carry the RF protocol in subType (e.g. "FrSkyX2,D16")
+	if name == "Multi" {
+		subType := strings.TrimSpace(module.SubType)
+		if subType != "" {
+			if _, err := strconv.Atoi(subType); err != nil {
+				name = "Multi " + subType
+			}
+		}
+	}
+	return name
+}
+
+// edgeTXModelID reads header.modelId, which EdgeTX writes as an index-keyed map
+// ({0: 3, 1: 0}); a sequence or plain scalar is accepted too
+func edgeTXModelID(node *yaml.Node, module int) (int, bool) {
+	var value string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value = node.Value
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == strconv.Itoa(module) {
+				value = node.Content[i+1].Value
+				break
+			}
+		}
+	case yaml.SequenceNode:
+		if module < len(node.Content) {
+			value = node.Content[module].Value
+		}
+	default:
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// edgeTXModelFileData is a model file read from a backup
+type edgeTXModelFileData struct {
+	name string
+	data []byte
+}
+
+// readEdgeTXModelFiles extracts the MODELS/*.yml files from a backup, sorted by path
+func readEdgeTXModelFiles(fileName string, r io.ReaderAt, size int64) ([]edgeTXModelFileData, []string, error) {
+	header := make([]byte, 4)
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return nil, nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if !bytes.HasPrefix(header, []byte("PK\x03\x04")) {
+		if !isYAMLFile(fileName) {
+			return nil, nil, fmt.Errorf("unsupported backup format: expected a .zip archive or .yml model file")
+		}
+		if size > maxEdgeTXModelFileSize {
+			return nil, nil, fmt.Errorf("model file exceeds %d bytes", maxEdgeTXModelFileSize)
+		}
+		data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read model file: %w", err)
+		}
+		return []edgeTXModelFileData{{name: path.Base(fileName), data: data}}, nil, nil
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	var (
+		files    []edgeTXModelFileData
+		warnings []string
+	)
+	for _, entry := range archive.File {
+		if !isEdgeTXModelPath(entry.Name) {
+			continue
+		}
+		if len(files) >= maxEdgeTXModelFiles {
+			warnings = append(warnings, fmt.Sprintf("only the first %d model files were parsed", maxEdgeTXModelFiles))
+			break
+		}
+		if entry.UncompressedSize64 > maxEdgeTXModelFileSize {
+			warnings = append(warnings, fmt.Sprintf("%s: skipped, file too large", entry.Name))
+			continue
+		}
+
+		data, err := readZipEntry(entry)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", entry.Name, err))
+			continue
+		}
+		files = append(files, edgeTXModelFileData{name: entry.Name, data: data})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, warnings, nil
+}
+
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open: %w", err)
+	}
+	defer rc.Close()
+
+	// Guard against entries whose header understates their size
+	data, err := io.ReadAll(io.LimitReader(rc, maxEdgeTXModelFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+	if len(data) > maxEdgeTXModelFileSize {
+		return nil, fmt.Errorf("skipped, file too large")
+	}
+	return data, nil
+}
+
+// isEdgeTXModelPath matches MODELS/<model>.yml at any depth, skipping the
+// models.yml/labels.yml index files EdgeTX keeps alongside the models
+func isEdgeTXModelPath(name string) bool {
+	name = strings.ReplaceAll(name, "\\", "/")
+	dir, base := path.Split(name)
+	if !strings.EqualFold(path.Base(strings.TrimSuffix(dir, "/")), "MODELS") || !isYAMLFile(base) {
+		return false
+	}
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+	switch strings.ToLower(base) {
+	case "models.yml", "models.yaml", "labels.yml", "labels.yaml":
+		return false
+	}
+	return true
+}
+
+func isYAMLFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
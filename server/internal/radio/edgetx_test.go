@@ -0,0 +1,182 @@
+package radio
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+const testEdgeTXModel = `semver: 2.9.4
+header:
+  name: "Nazgul5"
+  bitmap: ""
+  modelId:
+    0: 7
+    1: 0
+timers:
+  0:
+    start: 240
+    swtch: "SA2"
+    value: 0
+    mode: ON
+    countdownBeep: 1
+    minuteBeep: 1
+    persistent: 1
+    countdownStart: 0
+    name: "Flight"
+  1:
+    start: 0
+    swtch: "NONE"
+    mode: OFF
+    persistent: 0
+    name: ""
+logicalSw:
+  0:
+    func: FUNC_VPOS
+    def: "ch5,0"
+  1:
+    func: FUNC_AND
+    def: "L1,SA2"
+  2:
+    func: FUNC_NONE
+moduleData:
+  0:
+    type: TYPE_CROSSFIRE
+    subType: 0
+    channelsStart: 0
+    channelsCount: 16
+  1:
+    type: TYPE_NONE
+`
+
+const testEdgeTXExternalModel = `header:
+  name: "Wing"
+  modelId:
+    0: 0
+    1: 12
+moduleData:
+  0:
+    type: TYPE_NONE
+  1:
+    type: TYPE_MULTIMODULE
+    subType: FrSkyX2,D16
+`
+
+func TestParseEdgeTXModel(t *testing.T) {
+	model, err := ParseEdgeTXModel("MODELS/model01.yml", []byte(testEdgeTXModel))
+	if err != nil {
+		t.Fatalf("ParseEdgeTXModel() error = %v", err)
+	}
+
+	if model.Name != "Nazgul5" {
+		t.Errorf("Name = %q, want Nazgul5", model.Name)
+	}
+	if model.ModuleType != models.EdgeTXModuleInternal || model.InternalProtocol != "CRSF" || model.ExternalProtocol != "" {
+		t.Errorf("modules = %s/%q/%q, want internal CRSF only", model.ModuleType, model.InternalProtocol, model.ExternalProtocol)
+	}
+	if model.ReceiverNumber == nil || *model.ReceiverNumber != 7 {
+		t.Errorf("ReceiverNumber = %v, want 7", model.ReceiverNumber)
+	}
+	if len(model.Timers) != 1 {
+		t.Fatalf("Timers = %+v, want 1 enabled timer", model.Timers)
+	}
+	timer := model.Timers[0]
+	if timer.Index != 1 || timer.Name != "Flight" || timer.StartSeconds != 240 || timer.Switch != "SA2" || !timer.Persistent {
+		t.Errorf("Timers[0] = %+v, want Flight countdown from 240s on SA2, persistent", timer)
+	}
+	if model.LogicalSwitchCount != 2 {
+		t.Errorf("LogicalSwitchCount = %d, want 2", model.LogicalSwitchCount)
+	}
+}
+
+func TestParseEdgeTXModel_ExternalMulti(t *testing.T) {
+	model, err := ParseEdgeTXModel("MODELS/model02.yml", []byte(testEdgeTXExternalModel))
+	if err != nil {
+		t.Fatalf("ParseEdgeTXModel() error = %v", err)
+	}
+
+	if model.ModuleType != models.EdgeTXModuleExternal || model.ExternalProtocol != "Multi FrSkyX2,D16" {
+		t.Errorf("modules = %s/%q, want external Multi FrSkyX2,D16", model.ModuleType, model.ExternalProtocol)
+	}
+	if model.ReceiverNumber == nil || *model.ReceiverNumber != 12 {
+		t.Errorf("ReceiverNumber = %v, want external module's 12", model.ReceiverNumber)
+	}
+}
+
+func TestParseEdgeTXBackup_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"RADIO/radio.yml":     "board: tx16s\n",
+		"MODELS/models.yml":   "- model01.yml\n",
+		"MODELS/model01.yml":  testEdgeTXModel,
+		"MODELS/model02.yml":  testEdgeTXExternalModel,
+		"MODELS/broken.yml":   "header: [unterminated\n",
+		"SOUNDS/en/hello.wav": "RIFF",
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+
+	summary, err := ParseEdgeTXBackup("sdcard.zip", bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ParseEdgeTXBackup() error = %v", err)
+	}
+
+	if summary.ModelCount != 2 {
+		t.Fatalf("ModelCount = %d, want 2 (models: %+v)", summary.ModelCount, summary.Models)
+	}
+	if summary.Models[0].Name != "Nazgul5" || summary.Models[1].Name != "Wing" {
+		t.Errorf("models = %q, %q, want sorted by file name", summary.Models[0].Name, summary.Models[1].Name)
+	}
+	if len(summary.ParseWarnings) != 1 || !strings.Contains(summary.ParseWarnings[0], "MODELS/broken.yml") {
+		t.Errorf("ParseWarnings = %v, want one warning for broken.yml", summary.ParseWarnings)
+	}
+}
+
+func TestParseEdgeTXBackup_SingleFile(t *testing.T) {
+	data := []byte(testEdgeTXModel)
+
+	summary, err := ParseEdgeTXBackup("model01.yml", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ParseEdgeTXBackup() error = %v", err)
+	}
+	if summary.ModelCount != 1 || summary.Models[0].FileName != "model01.yml" {
+		t.Errorf("summary = %+v, want the single model", summary)
+	}
+
+	if _, err := ParseEdgeTXBackup("backup.bin", bytes.NewReader([]byte("binary")), 6); err == nil {
+		t.Error("ParseEdgeTXBackup() expected error for unsupported format, got nil")
+	}
+}
+
+func TestIsEdgeTXModelPath(t *testing.T) {
+	tests := map[string]bool{
+		"MODELS/model01.yml":           true,
+		"backup/MODELS/model01.yml":    true,
+		"models/quad.yaml":             true,
+		"MODELS\\model03.yml":          true,
+		"MODELS/models.yml":            false,
+		"MODELS/labels.yml":            false,
+		"MODELS/._model01.yml":         false,
+		"RADIO/radio.yml":              false,
+		"MODELS/model01.bin":           false,
+		"MODELS/subdir/model01.yml":    false,
+		"SCRIPTS/MODELS/something.lua": false,
+	}
+	for name, want := range tests {
+		if got := isEdgeTXModelPath(name); got != want {
+			t.Errorf("isEdgeTXModelPath(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -4,23 +4,31 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/johnrirwin/flyingforge/internal/database"
 	"github.com/johnrirwin/flyingforge/internal/logging"
 	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
 )
 
 const (
 	// MaxBackupFileSize is the maximum allowed backup file size (100MB)
 	MaxBackupFileSize = 100 * 1024 * 1024
-	// DefaultStorageDir is the default directory for backup storage
-	DefaultStorageDir = "./data/radio_backups"
+	// DefaultLocalStorageRoot is the object store root used when none is configured.
+	// Backups live under radio_backups/ within it.
+	DefaultLocalStorageRoot = "./data"
+	// DefaultDownloadURLTTL is how long direct download URLs stay valid
+	DefaultDownloadURLTTL = 15 * time.Minute
 )
 
 // ServiceError represents a service-level error
@@ -34,20 +42,26 @@ func (e *ServiceError) Error() string {
 
 // Service handles radio operations
 type Service struct {
-	store      *database.RadioStore
-	storageDir string
-	logger     *logging.Logger
+	store          *database.RadioStore
+	blobs          storage.ObjectStore
+	downloadURLTTL time.Duration
+	logger         *logging.Logger
 }
 
-// NewService creates a new radio service
-func NewService(store *database.RadioStore, storageDir string, logger *logging.Logger) *Service {
-	if storageDir == "" {
-		storageDir = DefaultStorageDir
+// NewService creates a new radio service. A nil object store keeps backups
+// on local disk under DefaultLocalStorageRoot.
+func NewService(store *database.RadioStore, blobs storage.ObjectStore, downloadURLTTL time.Duration, logger *logging.Logger) *Service {
+	if blobs == nil {
+		blobs = storage.NewLocalStore(DefaultLocalStorageRoot)
+	}
+	if downloadURLTTL <= 0 {
+		downloadURLTTL = DefaultDownloadURLTTL
 	}
 	return &Service{
-		store:      store,
-		storageDir: storageDir,
-		logger:     logger,
+		store:          store,
+		blobs:          blobs,
+		downloadURLTTL: downloadURLTTL,
+		logger:         logger,
 	}
 }
 
@@ -119,24 +133,11 @@ func (s *Service) DeleteRadio(ctx context.Context, id string, userID string) err
 	if err != nil {
 		s.logger.Warn("Failed to list backups for deletion", logging.WithField("error", err.Error()))
 	} else {
-		for _, backup := range backups.Backups {
-			if backup.StoragePath != "" {
-				if err := os.Remove(backup.StoragePath); err != nil && !os.IsNotExist(err) {
-					s.logger.Warn("Failed to delete backup file", logging.WithFields(map[string]interface{}{
-						"path":  backup.StoragePath,
-						"error": err.Error(),
-					}))
-				}
-			}
+		for i := range backups.Backups {
+			s.removeBackupFile(ctx, &backups.Backups[i])
 		}
 	}
 
-	// Delete the radio directory if it exists
-	radioDir := filepath.Join(s.storageDir, id)
-	if err := os.RemoveAll(radioDir); err != nil && !os.IsNotExist(err) {
-		s.logger.Warn("Failed to delete radio directory", logging.WithField("error", err.Error()))
-	}
-
 	// Delete the radio record (cascades to backups in DB)
 	if err := s.store.DeleteRadio(ctx, id, userID); err != nil {
 		s.logger.Error("Failed to delete radio", logging.WithFields(map[string]interface{}{
@@ -175,26 +176,14 @@ func (s *Service) CreateBackup(ctx context.Context, radioID string, userID strin
 		return nil, &ServiceError{Message: "radio not found"}
 	}
 
-	// Create storage directory
-	radioDir := filepath.Join(s.storageDir, radioID)
-	if err := os.MkdirAll(radioDir, 0755); err != nil {
-		s.logger.Error("Failed to create storage directory", logging.WithField("error", err.Error()))
-		return nil, &ServiceError{Message: "failed to create storage directory"}
-	}
-
-	// Sanitize filename and create storage path
-	safeFileName := sanitizeFileName(params.FileName)
-	storagePath := filepath.Join(radioDir, safeFileName)
-
-	// Ensure unique filename
-	storagePath = ensureUniquePath(storagePath)
-
-	// Create the file
-	file, err := os.Create(storagePath)
+	// Spool the upload to a temp file so it can be size-checked, hashed and
+	// parsed before it is handed to the object store
+	file, err := os.CreateTemp("", "radio-backup-*")
 	if err != nil {
 		s.logger.Error("Failed to create backup file", logging.WithField("error", err.Error()))
 		return nil, &ServiceError{Message: "failed to create backup file"}
 	}
+	defer os.Remove(file.Name())
 	defer file.Close()
 
 	// Copy file content and calculate checksum
@@ -206,14 +195,12 @@ func (s *Service) CreateBackup(ctx context.Context, radioID string, userID strin
 
 	written, err := io.Copy(file, teeReader)
 	if err != nil {
-		os.Remove(storagePath)
 		s.logger.Error("Failed to write backup file", logging.WithField("error", err.Error()))
 		return nil, &ServiceError{Message: "failed to write backup file"}
 	}
 
 	// Verify actual written size doesn't exceed limit
 	if written > MaxBackupFileSize {
-		os.Remove(storagePath)
 		return nil, &ServiceError{Message: fmt.Sprintf("actual file size (%d bytes) exceeds maximum allowed (%d bytes)", written, MaxBackupFileSize)}
 	}
 
@@ -221,17 +208,36 @@ func (s *Service) CreateBackup(ctx context.Context, radioID string, userID strin
 	params.FileSize = written
 	params.Checksum = hex.EncodeToString(hasher.Sum(nil))
 
+	// Parse the EdgeTX model list so it can be listed without downloading the archive
+	if params.BackupType.HasEdgeTXModels() {
+		params.ModelSummary = s.parseModelSummary(params.FileName, file, written)
+	}
+
+	// Upload before inserting so a record never points at a missing object
+	storageKey := backupObjectKey(radioID, params.FileName)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind backup file: %w", err)
+	}
+	if err := s.blobs.Put(ctx, storageKey, file, written, "application/octet-stream"); err != nil {
+		s.logger.Error("Failed to store backup file", logging.WithField("error", err.Error()))
+		return nil, &ServiceError{Message: "failed to store backup file"}
+	}
+	params.StorageKey = storageKey
+
 	s.logger.Debug("Creating backup record", logging.WithFields(map[string]interface{}{
 		"radio_id":    radioID,
 		"backup_name": params.BackupName,
 		"file_name":   params.FileName,
 		"file_size":   params.FileSize,
+		"storage_key": storageKey,
 	}))
 
 	// Create the database record
-	backup, err := s.store.CreateBackup(ctx, radioID, params, storagePath)
+	backup, err := s.store.CreateBackup(ctx, radioID, params, "")
 	if err != nil {
-		os.Remove(storagePath)
+		if delErr := s.blobs.Delete(ctx, storageKey); delErr != nil {
+			s.logger.Warn("Failed to delete orphaned backup object", logging.WithField("error", delErr.Error()))
+		}
 		s.logger.Error("Failed to create backup record", logging.WithField("error", err.Error()))
 		return nil, err
 	}
@@ -278,11 +284,17 @@ func (s *Service) GetBackupFile(ctx context.Context, backupID string, radioID st
 		return nil, nil, &ServiceError{Message: "backup not found"}
 	}
 
-	file, err := os.Open(backup.StoragePath)
+	var file io.ReadCloser
+	if backup.StorageKey != "" {
+		file, _, err = s.blobs.Get(ctx, backup.StorageKey)
+	} else {
+		file, err = os.Open(backup.StoragePath)
+	}
 	if err != nil {
 		s.logger.Error("Failed to open backup file", logging.WithFields(map[string]interface{}{
-			"path":  backup.StoragePath,
-			"error": err.Error(),
+			"storage_key": backup.StorageKey,
+			"path":        backup.StoragePath,
+			"error":       err.Error(),
 		}))
 		return nil, nil, &ServiceError{Message: "backup file not found"}
 	}
@@ -290,6 +302,250 @@ func (s *Service) GetBackupFile(ctx context.Context, backupID string, radioID st
 	return file, backup, nil
 }
 
+// GetBackupDownloadURL returns a short-lived URL that downloads the backup
+// straight from the object store, bypassing the API server.
+func (s *Service) GetBackupDownloadURL(ctx context.Context, backupID string, radioID string, userID string) (*models.RadioBackupDownloadURL, error) {
+	backup, err := s.GetBackup(ctx, backupID, radioID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if backup == nil {
+		return nil, &ServiceError{Message: "backup not found"}
+	}
+	if backup.StorageKey == "" {
+		return nil, &ServiceError{Message: "direct download is not available for this backup"}
+	}
+
+	url, err := s.blobs.PresignGet(ctx, backup.StorageKey, s.downloadURLTTL, backup.FileName)
+	if errors.Is(err, storage.ErrPresignUnsupported) {
+		return nil, &ServiceError{Message: "direct download is not available with the configured storage backend"}
+	}
+	if err != nil {
+		s.logger.Error("Failed to presign backup download", logging.WithFields(map[string]interface{}{
+			"id":    backup.ID,
+			"error": err.Error(),
+		}))
+		return nil, err
+	}
+
+	return &models.RadioBackupDownloadURL{
+		URL:       url,
+		ExpiresAt: time.Now().UTC().Add(s.downloadURLTTL),
+	}, nil
+}
+
+// GetBackupModels returns the parsed EdgeTX model list for a backup.
+// Backups uploaded before parsing existed are parsed on first request and the result saved.
+func (s *Service) GetBackupModels(ctx context.Context, backupID string, radioID string, userID string) (*models.RadioBackupModelSummary, error) {
+	backup, err := s.GetBackup(ctx, backupID, radioID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if backup == nil {
+		return nil, &ServiceError{Message: "backup not found"}
+	}
+	if !backup.BackupType.HasEdgeTXModels() {
+		return nil, &ServiceError{Message: fmt.Sprintf("%s backups do not contain EdgeTX models", backup.BackupType)}
+	}
+	if backup.ModelSummary != nil {
+		return backup.ModelSummary, nil
+	}
+
+	file, size, err := s.openBackupFile(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	summary := s.parseModelSummary(backup.FileName, file, size)
+	if err := s.store.UpdateBackupModelSummary(ctx, backup.ID, radioID, summary); err != nil {
+		s.logger.Warn("Failed to save backup model summary", logging.WithFields(map[string]interface{}{
+			"id":    backup.ID,
+			"error": err.Error(),
+		}))
+	}
+	return summary, nil
+}
+
+// parseModelSummary parses an EdgeTX backup. An unreadable archive yields an
+// empty summary with a warning rather than rejecting the upload.
+func (s *Service) parseModelSummary(fileName string, r io.ReaderAt, size int64) *models.RadioBackupModelSummary {
+	summary, err := ParseEdgeTXBackup(fileName, r, size)
+	if err != nil {
+		s.logger.Warn("Failed to parse EdgeTX backup", logging.WithFields(map[string]interface{}{
+			"file_name": fileName,
+			"error":     err.Error(),
+		}))
+		return &models.RadioBackupModelSummary{
+			Models:        []models.EdgeTXModel{},
+			ParseWarnings: []string{err.Error()},
+			ParsedAt:      time.Now().UTC(),
+		}
+	}
+	return summary
+}
+
+// DiffBackups compares two backups of the same radio. EdgeTX backups are compared
+// model by model; anything else falls back to a file-level manifest diff.
+func (s *Service) DiffBackups(ctx context.Context, radioID string, baseID string, targetID string, userID string) (*models.RadioBackupDiff, error) {
+	if baseID == "" {
+		return nil, &ServiceError{Message: "base backup is required"}
+	}
+	if baseID == targetID {
+		return nil, &ServiceError{Message: "cannot compare a backup with itself"}
+	}
+
+	base, err := s.GetBackup(ctx, baseID, radioID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return nil, &ServiceError{Message: "base backup not found"}
+	}
+	target, err := s.GetBackup(ctx, targetID, radioID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, &ServiceError{Message: "backup not found"}
+	}
+
+	baseFile, baseSize, err := s.openBackupFile(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	defer baseFile.Close()
+	targetFile, targetSize, err := s.openBackupFile(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer targetFile.Close()
+
+	diff := &models.RadioBackupDiff{
+		RadioID: radioID,
+		Base:    backupRef(base),
+		Target:  backupRef(target),
+	}
+
+	if base.BackupType.HasEdgeTXModels() && target.BackupType.HasEdgeTXModels() {
+		baseModels, baseWarnings, baseErr := readEdgeTXModelFiles(base.FileName, baseFile, baseSize)
+		targetModels, targetWarnings, targetErr := readEdgeTXModelFiles(target.FileName, targetFile, targetSize)
+		if baseErr == nil && targetErr == nil {
+			diff.Mode = models.RadioBackupDiffModeEdgeTX
+			diff.Models = diffEdgeTXModelFiles(baseModels, targetModels)
+			diff.HasChanges = len(diff.Models.Added) > 0 || len(diff.Models.Removed) > 0 || len(diff.Models.Changed) > 0
+			for _, w := range baseWarnings {
+				diff.Warnings = append(diff.Warnings, "base: "+w)
+			}
+			for _, w := range targetWarnings {
+				diff.Warnings = append(diff.Warnings, "target: "+w)
+			}
+			return diff, nil
+		}
+		diff.Warnings = append(diff.Warnings, "EdgeTX models could not be read, compared files instead")
+	}
+
+	baseManifest, baseArchive, err := buildManifest(ctx, base.FileName, baseFile, baseSize)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("failed to read base backup: %v", err)}
+	}
+	targetManifest, targetArchive, err := buildManifest(ctx, target.FileName, targetFile, targetSize)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, &ServiceError{Message: fmt.Sprintf("failed to read backup: %v", err)}
+	}
+	// Two single-file backups are the same file even if they were uploaded under different names
+	if !baseArchive && !targetArchive {
+		baseManifest[0].Path = targetManifest[0].Path
+	}
+
+	diff.Mode = models.RadioBackupDiffModeManifest
+	diff.Files = diffManifests(baseManifest, targetManifest)
+	diff.HasChanges = len(diff.Files.Added) > 0 || len(diff.Files.Removed) > 0 || len(diff.Files.Changed) > 0
+	return diff, nil
+}
+
+// openBackupFile opens a backup's stored file for random access and returns its size.
+// Objects in remote stores are copied to a temp file that is removed on Close.
+func (s *Service) openBackupFile(ctx context.Context, backup *models.RadioBackup) (storage.ReadAtCloser, int64, error) {
+	file, size, err := s.openBackupReaderAt(ctx, backup)
+	if err != nil {
+		s.logger.Error("Failed to open backup file", logging.WithFields(map[string]interface{}{
+			"storage_key": backup.StorageKey,
+			"path":        backup.StoragePath,
+			"error":       err.Error(),
+		}))
+		return nil, 0, &ServiceError{Message: "backup file not found"}
+	}
+	return file, size, nil
+}
+
+func (s *Service) openBackupReaderAt(ctx context.Context, backup *models.RadioBackup) (storage.ReadAtCloser, int64, error) {
+	if backup.StorageKey == "" {
+		return openLocalFile(backup.StoragePath)
+	}
+	if opener, ok := s.blobs.(storage.FileOpener); ok {
+		return opener.OpenFile(backup.StorageKey)
+	}
+
+	body, _, err := s.blobs.Get(ctx, backup.StorageKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "radio-backup-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, 0, err
+	}
+	return &tempFile{File: tmp}, size, nil
+}
+
+func openLocalFile(path string) (storage.ReadAtCloser, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// tempFile deletes itself when closed
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.File.Name())
+	return err
+}
+
+func backupRef(backup *models.RadioBackup) models.RadioBackupRef {
+	return models.RadioBackupRef{
+		ID:         backup.ID,
+		BackupName: backup.BackupName,
+		BackupType: backup.BackupType,
+		FileName:   backup.FileName,
+		CreatedAt:  backup.CreatedAt,
+	}
+}
+
 // DeleteBackup deletes a backup and its file
 func (s *Service) DeleteBackup(ctx context.Context, backupID string, radioID string, userID string) error {
 	// Verify the radio belongs to user
@@ -313,17 +569,103 @@ func (s *Service) DeleteBackup(ctx context.Context, backupID string, radioID str
 		return err
 	}
 
-	// Delete the file
-	if backup.StoragePath != "" {
-		if err := os.Remove(backup.StoragePath); err != nil && !os.IsNotExist(err) {
-			s.logger.Warn("Failed to delete backup file", logging.WithFields(map[string]interface{}{
-				"path":  backup.StoragePath,
-				"error": err.Error(),
+	s.removeBackupFile(ctx, backup)
+
+	s.logger.Info("Deleted backup", logging.WithField("id", backupID))
+	return nil
+}
+
+// removeBackupFile deletes a backup's stored object, or its file on disk for
+// backups uploaded before object storage. Failures are logged, not returned.
+func (s *Service) removeBackupFile(ctx context.Context, backup *models.RadioBackup) {
+	if backup.StorageKey != "" {
+		if err := s.blobs.Delete(ctx, backup.StorageKey); err != nil {
+			s.logger.Warn("Failed to delete backup object", logging.WithFields(map[string]interface{}{
+				"storage_key": backup.StorageKey,
+				"error":       err.Error(),
 			}))
 		}
+		return
+	}
+	if backup.StoragePath == "" {
+		return
+	}
+	if err := os.Remove(backup.StoragePath); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to delete backup file", logging.WithFields(map[string]interface{}{
+			"path":  backup.StoragePath,
+			"error": err.Error(),
+		}))
+		return
 	}
+	// Remove the radio directory once its last legacy file is gone
+	_ = os.Remove(filepath.Dir(backup.StoragePath))
+}
 
-	s.logger.Info("Deleted backup", logging.WithField("id", backupID))
+// MoveLocalBackups uploads backups still stored as local files into the object
+// store and points their records at the new keys. Files that can't be read are
+// skipped. When deleteLocal is set the local copy is removed after a move.
+func (s *Service) MoveLocalBackups(ctx context.Context, batchSize int, deleteLocal bool) (moved int, skipped int, err error) {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	for {
+		// Moved backups drop out of the result set, skipped ones don't
+		backups, err := s.store.ListLocalBackups(ctx, batchSize, skipped)
+		if err != nil {
+			return moved, skipped, err
+		}
+		if len(backups) == 0 {
+			return moved, skipped, nil
+		}
+
+		for i := range backups {
+			backup := &backups[i]
+			if err := s.moveLocalBackup(ctx, backup); err != nil {
+				s.logger.Warn("Failed to move backup to object storage", logging.WithFields(map[string]interface{}{
+					"id":    backup.ID,
+					"path":  backup.StoragePath,
+					"error": err.Error(),
+				}))
+				skipped++
+				continue
+			}
+			moved++
+
+			if deleteLocal {
+				if err := os.Remove(backup.StoragePath); err != nil && !os.IsNotExist(err) {
+					s.logger.Warn("Failed to delete local backup file", logging.WithFields(map[string]interface{}{
+						"path":  backup.StoragePath,
+						"error": err.Error(),
+					}))
+				} else {
+					_ = os.Remove(filepath.Dir(backup.StoragePath))
+				}
+			}
+		}
+	}
+}
+
+func (s *Service) moveLocalBackup(ctx context.Context, backup *models.RadioBackup) error {
+	file, err := os.Open(backup.StoragePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	key := backupObjectKey(backup.RadioID, backup.FileName)
+	if err := s.blobs.Put(ctx, key, file, info.Size(), "application/octet-stream"); err != nil {
+		return err
+	}
+	if err := s.store.SetBackupStorageKey(ctx, backup.ID, key); err != nil {
+		_ = s.blobs.Delete(ctx, key)
+		return err
+	}
 	return nil
 }
 
@@ -348,22 +690,11 @@ func sanitizeFileName(filename string) string {
 	return safe
 }
 
-// ensureUniquePath adds a suffix if the path already exists
-func ensureUniquePath(path string) string {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return path
+// backupObjectKey builds a unique object key that keeps the original file name
+func backupObjectKey(radioID string, fileName string) string {
+	safe := sanitizeFileName(fileName)
+	if safe == "" || safe == "." || safe == ".." {
+		safe = "backup"
 	}
-
-	ext := filepath.Ext(path)
-	base := strings.TrimSuffix(path, ext)
-
-	for i := 1; i < 1000; i++ {
-		newPath := fmt.Sprintf("%s_%d%s", base, i, ext)
-		if _, err := os.Stat(newPath); os.IsNotExist(err) {
-			return newPath
-		}
-	}
-
-	// Fallback: use timestamp
-	return fmt.Sprintf("%s_%d%s", base, os.Getpid(), ext)
+	return fmt.Sprintf("radio_backups/%s/%s/%s", radioID, uuid.NewString(), safe)
 }
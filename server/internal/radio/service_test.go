@@ -0,0 +1,79 @@
+package radio
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
+)
+
+func TestBackupObjectKey(t *testing.T) {
+	key := backupObjectKey("radio-1", `../my "models".zip`)
+	if err := storage.ValidateKey(key); err != nil {
+		t.Fatalf("backupObjectKey() = %q is not a valid key: %v", key, err)
+	}
+	if !strings.HasPrefix(key, "radio_backups/radio-1/") || !strings.HasSuffix(key, "/.._my _models_.zip") {
+		t.Errorf("backupObjectKey() = %q", key)
+	}
+	if key == backupObjectKey("radio-1", `../my "models".zip`) {
+		t.Error("backupObjectKey() should be unique per upload")
+	}
+
+	if key := backupObjectKey("radio-1", ".."); !strings.HasSuffix(key, "/backup") {
+		t.Errorf("backupObjectKey(\"..\") = %q, want fallback name", key)
+	}
+}
+
+func TestOpenBackupFile(t *testing.T) {
+	ctx := context.Background()
+	blobs := storage.NewMemoryStore()
+	svc := NewService(nil, blobs, 0, logging.New(logging.LevelError))
+
+	if err := blobs.Put(ctx, "radio_backups/r1/b1/models.zip", strings.NewReader("remote bytes"), 12, ""); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	legacyPath := filepath.Join(t.TempDir(), "legacy.bin")
+	if err := os.WriteFile(legacyPath, []byte("legacy bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		backup models.RadioBackup
+		want   string
+	}{
+		{"object store", models.RadioBackup{StorageKey: "radio_backups/r1/b1/models.zip"}, "remote bytes"},
+		{"legacy local file", models.RadioBackup{StoragePath: legacyPath}, "legacy bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, size, err := svc.openBackupFile(ctx, &tt.backup)
+			if err != nil {
+				t.Fatalf("openBackupFile() error = %v", err)
+			}
+			defer file.Close()
+
+			data, err := io.ReadAll(io.NewSectionReader(file, 0, size))
+			if err != nil || string(data) != tt.want {
+				t.Errorf("openBackupFile() read %q, %v; want %q", data, err, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := svc.openBackupFile(ctx, &models.RadioBackup{StorageKey: "radio_backups/missing"}); err == nil {
+		t.Error("openBackupFile() expected error for missing object")
+	}
+}
+
+func TestNewServiceDefaults(t *testing.T) {
+	svc := NewService(nil, storage.NewMemoryStore(), 0, logging.New(logging.LevelError))
+	if svc.downloadURLTTL != DefaultDownloadURLTTL {
+		t.Errorf("downloadURLTTL = %v, want default %v", svc.downloadURLTTL, DefaultDownloadURLTTL)
+	}
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalStore keeps objects as files under a root directory. It cannot issue
+// presigned URLs, so callers stream downloads through the API instead.
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates a filesystem-backed store rooted at dir.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{root: dir}
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the object to a temp file and renames it into place.
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("create object directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create object file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write object: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("store object: %w", err)
+	}
+	return nil
+}
+
+// Get opens the object's file for streaming.
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	file, size, err := s.openFile(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("stat object: %w", err)
+	}
+	return file, &ObjectInfo{Key: key, Size: size, LastModified: info.ModTime()}, nil
+}
+
+// OpenFile opens the object's file for random access.
+func (s *LocalStore) OpenFile(key string) (ReadAtCloser, int64, error) {
+	return s.openFile(key)
+}
+
+func (s *LocalStore) openFile(key string) (*os.File, int64, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	file, err := os.Open(target)
+	if os.IsNotExist(err) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("open object: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("stat object: %w", err)
+	}
+	return file, info.Size(), nil
+}
+
+// Delete removes the object's file and its directory if that is now empty.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete object: %w", err)
+	}
+	// Best effort: fails harmlessly if other objects share the directory
+	_ = os.Remove(filepath.Dir(target))
+	return nil
+}
+
+// PresignGet is not supported for local files.
+func (s *LocalStore) PresignGet(ctx context.Context, key string, ttl time.Duration, downloadName string) (string, error) {
+	return "", ErrPresignUnsupported
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStore_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "radio_backups/r1/b1/models.zip", strings.NewReader("zip bytes"), 9, "application/zip"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "radio_backups", "r1", "b1", "models.zip")); err != nil {
+		t.Fatalf("object file missing: %v", err)
+	}
+
+	rc, info, err := store.Get(ctx, "radio_backups/r1/b1/models.zip")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "zip bytes" || info.Size != 9 {
+		t.Errorf("Get() = %q, %+v", data, info)
+	}
+
+	ra, size, err := store.OpenFile("radio_backups/r1/b1/models.zip")
+	if err != nil || size != 9 {
+		t.Fatalf("OpenFile() = %d, %v", size, err)
+	}
+	ra.Close()
+
+	if _, err := store.PresignGet(ctx, "radio_backups/r1/b1/models.zip", time.Minute, ""); !errors.Is(err, ErrPresignUnsupported) {
+		t.Errorf("PresignGet() error = %v, want ErrPresignUnsupported", err)
+	}
+
+	if err := store.Delete(ctx, "radio_backups/r1/b1/models.zip"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, _, err := store.Get(ctx, "radio_backups/r1/b1/models.zip"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "radio_backups", "r1", "b1")); !os.IsNotExist(err) {
+		t.Errorf("empty object directory was not removed: %v", err)
+	}
+	if err := store.Delete(ctx, "radio_backups/r1/b1/models.zip"); err != nil {
+		t.Errorf("Delete() of missing object error = %v, want nil", err)
+	}
+}
+
+func TestValidateKey(t *testing.T) {
+	valid := []string{"images/u1/abc", "radio_backups/r1/b1/my file.zip"}
+	invalid := []string{"", "/etc/passwd", "../secret", "images/../../x", "images//x", "images\\x", "images/./x"}
+
+	for _, key := range valid {
+		if err := ValidateKey(key); err != nil {
+			t.Errorf("ValidateKey(%q) error = %v", key, err)
+		}
+	}
+	for _, key := range invalid {
+		if err := ValidateKey(key); err == nil {
+			t.Errorf("ValidateKey(%q) expected error", key)
+		}
+	}
+}
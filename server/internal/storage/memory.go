@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process ObjectStore for tests and local development.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+	modified    time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string]memoryObject)}
+}
+
+// Put stores a copy of body.
+func (s *MemoryStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("read object body: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{data: data, contentType: contentType, modified: time.Now().UTC()}
+	return nil
+}
+
+// Get returns a reader over the stored bytes.
+func (s *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	s.mu.RLock()
+	obj, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+
+	sum := md5.Sum(obj.data)
+	return io.NopCloser(bytes.NewReader(obj.data)), &ObjectInfo{
+		Key:          key,
+		Size:         int64(len(obj.data)),
+		ContentType:  obj.contentType,
+		ETag:         hex.EncodeToString(sum[:]),
+		LastModified: obj.modified,
+	}, nil
+}
+
+// Delete removes an object.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+// PresignGet returns a fake URL that encodes the key and expiry.
+func (s *MemoryStore) PresignGet(ctx context.Context, key string, ttl time.Duration, downloadName string) (string, error) {
+	s.mu.RLock()
+	_, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return "", ErrNotFound
+	}
+	return fmt.Sprintf("memory://objects/%s?expires=%d", url.PathEscape(key), time.Now().Add(ttl).Unix()), nil
+}
+
+// Keys lists stored keys in order.
+func (s *MemoryStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
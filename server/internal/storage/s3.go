@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/johnrirwin/flyingforge/internal/config"
+)
+
+// S3Store keeps objects in an S3 bucket or an S3-compatible store (MinIO, R2, ...).
+type S3Store struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+}
+
+// NewS3StoreFromConfig creates an S3 store from storage config. Static keys are
+// used when configured; otherwise the ambient AWS credential chain applies.
+func NewS3StoreFromConfig(ctx context.Context, cfg config.StorageConfig) (*S3Store, error) {
+	if cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("S3_BUCKET is required for the s3 storage backend")
+	}
+
+	loadOptions := []func(*awsconfig.LoadOptions) error{}
+	if cfg.S3Region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(cfg.S3Region))
+	}
+	if cfg.S3AccessKeyID != "" {
+		loadOptions = append(loadOptions, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.S3AccessKeyID, cfg.S3SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	if awsCfg.Region == "" {
+		// S3-compatible stores ignore the region but request signing needs one
+		awsCfg.Region = "us-east-1"
+	}
+
+	return NewS3Store(s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.S3Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.S3Endpoint)
+		}
+		o.UsePathStyle = cfg.S3ForcePathStyle
+		// Many S3-compatible stores reject the newer default integrity checksums
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	}), cfg.S3Bucket), nil
+}
+
+// NewS3Store wraps an existing S3 client.
+func NewS3Store(client *s3.Client, bucket string) *S3Store {
+	return &S3Store{
+		client:  client,
+		presign: s3.NewPresignClient(client),
+		bucket:  bucket,
+	}
+}
+
+// Put uploads an object. Non-seekable bodies are spooled to a temp file first
+// because request signing over plain HTTP needs to read the payload twice.
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	seeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		tmp, err := os.CreateTemp("", "s3-upload-*")
+		if err != nil {
+			return fmt.Errorf("spool object: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+
+		if size, err = io.Copy(tmp, body); err != nil {
+			return fmt.Errorf("spool object: %w", err)
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("spool object: %w", err)
+		}
+		seeker = tmp
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   seeker,
+	}
+	if size >= 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get streams an object from the bucket.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("get object %s: %w", key, err)
+	}
+
+	info := &ObjectInfo{
+		Key:         key,
+		Size:        -1,
+		ContentType: aws.ToString(output.ContentType),
+		ETag:        strings.Trim(aws.ToString(output.ETag), `"`),
+	}
+	if output.ContentLength != nil {
+		info.Size = *output.ContentLength
+	}
+	if output.LastModified != nil {
+		info.LastModified = *output.LastModified
+	}
+	return output.Body, info, nil
+}
+
+// Delete removes an object. S3 treats deleting a missing key as success.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("delete object %s: %w", key, err)
+	}
+	return nil
+}
+
+// PresignGet returns a signed GET URL valid for ttl.
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration, downloadName string) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if downloadName != "" {
+		input.ResponseContentDisposition = aws.String(attachmentDisposition(downloadName))
+	}
+
+	req, err := s.presign.PresignGetObject(ctx, input, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("presign object %s: %w", key, err)
+	}
+	return req.URL, nil
+}
+
+func isS3NotFound(err error) bool {
+	var noSuchKey *s3types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return true
+	}
+	var notFound *s3types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == 404
+}
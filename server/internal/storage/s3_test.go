@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/config"
+)
+
+// fakeS3 is a minimal path-style S3 endpoint: PUT, GET and DELETE on /{bucket}/{key}
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	types   map[string]string
+	queries []string
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := "/" + f.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+	f.queries = append(f.queries, r.URL.RawQuery)
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		f.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"etag-1"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>missing</Message></Error>`)
+			return
+		}
+		if cd := r.URL.Query().Get("response-content-disposition"); cd != "" {
+			w.Header().Set("Content-Disposition", cd)
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Header().Set("ETag", `"etag-1"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3Store(t *testing.T) (*S3Store, *fakeS3, *httptest.Server) {
+	t.Helper()
+	fake := newFakeS3("uploads")
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	store, err := NewS3StoreFromConfig(context.Background(), config.StorageConfig{
+		Backend:           "s3",
+		S3Bucket:          "uploads",
+		S3Region:          "us-east-1",
+		S3Endpoint:        server.URL,
+		S3AccessKeyID:     "test",
+		S3SecretAccessKey: "secret",
+		S3ForcePathStyle:  true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3StoreFromConfig() error = %v", err)
+	}
+	return store, fake, server
+}
+
+func TestS3Store_RoundTrip(t *testing.T) {
+	store, fake, _ := newTestS3Store(t)
+	ctx := context.Background()
+
+	// A non-seekable body exercises the temp-file spooling path
+	body := io.MultiReader(strings.NewReader("hello "), strings.NewReader("world"))
+	if err := store.Put(ctx, "images/user-1/abc", body, -1, "image/png"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if string(fake.objects["images/user-1/abc"]) != "hello world" || fake.types["images/user-1/abc"] != "image/png" {
+		t.Fatalf("stored object = %q (%s)", fake.objects["images/user-1/abc"], fake.types["images/user-1/abc"])
+	}
+
+	rc, info, err := store.Get(ctx, "images/user-1/abc")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "hello world" || info.Size != 11 || info.ETag != "etag-1" {
+		t.Errorf("Get() = %q, %+v", data, info)
+	}
+
+	if err := store.Delete(ctx, "images/user-1/abc"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, _, err := store.Get(ctx, "images/user-1/abc"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3Store_PresignGet(t *testing.T) {
+	store, _, server := newTestS3Store(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "radio_backups/r1/b1/models.zip", strings.NewReader("PK"), 2, "application/zip"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	url, err := store.PresignGet(ctx, "radio_backups/r1/b1/models.zip", 5*time.Minute, `my "backup".zip`)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	if !strings.HasPrefix(url, server.URL+"/uploads/radio_backups/r1/b1/models.zip?") ||
+		!strings.Contains(url, "X-Amz-Signature=") || !strings.Contains(url, "X-Amz-Expires=300") {
+		t.Fatalf("PresignGet() = %s, want signed path-style URL expiring in 300s", url)
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET presigned URL error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Disposition") != `attachment; filename="my _backup_.zip"` {
+		t.Errorf("presigned GET = %d, Content-Disposition %q", resp.StatusCode, resp.Header.Get("Content-Disposition"))
+	}
+}
+
+func TestNewS3StoreFromConfig_RequiresBucket(t *testing.T) {
+	if _, err := NewS3StoreFromConfig(context.Background(), config.StorageConfig{Backend: "s3"}); err == nil {
+		t.Fatal("NewS3StoreFromConfig() expected error without bucket")
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when an object does not exist.
+	ErrNotFound = errors.New("object not found")
+	// ErrPresignUnsupported is returned by backends that cannot issue direct download URLs.
+	ErrPresignUnsupported = errors.New("direct download URLs are not supported by this storage backend")
+)
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64 // -1 when unknown
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// ObjectStore keeps user file blobs (image assets, radio backups) outside the database.
+type ObjectStore interface {
+	// Put stores body under key, replacing any existing object. Pass an
+	// io.ReadSeeker when possible so backends can retry without buffering.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens an object for streaming. Callers must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a short-lived URL that downloads the object directly.
+	// downloadName, if set, is sent as the attachment file name.
+	PresignGet(ctx context.Context, key string, ttl time.Duration, downloadName string) (string, error)
+}
+
+// FileOpener is implemented by stores that keep objects on the local filesystem,
+// letting callers that need random access (e.g. zip parsing) skip a temp copy.
+type FileOpener interface {
+	OpenFile(key string) (ReadAtCloser, int64, error)
+}
+
+// ReadAtCloser is a random-access object reader.
+type ReadAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// ValidateKey rejects keys that are empty, absolute or escape their prefix.
+func ValidateKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("object key is required")
+	}
+	if strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	if path.Clean(key) != key {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	return nil
+}
+
+// attachmentDisposition builds a Content-Disposition header for downloads.
+func attachmentDisposition(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '"' || r == '\\' || r < 0x20 || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, name)
+	return fmt.Sprintf(`attachment; filename="%s"`, name)
+}
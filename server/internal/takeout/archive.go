@@ -0,0 +1,638 @@
+package takeout
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// listPageSize is the page size used to walk paginated store listings
+const listPageSize = 100
+
+// ProfileReader reads the user's profile
+type ProfileReader interface {
+	GetByID(ctx context.Context, id string) (*models.User, error)
+}
+
+// InventoryReader reads inventory items
+type InventoryReader interface {
+	List(ctx context.Context, userID string, params models.InventoryFilterParams) (*models.InventoryResponse, error)
+}
+
+// AircraftReader reads aircraft with their components, decrypted receiver
+// settings and galleries
+type AircraftReader interface {
+	ListByUserID(ctx context.Context, userID string) ([]*models.Aircraft, error)
+	GetDetails(ctx context.Context, id string, userID string) (*models.AircraftDetailsResponse, error)
+	ListGallery(ctx context.Context, id string) ([]models.GalleryImage, error)
+}
+
+// FCConfigReader reads flight controller configs and tuning snapshots
+type FCConfigReader interface {
+	ListConfigs(ctx context.Context, userID string, params models.FCConfigListParams) (*models.FCConfigListResponse, error)
+	GetConfig(ctx context.Context, id string, userID string) (*models.FlightControllerConfig, error)
+	ListAllTuningSnapshots(ctx context.Context, userID string) ([]models.AircraftTuningSnapshot, error)
+}
+
+// BatteryReader reads batteries and their logs
+type BatteryReader interface {
+	ListAll(ctx context.Context, userID string) ([]models.Battery, error)
+	ListAllLogs(ctx context.Context, userID string) ([]models.BatteryLog, error)
+}
+
+// MaintenanceReader reads an aircraft's crash, repair and maintenance log.
+// It does not check ownership, so it is only asked about aircraft the
+// aircraft section already listed for the user.
+type MaintenanceReader interface {
+	ListMaintenanceEvents(ctx context.Context, aircraftID string) ([]models.MaintenanceEvent, error)
+}
+
+// FlightReader reads logged flight sessions
+type FlightReader interface {
+	List(ctx context.Context, userID string, params models.FlightSessionListParams) (*models.FlightSessionListResponse, error)
+}
+
+// OrderReader reads tracked orders with their linked items
+type OrderReader interface {
+	List(ctx context.Context, userID string, params models.OrderListParams) (*models.OrderListResponse, error)
+}
+
+// RadioReader reads radios and their backup files
+type RadioReader interface {
+	ListRadios(ctx context.Context, userID string, params models.RadioListParams) (*models.RadioListResponse, error)
+	ListBackups(ctx context.Context, radioID string, userID string, params models.RadioBackupListParams) (*models.RadioBackupListResponse, error)
+	GetBackupFile(ctx context.Context, backupID string, radioID string, userID string) (io.ReadCloser, *models.RadioBackup, error)
+}
+
+// BuildReader reads the user's builds and their galleries
+type BuildReader interface {
+	ListByOwner(ctx context.Context, ownerUserID string, params models.BuildListParams) (*models.BuildListResponse, error)
+	ListGallery(ctx context.Context, userID string, buildID string) ([]models.GalleryImage, error)
+}
+
+// CatalogReader reads the gear catalog entries the user's inventory and
+// builds link to, so an import can match them on another instance
+type CatalogReader interface {
+	Get(ctx context.Context, id string) (*models.GearCatalogItem, error)
+}
+
+// ImageReader reads the user's stored image originals
+type ImageReader interface {
+	ListByOwner(ctx context.Context, ownerUserID string) ([]models.ImageAsset, error)
+	Open(ctx context.Context, imageID string) (*images.ImageStream, error)
+}
+
+// Sources are where an export reads the user's data from. A nil source
+// leaves its section out of the archive.
+type Sources struct {
+	Profile     ProfileReader
+	Inventory   InventoryReader
+	Aircraft    AircraftReader
+	Maintenance MaintenanceReader
+	FCConfigs   FCConfigReader
+	Batteries   BatteryReader
+	Flights     FlightReader
+	Orders      OrderReader
+	Radios      RadioReader
+	Builds      BuildReader
+	Catalog     CatalogReader
+	Images      ImageReader
+}
+
+// archiveWriter adds files to a ZIP archive and records them in the manifest
+type archiveWriter struct {
+	zip      *zip.Writer
+	manifest *models.TakeoutManifest
+
+	aircraftIDs []string                       // Aircraft written by the aircraft section
+	catalogIDs  []string                       // Catalog entries referenced so far, in first-seen order
+	seen        map[string]bool                // Catalog IDs already in catalogIDs
+	gallery     map[string]models.GalleryImage // Gallery placement of aircraft and build images, by asset ID
+}
+
+// WriteArchive writes the user's data to w as a ZIP archive with
+// manifest.json at its root, and returns the manifest.
+func (s *Service) WriteArchive(ctx context.Context, w io.Writer, exportID string, userID string) (*models.TakeoutManifest, error) {
+	archive := &archiveWriter{
+		zip: zip.NewWriter(w),
+		manifest: &models.TakeoutManifest{
+			Format:    models.TakeoutFormat,
+			Version:   models.TakeoutFormatVersion,
+			ExportID:  exportID,
+			UserID:    userID,
+			CreatedAt: s.now().UTC(),
+			Counts:    map[string]int{},
+			Files:     []models.TakeoutFile{},
+		},
+		seen:    map[string]bool{},
+		gallery: map[string]models.GalleryImage{},
+	}
+
+	sections := []func(context.Context, *archiveWriter, string) error{
+		s.writeProfile,
+		s.writeInventory,
+		s.writeAircraft,
+		s.writeMaintenance,
+		s.writeFCConfigs,
+		s.writeBatteries,
+		s.writeFlights,
+		s.writeOrders,
+		s.writeRadios,
+		s.writeBuilds,
+		s.writeCatalog,
+		s.writeImages,
+	}
+	for _, section := range sections {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := section(ctx, archive, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.finish(); err != nil {
+		return nil, err
+	}
+	return archive.manifest, nil
+}
+
+func (s *Service) writeProfile(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Profile == nil {
+		return nil
+	}
+	user, err := s.sources.Profile.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("read profile: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("user %s not found", userID)
+	}
+	return archive.addJSON("profile.json", models.TakeoutFileProfile, user)
+}
+
+func (s *Service) writeInventory(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Inventory == nil {
+		return nil
+	}
+	items := []models.InventoryItem{}
+	for {
+		page, err := s.sources.Inventory.List(ctx, userID, models.InventoryFilterParams{Limit: listPageSize, Offset: len(items)})
+		if err != nil {
+			return fmt.Errorf("read inventory: %w", err)
+		}
+		items = append(items, page.Items...)
+		if len(page.Items) < listPageSize || len(items) >= page.TotalCount {
+			break
+		}
+	}
+	// Tracked units travel inside their item
+	units := 0
+	for _, item := range items {
+		archive.referenceCatalog(item.CatalogID)
+		units += len(item.Units)
+	}
+	archive.manifest.Counts["inventory"] = len(items)
+	archive.manifest.Counts["inventoryUnits"] = units
+	return archive.addJSON("inventory.json", models.TakeoutFileInventory, items)
+}
+
+func (s *Service) writeAircraft(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Aircraft == nil {
+		return nil
+	}
+	aircraft, err := s.sources.Aircraft.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("read aircraft: %w", err)
+	}
+
+	details := make([]models.AircraftDetailsResponse, 0, len(aircraft))
+	for _, a := range aircraft {
+		detail, err := s.sources.Aircraft.GetDetails(ctx, a.ID, userID)
+		if err != nil {
+			return fmt.Errorf("read aircraft %s: %w", a.ID, err)
+		}
+		if detail == nil {
+			continue
+		}
+		details = append(details, *detail)
+		archive.aircraftIDs = append(archive.aircraftIDs, a.ID)
+
+		gallery, err := s.sources.Aircraft.ListGallery(ctx, a.ID)
+		if err != nil {
+			return fmt.Errorf("read gallery for aircraft %s: %w", a.ID, err)
+		}
+		archive.placeImages(gallery, a.ImageAssetID)
+	}
+	archive.manifest.Counts["aircraft"] = len(details)
+	return archive.addJSON("aircraft.json", models.TakeoutFileAircraft, details)
+}
+
+func (s *Service) writeMaintenance(ctx context.Context, archive *archiveWriter, _ string) error {
+	if s.sources.Maintenance == nil || s.sources.Aircraft == nil {
+		return nil
+	}
+	events := []models.MaintenanceEvent{}
+	for _, aircraftID := range archive.aircraftIDs {
+		page, err := s.sources.Maintenance.ListMaintenanceEvents(ctx, aircraftID)
+		if err != nil {
+			return fmt.Errorf("read maintenance events for aircraft %s: %w", aircraftID, err)
+		}
+		events = append(events, page...)
+	}
+	archive.manifest.Counts["maintenanceEvents"] = len(events)
+	return archive.addJSON("maintenance_events.json", models.TakeoutFileMaintenanceEvents, events)
+}
+
+func (s *Service) writeFCConfigs(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.FCConfigs == nil {
+		return nil
+	}
+	summaries := []models.FlightControllerConfig{}
+	for {
+		page, err := s.sources.FCConfigs.ListConfigs(ctx, userID, models.FCConfigListParams{Limit: listPageSize, Offset: len(summaries)})
+		if err != nil {
+			return fmt.Errorf("read FC configs: %w", err)
+		}
+		summaries = append(summaries, page.Configs...)
+		if len(page.Configs) < listPageSize || len(summaries) >= page.TotalCount {
+			break
+		}
+	}
+
+	// Listings leave out the raw dump, so each config is loaded in full
+	configs := make([]models.FlightControllerConfig, 0, len(summaries))
+	for _, summary := range summaries {
+		config, err := s.sources.FCConfigs.GetConfig(ctx, summary.ID, userID)
+		if err != nil {
+			return fmt.Errorf("read FC config %s: %w", summary.ID, err)
+		}
+		if config == nil {
+			continue
+		}
+		configs = append(configs, *config)
+		if config.RawCLIDump != "" {
+			entry := models.TakeoutFile{
+				Path:        "fc_configs/" + config.ID + ".txt",
+				Kind:        models.TakeoutFileFCConfigDump,
+				ContentType: "text/plain",
+				RecordID:    config.ID,
+			}
+			if err := archive.add(entry, strings.NewReader(config.RawCLIDump)); err != nil {
+				return err
+			}
+		}
+	}
+	archive.manifest.Counts["fcConfigs"] = len(configs)
+	if err := archive.addJSON("fc_configs.json", models.TakeoutFileFCConfigs, configs); err != nil {
+		return err
+	}
+
+	snapshots, err := s.sources.FCConfigs.ListAllTuningSnapshots(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("read tuning snapshots: %w", err)
+	}
+	archive.manifest.Counts["tuningSnapshots"] = len(snapshots)
+	return archive.addJSON("tuning_snapshots.json", models.TakeoutFileTuningSnapshots, snapshots)
+}
+
+func (s *Service) writeBatteries(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Batteries == nil {
+		return nil
+	}
+	batteries, err := s.sources.Batteries.ListAll(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("read batteries: %w", err)
+	}
+	logs, err := s.sources.Batteries.ListAllLogs(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("read battery logs: %w", err)
+	}
+	archive.manifest.Counts["batteries"] = len(batteries)
+	archive.manifest.Counts["batteryLogs"] = len(logs)
+	if err := archive.addJSON("batteries.json", models.TakeoutFileBatteries, batteries); err != nil {
+		return err
+	}
+	return archive.addJSON("battery_logs.json", models.TakeoutFileBatteryLogs, logs)
+}
+
+func (s *Service) writeFlights(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Flights == nil {
+		return nil
+	}
+	sessions := []models.FlightSession{}
+	for {
+		page, err := s.sources.Flights.List(ctx, userID, models.FlightSessionListParams{Limit: listPageSize, Offset: len(sessions)})
+		if err != nil {
+			return fmt.Errorf("read flight sessions: %w", err)
+		}
+		sessions = append(sessions, page.Sessions...)
+		if len(page.Sessions) < listPageSize || len(sessions) >= page.TotalCount {
+			break
+		}
+	}
+	archive.manifest.Counts["flightSessions"] = len(sessions)
+	return archive.addJSON("flight_sessions.json", models.TakeoutFileFlightSessions, sessions)
+}
+
+func (s *Service) writeOrders(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Orders == nil {
+		return nil
+	}
+	orders := []models.Order{}
+	for {
+		page, err := s.sources.Orders.List(ctx, userID, models.OrderListParams{IncludeArchived: true, Limit: listPageSize, Offset: len(orders)})
+		if err != nil {
+			return fmt.Errorf("read orders: %w", err)
+		}
+		orders = append(orders, page.Orders...)
+		if len(page.Orders) < listPageSize || len(orders) >= page.TotalCount {
+			break
+		}
+	}
+	items := 0
+	for _, order := range orders {
+		items += len(order.Items)
+	}
+	archive.manifest.Counts["orders"] = len(orders)
+	archive.manifest.Counts["orderItems"] = items
+	return archive.addJSON("orders.json", models.TakeoutFileOrders, orders)
+}
+
+func (s *Service) writeRadios(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Radios == nil {
+		return nil
+	}
+	radios := []models.Radio{}
+	for {
+		page, err := s.sources.Radios.ListRadios(ctx, userID, models.RadioListParams{Limit: listPageSize, Offset: len(radios)})
+		if err != nil {
+			return fmt.Errorf("read radios: %w", err)
+		}
+		radios = append(radios, page.Radios...)
+		if len(page.Radios) < listPageSize || len(radios) >= page.TotalCount {
+			break
+		}
+	}
+
+	entries := make([]models.TakeoutRadio, 0, len(radios))
+	backupCount := 0
+	for _, radio := range radios {
+		backups := []models.RadioBackup{}
+		for {
+			page, err := s.sources.Radios.ListBackups(ctx, radio.ID, userID, models.RadioBackupListParams{Limit: listPageSize, Offset: len(backups)})
+			if err != nil {
+				return fmt.Errorf("read backups for radio %s: %w", radio.ID, err)
+			}
+			backups = append(backups, page.Backups...)
+			if len(page.Backups) < listPageSize || len(backups) >= page.TotalCount {
+				break
+			}
+		}
+
+		for _, backup := range backups {
+			if err := s.writeRadioBackup(ctx, archive, radio.ID, backup, userID); err != nil {
+				return err
+			}
+		}
+		backupCount += len(backups)
+		entries = append(entries, models.TakeoutRadio{Radio: radio, Backups: backups})
+	}
+	archive.manifest.Counts["radios"] = len(entries)
+	archive.manifest.Counts["radioBackups"] = backupCount
+	return archive.addJSON("radios.json", models.TakeoutFileRadios, entries)
+}
+
+func (s *Service) writeRadioBackup(ctx context.Context, archive *archiveWriter, radioID string, backup models.RadioBackup, userID string) error {
+	file, _, err := s.sources.Radios.GetBackupFile(ctx, backup.ID, radioID, userID)
+	if err != nil {
+		// A missing file should not cost the user the rest of their export
+		archive.warn("radio backup %s: %v", backup.ID, err)
+		return nil
+	}
+	defer file.Close()
+
+	entry := models.TakeoutFile{
+		Path:        "radio_backups/" + backup.ID + "/" + safeFileName(backup.FileName, "backup.bin"),
+		Kind:        models.TakeoutFileRadioBackup,
+		ContentType: "application/octet-stream",
+		RecordID:    backup.ID,
+		ParentID:    radioID,
+	}
+	return archive.add(entry, file)
+}
+
+func (s *Service) writeBuilds(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Builds == nil {
+		return nil
+	}
+	builds := []models.Build{}
+	for {
+		page, err := s.sources.Builds.ListByOwner(ctx, userID, models.BuildListParams{Limit: listPageSize, Offset: len(builds)})
+		if err != nil {
+			return fmt.Errorf("read builds: %w", err)
+		}
+		builds = append(builds, page.Builds...)
+		if len(page.Builds) < listPageSize || len(builds) >= page.TotalCount {
+			break
+		}
+	}
+	for _, build := range builds {
+		for _, part := range build.Parts {
+			archive.referenceCatalog(part.CatalogItemID)
+		}
+		gallery, err := s.sources.Builds.ListGallery(ctx, userID, build.ID)
+		if err != nil {
+			return fmt.Errorf("read gallery for build %s: %w", build.ID, err)
+		}
+		archive.placeImages(gallery, build.ImageAssetID)
+	}
+	archive.manifest.Counts["builds"] = len(builds)
+	return archive.addJSON("builds.json", models.TakeoutFileBuilds, builds)
+}
+
+func (s *Service) writeCatalog(ctx context.Context, archive *archiveWriter, _ string) error {
+	if s.sources.Catalog == nil {
+		return nil
+	}
+	items := make([]models.GearCatalogItem, 0, len(archive.catalogIDs))
+	for _, id := range archive.catalogIDs {
+		item, err := s.sources.Catalog.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("read catalog item %s: %w", id, err)
+		}
+		if item == nil {
+			archive.warn("catalog item %s: not found", id)
+			continue
+		}
+		items = append(items, *item)
+	}
+	archive.manifest.Counts["catalogItems"] = len(items)
+	return archive.addJSON("catalog.json", models.TakeoutFileCatalog, items)
+}
+
+func (s *Service) writeImages(ctx context.Context, archive *archiveWriter, userID string) error {
+	if s.sources.Images == nil {
+		return nil
+	}
+	assets, err := s.sources.Images.ListByOwner(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("read images: %w", err)
+	}
+
+	written := 0
+	for _, asset := range assets {
+		ok, err := s.writeImage(ctx, archive, asset)
+		if err != nil {
+			return err
+		}
+		if ok {
+			written++
+		}
+	}
+	archive.manifest.Counts["images"] = written
+	return nil
+}
+
+func (s *Service) writeImage(ctx context.Context, archive *archiveWriter, asset models.ImageAsset) (bool, error) {
+	stream, err := s.sources.Images.Open(ctx, asset.ID)
+	if err != nil || stream == nil {
+		archive.warn("image %s: original not found", asset.ID)
+		return false, nil
+	}
+	defer stream.Body.Close()
+
+	body := bufio.NewReader(stream.Body)
+	head, _ := body.Peek(512)
+	contentType := http.DetectContentType(head)
+
+	entry := models.TakeoutFile{
+		Path:        "images/" + asset.ID + imageExtension(contentType),
+		Kind:        models.TakeoutFileImage,
+		ContentType: contentType,
+		RecordID:    asset.ID,
+		EntityType:  asset.EntityType,
+		EntityID:    asset.EntityID,
+		ImageStatus: asset.Status,
+	}
+	if placement, ok := archive.gallery[asset.ID]; ok {
+		entry.Cover = placement.IsCover
+		entry.Caption = placement.Caption
+		entry.Position = placement.Position
+	}
+	if err := archive.add(entry, body); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// finish writes the manifest and closes the archive. The manifest goes last
+// so it can list every other file.
+func (a *archiveWriter) finish() error {
+	manifest, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	file, err := a.zip.Create("manifest.json")
+	if err != nil {
+		return fmt.Errorf("add manifest: %w", err)
+	}
+	if _, err := file.Write(manifest); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err := a.zip.Close(); err != nil {
+		return fmt.Errorf("finish archive: %w", err)
+	}
+	return nil
+}
+
+// addJSON adds an indented JSON document
+func (a *archiveWriter) addJSON(name string, kind models.TakeoutFileKind, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	entry := models.TakeoutFile{Path: name, Kind: kind, ContentType: "application/json"}
+	return a.add(entry, strings.NewReader(string(data)))
+}
+
+// add copies body into the archive and records its size and checksum
+func (a *archiveWriter) add(entry models.TakeoutFile, body io.Reader) error {
+	file, err := a.zip.Create(entry.Path)
+	if err != nil {
+		return fmt.Errorf("add %s: %w", entry.Path, err)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), body)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", entry.Path, err)
+	}
+	entry.Size = size
+	entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	a.manifest.Files = append(a.manifest.Files, entry)
+	return nil
+}
+
+// referenceCatalog records a catalog entry to include in catalog.json
+func (a *archiveWriter) referenceCatalog(id string) {
+	id = strings.TrimSpace(id)
+	if id == "" || a.seen[id] {
+		return
+	}
+	a.seen[id] = true
+	a.catalogIDs = append(a.catalogIDs, id)
+}
+
+// placeImages records where gallery images sit so the import can rebuild the
+// gallery. coverAssetID covers images set before galleries existed.
+func (a *archiveWriter) placeImages(gallery []models.GalleryImage, coverAssetID string) {
+	for _, image := range gallery {
+		a.gallery[image.ImageAssetID] = image
+	}
+	if _, ok := a.gallery[coverAssetID]; coverAssetID != "" && !ok {
+		a.gallery[coverAssetID] = models.GalleryImage{ImageAssetID: coverAssetID, IsCover: true}
+	}
+}
+
+func (a *archiveWriter) warn(format string, args ...interface{}) {
+	a.manifest.Warnings = append(a.manifest.Warnings, fmt.Sprintf(format, args...))
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// safeFileName reduces an uploaded file name to one safe inside a ZIP path
+func safeFileName(name string, fallback string) string {
+	name = unsafeFileNameChars.ReplaceAllString(path.Base(strings.ReplaceAll(name, "\\", "/")), "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func imageExtension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".bin"
+	}
+}
@@ -0,0 +1,276 @@
+package takeout
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
+)
+
+const (
+	// MaxImportArchiveSize is the largest export archive accepted for import
+	MaxImportArchiveSize = 1 << 30
+
+	// importTimeout bounds how long one import may run. Jobs still unfinished
+	// well past it were lost to a restart and are failed by cleanup.
+	importTimeout = 30 * time.Minute
+
+	maxListedImports = 10
+)
+
+// ImportJobStore defines the interface for import job storage operations
+type ImportJobStore interface {
+	Create(ctx context.Context, userID string, sourceExportID string) (*models.DataImport, error)
+	Get(ctx context.Context, id string, userID string) (*models.DataImport, error)
+	ListByUser(ctx context.Context, userID string, limit int) ([]models.DataImport, error)
+	GetActive(ctx context.Context, userID string) (*models.DataImport, error)
+	MarkRunning(ctx context.Context, id string) error
+	MarkCompleted(ctx context.Context, id string, result *models.DataImportResult) error
+	MarkFailed(ctx context.Context, id string, message string, result *models.DataImportResult) error
+	FailStale(ctx context.Context, cutoff time.Time, message string) ([]models.DataImport, error)
+}
+
+// Importer restores export archives into a user's account, possibly on a
+// different FlyingForge instance than the one that wrote them
+type Importer struct {
+	jobs    ImportJobStore
+	targets Targets
+	blobs   storage.ObjectStore
+	logger  *logging.Logger
+	now     func() time.Time
+	start   func(job func()) // Runs a job in the background
+
+	mu      sync.Mutex
+	running map[string]bool // Users with an import queued or running in this process
+}
+
+// NewImporter creates a new import service. A nil object store keeps
+// uploaded archives on local disk under DefaultLocalStorageRoot.
+func NewImporter(jobs *database.DataImportStore, targets Targets, blobs storage.ObjectStore, logger *logging.Logger) *Importer {
+	if blobs == nil {
+		blobs = storage.NewLocalStore(DefaultLocalStorageRoot)
+	}
+	return &Importer{
+		jobs:    jobs,
+		targets: targets,
+		blobs:   blobs,
+		running: map[string]bool{},
+		logger:  logger,
+		now:     time.Now,
+		start:   func(job func()) { go job() },
+	}
+}
+
+// RequestImport checks that archive is a FlyingForge export, stores it and
+// queues it to be restored into the user's account. Only one import per
+// user runs at a time, and an archive that was already imported into the
+// account is turned away rather than restored twice.
+func (s *Importer) RequestImport(ctx context.Context, userID string, archive io.ReaderAt, size int64) (*models.DataImport, error) {
+	if size <= 0 {
+		return nil, &ServiceError{Message: "archive is empty"}
+	}
+	if size > MaxImportArchiveSize {
+		return nil, &ServiceError{Message: fmt.Sprintf("archive exceeds maximum size (%d bytes)", MaxImportArchiveSize)}
+	}
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, &ServiceError{Message: "archive is not a valid ZIP file"}
+	}
+	manifest, err := readManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+	exportID := strings.TrimSpace(manifest.ExportID)
+	if exportID == "" {
+		return nil, &ServiceError{Message: "manifest.json has no export ID"}
+	}
+
+	if !s.claim(userID) {
+		return nil, &ServiceError{Message: "an import is already in progress"}
+	}
+	queued := false
+	defer func() {
+		if !queued {
+			s.release(userID)
+		}
+	}()
+
+	active, err := s.jobs.GetActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, &ServiceError{Message: "an import is already in progress"}
+	}
+
+	job, err := s.jobs.Create(ctx, userID, exportID)
+	if errors.Is(err, database.ErrDataImportDuplicate) {
+		return nil, &ServiceError{Message: "this archive has already been imported into your account"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.blobs.Put(ctx, importObjectKey(userID, job.ID), io.NewSectionReader(archive, 0, size), size, "application/zip"); err != nil {
+		if markErr := s.jobs.MarkFailed(ctx, job.ID, "failed to store the uploaded archive", nil); markErr != nil {
+			s.logger.Error("Failed to record data import failure", logging.WithField("error", markErr.Error()))
+		}
+		return nil, fmt.Errorf("store import archive: %w", err)
+	}
+
+	s.logger.Info("Queued data import", logging.WithFields(map[string]interface{}{
+		"userId":   userID,
+		"importId": job.ID,
+		"size":     size,
+	}))
+
+	importID := job.ID
+	queued = true
+	s.start(func() {
+		defer s.release(userID)
+		s.run(importID, userID)
+	})
+	return job, nil
+}
+
+// claim reserves the user's import slot, reporting false when the user
+// already has an import queued or running
+func (s *Importer) claim(userID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[userID] {
+		return false
+	}
+	s.running[userID] = true
+	return true
+}
+
+func (s *Importer) release(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, userID)
+}
+
+// ListImports returns the user's most recent imports
+func (s *Importer) ListImports(ctx context.Context, userID string) ([]models.DataImport, error) {
+	return s.jobs.ListByUser(ctx, userID, maxListedImports)
+}
+
+// GetImport returns one of the user's imports
+func (s *Importer) GetImport(ctx context.Context, userID string, id string) (*models.DataImport, error) {
+	job, err := s.jobs.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, &ServiceError{Message: "import not found"}
+	}
+	return job, nil
+}
+
+// CleanupStale fails imports that were lost to a restart and deletes their
+// uploaded archives. It returns how many imports were failed.
+func (s *Importer) CleanupStale(ctx context.Context) (int, error) {
+	stale, err := s.jobs.FailStale(ctx, s.now().Add(-2*importTimeout), "import was interrupted; please upload the archive again")
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range stale {
+		s.deleteUpload(ctx, job.UserID, job.ID)
+	}
+	return len(stale), nil
+}
+
+// importObjectKey is the object storage key for an uploaded archive
+func importObjectKey(userID, importID string) string {
+	return fmt.Sprintf("imports/%s/%s.zip", userID, importID)
+}
+
+func (s *Importer) deleteUpload(ctx context.Context, userID, importID string) {
+	if err := s.blobs.Delete(ctx, importObjectKey(userID, importID)); err != nil {
+		s.logger.Warn("Failed to delete uploaded import archive", logging.WithFields(map[string]interface{}{
+			"importId": importID,
+			"error":    err.Error(),
+		}))
+	}
+}
+
+// run restores a queued import and records the outcome. The uploaded
+// archive is deleted either way.
+func (s *Importer) run(importID string, userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+	defer s.deleteUpload(context.Background(), userID, importID)
+
+	fields := map[string]interface{}{"importId": importID, "userId": userID}
+	if err := s.jobs.MarkRunning(ctx, importID); err != nil {
+		s.logger.Error("Failed to start data import", logging.WithFields(fields), logging.WithField("error", err.Error()))
+		return
+	}
+
+	result, err := s.restoreUpload(ctx, importID, userID)
+	statusCtx, statusCancel := context.WithTimeout(context.Background(), jobStatusTimeout)
+	defer statusCancel()
+	if err != nil {
+		s.logger.Error("Data import failed", logging.WithFields(fields), logging.WithField("error", err.Error()))
+		message := "import failed; please try again"
+		if svcErr, ok := err.(*ServiceError); ok {
+			message = svcErr.Message
+		}
+		if markErr := s.jobs.MarkFailed(statusCtx, importID, message, result); markErr != nil {
+			s.logger.Error("Failed to record data import failure", logging.WithField("error", markErr.Error()))
+		}
+		return
+	}
+
+	if err := s.jobs.MarkCompleted(statusCtx, importID, result); err != nil {
+		s.logger.Error("Failed to record finished data import", logging.WithFields(fields), logging.WithField("error", err.Error()))
+		return
+	}
+
+	s.logger.Info("Data import completed", logging.WithFields(fields), logging.WithFields(map[string]interface{}{
+		"imported": result.Imported,
+		"warnings": len(result.Warnings),
+	}))
+}
+
+// restoreUpload copies the stored archive to a temp file, since ZIP
+// readers need random access, then restores it
+func (s *Importer) restoreUpload(ctx context.Context, importID string, userID string) (*models.DataImportResult, error) {
+	body, _, err := s.blobs.Get(ctx, importObjectKey(userID, importID))
+	if err != nil {
+		return nil, fmt.Errorf("open uploaded archive: %w", err)
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "flyingforge-import-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("create temp archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(body, MaxImportArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("copy uploaded archive: %w", err)
+	}
+	if size > MaxImportArchiveSize {
+		return nil, &ServiceError{Message: "archive exceeds maximum size"}
+	}
+
+	archive, err := openArchive(tmp, size)
+	if err != nil {
+		return nil, err
+	}
+	return s.restore(ctx, archive, userID)
+}
@@ -0,0 +1,499 @@
+package takeout
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// mockImportJobStore implements ImportJobStore in memory
+type mockImportJobStore struct {
+	imports map[string]*models.DataImport
+}
+
+func newMockImportJobStore() *mockImportJobStore {
+	return &mockImportJobStore{imports: map[string]*models.DataImport{}}
+}
+
+func (m *mockImportJobStore) Create(_ context.Context, userID string, sourceExportID string) (*models.DataImport, error) {
+	for _, job := range m.imports {
+		if job.UserID == userID && job.SourceExportID == sourceExportID && job.Status != models.DataImportFailed {
+			return nil, database.ErrDataImportDuplicate
+		}
+	}
+	job := &models.DataImport{ID: fmt.Sprintf("import-%d", len(m.imports)+1), UserID: userID, SourceExportID: sourceExportID, Status: models.DataImportPending, CreatedAt: time.Now()}
+	m.imports[job.ID] = job
+	copied := *job
+	return &copied, nil
+}
+
+func (m *mockImportJobStore) Get(_ context.Context, id string, userID string) (*models.DataImport, error) {
+	job, ok := m.imports[id]
+	if !ok || job.UserID != userID {
+		return nil, nil
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (m *mockImportJobStore) ListByUser(_ context.Context, userID string, _ int) ([]models.DataImport, error) {
+	jobs := []models.DataImport{}
+	for _, job := range m.imports {
+		if job.UserID == userID {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *mockImportJobStore) GetActive(_ context.Context, userID string) (*models.DataImport, error) {
+	for _, job := range m.imports {
+		if job.UserID == userID && (job.Status == models.DataImportPending || job.Status == models.DataImportRunning) {
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockImportJobStore) MarkRunning(_ context.Context, id string) error {
+	m.imports[id].Status = models.DataImportRunning
+	return nil
+}
+
+func (m *mockImportJobStore) MarkCompleted(_ context.Context, id string, result *models.DataImportResult) error {
+	m.imports[id].Status = models.DataImportCompleted
+	m.imports[id].Result = result
+	return nil
+}
+
+func (m *mockImportJobStore) MarkFailed(_ context.Context, id string, message string, result *models.DataImportResult) error {
+	m.imports[id].Status = models.DataImportFailed
+	m.imports[id].Error = message
+	m.imports[id].Result = result
+	return nil
+}
+
+func (m *mockImportJobStore) FailStale(_ context.Context, _ time.Time, _ string) ([]models.DataImport, error) {
+	return nil, nil
+}
+
+type fakeCatalog struct {
+	byKey   map[string]*models.GearCatalogItem
+	created []models.CreateGearCatalogParams
+}
+
+func (f *fakeCatalog) GetByCanonicalKey(_ context.Context, key string) (*models.GearCatalogItem, error) {
+	return f.byKey[key], nil
+}
+
+func (f *fakeCatalog) Create(_ context.Context, _ string, params models.CreateGearCatalogParams) (*models.GearCatalogCreateResponse, error) {
+	f.created = append(f.created, params)
+	item := &models.GearCatalogItem{ID: fmt.Sprintf("new-cat-%d", len(f.created)), Status: models.CatalogStatusPending}
+	return &models.GearCatalogCreateResponse{Item: item}, nil
+}
+
+type fakeInventory struct {
+	added []models.AddInventoryParams
+	units map[string][]models.AddInventoryUnitParams // By new item ID
+}
+
+func (f *fakeInventory) AddItem(_ context.Context, _ string, params models.AddInventoryParams) (*models.InventoryItem, error) {
+	f.added = append(f.added, params)
+	return &models.InventoryItem{ID: fmt.Sprintf("new-inv-%d", len(f.added))}, nil
+}
+
+func (f *fakeInventory) AddUnit(_ context.Context, _ string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error) {
+	f.units[itemID] = append(f.units[itemID], params)
+	return &models.InventoryUnit{ID: fmt.Sprintf("new-unit-%d", len(f.units[itemID])), InventoryItemID: itemID}, nil
+}
+
+type fakeAircraft struct {
+	created    int
+	components []models.SetComponentParams
+	settings   []models.SetReceiverSettingsParams
+	gallery    map[string][]models.AddGalleryImageParams
+}
+
+func (f *fakeAircraft) Create(_ context.Context, _ string, _ models.CreateAircraftParams) (*models.Aircraft, error) {
+	f.created++
+	return &models.Aircraft{ID: fmt.Sprintf("new-ac-%d", f.created)}, nil
+}
+
+func (f *fakeAircraft) SetComponent(_ context.Context, _ string, params models.SetComponentParams) (*models.AircraftComponent, error) {
+	f.components = append(f.components, params)
+	return &models.AircraftComponent{}, nil
+}
+
+func (f *fakeAircraft) SetReceiverSettings(_ context.Context, _ string, params models.SetReceiverSettingsParams) (*models.AircraftReceiverSettings, error) {
+	f.settings = append(f.settings, params)
+	return &models.AircraftReceiverSettings{}, nil
+}
+
+func (f *fakeAircraft) AddGalleryImage(_ context.Context, aircraftID string, _ string, params models.AddGalleryImageParams) (*models.ModerationDecision, *models.GalleryImage, error) {
+	if strings.Contains(string(params.ImageData), "unsafe") {
+		return &models.ModerationDecision{Status: models.ImageModerationRejected, Reason: "unsafe content"}, nil, nil
+	}
+	f.gallery[aircraftID] = append(f.gallery[aircraftID], params)
+	return &models.ModerationDecision{Status: models.ImageModerationApproved}, &models.GalleryImage{}, nil
+}
+
+type fakeFCConfigs struct {
+	configs   []models.FlightControllerConfig
+	snapshots []models.AircraftTuningSnapshot
+}
+
+func (f *fakeFCConfigs) SaveConfig(_ context.Context, _ string, config *models.FlightControllerConfig) error {
+	config.ID = fmt.Sprintf("new-cfg-%d", len(f.configs)+1)
+	f.configs = append(f.configs, *config)
+	return nil
+}
+
+func (f *fakeFCConfigs) SaveTuningSnapshot(_ context.Context, _ string, snapshot *models.AircraftTuningSnapshot) error {
+	f.snapshots = append(f.snapshots, *snapshot)
+	return nil
+}
+
+type fakeBuilds struct {
+	drafts []models.CreateBuildParams
+}
+
+func (f *fakeBuilds) CreateDraft(_ context.Context, _ string, params models.CreateBuildParams) (*models.Build, error) {
+	f.drafts = append(f.drafts, params)
+	return &models.Build{ID: fmt.Sprintf("new-build-%d", len(f.drafts))}, nil
+}
+
+func (f *fakeBuilds) AddGalleryImage(_ context.Context, _ string, _ string, _ models.AddGalleryImageParams) (*models.ModerationDecision, *models.GalleryImage, error) {
+	return &models.ModerationDecision{Status: models.ImageModerationApproved}, &models.GalleryImage{}, nil
+}
+
+type importFakes struct {
+	catalog   *fakeCatalog
+	inventory *fakeInventory
+	aircraft  *fakeAircraft
+	fcConfigs *fakeFCConfigs
+	builds    *fakeBuilds
+}
+
+func newTestImporter(t *testing.T) (*Importer, *mockImportJobStore, *importFakes) {
+	fakes := &importFakes{
+		catalog: &fakeCatalog{byKey: map[string]*models.GearCatalogItem{
+			"motors|tmotor|f60 pro v": {ID: "target-motor"},
+		}},
+		inventory: &fakeInventory{units: map[string][]models.AddInventoryUnitParams{}},
+		aircraft:  &fakeAircraft{gallery: map[string][]models.AddGalleryImageParams{}},
+		fcConfigs: &fakeFCConfigs{},
+		builds:    &fakeBuilds{},
+	}
+	jobs := newMockImportJobStore()
+	return &Importer{
+		jobs: jobs,
+		targets: Targets{
+			Catalog:   fakes.catalog,
+			Inventory: fakes.inventory,
+			Aircraft:  fakes.aircraft,
+			FCConfigs: fakes.fcConfigs,
+			Builds:    fakes.builds,
+		},
+		blobs:   storage.NewLocalStore(t.TempDir()),
+		running: map[string]bool{},
+		logger:  testutil.NullLogger(),
+		now:     time.Now,
+		start:   func(job func()) { job() },
+	}, jobs, fakes
+}
+
+// buildTestArchive writes an export archive from another instance whose
+// records link to each other by that instance's IDs
+func buildTestArchive(t *testing.T, tamper bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := &archiveWriter{
+		zip: zip.NewWriter(&buf),
+		manifest: &models.TakeoutManifest{
+			Format:   models.TakeoutFormat,
+			Version:  models.TakeoutFormatVersion,
+			ExportID: "export-1",
+			UserID:   "source-user",
+			Counts:   map[string]int{},
+		},
+	}
+
+	unitPrice := 24.99
+	add := func(name string, kind models.TakeoutFileKind, value interface{}) {
+		if err := archive.addJSON(name, kind, value); err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+	}
+	add("catalog.json", models.TakeoutFileCatalog, []models.GearCatalogItem{
+		{ID: "cat-fc", GearType: "fc", Brand: "Matek", Model: "H743", CanonicalKey: "fc|matek|h743"},
+		{ID: "cat-motor", GearType: "motors", Brand: "TMotor", Model: "F60 Pro V", CanonicalKey: "motors|tmotor|f60 pro v"},
+	})
+	add("inventory.json", models.TakeoutFileInventory, []models.InventoryItem{
+		{ID: "inv-fc", Name: "Matek H743", Category: models.CategoryFC, Quantity: 1, CatalogID: "cat-fc"},
+		{ID: "inv-motor", Name: "F60 Pro V", Category: models.CategoryMotors, Quantity: 4, CatalogID: "cat-motor", Units: []models.InventoryUnit{
+			{ID: "unit-1", SerialNumber: "F60-001", PurchasePrice: &unitPrice, Condition: models.InventoryUnitDamaged, AircraftID: "ac-1", BuildID: "build-1"},
+			{ID: "unit-2", SerialNumber: "F60-002", Condition: models.InventoryUnitNew, AircraftID: "ac-gone"},
+		}},
+	})
+	add("aircraft.json", models.TakeoutFileAircraft, []models.AircraftDetailsResponse{{
+		Aircraft:         models.Aircraft{ID: "ac-1", Name: "Five inch"},
+		Components:       []models.AircraftComponent{{Category: models.ComponentCategoryFC, InventoryItemID: "inv-fc"}},
+		ReceiverSettings: &models.AircraftReceiverSettings{Settings: json.RawMessage(`{"bindPhrase":"phrase"}`)},
+	}})
+	add("fc_configs.json", models.TakeoutFileFCConfigs, []models.FlightControllerConfig{
+		{ID: "cfg-1", InventoryItemID: "inv-fc", Name: "Baseline", RawCLIDump: "# dump"},
+	})
+	add("tuning_snapshots.json", models.TakeoutFileTuningSnapshots, []models.AircraftTuningSnapshot{
+		{ID: "snap-1", AircraftID: "ac-1", FlightControllerID: "inv-fc", FlightControllerConfigID: "cfg-1"},
+	})
+	add("builds.json", models.TakeoutFileBuilds, []models.Build{{
+		ID: "build-1", Title: "Freestyle five", Status: models.BuildStatusPublished, SourceAircraftID: "ac-1",
+		Parts: []models.BuildPart{
+			{GearType: "motors", CatalogItemID: "cat-motor"},
+			{GearType: "frame", CatalogItemID: "cat-frame", CatalogItem: &models.BuildCatalogItem{GearType: "frame", Brand: "Apex", Model: "EVO"}},
+		},
+	}})
+
+	png := "\x89PNG\r\n\x1a\n"
+	images := []models.TakeoutFile{
+		{Path: "images/img-2.png", Position: 1, Caption: "Unsafe", RecordID: "img-2"},
+		{Path: "images/img-1.png", Cover: true, Caption: "Maiden", RecordID: "img-1"},
+	}
+	for i, entry := range images {
+		entry.Kind = models.TakeoutFileImage
+		entry.ContentType = "image/png"
+		entry.EntityType = models.ImageEntityAircraft
+		entry.EntityID = "ac-1"
+		body := png + "cover"
+		if i == 0 {
+			body = png + "unsafe"
+		}
+		if err := archive.add(entry, strings.NewReader(body)); err != nil {
+			t.Fatalf("failed to add image: %v", err)
+		}
+	}
+
+	if tamper {
+		archive.manifest.Files[1].SHA256 = strings.Repeat("0", 64)
+	}
+	if err := archive.finish(); err != nil {
+		t.Fatalf("failed to finish archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRestoreRemapsLinkedRecords(t *testing.T) {
+	importer, _, fakes := newTestImporter(t)
+	data := buildTestArchive(t, false)
+
+	archive, err := openArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected archive to open, got %v", err)
+	}
+	result, err := importer.restore(context.Background(), archive, "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The FC is unknown here and becomes a pending entry; the motor matches by key
+	if len(fakes.catalog.created) != 2 || fakes.catalog.created[0].Model != "H743" || fakes.catalog.created[1].Model != "EVO" {
+		t.Fatalf("expected pending catalog entries for unmatched gear, got %+v", fakes.catalog.created)
+	}
+	if fakes.inventory.added[0].CatalogID != "new-cat-1" || fakes.inventory.added[1].CatalogID != "target-motor" {
+		t.Fatalf("expected inventory to link to this instance's catalog, got %+v", fakes.inventory.added)
+	}
+
+	if len(fakes.aircraft.components) != 1 || fakes.aircraft.components[0].AircraftID != "new-ac-1" || fakes.aircraft.components[0].InventoryItemID != "new-inv-1" {
+		t.Fatalf("expected component to be re-linked, got %+v", fakes.aircraft.components)
+	}
+	if len(fakes.aircraft.settings) != 1 || !strings.Contains(string(fakes.aircraft.settings[0].Settings), "phrase") {
+		t.Fatalf("expected receiver settings to be restored, got %+v", fakes.aircraft.settings)
+	}
+
+	if len(fakes.fcConfigs.configs) != 1 || fakes.fcConfigs.configs[0].InventoryItemID != "new-inv-1" {
+		t.Fatalf("expected FC config to be re-linked, got %+v", fakes.fcConfigs.configs)
+	}
+	snapshot := fakes.fcConfigs.snapshots[0]
+	if snapshot.AircraftID != "new-ac-1" || snapshot.FlightControllerID != "new-inv-1" || snapshot.FlightControllerConfigID != "new-cfg-1" {
+		t.Fatalf("expected tuning snapshot to be re-linked, got %+v", snapshot)
+	}
+
+	draft := fakes.builds.drafts[0]
+	if draft.SourceAircraftID != "new-ac-1" || len(draft.Parts) != 2 || draft.Parts[0].CatalogItemID != "target-motor" || draft.Parts[1].CatalogItemID != "new-cat-2" {
+		t.Fatalf("expected build parts to be matched by canonical key, got %+v", draft)
+	}
+
+	gallery := fakes.aircraft.gallery["new-ac-1"]
+	if len(gallery) != 1 || gallery[0].Caption != "Maiden" {
+		t.Fatalf("expected the cover to be re-moderated and attached, got %+v", gallery)
+	}
+	if result.ImagesRejected != 1 || result.Imported["images"] != 1 {
+		t.Fatalf("expected the unsafe image to be rejected, got %+v", result)
+	}
+	units := fakes.inventory.units["new-inv-2"]
+	if len(units) != 2 || units[0].SerialNumber != "F60-001" || units[0].Condition != models.InventoryUnitDamaged || units[0].PurchasePrice == nil || *units[0].PurchasePrice != 24.99 {
+		t.Fatalf("expected units to keep their serials, conditions and prices, got %+v", units)
+	}
+	if units[0].AircraftID != "new-ac-1" || units[0].BuildID != "new-build-1" {
+		t.Fatalf("expected the unit's aircraft and build to be re-linked, got %+v", units[0])
+	}
+	if units[1].AircraftID != "" || result.Imported["inventoryUnits"] != 2 {
+		t.Fatalf("expected a unit on a missing aircraft to be restored unassigned, got %+v", units[1])
+	}
+	if result.CatalogCreated != 2 || result.Imported["inventory"] != 2 || result.Imported["tuningSnapshots"] != 1 || result.SourceUserID != "source-user" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestRequestImportRestoresUploadedArchive(t *testing.T) {
+	importer, jobs, fakes := newTestImporter(t)
+	data := buildTestArchive(t, false)
+
+	job, err := importer.RequestImport(context.Background(), "user-1", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	stored := jobs.imports[job.ID]
+	if stored.Status != models.DataImportCompleted || stored.Result == nil || stored.Result.Imported["aircraft"] != 1 {
+		t.Fatalf("expected a completed import, got %+v", stored)
+	}
+	if fakes.aircraft.created != 1 {
+		t.Fatalf("expected one aircraft to be created, got %d", fakes.aircraft.created)
+	}
+	if _, _, err := importer.blobs.Get(context.Background(), importObjectKey("user-1", job.ID)); err == nil {
+		t.Fatal("expected the uploaded archive to be deleted")
+	}
+}
+
+func TestRequestImportRejectsArchiveAlreadyImported(t *testing.T) {
+	importer, jobs, fakes := newTestImporter(t)
+	data := buildTestArchive(t, false)
+	ctx := context.Background()
+
+	if _, err := importer.RequestImport(ctx, "user-1", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err := importer.RequestImport(ctx, "user-1", bytes.NewReader(data), int64(len(data)))
+	if svcErr, ok := err.(*ServiceError); !ok || !strings.Contains(svcErr.Message, "already been imported") {
+		t.Fatalf("expected the same archive to be turned away, got %v", err)
+	}
+	if len(jobs.imports) != 1 || fakes.aircraft.created != 1 {
+		t.Fatalf("expected nothing restored twice, got %d jobs and %d aircraft", len(jobs.imports), fakes.aircraft.created)
+	}
+
+	// Another account may import the same archive
+	if _, err := importer.RequestImport(ctx, "user-2", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("expected another user's import to be accepted, got %v", err)
+	}
+}
+
+func TestRequestImportRetriesFailedArchive(t *testing.T) {
+	importer, jobs, _ := newTestImporter(t)
+	data := buildTestArchive(t, false)
+	ctx := context.Background()
+
+	first, err := importer.RequestImport(ctx, "user-1", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	jobs.imports[first.ID].Status = models.DataImportFailed
+
+	if _, err := importer.RequestImport(ctx, "user-1", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("expected a failed import to be retried, got %v", err)
+	}
+}
+
+func TestRequestImportLimitsOneImportPerUser(t *testing.T) {
+	importer, _, _ := newTestImporter(t)
+	var queued []func()
+	importer.start = func(job func()) { queued = append(queued, job) }
+	ctx := context.Background()
+
+	first := buildTestArchive(t, false)
+	if _, err := importer.RequestImport(ctx, "user-1", bytes.NewReader(first), int64(len(first))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := importer.RequestImport(ctx, "user-1", bytes.NewReader(first), int64(len(first))); err == nil || !strings.Contains(err.Error(), "in progress") {
+		t.Fatalf("expected a second import for the same user to wait, got %v", err)
+	}
+	// One user's import does not hold up another's
+	if _, err := importer.RequestImport(ctx, "user-2", bytes.NewReader(first), int64(len(first))); err != nil {
+		t.Fatalf("expected another user's import to be accepted, got %v", err)
+	}
+
+	for _, job := range queued {
+		job()
+	}
+	if len(importer.running) != 0 {
+		t.Fatalf("expected finished imports to free their users' slots, got %v", importer.running)
+	}
+}
+
+func TestRequestImportRejectsTamperedArchive(t *testing.T) {
+	importer, jobs, fakes := newTestImporter(t)
+	data := buildTestArchive(t, true)
+
+	job, err := importer.RequestImport(context.Background(), "user-1", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected the upload to be queued, got %v", err)
+	}
+	stored := jobs.imports[job.ID]
+	if stored.Status != models.DataImportFailed || !strings.Contains(stored.Error, "inventory.json") {
+		t.Fatalf("expected a checksum failure, got %+v", stored)
+	}
+	if len(fakes.catalog.created) != 0 || len(fakes.inventory.added) != 0 {
+		t.Fatal("expected nothing to be restored from a damaged archive")
+	}
+}
+
+func TestOpenArchiveRejectsDuplicateManifestEntries(t *testing.T) {
+	var buf bytes.Buffer
+	archive := &archiveWriter{
+		zip:      zip.NewWriter(&buf),
+		manifest: &models.TakeoutManifest{Format: models.TakeoutFormat, Version: models.TakeoutFormatVersion, Counts: map[string]int{}},
+	}
+	if err := archive.addJSON("inventory.json", models.TakeoutFileInventory, []models.InventoryItem{}); err != nil {
+		t.Fatalf("failed to add inventory: %v", err)
+	}
+	archive.manifest.Files = append(archive.manifest.Files, archive.manifest.Files[0])
+	if err := archive.finish(); err != nil {
+		t.Fatalf("failed to finish archive: %v", err)
+	}
+
+	_, err := openArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if serviceErr, ok := err.(*ServiceError); !ok || !strings.Contains(serviceErr.Message, "more than once") {
+		t.Fatalf("expected a duplicate entry error, got %v", err)
+	}
+}
+
+func TestRequestImportRejectsOtherArchives(t *testing.T) {
+	importer, jobs, _ := newTestImporter(t)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	file, _ := zw.Create("manifest.json")
+	_, _ = file.Write([]byte(`{"format":"something-else","version":1}`))
+	_ = zw.Close()
+
+	_, err := importer.RequestImport(context.Background(), "user-1", bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if _, ok := err.(*ServiceError); !ok {
+		t.Fatalf("expected a service error, got %v", err)
+	}
+	if _, err := importer.RequestImport(context.Background(), "user-1", strings.NewReader("not a zip"), 9); err == nil {
+		t.Fatal("expected a non-ZIP upload to be rejected")
+	}
+	if len(jobs.imports) != 0 {
+		t.Fatal("expected no import job for a rejected upload")
+	}
+}
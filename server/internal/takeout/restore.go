@@ -0,0 +1,917 @@
+package takeout
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+)
+
+// maxImportFileSize caps any one file inside an import archive, so a
+// crafted archive cannot expand far beyond its upload size
+const maxImportFileSize = 256 << 20
+
+// maxImportTotalSize caps the combined size of the files a manifest lists
+const maxImportTotalSize = 4 * MaxImportArchiveSize
+
+// CatalogWriter matches catalog entries by canonical key and submits
+// pending entries for gear the instance does not know
+type CatalogWriter interface {
+	GetByCanonicalKey(ctx context.Context, canonicalKey string) (*models.GearCatalogItem, error)
+	Create(ctx context.Context, userID string, params models.CreateGearCatalogParams) (*models.GearCatalogCreateResponse, error)
+}
+
+// InventoryWriter adds inventory items and their tracked units
+type InventoryWriter interface {
+	AddItem(ctx context.Context, userID string, params models.AddInventoryParams) (*models.InventoryItem, error)
+	AddUnit(ctx context.Context, userID string, itemID string, params models.AddInventoryUnitParams) (*models.InventoryUnit, error)
+}
+
+// AircraftWriter creates aircraft and attaches their components, receiver
+// settings and moderated gallery images
+type AircraftWriter interface {
+	Create(ctx context.Context, userID string, params models.CreateAircraftParams) (*models.Aircraft, error)
+	SetComponent(ctx context.Context, userID string, params models.SetComponentParams) (*models.AircraftComponent, error)
+	SetReceiverSettings(ctx context.Context, userID string, params models.SetReceiverSettingsParams) (*models.AircraftReceiverSettings, error)
+	AddGalleryImage(ctx context.Context, aircraftID string, userID string, params models.AddGalleryImageParams) (*models.ModerationDecision, *models.GalleryImage, error)
+}
+
+// FCConfigWriter stores flight controller configs and tuning snapshots as
+// exported, without re-parsing dumps or deriving new snapshots
+type FCConfigWriter interface {
+	SaveConfig(ctx context.Context, userID string, config *models.FlightControllerConfig) error
+	SaveTuningSnapshot(ctx context.Context, userID string, snapshot *models.AircraftTuningSnapshot) error
+}
+
+// BatteryWriter creates batteries and their logs
+type BatteryWriter interface {
+	Create(ctx context.Context, userID string, params models.CreateBatteryParams) (*models.Battery, error)
+	CreateLog(ctx context.Context, userID string, params models.CreateBatteryLogParams) (*models.BatteryLog, error)
+}
+
+// RadioWriter creates radios and stores their backup files
+type RadioWriter interface {
+	CreateRadio(ctx context.Context, userID string, params models.CreateRadioParams) (*models.Radio, error)
+	CreateBackup(ctx context.Context, radioID string, userID string, params models.CreateRadioBackupParams, fileReader io.Reader) (*models.RadioBackup, error)
+}
+
+// BuildWriter creates draft builds and adds moderated gallery images
+type BuildWriter interface {
+	CreateDraft(ctx context.Context, ownerUserID string, params models.CreateBuildParams) (*models.Build, error)
+	AddGalleryImage(ctx context.Context, userID string, buildID string, params models.AddGalleryImageParams) (*models.ModerationDecision, *models.GalleryImage, error)
+}
+
+// ProfileWriter sets the restored avatar on the user's profile
+type ProfileWriter interface {
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	Update(ctx context.Context, id string, params models.UpdateUserParams) (*models.User, error)
+}
+
+// ImageModerator moderates and stores avatar images
+type ImageModerator interface {
+	ModerateAndPersist(ctx context.Context, req images.SaveRequest) (*models.ModerationDecision, *models.ImageAsset, error)
+	Delete(ctx context.Context, imageID string) error
+}
+
+// Targets are where an import writes the user's data. A nil target skips
+// its section of the archive.
+type Targets struct {
+	Catalog   CatalogWriter
+	Inventory InventoryWriter
+	Aircraft  AircraftWriter
+	FCConfigs FCConfigWriter
+	Batteries BatteryWriter
+	Radios    RadioWriter
+	Builds    BuildWriter
+	Profile   ProfileWriter
+	Images    ImageModerator // Avatars only; gallery images are moderated by their owners' services
+}
+
+// archiveReader reads an export archive whose files have been checked
+// against its manifest
+type archiveReader struct {
+	manifest models.TakeoutManifest
+	files    map[string]*zip.File
+}
+
+// readManifest decodes manifest.json and checks that the archive is an
+// export this version of FlyingForge understands
+func readManifest(zr *zip.Reader) (*models.TakeoutManifest, error) {
+	var file *zip.File
+	for _, f := range zr.File {
+		if f.Name == "manifest.json" {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil, &ServiceError{Message: "archive has no manifest.json; is it a FlyingForge export?"}
+	}
+
+	body, err := file.Open()
+	if err != nil {
+		return nil, &ServiceError{Message: "manifest.json could not be read"}
+	}
+	defer body.Close()
+
+	var manifest models.TakeoutManifest
+	if err := json.NewDecoder(io.LimitReader(body, maxImportFileSize)).Decode(&manifest); err != nil {
+		return nil, &ServiceError{Message: "manifest.json is not valid JSON"}
+	}
+	if manifest.Format != models.TakeoutFormat {
+		return nil, &ServiceError{Message: "archive is not a FlyingForge export"}
+	}
+	if manifest.Version < 1 || manifest.Version > models.TakeoutFormatVersion {
+		return nil, &ServiceError{Message: fmt.Sprintf("unsupported export format version %d", manifest.Version)}
+	}
+	return &manifest, nil
+}
+
+// openArchive reads the manifest, rejects duplicate or oversized entries, and
+// verifies the size and checksum of every file it lists, so a damaged archive
+// is rejected before anything is restored
+func openArchive(r io.ReaderAt, size int64) (*archiveReader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, &ServiceError{Message: "archive is not a valid ZIP file"}
+	}
+	manifest, err := readManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]bool, len(manifest.Files))
+	var total int64
+	for _, entry := range manifest.Files {
+		if listed[entry.Path] {
+			return nil, &ServiceError{Message: fmt.Sprintf("manifest lists %s more than once", entry.Path)}
+		}
+		listed[entry.Path] = true
+		if entry.Size < 0 || entry.Size > maxImportFileSize {
+			return nil, &ServiceError{Message: fmt.Sprintf("%s is too large to import", entry.Path)}
+		}
+		if total += entry.Size; total > maxImportTotalSize {
+			return nil, &ServiceError{Message: "archive contents are too large to import"}
+		}
+	}
+
+	archive := &archiveReader{manifest: *manifest, files: map[string]*zip.File{}}
+	for _, f := range zr.File {
+		archive.files[f.Name] = f
+	}
+	for _, entry := range manifest.Files {
+		if err := archive.verify(entry); err != nil {
+			return nil, err
+		}
+	}
+	return archive, nil
+}
+
+func (a *archiveReader) verify(entry models.TakeoutFile) error {
+	file, ok := a.files[entry.Path]
+	if !ok {
+		return &ServiceError{Message: fmt.Sprintf("archive is missing %s", entry.Path)}
+	}
+
+	body, err := file.Open()
+	if err != nil {
+		return &ServiceError{Message: fmt.Sprintf("%s could not be read", entry.Path)}
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, io.LimitReader(body, entry.Size+1))
+	if err != nil {
+		return &ServiceError{Message: fmt.Sprintf("%s could not be read", entry.Path)}
+	}
+	if n != entry.Size || hex.EncodeToString(hash.Sum(nil)) != entry.SHA256 {
+		return &ServiceError{Message: fmt.Sprintf("%s does not match its checksum in the manifest", entry.Path)}
+	}
+	return nil
+}
+
+// open returns a reader for a verified file
+func (a *archiveReader) open(entry models.TakeoutFile) (io.ReadCloser, error) {
+	file, ok := a.files[entry.Path]
+	if !ok {
+		return nil, fmt.Errorf("archive is missing %s", entry.Path)
+	}
+	return file.Open()
+}
+
+// read returns the content of a verified file
+func (a *archiveReader) read(entry models.TakeoutFile) ([]byte, error) {
+	body, err := a.open(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(io.LimitReader(body, maxImportFileSize))
+}
+
+// readJSON decodes the section file of the given kind into v. It reports
+// false when the archive has no such section.
+func (a *archiveReader) readJSON(kind models.TakeoutFileKind, v interface{}) (bool, error) {
+	for _, entry := range a.manifest.Files {
+		if entry.Kind != kind {
+			continue
+		}
+		data, err := a.read(entry)
+		if err != nil {
+			return false, fmt.Errorf("read %s: %w", entry.Path, err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return false, &ServiceError{Message: fmt.Sprintf("%s is not valid: %v", entry.Path, err)}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// filesOfKind returns the manifest entries of one kind
+func (a *archiveReader) filesOfKind(kind models.TakeoutFileKind) []models.TakeoutFile {
+	var entries []models.TakeoutFile
+	for _, entry := range a.manifest.Files {
+		if entry.Kind == kind {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// restoreState maps record IDs in the archive to the IDs they were given
+// on import
+type restoreState struct {
+	userID  string
+	archive *archiveReader
+	result  *models.DataImportResult
+
+	catalogSource map[string]models.GearCatalogItem // Exported catalog entries by old ID
+	catalog       map[string]string                 // Old catalog ID to matched or created ID
+	inventory     map[string]string
+	aircraft      map[string]string
+	fcConfigs     map[string]string
+	batteries     map[string]string
+	radios        map[string]string
+	builds        map[string]string
+	units         []pendingUnit // Restored once the aircraft and builds they sit on are
+	profile       *models.User
+}
+
+// pendingUnit is an exported unit waiting for its item's new ID
+type pendingUnit struct {
+	itemID   string
+	itemName string
+	unit     models.InventoryUnit
+}
+
+func (st *restoreState) warn(format string, args ...interface{}) {
+	st.result.Warnings = append(st.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (st *restoreState) count(key string) {
+	st.result.Imported[key]++
+}
+
+// restore writes a verified archive's records into the user's account,
+// remapping IDs so links between records survive. Records that cannot be
+// restored are skipped with a warning; the returned result covers
+// everything restored even when an error stops the import.
+func (s *Importer) restore(ctx context.Context, archive *archiveReader, userID string) (*models.DataImportResult, error) {
+	st := &restoreState{
+		userID:  userID,
+		archive: archive,
+		result: &models.DataImportResult{
+			SourceExportID: archive.manifest.ExportID,
+			SourceUserID:   archive.manifest.UserID,
+			Imported:       map[string]int{},
+		},
+		catalogSource: map[string]models.GearCatalogItem{},
+		catalog:       map[string]string{},
+		inventory:     map[string]string{},
+		aircraft:      map[string]string{},
+		fcConfigs:     map[string]string{},
+		batteries:     map[string]string{},
+		radios:        map[string]string{},
+		builds:        map[string]string{},
+	}
+
+	// Sections run in dependency order: each only links to records
+	// restored by the ones before it
+	sections := []func(context.Context, *restoreState) error{
+		s.loadSources,
+		s.restoreInventory,
+		s.restoreAircraft,
+		s.restoreFCConfigs,
+		s.restoreBatteries,
+		s.restoreRadios,
+		s.restoreBuilds,
+		s.restoreInventoryUnits,
+		s.restoreImages,
+	}
+	for _, section := range sections {
+		if err := ctx.Err(); err != nil {
+			return st.result, err
+		}
+		if err := section(ctx, st); err != nil {
+			return st.result, err
+		}
+	}
+	return st.result, nil
+}
+
+// loadSources reads the profile and catalog entries the other sections refer to
+func (s *Importer) loadSources(_ context.Context, st *restoreState) error {
+	var profile models.User
+	found, err := st.archive.readJSON(models.TakeoutFileProfile, &profile)
+	if err != nil {
+		return err
+	}
+	if found {
+		st.profile = &profile
+	}
+
+	var items []models.GearCatalogItem
+	if _, err := st.archive.readJSON(models.TakeoutFileCatalog, &items); err != nil {
+		return err
+	}
+	for _, item := range items {
+		st.catalogSource[item.ID] = item
+	}
+	return nil
+}
+
+// resolveCatalog returns this instance's ID for an exported catalog entry.
+// Entries are matched by canonical key; unknown gear is submitted as a
+// pending entry. fallback describes the entry when catalog.json does not.
+func (s *Importer) resolveCatalog(ctx context.Context, st *restoreState, oldID string, fallback *models.GearCatalogItem) string {
+	oldID = strings.TrimSpace(oldID)
+	if oldID == "" || s.targets.Catalog == nil {
+		return ""
+	}
+	if id, ok := st.catalog[oldID]; ok {
+		return id
+	}
+
+	item, ok := st.catalogSource[oldID]
+	if !ok {
+		if fallback == nil {
+			st.warn("catalog item %s: not in archive", oldID)
+			st.catalog[oldID] = ""
+			return ""
+		}
+		item = *fallback
+	}
+
+	key := strings.TrimSpace(item.CanonicalKey)
+	if key == "" {
+		key = models.BuildCanonicalKey(item.GearType, item.Brand, item.Model, item.Variant)
+	}
+	existing, err := s.targets.Catalog.GetByCanonicalKey(ctx, key)
+	if err != nil {
+		st.warn("catalog item %s: %v", item.DisplayName(), err)
+		st.catalog[oldID] = ""
+		return ""
+	}
+	if existing != nil {
+		st.catalog[oldID] = existing.ID
+		st.count("catalogMatched")
+		return existing.ID
+	}
+
+	created, err := s.targets.Catalog.Create(ctx, st.userID, models.CreateGearCatalogParams{
+		GearType:    item.GearType,
+		Brand:       item.Brand,
+		Model:       item.Model,
+		Variant:     item.Variant,
+		Specs:       item.Specs,
+		BestFor:     item.BestFor,
+		MSRP:        item.MSRP,
+		Description: item.Description,
+	})
+	if err != nil || created == nil || created.Item == nil {
+		st.warn("catalog item %s: %v", item.DisplayName(), err)
+		st.catalog[oldID] = ""
+		return ""
+	}
+	if created.Existing {
+		st.count("catalogMatched")
+	} else {
+		st.result.CatalogCreated++
+	}
+	st.catalog[oldID] = created.Item.ID
+	return created.Item.ID
+}
+
+func (s *Importer) restoreInventory(ctx context.Context, st *restoreState) error {
+	if s.targets.Inventory == nil {
+		return nil
+	}
+	var items []models.InventoryItem
+	if _, err := st.archive.readJSON(models.TakeoutFileInventory, &items); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		catalogID := s.resolveCatalog(ctx, st, item.CatalogID, item.CatalogItem)
+		added, err := s.targets.Inventory.AddItem(ctx, st.userID, models.AddInventoryParams{
+			Name:              item.Name,
+			Category:          item.Category,
+			Manufacturer:      item.Manufacturer,
+			Quantity:          item.Quantity,
+			Notes:             item.Notes,
+			PurchasePrice:     item.PurchasePrice,
+			PurchaseSeller:    item.PurchaseSeller,
+			ProductURL:        item.ProductURL,
+			Specs:             item.Specs,
+			SourceEquipmentID: item.SourceEquipmentID,
+			CatalogID:         catalogID,
+		})
+		if err != nil {
+			st.warn("inventory item %q: %v", item.Name, err)
+			continue
+		}
+		st.inventory[item.ID] = added.ID
+		st.count("inventory")
+		for _, unit := range item.Units {
+			st.units = append(st.units, pendingUnit{itemID: added.ID, itemName: item.Name, unit: unit})
+		}
+	}
+	return nil
+}
+
+// restoreInventoryUnits tracks the exported units of restored items with
+// their serial numbers, conditions and prices. A unit whose aircraft or
+// build was not restored is kept unassigned.
+func (s *Importer) restoreInventoryUnits(ctx context.Context, st *restoreState) error {
+	for _, pending := range st.units {
+		source := pending.unit
+		params := models.AddInventoryUnitParams{
+			SerialNumber:   source.SerialNumber,
+			PurchasePrice:  source.PurchasePrice,
+			PurchaseSeller: source.PurchaseSeller,
+			Condition:      source.Condition,
+			Notes:          source.Notes,
+		}
+		if source.AircraftID != "" {
+			if aircraftID, ok := st.aircraft[source.AircraftID]; ok {
+				params.AircraftID = aircraftID
+			} else {
+				st.warn("inventory item %q: a unit's aircraft was not restored, so the unit is unassigned", pending.itemName)
+			}
+		}
+		if source.BuildID != "" {
+			if buildID, ok := st.builds[source.BuildID]; ok {
+				params.BuildID = buildID
+			} else {
+				st.warn("inventory item %q: a unit's build was not restored, so the unit is unassigned", pending.itemName)
+			}
+		}
+		if _, err := s.targets.Inventory.AddUnit(ctx, st.userID, pending.itemID, params); err != nil {
+			st.warn("inventory item %q: unit %s: %v", pending.itemName, source.SerialNumber, err)
+			continue
+		}
+		st.count("inventoryUnits")
+	}
+	return nil
+}
+
+func (s *Importer) restoreAircraft(ctx context.Context, st *restoreState) error {
+	if s.targets.Aircraft == nil {
+		return nil
+	}
+	var details []models.AircraftDetailsResponse
+	if _, err := st.archive.readJSON(models.TakeoutFileAircraft, &details); err != nil {
+		return err
+	}
+
+	for _, detail := range details {
+		source := detail.Aircraft
+		created, err := s.targets.Aircraft.Create(ctx, st.userID, models.CreateAircraftParams{
+			Name:        source.Name,
+			Nickname:    source.Nickname,
+			Type:        source.Type,
+			Description: source.Description,
+		})
+		if err != nil {
+			st.warn("aircraft %q: %v", source.Name, err)
+			continue
+		}
+		st.aircraft[source.ID] = created.ID
+		st.count("aircraft")
+
+		for _, component := range detail.Components {
+			inventoryID, ok := st.inventory[component.InventoryItemID]
+			if !ok {
+				st.warn("aircraft %q: %s component was not restored because its inventory item was not", source.Name, component.Category)
+				continue
+			}
+			if _, err := s.targets.Aircraft.SetComponent(ctx, st.userID, models.SetComponentParams{
+				AircraftID:      created.ID,
+				Category:        component.Category,
+				InventoryItemID: inventoryID,
+				Notes:           component.Notes,
+			}); err != nil {
+				st.warn("aircraft %q: %s component: %v", source.Name, component.Category, err)
+				continue
+			}
+			st.count("components")
+		}
+
+		if detail.ReceiverSettings != nil && len(detail.ReceiverSettings.Settings) > 0 {
+			if _, err := s.targets.Aircraft.SetReceiverSettings(ctx, st.userID, models.SetReceiverSettingsParams{
+				AircraftID: created.ID,
+				Settings:   detail.ReceiverSettings.Settings,
+			}); err != nil {
+				st.warn("aircraft %q: receiver settings: %v", source.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Importer) restoreFCConfigs(ctx context.Context, st *restoreState) error {
+	if s.targets.FCConfigs == nil {
+		return nil
+	}
+	var configs []models.FlightControllerConfig
+	if _, err := st.archive.readJSON(models.TakeoutFileFCConfigs, &configs); err != nil {
+		return err
+	}
+	dumps := map[string]models.TakeoutFile{}
+	for _, entry := range st.archive.filesOfKind(models.TakeoutFileFCConfigDump) {
+		dumps[entry.RecordID] = entry
+	}
+
+	for _, source := range configs {
+		inventoryID, ok := st.inventory[source.InventoryItemID]
+		if !ok {
+			st.warn("FC config %q: its flight controller was not restored", source.Name)
+			continue
+		}
+		config := source
+		config.ID = ""
+		config.UserID = st.userID
+		config.InventoryItemID = inventoryID
+		if config.RawCLIDump == "" {
+			if entry, ok := dumps[source.ID]; ok {
+				dump, err := st.archive.read(entry)
+				if err != nil {
+					return fmt.Errorf("read %s: %w", entry.Path, err)
+				}
+				config.RawCLIDump = string(dump)
+			}
+		}
+		if err := s.targets.FCConfigs.SaveConfig(ctx, st.userID, &config); err != nil {
+			st.warn("FC config %q: %v", source.Name, err)
+			continue
+		}
+		st.fcConfigs[source.ID] = config.ID
+		st.count("fcConfigs")
+	}
+
+	var snapshots []models.AircraftTuningSnapshot
+	if _, err := st.archive.readJSON(models.TakeoutFileTuningSnapshots, &snapshots); err != nil {
+		return err
+	}
+	for _, source := range snapshots {
+		aircraftID, ok := st.aircraft[source.AircraftID]
+		if !ok {
+			st.warn("tuning snapshot %s: its aircraft was not restored", source.ID)
+			continue
+		}
+		snapshot := source
+		snapshot.ID = ""
+		snapshot.AircraftID = aircraftID
+		snapshot.FlightControllerID = st.inventory[source.FlightControllerID]
+		snapshot.FlightControllerConfigID = st.fcConfigs[source.FlightControllerConfigID]
+		if err := s.targets.FCConfigs.SaveTuningSnapshot(ctx, st.userID, &snapshot); err != nil {
+			st.warn("tuning snapshot %s: %v", source.ID, err)
+			continue
+		}
+		st.count("tuningSnapshots")
+	}
+	return nil
+}
+
+func (s *Importer) restoreBatteries(ctx context.Context, st *restoreState) error {
+	if s.targets.Batteries == nil {
+		return nil
+	}
+	var batteries []models.Battery
+	if _, err := st.archive.readJSON(models.TakeoutFileBatteries, &batteries); err != nil {
+		return err
+	}
+	for _, source := range batteries {
+		created, err := s.targets.Batteries.Create(ctx, st.userID, models.CreateBatteryParams{
+			Name:         source.Name,
+			Chemistry:    source.Chemistry,
+			Cells:        source.Cells,
+			CapacityMah:  source.CapacityMah,
+			CRating:      source.CRating,
+			Connector:    source.Connector,
+			WeightGrams:  source.WeightGrams,
+			Brand:        source.Brand,
+			Model:        source.Model,
+			PurchaseDate: source.PurchaseDate,
+			Notes:        source.Notes,
+		})
+		if err != nil {
+			st.warn("battery %s: %v", source.BatteryCode, err)
+			continue
+		}
+		st.batteries[source.ID] = created.ID
+		st.count("batteries")
+	}
+
+	var logs []models.BatteryLog
+	if _, err := st.archive.readJSON(models.TakeoutFileBatteryLogs, &logs); err != nil {
+		return err
+	}
+	for _, source := range logs {
+		batteryID, ok := st.batteries[source.BatteryID]
+		if !ok {
+			continue // Already warned about the battery
+		}
+		loggedAt := source.LoggedAt
+		if _, err := s.targets.Batteries.CreateLog(ctx, st.userID, models.CreateBatteryLogParams{
+			BatteryID:     batteryID,
+			AircraftID:    st.aircraft[source.AircraftID],
+			LoggedAt:      &loggedAt,
+			CycleDelta:    source.CycleDelta,
+			IRMohmPerCell: source.IRMohmPerCell,
+			MinCellV:      source.MinCellV,
+			MaxCellV:      source.MaxCellV,
+			StorageOk:     source.StorageOk,
+			Notes:         source.Notes,
+		}); err != nil {
+			st.warn("battery log %s: %v", source.ID, err)
+			continue
+		}
+		st.count("batteryLogs")
+	}
+	return nil
+}
+
+func (s *Importer) restoreRadios(ctx context.Context, st *restoreState) error {
+	if s.targets.Radios == nil {
+		return nil
+	}
+	var radios []models.TakeoutRadio
+	if _, err := st.archive.readJSON(models.TakeoutFileRadios, &radios); err != nil {
+		return err
+	}
+	files := map[string]models.TakeoutFile{}
+	for _, entry := range st.archive.filesOfKind(models.TakeoutFileRadioBackup) {
+		files[entry.RecordID] = entry
+	}
+
+	for _, source := range radios {
+		created, err := s.targets.Radios.CreateRadio(ctx, st.userID, models.CreateRadioParams{
+			Manufacturer:   source.Radio.Manufacturer,
+			Model:          source.Radio.Model,
+			FirmwareFamily: source.Radio.FirmwareFamily,
+			Notes:          source.Radio.Notes,
+		})
+		if err != nil {
+			st.warn("radio %s: %v", source.Radio.Model, err)
+			continue
+		}
+		st.radios[source.Radio.ID] = created.ID
+		st.count("radios")
+
+		for _, backup := range source.Backups {
+			entry, ok := files[backup.ID]
+			if !ok {
+				st.warn("radio backup %q: file not in archive", backup.BackupName)
+				continue
+			}
+			if err := s.restoreRadioBackup(ctx, st, created.ID, backup, entry); err != nil {
+				st.warn("radio backup %q: %v", backup.BackupName, err)
+				continue
+			}
+			st.count("radioBackups")
+		}
+	}
+	return nil
+}
+
+func (s *Importer) restoreRadioBackup(ctx context.Context, st *restoreState, radioID string, backup models.RadioBackup, entry models.TakeoutFile) error {
+	body, err := st.archive.open(entry)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	_, err = s.targets.Radios.CreateBackup(ctx, radioID, st.userID, models.CreateRadioBackupParams{
+		BackupName: backup.BackupName,
+		BackupType: backup.BackupType,
+		FileName:   backup.FileName,
+		FileSize:   entry.Size,
+	}, body)
+	return err
+}
+
+// restoreBuilds restores builds as drafts; publishing goes through
+// moderation again on the new instance
+func (s *Importer) restoreBuilds(ctx context.Context, st *restoreState) error {
+	if s.targets.Builds == nil {
+		return nil
+	}
+	var builds []models.Build
+	if _, err := st.archive.readJSON(models.TakeoutFileBuilds, &builds); err != nil {
+		return err
+	}
+
+	for _, source := range builds {
+		parts := make([]models.BuildPartInput, 0, len(source.Parts))
+		for _, part := range source.Parts {
+			catalogID := s.resolveCatalog(ctx, st, part.CatalogItemID, buildPartCatalogItem(part))
+			if catalogID == "" {
+				st.warn("build %q: %s part was left out because its catalog item could not be matched", source.Title, part.GearType)
+				continue
+			}
+			parts = append(parts, models.BuildPartInput{
+				GearType:      part.GearType,
+				CatalogItemID: catalogID,
+				Position:      part.Position,
+				Notes:         part.Notes,
+			})
+		}
+
+		created, err := s.targets.Builds.CreateDraft(ctx, st.userID, models.CreateBuildParams{
+			Title:            source.Title,
+			Description:      source.Description,
+			YouTubeURL:       source.YouTubeURL,
+			FlightYouTubeURL: source.FlightYouTubeURL,
+			SourceAircraftID: st.aircraft[source.SourceAircraftID],
+			Parts:            parts,
+		})
+		if err != nil {
+			st.warn("build %q: %v", source.Title, err)
+			continue
+		}
+		st.builds[source.ID] = created.ID
+		st.count("builds")
+	}
+	return nil
+}
+
+// buildPartCatalogItem describes a build part's catalog entry from the
+// summary embedded on the part
+func buildPartCatalogItem(part models.BuildPart) *models.GearCatalogItem {
+	if part.CatalogItem == nil {
+		return nil
+	}
+	return &models.GearCatalogItem{
+		ID:       part.CatalogItem.ID,
+		GearType: part.CatalogItem.GearType,
+		Brand:    part.CatalogItem.Brand,
+		Model:    part.CatalogItem.Model,
+		Variant:  part.CatalogItem.Variant,
+		MSRP:     part.CatalogItem.MSRP,
+	}
+}
+
+// restoreImages re-attaches images through the same moderation an upload
+// goes through. Covers are added first so each gallery gets its cover back.
+func (s *Importer) restoreImages(ctx context.Context, st *restoreState) error {
+	entries := st.archive.filesOfKind(models.TakeoutFileImage)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Cover != entries[j].Cover {
+			return entries[i].Cover
+		}
+		return entries[i].Position < entries[j].Position
+	})
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		decision, err := s.restoreImage(ctx, st, entry)
+		if err != nil {
+			st.warn("image %s: %v", entry.RecordID, err)
+			continue
+		}
+		if decision == nil {
+			continue // Nothing to attach it to
+		}
+		if !decision.Accepted() {
+			st.result.ImagesRejected++
+			reason := decision.Reason
+			if reason == "" {
+				reason = "not approved"
+			}
+			st.warn("image %s: rejected by moderation: %s", entry.RecordID, reason)
+			continue
+		}
+		if decision.Queued {
+			st.result.ImagesQueued++
+		}
+		st.count("images")
+	}
+	return nil
+}
+
+// restoreImage moderates and attaches one image. A nil decision means the
+// image's record was not restored or images of its kind are not imported.
+func (s *Importer) restoreImage(ctx context.Context, st *restoreState, entry models.TakeoutFile) (*models.ModerationDecision, error) {
+	switch entry.EntityType {
+	case models.ImageEntityAircraft:
+		aircraftID, ok := st.aircraft[entry.EntityID]
+		if !ok || s.targets.Aircraft == nil {
+			return nil, nil
+		}
+		params, err := galleryImageParams(st, entry)
+		if err != nil {
+			return nil, err
+		}
+		decision, _, err := s.targets.Aircraft.AddGalleryImage(ctx, aircraftID, st.userID, params)
+		return decision, err
+
+	case models.ImageEntityBuild:
+		buildID, ok := st.builds[entry.EntityID]
+		if !ok || s.targets.Builds == nil {
+			return nil, nil
+		}
+		params, err := galleryImageParams(st, entry)
+		if err != nil {
+			return nil, err
+		}
+		decision, _, err := s.targets.Builds.AddGalleryImage(ctx, st.userID, buildID, params)
+		return decision, err
+
+	case models.ImageEntityAvatar:
+		if st.profile == nil || st.profile.AvatarImageID != entry.RecordID {
+			return nil, nil // An old avatar that is no longer in use
+		}
+		return s.restoreAvatar(ctx, st, entry)
+
+	default:
+		return nil, nil
+	}
+}
+
+func galleryImageParams(st *restoreState, entry models.TakeoutFile) (models.AddGalleryImageParams, error) {
+	data, err := st.archive.read(entry)
+	if err != nil {
+		return models.AddGalleryImageParams{}, err
+	}
+	return models.AddGalleryImageParams{
+		Caption:   entry.Caption,
+		ImageType: entry.ContentType,
+		ImageData: data,
+	}, nil
+}
+
+// restoreAvatar moderates the exported avatar and makes it the user's
+// custom avatar, as an avatar upload does
+func (s *Importer) restoreAvatar(ctx context.Context, st *restoreState, entry models.TakeoutFile) (*models.ModerationDecision, error) {
+	if s.targets.Profile == nil || s.targets.Images == nil {
+		return nil, nil
+	}
+	data, err := st.archive.read(entry)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.targets.Profile.GetByID(ctx, st.userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	decision, asset, err := s.targets.Images.ModerateAndPersist(ctx, images.SaveRequest{
+		OwnerUserID: st.userID,
+		EntityType:  models.ImageEntityAvatar,
+		EntityID:    st.userID,
+		ImageBytes:  data,
+	})
+	if err != nil || !decision.Accepted() || asset == nil {
+		return decision, err
+	}
+
+	avatarURL := "/api/images/" + asset.ID
+	avatarType := models.AvatarTypeCustom
+	if _, err := s.targets.Profile.Update(ctx, st.userID, models.UpdateUserParams{
+		CustomAvatarURL: &avatarURL,
+		AvatarImageID:   &asset.ID,
+		AvatarType:      &avatarType,
+	}); err != nil {
+		_ = s.targets.Images.Delete(ctx, asset.ID)
+		return nil, err
+	}
+	if current.AvatarImageID != "" && current.AvatarImageID != asset.ID {
+		_ = s.targets.Images.Delete(ctx, current.AvatarImageID)
+	}
+	return decision, nil
+}
@@ -0,0 +1,338 @@
+// Package takeout exports a user's data as a ZIP archive and imports it back.
+package takeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/database"
+	"github.com/johnrirwin/flyingforge/internal/logging"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
+)
+
+const (
+	// DefaultLocalStorageRoot is the object store root used when none is configured.
+	// Archives live under exports/ within it.
+	DefaultLocalStorageRoot = "./data"
+	// DefaultRetention is how long a finished archive can be downloaded
+	DefaultRetention = 7 * 24 * time.Hour
+	// DefaultDownloadURLTTL is how long direct download URLs stay valid
+	DefaultDownloadURLTTL = 15 * time.Minute
+
+	// exportTimeout bounds how long one archive may take to build. Jobs still
+	// unfinished well past it were lost to a restart and are failed by cleanup.
+	exportTimeout = 30 * time.Minute
+	// jobStatusTimeout bounds recording a job's outcome. It has its own
+	// context so a job that ran out of time can still be marked failed.
+	jobStatusTimeout = 30 * time.Second
+
+	maxConcurrentExports = 2
+	maxListedExports     = 10
+	cleanupBatchSize     = 100
+)
+
+// ServiceError represents a service-level error
+type ServiceError struct {
+	Message string
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+// JobStore defines the interface for export job storage operations
+type JobStore interface {
+	Create(ctx context.Context, userID string) (*models.DataExport, error)
+	Get(ctx context.Context, id string, userID string) (*models.DataExport, error)
+	ListByUser(ctx context.Context, userID string, limit int) ([]models.DataExport, error)
+	ListArchived(ctx context.Context, userID string) ([]models.DataExport, error)
+	GetActive(ctx context.Context, userID string) (*models.DataExport, error)
+	MarkRunning(ctx context.Context, id string) error
+	MarkReady(ctx context.Context, id string, storageKey string, fileSize int64, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, id string, message string) error
+	MarkExpired(ctx context.Context, id string) error
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]models.DataExport, error)
+	FailStale(ctx context.Context, cutoff time.Time, message string) (int64, error)
+}
+
+// Service runs data export jobs and serves their archives
+type Service struct {
+	jobs           JobStore
+	sources        Sources
+	blobs          storage.ObjectStore
+	retention      time.Duration
+	downloadURLTTL time.Duration
+	slots          chan struct{}
+	logger         *logging.Logger
+	now            func() time.Time
+	start          func(job func()) // Runs a job in the background
+}
+
+// NewService creates a new export service. A nil object store keeps
+// archives on local disk under DefaultLocalStorageRoot.
+func NewService(jobs *database.DataExportStore, sources Sources, blobs storage.ObjectStore, downloadURLTTL time.Duration, logger *logging.Logger) *Service {
+	if blobs == nil {
+		blobs = storage.NewLocalStore(DefaultLocalStorageRoot)
+	}
+	if downloadURLTTL <= 0 {
+		downloadURLTTL = DefaultDownloadURLTTL
+	}
+	return &Service{
+		jobs:           jobs,
+		sources:        sources,
+		blobs:          blobs,
+		retention:      DefaultRetention,
+		downloadURLTTL: downloadURLTTL,
+		slots:          make(chan struct{}, maxConcurrentExports),
+		logger:         logger,
+		now:            time.Now,
+		start:          func(job func()) { go job() },
+	}
+}
+
+// RequestExport queues an export of the user's data. While one is already
+// pending or running it is returned instead of starting another.
+func (s *Service) RequestExport(ctx context.Context, userID string) (*models.DataExport, error) {
+	active, err := s.jobs.GetActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return active, nil
+	}
+
+	export, err := s.jobs.Create(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Queued data export", logging.WithFields(map[string]interface{}{
+		"userId":   userID,
+		"exportId": export.ID,
+	}))
+
+	exportID := export.ID
+	s.start(func() { s.run(exportID, userID) })
+	return export, nil
+}
+
+// ListExports returns the user's most recent exports
+func (s *Service) ListExports(ctx context.Context, userID string) ([]models.DataExport, error) {
+	exports, err := s.jobs.ListByUser(ctx, userID, maxListedExports)
+	if err != nil {
+		return nil, err
+	}
+	for i := range exports {
+		s.setDownloadURL(&exports[i])
+	}
+	return exports, nil
+}
+
+// GetExport returns one of the user's exports
+func (s *Service) GetExport(ctx context.Context, userID string, id string) (*models.DataExport, error) {
+	export, err := s.jobs.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, &ServiceError{Message: "export not found"}
+	}
+	s.setDownloadURL(export)
+	return export, nil
+}
+
+// OpenArchive returns a reader for a ready export's ZIP archive
+func (s *Service) OpenArchive(ctx context.Context, userID string, id string) (io.ReadCloser, *models.DataExport, error) {
+	export, err := s.downloadable(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, _, err := s.blobs.Get(ctx, export.StorageKey)
+	if err != nil {
+		s.logger.Error("Failed to open export archive", logging.WithFields(map[string]interface{}{
+			"exportId":    export.ID,
+			"storage_key": export.StorageKey,
+			"error":       err.Error(),
+		}))
+		return nil, nil, &ServiceError{Message: "export archive not found"}
+	}
+	return file, export, nil
+}
+
+// GetDownloadURL returns a short-lived URL that downloads the archive
+// straight from the object store, bypassing the API server.
+func (s *Service) GetDownloadURL(ctx context.Context, userID string, id string) (*models.DataExportDownloadURL, error) {
+	export, err := s.downloadable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.blobs.PresignGet(ctx, export.StorageKey, s.downloadURLTTL, ArchiveFileName(export))
+	if errors.Is(err, storage.ErrPresignUnsupported) {
+		return nil, &ServiceError{Message: "direct download is not available with the configured storage backend"}
+	}
+	if err != nil {
+		s.logger.Error("Failed to presign export download", logging.WithFields(map[string]interface{}{
+			"exportId": export.ID,
+			"error":    err.Error(),
+		}))
+		return nil, err
+	}
+
+	return &models.DataExportDownloadURL{
+		URL:       url,
+		ExpiresAt: s.now().UTC().Add(s.downloadURLTTL),
+	}, nil
+}
+
+// CleanupExpired deletes archives past their expiry and fails jobs that
+// were lost to a restart. It returns how many archives were deleted.
+func (s *Service) CleanupExpired(ctx context.Context) (int, error) {
+	now := s.now()
+	if failed, err := s.jobs.FailStale(ctx, now.Add(-2*exportTimeout), "export was interrupted; please request a new one"); err != nil {
+		return 0, err
+	} else if failed > 0 {
+		s.logger.Warn("Failed interrupted data exports", logging.WithField("count", failed))
+	}
+
+	expired, err := s.jobs.ListExpired(ctx, now, cleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, export := range expired {
+		if export.StorageKey != "" {
+			if err := s.blobs.Delete(ctx, export.StorageKey); err != nil {
+				s.logger.Warn("Failed to delete expired export archive", logging.WithFields(map[string]interface{}{
+					"exportId": export.ID,
+					"error":    err.Error(),
+				}))
+				continue
+			}
+		}
+		if err := s.jobs.MarkExpired(ctx, export.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// DeleteArchives removes every stored archive of the user's, ahead of the
+// account being deleted. Export rows go with the account.
+func (s *Service) DeleteArchives(ctx context.Context, userID string) error {
+	exports, err := s.jobs.ListArchived(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err := s.blobs.Delete(ctx, export.StorageKey); err != nil {
+			return fmt.Errorf("delete export archive %s: %w", export.ID, err)
+		}
+	}
+	return nil
+}
+
+// ArchiveFileName is the download file name for an export archive
+func ArchiveFileName(export *models.DataExport) string {
+	return fmt.Sprintf("flyingforge-export-%s.zip", export.CreatedAt.UTC().Format("20060102"))
+}
+
+// archiveObjectKey is the object storage key for an export archive
+func archiveObjectKey(userID, exportID string) string {
+	return fmt.Sprintf("exports/%s/%s.zip", userID, exportID)
+}
+
+func (s *Service) downloadable(ctx context.Context, userID string, id string) (*models.DataExport, error) {
+	export, err := s.jobs.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, &ServiceError{Message: "export not found"}
+	}
+	if export.Status != models.DataExportReady || export.StorageKey == "" {
+		return nil, &ServiceError{Message: fmt.Sprintf("export is %s, not ready to download", export.Status)}
+	}
+	if export.ExpiresAt != nil && !s.now().Before(*export.ExpiresAt) {
+		return nil, &ServiceError{Message: "export has expired; please request a new one"}
+	}
+	return export, nil
+}
+
+func (s *Service) setDownloadURL(export *models.DataExport) {
+	if export.Status == models.DataExportReady {
+		export.DownloadURL = "/api/me/exports/" + export.ID + "/download"
+	}
+}
+
+// run builds the archive for a queued export and records the outcome
+func (s *Service) run(exportID string, userID string) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	fields := map[string]interface{}{"exportId": exportID, "userId": userID}
+	if err := s.jobs.MarkRunning(ctx, exportID); err != nil {
+		s.logger.Error("Failed to start data export", logging.WithFields(fields), logging.WithField("error", err.Error()))
+		return
+	}
+
+	storageKey, size, err := s.build(ctx, exportID, userID)
+	statusCtx, statusCancel := context.WithTimeout(context.Background(), jobStatusTimeout)
+	defer statusCancel()
+	if err != nil {
+		s.logger.Error("Data export failed", logging.WithFields(fields), logging.WithField("error", err.Error()))
+		if markErr := s.jobs.MarkFailed(statusCtx, exportID, "export failed; please try again"); markErr != nil {
+			s.logger.Error("Failed to record data export failure", logging.WithField("error", markErr.Error()))
+		}
+		return
+	}
+
+	if err := s.jobs.MarkReady(statusCtx, exportID, storageKey, size, s.now().Add(s.retention)); err != nil {
+		s.logger.Error("Failed to record finished data export", logging.WithFields(fields), logging.WithField("error", err.Error()))
+		if delErr := s.blobs.Delete(statusCtx, storageKey); delErr != nil {
+			s.logger.Warn("Failed to delete orphaned export archive", logging.WithField("error", delErr.Error()))
+		}
+		return
+	}
+
+	s.logger.Info("Data export ready", logging.WithFields(fields), logging.WithField("size", size))
+}
+
+// build writes the archive to a temp file, then stores it
+func (s *Service) build(ctx context.Context, exportID string, userID string) (string, int64, error) {
+	tmp, err := os.CreateTemp("", "flyingforge-export-*.zip")
+	if err != nil {
+		return "", 0, fmt.Errorf("create temp archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := s.WriteArchive(ctx, tmp, exportID, userID); err != nil {
+		return "", 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, fmt.Errorf("size temp archive: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, fmt.Errorf("rewind temp archive: %w", err)
+	}
+
+	storageKey := archiveObjectKey(userID, exportID)
+	if err := s.blobs.Put(ctx, storageKey, tmp, size, "application/zip"); err != nil {
+		return "", 0, fmt.Errorf("store archive: %w", err)
+	}
+	return storageKey, size, nil
+}
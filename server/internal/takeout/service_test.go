@@ -0,0 +1,464 @@
+package takeout
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnrirwin/flyingforge/internal/images"
+	"github.com/johnrirwin/flyingforge/internal/models"
+	"github.com/johnrirwin/flyingforge/internal/storage"
+	"github.com/johnrirwin/flyingforge/internal/testutil"
+)
+
+// mockJobStore implements JobStore in memory
+type mockJobStore struct {
+	exports map[string]*models.DataExport
+	nextID  int
+}
+
+func newMockJobStore() *mockJobStore {
+	return &mockJobStore{exports: make(map[string]*models.DataExport)}
+}
+
+func (m *mockJobStore) Create(_ context.Context, userID string) (*models.DataExport, error) {
+	m.nextID++
+	export := &models.DataExport{ID: fmt.Sprintf("export-%d", m.nextID), UserID: userID, Status: models.DataExportPending, CreatedAt: time.Now()}
+	m.exports[export.ID] = export
+	copied := *export
+	return &copied, nil
+}
+
+func (m *mockJobStore) Get(_ context.Context, id string, userID string) (*models.DataExport, error) {
+	export := m.exports[id]
+	if export == nil || export.UserID != userID {
+		return nil, nil
+	}
+	copied := *export
+	return &copied, nil
+}
+
+func (m *mockJobStore) ListByUser(_ context.Context, userID string, _ int) ([]models.DataExport, error) {
+	exports := []models.DataExport{}
+	for _, export := range m.exports {
+		if export.UserID == userID {
+			exports = append(exports, *export)
+		}
+	}
+	return exports, nil
+}
+
+func (m *mockJobStore) ListArchived(ctx context.Context, userID string) ([]models.DataExport, error) {
+	exports, _ := m.ListByUser(ctx, userID, 0)
+	archived := []models.DataExport{}
+	for _, export := range exports {
+		if export.StorageKey != "" {
+			archived = append(archived, export)
+		}
+	}
+	return archived, nil
+}
+
+func (m *mockJobStore) GetActive(_ context.Context, userID string) (*models.DataExport, error) {
+	for _, export := range m.exports {
+		if export.UserID == userID && (export.Status == models.DataExportPending || export.Status == models.DataExportRunning) {
+			copied := *export
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockJobStore) MarkRunning(_ context.Context, id string) error {
+	m.exports[id].Status = models.DataExportRunning
+	return nil
+}
+
+func (m *mockJobStore) MarkReady(_ context.Context, id string, storageKey string, fileSize int64, expiresAt time.Time) error {
+	export := m.exports[id]
+	export.Status = models.DataExportReady
+	export.StorageKey = storageKey
+	export.FileSize = fileSize
+	export.ExpiresAt = &expiresAt
+	return nil
+}
+
+func (m *mockJobStore) MarkFailed(_ context.Context, id string, message string) error {
+	m.exports[id].Status = models.DataExportFailed
+	m.exports[id].Error = message
+	return nil
+}
+
+func (m *mockJobStore) MarkExpired(_ context.Context, id string) error {
+	m.exports[id].Status = models.DataExportExpired
+	m.exports[id].StorageKey = ""
+	return nil
+}
+
+func (m *mockJobStore) ListExpired(_ context.Context, now time.Time, _ int) ([]models.DataExport, error) {
+	expired := []models.DataExport{}
+	for _, export := range m.exports {
+		if export.Status == models.DataExportReady && export.ExpiresAt != nil && !export.ExpiresAt.After(now) {
+			expired = append(expired, *export)
+		}
+	}
+	return expired, nil
+}
+
+func (m *mockJobStore) FailStale(_ context.Context, _ time.Time, _ string) (int64, error) {
+	return 0, nil
+}
+
+type stubProfile struct{}
+
+func (stubProfile) GetByID(_ context.Context, id string) (*models.User, error) {
+	return &models.User{ID: id, Email: "pilot@example.com", CallSign: "Pilot"}, nil
+}
+
+type stubAircraft struct{}
+
+func (stubAircraft) ListByUserID(_ context.Context, _ string) ([]*models.Aircraft, error) {
+	return []*models.Aircraft{{ID: "ac-1", Name: "Five inch"}}, nil
+}
+
+func (stubAircraft) GetDetails(_ context.Context, id string, _ string) (*models.AircraftDetailsResponse, error) {
+	return &models.AircraftDetailsResponse{
+		Aircraft:         models.Aircraft{ID: id, Name: "Five inch"},
+		Components:       []models.AircraftComponent{{InventoryItemID: "inv-1"}},
+		ReceiverSettings: &models.AircraftReceiverSettings{Settings: json.RawMessage(`{"bindPhrase":"plaintext phrase"}`)},
+	}, nil
+}
+
+func (stubAircraft) ListGallery(_ context.Context, _ string) ([]models.GalleryImage, error) {
+	return []models.GalleryImage{{ID: "gal-1", ImageAssetID: "img-1", Caption: "First flight", IsCover: true}}, nil
+}
+
+// stubMaintenance records which aircraft it was asked about
+type stubMaintenance struct {
+	asked []string
+}
+
+func (s *stubMaintenance) ListMaintenanceEvents(_ context.Context, aircraftID string) ([]models.MaintenanceEvent, error) {
+	s.asked = append(s.asked, aircraftID)
+	return []models.MaintenanceEvent{{ID: "evt-1", AircraftID: aircraftID, Type: models.MaintenanceEventCrash, Title: "Clipped a gate"}}, nil
+}
+
+type stubInventory struct{}
+
+func (stubInventory) List(_ context.Context, _ string, _ models.InventoryFilterParams) (*models.InventoryResponse, error) {
+	return &models.InventoryResponse{Items: []models.InventoryItem{{
+		ID:    "inv-1",
+		Name:  "2207 motor",
+		Units: []models.InventoryUnit{{ID: "unit-1", SerialNumber: "M-001"}, {ID: "unit-2", SerialNumber: "M-002"}},
+	}}, TotalCount: 1}, nil
+}
+
+type stubFlights struct{}
+
+func (stubFlights) List(_ context.Context, _ string, params models.FlightSessionListParams) (*models.FlightSessionListResponse, error) {
+	sessions := []models.FlightSession{}
+	for i := params.Offset; i < 150 && len(sessions) < params.Limit; i++ {
+		sessions = append(sessions, models.FlightSession{ID: fmt.Sprintf("flight-%d", i), AircraftID: "ac-1", FlightCount: 1})
+	}
+	return &models.FlightSessionListResponse{Sessions: sessions, TotalCount: 150}, nil
+}
+
+// stubOrders records whether archived orders were asked for
+type stubOrders struct {
+	includeArchived bool
+}
+
+func (s *stubOrders) List(_ context.Context, _ string, params models.OrderListParams) (*models.OrderListResponse, error) {
+	s.includeArchived = params.IncludeArchived
+	return &models.OrderListResponse{Orders: []models.Order{{
+		ID:       "order-1",
+		Archived: true,
+		Items:    []models.OrderItem{{InventoryItemID: "inv-1"}, {InventoryItemID: "inv-2"}},
+	}}, TotalCount: 1}, nil
+}
+
+type stubFCConfigs struct{}
+
+func (stubFCConfigs) ListConfigs(_ context.Context, _ string, _ models.FCConfigListParams) (*models.FCConfigListResponse, error) {
+	return &models.FCConfigListResponse{Configs: []models.FlightControllerConfig{{ID: "cfg-1"}}, TotalCount: 1}, nil
+}
+
+func (stubFCConfigs) GetConfig(_ context.Context, id string, _ string) (*models.FlightControllerConfig, error) {
+	return &models.FlightControllerConfig{ID: id, RawCLIDump: "# dump\nset gyro_lpf1_static_hz = 250\n"}, nil
+}
+
+func (stubFCConfigs) ListAllTuningSnapshots(_ context.Context, _ string) ([]models.AircraftTuningSnapshot, error) {
+	return []models.AircraftTuningSnapshot{{ID: "snap-1", AircraftID: "ac-1", FlightControllerConfigID: "cfg-1"}}, nil
+}
+
+type stubRadios struct{}
+
+func (stubRadios) ListRadios(_ context.Context, _ string, _ models.RadioListParams) (*models.RadioListResponse, error) {
+	return &models.RadioListResponse{Radios: []models.Radio{{ID: "radio-1"}}, TotalCount: 1}, nil
+}
+
+func (stubRadios) ListBackups(_ context.Context, radioID string, _ string, _ models.RadioBackupListParams) (*models.RadioBackupListResponse, error) {
+	return &models.RadioBackupListResponse{Backups: []models.RadioBackup{
+		{ID: "backup-1", RadioID: radioID, FileName: "../../etc/models.bin"},
+		{ID: "backup-2", RadioID: radioID, FileName: "lost.bin"},
+	}, TotalCount: 2}, nil
+}
+
+func (stubRadios) GetBackupFile(_ context.Context, backupID string, _ string, _ string) (io.ReadCloser, *models.RadioBackup, error) {
+	if backupID != "backup-1" {
+		return nil, nil, errors.New("backup file not found")
+	}
+	return io.NopCloser(strings.NewReader("edgetx")), &models.RadioBackup{ID: backupID}, nil
+}
+
+type stubImages struct{}
+
+func (stubImages) ListByOwner(_ context.Context, ownerUserID string) ([]models.ImageAsset, error) {
+	return []models.ImageAsset{{ID: "img-1", OwnerUserID: ownerUserID, EntityType: models.ImageEntityAircraft, EntityID: "ac-1", Status: models.ImageModerationApproved}}, nil
+}
+
+func (stubImages) Open(_ context.Context, imageID string) (*images.ImageStream, error) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	return &images.ImageStream{Asset: &models.ImageAsset{ID: imageID}, Body: io.NopCloser(bytes.NewReader(png)), Size: int64(len(png))}, nil
+}
+
+func newTestService(t *testing.T) (*Service, *mockJobStore) {
+	jobs := newMockJobStore()
+	return &Service{
+		jobs: jobs,
+		sources: Sources{
+			Profile:   stubProfile{},
+			Aircraft:  stubAircraft{},
+			FCConfigs: stubFCConfigs{},
+			Radios:    stubRadios{},
+			Images:    stubImages{},
+		},
+		blobs:          storage.NewLocalStore(t.TempDir()),
+		retention:      DefaultRetention,
+		downloadURLTTL: DefaultDownloadURLTTL,
+		slots:          make(chan struct{}, 1),
+		logger:         testutil.NullLogger(),
+		now:            time.Now,
+		start:          func(job func()) { job() },
+	}, jobs
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		body, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		content, _ := io.ReadAll(body)
+		body.Close()
+		files[file.Name] = content
+	}
+	return files
+}
+
+func TestWriteArchiveListsEveryFileInManifest(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	var buf bytes.Buffer
+	if _, err := svc.WriteArchive(context.Background(), &buf, "export-1", "user-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	files := readArchive(t, buf.Bytes())
+
+	var manifest models.TakeoutManifest
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	if manifest.Format != models.TakeoutFormat || manifest.Version != models.TakeoutFormatVersion || manifest.UserID != "user-1" {
+		t.Fatalf("unexpected manifest header: %+v", manifest)
+	}
+	if len(manifest.Files) != len(files)-1 {
+		t.Fatalf("expected manifest to list %d files, got %d", len(files)-1, len(manifest.Files))
+	}
+	for _, entry := range manifest.Files {
+		content, ok := files[entry.Path]
+		if !ok {
+			t.Fatalf("manifest lists missing file %s", entry.Path)
+		}
+		sum := sha256.Sum256(content)
+		if entry.SHA256 != hex.EncodeToString(sum[:]) || entry.Size != int64(len(content)) {
+			t.Fatalf("checksum or size mismatch for %s", entry.Path)
+		}
+	}
+
+	if manifest.Counts["aircraft"] != 1 || manifest.Counts["fcConfigs"] != 1 || manifest.Counts["tuningSnapshots"] != 1 || manifest.Counts["radioBackups"] != 2 || manifest.Counts["images"] != 1 {
+		t.Fatalf("unexpected counts: %v", manifest.Counts)
+	}
+	if !strings.Contains(string(files["aircraft.json"]), "plaintext phrase") {
+		t.Fatal("expected decrypted receiver settings in aircraft.json")
+	}
+	if !strings.Contains(string(files["fc_configs/cfg-1.txt"]), "gyro_lpf1_static_hz") {
+		t.Fatal("expected raw CLI dump to be exported")
+	}
+	if _, ok := files["radio_backups/backup-1/models.bin"]; !ok {
+		t.Fatalf("expected sanitized backup file name, got %v", manifest.Files)
+	}
+	if _, ok := files["images/img-1.png"]; !ok {
+		t.Fatal("expected image original with a sniffed extension")
+	}
+	for _, entry := range manifest.Files {
+		if entry.Kind == models.TakeoutFileImage && (!entry.Cover || entry.Caption != "First flight") {
+			t.Fatalf("expected image to keep its gallery placement, got %+v", entry)
+		}
+	}
+	if len(manifest.Warnings) != 1 || !strings.Contains(manifest.Warnings[0], "backup-2") {
+		t.Fatalf("expected a warning for the unreadable backup, got %v", manifest.Warnings)
+	}
+	if _, ok := files["inventory.json"]; ok {
+		t.Fatal("expected sections without a source to be left out")
+	}
+}
+
+func TestWriteArchiveIncludesFlightsMaintenanceAndOrders(t *testing.T) {
+	svc, _ := newTestService(t)
+	maintenance := &stubMaintenance{}
+	orders := &stubOrders{}
+	svc.sources.Inventory = stubInventory{}
+	svc.sources.Maintenance = maintenance
+	svc.sources.Flights = stubFlights{}
+	svc.sources.Orders = orders
+
+	var buf bytes.Buffer
+	manifest, err := svc.WriteArchive(context.Background(), &buf, "export-1", "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	files := readArchive(t, buf.Bytes())
+
+	want := map[string]int{"inventory": 1, "inventoryUnits": 2, "maintenanceEvents": 1, "flightSessions": 150, "orders": 1, "orderItems": 2}
+	for name, count := range want {
+		if manifest.Counts[name] != count {
+			t.Errorf("expected %d %s, got %d", count, name, manifest.Counts[name])
+		}
+	}
+	if len(maintenance.asked) != 1 || maintenance.asked[0] != "ac-1" {
+		t.Errorf("expected maintenance to be read for the exported aircraft only, got %v", maintenance.asked)
+	}
+	if !orders.includeArchived {
+		t.Error("expected archived orders to be exported")
+	}
+	if !strings.Contains(string(files["inventory.json"]), "M-002") {
+		t.Error("expected unit serial numbers in inventory.json")
+	}
+
+	kinds := map[models.TakeoutFileKind]string{}
+	for _, entry := range manifest.Files {
+		kinds[entry.Kind] = entry.Path
+	}
+	for kind, path := range map[models.TakeoutFileKind]string{
+		models.TakeoutFileMaintenanceEvents: "maintenance_events.json",
+		models.TakeoutFileFlightSessions:    "flight_sessions.json",
+		models.TakeoutFileOrders:            "orders.json",
+	} {
+		if kinds[kind] != path {
+			t.Errorf("expected %s listed as %s, got %q", path, kind, kinds[kind])
+		}
+		if _, ok := files[path]; !ok {
+			t.Errorf("expected %s in the archive", path)
+		}
+	}
+}
+
+func TestRequestExportBuildsArchiveForDownload(t *testing.T) {
+	svc, jobs := newTestService(t)
+	ctx := context.Background()
+
+	export, err := svc.RequestExport(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	stored := jobs.exports[export.ID]
+	if stored.Status != models.DataExportReady || stored.FileSize == 0 || stored.ExpiresAt == nil {
+		t.Fatalf("expected a ready export, got %+v", stored)
+	}
+
+	got, err := svc.GetExport(ctx, "user-1", export.ID)
+	if err != nil || got.DownloadURL != "/api/me/exports/"+export.ID+"/download" {
+		t.Fatalf("expected a download link, got %+v (%v)", got, err)
+	}
+	if _, err := svc.GetExport(ctx, "user-2", export.ID); err == nil {
+		t.Fatal("expected another user's export to be hidden")
+	}
+
+	file, _, err := svc.OpenArchive(ctx, "user-1", export.ID)
+	if err != nil {
+		t.Fatalf("expected archive to open, got %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if _, ok := readArchive(t, data)["manifest.json"]; !ok {
+		t.Fatal("expected stored archive to contain a manifest")
+	}
+
+	if _, err := svc.GetDownloadURL(ctx, "user-1", export.ID); err == nil {
+		t.Fatal("expected local storage not to issue direct download URLs")
+	}
+}
+
+func TestRequestExportReturnsActiveJob(t *testing.T) {
+	svc, jobs := newTestService(t)
+	svc.start = func(func()) {} // Leave the job queued
+
+	first, err := svc.RequestExport(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := svc.RequestExport(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.ID != second.ID || len(jobs.exports) != 1 {
+		t.Fatalf("expected the queued export to be reused, got %s and %s", first.ID, second.ID)
+	}
+	if _, _, err := svc.OpenArchive(context.Background(), "user-1", first.ID); err == nil {
+		t.Fatal("expected a queued export not to be downloadable")
+	}
+}
+
+func TestCleanupExpiredDeletesArchives(t *testing.T) {
+	svc, jobs := newTestService(t)
+	ctx := context.Background()
+
+	export, err := svc.RequestExport(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	storageKey := jobs.exports[export.ID].StorageKey
+
+	svc.now = func() time.Time { return time.Now().Add(DefaultRetention + time.Hour) }
+	if _, _, err := svc.OpenArchive(ctx, "user-1", export.ID); err == nil {
+		t.Fatal("expected an expired export not to be downloadable")
+	}
+
+	deleted, err := svc.CleanupExpired(ctx)
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected one archive to be deleted, got %d (%v)", deleted, err)
+	}
+	if jobs.exports[export.ID].Status != models.DataExportExpired {
+		t.Fatalf("expected export to be marked expired, got %s", jobs.exports[export.ID].Status)
+	}
+	if _, _, err := svc.blobs.Get(ctx, storageKey); err == nil {
+		t.Fatal("expected archive object to be deleted")
+	}
+}
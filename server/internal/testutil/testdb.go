@@ -103,6 +103,9 @@ func (tdb *TestDB) Cleanup(ctx context.Context) {
 		"oauth_authorization_codes",
 		"oauth_clients",
 		"refresh_tokens",
+		"passkey_credentials",
+		"webauthn_challenges",
+		"email_login_tokens",
 		"user_identities",
 		"users",
 	}
@@ -176,6 +176,10 @@ resource "aws_ecs_task_definition" "server" {
           name  = "MODERATION_REJECT_CONFIDENCE"
           value = "70"
         },
+        {
+          name  = "MODERATION_REVIEW_CONFIDENCE"
+          value = "50"
+        },
         {
           name  = "MODERATION_TIMEOUT"
           value = "5s"
@@ -115,6 +115,7 @@ export interface InventoryItem {
   buildId?: string;
   purchasePrice?: number;
   purchaseSeller?: string;
+  units?: InventoryUnit[];
   productUrl?: string;
   imageUrl?: string;
   specs?: Record<string, unknown>;
@@ -123,6 +124,21 @@ export interface InventoryItem {
   updatedAt: string;
 }
 
+// Individually tracked piece of an inventory item
+export interface InventoryUnit {
+  id: string;
+  inventoryItemId: string;
+  serialNumber?: string;
+  purchasePrice?: number;
+  purchaseSeller?: string;
+  condition: 'new' | 'used' | 'damaged' | 'dead' | 'repaired';
+  aircraftId?: string;
+  buildId?: string;
+  notes?: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
 // Add inventory item params
 export interface AddInventoryParams {
   name: string;
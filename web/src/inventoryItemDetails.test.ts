@@ -27,6 +27,30 @@ describe('inventoryItemDetails', () => {
     ]);
   });
 
+  it('reads item details from tracked units before specs', () => {
+    const unit = {
+      inventoryItemId: 'inv-1',
+      condition: 'new' as const,
+      createdAt: '2026-01-01T00:00:00Z',
+      updatedAt: '2026-01-01T00:00:00Z',
+    };
+    const details = buildInventoryItemDetails({
+      ...baseItem,
+      units: [
+        { ...unit, id: 'unit-1', purchasePrice: 80, purchaseSeller: 'GetFPV' },
+        { ...unit, id: 'unit-2', purchasePrice: 95, buildId: 'Quad B' },
+      ],
+      specs: {
+        [INVENTORY_ITEM_DETAILS_SPEC_KEY]: [{ purchasePrice: 1 }, { purchasePrice: 2 }],
+      },
+    });
+
+    expect(details).toEqual([
+      { purchasePrice: 80, purchaseSeller: 'GetFPV', buildId: undefined },
+      { purchasePrice: 95, purchaseSeller: undefined, buildId: 'Quad B' },
+    ]);
+  });
+
   it('reads and normalizes item details from specs', () => {
     const details = getInventoryItemDetailsFromSpecs({
       [INVENTORY_ITEM_DETAILS_SPEC_KEY]: [
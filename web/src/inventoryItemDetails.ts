@@ -85,7 +85,18 @@ export function getInventoryItemDetailsFromSpecs(specs?: Record<string, unknown>
 
 export function buildInventoryItemDetails(item: InventoryItem): InventoryItemDetail[] {
   const quantity = Number.isInteger(item.quantity) && item.quantity > 0 ? item.quantity : 0;
-  const detailsFromSpecs = getInventoryItemDetailsFromSpecs(item.specs);
+  // Tracked units are authoritative; details in specs only exist on items
+  // saved before units were introduced.
+  const detailsFromSpecs =
+    item.units && item.units.length > 0
+      ? item.units.map((unit) =>
+          sanitizeDetail({
+            purchasePrice: unit.purchasePrice,
+            purchaseSeller: unit.purchaseSeller,
+            buildId: unit.buildId,
+          }),
+        )
+      : getInventoryItemDetailsFromSpecs(item.specs);
   const fallback = sanitizeDetail({
     purchasePrice: item.purchasePrice,
     purchaseSeller: item.purchaseSeller,